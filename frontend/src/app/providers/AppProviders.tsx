@@ -25,7 +25,9 @@ import {
   LIBRARY_FILE_EVENTS_QUERY_KEY,
   LIBRARY_HISTORY_QUERY_KEY,
   LIBRARY_LIST_QUERY_KEY,
+  LIBRARY_OPERATION_QUEUE_QUERY_KEY,
   LIBRARY_OPERATIONS_QUERY_KEY,
+  LIBRARY_SUBSCRIPTIONS_QUERY_KEY,
   LIBRARY_WORKSPACE_QUERY_KEY,
   LIBRARY_WORKSPACE_PROJECT_QUERY_KEY,
 } from "@/shared/query/library";
@@ -569,6 +571,17 @@ export function AppProviders({ children }: PropsWithChildren) {
       }
       if (normalizedTopic === REALTIME_TOPICS.library.workspaceProject) {
         scheduleLibraryResync(resolveLibraryID(payload));
+        return;
+      }
+      if (normalizedTopic === REALTIME_TOPICS.library.subscription) {
+        queryClient.invalidateQueries({ queryKey: LIBRARY_SUBSCRIPTIONS_QUERY_KEY, refetchType: "active" });
+        return;
+      }
+      if (normalizedTopic === REALTIME_TOPICS.library.queue) {
+        if (payload && typeof payload === "object") {
+          queryClient.setQueryData(LIBRARY_OPERATION_QUEUE_QUERY_KEY, payload);
+        }
+        queryClient.invalidateQueries({ queryKey: LIBRARY_OPERATIONS_QUERY_KEY, refetchType: "active" });
       }
     };
     const handleGatewayLibraryEvent = (event: GatewayEvent) => {
@@ -645,6 +658,12 @@ export function AppProviders({ children }: PropsWithChildren) {
     const offLibraryWorkspaceProject = registerTopic(REALTIME_TOPICS.library.workspaceProject, (event) => {
       handleLibraryEvent(REALTIME_TOPICS.library.workspaceProject, (event?.type ?? "upsert").trim().toLowerCase(), event?.payload);
     });
+    const offLibrarySubscription = registerTopic(REALTIME_TOPICS.library.subscription, (event) => {
+      handleLibraryEvent(REALTIME_TOPICS.library.subscription, (event?.type ?? "upsert").trim().toLowerCase(), event?.payload);
+    });
+    const offLibraryQueue = registerTopic(REALTIME_TOPICS.library.queue, (event) => {
+      handleLibraryEvent(REALTIME_TOPICS.library.queue, (event?.type ?? "upsert").trim().toLowerCase(), event?.payload);
+    });
     return () => {
       if (threadResyncTimerRef.current !== null) {
         window.clearTimeout(threadResyncTimerRef.current);
@@ -670,6 +689,8 @@ export function AppProviders({ children }: PropsWithChildren) {
       offLibraryHistory();
       offLibraryWorkspace();
       offLibraryWorkspaceProject();
+      offLibrarySubscription();
+      offLibraryQueue();
       offLibraryOperation();
     };
   }, []);
@@ -895,6 +895,9 @@ const formatPayloadSummary = (job: CronJob, t: (key: string) => string) => {
   if (kind === "agentTurn") {
     return t("cron.payload.agentTurn");
   }
+  if (kind === "subscriptionPoll") {
+    return t("cron.payload.subscriptionPoll");
+  }
   return t("cron.payload.systemEvent");
 };
 
@@ -262,14 +262,24 @@ const SUBTITLE_EXPORT_FORMAT_ORDER = [
   "vtt",
   "ass",
   "itt",
+  "ttml",
   "fcpxml",
+  "sbv",
+  "sub",
+  "lrc",
+  "stl",
 ] as const;
 const SUBTITLE_EXPORT_PROFILE_FORMAT_OPTIONS = [
   { value: "srt", label: "SRT" },
   { value: "vtt", label: "VTT" },
   { value: "ass", label: "ASS/SSA" },
   { value: "itt", label: "ITT" },
+  { value: "ttml", label: "TTML (IMSC1)" },
   { value: "fcpxml", label: "FCPXML" },
+  { value: "sbv", label: "SBV" },
+  { value: "sub", label: "SUB (MicroDVD)" },
+  { value: "lrc", label: "LRC" },
+  { value: "stl", label: "EBU-STL" },
 ] as const;
 const GLOSSARY_CATEGORY_ORDER = ["all", "translate", "proofread"] as const;
 const PROMPT_CATEGORY_ORDER = [
@@ -358,6 +368,26 @@ const FFMPEG_SPEED_PRESET_OPTIONS = [
   { value: "slow", label: "slow" },
 ] as const;
 
+const ASPECT_RATIO_OPTIONS = [
+  { value: "original", label: "Original" },
+  { value: "16:9", label: "16:9" },
+  { value: "9:16", label: "9:16" },
+  { value: "1:1", label: "1:1" },
+  { value: "4:5", label: "4:5" },
+  { value: "3:4", label: "3:4" },
+] as const;
+
+const REFRAME_MODE_OPTIONS = ["pad", "blur", "crop"] as const;
+
+const DEFAULT_LOUDNESS_TARGET_LUFS = -16;
+
+const LOUDNESS_TARGET_OPTIONS = [
+  { value: -14, label: "-14 LUFS (streaming)" },
+  { value: -16, label: "-16 LUFS (podcast)" },
+  { value: -23, label: "-23 LUFS (EBU R128)" },
+  { value: -24, label: "-24 LUFS (ATSC A/85)" },
+] as const;
+
 function resolveTranscodeOutputTypeLabel(
   outputType: TranscodePreset["outputType"],
   t: (key: string) => string,
@@ -430,6 +460,9 @@ function resolveCompactPresetMeta(
     } else {
       parts.push(t("library.workspace.transcode.summary.original"));
     }
+    if (preset.aspectRatio) {
+      parts.push(preset.aspectRatio);
+    }
   } else if (preset.audioCodec) {
     parts.push(resolveCompactCodecToken(preset.audioCodec));
     if (preset.audioBitrateKbps) {
@@ -6101,6 +6134,83 @@ export function LibraryConfigPage({
                     </div>
                   ) : null}
 
+                  <ConfigSelectField
+                    label={t("library.config.videoExportPresets.aspectRatio")}
+                    description={t("library.config.videoExportPresets.aspectRatioDescription")}
+                    inline
+                    value={draft.aspectRatio || "original"}
+                    disabled={!selectedVideoPresetEditing}
+                    onChange={(nextValue) =>
+                      setVideoExportPresetDraft((current) =>
+                        current
+                          ? nextValue === "original"
+                            ? { ...current, aspectRatio: "", reframeMode: "" }
+                            : {
+                                ...current,
+                                aspectRatio: nextValue,
+                                reframeMode: current.reframeMode || "pad",
+                              }
+                          : current,
+                      )
+                    }
+                    options={ASPECT_RATIO_OPTIONS.map((option) => ({
+                      value: option.value,
+                      label: option.label,
+                    }))}
+                  />
+
+                  {draft.aspectRatio ? (
+                    <ConfigSelectField
+                      label={t("library.config.videoExportPresets.reframeMode")}
+                      description={t("library.config.videoExportPresets.reframeModeDescription")}
+                      inline
+                      value={draft.reframeMode || "pad"}
+                      disabled={!selectedVideoPresetEditing}
+                      onChange={(nextValue) =>
+                        setVideoExportPresetDraft((current) =>
+                          current
+                            ? { ...current, reframeMode: nextValue }
+                            : current,
+                        )
+                      }
+                      options={REFRAME_MODE_OPTIONS.map((mode) => ({
+                        value: mode,
+                        label: t(`library.config.videoExportPresets.reframeModes.${mode}`),
+                      }))}
+                    />
+                  ) : null}
+
+                  <div className="grid gap-3 md:grid-cols-2">
+                    <ConfigNumberField
+                      label={t("library.config.videoExportPresets.maxBitrate")}
+                      description={t("library.config.videoExportPresets.maxBitrateDescription")}
+                      value={draft.maxBitrateKbps ?? 0}
+                      min={0}
+                      disabled={!selectedVideoPresetEditing}
+                      onChange={(nextValue) =>
+                        setVideoExportPresetDraft((current) =>
+                          current
+                            ? { ...current, maxBitrateKbps: nextValue }
+                            : current,
+                        )
+                      }
+                    />
+                    <ConfigNumberField
+                      label={t("library.config.videoExportPresets.maxFileSize")}
+                      description={t("library.config.videoExportPresets.maxFileSizeDescription")}
+                      value={draft.maxFileSizeMb ?? 0}
+                      min={0}
+                      disabled={!selectedVideoPresetEditing}
+                      onChange={(nextValue) =>
+                        setVideoExportPresetDraft((current) =>
+                          current
+                            ? { ...current, maxFileSizeMb: nextValue }
+                            : current,
+                        )
+                      }
+                    />
+                  </div>
+
                   <ConfigSelectField
                     label={t("library.config.videoExportPresets.ffmpegPreset")}
                     inline
@@ -6186,6 +6296,77 @@ export function LibraryConfigPage({
                 </>
               )}
 
+              <ConfigSwitchField
+                label={t("library.config.videoExportPresets.loudnessNormalize")}
+                description={t("library.config.videoExportPresets.loudnessNormalizeDescription")}
+                checked={Boolean(draft.loudnessNormalize)}
+                disabled={!selectedVideoPresetEditing}
+                onCheckedChange={(checked) =>
+                  setVideoExportPresetDraft((current) =>
+                    current
+                      ? {
+                          ...current,
+                          loudnessNormalize: checked,
+                          loudnessTargetI: checked
+                            ? (current.loudnessTargetI ?? DEFAULT_LOUDNESS_TARGET_LUFS)
+                            : undefined,
+                        }
+                      : current,
+                  )
+                }
+              />
+
+              {draft.loudnessNormalize ? (
+                <ConfigSelectField
+                  label={t("library.config.videoExportPresets.loudnessTarget")}
+                  description={t("library.config.videoExportPresets.loudnessTargetDescription")}
+                  inline
+                  value={String(draft.loudnessTargetI ?? DEFAULT_LOUDNESS_TARGET_LUFS)}
+                  disabled={!selectedVideoPresetEditing}
+                  onChange={(nextValue) =>
+                    setVideoExportPresetDraft((current) =>
+                      current
+                        ? { ...current, loudnessTargetI: Number(nextValue) }
+                        : current,
+                    )
+                  }
+                  options={LOUDNESS_TARGET_OPTIONS.map((option) => ({
+                    value: String(option.value),
+                    label: option.label,
+                  }))}
+                />
+              ) : null}
+
+              <ConfigSwitchField
+                label={t("library.config.videoExportPresets.noiseReduction")}
+                description={t("library.config.videoExportPresets.noiseReductionDescription")}
+                checked={Boolean(draft.noiseReduction)}
+                disabled={!selectedVideoPresetEditing}
+                onCheckedChange={(checked) =>
+                  setVideoExportPresetDraft((current) =>
+                    current
+                      ? { ...current, noiseReduction: checked }
+                      : current,
+                  )
+                }
+              />
+
+              {draft.outputType === "audio" ? (
+                <ConfigSwitchField
+                  label={t("library.config.videoExportPresets.trimSilence")}
+                  description={t("library.config.videoExportPresets.trimSilenceDescription")}
+                  checked={Boolean(draft.trimSilence)}
+                  disabled={!selectedVideoPresetEditing}
+                  onCheckedChange={(checked) =>
+                    setVideoExportPresetDraft((current) =>
+                      current
+                        ? { ...current, trimSilence: checked }
+                        : current,
+                    )
+                  }
+                />
+              ) : null}
+
             </ConfigDetailPanel>
           ) : (
             <ConfigStandardEmptyState
@@ -1,4 +1,4 @@
-import { Download, FileText, FileVideo, Film, Sparkles } from "lucide-react"
+import { AudioLines, AudioWaveform, BookA, Captions, Download, FileText, FileVideo, Film, Images, Languages, ListTree, ListVideo, Scissors, Sparkles, Workflow } from "lucide-react"
 import type { SimpleIcon } from "simple-icons"
 import {
   siBilibili,
@@ -45,12 +45,24 @@ const SITE_ICON_MAP: Record<string, SimpleIcon> = {
 const TASK_ICON: Record<string, typeof Download> = {
   "yt-dlp": Download,
   download: Download,
+  download_playlist: ListVideo,
   subtitle: FileText,
   subtitle_translate: FileText,
   subtitle_proofread: Sparkles,
   subtitle_qa_review: Sparkles,
+  subtitle_transcribe: AudioLines,
+  subtitle_ocr: Captions,
+  subtitle_extract: Captions,
+  subtitle_align: AudioWaveform,
+  subtitle_bilingual_merge: Languages,
+  subtitle_bilingual_split: Languages,
+  subtitle_glossary_extract: BookA,
+  subtitle_pipeline: Workflow,
   "import-video": FileVideo,
   transcode: Film,
+  clip: Scissors,
+  chapter_split: ListTree,
+  thumbnail_generate: Images,
 }
 
 export function LibraryTaskIcon({ taskType, sourceDomain, sourceIcon, className }: LibraryTaskIconProps) {
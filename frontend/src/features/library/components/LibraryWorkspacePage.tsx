@@ -1456,7 +1456,9 @@ export function LibraryWorkspacePage(props: WorkspacePageProps) {
     const kindLabel =
       pendingReview.kind === "qa"
         ? t("library.workspace.review.pendingQaShort")
-        : t("library.workspace.review.pendingProofreadShort");
+        : pendingReview.kind === "align"
+          ? t("library.workspace.review.pendingAlignShort")
+          : t("library.workspace.review.pendingProofreadShort");
     return t("library.workspace.review.pendingButtonWithMeta")
       .replace("{kind}", kindLabel)
       .replace("{count}", String(pendingReviewUnresolvedCount));
@@ -1869,11 +1871,15 @@ export function LibraryWorkspacePage(props: WorkspacePageProps) {
       title:
         pendingReview.kind === "qa"
           ? t("library.workspace.notifications.qaReviewReadyTitle")
-          : t("library.workspace.notifications.proofreadReadyTitle"),
+          : pendingReview.kind === "align"
+            ? t("library.workspace.notifications.alignReviewReadyTitle")
+            : t("library.workspace.notifications.proofreadReadyTitle"),
       description:
         pendingReview.kind === "qa"
           ? t("library.workspace.notifications.qaReviewReadyDescription").replace("{count}", String(pendingReviewCount))
-          : t("library.workspace.notifications.proofreadReadyDescription").replace("{count}", String(pendingReviewCount)),
+          : pendingReview.kind === "align"
+            ? t("library.workspace.notifications.alignReviewReadyDescription").replace("{count}", String(pendingReviewCount))
+            : t("library.workspace.notifications.proofreadReadyDescription").replace("{count}", String(pendingReviewCount)),
     });
   }, [pendingReview?.kind, pendingReview?.sessionId, pendingReviewCount, t]);
 
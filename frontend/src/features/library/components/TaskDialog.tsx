@@ -1590,6 +1590,42 @@ function resolveSourceLabel(operation: LibraryOperationDTO, t: (key: string) =>
   if (operation.kind === "subtitle_qa_review") {
     return t("library.jobType.subtitleQaReview")
   }
+  if (operation.kind === "subtitle_transcribe") {
+    return t("library.jobType.subtitleTranscribe")
+  }
+  if (operation.kind === "subtitle_align") {
+    return t("library.jobType.subtitleAlign")
+  }
+  if (operation.kind === "subtitle_bilingual_merge") {
+    return t("library.jobType.subtitleBilingualMerge")
+  }
+  if (operation.kind === "subtitle_bilingual_split") {
+    return t("library.jobType.subtitleBilingualSplit")
+  }
+  if (operation.kind === "subtitle_glossary_extract") {
+    return t("library.jobType.subtitleGlossaryExtract")
+  }
+  if (operation.kind === "subtitle_pipeline") {
+    return t("library.jobType.subtitlePipeline")
+  }
+  if (operation.kind === "download_playlist") {
+    return t("library.jobType.downloadPlaylist")
+  }
+  if (operation.kind === "clip") {
+    return t("library.jobType.clip")
+  }
+  if (operation.kind === "chapter_split") {
+    return t("library.jobType.chapterSplit")
+  }
+  if (operation.kind === "subtitle_ocr") {
+    return t("library.jobType.subtitleOcr")
+  }
+  if (operation.kind === "subtitle_extract") {
+    return t("library.jobType.subtitleExtract")
+  }
+  if (operation.kind === "thumbnail_generate") {
+    return t("library.jobType.thumbnailGenerate")
+  }
   return operation.kind
 }
 
@@ -1623,6 +1659,24 @@ function resolveOriginKindLabel(kind: string | undefined, t: (key: string) => st
       return t("library.workspace.actions.proofread")
     case "subtitle_qa_review":
       return t("library.workspace.header.qa")
+    case "subtitle_transcribe":
+      return t("library.jobType.subtitleTranscribe")
+    case "subtitle_align":
+      return t("library.jobType.subtitleAlign")
+    case "subtitle_bilingual_merge":
+      return t("library.jobType.subtitleBilingualMerge")
+    case "subtitle_bilingual_split":
+      return t("library.jobType.subtitleBilingualSplit")
+    case "clip":
+      return t("library.jobType.clip")
+    case "chapter_split":
+      return t("library.jobType.chapterSplit")
+    case "subtitle_ocr":
+      return t("library.jobType.subtitleOcr")
+    case "subtitle_extract":
+      return t("library.jobType.subtitleExtract")
+    case "thumbnail_generate":
+      return t("library.jobType.thumbnailGenerate")
     case "import_video":
       return t("library.actions.importVideo")
     case "import_subtitle":
@@ -1636,7 +1690,7 @@ function resolveOriginKindLabel(kind: string | undefined, t: (key: string) => st
 
 function isResumableSubtitleTask(kind: string | undefined) {
   const normalized = kind?.trim().toLowerCase() ?? ""
-  return normalized === "subtitle_translate" || normalized === "subtitle_proofread"
+  return normalized === "subtitle_translate" || normalized === "subtitle_proofread" || normalized === "subtitle_transcribe"
 }
 
 function parseSubtitleTaskOutput(operation: LibraryOperationDTO | undefined): SubtitleTaskOutputSummary | null {
@@ -611,6 +611,17 @@ export function buildDefaultSubtitleExportConfig(
       defaultLane: 1,
       startTimecodeSeconds: DEFAULT_FCPXML_START_TIMECODE_SECONDS,
     },
+    ttml: {
+      language: "en-US",
+    },
+    sub: {
+      frameRate,
+    },
+    stl: {
+      frameRate: frameRate >= 27.5 ? 30 : 25,
+      language: "en-US",
+      title: assTitle,
+    },
   };
 }
 
@@ -642,6 +653,19 @@ function pickPresetDimensions(preset: LibrarySubtitleExportPresetDTO) {
         frameDuration: "",
       };
     }
+    case "sub":
+    case "stl":
+      return {
+        width: 0,
+        height: 0,
+        frameRate: positiveNumber(
+          format === "sub"
+            ? preset.config?.sub?.frameRate
+            : preset.config?.stl?.frameRate,
+          0,
+        ),
+        frameDuration: "",
+      };
     case "fcpxml":
       return {
         width: positiveNumber(preset.config?.fcpxml?.width, 0),
@@ -858,6 +882,20 @@ export function mergeSubtitleExportConfig(
         override.fcpxml?.startTimecodeSeconds,
       ),
     },
+    ttml: {
+      ...(base.ttml ?? {}),
+      language: mergeString(base.ttml?.language, override.ttml?.language),
+    },
+    sub: {
+      ...(base.sub ?? {}),
+      frameRate: mergeNumber(base.sub?.frameRate, override.sub?.frameRate),
+    },
+    stl: {
+      ...(base.stl ?? {}),
+      frameRate: mergeNumber(base.stl?.frameRate, override.stl?.frameRate),
+      language: mergeString(base.stl?.language, override.stl?.language),
+      title: mergeString(base.stl?.title, override.stl?.title),
+    },
   };
 }
 
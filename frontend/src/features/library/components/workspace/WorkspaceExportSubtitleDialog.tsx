@@ -43,7 +43,22 @@ import {
   resolveITTFrameTimingFromPresetValue,
 } from "../../utils/subtitleStyles";
 
-const FORMAT_OPTIONS = ["srt", "vtt", "ass", "ssa", "itt", "fcpxml"] as const;
+const FORMAT_OPTIONS = [
+  "srt",
+  "vtt",
+  "ass",
+  "ssa",
+  "itt",
+  "ttml",
+  "fcpxml",
+  "sbv",
+  "sub",
+  "lrc",
+  "stl",
+] as const;
+const MICRODVD_FRAME_RATE_OPTIONS = [23.976, 24, 25, 29.97, 30] as const;
+const EBU_STL_FRAME_RATE_OPTIONS = [25, 30] as const;
+const STYLELESS_EXPORT_FORMATS = new Set(["srt", "ttml", "sbv", "sub", "lrc", "stl"]);
 
 type WorkspaceExportSubtitleDialogProps = {
   open: boolean;
@@ -225,6 +240,12 @@ function buildResolvedOutputSummary(
       return `${exportConfig.ass?.playResX ?? 1920}×${exportConfig.ass?.playResY ?? 1080} · ${exportConfig.ass?.title || DEFAULT_SUBTITLE_EXPORT_ASS_TITLE}`;
     case "itt":
       return `${resolveITTFrameRateLabel(exportConfig.itt?.frameRate, exportConfig.itt?.frameRateMultiplier)} · ${exportConfig.itt?.language || "en-US"}`;
+    case "ttml":
+      return `IMSC1 · ${exportConfig.ttml?.language || "en-US"}`;
+    case "sub":
+      return `${exportConfig.sub?.frameRate ?? 23.976} fps`;
+    case "stl":
+      return `${exportConfig.stl?.frameRate ?? 25} fps · ${exportConfig.stl?.language || "en-US"}`;
     case "fcpxml":
       return `${exportConfig.fcpxml?.width ?? 1920}×${exportConfig.fcpxml?.height ?? 1080} · ${resolveFCPXMLFrameDurationLabel(exportConfig.fcpxml?.frameDuration)} · ${exportConfig.fcpxml?.colorSpace || "1-1-1 (Rec. 709)"}`;
     default:
@@ -367,7 +388,7 @@ function resolveExportSubtitleStyleSummary(
   lingualStyle: LibraryBilingualStyleDTO | null | undefined,
   t: (key: string) => string,
 ): ExportSubtitleStyleSummary {
-  if (resolvePresetFormatKey(format) === "srt") {
+  if (STYLELESS_EXPORT_FORMATS.has(resolvePresetFormatKey(format))) {
     return {
       headline: t("library.workspace.dialogs.exportSubtitle.styleUnsupported"),
       detail: t("library.workspace.dialogs.exportSubtitle.styleUnsupportedDescription"),
@@ -444,6 +465,9 @@ export function WorkspaceExportSubtitleDialog({
   const assConfig = exportConfig.ass ?? {};
   const ittConfig = exportConfig.itt ?? {};
   const fcpxmlConfig = exportConfig.fcpxml ?? {};
+  const ttmlConfig = exportConfig.ttml ?? {};
+  const subConfig = exportConfig.sub ?? {};
+  const stlConfig = exportConfig.stl ?? {};
   const defaultEnglishUSLabel = React.useMemo(
     () => `${t("settings.language.option.en")} (en-US)`,
     [t],
@@ -922,6 +946,148 @@ export function WorkspaceExportSubtitleDialog({
                   </div>
                 </TabsContent>
 
+                <TabsContent
+                  value="ttml"
+                  className="mt-0 data-[state=inactive]:hidden"
+                >
+                  <div className="grid gap-2 md:grid-cols-2">
+                    <CompactFieldPanel
+                      label={t("library.workspace.dialogs.exportSubtitle.language")}
+                    >
+                      <Select
+                        value={(ttmlConfig.language ?? "").trim() || "en-US"}
+                        onChange={(event) =>
+                          onExportConfigChange({
+                            ...exportConfig,
+                            ttml: {
+                              ...(exportConfig.ttml ?? {}),
+                              language: event.target.value,
+                            },
+                          })
+                        }
+                        disabled={isSubmitting}
+                        className="h-8 w-full border-border/70 bg-background/80"
+                      >
+                        {effectiveITTLanguageOptions.map((option) => (
+                          <option key={option.value} value={option.value}>
+                            {option.label}
+                          </option>
+                        ))}
+                      </Select>
+                    </CompactFieldPanel>
+                  </div>
+                </TabsContent>
+
+                <TabsContent
+                  value="sub"
+                  className="mt-0 data-[state=inactive]:hidden"
+                >
+                  <div className="grid gap-2 md:grid-cols-2">
+                    <CompactFieldPanel
+                      label={t("library.workspace.dialogs.exportSubtitle.frameRate")}
+                    >
+                      <Select
+                        value={String(subConfig.frameRate ?? 23.976)}
+                        onChange={(event) =>
+                          onExportConfigChange({
+                            ...exportConfig,
+                            sub: {
+                              ...(exportConfig.sub ?? {}),
+                              frameRate: Number(event.target.value),
+                            },
+                          })
+                        }
+                        disabled={isSubmitting}
+                        className="h-8 w-full border-border/70 bg-background/80"
+                      >
+                        {MICRODVD_FRAME_RATE_OPTIONS.map((value) => (
+                          <option key={value} value={String(value)}>
+                            {value} fps
+                          </option>
+                        ))}
+                      </Select>
+                    </CompactFieldPanel>
+                  </div>
+                </TabsContent>
+
+                <TabsContent
+                  value="stl"
+                  className="mt-0 data-[state=inactive]:hidden"
+                >
+                  <div className="grid gap-2 md:grid-cols-2 xl:grid-cols-3">
+                    <CompactFieldPanel
+                      label={t("library.workspace.dialogs.exportSubtitle.frameRate")}
+                    >
+                      <Select
+                        value={String(stlConfig.frameRate ?? 25)}
+                        onChange={(event) =>
+                          onExportConfigChange({
+                            ...exportConfig,
+                            stl: {
+                              ...(exportConfig.stl ?? {}),
+                              frameRate: Number(event.target.value),
+                            },
+                          })
+                        }
+                        disabled={isSubmitting}
+                        className="h-8 w-full border-border/70 bg-background/80"
+                      >
+                        {EBU_STL_FRAME_RATE_OPTIONS.map((value) => (
+                          <option key={value} value={String(value)}>
+                            {value} fps
+                          </option>
+                        ))}
+                      </Select>
+                    </CompactFieldPanel>
+
+                    <CompactFieldPanel
+                      label={t("library.workspace.dialogs.exportSubtitle.language")}
+                    >
+                      <Select
+                        value={(stlConfig.language ?? "").trim() || "en-US"}
+                        onChange={(event) =>
+                          onExportConfigChange({
+                            ...exportConfig,
+                            stl: {
+                              ...(exportConfig.stl ?? {}),
+                              language: event.target.value,
+                            },
+                          })
+                        }
+                        disabled={isSubmitting}
+                        className="h-8 w-full border-border/70 bg-background/80"
+                      >
+                        {effectiveITTLanguageOptions.map((option) => (
+                          <option key={option.value} value={option.value}>
+                            {option.label}
+                          </option>
+                        ))}
+                      </Select>
+                    </CompactFieldPanel>
+
+                    <CompactFieldPanel
+                      label={t("library.workspace.dialogs.exportSubtitle.title")}
+                    >
+                      <input
+                        value={stlConfig.title || ""}
+                        onChange={(event) =>
+                          onExportConfigChange({
+                            ...exportConfig,
+                            stl: {
+                              ...(exportConfig.stl ?? {}),
+                              title: event.target.value,
+                            },
+                          })
+                        }
+                        disabled={isSubmitting}
+                        maxLength={32}
+                        className="h-8 w-full rounded-md border border-border/70 bg-background/80 px-2 text-xs"
+                        placeholder={DEFAULT_SUBTITLE_EXPORT_ASS_TITLE}
+                      />
+                    </CompactFieldPanel>
+                  </div>
+                </TabsContent>
+
                 <TabsContent
                   value="fcpxml"
                   className="mt-0 data-[state=inactive]:hidden"
@@ -800,12 +800,19 @@ function buildWorkspaceReviewSuggestion(
     sourceLabel: resolveReviewSourceLabel(reviewKind, item),
     detailLabel: resolveReviewDetailLabel(reviewKind, item),
     reason: item.reason?.trim() || resolveReviewDefaultReason(reviewKind),
-    originalText: item.originalText,
-    suggestedText: item.suggestedText,
+    originalText: formatReviewSuggestionText(item.originalText, item.originalStart, item.originalEnd),
+    suggestedText: formatReviewSuggestionText(item.suggestedText, item.suggestedStart, item.suggestedEnd),
     severity: normalizeReviewSeverity(item.severity),
   }
 }
 
+function formatReviewSuggestionText(text: string, start?: string, end?: string) {
+  if (!start?.trim() || !end?.trim()) {
+    return text
+  }
+  return `${start.trim()} → ${end.trim()}\n${text}`
+}
+
 function buildReviewIssue(item: WorkspaceReviewSuggestion): WorkspaceCueIssue {
   return {
     code: `review-${item.kind}-${item.cueIndex}`,
@@ -832,12 +839,18 @@ function resolveReviewSourceLabel(
   reviewKind: "proofread" | "qa" | string,
   item: SubtitleReviewSuggestionDTO,
 ) {
+  if (reviewKind === "align") {
+    return t("library.workspace.review.sourceAlign")
+  }
   return reviewKind === "qa"
     ? t("library.workspace.review.sourceQa")
     : t("library.workspace.review.sourceProofread")
 }
 
 function resolveReviewDefaultReason(reviewKind: "proofread" | "qa" | string) {
+  if (reviewKind === "align") {
+    return t("library.workspace.review.defaultAlignReason")
+  }
   return reviewKind === "qa"
     ? t("library.workspace.review.defaultQaReason")
     : t("library.workspace.review.defaultProofreadReason")
@@ -850,6 +863,9 @@ function resolveReviewDetailLabel(
   const primaryCategory = item.categories?.find((value) => value.trim()) || item.sourceCode || ""
   const normalized = primaryCategory.trim().toLowerCase()
   if (!normalized) {
+    if (reviewKind === "align") {
+      return t("library.workspace.review.detailAlign")
+    }
     return reviewKind === "qa"
       ? t("library.workspace.review.detailQa")
       : t("library.workspace.review.detailProofread")
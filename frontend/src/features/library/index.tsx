@@ -198,7 +198,22 @@ const VIDEO_IMPORT_EXTENSIONS = [
   "ogv",
 ]
 
-const SUBTITLE_IMPORT_EXTENSIONS = ["srt", "vtt", "webvtt", "ass", "ssa", "itt", "ttml", "xml", "dfxp", "fcpxml"]
+const SUBTITLE_IMPORT_EXTENSIONS = [
+  "srt",
+  "vtt",
+  "webvtt",
+  "ass",
+  "ssa",
+  "itt",
+  "ttml",
+  "xml",
+  "dfxp",
+  "fcpxml",
+  "sbv",
+  "sub",
+  "lrc",
+  "stl",
+]
 const RESOURCE_LIBRARY_GRID_BATCH_SIZE = 12
 const RESOURCE_RECORD_BATCH_SIZE = 10
 
@@ -431,17 +446,41 @@ export function LibraryPage() {
       typeLabels,
       jobTypeLabels: {
         download: t("library.jobType.ytdlp"),
+        download_playlist: t("library.jobType.downloadPlaylist"),
         transcode: t("library.jobType.transcode"),
         subtitle_translate: t("library.jobType.subtitleTranslate"),
         subtitle_proofread: t("library.jobType.subtitleProofread"),
         subtitle_qa_review: t("library.jobType.subtitleQaReview"),
+        subtitle_transcribe: t("library.jobType.subtitleTranscribe"),
+        subtitle_ocr: t("library.jobType.subtitleOcr"),
+        subtitle_extract: t("library.jobType.subtitleExtract"),
+        subtitle_align: t("library.jobType.subtitleAlign"),
+        subtitle_bilingual_merge: t("library.jobType.subtitleBilingualMerge"),
+        subtitle_bilingual_split: t("library.jobType.subtitleBilingualSplit"),
+        subtitle_glossary_extract: t("library.jobType.subtitleGlossaryExtract"),
+        subtitle_pipeline: t("library.jobType.subtitlePipeline"),
+        clip: t("library.jobType.clip"),
+        chapter_split: t("library.jobType.chapterSplit"),
+        thumbnail_generate: t("library.jobType.thumbnailGenerate"),
       },
       originLabels: {
         download: typeLabels.manual,
+        download_playlist: typeLabels.manual,
         transcode: typeLabels.transcode,
         subtitle_translate: typeLabels.subtitle,
         subtitle_proofread: typeLabels.subtitle,
         subtitle_qa_review: typeLabels.subtitle,
+        subtitle_transcribe: typeLabels.subtitle,
+        subtitle_ocr: typeLabels.subtitle,
+        subtitle_extract: typeLabels.subtitle,
+        subtitle_align: typeLabels.subtitle,
+        subtitle_bilingual_merge: typeLabels.subtitle,
+        subtitle_bilingual_split: typeLabels.subtitle,
+        subtitle_glossary_extract: typeLabels.subtitle,
+        subtitle_pipeline: typeLabels.subtitle,
+        clip: typeLabels.transcode,
+        chapter_split: typeLabels.transcode,
+        thumbnail_generate: typeLabels.thumbnail,
         import_video: typeLabels.import,
         import_subtitle: typeLabels.import,
       },
@@ -1670,6 +1709,7 @@ export function LibraryPage() {
         path: importVideoPath.trim(),
         libraryId: importTargetMode === "existing" ? importTargetLibraryID || undefined : undefined,
         title: importVideoTitle.trim(),
+        extractSubtitles: true,
         source: "import",
       },
       "video",
@@ -4313,9 +4353,16 @@ function resolveLibraryCoverFile(library?: LibraryDTO) {
   if (!library) {
     return undefined
   }
-  return sortByUpdatedAtDesc(
-    (library.files ?? []).filter((file) => normalizeLibraryKind(file.kind) === "thumbnail" && !file.state.deleted),
-  ).find((file) => Boolean(file.storage.localPath?.trim()))
+  const thumbnails = sortByUpdatedAtDesc(
+    (library.files ?? []).filter(
+      (file) => normalizeLibraryKind(file.kind) === "thumbnail" && !file.state.deleted && Boolean(file.storage.localPath?.trim()),
+    ),
+  )
+  // A cover chosen for a media file wins over the most recent thumbnail.
+  const coverIDs = new Set(
+    (library.files ?? []).map((file) => file.media?.coverFileId?.trim() ?? "").filter((id) => id !== ""),
+  )
+  return thumbnails.find((file) => coverIDs.has(file.id)) ?? thumbnails[0]
 }
 
 function resolveLibraryCoverPath(library?: LibraryDTO) {
@@ -4384,6 +4431,30 @@ function resolveHistoryActionLabel(action: string, t: Translator) {
       return t("library.workspace.actions.proofread")
     case "subtitle_qa_review":
       return t("library.workspace.header.qa")
+    case "subtitle_transcribe":
+      return t("library.jobType.subtitleTranscribe")
+    case "subtitle_align":
+      return t("library.jobType.subtitleAlign")
+    case "subtitle_bilingual_merge":
+      return t("library.jobType.subtitleBilingualMerge")
+    case "subtitle_bilingual_split":
+      return t("library.jobType.subtitleBilingualSplit")
+    case "subtitle_glossary_extract":
+      return t("library.jobType.subtitleGlossaryExtract")
+    case "subtitle_pipeline":
+      return t("library.jobType.subtitlePipeline")
+    case "download_playlist":
+      return t("library.jobType.downloadPlaylist")
+    case "clip":
+      return t("library.jobType.clip")
+    case "chapter_split":
+      return t("library.jobType.chapterSplit")
+    case "subtitle_ocr":
+      return t("library.jobType.subtitleOcr")
+    case "subtitle_extract":
+      return t("library.jobType.subtitleExtract")
+    case "thumbnail_generate":
+      return t("library.jobType.thumbnailGenerate")
     case "import_video":
       return t("library.actions.importVideo")
     case "import_subtitle":
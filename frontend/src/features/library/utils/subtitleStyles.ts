@@ -584,10 +584,16 @@ export function normalizeSubtitleExportFormat(value: string) {
     case "ssa":
     case "itt":
     case "fcpxml":
-      return normalized === "webvtt" ? "vtt" : normalized
     case "ttml":
-    case "xml":
+    case "sbv":
+    case "sub":
+    case "lrc":
+    case "stl":
+      return normalized === "webvtt" ? "vtt" : normalized
     case "dfxp":
+    case "imsc":
+      return "ttml"
+    case "xml":
       return "itt"
     default:
       return "srt"
@@ -75,6 +75,16 @@ type BuiltinVideoScaleSpec = {
   scale: TranscodeScaleMode;
 };
 
+type BuiltinPlatformPresetSpec = {
+  id: string;
+  name: string;
+  aspectRatio: string;
+  reframeMode: string;
+  scale: TranscodeScaleMode;
+  maxBitrateKbps: number;
+  maxFileSizeMb?: number;
+};
+
 type BuiltinAudioPresetSpec = {
   id: string;
   name: string;
@@ -173,6 +183,82 @@ const BUILTIN_VIDEO_SCALE_SPECS: BuiltinVideoScaleSpec[] = [
   { idSuffix: "480p", nameSuffix: "480p", scale: "480p" },
 ];
 
+const BUILTIN_PLATFORM_PRESET_SPECS: BuiltinPlatformPresetSpec[] = [
+  {
+    id: "builtin-platform-youtube-1080p",
+    name: "YouTube 1080p",
+    aspectRatio: "16:9",
+    reframeMode: "pad",
+    scale: "1080p",
+    maxBitrateKbps: 12000,
+  },
+  {
+    id: "builtin-platform-youtube-shorts",
+    name: "YouTube Shorts",
+    aspectRatio: "9:16",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 10000,
+  },
+  {
+    id: "builtin-platform-tiktok",
+    name: "TikTok",
+    aspectRatio: "9:16",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 8000,
+    maxFileSizeMb: 287,
+  },
+  {
+    id: "builtin-platform-douyin",
+    name: "Douyin",
+    aspectRatio: "9:16",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 8000,
+  },
+  {
+    id: "builtin-platform-bilibili-1080p",
+    name: "Bilibili 1080p",
+    aspectRatio: "16:9",
+    reframeMode: "pad",
+    scale: "1080p",
+    maxBitrateKbps: 6000,
+  },
+  {
+    id: "builtin-platform-bilibili-vertical",
+    name: "Bilibili Vertical",
+    aspectRatio: "9:16",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 6000,
+  },
+  {
+    id: "builtin-platform-xiaohongshu",
+    name: "Xiaohongshu",
+    aspectRatio: "3:4",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 8000,
+  },
+  {
+    id: "builtin-platform-square",
+    name: "Square 1:1",
+    aspectRatio: "1:1",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 8000,
+  },
+  {
+    id: "builtin-platform-feed-4x5",
+    name: "Feed 4:5",
+    aspectRatio: "4:5",
+    reframeMode: "blur",
+    scale: "1080p",
+    maxBitrateKbps: 8000,
+  },
+];
+
 const BUILTIN_AUDIO_PRESET_SPECS: BuiltinAudioPresetSpec[] = [
   {
     id: "builtin-audio-mp3-128k",
@@ -278,6 +364,29 @@ function buildBuiltinVideoPresets(): TranscodePreset[] {
   );
 }
 
+function buildBuiltinPlatformPresets(): TranscodePreset[] {
+  return BUILTIN_PLATFORM_PRESET_SPECS.map((spec) => ({
+    id: spec.id,
+    name: spec.name,
+    outputType: "video" as const,
+    container: "mp4",
+    videoCodec: "h264",
+    audioCodec: "aac",
+    qualityMode: "crf" as const,
+    crf: DEFAULT_H264_CRF,
+    audioBitrateKbps: resolveRecommendedAudioBitrateKbps("aac"),
+    scale: spec.scale,
+    ffmpegPreset: DEFAULT_FFMPEG_PRESET,
+    allowUpscale: true,
+    requiresVideo: true,
+    aspectRatio: spec.aspectRatio,
+    reframeMode: spec.reframeMode,
+    maxBitrateKbps: spec.maxBitrateKbps,
+    maxFileSizeMb: spec.maxFileSizeMb,
+    isBuiltin: true,
+  }));
+}
+
 function buildBuiltinAudioPresets(): TranscodePreset[] {
   return BUILTIN_AUDIO_PRESET_SPECS.map((spec) => ({
     id: spec.id,
@@ -293,6 +402,7 @@ function buildBuiltinAudioPresets(): TranscodePreset[] {
 
 export const BUILTIN_PRESETS: TranscodePreset[] = [
   ...buildBuiltinVideoPresets(),
+  ...buildBuiltinPlatformPresets(),
   ...buildBuiltinAudioPresets(),
 ];
 
@@ -313,6 +423,9 @@ export function buildPresetSummary(
     } else {
       parts.push(t("library.workspace.transcode.summary.original"));
     }
+    if (preset.aspectRatio) {
+      parts.push(preset.aspectRatio);
+    }
     if (preset.qualityMode === "bitrate" && preset.bitrateKbps) {
       parts.push(
         t("library.workspace.transcode.summary.kbps").replace(
@@ -439,6 +552,10 @@ export function normalizePresetForOutput(
       scale: "original",
       width: undefined,
       height: undefined,
+      aspectRatio: undefined,
+      reframeMode: undefined,
+      maxBitrateKbps: undefined,
+      maxFileSizeMb: undefined,
       requiresVideo: false,
       requiresAudio: true,
       audioBitrateKbps:
@@ -465,6 +582,7 @@ export function normalizePresetForOutput(
       resolveRecommendedAudioBitrateKbps(preset.audioCodec || "aac"),
     ffmpegPreset: preset.ffmpegPreset || DEFAULT_FFMPEG_PRESET,
     requiresVideo: true,
+    trimSilence: false,
   };
 }
 
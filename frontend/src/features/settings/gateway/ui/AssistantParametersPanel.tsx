@@ -34,6 +34,7 @@ import type {
   AssistantUser,
   UserExtraField,
 } from "@/shared/store/assistant";
+import type { GatewayToolSpec } from "@/shared/store/gatewayTools";
 
 import { AssistantEmojiPicker } from "./AssistantEmojiPicker";
 import { Assistant3DAvatar } from "./Assistant3DAvatar";
@@ -103,6 +104,11 @@ const TOOL_CATEGORY_ORDER = [
   "other",
 ] as const;
 
+// MCP tools are mounted from the assistant's own server list, so they are not
+// toggled individually alongside the builtin tools.
+const isAssistantToggleableTool = (spec?: GatewayToolSpec) =>
+  Boolean(spec && typeof spec.id === "string" && spec.id.trim() && spec.kind !== "mcp");
+
 const resolveToolCategoryId = (value?: string) => {
   const normalized = value?.trim().toLowerCase();
   return normalized || "general";
@@ -375,7 +381,7 @@ export function AssistantParametersPanel({
         .map((item) => [String(item.id), Boolean(item.enabled)])
     );
     return (gatewayTools ?? [])
-      .filter((spec) => spec && typeof spec.id === "string" && spec.id.trim())
+      .filter(isAssistantToggleableTool)
       .map((spec) => {
         const specId = spec.id.trim();
         const defaultEnabled = items.length === 0 ? true : Boolean(lookup.get(specId));
@@ -436,14 +442,14 @@ export function AssistantParametersPanel({
 
   const handleToolToggle = (toolId: string, enabled: boolean) => {
     const nextItems = (gatewayTools ?? [])
-      .filter((spec) => spec && typeof spec.id === "string" && spec.id.trim())
+      .filter(isAssistantToggleableTool)
       .map((spec) => {
         const specId = spec.id.trim();
         const current = toolEntryById.get(specId);
         const resolved = specId === toolId ? enabled : current?.enabled ?? true;
         return { id: specId, enabled: spec.enabled === false ? false : resolved };
       });
-    updateTools({ items: nextItems }, true);
+    updateTools({ ...tools, items: nextItems }, true);
   };
 
   const handleSkillsEnabledChange = (enabled: boolean) => {
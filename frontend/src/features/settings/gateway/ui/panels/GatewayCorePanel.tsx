@@ -75,6 +75,7 @@ export function GatewayCorePanel({
   onExecPermissionModeChange,
   updateGateway,
 }: GatewayCorePanelProps) {
+  const isSandboxDisabled = isDisabled || !(gateway?.sandboxEnabled ?? true);
   return renderRows([
     <div className={rowClassName}>
       {renderRowLabel(
@@ -118,6 +119,68 @@ export function GatewayCorePanel({
         onCheckedChange={(value) => updateGateway({ sandboxEnabled: value })}
       />
     </div>,
+    <div className={rowClassName}>
+      {renderRowLabel(
+        t("settings.gateway.detailsPanel.gateway.sandboxNetwork"),
+        t("settings.gateway.detailsPanel.gateway.sandboxNetworkHelp")
+      )}
+      <Switch
+        checked={gateway?.sandbox?.allowNetwork ?? true}
+        disabled={isSandboxDisabled}
+        onCheckedChange={(value) => updateGateway({ sandbox: { allowNetwork: value } })}
+      />
+    </div>,
+    <div className={rowClassName}>
+      {renderRowLabel(
+        t("settings.gateway.detailsPanel.gateway.sandboxCpuSeconds"),
+        t("settings.gateway.detailsPanel.gateway.sandboxCpuSecondsHelp")
+      )}
+      <Input
+        type="number"
+        min={0}
+        value={gateway?.sandbox?.cpuSeconds ?? 0}
+        size="compact"
+        className={controlClassName}
+        disabled={isSandboxDisabled}
+        onChange={(event) =>
+          updateGateway({ sandbox: { cpuSeconds: Math.max(0, Number(event.target.value) || 0) } })
+        }
+      />
+    </div>,
+    <div className={rowClassName}>
+      {renderRowLabel(
+        t("settings.gateway.detailsPanel.gateway.sandboxMemoryMb"),
+        t("settings.gateway.detailsPanel.gateway.sandboxMemoryMbHelp")
+      )}
+      <Input
+        type="number"
+        min={0}
+        value={gateway?.sandbox?.memoryMb ?? 0}
+        size="compact"
+        className={controlClassName}
+        disabled={isSandboxDisabled}
+        onChange={(event) =>
+          updateGateway({ sandbox: { memoryMb: Math.max(0, Number(event.target.value) || 0) } })
+        }
+      />
+    </div>,
+    <div className={rowClassName}>
+      {renderRowLabel(
+        t("settings.gateway.detailsPanel.gateway.sandboxTimeoutSeconds"),
+        t("settings.gateway.detailsPanel.gateway.sandboxTimeoutSecondsHelp")
+      )}
+      <Input
+        type="number"
+        min={0}
+        value={gateway?.sandbox?.timeoutSeconds ?? 0}
+        size="compact"
+        className={controlClassName}
+        disabled={isSandboxDisabled}
+        onChange={(event) =>
+          updateGateway({ sandbox: { timeoutSeconds: Math.max(0, Number(event.target.value) || 0) } })
+        }
+      />
+    </div>,
     <div className={rowClassName}>
       {renderRowLabel(
         t("settings.gateway.detailsPanel.runtime.maxSteps"),
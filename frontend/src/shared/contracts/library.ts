@@ -58,6 +58,24 @@ export interface LibraryLanguageAssetsConfigDTO {
   promptProfiles: LibraryPromptProfileDTO[]
 }
 
+export interface LibraryDeliveryProfileDTO {
+  id: string
+  name: string
+  description?: string
+  maxCps: number
+  maxCharsPerLine: number
+  maxLines: number
+  minGapMs: number
+  minDurationMs: number
+  maxDurationMs: number
+}
+
+export interface LibrarySubtitleDeliveryConfigDTO {
+  builtin: LibraryDeliveryProfileDTO[]
+  custom: LibraryDeliveryProfileDTO[]
+  defaultProfileId: string
+}
+
 export interface LibraryTaskRuntimeSettingsDTO {
   structuredOutputMode: string
   thinkingMode: string
@@ -71,6 +89,12 @@ export interface LibraryTaskRuntimeConfigDTO {
   proofread: LibraryTaskRuntimeSettingsDTO
 }
 
+export interface LibraryOperationQueueConfigDTO {
+  downloadConcurrency: number
+  transcodeConcurrency: number
+  rateLimit?: string
+}
+
 export interface LibrarySubtitleStyleDefaultsDTO {
   monoStyleId?: string
   bilingualStyleId?: string
@@ -342,7 +366,9 @@ export interface LibraryModuleConfigDTO {
   translateLanguages: LibraryTranslateLanguagesConfigDTO
   languageAssets: LibraryLanguageAssetsConfigDTO
   subtitleStyles: LibrarySubtitleStyleConfigDTO
+  subtitleDelivery: LibrarySubtitleDeliveryConfigDTO
   taskRuntime: LibraryTaskRuntimeConfigDTO
+  queue: LibraryOperationQueueConfigDTO
 }
 
 export interface LibraryFileStorageDTO {
@@ -366,6 +392,7 @@ export interface LibraryFileOriginDTO {
 
 export interface LibraryFileLineageDTO {
   rootFileId?: string
+  sourceFileIds?: string[]
 }
 
 export interface LibraryMediaInfoDTO {
@@ -382,6 +409,26 @@ export interface LibraryMediaInfoDTO {
   sizeBytes?: number
   language?: string
   cueCount?: number
+  loudnessLufs?: number
+  chapters?: LibraryMediaChapterDTO[]
+  subtitleStreams?: LibraryMediaSubtitleStreamDTO[]
+  coverFileId?: string
+}
+
+export interface LibraryMediaChapterDTO {
+  startMs: number
+  endMs?: number
+  title: string
+}
+
+export interface LibraryMediaSubtitleStreamDTO {
+  index: number
+  codec?: string
+  language?: string
+  title?: string
+  default?: boolean
+  forced?: boolean
+  textBased: boolean
 }
 
 export interface LibraryFileStateDTO {
@@ -494,6 +541,27 @@ export interface OperationListItemDTO {
   startedAt?: string
   finishedAt?: string
   createdAt: string
+  queue?: OperationQueueEntryDTO
+}
+
+export interface OperationQueueEntryDTO {
+  kind: string
+  position: number
+  priority: number
+  paused?: boolean
+}
+
+export interface OperationQueueKindDTO {
+  kind: string
+  concurrency: number
+  running: number
+  waiting: number
+}
+
+export interface OperationQueueStateDTO {
+  paused: boolean
+  rateLimit?: string
+  kinds: OperationQueueKindDTO[]
 }
 
 export interface LibraryHistoryRecordSourceDTO {
@@ -569,6 +637,8 @@ export interface WorkspaceTrackTasksDTO {
   translate?: WorkspaceTaskSummaryDTO[]
   proofread?: WorkspaceTaskSummaryDTO
   qa?: WorkspaceTaskSummaryDTO
+  align?: WorkspaceTaskSummaryDTO
+  bilingual?: WorkspaceTaskSummaryDTO
 }
 
 export interface WorkspaceTrackPendingReviewDTO {
@@ -711,6 +781,15 @@ export interface CancelOperationRequest {
   operationId: string
 }
 
+export interface SetOperationQueuePausedRequest {
+  paused: boolean
+}
+
+export interface SetOperationPriorityRequest {
+  operationId: string
+  priority: number
+}
+
 export interface ResumeOperationRequest {
   operationId: string
 }
@@ -829,6 +908,42 @@ export interface CreateYtdlpJobRequest {
   deleteSourceFileAfterTranscode?: boolean
   connectorId?: string
   useConnector?: boolean
+  playlistItems?: string
+  downloadSections?: YtdlpDownloadSection[]
+  priority?: number
+}
+
+export interface YtdlpDownloadSection {
+  startMs: number
+  endMs?: number
+}
+
+export interface CreateYtdlpPlaylistJobRequest {
+  url: string
+  libraryId?: string
+  title?: string
+  extractor?: string
+  author?: string
+  thumbnailUrl?: string
+  entries: YtdlpPlaylistEntry[]
+  maxConcurrency?: number
+  writeThumbnail?: boolean
+  quality?: string
+  formatId?: string
+  subtitleLangs?: string[]
+  subtitleAuto?: boolean
+  subtitleAll?: boolean
+  subtitleFormat?: string
+  transcodePresetId?: string
+  deleteSourceFileAfterTranscode?: boolean
+  connectorId?: string
+  useConnector?: boolean
+  logPolicy?: string
+  priority?: number
+  source?: string
+  caller?: string
+  sessionKey?: string
+  runId?: string
 }
 
 export interface CheckYtdlpOperationFailureRequest {
@@ -879,6 +994,7 @@ export interface PrepareYtdlpDownloadResponse {
   connectorId?: string
   connectorAvailable: boolean
   reachable?: boolean
+  playlist?: boolean
 }
 
 export interface ResolveDomainIconRequest {
@@ -895,6 +1011,8 @@ export interface ParseYtdlpDownloadRequest {
   url: string
   connectorId?: string
   useConnector?: boolean
+  playlist?: boolean
+  playlistLimit?: number
 }
 
 export interface YtdlpFormatOption {
@@ -925,6 +1043,30 @@ export interface ParseYtdlpDownloadResponse {
   thumbnailUrl?: string
   formats: YtdlpFormatOption[]
   subtitles: YtdlpSubtitleOption[]
+  playlist?: YtdlpPlaylistInfo
+}
+
+export interface YtdlpPlaylistEntry {
+  index: number
+  id?: string
+  url: string
+  title?: string
+  author?: string
+  durationSeconds?: number
+  thumbnailUrl?: string
+}
+
+export type YtdlpPlaylistKind = "playlist" | "channel"
+
+export interface YtdlpPlaylistInfo {
+  id?: string
+  kind: YtdlpPlaylistKind
+  title?: string
+  author?: string
+  url: string
+  entryCount: number
+  truncated?: boolean
+  entries: YtdlpPlaylistEntry[]
 }
 
 export interface CreateSubtitleImportRequest {
@@ -940,6 +1082,7 @@ export interface CreateVideoImportRequest {
   path: string
   libraryId?: string
   title?: string
+  extractSubtitles?: boolean
   source?: string
   sessionKey?: string
   runId?: string
@@ -966,6 +1109,11 @@ export interface CreateTranscodeJobRequest {
   scale?: string
   width?: number
   height?: number
+  aspectRatio?: string
+  reframeMode?: string
+  maxBitrateKbps?: number
+  maxFileSizeMb?: number
+  variantPresetIds?: string[]
   subtitleHandling?: string
   subtitleFileId?: string
   secondarySubtitleFileId?: string
@@ -977,6 +1125,76 @@ export interface CreateTranscodeJobRequest {
   generatedSubtitleDocument?: SubtitleDocument
   generatedSubtitleContent?: string
   deleteSourceFileAfterTranscode?: boolean
+  embedCover?: boolean
+  priority?: number
+}
+
+export interface CreateClipJobRequest {
+  fileId: string
+  startMs: number
+  endMs: number
+  title?: string
+  reencode?: boolean
+  includeSubtitle?: boolean
+  subtitleFileId?: string
+  source?: string
+  sessionKey?: string
+  runId?: string
+  priority?: number
+}
+
+export interface UpdateFileChaptersRequest {
+  fileId: string
+  chapters?: LibraryMediaChapterDTO[]
+  timestamps?: string
+}
+
+export interface ExportFileChaptersRequest {
+  fileId: string
+  format: "youtube" | "ffmetadata"
+}
+
+export interface ExportFileChaptersResult {
+  fileId: string
+  format: string
+  content: string
+  chapterCount: number
+}
+
+export interface CreateChapterSplitJobRequest {
+  fileId: string
+  reencode?: boolean
+  source?: string
+  sessionKey?: string
+  runId?: string
+  priority?: number
+}
+
+export interface CreateSubtitleExtractJobRequest {
+  fileId: string
+  streamIndexes?: number[]
+  outputFormat?: "srt" | "vtt" | "ass"
+  source?: string
+  sessionKey?: string
+  runId?: string
+  priority?: number
+}
+
+export interface CreateThumbnailJobRequest {
+  fileId: string
+  count?: number
+  sceneThreshold?: number
+  columns?: number
+  coverIndex?: number
+  source?: string
+  sessionKey?: string
+  runId?: string
+  priority?: number
+}
+
+export interface SetFileCoverRequest {
+  fileId: string
+  coverFileId?: string
 }
 
 export interface ListTranscodePresetsForDownloadRequest {
@@ -1002,6 +1220,16 @@ export interface TranscodePreset {
   requiresVideo?: boolean
   requiresAudio?: boolean
   isBuiltin?: boolean
+  loudnessNormalize?: boolean
+  loudnessTargetI?: number
+  loudnessTruePeak?: number
+  loudnessRange?: number
+  noiseReduction?: boolean
+  trimSilence?: boolean
+  aspectRatio?: string
+  reframeMode?: string
+  maxBitrateKbps?: number
+  maxFileSizeMb?: number
   createdAt?: string
   updatedAt?: string
 }
@@ -1010,6 +1238,47 @@ export interface DeleteTranscodePresetRequest {
   id: string
 }
 
+export interface LibrarySubscriptionDTO {
+  id: string
+  name: string
+  url: string
+  libraryId?: string
+  connectorId?: string
+  useConnector?: boolean
+  quality?: string
+  formatId?: string
+  subtitleLangs?: string[]
+  subtitleAuto?: boolean
+  subtitleFormat?: string
+  transcodePresetId?: string
+  deleteSourceFileAfterTranscode?: boolean
+  pollIntervalMinutes: number
+  downloadExisting?: boolean
+  enabled: boolean
+  archivedCount: number
+  lastCheckedAt?: string
+  lastError?: string
+  createdAt?: string
+  updatedAt?: string
+}
+
+export interface DeleteSubscriptionRequest {
+  id: string
+}
+
+export interface PollSubscriptionRequest {
+  id: string
+}
+
+export interface PollSubscriptionResult {
+  subscription: LibrarySubscriptionDTO
+  entryCount: number
+  newEntryCount: number
+  seededCount?: number
+  operationIds: string[]
+  skipped?: boolean
+}
+
 export interface SubtitleCue {
   index: number
   start: string
@@ -1059,6 +1328,9 @@ export interface SubtitleExportConfig {
   ass?: SubtitleASSExportConfig
   itt?: SubtitleITTExportConfig
   fcpxml?: SubtitleFCPXMLExportConfig
+  ttml?: SubtitleTTMLExportConfig
+  sub?: SubtitleSUBExportConfig
+  stl?: SubtitleSTLExportConfig
 }
 
 export interface SubtitleSRTExportConfig {
@@ -1095,6 +1367,20 @@ export interface SubtitleFCPXMLExportConfig {
   startTimecodeSeconds?: number
 }
 
+export interface SubtitleTTMLExportConfig {
+  language?: string
+}
+
+export interface SubtitleSUBExportConfig {
+  frameRate?: number
+}
+
+export interface SubtitleSTLExportConfig {
+  frameRate?: number
+  language?: string
+  title?: string
+}
+
 export interface SubtitleExportRequest {
   exportPath: string
   fileId?: string
@@ -1121,6 +1407,8 @@ export interface SubtitleValidateRequest {
   content?: string
   format?: string
   document?: SubtitleDocument
+  deliveryProfileId?: string
+  deliveryProfile?: LibraryDeliveryProfileDTO
 }
 
 export interface SubtitleValidateIssue {
@@ -1134,12 +1422,19 @@ export interface SubtitleValidateResult {
   valid: boolean
   issueCount: number
   issues?: SubtitleValidateIssue[]
+  deliveryProfileId?: string
 }
 
 export interface SubtitleReviewSuggestionDTO {
   cueIndex: number
   originalText: string
   suggestedText: string
+  originalStart?: string
+  originalEnd?: string
+  suggestedStart?: string
+  suggestedEnd?: string
+  suggestedCues?: SubtitleCue[]
+  mergedCueIndexes?: number[]
   categories?: string[]
   reason?: string
   sourceCode?: string
@@ -1185,6 +1480,8 @@ export interface SubtitleSaveRequest {
   targetFormat?: string
   content?: string
   document?: SubtitleDocument
+  sessionKey?: string
+  runId?: string
 }
 
 export interface SubtitleSaveResult {
@@ -1208,6 +1505,7 @@ export interface SubtitleTranslateRequest {
   referenceTrackFileIds?: string[]
   promptProfileIds?: string[]
   inlinePrompt?: string
+  disableTranslationMemory?: boolean
   sessionKey?: string
   runId?: string
 }
@@ -1240,10 +1538,173 @@ export interface SubtitleQAReviewRequest {
   outputFormat?: string
   source?: string
   normalizeWhitespace?: boolean
+  deliveryProfileId?: string
+  deliveryAutoFix?: boolean
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleTranscribeRequest {
+  fileId: string
+  libraryId?: string
+  language?: string
+  modelPath?: string
+  outputFormat?: string
+  prompt?: string
+  threads?: number
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleOCRRequest {
+  fileId: string
+  libraryId?: string
+  language?: string
+  sampleIntervalMs?: number
+  regionTop?: number
+  regionHeight?: number
+  outputFormat?: string
+  threads?: number
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleRetimeAnchor {
+  cueIndex?: number
+  sourceMs?: number
+  targetMs: number
+}
+
+export interface SubtitleRetimeRange {
+  startMs: number
+  endMs?: number
+  offsetMs: number
+}
+
+export interface SubtitleRetimeRequest {
+  fileId?: string
+  documentId?: string
+  path?: string
+  content?: string
+  format?: string
+  document?: SubtitleDocument
+  mode: "offset" | "stretch" | "framerate" | "ranges"
+  offsetMs?: number
+  anchors?: SubtitleRetimeAnchor[]
+  fromFps?: number
+  toFps?: number
+  ranges?: SubtitleRetimeRange[]
+  dryRun?: boolean
+}
+
+export interface SubtitleRetimeResult {
+  fileId?: string
+  format: string
+  content: string
+  cueCount: number
+  changedCueCount: number
+  sourceRevisionId?: string
+  revisionId?: string
+  document: SubtitleDocument
+}
+
+export interface SubtitleAlignRequest {
+  fileId?: string
+  documentId?: string
+  path?: string
+  libraryId?: string
+  videoFileId?: string
+  maxOffsetMs?: number
+  noiseThresholdDb?: number
+  minSilenceMs?: number
+  minChangeMs?: number
+  source?: string
   sessionKey?: string
   runId?: string
 }
 
+export interface SubtitleBilingualMergeRequest {
+  libraryId?: string
+  primaryFileId: string
+  secondaryFileId: string
+  outputFormat?: string
+  minOverlapRatio?: number
+  unmatchedMode?: "keep" | "drop"
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleBilingualSplitRequest {
+  fileId?: string
+  documentId?: string
+  path?: string
+  libraryId?: string
+  splitMode?: "auto" | "script" | "halves"
+  primaryLanguage?: string
+  secondaryLanguage?: string
+  outputFormat?: string
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleGlossaryExtractRequest {
+  fileIds?: string[]
+  libraryId?: string
+  assistantId?: string
+  sourceLanguage?: string
+  targetLanguage: string
+  glossaryProfileId?: string
+  minOccurrences?: number
+  maxCandidates?: number
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export type SubtitlePipelineStepKind = "proofread" | "translate" | "qa_review" | "export"
+
+export interface SubtitlePipelineStep {
+  kind: SubtitlePipelineStepKind
+  targetLanguages?: string[]
+  autoApply?: boolean
+  exportDirectory?: string
+  exportFormat?: string
+  exportConfig?: SubtitleExportConfig
+  translate?: Partial<SubtitleTranslateRequest>
+  proofread?: Partial<SubtitleProofreadRequest>
+  qaReview?: Partial<SubtitleQAReviewRequest>
+}
+
+export interface SubtitlePipelineRequest {
+  fileIds?: string[]
+  libraryId?: string
+  steps: SubtitlePipelineStep[]
+  maxConcurrency?: number
+  source?: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface SubtitleGlossaryTermCandidateDTO {
+  source: string
+  target: string
+  category?: string
+  note?: string
+  occurrences: number
+  example?: string
+}
+
+export interface AcceptGlossaryTermCandidatesRequest {
+  operationId?: string
+  glossaryProfileId?: string
+  profileName?: string
+  terms?: LibraryGlossaryTermDTO[]
+}
+
 export interface RestoreSubtitleOriginalRequest {
   fileId?: string
   documentId?: string
@@ -1255,3 +1716,75 @@ export interface RestoreSubtitleOriginalResult {
   format: string
   bytes: number
 }
+
+export interface ListSubtitleRevisionsRequest {
+  fileId?: string
+  documentId?: string
+  limit?: number
+}
+
+export interface SubtitleRevisionDTO {
+  id: string
+  libraryId: string
+  fileId: string
+  format: string
+  sourceKind: string
+  actor: string
+  candidate: boolean
+  current: boolean
+  operationId?: string
+  operationKind?: string
+  operationName?: string
+  reviewSessionId?: string
+  cueCount: number
+  bytes: number
+  createdAt: string
+}
+
+export interface DiffSubtitleRevisionsRequest {
+  fileId?: string
+  fromRevisionId: string
+  toRevisionId?: string
+  includeUnchanged?: boolean
+}
+
+export interface SubtitleRevisionDiffCueDTO {
+  status: string
+  fromIndex?: number
+  toIndex?: number
+  fromStart?: string
+  fromEnd?: string
+  toStart?: string
+  toEnd?: string
+  fromText?: string
+  toText?: string
+  textChanged?: boolean
+  timingChanged?: boolean
+}
+
+export interface SubtitleRevisionDiffResult {
+  fileId: string
+  fromRevisionId: string
+  toRevisionId: string
+  addedCount: number
+  removedCount: number
+  modifiedCount: number
+  unchangedCount: number
+  cues: SubtitleRevisionDiffCueDTO[]
+}
+
+export interface RestoreSubtitleRevisionRequest {
+  fileId?: string
+  revisionId: string
+  sessionKey?: string
+  runId?: string
+}
+
+export interface RestoreSubtitleRevisionResult {
+  fileId: string
+  restoredRevisionId: string
+  revisionId: string
+  snapshotRevisionId?: string
+  format: string
+  bytes: number
+}
@@ -188,6 +188,7 @@ export interface GatewaySettings {
   controlPlaneEnabled: boolean;
   voiceEnabled: boolean;
   sandboxEnabled: boolean;
+  sandbox: GatewaySandboxSettings;
   voiceWakeEnabled: boolean;
   http: GatewayHTTPSettings;
   channelHealthCheckMinutes: number;
@@ -345,6 +346,13 @@ export interface GatewaySubagentToolPolicy {
   deny: string[];
 }
 
+export interface GatewaySandboxSettings {
+  allowNetwork: boolean;
+  cpuSeconds: number;
+  memoryMb: number;
+  timeoutSeconds: number;
+}
+
 export interface GatewayCronSettings {
   enabled: boolean;
   maxConcurrentRuns: number;
@@ -408,6 +416,7 @@ export interface UpdateGatewaySettingsRequest {
   controlPlaneEnabled?: boolean;
   voiceEnabled?: boolean;
   sandboxEnabled?: boolean;
+  sandbox?: UpdateGatewaySandboxSettingsRequest;
   voiceWakeEnabled?: boolean;
   http?: UpdateGatewayHTTPSettingsRequest;
   channelHealthCheckMinutes?: number;
@@ -565,6 +574,13 @@ export interface UpdateGatewaySubagentToolPolicyRequest {
   deny?: string[];
 }
 
+export interface UpdateGatewaySandboxSettingsRequest {
+  allowNetwork?: boolean;
+  cpuSeconds?: number;
+  memoryMb?: number;
+  timeoutSeconds?: number;
+}
+
 export interface UpdateGatewayCronSettingsRequest {
   enabled?: boolean;
   maxConcurrentRuns?: number;
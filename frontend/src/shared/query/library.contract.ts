@@ -4,6 +4,7 @@ import type {
   ApplySubtitleReviewSessionResult,
   CheckYtdlpOperationFailureResponse,
   DiscardSubtitleReviewSessionResult,
+  ExportFileChaptersResult,
   FileEventRecordDTO,
   GenerateSubtitleStylePreviewASSResult,
   GenerateWorkspacePreviewASSResult,
@@ -13,16 +14,23 @@ import type {
   LibraryHistoryRecordDTO,
   LibraryModuleConfigDTO,
   LibraryOperationDTO,
+  LibrarySubscriptionDTO,
   OperationListItemDTO,
+  OperationQueueStateDTO,
   ParseYtdlpDownloadResponse,
+  PollSubscriptionResult,
   PrepareYtdlpDownloadResponse,
   ResolveDomainIconResponse,
   RestoreSubtitleOriginalResult,
+  RestoreSubtitleRevisionResult,
   SubtitleConvertResult,
   SubtitleExportResult,
   SubtitleFixTyposResult,
   SubtitleParseResult,
+  SubtitleRetimeResult,
   SubtitleReviewSessionDetailDTO,
+  SubtitleRevisionDTO,
+  SubtitleRevisionDiffResult,
   SubtitleSaveResult,
   SubtitleValidateResult,
   TranscodePreset,
@@ -123,6 +131,28 @@ const libraryLanguageAssetsConfigSchema = z
   })
   .passthrough()
 
+const libraryDeliveryProfileSchema = z
+  .object({
+    id: z.string(),
+    name: z.string(),
+    description: z.string().optional(),
+    maxCps: z.number(),
+    maxCharsPerLine: z.number(),
+    maxLines: z.number(),
+    minGapMs: z.number(),
+    minDurationMs: z.number(),
+    maxDurationMs: z.number(),
+  })
+  .passthrough()
+
+const librarySubtitleDeliveryConfigSchema = z
+  .object({
+    builtin: z.array(libraryDeliveryProfileSchema),
+    custom: z.array(libraryDeliveryProfileSchema),
+    defaultProfileId: z.string(),
+  })
+  .passthrough()
+
 const libraryTaskRuntimeSettingsSchema = z
   .object({
     structuredOutputMode: z.string(),
@@ -140,6 +170,14 @@ const libraryTaskRuntimeConfigSchema = z
   })
   .passthrough()
 
+const libraryOperationQueueConfigSchema = z
+  .object({
+    downloadConcurrency: z.number(),
+    transcodeConcurrency: z.number(),
+    rateLimit: z.string().optional(),
+  })
+  .passthrough()
+
 const assStyleSpecSchema = z
   .object({
     fontname: z.string(),
@@ -260,6 +298,26 @@ const subtitleExportConfigSchema = z
       })
       .passthrough()
       .optional(),
+    ttml: z
+      .object({
+        language: z.string().optional(),
+      })
+      .passthrough()
+      .optional(),
+    sub: z
+      .object({
+        frameRate: z.number().optional(),
+      })
+      .passthrough()
+      .optional(),
+    stl: z
+      .object({
+        frameRate: z.number().optional(),
+        language: z.string().optional(),
+        title: z.string().optional(),
+      })
+      .passthrough()
+      .optional(),
   })
   .passthrough()
 
@@ -382,7 +440,9 @@ const libraryModuleConfigSchema = z
     translateLanguages: libraryTranslateLanguagesConfigSchema,
     languageAssets: libraryLanguageAssetsConfigSchema,
     subtitleStyles: librarySubtitleStyleConfigSchema,
+    subtitleDelivery: librarySubtitleDeliveryConfigSchema,
     taskRuntime: libraryTaskRuntimeConfigSchema,
+    queue: libraryOperationQueueConfigSchema,
   })
   .passthrough()
 
@@ -414,6 +474,27 @@ const libraryFileOriginSchema = z
 const libraryFileLineageSchema = z
   .object({
     rootFileId: z.string().optional(),
+    sourceFileIds: stringArraySchema.optional(),
+  })
+  .passthrough()
+
+const libraryMediaChapterSchema = z
+  .object({
+    startMs: z.number(),
+    endMs: z.number().optional(),
+    title: z.string(),
+  })
+  .passthrough()
+
+const libraryMediaSubtitleStreamSchema = z
+  .object({
+    index: z.number(),
+    codec: z.string().optional(),
+    language: z.string().optional(),
+    title: z.string().optional(),
+    default: z.boolean().optional(),
+    forced: z.boolean().optional(),
+    textBased: z.boolean(),
   })
   .passthrough()
 
@@ -432,6 +513,10 @@ const libraryMediaInfoSchema = z
     sizeBytes: z.number().optional(),
     language: z.string().optional(),
     cueCount: z.number().optional(),
+    loudnessLufs: z.number().optional(),
+    chapters: z.array(libraryMediaChapterSchema).optional(),
+    subtitleStreams: z.array(libraryMediaSubtitleStreamSchema).optional(),
+    coverFileId: z.string().optional(),
   })
   .passthrough()
 
@@ -564,6 +649,32 @@ const operationListItemSchema = z
     startedAt: z.string().optional(),
     finishedAt: z.string().optional(),
     createdAt: z.string(),
+    queue: z
+      .object({
+        kind: z.string(),
+        position: z.number(),
+        priority: z.number(),
+        paused: z.boolean().optional(),
+      })
+      .passthrough()
+      .optional(),
+  })
+  .passthrough()
+
+const operationQueueStateSchema = z
+  .object({
+    paused: z.boolean(),
+    rateLimit: z.string().optional(),
+    kinds: z.array(
+      z
+        .object({
+          kind: z.string(),
+          concurrency: z.number(),
+          running: z.number(),
+          waiting: z.number(),
+        })
+        .passthrough()
+    ),
   })
   .passthrough()
 
@@ -728,6 +839,8 @@ const workspaceTrackTasksSchema = z
     translate: z.array(workspaceTaskSummarySchema).optional(),
     proofread: workspaceTaskSummarySchema.optional(),
     qa: workspaceTaskSummarySchema.optional(),
+    align: workspaceTaskSummarySchema.optional(),
+    bilingual: workspaceTaskSummarySchema.optional(),
   })
   .passthrough()
 
@@ -813,6 +926,12 @@ const subtitleReviewSessionDetailSchema = z
             cueIndex: z.number(),
             originalText: z.string(),
             suggestedText: z.string(),
+            originalStart: z.string().optional(),
+            originalEnd: z.string().optional(),
+            suggestedStart: z.string().optional(),
+            suggestedEnd: z.string().optional(),
+            suggestedCues: z.array(subtitleCueSchema).optional(),
+            mergedCueIndexes: z.array(z.number()).optional(),
             categories: stringArraySchema.optional(),
             reason: z.string().optional(),
             sourceCode: z.string().optional(),
@@ -888,6 +1007,7 @@ const prepareYtdlpDownloadResponseSchema = z
     connectorId: z.string().optional(),
     connectorAvailable: z.boolean(),
     reachable: z.boolean().optional(),
+    playlist: z.boolean().optional(),
   })
   .passthrough()
 
@@ -931,6 +1051,31 @@ const parseYtdlpDownloadResponseSchema = z
         })
         .passthrough(),
     ),
+    playlist: z
+      .object({
+        id: z.string().optional(),
+        kind: z.enum(["playlist", "channel"]),
+        title: z.string().optional(),
+        author: z.string().optional(),
+        url: z.string(),
+        entryCount: z.number(),
+        truncated: z.boolean().optional(),
+        entries: z.array(
+          z
+            .object({
+              index: z.number(),
+              id: z.string().optional(),
+              url: z.string(),
+              title: z.string().optional(),
+              author: z.string().optional(),
+              durationSeconds: z.number().optional(),
+              thumbnailUrl: z.string().optional(),
+            })
+            .passthrough(),
+        ),
+      })
+      .passthrough()
+      .optional(),
   })
   .passthrough()
 
@@ -954,11 +1099,58 @@ const transcodePresetSchema = z
     requiresVideo: z.boolean().optional(),
     requiresAudio: z.boolean().optional(),
     isBuiltin: z.boolean().optional(),
+    loudnessNormalize: z.boolean().optional(),
+    loudnessTargetI: z.number().optional(),
+    loudnessTruePeak: z.number().optional(),
+    loudnessRange: z.number().optional(),
+    noiseReduction: z.boolean().optional(),
+    trimSilence: z.boolean().optional(),
+    aspectRatio: z.string().optional(),
+    reframeMode: z.string().optional(),
+    maxBitrateKbps: z.number().optional(),
+    maxFileSizeMb: z.number().optional(),
     createdAt: z.string().optional(),
     updatedAt: z.string().optional(),
   })
   .passthrough()
 
+const librarySubscriptionSchema = z
+  .object({
+    id: z.string(),
+    name: z.string(),
+    url: z.string(),
+    libraryId: z.string().optional(),
+    connectorId: z.string().optional(),
+    useConnector: z.boolean().optional(),
+    quality: z.string().optional(),
+    formatId: z.string().optional(),
+    subtitleLangs: stringArraySchema.optional(),
+    subtitleAuto: z.boolean().optional(),
+    subtitleFormat: z.string().optional(),
+    transcodePresetId: z.string().optional(),
+    deleteSourceFileAfterTranscode: z.boolean().optional(),
+    pollIntervalMinutes: z.number(),
+    downloadExisting: z.boolean().optional(),
+    enabled: z.boolean(),
+    archivedCount: z.number(),
+    lastCheckedAt: z.string().optional(),
+    lastError: z.string().optional(),
+    createdAt: z.string().optional(),
+    updatedAt: z.string().optional(),
+  })
+  .passthrough()
+
+const pollSubscriptionResultSchema = z
+  .object({
+    subscription: librarySubscriptionSchema,
+    entryCount: z.number(),
+    newEntryCount: z.number(),
+    seededCount: z.number().optional(),
+    operationIds: stringArraySchema,
+    skipped: z.boolean().optional(),
+  })
+  .passthrough()
+
 const subtitleParseResultSchema = z
   .object({
     format: z.string(),
@@ -984,6 +1176,15 @@ const subtitleExportResultSchema = z
   })
   .passthrough()
 
+const exportFileChaptersResultSchema = z
+  .object({
+    fileId: z.string(),
+    format: z.string(),
+    content: z.string(),
+    chapterCount: z.number(),
+  })
+  .passthrough()
+
 const subtitleValidateResultSchema = z
   .object({
     valid: z.boolean(),
@@ -1000,6 +1201,7 @@ const subtitleValidateResultSchema = z
           .passthrough(),
       )
       .optional(),
+    deliveryProfileId: z.string().optional(),
   })
   .passthrough()
 
@@ -1020,6 +1222,19 @@ const subtitleSaveResultSchema = z
   })
   .passthrough()
 
+const subtitleRetimeResultSchema = z
+  .object({
+    fileId: z.string().optional(),
+    format: z.string(),
+    content: z.string(),
+    cueCount: z.number(),
+    changedCueCount: z.number(),
+    sourceRevisionId: z.string().optional(),
+    revisionId: z.string().optional(),
+    document: subtitleDocumentSchema,
+  })
+  .passthrough()
+
 const restoreSubtitleOriginalResultSchema = z
   .object({
     fileId: z.string().optional(),
@@ -1028,6 +1243,66 @@ const restoreSubtitleOriginalResultSchema = z
   })
   .passthrough()
 
+const subtitleRevisionSchema = z
+  .object({
+    id: z.string(),
+    libraryId: z.string(),
+    fileId: z.string(),
+    format: z.string(),
+    sourceKind: z.string(),
+    actor: z.string(),
+    candidate: z.boolean(),
+    current: z.boolean(),
+    operationId: z.string().optional(),
+    operationKind: z.string().optional(),
+    operationName: z.string().optional(),
+    reviewSessionId: z.string().optional(),
+    cueCount: z.number(),
+    bytes: z.number(),
+    createdAt: z.string(),
+  })
+  .passthrough()
+
+const subtitleRevisionDiffResultSchema = z
+  .object({
+    fileId: z.string(),
+    fromRevisionId: z.string(),
+    toRevisionId: z.string(),
+    addedCount: z.number(),
+    removedCount: z.number(),
+    modifiedCount: z.number(),
+    unchangedCount: z.number(),
+    cues: z.array(
+      z
+        .object({
+          status: z.string(),
+          fromIndex: z.number().optional(),
+          toIndex: z.number().optional(),
+          fromStart: z.string().optional(),
+          fromEnd: z.string().optional(),
+          toStart: z.string().optional(),
+          toEnd: z.string().optional(),
+          fromText: z.string().optional(),
+          toText: z.string().optional(),
+          textChanged: z.boolean().optional(),
+          timingChanged: z.boolean().optional(),
+        })
+        .passthrough(),
+    ),
+  })
+  .passthrough()
+
+const restoreSubtitleRevisionResultSchema = z
+  .object({
+    fileId: z.string(),
+    restoredRevisionId: z.string(),
+    revisionId: z.string(),
+    snapshotRevisionId: z.string().optional(),
+    format: z.string(),
+    bytes: z.number(),
+  })
+  .passthrough()
+
 export function parseLibraryListPayload(input: unknown): LibraryDTO[] {
   return parseContract<LibraryDTO[]>(z.array(librarySchema), input, "library list")
 }
@@ -1044,6 +1319,10 @@ export function parseOperationListPayload(input: unknown): OperationListItemDTO[
   return parseContract<OperationListItemDTO[]>(z.array(operationListItemSchema), input, "operation list")
 }
 
+export function parseOperationQueuePayload(input: unknown): OperationQueueStateDTO {
+  return parseContract<OperationQueueStateDTO>(operationQueueStateSchema, input, "operation queue")
+}
+
 export function parseLibraryOperationPayload(input: unknown): LibraryOperationDTO {
   return parseContract<LibraryOperationDTO>(libraryOperationSchema, input, "library operation")
 }
@@ -1166,6 +1445,18 @@ export function parseTranscodePresetPayload(input: unknown): TranscodePreset {
   return parseContract<TranscodePreset>(transcodePresetSchema, input, "transcode preset")
 }
 
+export function parseLibrarySubscriptionListPayload(input: unknown): LibrarySubscriptionDTO[] {
+  return parseContract<LibrarySubscriptionDTO[]>(z.array(librarySubscriptionSchema), input, "library subscription list")
+}
+
+export function parseLibrarySubscriptionPayload(input: unknown): LibrarySubscriptionDTO {
+  return parseContract<LibrarySubscriptionDTO>(librarySubscriptionSchema, input, "library subscription")
+}
+
+export function parsePollSubscriptionPayload(input: unknown): PollSubscriptionResult {
+  return parseContract<PollSubscriptionResult>(pollSubscriptionResultSchema, input, "subscription poll result")
+}
+
 export function parseSubtitleParsePayload(input: unknown): SubtitleParseResult {
   return parseContract<SubtitleParseResult>(subtitleParseResultSchema, input, "subtitle parse")
 }
@@ -1178,6 +1469,10 @@ export function parseSubtitleExportPayload(input: unknown): SubtitleExportResult
   return parseContract<SubtitleExportResult>(subtitleExportResultSchema, input, "subtitle export")
 }
 
+export function parseExportFileChaptersPayload(input: unknown): ExportFileChaptersResult {
+  return parseContract<ExportFileChaptersResult>(exportFileChaptersResultSchema, input, "file chapters export")
+}
+
 export function parseSubtitleValidatePayload(input: unknown): SubtitleValidateResult {
   return parseContract<SubtitleValidateResult>(subtitleValidateResultSchema, input, "subtitle validate")
 }
@@ -1190,6 +1485,10 @@ export function parseSubtitleSavePayload(input: unknown): SubtitleSaveResult {
   return parseContract<SubtitleSaveResult>(subtitleSaveResultSchema, input, "subtitle save")
 }
 
+export function parseSubtitleRetimePayload(input: unknown): SubtitleRetimeResult {
+  return parseContract<SubtitleRetimeResult>(subtitleRetimeResultSchema, input, "subtitle retime")
+}
+
 export function parseRestoreSubtitleOriginalPayload(input: unknown): RestoreSubtitleOriginalResult {
   return parseContract<RestoreSubtitleOriginalResult>(
     restoreSubtitleOriginalResultSchema,
@@ -1197,3 +1496,19 @@ export function parseRestoreSubtitleOriginalPayload(input: unknown): RestoreSubt
     "restore subtitle original",
   )
 }
+
+export function parseSubtitleRevisionListPayload(input: unknown): SubtitleRevisionDTO[] {
+  return parseContract<SubtitleRevisionDTO[]>(z.array(subtitleRevisionSchema), input, "subtitle revision list")
+}
+
+export function parseSubtitleRevisionDiffPayload(input: unknown): SubtitleRevisionDiffResult {
+  return parseContract<SubtitleRevisionDiffResult>(subtitleRevisionDiffResultSchema, input, "subtitle revision diff")
+}
+
+export function parseRestoreSubtitleRevisionPayload(input: unknown): RestoreSubtitleRevisionResult {
+  return parseContract<RestoreSubtitleRevisionResult>(
+    restoreSubtitleRevisionResultSchema,
+    input,
+    "restore subtitle revision",
+  )
+}
@@ -6,6 +6,7 @@ import {
   parseApplySubtitleReviewSessionPayload,
   parseCheckYtdlpOperationFailurePayload,
   parseDiscardSubtitleReviewSessionPayload,
+  parseExportFileChaptersPayload,
   parseFileEventPayload,
   parseGenerateSubtitleStylePreviewPayload,
   parseGenerateWorkspacePreviewAssPayload,
@@ -16,16 +17,24 @@ import {
   parseLibraryModuleConfigPayload,
   parseLibraryOperationPayload,
   parseLibraryPayload,
+  parseLibrarySubscriptionListPayload,
+  parseLibrarySubscriptionPayload,
   parseOperationListPayload,
+  parseOperationQueuePayload,
   parseParseYtdlpDownloadPayload,
+  parsePollSubscriptionPayload,
   parsePrepareYtdlpDownloadPayload,
   parseResolveDomainIconPayload,
   parseRestoreSubtitleOriginalPayload,
+  parseRestoreSubtitleRevisionPayload,
   parseSubtitleConvertPayload,
   parseSubtitleExportPayload,
   parseSubtitleFixTyposPayload,
   parseSubtitleParsePayload,
+  parseSubtitleRetimePayload,
   parseSubtitleReviewSessionPayload,
+  parseSubtitleRevisionDiffPayload,
+  parseSubtitleRevisionListPayload,
   parseSubtitleSavePayload,
   parseSubtitleValidatePayload,
   parseTranscodePresetListPayload,
@@ -40,17 +49,26 @@ import type {
   CreateSubtitleImportRequest,
   ApplySubtitleReviewSessionRequest,
   ApplySubtitleReviewSessionResult,
+  CreateChapterSplitJobRequest,
+  CreateSubtitleExtractJobRequest,
+  CreateThumbnailJobRequest,
+  CreateClipJobRequest,
   CreateTranscodeJobRequest,
   DiscardSubtitleReviewSessionRequest,
   DiscardSubtitleReviewSessionResult,
   CreateVideoImportRequest,
   CreateYtdlpJobRequest,
+  CreateYtdlpPlaylistJobRequest,
   DeleteFileRequest,
   DeleteFilesRequest,
   DeleteLibraryRequest,
+  ExportFileChaptersRequest,
+  ExportFileChaptersResult,
   DeleteOperationRequest,
   DeleteOperationsRequest,
+  DeleteSubscriptionRequest,
   DeleteTranscodePresetRequest,
+  DiffSubtitleRevisionsRequest,
   FileEventRecordDTO,
   GetLibraryRequest,
   GetOperationRequest,
@@ -63,19 +81,26 @@ import type {
   LibraryFileDTO,
   LibraryHistoryRecordDTO,
   LibraryOperationDTO,
+  LibrarySubscriptionDTO,
   ListFileEventsRequest,
   ListLibraryHistoryRequest,
   ListOperationsRequest,
+  ListSubtitleRevisionsRequest,
   ListTranscodePresetsForDownloadRequest,
   OpenFileLocationRequest,
   OpenPathRequest,
   OperationListItemDTO,
+  OperationQueueStateDTO,
+  UpdateFileChaptersRequest,
+  SetFileCoverRequest,
   GenerateWorkspacePreviewASSRequest,
   GenerateWorkspacePreviewASSResult,
   GenerateSubtitleStylePreviewASSRequest,
   GenerateSubtitleStylePreviewASSResult,
   ParseYtdlpDownloadRequest,
   ParseYtdlpDownloadResponse,
+  PollSubscriptionRequest,
+  PollSubscriptionResult,
   PrepareYtdlpDownloadRequest,
   PrepareYtdlpDownloadResponse,
   RenameLibraryRequest,
@@ -84,8 +109,12 @@ import type {
   ResumeOperationRequest,
   RestoreSubtitleOriginalRequest,
   RestoreSubtitleOriginalResult,
+  RestoreSubtitleRevisionRequest,
+  RestoreSubtitleRevisionResult,
   RetryYtdlpOperationRequest,
   SaveWorkspaceStateRequest,
+  SetOperationPriorityRequest,
+  SetOperationQueuePausedRequest,
   SubtitleConvertRequest,
   SubtitleConvertResult,
   SubtitleExportRequest,
@@ -97,9 +126,21 @@ import type {
   SubtitleParseRequest,
   SubtitleParseResult,
   SubtitleQAReviewRequest,
+  SubtitleAlignRequest,
+  SubtitleBilingualMergeRequest,
+  SubtitleBilingualSplitRequest,
+  SubtitleGlossaryExtractRequest,
+  SubtitlePipelineRequest,
+  AcceptGlossaryTermCandidatesRequest,
+  SubtitleRetimeRequest,
+  SubtitleRetimeResult,
+  SubtitleTranscribeRequest,
+  SubtitleOCRRequest,
   SubtitleSaveRequest,
   SubtitleSaveResult,
   SubtitleReviewSessionDetailDTO,
+  SubtitleRevisionDTO,
+  SubtitleRevisionDiffResult,
   SubtitleTranslateRequest,
   SubtitleValidateRequest,
   SubtitleValidateResult,
@@ -119,8 +160,11 @@ export const LIBRARY_HISTORY_QUERY_KEY = ["library", "history"] as const
 export const LIBRARY_FILE_EVENTS_QUERY_KEY = ["library", "file-events"] as const
 export const LIBRARY_WORKSPACE_QUERY_KEY = ["library", "workspace"] as const
 export const LIBRARY_WORKSPACE_PROJECT_QUERY_KEY = ["library", "workspace-project"] as const
+export const LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY = ["library", "subtitle-revisions"] as const
 export const LIBRARY_TRANSCODE_PRESETS_QUERY_KEY = ["library", "transcode-presets"] as const
 export const LIBRARY_TRANSCODE_PRESETS_FOR_DOWNLOAD_QUERY_KEY = ["library", "transcode-presets-download"] as const
+export const LIBRARY_SUBSCRIPTIONS_QUERY_KEY = ["library", "subscriptions"] as const
+export const LIBRARY_OPERATION_QUEUE_QUERY_KEY = ["library", "operation-queue"] as const
 
 function invalidateLibraryQueries(queryClient: ReturnType<typeof useQueryClient>, libraryId?: string) {
   queryClient.invalidateQueries({ queryKey: LIBRARY_LIST_QUERY_KEY })
@@ -333,6 +377,48 @@ export function useResumeOperation() {
   })
 }
 
+export function useOperationQueue() {
+  return useQuery({
+    queryKey: LIBRARY_OPERATION_QUEUE_QUERY_KEY,
+    queryFn: async (): Promise<OperationQueueStateDTO> => {
+      return parseGeneratedPayload(await LibraryHandler.GetOperationQueue(), parseOperationQueuePayload)
+    },
+    staleTime: 3_000,
+  })
+}
+
+export function useSetOperationQueuePaused() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SetOperationQueuePausedRequest): Promise<OperationQueueStateDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.SetOperationQueuePaused(LibraryBindings.SetOperationQueuePausedRequest.createFrom(request)),
+        parseOperationQueuePayload,
+      )
+    },
+    onSuccess: (state) => {
+      queryClient.setQueryData(LIBRARY_OPERATION_QUEUE_QUERY_KEY, state)
+      queryClient.invalidateQueries({ queryKey: LIBRARY_OPERATIONS_QUERY_KEY })
+    },
+  })
+}
+
+export function useSetOperationPriority() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SetOperationPriorityRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.SetOperationPriority(LibraryBindings.SetOperationPriorityRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => {
+      queryClient.invalidateQueries({ queryKey: LIBRARY_OPERATION_QUEUE_QUERY_KEY })
+      invalidateLibraryQueries(queryClient, operation.libraryId)
+    },
+  })
+}
+
 export function useDeleteOperation() {
   const queryClient = useQueryClient()
   return useMutation({
@@ -631,6 +717,19 @@ export function useCreateYtdlpJob() {
   })
 }
 
+export function useCreateYtdlpPlaylistJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: CreateYtdlpPlaylistJobRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateYTDLPPlaylistJob(LibraryBindings.CreateYTDLPPlaylistJobRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
 export function useCheckYtdlpOperationFailure() {
   return useMutation({
     mutationFn: async (request: CheckYtdlpOperationFailureRequest): Promise<CheckYtdlpOperationFailureResponse> => {
@@ -715,6 +814,95 @@ export function useCreateTranscodeJob() {
   })
 }
 
+export function useCreateClipJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: CreateClipJobRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateClipJob(LibraryBindings.CreateClipJobRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateChapterSplitJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: CreateChapterSplitJobRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateChapterSplitJob(LibraryBindings.CreateChapterSplitJobRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateSubtitleExtractJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: CreateSubtitleExtractJobRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitleExtractJob(LibraryBindings.CreateSubtitleExtractJobRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateThumbnailJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: CreateThumbnailJobRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateThumbnailJob(LibraryBindings.CreateThumbnailJobRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useSetFileCover() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SetFileCoverRequest): Promise<LibraryFileDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.SetFileCover(LibraryBindings.SetFileCoverRequest.createFrom(request)),
+        parseLibraryFilePayload,
+      )
+    },
+    onSuccess: (file) => invalidateLibraryQueries(queryClient, file.libraryId),
+  })
+}
+
+export function useUpdateFileChapters() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: UpdateFileChaptersRequest): Promise<LibraryFileDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.UpdateFileChapters(LibraryBindings.UpdateFileChaptersRequest.createFrom(request)),
+        parseLibraryFilePayload,
+      )
+    },
+    onSuccess: (file) => invalidateLibraryQueries(queryClient, file.libraryId),
+  })
+}
+
+export function useExportFileChapters() {
+  return useMutation({
+    mutationFn: async (request: ExportFileChaptersRequest): Promise<ExportFileChaptersResult> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.ExportFileChapters(LibraryBindings.ExportFileChaptersRequest.createFrom(request)),
+        parseExportFileChaptersPayload,
+      )
+    },
+  })
+}
+
 export function useCreateSubtitleTranslateJob() {
   const queryClient = useQueryClient()
   return useMutation({
@@ -805,6 +993,61 @@ export function useDeleteTranscodePreset() {
   })
 }
 
+export function useLibrarySubscriptions() {
+  return useQuery({
+    queryKey: LIBRARY_SUBSCRIPTIONS_QUERY_KEY,
+    queryFn: async (): Promise<LibrarySubscriptionDTO[]> => {
+      return parseGeneratedPayload((await LibraryHandler.ListSubscriptions()) ?? [], parseLibrarySubscriptionListPayload)
+    },
+    staleTime: 30_000,
+  })
+}
+
+export function useSaveLibrarySubscription() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: LibrarySubscriptionDTO): Promise<LibrarySubscriptionDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.SaveSubscription(LibraryBindings.LibrarySubscriptionDTO.createFrom(request)),
+        parseLibrarySubscriptionPayload,
+      )
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: LIBRARY_SUBSCRIPTIONS_QUERY_KEY })
+    },
+  })
+}
+
+export function useDeleteLibrarySubscription() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: DeleteSubscriptionRequest): Promise<void> => {
+      await LibraryHandler.DeleteSubscription(LibraryBindings.DeleteSubscriptionRequest.createFrom(request))
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: LIBRARY_SUBSCRIPTIONS_QUERY_KEY })
+    },
+  })
+}
+
+export function usePollLibrarySubscription() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: PollSubscriptionRequest): Promise<PollSubscriptionResult> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.PollSubscription(LibraryBindings.PollSubscriptionRequest.createFrom(request)),
+        parsePollSubscriptionPayload,
+      )
+    },
+    onSuccess: (result) => {
+      queryClient.invalidateQueries({ queryKey: LIBRARY_SUBSCRIPTIONS_QUERY_KEY })
+      if (result.operationIds.length > 0) {
+        invalidateLibraryQueries(queryClient, result.subscription.libraryId)
+      }
+    },
+  })
+}
+
 export function useParseSubtitle() {
   return useMutation({
     mutationFn: async (request: SubtitleParseRequest): Promise<SubtitleParseResult> => {
@@ -864,6 +1107,117 @@ export function useCreateSubtitleQAReviewJob() {
   })
 }
 
+export function useCreateSubtitleTranscribeJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleTranscribeRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitleTranscribeJob(
+          LibraryBindings.SubtitleTranscribeRequest.createFrom(request),
+        ),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateSubtitleOCRJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleOCRRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitleOCRJob(LibraryBindings.SubtitleOCRRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateSubtitleAlignJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleAlignRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitleAlignJob(LibraryBindings.SubtitleAlignRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useMergeBilingualSubtitles() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleBilingualMergeRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.MergeBilingualSubtitles(LibraryBindings.SubtitleBilingualMergeRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useSplitBilingualSubtitle() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleBilingualSplitRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.SplitBilingualSubtitle(LibraryBindings.SubtitleBilingualSplitRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateSubtitleGlossaryExtractJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleGlossaryExtractRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitleGlossaryExtractJob(LibraryBindings.SubtitleGlossaryExtractRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useCreateSubtitlePipelineJob() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitlePipelineRequest): Promise<LibraryOperationDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.CreateSubtitlePipelineJob(LibraryBindings.SubtitlePipelineRequest.createFrom(request)),
+        parseLibraryOperationPayload,
+      )
+    },
+    onSuccess: (operation) => invalidateLibraryQueries(queryClient, operation.libraryId),
+  })
+}
+
+export function useAcceptGlossaryTermCandidates() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: AcceptGlossaryTermCandidatesRequest): Promise<LibraryModuleConfigDTO> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.AcceptGlossaryTermCandidates(
+          LibraryBindings.AcceptGlossaryTermCandidatesRequest.createFrom(request),
+        ),
+        parseLibraryModuleConfigPayload,
+      )
+    },
+    onSuccess: (config) => {
+      queryClient.setQueryData(LIBRARY_MODULE_CONFIG_QUERY_KEY, config)
+      queryClient.invalidateQueries({ queryKey: LIBRARY_OPERATIONS_QUERY_KEY })
+    },
+  })
+}
+
 export function useFixSubtitleTypos() {
   return useMutation({
     mutationFn: async (request: SubtitleFixTyposRequest): Promise<SubtitleFixTyposResult> => {
@@ -876,6 +1230,7 @@ export function useFixSubtitleTypos() {
 }
 
 export function useSaveSubtitle() {
+  const queryClient = useQueryClient()
   return useMutation({
     mutationFn: async (request: SubtitleSaveRequest): Promise<SubtitleSaveResult> => {
       return parseGeneratedPayload(
@@ -883,10 +1238,25 @@ export function useSaveSubtitle() {
         parseSubtitleSavePayload,
       )
     },
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY }),
+  })
+}
+
+export function useRetimeSubtitle() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: SubtitleRetimeRequest): Promise<SubtitleRetimeResult> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.RetimeSubtitle(LibraryBindings.SubtitleRetimeRequest.createFrom(request)),
+        parseSubtitleRetimePayload,
+      )
+    },
+    onSuccess: () => invalidateLibraryQueries(queryClient),
   })
 }
 
 export function useRestoreSubtitleOriginal() {
+  const queryClient = useQueryClient()
   return useMutation({
     mutationFn: async (request: RestoreSubtitleOriginalRequest): Promise<RestoreSubtitleOriginalResult> => {
       return parseGeneratedPayload(
@@ -896,5 +1266,61 @@ export function useRestoreSubtitleOriginal() {
         parseRestoreSubtitleOriginalPayload,
       )
     },
+    onSuccess: () => queryClient.invalidateQueries({ queryKey: LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY }),
+  })
+}
+
+export function useSubtitleRevisions(fileId: string, enabled = true) {
+  return useQuery({
+    queryKey: [...LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY, fileId],
+    enabled: enabled && fileId.trim().length > 0,
+    queryFn: async (): Promise<SubtitleRevisionDTO[]> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.ListSubtitleRevisions(
+          LibraryBindings.ListSubtitleRevisionsRequest.createFrom({ fileId } satisfies ListSubtitleRevisionsRequest),
+        ),
+        parseSubtitleRevisionListPayload,
+      )
+    },
+    staleTime: 1_000,
+  })
+}
+
+export function useSubtitleRevisionDiff(request: DiffSubtitleRevisionsRequest, enabled = true) {
+  return useQuery({
+    queryKey: [
+      ...LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY,
+      request.fileId ?? "",
+      "diff",
+      request.fromRevisionId,
+      request.toRevisionId ?? "",
+      request.includeUnchanged ?? false,
+    ],
+    enabled: enabled && request.fromRevisionId.trim().length > 0,
+    queryFn: async (): Promise<SubtitleRevisionDiffResult> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.DiffSubtitleRevisions(LibraryBindings.DiffSubtitleRevisionsRequest.createFrom(request)),
+        parseSubtitleRevisionDiffPayload,
+      )
+    },
+    staleTime: 1_000,
+  })
+}
+
+export function useRestoreSubtitleRevision() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async (request: RestoreSubtitleRevisionRequest): Promise<RestoreSubtitleRevisionResult> => {
+      return parseGeneratedPayload(
+        await LibraryHandler.RestoreSubtitleRevision(
+          LibraryBindings.RestoreSubtitleRevisionRequest.createFrom(request),
+        ),
+        parseRestoreSubtitleRevisionPayload,
+      )
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: LIBRARY_SUBTITLE_REVISIONS_QUERY_KEY })
+      invalidateLibraryQueries(queryClient)
+    },
   })
 }
@@ -15,6 +15,8 @@ export const REALTIME_TOPICS = {
     history: "library.history",
     workspace: "library.workspace",
     workspaceProject: "library.workspace_project",
+    subscription: "library.subscription",
+    queue: "library.queue",
   },
   notices: {
     created: "notice.created",
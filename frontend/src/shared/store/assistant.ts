@@ -76,8 +76,25 @@ export interface AssistantToolItem {
   enabled: boolean;
 }
 
+export type AssistantMCPTransport = "stdio" | "http";
+
+export interface AssistantMCPServer {
+  id?: string;
+  transport?: AssistantMCPTransport | string;
+  command?: string;
+  args?: string[];
+  env?: Record<string, string>;
+  workingDir?: string;
+  url?: string;
+  headers?: Record<string, string>;
+  timeoutSeconds?: number;
+  requireApproval?: boolean;
+  enabled: boolean;
+}
+
 export interface AssistantTools {
   items?: AssistantToolItem[];
+  mcpServers?: AssistantMCPServer[];
 }
 
 export interface AssistantSkills {
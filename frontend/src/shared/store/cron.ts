@@ -1,7 +1,7 @@
 export type CronScheduleType = "cron" | "every" | "at";
 export type CronSessionTarget = "main" | "isolated";
 export type CronWakeMode = "now" | "next-heartbeat";
-export type CronPayloadKind = "systemEvent" | "agentTurn";
+export type CronPayloadKind = "systemEvent" | "agentTurn" | "subscriptionPoll";
 export type CronDeliveryMode = "none" | "announce" | "webhook";
 
 export interface CronSchedule {
@@ -22,6 +22,7 @@ export interface CronPayloadSpec {
   thinking?: string;
   timeoutSeconds?: number;
   lightContext?: boolean;
+  subscriptionId?: string;
 }
 
 export interface CronFailureDestination {
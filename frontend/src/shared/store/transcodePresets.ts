@@ -25,6 +25,16 @@ export type TranscodePreset = {
   requiresVideo?: boolean
   requiresAudio?: boolean
   isBuiltin?: boolean
+  loudnessNormalize?: boolean
+  loudnessTargetI?: number
+  loudnessTruePeak?: number
+  loudnessRange?: number
+  noiseReduction?: boolean
+  trimSilence?: boolean
+  aspectRatio?: string
+  reframeMode?: string
+  maxBitrateKbps?: number
+  maxFileSizeMb?: number
   description?: string
 }
 
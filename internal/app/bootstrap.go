@@ -102,6 +102,7 @@ import (
 	"dreamcreator/internal/infrastructure/ws"
 	telegramchannel "dreamcreator/internal/presentation/channels/telegram"
 	webchannel "dreamcreator/internal/presentation/channels/web"
+	mcphttp "dreamcreator/internal/presentation/gateway/http/mcp"
 	openaihttp "dreamcreator/internal/presentation/gateway/http/openai"
 	openresponseshttp "dreamcreator/internal/presentation/gateway/http/openresponses"
 	gatewaytoolhttp "dreamcreator/internal/presentation/gateway/http/tools"
@@ -465,6 +466,8 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 	subtitleDocumentRepo := libraryrepo.NewSQLiteSubtitleDocumentRepository(database.Bun)
 	subtitleRevisionRepo := libraryrepo.NewSQLiteSubtitleRevisionRepository(database.Bun)
 	subtitleReviewRepo := libraryrepo.NewSQLiteSubtitleReviewSessionRepository(database.Bun)
+	translationMemoryRepo := libraryrepo.NewSQLiteTranslationMemoryRepository(database.Bun)
+	subscriptionRepo := libraryrepo.NewSQLiteSubscriptionRepository(database.Bun)
 	faviconCache := libraryicons.NewFaviconCache()
 	libraryService := libraryservice.NewLibraryService(
 		libraryRepo,
@@ -478,6 +481,8 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 		subtitleDocumentRepo,
 		subtitleRevisionRepo,
 		subtitleReviewRepo,
+		translationMemoryRepo,
+		subscriptionRepo,
 		presetRepo,
 		settingsService,
 		faviconCache,
@@ -508,12 +513,34 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 	toolExecutor := gatewaytools.NewRegistryExecutor()
 	toolService.SetExecutor(toolExecutor)
 	policyAuditStore := toolpolicyrepo.NewSQLitePolicyAuditStore(database.Bun)
-	sandboxService := gatewaysandbox.NewService(currentSettings.Gateway.SandboxEnabled, secure.NoopHealthChecker{})
+	policyRuleStore := toolpolicyrepo.NewSQLitePolicyRuleStore(database.Bun)
+	toolPolicy.SetRuleEvaluator(policyRuleStore)
+	var sandboxHealth secure.HealthChecker = secure.NoopHealthChecker{}
+	if sandboxBackend := secure.NewCommandSandbox(); sandboxBackend != nil {
+		sandboxHealth = sandboxBackend
+	}
+	sandboxService := gatewaysandbox.NewService(currentSettings.Gateway.SandboxEnabled, sandboxHealth)
+	sandboxService.SetSettingsReader(settingsService)
 	gatewayToolService := gatewaytools.NewService(toolService, approvalService, sandboxService, settingsNotifier, policyAuditStore, gatewayEvents)
+	mcpManager := gatewaytools.NewMCPManager(toolService, toolExecutor)
+	gatewayToolService.SetMCPManager(mcpManager)
+	app.OnShutdown(mcpManager.Close)
+	processManager := gatewaytools.NewProcessManager(gatewayEvents)
+	gatewayToolService.SetProcessManager(processManager)
+	app.OnShutdown(processManager.Close)
 	toolsInvokeHandler := gatewaytoolhttp.NewHandler(gatewayToolService)
 	realtimeServer.Handle("/tools/invoke", toolsInvokeHandler)
 	realtimeServer.Handle("/tools/invoke/", toolsInvokeHandler)
-	app.RegisterService(application.NewService(wails.NewToolsHandler(toolService, gatewayToolService)))
+	mcpServer := mcphttp.NewServer(gatewayToolService, gatewayAuth, gatewayScopeGuard, appVersion)
+	realtimeServer.Handle("/mcp", mcpServer)
+	if removeMCPEndpoint, err := issueMCPToken(gatewayAuth, realtimeServer.HTTPURL()+"/mcp"); err != nil {
+		zap.L().Warn("publish mcp endpoint failed", zap.Error(err))
+	} else {
+		app.OnShutdown(removeMCPEndpoint)
+	}
+	toolsHandler := wails.NewToolsHandler(toolService, gatewayToolService)
+	toolsHandler.SetPolicyRuleService(toolsservice.NewPolicyRuleService(policyRuleStore))
+	app.RegisterService(application.NewService(toolsHandler))
 	workspaceBaseDir, err := workspacefs.DefaultBaseDir()
 	if err != nil {
 		return nil, err
@@ -522,6 +549,11 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 	if err != nil {
 		return nil, err
 	}
+	if mcpWorkspace, err := workspaceManager.CreateWorkspace(ctx, mcpWorkspaceID); err != nil {
+		zap.L().Warn("create mcp workspace failed", zap.Error(err))
+	} else {
+		mcpServer.SetWorkspacePath(mcpWorkspace)
+	}
 	assistantRepo := assistantrepo.NewSQLiteAssistantRepository(database.Bun)
 	workspaceService := workspaceservice.NewWorkspaceService(workspaceRepo, workspaceManager, assistantRepo)
 	skillsService.SetWorkspaceResolver(workspaceService)
@@ -582,6 +614,7 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 	app.OnShutdown(purgeCancel)
 	startThreadPurgeWorker(purgeCtx, threadService)
 	startLLMCallRecordPruneWorker(purgeCtx, llmCallRecordService)
+	startMCPSessionSweepWorker(purgeCtx, mcpServer)
 
 	voiceConfigRepo := voicerepo.NewSQLiteVoiceConfigRepository(database.Bun)
 	ttsJobRepo := voicerepo.NewSQLiteTTSJobRepository(database.Bun)
@@ -615,6 +648,8 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 		Models:        modelRepo,
 		Secrets:       secretRepo,
 		Memory:        memoryService,
+		MCP:           mcpManager,
+		Processes:     processManager,
 	})
 	sessionManager := sessionmanager.NewManager()
 	queueStore := gatewayqueuerepo.NewSQLiteQueueStore(database.Bun)
@@ -877,6 +912,20 @@ func CreateApplication(assets fs.FS) (*application.App, error) {
 		}
 		return nil
 	})
+	libraryService.SetSubscriptionScheduler(librarySubscriptionScheduler{scheduler: cronScheduler})
+	cronScheduler.SetSubscriptionPoller(func(ctx context.Context, request gatewaycron.SubscriptionPollRequest) (gatewaycron.SubscriptionPollResult, error) {
+		result, err := libraryService.PollScheduledSubscription(ctx, request.SubscriptionID)
+		if err != nil {
+			return gatewaycron.SubscriptionPollResult{Status: "failed", Error: err.Error()}, err
+		}
+		if result.Skipped {
+			return gatewaycron.SubscriptionPollResult{Status: "skipped", Summary: "subscription disabled"}, nil
+		}
+		return gatewaycron.SubscriptionPollResult{
+			Status:  "completed",
+			Summary: fmt.Sprintf("%d new entries, %d downloads queued", result.NewEntryCount, len(result.OperationIDs)),
+		}, nil
+	})
 	cronScheduler.SetIsolatedExecutor(func(ctx context.Context, request gatewaycron.IsolatedExecutionRequest) (gatewaycron.IsolatedExecutionResult, error) {
 		if runtimeService == nil {
 			err := errors.New("runtime service unavailable")
@@ -11,6 +11,7 @@ import (
 	llmrecord "dreamcreator/internal/application/llmrecord"
 	threadservice "dreamcreator/internal/application/thread/service"
 	"dreamcreator/internal/infrastructure/providersync"
+	mcphttp "dreamcreator/internal/presentation/gateway/http/mcp"
 
 	"go.uber.org/zap"
 )
@@ -69,6 +70,27 @@ func startLLMCallRecordPruneWorker(ctx context.Context, service *llmrecord.Servi
 	}()
 }
 
+func startMCPSessionSweepWorker(ctx context.Context, server *mcphttp.Server) {
+	if server == nil {
+		return
+	}
+
+	const interval = time.Hour
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				server.SweepIdleSessions(ctx)
+			}
+		}
+	}()
+}
+
 func startModelsDevCatalogSyncWorker(ctx context.Context, service *providersync.ModelsDevCatalogService) {
 	if service == nil {
 		return
@@ -3,10 +3,14 @@ package app
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
+	"time"
 
+	gatewaycron "dreamcreator/internal/application/gateway/cron"
 	settingsdto "dreamcreator/internal/application/settings/dto"
 	"dreamcreator/internal/application/settings/service"
+	"dreamcreator/internal/domain/library"
 	"dreamcreator/internal/presentation/wails"
 )
 
@@ -66,3 +70,51 @@ func (adapter *settingsBroadcastAdapter) ApplySettings(updated settingsdto.Setti
 		applier(updated)
 	}
 }
+
+// librarySubscriptionScheduler maps library subscriptions onto cron poll jobs.
+type librarySubscriptionScheduler struct {
+	scheduler *gatewaycron.Scheduler
+}
+
+func librarySubscriptionJobID(subscriptionID string) string {
+	return "library-subscription-" + strings.TrimSpace(subscriptionID)
+}
+
+func (adapter librarySubscriptionScheduler) ScheduleSubscription(ctx context.Context, item library.Subscription) error {
+	if adapter.scheduler == nil {
+		return errors.New("cron scheduler unavailable")
+	}
+	_, err := adapter.scheduler.Upsert(ctx, gatewaycron.CronJob{
+		JobID:       librarySubscriptionJobID(item.ID),
+		Name:        "Subscription: " + item.Name,
+		Description: item.URL,
+		Enabled:     item.Enabled,
+		Schedule: gatewaycron.CronSchedule{
+			Kind:    "every",
+			EveryMs: int64(item.PollIntervalMinutes) * int64(time.Minute/time.Millisecond),
+		},
+		SessionTarget: "isolated",
+		PayloadSpec: gatewaycron.CronPayload{
+			Kind:           "subscriptionPoll",
+			SubscriptionID: item.ID,
+		},
+	})
+	return err
+}
+
+func (adapter librarySubscriptionScheduler) UnscheduleSubscription(ctx context.Context, subscriptionID string) error {
+	if adapter.scheduler == nil {
+		return nil
+	}
+	jobID := librarySubscriptionJobID(subscriptionID)
+	jobs, err := adapter.scheduler.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.JobID == jobID {
+			return adapter.scheduler.Delete(ctx, jobID)
+		}
+	}
+	return nil
+}
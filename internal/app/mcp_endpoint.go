@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	gatewayauth "dreamcreator/internal/application/gateway/auth"
+	mcphttp "dreamcreator/internal/presentation/gateway/http/mcp"
+)
+
+const (
+	mcpTokenSubject = "mcp-local"
+	// mcpWorkspaceID names the workspace directory MCP tool calls run in.
+	mcpWorkspaceID = "mcp"
+)
+
+// TryRunMCPStdioBridge turns this process into a stdio MCP shim for the
+// running app when launched with --mcp-stdio. It reports whether the launch
+// was handled so the caller skips starting the UI.
+func TryRunMCPStdioBridge(ctx context.Context, args []string) (bool, error) {
+	if !currentStartupContext(args).mcpStdio {
+		return false, nil
+	}
+	path, err := mcpEndpointPath()
+	if err != nil {
+		return true, err
+	}
+	endpoint, err := mcphttp.LoadEndpoint(path)
+	if err != nil {
+		return true, err
+	}
+	return true, mcphttp.RunStdioBridge(ctx, endpoint, os.Stdin, os.Stdout, nil)
+}
+
+func mcpEndpointPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "dreamcreator", mcphttp.EndpointFileName), nil
+}
+
+// issueMCPToken registers a per-launch gateway token for local MCP clients
+// and publishes it next to the endpoint URL for the stdio shim.
+func issueMCPToken(auth *gatewayauth.InMemoryService, url string) (func(), error) {
+	if auth == nil {
+		return nil, errors.New("gateway auth unavailable")
+	}
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return nil, fmt.Errorf("generate mcp token: %w", err)
+	}
+	token := hex.EncodeToString(buffer)
+	auth.AddToken(token, gatewayauth.AuthContext{
+		Subject:  mcpTokenSubject,
+		Role:     "operator",
+		Scopes:   mcphttp.DefaultScopes(),
+		AuthType: "token",
+	})
+	path, err := mcpEndpointPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := mcphttp.WriteEndpoint(path, mcphttp.Endpoint{URL: url, Token: token}); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = os.Remove(path)
+	}, nil
+}
@@ -4,10 +4,12 @@ import "strings"
 
 const autoStartLaunchArgument = "--autostart"
 const skipPreparedUpdateLaunchArgument = "--skip-prepared-update-once"
+const mcpStdioLaunchArgument = "--mcp-stdio"
 
 type startupContext struct {
 	launchedByAutoStart bool
 	skipPreparedUpdate  bool
+	mcpStdio            bool
 }
 
 func currentStartupContext(args []string) startupContext {
@@ -18,6 +20,8 @@ func currentStartupContext(args []string) startupContext {
 			context.launchedByAutoStart = true
 		case skipPreparedUpdateLaunchArgument:
 			context.skipPreparedUpdate = true
+		case mcpStdioLaunchArgument:
+			context.mcpStdio = true
 		}
 	}
 	return context
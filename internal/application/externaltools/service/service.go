@@ -69,6 +69,16 @@ var (
 			SourceRef: "clawhub",
 			Manager:   toolManagerBun,
 		},
+		externaltools.ToolWhisperCPP: {
+			ToolKind:  string(externaltools.KindBin),
+			Kind:      sourceKindGitHubRelease,
+			SourceRef: "ggml-org/whisper.cpp",
+		},
+		externaltools.ToolTesseract: {
+			ToolKind:  string(externaltools.KindBin),
+			Kind:      sourceKindGitHubRelease,
+			SourceRef: "tesseract-ocr/tesseract",
+		},
 	}
 
 	semverTokenPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.-]+)?$`)
@@ -155,6 +165,8 @@ func (service *ExternalToolsService) EnsureDefaults(ctx context.Context) error {
 		externaltools.ToolFFmpeg,
 		externaltools.ToolBun,
 		externaltools.ToolClawHub,
+		externaltools.ToolWhisperCPP,
+		externaltools.ToolTesseract,
 	}
 	existing, err := service.repo.List(ctx)
 	if err != nil {
@@ -285,6 +297,16 @@ func (service *ExternalToolsService) InstallTool(ctx context.Context, request dt
 			return service.installFFmpeg(ctx, request.Version)
 		case externaltools.ToolBun:
 			return service.installBun(ctx, request.Version)
+		case externaltools.ToolWhisperCPP:
+			// whisper.cpp only publishes prebuilt Windows archives; every platform
+			// points at a locally built whisper-cli through SetToolPath instead.
+			service.setInstallState(toolName, installStageError, downloadProgressStart, "whisper.cpp auto-install is not supported, set the whisper-cli path manually")
+			return dto.ExternalTool{}, fmt.Errorf("auto-install is unsupported for tool %s", toolName)
+		case externaltools.ToolTesseract:
+			// Tesseract releases are source-only and need language data next to the
+			// binary, so it is installed through the system package manager.
+			service.setInstallState(toolName, installStageError, downloadProgressStart, "Tesseract auto-install is not supported, set the tesseract path manually")
+			return dto.ExternalTool{}, fmt.Errorf("auto-install is unsupported for tool %s", toolName)
 		default:
 			service.setInstallState(toolName, installStageError, downloadProgressStart, "invalid tool")
 			return dto.ExternalTool{}, externaltools.ErrInvalidTool
@@ -499,6 +521,10 @@ func resolveToolReleaseLegacy(ctx context.Context, name externaltools.ToolName)
 		displayName = "FFmpeg"
 	} else if name == externaltools.ToolBun {
 		displayName = "Bun"
+	} else if name == externaltools.ToolWhisperCPP {
+		displayName = "whisper.cpp"
+	} else if name == externaltools.ToolTesseract {
+		displayName = "Tesseract"
 	}
 	return softwareupdate.ToolRelease{
 		Name:               name,
@@ -1336,6 +1362,28 @@ func resolveToolUpdate(ctx context.Context, name externaltools.ToolName) (string
 				return "", "", "", err
 			}
 			return version, "", "", nil
+		case externaltools.ToolWhisperCPP:
+			release, err := getLatestGitHubRelease(ctx, "ggml-org", "whisper.cpp")
+			if err != nil {
+				return "", "", "", err
+			}
+			notes := strings.TrimSpace(release.Body)
+			notesURL := ""
+			if notes != "" {
+				notesURL = release.HTMLURL
+			}
+			return strings.TrimPrefix(strings.TrimSpace(release.TagName), "v"), notes, notesURL, nil
+		case externaltools.ToolTesseract:
+			release, err := getLatestGitHubRelease(ctx, "tesseract-ocr", "tesseract")
+			if err != nil {
+				return "", "", "", err
+			}
+			notes := strings.TrimSpace(release.Body)
+			notesURL := ""
+			if notes != "" {
+				notesURL = release.HTMLURL
+			}
+			return strings.TrimPrefix(strings.TrimSpace(release.TagName), "v"), notes, notesURL, nil
 		default:
 			return "", "", "", externaltools.ErrInvalidTool
 		}
@@ -2142,6 +2190,8 @@ func resolveVersion(ctx context.Context, name externaltools.ToolName, execPath s
 		args = []string{"-version"}
 	case externaltools.ToolClawHub:
 		args = []string{"--cli-version"}
+	case externaltools.ToolWhisperCPP:
+		args = []string{"--help"}
 	default:
 		args = []string{"--version"}
 	}
@@ -2167,6 +2217,10 @@ func resolveVersion(ctx context.Context, name externaltools.ToolName, execPath s
 		return parseFFmpegVersion(text)
 	case externaltools.ToolClawHub:
 		return parseClawHubVersion(text)
+	case externaltools.ToolWhisperCPP:
+		return parseWhisperCPPVersion(text)
+	case externaltools.ToolTesseract:
+		return parseTesseractVersion(text)
 	default:
 		return strings.Fields(text)[0], nil
 	}
@@ -2196,6 +2250,35 @@ func parseClawHubVersion(output string) (string, error) {
 	return "", fmt.Errorf("clawhub version not found")
 }
 
+// parseWhisperCPPVersion accepts the usage banner printed by whisper-cli,
+// which only carries a version token on some builds.
+func parseWhisperCPPVersion(output string) (string, error) {
+	for _, token := range strings.Fields(output) {
+		candidate := strings.Trim(strings.TrimSpace(token), ",;:()[]{}")
+		if semverTokenPattern.MatchString(candidate) {
+			return strings.TrimPrefix(candidate, "v"), nil
+		}
+	}
+	lower := strings.ToLower(output)
+	if strings.Contains(lower, "usage:") && strings.Contains(lower, "--model") {
+		return "unknown", nil
+	}
+	return "", fmt.Errorf("whisper-cli usage not recognized")
+}
+
+// parseTesseractVersion reads the first banner line, e.g. "tesseract 5.3.0" or
+// "tesseract v5.3.3.20231005" on the Windows builds.
+func parseTesseractVersion(output string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[0])
+	for i, field := range fields {
+		if strings.EqualFold(field, "tesseract") && i+1 < len(fields) {
+			return strings.TrimPrefix(strings.TrimSpace(fields[i+1]), "v"), nil
+		}
+	}
+	return "", fmt.Errorf("tesseract version not found")
+}
+
 func percent(written int64, total int64) int {
 	if total <= 0 {
 		return 0
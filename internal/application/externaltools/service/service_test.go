@@ -60,6 +60,8 @@ func TestEnsureDefaultsIncludesBunAndClawHub(t *testing.T) {
 		externaltools.ToolFFmpeg,
 		externaltools.ToolBun,
 		externaltools.ToolClawHub,
+		externaltools.ToolWhisperCPP,
+		externaltools.ToolTesseract,
 	} {
 		if _, err := repo.Get(context.Background(), string(name)); err != nil {
 			t.Fatalf("expected default tool %s: %v", name, err)
@@ -156,6 +158,39 @@ func TestParseClawHubVersionFromUsageText(t *testing.T) {
 	}
 }
 
+func TestParseWhisperCPPVersionFromUsageText(t *testing.T) {
+	t.Parallel()
+
+	version, err := parseWhisperCPPVersion("\nusage: whisper-cli [options] file0 file1 ...\n  -m FNAME, --model FNAME [models/ggml-base.en.bin] model path\n")
+	if err != nil {
+		t.Fatalf("parse whisper-cli version failed: %v", err)
+	}
+	if version != "unknown" {
+		t.Fatalf("unexpected version: %s", version)
+	}
+	if _, err := parseWhisperCPPVersion("command not found"); err == nil {
+		t.Fatalf("expected error for unrelated output")
+	}
+}
+
+func TestParseTesseractVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := parseTesseractVersion("tesseract 5.3.0\n leptonica-1.82.0\n  libgif 5.2.1 : libjpeg 8d (libjpeg-turbo 2.1.2)\n")
+	if err != nil {
+		t.Fatalf("parse tesseract version failed: %v", err)
+	}
+	if version != "5.3.0" {
+		t.Fatalf("unexpected version: %s", version)
+	}
+	if version, err := parseTesseractVersion("tesseract v5.3.3.20231005\n leptonica-1.83.1"); err != nil || version != "5.3.3.20231005" {
+		t.Fatalf("unexpected windows version: %q, %v", version, err)
+	}
+	if _, err := parseTesseractVersion("command not found"); err == nil {
+		t.Fatalf("expected error for unrelated output")
+	}
+}
+
 func TestListToolsMarksFFmpegInvalidWhenFFprobeMissing(t *testing.T) {
 	t.Parallel()
 
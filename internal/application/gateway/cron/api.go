@@ -29,6 +29,7 @@ type PayloadDTO struct {
 	Thinking       string `json:"thinking,omitempty"`
 	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
 	LightContext   bool   `json:"lightContext,omitempty"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
 }
 
 type FailureDestinationDTO struct {
@@ -183,8 +184,12 @@ func validatePayload(input PayloadDTO) error {
 		if input.TimeoutSeconds < 0 {
 			return errors.New("payload.timeoutSeconds must be >= 0")
 		}
+	case "subscriptionpoll":
+		if strings.TrimSpace(input.SubscriptionID) == "" {
+			return errors.New("payload.subscriptionId is required when payload.kind=subscriptionPoll")
+		}
 	default:
-		return errors.New("payload.kind must be one of: systemEvent, agentTurn")
+		return errors.New("payload.kind must be one of: systemEvent, agentTurn, subscriptionPoll")
 	}
 	return nil
 }
@@ -255,8 +260,8 @@ func validateTargetAndPayload(sessionTarget string, payloadKind string) error {
 			return errors.New("sessionTarget=main requires payload.kind=systemEvent (hint: use payload.text with systemEvent)")
 		}
 	case "isolated":
-		if payload != "agentturn" {
-			return errors.New("sessionTarget=isolated requires payload.kind=agentTurn (hint: use payload.message with agentTurn)")
+		if payload != "agentturn" && payload != "subscriptionpoll" {
+			return errors.New("sessionTarget=isolated requires payload.kind=agentTurn or subscriptionPoll (hint: use payload.message with agentTurn)")
 		}
 	default:
 		return errors.New("sessionTarget must be one of: main, isolated")
@@ -600,6 +605,7 @@ func BuildCronJobFromCreate(input CreateInput, now time.Time) CronJob {
 			Thinking:       strings.TrimSpace(input.Payload.Thinking),
 			TimeoutSeconds: input.Payload.TimeoutSeconds,
 			LightContext:   input.Payload.LightContext,
+			SubscriptionID: strings.TrimSpace(input.Payload.SubscriptionID),
 		},
 		Delivery:   delivery,
 		SessionKey: strings.TrimSpace(input.SessionKey),
@@ -651,6 +657,7 @@ func ApplyPatch(job CronJob, patch PatchInput) CronJob {
 			Thinking:       strings.TrimSpace(patch.Payload.Thinking),
 			TimeoutSeconds: patch.Payload.TimeoutSeconds,
 			LightContext:   patch.Payload.LightContext,
+			SubscriptionID: strings.TrimSpace(patch.Payload.SubscriptionID),
 		}
 	}
 	if patch.Delivery != nil {
@@ -20,6 +20,7 @@ type CronPayload struct {
 	Thinking       string `json:"thinking,omitempty"`
 	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
 	LightContext   bool   `json:"lightContext,omitempty"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
 }
 
 type CronFailureDestination struct {
@@ -82,6 +82,21 @@ type IsolatedExecutionResult struct {
 
 type IsolatedExecutor func(ctx context.Context, request IsolatedExecutionRequest) (IsolatedExecutionResult, error)
 
+type SubscriptionPollRequest struct {
+	RunID          string
+	JobID          string
+	JobName        string
+	SubscriptionID string
+}
+
+type SubscriptionPollResult struct {
+	Status  string
+	Error   string
+	Summary string
+}
+
+type SubscriptionPoller func(ctx context.Context, request SubscriptionPollRequest) (SubscriptionPollResult, error)
+
 type HeartbeatDeliveryEvent struct {
 	RunID      string
 	Source     string
@@ -160,6 +175,7 @@ type Scheduler struct {
 	mainSystemEventEnqueue MainSystemEventEnqueuer
 	wakeTrigger            WakeTrigger
 	isolatedExecutor       IsolatedExecutor
+	subscriptionPoller     SubscriptionPoller
 	runRealtimeNotifier    RunRealtimeNotifier
 	assistantIDResolver    AssistantIDResolver
 }
@@ -307,6 +323,15 @@ func (scheduler *Scheduler) SetIsolatedExecutor(executor IsolatedExecutor) {
 	scheduler.mu.Unlock()
 }
 
+func (scheduler *Scheduler) SetSubscriptionPoller(poller SubscriptionPoller) {
+	if scheduler == nil {
+		return
+	}
+	scheduler.mu.Lock()
+	scheduler.subscriptionPoller = poller
+	scheduler.mu.Unlock()
+}
+
 func (scheduler *Scheduler) SetRunRealtimeNotifier(notifier RunRealtimeNotifier) {
 	if scheduler == nil {
 		return
@@ -738,7 +763,13 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 	}
 	scheduler.appendRunEvent(ctx, buildRunEvent(run, "started", "running", "", "", "", "scheduler", nil))
 
-	if handled, err := scheduler.tryExecuteIsolated(ctx, job, &run); handled {
+	source := "subscription_poller"
+	handled, err := scheduler.tryExecuteSubscriptionPoll(ctx, job, &run)
+	if !handled {
+		source = "isolated_executor"
+		handled, err = scheduler.tryExecuteIsolated(ctx, job, &run)
+	}
+	if handled {
 		scheduler.applyRunDelivery(ctx, job, &run)
 		if scheduler.store != nil {
 			_ = scheduler.store.SaveCronRun(ctx, run)
@@ -748,7 +779,7 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 			scheduler.appendRunEvent(ctx, buildRunEvent(run, "action_failed", "failed", "", strings.TrimSpace(run.Error), "", "scheduler", map[string]any{
 				"sessionTarget": strings.TrimSpace(job.SessionTarget),
 				"payloadKind":   normalizePayloadKind(job.PayloadSpec.Kind),
-				"source":        "isolated_executor",
+				"source":        source,
 			}))
 			scheduler.appendRunEvent(ctx, buildRunEvent(run, "failed", "failed", "", strings.TrimSpace(run.Error), "", "scheduler", nil))
 			scheduler.updateRunState(ctx, run)
@@ -757,6 +788,9 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 				return run, err
 			}
 			if strings.TrimSpace(run.Error) == "" {
+				if source == "subscription_poller" {
+					return run, errors.New("subscription poll failed")
+				}
 				return run, errors.New("isolated execution failed")
 			}
 			return run, errors.New(strings.TrimSpace(run.Error))
@@ -764,7 +798,7 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 			scheduler.appendRunEvent(ctx, buildRunEvent(run, "skipped", "skipped", run.Summary, "", "", "scheduler", map[string]any{
 				"sessionTarget": strings.TrimSpace(job.SessionTarget),
 				"payloadKind":   normalizePayloadKind(job.PayloadSpec.Kind),
-				"source":        "isolated_executor",
+				"source":        source,
 			}))
 			scheduler.updateRunState(ctx, run)
 			scheduler.resetRetry(job.JobID)
@@ -774,7 +808,7 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 			scheduler.appendRunEvent(ctx, buildRunEvent(run, "completed", "completed", run.Summary, "", "", "scheduler", map[string]any{
 				"sessionTarget": strings.TrimSpace(job.SessionTarget),
 				"payloadKind":   normalizePayloadKind(job.PayloadSpec.Kind),
-				"source":        "isolated_executor",
+				"source":        source,
 			}))
 			scheduler.updateRunState(ctx, run)
 			scheduler.resetRetry(job.JobID)
@@ -783,7 +817,7 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 		}
 	}
 
-	err := scheduler.triggerAction(ctx, job, strings.TrimSpace(run.RunID))
+	err = scheduler.triggerAction(ctx, job, strings.TrimSpace(run.RunID))
 	if err != nil {
 		run.Status = "failed"
 		run.Error = err.Error()
@@ -835,6 +869,61 @@ func (scheduler *Scheduler) executeJob(ctx context.Context, job CronJob) (CronRu
 	return run, nil
 }
 
+func (scheduler *Scheduler) tryExecuteSubscriptionPoll(ctx context.Context, job CronJob, run *CronRunRecord) (bool, error) {
+	if scheduler == nil || run == nil {
+		return false, nil
+	}
+	if normalizePayloadKind(job.PayloadSpec.Kind) != "subscriptionPoll" {
+		return false, nil
+	}
+	poller := scheduler.subscriptionPollerFunc()
+	if poller == nil {
+		run.EndedAt = scheduler.now()
+		run.Status = "failed"
+		run.Error = "subscription poller unavailable"
+		run.Summary = summarizeRun(*run)
+		run.LatestStage = "failed"
+		return true, errors.New(run.Error)
+	}
+
+	result, err := poller(ctx, SubscriptionPollRequest{
+		RunID:          strings.TrimSpace(run.RunID),
+		JobID:          strings.TrimSpace(job.JobID),
+		JobName:        strings.TrimSpace(job.Name),
+		SubscriptionID: strings.TrimSpace(job.PayloadSpec.SubscriptionID),
+	})
+	run.EndedAt = scheduler.now()
+	if err != nil {
+		run.Status = "failed"
+		run.Error = strings.TrimSpace(err.Error())
+		run.Summary = summarizeRun(*run)
+		run.LatestStage = "failed"
+		return true, err
+	}
+
+	status := normalizeDirectRunStatus(result.Status)
+	run.Status = status
+	if text := strings.TrimSpace(result.Error); text != "" {
+		run.Error = text
+	}
+	if text := strings.TrimSpace(result.Summary); text != "" {
+		run.Summary = text
+	}
+	if status == "failed" {
+		if strings.TrimSpace(run.Error) == "" {
+			run.Error = "subscription poll failed"
+		}
+		run.Summary = summarizeRun(*run)
+		run.LatestStage = "failed"
+		return true, errors.New(strings.TrimSpace(run.Error))
+	}
+	if strings.TrimSpace(run.Summary) == "" {
+		run.Summary = summarizeRun(*run)
+	}
+	run.LatestStage = status
+	return true, nil
+}
+
 func (scheduler *Scheduler) tryExecuteIsolated(ctx context.Context, job CronJob, run *CronRunRecord) (bool, error) {
 	if scheduler == nil || run == nil {
 		return false, nil
@@ -1342,6 +1431,15 @@ func (scheduler *Scheduler) isolatedExecutorFunc() IsolatedExecutor {
 	return scheduler.isolatedExecutor
 }
 
+func (scheduler *Scheduler) subscriptionPollerFunc() SubscriptionPoller {
+	if scheduler == nil {
+		return nil
+	}
+	scheduler.mu.RLock()
+	defer scheduler.mu.RUnlock()
+	return scheduler.subscriptionPoller
+}
+
 func (scheduler *Scheduler) runRealtimeNotifierFunc() RunRealtimeNotifier {
 	if scheduler == nil {
 		return nil
@@ -2027,7 +2125,7 @@ func normalizeJob(job CronJob) CronJob {
 	job.Schedule = normalizeSchedule(job.Schedule)
 	job.PayloadSpec = normalizePayload(job.PayloadSpec)
 	if job.SessionTarget == "" {
-		if strings.EqualFold(job.PayloadSpec.Kind, "agentTurn") {
+		if strings.EqualFold(job.PayloadSpec.Kind, "agentTurn") || strings.EqualFold(job.PayloadSpec.Kind, "subscriptionPoll") {
 			job.SessionTarget = "isolated"
 		} else {
 			job.SessionTarget = "main"
@@ -2086,8 +2184,11 @@ func validateJobSemantics(job CronJob) error {
 			return errors.New("sessionTarget=main requires payload.kind=systemEvent (hint: use payload.text with systemEvent)")
 		}
 	case "isolated":
-		if payloadKind != "agentturn" {
-			return errors.New("sessionTarget=isolated requires payload.kind=agentTurn (hint: use payload.message with agentTurn)")
+		if payloadKind != "agentturn" && payloadKind != "subscriptionpoll" {
+			return errors.New("sessionTarget=isolated requires payload.kind=agentTurn or subscriptionPoll (hint: use payload.message with agentTurn)")
+		}
+		if payloadKind == "subscriptionpoll" && strings.TrimSpace(job.PayloadSpec.SubscriptionID) == "" {
+			return errors.New("payload.subscriptionId is required when payload.kind=subscriptionPoll")
 		}
 	default:
 		return errors.New("sessionTarget must be one of: main, isolated")
@@ -2268,6 +2369,7 @@ func normalizePayload(spec CronPayload) CronPayload {
 	normalized.Message = strings.TrimSpace(normalized.Message)
 	normalized.Model = strings.TrimSpace(normalized.Model)
 	normalized.Thinking = strings.TrimSpace(normalized.Thinking)
+	normalized.SubscriptionID = strings.TrimSpace(normalized.SubscriptionID)
 	if normalized.TimeoutSeconds < 0 {
 		normalized.TimeoutSeconds = 0
 	}
@@ -2280,6 +2382,8 @@ func normalizePayloadKind(raw string) string {
 		return "agentTurn"
 	case "systemevent":
 		return "systemEvent"
+	case "subscriptionpoll":
+		return "subscriptionPoll"
 	default:
 		return ""
 	}
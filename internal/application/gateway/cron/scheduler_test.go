@@ -1134,3 +1134,42 @@ func TestApplyRunDeliverySkipsFailureDestinationWhenBestEffort(t *testing.T) {
 		t.Fatalf("expected best-effort configuration to skip failure destination delivery")
 	}
 }
+
+func TestExecuteJobUsesSubscriptionPollerForSubscriptionPoll(t *testing.T) {
+	scheduler := NewScheduler(nil, nil)
+	var captured SubscriptionPollRequest
+	scheduler.SetSubscriptionPoller(func(_ context.Context, request SubscriptionPollRequest) (SubscriptionPollResult, error) {
+		captured = request
+		return SubscriptionPollResult{
+			Status:  "completed",
+			Summary: "2 new entries, 2 downloads queued",
+		}, nil
+	})
+
+	run, err := scheduler.executeJob(context.Background(), CronJob{
+		JobID:   "library-subscription-sub-1",
+		Name:    "Subscription: creator",
+		Enabled: true,
+		Schedule: CronSchedule{
+			Kind:    "every",
+			EveryMs: 3_600_000,
+		},
+		SessionTarget: "isolated",
+		PayloadSpec: CronPayload{
+			Kind:           "subscriptionPoll",
+			SubscriptionID: "sub-1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("executeJob error: %v", err)
+	}
+	if run.Status != "completed" {
+		t.Fatalf("expected completed status, got %q", run.Status)
+	}
+	if run.Summary != "2 new entries, 2 downloads queued" {
+		t.Fatalf("unexpected summary: %q", run.Summary)
+	}
+	if captured.SubscriptionID != "sub-1" || captured.JobID != "library-subscription-sub-1" {
+		t.Fatalf("unexpected poll request: %#v", captured)
+	}
+}
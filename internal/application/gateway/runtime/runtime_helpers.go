@@ -9,6 +9,7 @@ import (
 	"dreamcreator/internal/application/agentruntime"
 	"dreamcreator/internal/application/gateway/queue"
 	runtimedto "dreamcreator/internal/application/gateway/runtime/dto"
+	gatewaytools "dreamcreator/internal/application/gateway/tools"
 	"dreamcreator/internal/application/runtimeconfig"
 	settingsdto "dreamcreator/internal/application/settings/dto"
 	skillsdto "dreamcreator/internal/application/skills/dto"
@@ -403,6 +404,12 @@ func (service *Service) filterToolSpecs(specs []tooldto.ToolSpec, config runtime
 	if isToolModeDisabled(config.Mode) {
 		return nil
 	}
+	mcpServers := make(map[string]struct{}, len(assistantTools.MCPServers))
+	for _, server := range assistantTools.MCPServers {
+		if server.Enabled {
+			mcpServers[domainassistant.NormalizeMCPServerID(server.ID)] = struct{}{}
+		}
+	}
 	assistantAllowed := make(map[string]struct{})
 	if len(assistantTools.Items) > 0 {
 		for _, item := range assistantTools.Items {
@@ -442,7 +449,17 @@ func (service *Service) filterToolSpecs(specs []tooldto.ToolSpec, config runtime
 			continue
 		}
 		key := strings.ToLower(name)
-		if len(assistantAllowed) > 0 {
+		if spec.Kind == gatewaytools.MCPToolKind {
+			// MCP tools are shared across assistants; only expose the ones
+			// mounted from this assistant's own servers.
+			serverID, ok := gatewaytools.MCPServerIDFromToolName(name)
+			if !ok {
+				continue
+			}
+			if _, mounted := mcpServers[serverID]; !mounted {
+				continue
+			}
+		} else if len(assistantAllowed) > 0 {
 			if _, ok := assistantAllowed[key]; !ok {
 				continue
 			}
@@ -460,6 +477,31 @@ func (service *Service) filterToolSpecs(specs []tooldto.ToolSpec, config runtime
 	return result
 }
 
+// withoutMCPServers drops the servers with the given ids, so tools mounted
+// under those ids by other assistants are not exposed.
+func withoutMCPServers(servers []domainassistant.AssistantMCPServer, ids []string) []domainassistant.AssistantMCPServer {
+	if len(ids) == 0 {
+		return servers
+	}
+	result := make([]domainassistant.AssistantMCPServer, 0, len(servers))
+	for _, server := range servers {
+		if !containsNormalizedMCPServerID(ids, server.ID) {
+			result = append(result, server)
+		}
+	}
+	return result
+}
+
+func containsNormalizedMCPServerID(ids []string, id string) bool {
+	id = domainassistant.NormalizeMCPServerID(id)
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
 func isToolModeDisabled(mode string) bool {
 	switch strings.ToLower(strings.TrimSpace(mode)) {
 	case "off", "none", "disabled":
@@ -1,9 +1,12 @@
 package runtime
 
 import (
+	"strings"
 	"testing"
 
 	runtimedto "dreamcreator/internal/application/gateway/runtime/dto"
+	tooldto "dreamcreator/internal/application/tools/dto"
+	domainassistant "dreamcreator/internal/domain/assistant"
 )
 
 func TestResolveThinkingLevel(t *testing.T) {
@@ -135,3 +138,43 @@ func TestResolveStructuredOutputConfig(t *testing.T) {
 		t.Fatalf("expected schema type object, got %#v", config.Schema["type"])
 	}
 }
+
+func TestFilterToolSpecsScopesMCPToolsToAssistantServers(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{}
+	specs := []tooldto.ToolSpec{
+		{ID: "read", Name: "read", Enabled: true},
+		{ID: "exec", Name: "exec", Enabled: true},
+		{ID: "mcp__files__echo", Name: "mcp__files__echo", Kind: "mcp", Enabled: true},
+		{ID: "mcp__other__echo", Name: "mcp__other__echo", Kind: "mcp", Enabled: true},
+	}
+	filtered := service.filterToolSpecs(specs, runtimedto.ToolExecutionConfig{}, domainassistant.AssistantTools{
+		Items:      []domainassistant.AssistantToolItem{{ID: "read", Enabled: true}},
+		MCPServers: []domainassistant.AssistantMCPServer{{ID: "files", Enabled: true}},
+	})
+	names := make([]string, 0, len(filtered))
+	for _, spec := range filtered {
+		names = append(names, spec.Name)
+	}
+	if strings.Join(names, ",") != "read,mcp__files__echo" {
+		t.Fatalf("unexpected tools %v", names)
+	}
+
+	filtered = service.filterToolSpecs(specs, runtimedto.ToolExecutionConfig{DenyList: []string{"mcp__files__echo"}}, domainassistant.AssistantTools{
+		MCPServers: []domainassistant.AssistantMCPServer{{ID: "files", Enabled: true}},
+	})
+	for _, spec := range filtered {
+		if spec.Kind == "mcp" {
+			t.Fatalf("expected the deny list to hide %q", spec.Name)
+		}
+	}
+
+	servers := withoutMCPServers([]domainassistant.AssistantMCPServer{{ID: "Files", Enabled: true}}, []string{"files"})
+	filtered = service.filterToolSpecs(specs, runtimedto.ToolExecutionConfig{}, domainassistant.AssistantTools{MCPServers: servers})
+	for _, spec := range filtered {
+		if spec.Kind == "mcp" {
+			t.Fatalf("expected rejected servers to hide %q", spec.Name)
+		}
+	}
+}
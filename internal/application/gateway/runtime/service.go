@@ -345,10 +345,15 @@ func (service *Service) runWithStream(ctx context.Context, request dto.RuntimeRu
 	toolConfig := mergeToolExecutionConfig(request.Tools, assistantSnapshot.Call, flags.IsSubagent)
 
 	var availableToolSpecs []tooldto.ToolSpec
+	assistantTools := assistantSnapshot.Tools
 	if service.tools != nil {
+		if !isToolModeDisabled(toolConfig.Mode) {
+			rejected := service.tools.MountMCPServers(ctx, assistantID, assistantTools.MCPServers)
+			assistantTools.MCPServers = withoutMCPServers(assistantTools.MCPServers, rejected)
+		}
 		availableToolSpecs = service.tools.ListTools(ctx)
 	}
-	toolSpecs := service.filterToolSpecs(availableToolSpecs, toolConfig, assistantSnapshot.Tools)
+	toolSpecs := service.filterToolSpecs(availableToolSpecs, toolConfig, assistantTools)
 	skillItems := service.resolveSkillPromptItems(
 		ctx,
 		resolvedModel.ProviderID,
@@ -471,11 +476,12 @@ func (service *Service) runWithStream(ctx context.Context, request dto.RuntimeRu
 		AgentID:         strings.TrimSpace(request.AgentID),
 		ProviderID:      strings.TrimSpace(resolvedModel.ProviderID),
 		Source:          "runtime",
+		WorkspacePath:   strings.TrimSpace(workspaceSnapshot.RootPath),
 		IsSubagent:      flags.IsSubagent,
 		RequireSandbox:  toolConfig.RequireSandbox,
 		RequireApproval: toolConfig.RequireApproval,
 	}
-	toolInfos, toolAdapters := service.resolveToolAdapters(runCtx, sessionKey, run.ID, toolConfig, assistantSnapshot.Tools, policyCtx)
+	toolInfos, toolAdapters := service.resolveToolAdapters(runCtx, sessionKey, run.ID, toolConfig, assistantTools, policyCtx)
 	if len(toolInfos) > 0 {
 		if toolModel, ok := resolveToolCallingModel(chatModel); ok {
 			if bound, bindErr := toolModel.WithTools(toolInfos); bindErr == nil {
@@ -2,19 +2,26 @@ package sandbox
 
 import (
 	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
+	settingsdto "dreamcreator/internal/application/settings/dto"
 	"dreamcreator/internal/infrastructure/secure"
 )
 
 type Resolution struct {
-	Allowed          bool   `json:"allowed"`
-	Mode             string `json:"mode,omitempty"`
-	WorkspaceAccess  string `json:"workspaceAccess,omitempty"`
-	SandboxID        string `json:"sandboxId,omitempty"`
-	Reason           string `json:"reason,omitempty"`
-	ApprovalRequired bool   `json:"approvalRequired,omitempty"`
+	Allowed          bool                  `json:"allowed"`
+	Mode             string                `json:"mode,omitempty"`
+	WorkspaceAccess  string                `json:"workspaceAccess,omitempty"`
+	SandboxID        string                `json:"sandboxId,omitempty"`
+	Reason           string                `json:"reason,omitempty"`
+	ApprovalRequired bool                  `json:"approvalRequired,omitempty"`
+	Policy           *secure.SandboxPolicy `json:"policy,omitempty"`
+
+	backend secure.CommandSandbox
 }
 
 type ResolveRequest struct {
@@ -28,28 +35,56 @@ type HealthStatus struct {
 	CheckedAt time.Time `json:"checkedAt"`
 }
 
+type SettingsReader interface {
+	GetSettings(ctx context.Context) (settingsdto.Settings, error)
+}
+
 type Service struct {
-	enabled bool
-	health  secure.HealthChecker
-	now     func() time.Time
+	enabled  bool
+	health   secure.HealthChecker
+	backend  secure.CommandSandbox
+	settings SettingsReader
+	now      func() time.Time
 }
 
+// NewService builds the sandbox resolver. When the health checker is also a
+// secure.CommandSandbox, sandboxed tools run inside that backend; otherwise
+// they run locally once the health check passes.
 func NewService(enabled bool, healthChecker secure.HealthChecker) *Service {
 	if healthChecker == nil {
 		healthChecker = secure.NoopHealthChecker{}
 	}
+	backend, _ := healthChecker.(secure.CommandSandbox)
 	return &Service{
 		enabled: enabled,
 		health:  healthChecker,
+		backend: backend,
 		now:     time.Now,
 	}
 }
 
+// SetSettingsReader makes Resolve follow the live gateway sandbox settings
+// instead of the values captured at startup.
+func (service *Service) SetSettingsReader(reader SettingsReader) {
+	if service == nil {
+		return
+	}
+	service.settings = reader
+}
+
 func (service *Service) Resolve(ctx context.Context, request ResolveRequest) (Resolution, error) {
 	if !request.RequiresSandbox {
 		return Resolution{Allowed: true, Mode: "off"}, nil
 	}
-	if !service.enabled {
+	enabled := service.enabled
+	policy := secure.SandboxPolicy{AllowNetwork: true}
+	if service.settings != nil {
+		if current, err := service.settings.GetSettings(ctx); err == nil {
+			enabled = current.Gateway.SandboxEnabled
+			policy = sandboxPolicyFromSettings(current.Gateway.Sandbox)
+		}
+	}
+	if !enabled {
 		return Resolution{Allowed: false, Mode: "off", Reason: "sandbox_unavailable"}, nil
 	}
 	status := service.health.Check(ctx)
@@ -61,14 +96,33 @@ func (service *Service) Resolve(ctx context.Context, request ResolveRequest) (Re
 		return Resolution{Allowed: false, Mode: "off", Reason: reason}, nil
 	}
 	workspacePath := strings.TrimSpace(request.WorkspacePath)
-	if workspacePath == "" {
-		workspacePath = "/"
+	if service.backend == nil {
+		if workspacePath == "" {
+			workspacePath = "/"
+		}
+		return Resolution{
+			Allowed:         true,
+			Mode:            "local",
+			WorkspaceAccess: "rw",
+			SandboxID:       "local",
+		}, nil
+	}
+	workspaceAccess := "none"
+	if workspacePath != "" {
+		absolute, err := filepath.Abs(workspacePath)
+		if err != nil {
+			return Resolution{}, err
+		}
+		policy.WorkspaceDir = absolute
+		workspaceAccess = "rw"
 	}
 	return Resolution{
 		Allowed:         true,
-		Mode:            "local",
-		WorkspaceAccess: "rw",
-		SandboxID:       "local",
+		Mode:            service.backend.Backend(),
+		WorkspaceAccess: workspaceAccess,
+		SandboxID:       service.backend.Backend(),
+		Policy:          &policy,
+		backend:         service.backend,
 	}, nil
 }
 
@@ -80,3 +134,76 @@ func (service *Service) Health(ctx context.Context) HealthStatus {
 		CheckedAt: status.CheckedAt,
 	}
 }
+
+// Confined reports whether commands and writes are restricted by a sandbox
+// backend rather than running with the user's full privileges.
+func (resolution Resolution) Confined() bool {
+	return resolution.backend != nil && resolution.Policy != nil
+}
+
+// Command builds a command that runs inside the resolved sandbox, or a plain
+// command when the resolution is not confined.
+func (resolution Resolution) Command(ctx context.Context, name string, args ...string) (*exec.Cmd, error) {
+	if !resolution.Confined() {
+		return exec.CommandContext(ctx, name, args...), nil
+	}
+	return resolution.backend.Command(ctx, *resolution.Policy, name, args...)
+}
+
+// WithoutTimeout returns a copy of the resolution whose commands have no
+// wall-clock limit, for long-lived processes whose lifetime is managed by
+// the caller. CPU and memory limits still apply.
+func (resolution Resolution) WithoutTimeout() Resolution {
+	if resolution.Policy == nil {
+		return resolution
+	}
+	policy := *resolution.Policy
+	policy.Timeout = 0
+	resolution.Policy = &policy
+	return resolution
+}
+
+// CheckWrite rejects in-process file writes outside the writable paths of a
+// confined resolution.
+func (resolution Resolution) CheckWrite(path string) error {
+	if !resolution.Confined() {
+		return nil
+	}
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, root := range append([]string{resolution.Policy.WorkspaceDir}, resolution.Policy.WritablePaths...) {
+		if strings.TrimSpace(root) == "" {
+			continue
+		}
+		roots := []string{filepath.Clean(root)}
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			roots = append(roots, resolved)
+		}
+		for _, candidate := range roots {
+			rel, err := filepath.Rel(candidate, target)
+			if err != nil {
+				continue
+			}
+			if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+				return nil
+			}
+		}
+	}
+	return errors.New("sandbox: writes are limited to the assistant workspace")
+}
+
+func sandboxPolicyFromSettings(settings settingsdto.GatewaySandboxSettings) secure.SandboxPolicy {
+	policy := secure.SandboxPolicy{
+		AllowNetwork: settings.AllowNetwork,
+		CPUSeconds:   settings.CPUSeconds,
+	}
+	if settings.MemoryMB > 0 {
+		policy.MemoryBytes = int64(settings.MemoryMB) << 20
+	}
+	if settings.TimeoutSeconds > 0 {
+		policy.Timeout = time.Duration(settings.TimeoutSeconds) * time.Second
+	}
+	return policy
+}
@@ -0,0 +1,107 @@
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	settingsdto "dreamcreator/internal/application/settings/dto"
+	"dreamcreator/internal/infrastructure/secure"
+)
+
+type stubBackend struct {
+	available bool
+	policies  []secure.SandboxPolicy
+}
+
+func (stub *stubBackend) Check(context.Context) secure.SandboxHealthStatus {
+	if !stub.available {
+		return secure.SandboxHealthStatus{Message: "bubblewrap (bwrap) is not installed"}
+	}
+	return secure.SandboxHealthStatus{Available: true, Message: "ok"}
+}
+
+func (stub *stubBackend) Backend() string {
+	return "stub"
+}
+
+func (stub *stubBackend) Command(ctx context.Context, policy secure.SandboxPolicy, name string, args ...string) (*exec.Cmd, error) {
+	stub.policies = append(stub.policies, policy)
+	return exec.CommandContext(ctx, "stub-sandbox", append([]string{name}, args...)...), nil
+}
+
+type stubSettings struct {
+	settings settingsdto.Settings
+}
+
+func (stub stubSettings) GetSettings(context.Context) (settingsdto.Settings, error) {
+	return stub.settings, nil
+}
+
+func TestResolveUsesBackendAndSettings(t *testing.T) {
+	backend := &stubBackend{available: true}
+	service := NewService(false, backend)
+	service.SetSettingsReader(stubSettings{settings: settingsdto.Settings{Gateway: settingsdto.GatewaySettings{
+		SandboxEnabled: true,
+		Sandbox:        settingsdto.GatewaySandboxSettings{CPUSeconds: 10, MemoryMB: 64, TimeoutSeconds: 30},
+	}}})
+	workspace := t.TempDir()
+
+	resolution, err := service.Resolve(context.Background(), ResolveRequest{RequiresSandbox: true, WorkspacePath: workspace})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if !resolution.Allowed || !resolution.Confined() || resolution.Mode != "stub" || resolution.WorkspaceAccess != "rw" {
+		t.Fatalf("unexpected resolution %#v", resolution)
+	}
+	policy := *resolution.Policy
+	if policy.WorkspaceDir != workspace || policy.AllowNetwork || policy.CPUSeconds != 10 || policy.MemoryBytes != 64<<20 || policy.Timeout != 30*time.Second {
+		t.Fatalf("unexpected policy %#v", policy)
+	}
+	cmd, err := resolution.Command(context.Background(), "ls", "-la")
+	if err != nil || cmd.Args[0] != "stub-sandbox" || len(backend.policies) != 1 {
+		t.Fatalf("expected the backend to build the command, got %v (%v)", cmd.Args, err)
+	}
+	if _, err := resolution.WithoutTimeout().Command(context.Background(), "sleep", "100"); err != nil || backend.policies[1].Timeout != 0 || backend.policies[1].CPUSeconds != 10 {
+		t.Fatalf("expected only the timeout to be dropped, got %#v (%v)", backend.policies, err)
+	}
+	if resolution.Policy.Timeout != 30*time.Second {
+		t.Fatal("expected WithoutTimeout to leave the original policy untouched")
+	}
+	if err := resolution.CheckWrite(filepath.Join(workspace, "notes", "a.txt")); err != nil {
+		t.Fatalf("expected workspace writes to be allowed: %v", err)
+	}
+	if err := resolution.CheckWrite(filepath.Join(filepath.Dir(workspace), "outside.txt")); err == nil {
+		t.Fatal("expected writes outside the workspace to be rejected")
+	}
+}
+
+func TestResolveReportsUnavailableBackend(t *testing.T) {
+	service := NewService(true, &stubBackend{})
+	resolution, err := service.Resolve(context.Background(), ResolveRequest{RequiresSandbox: true})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if resolution.Allowed || resolution.Reason != "bubblewrap (bwrap) is not installed" {
+		t.Fatalf("unexpected resolution %#v", resolution)
+	}
+	if health := service.Health(context.Background()); health.Available {
+		t.Fatalf("expected health to report the backend as unavailable, got %#v", health)
+	}
+}
+
+func TestResolveWithoutBackendRunsLocally(t *testing.T) {
+	service := NewService(true, nil)
+	resolution, err := service.Resolve(context.Background(), ResolveRequest{RequiresSandbox: true})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if !resolution.Allowed || resolution.Mode != "local" || resolution.Confined() {
+		t.Fatalf("unexpected resolution %#v", resolution)
+	}
+	if err := resolution.CheckWrite("/etc/hosts"); err != nil {
+		t.Fatalf("expected unconfined resolutions to allow writes: %v", err)
+	}
+}
@@ -152,16 +152,21 @@ func specProcess() toolSpec {
 	return toolSpec{
 		ID:          "process",
 		Name:        "process",
-		Description: "Spawn a background process.",
+		Description: "Start and manage background processes: start returns an id; poll returns new output and the exit status, log returns the buffered output, write sends stdin, kill stops the process tree, list shows this session's processes. Processes are killed when the session ends; in the sandbox they keep its CPU and memory limits but not its command timeout.",
 		Category:    "runtime",
 		RiskLevel:   "high",
 		SchemaJSON: schemaJSON(map[string]any{
 			"type": "object",
 			"properties": map[string]any{
+				"action":  map[string]any{"type": "string", "enum": []string{"start", "list", "poll", "log", "write", "kill"}},
 				"command": map[string]any{"type": "string"},
 				"cmd":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
 				"cwd":     map[string]any{"type": "string"},
 				"env":     map[string]any{"type": "object"},
+				"id":      map[string]any{"type": "string"},
+				"input":   map[string]any{"type": "string"},
+				"eof":     map[string]any{"type": "boolean"},
+				"waitMs":  map[string]any{"type": "integer"},
 			},
 		}),
 		Enabled: true,
@@ -1167,7 +1172,7 @@ func specExternalToolsQuery() toolSpec {
 	return toolSpec{
 		ID:          "external_tools_query",
 		Name:        "external_tools_query",
-		Description: "Query external tools state, progress, and updates.",
+		Description: "Query external tools state, progress, and updates, plus MCP server health (action=mcp).",
 		Category:    "external_tools",
 		RiskLevel:   "low",
 		SchemaJSON: schemaJSON(map[string]any{
@@ -1175,7 +1180,7 @@ func specExternalToolsQuery() toolSpec {
 			"properties": map[string]any{
 				"action": map[string]any{
 					"type": "string",
-					"enum": []string{"list", "status", "install_state", "updates"},
+					"enum": []string{"list", "status", "install_state", "updates", "mcp"},
 				},
 				"name": map[string]any{"type": "string"},
 			},
@@ -36,6 +36,8 @@ type BuiltinToolDeps struct {
 	Models        providers.ModelRepository
 	Secrets       providers.SecretRepository
 	Memory        *memoryservice.MemoryService
+	MCP           *MCPManager
+	Processes     *ProcessManager
 }
 
 func RegisterBuiltinTools(ctx context.Context, toolSvc *toolservice.ToolService, executor *RegistryExecutor, deps BuiltinToolDeps) {
@@ -47,7 +49,7 @@ func RegisterBuiltinTools(ctx context.Context, toolSvc *toolservice.ToolService,
 	registerTool(ctx, toolSvc, executor, specEdit(), runEditTool)
 	registerTool(ctx, toolSvc, executor, specApplyPatch(), runApplyPatchTool)
 	registerTool(ctx, toolSvc, executor, specExec(), runExecTool)
-	registerTool(ctx, toolSvc, executor, specProcess(), runProcessTool)
+	registerTool(ctx, toolSvc, executor, specProcess(), runProcessTool(deps.Processes))
 	registerTool(ctx, toolSvc, executor, specWebFetch(), runWebFetchTool(deps.Settings, deps.Connectors))
 	registerTool(ctx, toolSvc, executor, specWebSearch(), runWebSearchTool(deps.Settings, deps.Connectors))
 	registerTool(ctx, toolSvc, executor, specBrowser(), runBrowserTool(deps.Settings, deps.Connectors, deps.Nodes))
@@ -68,7 +70,7 @@ func RegisterBuiltinTools(ctx context.Context, toolSvc *toolservice.ToolService,
 	registerTool(ctx, toolSvc, executor, specMemoryQuery(), runMemoryQueryTool(deps.Memory))
 	registerTool(ctx, toolSvc, executor, specMemoryManage(), runMemoryManageTool(deps.Memory))
 	if deps.ExternalTools != nil {
-		registerTool(ctx, toolSvc, executor, specExternalToolsQuery(), runExternalToolsQueryTool(deps.ExternalTools))
+		registerTool(ctx, toolSvc, executor, specExternalToolsQuery(), runExternalToolsQueryTool(deps.ExternalTools, deps.MCP))
 		registerTool(ctx, toolSvc, executor, specExternalToolsManage(), runExternalToolsManageTool(deps.ExternalTools))
 	}
 	if deps.Skills != nil {
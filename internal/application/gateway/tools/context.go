@@ -1,6 +1,10 @@
 package tools
 
-import "context"
+import (
+	"context"
+
+	gatewaysandbox "dreamcreator/internal/application/gateway/sandbox"
+)
 
 type runtimeContextKey struct{}
 
@@ -30,3 +34,44 @@ func RuntimeContextFromContext(ctx context.Context) (string, string) {
 	}
 	return value.sessionKey, value.runID
 }
+
+type sandboxResolutionKey struct{}
+
+func withSandboxResolution(ctx context.Context, resolution gatewaysandbox.Resolution) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sandboxResolutionKey{}, resolution)
+}
+
+func sandboxResolutionFromContext(ctx context.Context) (gatewaysandbox.Resolution, bool) {
+	if ctx == nil {
+		return gatewaysandbox.Resolution{}, false
+	}
+	resolution, ok := ctx.Value(sandboxResolutionKey{}).(gatewaysandbox.Resolution)
+	return resolution, ok && resolution.Confined()
+}
+
+type toolCallKey struct{}
+
+type toolCallArgs struct {
+	action string
+	args   string
+}
+
+// withToolCall carries the invocation arguments to the policy pipeline, whose
+// Decide signature only sees the tool spec.
+func withToolCall(ctx context.Context, action string, args string) context.Context {
+	if ctx == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, toolCallKey{}, toolCallArgs{action: action, args: args})
+}
+
+func toolCallFromContext(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	value, _ := ctx.Value(toolCallKey{}).(toolCallArgs)
+	return value.action, value.args
+}
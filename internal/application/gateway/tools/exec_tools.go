@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -49,30 +50,78 @@ func runExecTool(ctx context.Context, args string) (string, error) {
 	return marshalResult(result), nil
 }
 
-type processResult struct {
-	PID     int    `json:"pid"`
-	Command string `json:"command"`
-}
-
-func runProcessTool(ctx context.Context, args string) (string, error) {
-	payload, err := parseToolArgs(args)
-	if err != nil {
-		return "", err
-	}
-	command, cmdArgs, err := resolveCommand(payload)
-	if err != nil {
-		return "", err
-	}
-	cwd := getStringArg(payload, "cwd", "workingDir", "workingDirectory")
-	cmd := exec.CommandContext(ctx, command, cmdArgs...)
-	processutil.ConfigureCLI(cmd)
-	if strings.TrimSpace(cwd) != "" {
-		cmd.Dir = cwd
-	}
-	if err := cmd.Start(); err != nil {
-		return "", err
+func runProcessTool(manager *ProcessManager) func(ctx context.Context, args string) (string, error) {
+	return func(ctx context.Context, args string) (string, error) {
+		if manager == nil {
+			return "", errors.New("process manager unavailable")
+		}
+		payload, err := parseToolArgs(args)
+		if err != nil {
+			return "", err
+		}
+		action := strings.ToLower(strings.TrimSpace(getStringArg(payload, "action")))
+		if action == "" {
+			action = "start"
+		}
+		id := getStringArg(payload, "id", "processId")
+		switch action {
+		case "start":
+			command, cmdArgs, err := resolveCommand(payload)
+			if err != nil {
+				return "", err
+			}
+			display := getStringArg(payload, "command", "cmdline")
+			if display == "" {
+				display = strings.Join(getStringSliceArg(payload, "cmd"), " ")
+			}
+			info, err := manager.Start(ctx, ProcessStartRequest{
+				Command: command,
+				Args:    cmdArgs,
+				Display: display,
+				Cwd:     getStringArg(payload, "cwd", "workingDir", "workingDirectory"),
+				Env:     getMapArg(payload, "env"),
+			})
+			if err != nil {
+				return "", err
+			}
+			return marshalResult(info), nil
+		case "list":
+			items := manager.List(ctx)
+			return marshalResult(map[string]any{
+				"items": items,
+				"total": len(items),
+			}), nil
+		case "poll":
+			waitMs, _ := getIntArg(payload, "waitMs", "timeoutMs")
+			waitMs = min(max(waitMs, 0), int(processMaxPollWait/time.Millisecond))
+			output, err := manager.Poll(ctx, id, time.Duration(waitMs)*time.Millisecond)
+			if err != nil {
+				return "", err
+			}
+			return marshalResult(output), nil
+		case "log":
+			output, err := manager.Log(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return marshalResult(output), nil
+		case "write":
+			closeStdin, _ := getBoolArg(payload, "eof", "closeStdin")
+			info, err := manager.Write(ctx, id, toString(payload["input"]), closeStdin)
+			if err != nil {
+				return "", err
+			}
+			return marshalResult(info), nil
+		case "kill":
+			info, err := manager.Kill(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return marshalResult(info), nil
+		default:
+			return "", fmt.Errorf("unsupported process action: %s", action)
+		}
 	}
-	return marshalResult(processResult{PID: cmd.Process.Pid, Command: command}), nil
 }
 
 func resolveCommand(args toolArgs) (string, []string, error) {
@@ -106,22 +155,36 @@ func resolveCommand(args toolArgs) (string, []string, error) {
 	return "bash", []string{"-lc", command}, nil
 }
 
-func runCommandWithInput(ctx context.Context, cwd string, command string, cmdArgs []string, input string, env map[string]any, maxOutput int) (int, string, string, error) {
-	cmd := exec.CommandContext(ctx, command, cmdArgs...)
+// newToolCommand builds a tool subprocess, confining it to the sandbox when
+// the invocation resolved one. Sandboxed commands default to the workspace
+// as their working directory.
+func newToolCommand(ctx context.Context, cwd string, command string, cmdArgs ...string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	if resolution, ok := sandboxResolutionFromContext(ctx); ok {
+		sandboxed, err := resolution.Command(ctx, command, cmdArgs...)
+		if err != nil {
+			return nil, err
+		}
+		cmd = sandboxed
+		if strings.TrimSpace(cwd) == "" {
+			cwd = resolution.Policy.WorkspaceDir
+		}
+	} else {
+		cmd = exec.CommandContext(ctx, command, cmdArgs...)
+	}
 	processutil.ConfigureCLI(cmd)
 	if strings.TrimSpace(cwd) != "" {
 		cmd.Dir = cwd
 	}
-	if len(env) > 0 {
-		merged := append([]string{}, cmd.Environ()...)
-		for key, value := range env {
-			if strings.TrimSpace(key) == "" {
-				continue
-			}
-			merged = append(merged, key+"="+strings.TrimSpace(toString(value)))
-		}
-		cmd.Env = merged
+	return cmd, nil
+}
+
+func runCommandWithInput(ctx context.Context, cwd string, command string, cmdArgs []string, input string, env map[string]any, maxOutput int) (int, string, string, error) {
+	cmd, err := newToolCommand(ctx, cwd, command, cmdArgs...)
+	if err != nil {
+		return -1, "", "", err
 	}
+	applyCommandEnv(cmd, env)
 	if input != "" {
 		cmd.Stdin = strings.NewReader(input)
 	}
@@ -129,7 +192,7 @@ func runCommandWithInput(ctx context.Context, cwd string, command string, cmdArg
 	var stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
-	err := cmd.Run()
+	err = cmd.Run()
 	exitCode := 0
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -150,6 +213,20 @@ func runCommandWithInput(ctx context.Context, cwd string, command string, cmdArg
 	return exitCode, stdout, stderr, err
 }
 
+func applyCommandEnv(cmd *exec.Cmd, env map[string]any) {
+	if len(env) == 0 {
+		return
+	}
+	merged := append([]string{}, cmd.Environ()...)
+	for key, value := range env {
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+		merged = append(merged, key+"="+strings.TrimSpace(toString(value)))
+	}
+	cmd.Env = merged
+}
+
 func toString(value any) string {
 	switch typed := value.(type) {
 	case string:
@@ -6,6 +6,7 @@ import (
 
 	externaltoolsdto "dreamcreator/internal/application/externaltools/dto"
 	externaltoolsservice "dreamcreator/internal/application/externaltools/service"
+	"dreamcreator/internal/domain/assistant"
 	"dreamcreator/internal/domain/externaltools"
 )
 
@@ -25,7 +26,7 @@ type externalToolWithReadiness struct {
 	Reason string `json:"reason,omitempty"`
 }
 
-func runExternalToolsQueryTool(service *externaltoolsservice.ExternalToolsService) func(ctx context.Context, args string) (string, error) {
+func runExternalToolsQueryTool(service *externaltoolsservice.ExternalToolsService, mcp *MCPManager) func(ctx context.Context, args string) (string, error) {
 	return func(ctx context.Context, args string) (string, error) {
 		if service == nil {
 			return marshalExternalToolsResult(externalToolsToolResult{
@@ -42,11 +43,16 @@ func runExternalToolsQueryTool(service *externaltoolsservice.ExternalToolsServic
 			}), nil
 		}
 		action := strings.ToLower(strings.TrimSpace(getStringArg(payload, "action", "type")))
-		if action == "" {
+		switch action {
+		case "":
 			action = "list"
+		case "mcp_servers", "mcp_health":
+			action = "mcp"
 		}
 		toolName := strings.TrimSpace(getStringArg(payload, "name", "tool"))
 		switch action {
+		case "mcp":
+			return marshalMCPServerHealthResult(mcp, action, toolName), nil
 		case "list":
 			items, listErr := service.ListTools(ctx)
 			if listErr != nil {
@@ -359,6 +365,44 @@ func marshalExternalToolMutationResult(ctx context.Context, service *externaltoo
 	}), nil
 }
 
+func marshalMCPServerHealthResult(mcp *MCPManager, action string, serverID string) string {
+	if mcp == nil {
+		return marshalExternalToolsResult(externalToolsToolResult{
+			Ok:     false,
+			Action: action,
+			Error:  "mcp servers unavailable",
+		})
+	}
+	servers := mcp.Health()
+	if serverID == "" {
+		return marshalExternalToolsResult(externalToolsToolResult{
+			Ok:     true,
+			Action: action,
+			Data:   servers,
+		})
+	}
+	normalized := assistant.NormalizeMCPServerID(serverID)
+	for _, server := range servers {
+		if server.ID != normalized {
+			continue
+		}
+		return marshalExternalToolsResult(externalToolsToolResult{
+			Ok:     true,
+			Action: action,
+			Tool:   server.ID,
+			Data:   server,
+			Ready:  server.Status == MCPServerStatusReady,
+			Reason: server.Error,
+		})
+	}
+	return marshalExternalToolsResult(externalToolsToolResult{
+		Ok:     false,
+		Action: action,
+		Tool:   serverID,
+		Error:  errMCPServerNotMounted.Error(),
+	})
+}
+
 func marshalExternalToolsResult(result externalToolsToolResult) string {
 	return marshalResult(result)
 }
@@ -52,7 +52,7 @@ func TestExternalToolsQueryStatusReturnsReadiness(t *testing.T) {
 		t.Fatalf("ensure defaults failed: %v", err)
 	}
 
-	handler := runExternalToolsQueryTool(svc)
+	handler := runExternalToolsQueryTool(svc, nil)
 	output, err := handler(context.Background(), `{"action":"status","name":"clawhub"}`)
 	if err != nil {
 		t.Fatalf("query status failed: %v", err)
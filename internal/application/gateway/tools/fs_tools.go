@@ -50,7 +50,6 @@ type writeResult struct {
 }
 
 func runWriteTool(ctx context.Context, args string) (string, error) {
-	_ = ctx
 	payload, err := parseToolArgs(args)
 	if err != nil {
 		return "", err
@@ -59,6 +58,9 @@ func runWriteTool(ctx context.Context, args string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if path, err = sandboxWritablePath(ctx, path); err != nil {
+		return "", err
+	}
 	content := getStringArg(payload, "content", "input", "text")
 	appendMode, _ := getBoolArg(payload, "append")
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -88,7 +90,6 @@ type editResult struct {
 }
 
 func runEditTool(ctx context.Context, args string) (string, error) {
-	_ = ctx
 	payload, err := parseToolArgs(args)
 	if err != nil {
 		return "", err
@@ -97,6 +98,9 @@ func runEditTool(ctx context.Context, args string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if path, err = sandboxWritablePath(ctx, path); err != nil {
+		return "", err
+	}
 	oldText := getStringArg(payload, "oldText", "old", "find")
 	newText := getStringArg(payload, "newText", "new", "replace")
 	if oldText == "" {
@@ -130,7 +134,6 @@ func runEditTool(ctx context.Context, args string) (string, error) {
 }
 
 func runApplyPatchTool(ctx context.Context, args string) (string, error) {
-	_ = ctx
 	payload, err := parseToolArgs(args)
 	if err != nil {
 		return "", err
@@ -143,6 +146,9 @@ func runApplyPatchTool(ctx context.Context, args string) (string, error) {
 	if strings.TrimSpace(root) == "" {
 		root = "."
 	}
+	if root, err = sandboxWritablePath(ctx, root); err != nil {
+		return "", err
+	}
 	cmdArgs := []string{"apply", "--whitespace=nowarn", "-"}
 	exitCode, stdout, stderr, err := runCommandWithInput(ctx, root, "git", cmdArgs, patch, nil, 0)
 	if err != nil {
@@ -160,3 +166,39 @@ func runApplyPatchTool(ctx context.Context, args string) (string, error) {
 	}
 	return marshalResult(map[string]any{"ok": true}), nil
 }
+
+// sandboxWritablePath anchors relative paths at the workspace and rejects
+// targets outside it when the invocation runs in a confined sandbox.
+func sandboxWritablePath(ctx context.Context, path string) (string, error) {
+	resolution, ok := sandboxResolutionFromContext(ctx)
+	if !ok {
+		return path, nil
+	}
+	if !filepath.IsAbs(path) && resolution.Policy.WorkspaceDir != "" {
+		path = filepath.Join(resolution.Policy.WorkspaceDir, path)
+	}
+	if err := resolution.CheckWrite(path); err != nil {
+		return "", err
+	}
+	// Follow symlinks on the existing part of the path so a link inside the
+	// workspace cannot redirect the write elsewhere.
+	existing := filepath.Clean(path)
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path, nil
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	if err := resolution.CheckWrite(resolved); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -66,6 +66,26 @@ var libraryManageMethodDefinitions = []libraryManageMethodDefinition{
 			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
 		),
 	},
+	{
+		name:        "download.playlist.create",
+		inputSchema: libraryManageDownloadPlaylistCreateParamsSchema(),
+		inputExample: map[string]any{
+			"action":  "download.playlist.create",
+			"url":     "https://www.youtube.com/playlist?list=PL123",
+			"title":   "Season 1",
+			"quality": "best",
+			"entries": []map[string]any{
+				{"index": 1, "url": "https://www.youtube.com/watch?v=abc", "title": "Episode 1"},
+				{"index": 2, "url": "https://www.youtube.com/watch?v=def", "title": "Episode 2"},
+			},
+		},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"download.playlist.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
 	{
 		name:         "download.retry",
 		inputSchema:  libraryManageOperationIDParamsSchema("Retry a failed download operation by id."),
@@ -88,6 +108,54 @@ var libraryManageMethodDefinitions = []libraryManageMethodDefinition{
 			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
 		),
 	},
+	{
+		name:         "clip.create",
+		inputSchema:  libraryManageClipCreateParamsSchema(),
+		inputExample: map[string]any{"action": "clip.create", "fileId": "file_video_123", "startMs": 600000, "endMs": 780000, "includeSubtitle": true},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"clip.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "chapters.split.create",
+		inputSchema:  libraryManageChapterSplitCreateParamsSchema(),
+		inputExample: map[string]any{"action": "chapters.split.create", "fileId": "file_video_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"chapters.split.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:        "file.chapters.update",
+		inputSchema: libraryManageFileChaptersUpdateParamsSchema(),
+		inputExample: map[string]any{
+			"action":     "file.chapters.update",
+			"fileId":     "file_video_123",
+			"timestamps": "0:00 Intro\n2:15 Setup\n10:40 Results",
+		},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.LibraryFileDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"file.chapters.update",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibraryFileDTO{})),
+		),
+	},
+	{
+		name:         "file.chapters.export",
+		inputSchema:  libraryManageFileChaptersExportParamsSchema(),
+		inputExample: map[string]any{"action": "file.chapters.export", "fileId": "file_video_123", "format": "youtube"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.ExportFileChaptersResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"file.chapters.export",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.ExportFileChaptersResult{})),
+		),
+	},
 	{
 		name:         "subtitle.translate.create",
 		inputSchema:  libraryManageSubtitleTranslateParamsSchema(),
@@ -121,6 +189,185 @@ var libraryManageMethodDefinitions = []libraryManageMethodDefinition{
 			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
 		),
 	},
+	{
+		name:         "subtitle.transcribe.create",
+		inputSchema:  libraryManageSubtitleTranscribeParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.transcribe.create", "fileId": "file_video_123", "language": "en"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.transcribe.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.ocr.create",
+		inputSchema:  libraryManageSubtitleOCRParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.ocr.create", "fileId": "file_video_123", "language": "eng"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.ocr.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "thumbnails.create",
+		inputSchema:  libraryManageThumbnailCreateParamsSchema(),
+		inputExample: map[string]any{"action": "thumbnails.create", "fileId": "file_video_123", "count": 9},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"thumbnails.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "file.cover.set",
+		inputSchema:  libraryManageFileCoverSetParamsSchema(),
+		inputExample: map[string]any{"action": "file.cover.set", "fileId": "file_video_123", "coverFileId": "file_thumbnail_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.LibraryFileDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"file.cover.set",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibraryFileDTO{})),
+		),
+	},
+	{
+		name:         "subtitle.extract.create",
+		inputSchema:  libraryManageSubtitleExtractParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.extract.create", "fileId": "file_video_123", "streamIndexes": []int{2}},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.extract.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.align.create",
+		inputSchema:  libraryManageSubtitleAlignParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.align.create", "fileId": "file_subtitle_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.align.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.bilingual_merge.create",
+		inputSchema:  libraryManageSubtitleBilingualMergeParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.bilingual_merge.create", "primaryFileId": "file_subtitle_en", "secondaryFileId": "file_subtitle_zh"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.bilingual_merge.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.bilingual_split.create",
+		inputSchema:  libraryManageSubtitleBilingualSplitParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.bilingual_split.create", "fileId": "file_subtitle_bilingual"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.bilingual_split.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.glossary_extract.create",
+		inputSchema:  libraryManageSubtitleGlossaryExtractParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.glossary_extract.create", "fileIds": []string{"file_subtitle_ep1", "file_subtitle_ep2"}, "targetLanguage": "zh-CN"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.glossary_extract.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:        "subtitle.pipeline.create",
+		inputSchema: libraryManageSubtitlePipelineParamsSchema(),
+		inputExample: map[string]any{
+			"action":    "subtitle.pipeline.create",
+			"libraryId": "lib_123",
+			"steps": []map[string]any{
+				{"kind": "proofread", "autoApply": true},
+				{"kind": "translate", "targetLanguages": []string{"zh-CN", "ja", "fr"}},
+				{"kind": "qa_review", "autoApply": true},
+				{"kind": "export", "exportDirectory": "/Users/me/Exports", "exportFormat": "ass"},
+			},
+		},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(libraryManageAsyncAcceptedResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.pipeline.create",
+			true,
+			buildToolTypeEmptyValue(reflect.TypeOf(libraryManageAsyncAcceptedResult{})),
+		),
+	},
+	{
+		name:         "subtitle.revision.restore",
+		inputSchema:  libraryManageSubtitleRevisionRestoreParamsSchema(),
+		inputExample: map[string]any{"action": "subtitle.revision.restore", "fileId": "file_subtitle_ep1", "revisionId": "rev_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.RestoreSubtitleRevisionResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subtitle.revision.restore",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.RestoreSubtitleRevisionResult{})),
+		),
+	},
+	{
+		name:          "subscription.list",
+		inputSchema:   map[string]any{"type": "object", "properties": map[string]any{}},
+		inputExample:  map[string]any{"action": "subscription.list"},
+		outputSchema:  buildLibraryManageSuccessOutputSchema(map[string]any{"type": "array", "items": buildToolTypeSchema(reflect.TypeOf(librarydto.LibrarySubscriptionDTO{}))}),
+		outputExample: buildLibraryManageSuccessOutputExample("subscription.list", false, []any{buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibrarySubscriptionDTO{}))}),
+	},
+	{
+		name:        "subscription.save",
+		inputSchema: libraryManageSubscriptionSaveParamsSchema(),
+		inputExample: map[string]any{
+			"action":              "subscription.save",
+			"url":                 "https://www.youtube.com/@example",
+			"name":                "Example channel",
+			"quality":             "best",
+			"pollIntervalMinutes": 60,
+			"enabled":             true,
+		},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.LibrarySubscriptionDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subscription.save",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibrarySubscriptionDTO{})),
+		),
+	},
+	{
+		name:         "subscription.delete",
+		inputSchema:  libraryManageSubscriptionIDParamsSchema("Delete a subscription and its download archive. Downloaded files are kept."),
+		inputExample: map[string]any{"action": "subscription.delete", "id": "sub_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":      map[string]any{"type": "string"},
+				"deleted": map[string]any{"type": "boolean"},
+			},
+		}),
+		outputExample: buildLibraryManageSuccessOutputExample("subscription.delete", false, map[string]any{"id": "sub_123", "deleted": true}),
+	},
+	{
+		name:         "subscription.poll",
+		inputSchema:  libraryManageSubscriptionIDParamsSchema("Check a subscription now and queue downloads for entries missing from its archive."),
+		inputExample: map[string]any{"action": "subscription.poll", "id": "sub_123"},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.PollSubscriptionResult{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"subscription.poll",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.PollSubscriptionResult{})),
+		),
+	},
 	{
 		name:         "operation.cancel",
 		inputSchema:  libraryManageOperationIDParamsSchema("Cancel a running or queued operation when that kind supports cancellation."),
@@ -132,6 +379,38 @@ var libraryManageMethodDefinitions = []libraryManageMethodDefinition{
 			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibraryOperationDTO{})),
 		),
 	},
+	{
+		name:         "operation.priority",
+		inputSchema:  libraryManageOperationPriorityParamsSchema(),
+		inputExample: map[string]any{"action": "operation.priority", "operationId": "op_queued_123", "priority": 10},
+		outputSchema: buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.LibraryOperationDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample(
+			"operation.priority",
+			false,
+			buildToolTypeEmptyValue(reflect.TypeOf(librarydto.LibraryOperationDTO{})),
+		),
+	},
+	{
+		name:          "queue.get",
+		inputSchema:   map[string]any{"type": "object", "properties": map[string]any{}},
+		inputExample:  map[string]any{"action": "queue.get"},
+		outputSchema:  buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample("queue.get", false, buildToolTypeEmptyValue(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+	},
+	{
+		name:          "queue.pause",
+		inputSchema:   map[string]any{"type": "object", "properties": map[string]any{}},
+		inputExample:  map[string]any{"action": "queue.pause"},
+		outputSchema:  buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample("queue.pause", false, buildToolTypeEmptyValue(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+	},
+	{
+		name:          "queue.resume",
+		inputSchema:   map[string]any{"type": "object", "properties": map[string]any{}},
+		inputExample:  map[string]any{"action": "queue.resume"},
+		outputSchema:  buildLibraryManageSuccessOutputSchema(buildToolTypeSchema(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+		outputExample: buildLibraryManageSuccessOutputExample("queue.resume", false, buildToolTypeEmptyValue(reflect.TypeOf(librarydto.OperationQueueStateDTO{}))),
+	},
 	{
 		name:         "operation.resume",
 		inputSchema:  libraryManageOperationIDParamsSchema("Resume a canceled or failed subtitle operation when checkpoint resume is supported."),
@@ -298,6 +577,14 @@ func libraryManageDownloadParseParamsSchema() map[string]any {
 				"type":        "boolean",
 				"description": "Read cookies from the selected connector during metadata parsing.",
 			},
+			"playlist": map[string]any{
+				"type":        "boolean",
+				"description": "List the playlist of a video URL that also references one. Playlist and channel URLs are listed automatically.",
+			},
+			"playlistLimit": map[string]any{
+				"type":        "integer",
+				"description": "Maximum number of playlist entries to list. Defaults to 500.",
+			},
 		},
 		"required": []string{"url"},
 	}
@@ -328,15 +615,84 @@ func libraryManageDownloadCreateParamsSchema() map[string]any {
 				"description": "Optional preset to enqueue an automatic follow-up transcode after download.",
 			},
 			"deleteSourceFileAfterTranscode": map[string]any{"type": "boolean"},
-			"source":                         map[string]any{"type": "string"},
-			"caller":                         map[string]any{"type": "string"},
-			"sessionKey":                     map[string]any{"type": "string"},
-			"runId":                          map[string]any{"type": "string"},
+			"downloadSections": map[string]any{
+				"type":        "array",
+				"description": "Download only these time ranges instead of the whole video. Omit endMs to download to the end.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"startMs": map[string]any{"type": "integer"},
+						"endMs":   map[string]any{"type": "integer"},
+					},
+					"required": []string{"startMs"},
+				},
+			},
+			"priority": map[string]any{
+				"type":        "integer",
+				"description": "Queue priority. Higher values start first when download or transcode slots are busy.",
+			},
+			"source":     map[string]any{"type": "string"},
+			"caller":     map[string]any{"type": "string"},
+			"sessionKey": map[string]any{"type": "string"},
+			"runId":      map[string]any{"type": "string"},
 		},
 		"required": []string{"url"},
 	}
 }
 
+func libraryManageDownloadPlaylistCreateParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url":          map[string]any{"type": "string", "description": "Playlist or channel URL returned by download.parse."},
+			"libraryId":    map[string]any{"type": "string"},
+			"title":        map[string]any{"type": "string"},
+			"extractor":    map[string]any{"type": "string"},
+			"author":       map[string]any{"type": "string"},
+			"thumbnailUrl": map[string]any{"type": "string"},
+			"entries": map[string]any{
+				"type":        "array",
+				"description": "Playlist entries to download, each as its own child download operation.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"index":        map[string]any{"type": "integer", "description": "1-based position in the playlist."},
+						"url":          map[string]any{"type": "string"},
+						"title":        map[string]any{"type": "string"},
+						"author":       map[string]any{"type": "string"},
+						"thumbnailUrl": map[string]any{"type": "string"},
+					},
+					"required": []string{"index"},
+				},
+			},
+			"maxConcurrency": map[string]any{
+				"type":        "integer",
+				"description": "Number of entries downloaded at the same time. Defaults to 2, at most 6.",
+			},
+			"writeThumbnail":                 map[string]any{"type": "boolean"},
+			"connectorId":                    map[string]any{"type": "string"},
+			"useConnector":                   map[string]any{"type": "boolean"},
+			"quality":                        map[string]any{"type": "string"},
+			"formatId":                       map[string]any{"type": "string", "description": "Format selector applied to every entry."},
+			"subtitleLangs":                  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"subtitleAuto":                   map[string]any{"type": "boolean"},
+			"subtitleAll":                    map[string]any{"type": "boolean"},
+			"subtitleFormat":                 map[string]any{"type": "string"},
+			"transcodePresetId":              map[string]any{"type": "string"},
+			"deleteSourceFileAfterTranscode": map[string]any{"type": "boolean"},
+			"priority": map[string]any{
+				"type":        "integer",
+				"description": "Queue priority. Higher values start first when download or transcode slots are busy.",
+			},
+			"source":     map[string]any{"type": "string"},
+			"caller":     map[string]any{"type": "string"},
+			"sessionKey": map[string]any{"type": "string"},
+			"runId":      map[string]any{"type": "string"},
+		},
+		"required": []string{"url", "entries"},
+	}
+}
+
 func libraryManageOperationIDParamsSchema(operationDescription string) map[string]any {
 	return map[string]any{
 		"type": "object",
@@ -354,6 +710,23 @@ func libraryManageOperationIDParamsSchema(operationDescription string) map[strin
 	}
 }
 
+func libraryManageOperationPriorityParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"operationId": map[string]any{
+				"type":        "string",
+				"description": "Queued download or transcode operation to reprioritize.",
+			},
+			"priority": map[string]any{
+				"type":        "integer",
+				"description": "Higher values start first. Zero is the default priority.",
+			},
+		},
+		"required": []string{"operationId", "priority"},
+	}
+}
+
 func libraryManageTranscodeCreateParamsSchema() map[string]any {
 	return map[string]any{
 		"type": "object",
@@ -378,6 +751,10 @@ func libraryManageTranscodeCreateParamsSchema() map[string]any {
 			"scale":                   map[string]any{"type": "string"},
 			"width":                   map[string]any{"type": "integer"},
 			"height":                  map[string]any{"type": "integer"},
+			"aspectRatio":             map[string]any{"type": "string", "description": "Reframe to 16:9, 9:16, 1:1, 4:5 or 3:4."},
+			"reframeMode":             map[string]any{"type": "string", "enum": []string{"pad", "blur", "crop"}},
+			"maxBitrateKbps":          map[string]any{"type": "integer"},
+			"maxFileSizeMb":           map[string]any{"type": "integer"},
 			"subtitleHandling":        map[string]any{"type": "string"},
 			"subtitleFileId":          map[string]any{"type": "string"},
 			"secondarySubtitleFileId": map[string]any{"type": "string"},
@@ -389,7 +766,17 @@ func libraryManageTranscodeCreateParamsSchema() map[string]any {
 				"type":        "string",
 				"description": "Inline subtitle payload for embed or burn-in flows.",
 			},
+			"embedCover": map[string]any{"type": "boolean", "description": "Embed the file cover as cover art in mp4, mov and mkv outputs. Requires a cover, see thumbnails.create."},
+			"variantPresetIds": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+				"description": "Extra presets rendered from the same source in one job, such as 16:9 and 9:16 platform presets.",
+			},
 			"deleteSourceFileAfterTranscode": map[string]any{"type": "boolean"},
+			"priority": map[string]any{
+				"type":        "integer",
+				"description": "Queue priority. Higher values start first when download or transcode slots are busy.",
+			},
 		},
 		"anyOf": []any{
 			map[string]any{"required": []string{"fileId"}},
@@ -398,25 +785,112 @@ func libraryManageTranscodeCreateParamsSchema() map[string]any {
 	}
 }
 
+func libraryManageClipCreateParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":  map[string]any{"type": "string", "description": "Library video or audio file to cut from."},
+			"startMs": map[string]any{"type": "integer"},
+			"endMs":   map[string]any{"type": "integer"},
+			"title":   map[string]any{"type": "string"},
+			"reencode": map[string]any{
+				"type":        "boolean",
+				"description": "Re-encode for a frame-accurate cut. Stream copy, the default, starts at the nearest keyframe.",
+			},
+			"includeSubtitle": map[string]any{
+				"type":        "boolean",
+				"description": "Crop and re-time the subtitle linked to the source file to the clip range.",
+			},
+			"subtitleFileId": map[string]any{"type": "string", "description": "Subtitle to crop instead of the linked one."},
+			"priority":       map[string]any{"type": "integer"},
+			"source":         map[string]any{"type": "string"},
+			"sessionKey":     map[string]any{"type": "string"},
+			"runId":          map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId", "startMs", "endMs"},
+	}
+}
+
+func libraryManageChapterSplitCreateParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId": map[string]any{"type": "string", "description": "Library video or audio file with chapters. One file is written per chapter."},
+			"reencode": map[string]any{
+				"type":        "boolean",
+				"description": "Re-encode for frame-accurate chapter boundaries. Stream copy, the default, cuts at keyframes.",
+			},
+			"priority":   map[string]any{"type": "integer"},
+			"source":     map[string]any{"type": "string"},
+			"sessionKey": map[string]any{"type": "string"},
+			"runId":      map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageFileChaptersUpdateParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId": map[string]any{"type": "string"},
+			"chapters": map[string]any{
+				"type":        "array",
+				"description": "Replacement chapter list. An empty list clears the chapters. endMs defaults to the next chapter start.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"startMs": map[string]any{"type": "integer"},
+						"endMs":   map[string]any{"type": "integer"},
+						"title":   map[string]any{"type": "string"},
+					},
+					"required": []string{"startMs"},
+				},
+			},
+			"timestamps": map[string]any{
+				"type":        "string",
+				"description": "YouTube description timestamps such as \"0:00 Intro\", one per line. Used instead of chapters when set.",
+			},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageFileChaptersExportParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId": map[string]any{"type": "string"},
+			"format": map[string]any{
+				"type":        "string",
+				"enum":        []string{"youtube", "ffmetadata"},
+				"description": "youtube renders description timestamps; ffmetadata renders an ffmpeg FFMETADATA1 file.",
+			},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
 func libraryManageSubtitleTranslateParamsSchema() map[string]any {
 	return map[string]any{
 		"type": "object",
 		"properties": map[string]any{
-			"fileId":                map[string]any{"type": "string"},
-			"documentId":            map[string]any{"type": "string"},
-			"libraryId":             map[string]any{"type": "string"},
-			"rootFileId":            map[string]any{"type": "string"},
-			"assistantId":           map[string]any{"type": "string"},
-			"targetLanguage":        map[string]any{"type": "string"},
-			"outputFormat":          map[string]any{"type": "string"},
-			"mode":                  map[string]any{"type": "string"},
-			"source":                map[string]any{"type": "string"},
-			"glossaryProfileIds":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
-			"referenceTrackFileIds": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
-			"promptProfileIds":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
-			"inlinePrompt":          map[string]any{"type": "string"},
-			"sessionKey":            map[string]any{"type": "string"},
-			"runId":                 map[string]any{"type": "string"},
+			"fileId":                   map[string]any{"type": "string"},
+			"documentId":               map[string]any{"type": "string"},
+			"libraryId":                map[string]any{"type": "string"},
+			"rootFileId":               map[string]any{"type": "string"},
+			"assistantId":              map[string]any{"type": "string"},
+			"targetLanguage":           map[string]any{"type": "string"},
+			"outputFormat":             map[string]any{"type": "string"},
+			"mode":                     map[string]any{"type": "string"},
+			"source":                   map[string]any{"type": "string"},
+			"glossaryProfileIds":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"referenceTrackFileIds":    map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"promptProfileIds":         map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"inlinePrompt":             map[string]any{"type": "string"},
+			"disableTranslationMemory": map[string]any{"type": "boolean"},
+			"sessionKey":               map[string]any{"type": "string"},
+			"runId":                    map[string]any{"type": "string"},
 		},
 		"required": []string{"targetLanguage"},
 		"anyOf": []any{
@@ -464,6 +938,8 @@ func libraryManageSubtitleQAReviewParamsSchema() map[string]any {
 			"outputFormat":        map[string]any{"type": "string"},
 			"source":              map[string]any{"type": "string"},
 			"normalizeWhitespace": map[string]any{"type": "boolean"},
+			"deliveryProfileId":   map[string]any{"type": "string", "description": "Delivery profile to check against, e.g. netflix, youtube or a custom profile id. Empty uses the default profile when deliveryAutoFix is set."},
+			"deliveryAutoFix":     map[string]any{"type": "boolean", "description": "Suggest re-wrapped lines, merged or split cues and adjusted timing for delivery profile violations."},
 			"sessionKey":          map[string]any{"type": "string"},
 			"runId":               map[string]any{"type": "string"},
 		},
@@ -473,3 +949,267 @@ func libraryManageSubtitleQAReviewParamsSchema() map[string]any {
 		},
 	}
 }
+
+func libraryManageSubtitleAlignParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":           map[string]any{"type": "string"},
+			"documentId":       map[string]any{"type": "string"},
+			"libraryId":        map[string]any{"type": "string"},
+			"videoFileId":      map[string]any{"type": "string", "description": "Video or audio file to align against. Defaults to the subtitle's source media."},
+			"maxOffsetMs":      map[string]any{"type": "integer", "minimum": 0, "description": "Largest constant offset to search in either direction."},
+			"noiseThresholdDb": map[string]any{"type": "number", "maximum": 0, "description": "Level below which audio counts as silence."},
+			"minSilenceMs":     map[string]any{"type": "integer", "minimum": 0},
+			"minChangeMs":      map[string]any{"type": "integer", "minimum": 0, "description": "Smallest timing change reported as a suggestion."},
+			"source":           map[string]any{"type": "string"},
+			"sessionKey":       map[string]any{"type": "string"},
+			"runId":            map[string]any{"type": "string"},
+		},
+		"anyOf": []any{
+			map[string]any{"required": []string{"fileId"}},
+			map[string]any{"required": []string{"documentId"}},
+		},
+	}
+}
+
+func libraryManageSubtitleBilingualMergeParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"libraryId":       map[string]any{"type": "string"},
+			"primaryFileId":   map[string]any{"type": "string", "description": "Subtitle whose timing and text go on the upper line."},
+			"secondaryFileId": map[string]any{"type": "string", "description": "Subtitle paired to the primary cues by time overlap."},
+			"outputFormat":    map[string]any{"type": "string", "description": "Defaults to the primary subtitle format."},
+			"minOverlapRatio": map[string]any{"type": "number", "minimum": 0, "maximum": 1, "description": "Share of the shorter cue that must overlap to pair two cues."},
+			"unmatchedMode":   map[string]any{"type": "string", "enum": []string{"keep", "drop"}, "description": "Keep unpaired cues as single-language lines or drop them."},
+			"source":          map[string]any{"type": "string"},
+			"sessionKey":      map[string]any{"type": "string"},
+			"runId":           map[string]any{"type": "string"},
+		},
+		"required": []string{"primaryFileId", "secondaryFileId"},
+	}
+}
+
+func libraryManageSubtitleBilingualSplitParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":            map[string]any{"type": "string"},
+			"documentId":        map[string]any{"type": "string"},
+			"libraryId":         map[string]any{"type": "string"},
+			"splitMode":         map[string]any{"type": "string", "enum": []string{"auto", "script", "halves"}, "description": "Split lines by writing system, by upper/lower half, or detect automatically."},
+			"primaryLanguage":   map[string]any{"type": "string", "description": "Label for the upper-line track."},
+			"secondaryLanguage": map[string]any{"type": "string", "description": "Label for the lower-line track."},
+			"outputFormat":      map[string]any{"type": "string"},
+			"source":            map[string]any{"type": "string"},
+			"sessionKey":        map[string]any{"type": "string"},
+			"runId":             map[string]any{"type": "string"},
+		},
+		"anyOf": []any{
+			map[string]any{"required": []string{"fileId"}},
+			map[string]any{"required": []string{"documentId"}},
+		},
+	}
+}
+
+func libraryManageSubtitleGlossaryExtractParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileIds":           map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Subtitles to scan; defaults to every subtitle in libraryId."},
+			"libraryId":         map[string]any{"type": "string"},
+			"assistantId":       map[string]any{"type": "string"},
+			"sourceLanguage":    map[string]any{"type": "string"},
+			"targetLanguage":    map[string]any{"type": "string", "description": "Language the proposed translations are written in."},
+			"glossaryProfileId": map[string]any{"type": "string", "description": "Profile whose existing terms are skipped and that receives accepted terms."},
+			"minOccurrences":    map[string]any{"type": "integer", "minimum": 1},
+			"maxCandidates":     map[string]any{"type": "integer", "minimum": 1, "maximum": 200},
+			"source":            map[string]any{"type": "string"},
+			"sessionKey":        map[string]any{"type": "string"},
+			"runId":             map[string]any{"type": "string"},
+		},
+		"required": []string{"targetLanguage"},
+		"anyOf": []any{
+			map[string]any{"required": []string{"fileIds"}},
+			map[string]any{"required": []string{"libraryId"}},
+		},
+	}
+}
+
+func libraryManageSubtitlePipelineParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileIds":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Subtitles to process; defaults to every subtitle in libraryId."},
+			"libraryId": map[string]any{"type": "string"},
+			"steps": map[string]any{
+				"type":        "array",
+				"minItems":    1,
+				"description": "Steps run in order on every file. Translate fans out per target language and later steps run on each translation.",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"kind":            map[string]any{"type": "string", "enum": []string{"proofread", "translate", "qa_review", "export"}},
+						"targetLanguages": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Translate only."},
+						"autoApply":       map[string]any{"type": "boolean", "description": "Proofread and qa_review: accept all suggestions before the next step."},
+						"exportDirectory": map[string]any{"type": "string", "description": "Export only; absolute directory for the exported files."},
+						"exportFormat":    map[string]any{"type": "string", "description": "Export only; defaults to srt."},
+						"translate":       map[string]any{"type": "object", "description": "Extra subtitle.translate.create options such as assistantId or glossaryProfileIds."},
+						"proofread":       map[string]any{"type": "object", "description": "Extra subtitle.proofread.create options."},
+						"qaReview":        map[string]any{"type": "object", "description": "Extra subtitle.qa_review.create options."},
+					},
+					"required": []string{"kind"},
+				},
+			},
+			"maxConcurrency": map[string]any{"type": "integer", "minimum": 1, "maximum": 8, "description": "Child operations running at once; defaults to 2."},
+			"source":         map[string]any{"type": "string"},
+			"sessionKey":     map[string]any{"type": "string"},
+			"runId":          map[string]any{"type": "string"},
+		},
+		"required": []string{"steps"},
+		"anyOf": []any{
+			map[string]any{"required": []string{"fileIds"}},
+			map[string]any{"required": []string{"libraryId"}},
+		},
+	}
+}
+
+func libraryManageSubtitleRevisionRestoreParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"revisionId": map[string]any{"type": "string", "description": "Revision from the library subtitle_revisions listing to make the working subtitle again."},
+			"fileId":     map[string]any{"type": "string", "description": "Optional guard; the revision must belong to this subtitle file."},
+			"sessionKey": map[string]any{"type": "string"},
+			"runId":      map[string]any{"type": "string"},
+		},
+		"required": []string{"revisionId"},
+	}
+}
+
+func libraryManageSubscriptionSaveParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":                             map[string]any{"type": "string", "description": "Existing subscription to update. Omit to create one."},
+			"name":                           map[string]any{"type": "string"},
+			"url":                            map[string]any{"type": "string", "description": "Channel or playlist URL to follow."},
+			"libraryId":                      map[string]any{"type": "string", "description": "Library for new downloads. Defaults to the library of the first download."},
+			"connectorId":                    map[string]any{"type": "string"},
+			"useConnector":                   map[string]any{"type": "boolean"},
+			"quality":                        map[string]any{"type": "string"},
+			"formatId":                       map[string]any{"type": "string"},
+			"subtitleLangs":                  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"subtitleAuto":                   map[string]any{"type": "boolean"},
+			"subtitleFormat":                 map[string]any{"type": "string"},
+			"transcodePresetId":              map[string]any{"type": "string"},
+			"deleteSourceFileAfterTranscode": map[string]any{"type": "boolean"},
+			"pollIntervalMinutes":            map[string]any{"type": "integer", "description": "Minutes between checks, from 15 to 10080. Defaults to 60."},
+			"downloadExisting":               map[string]any{"type": "boolean", "description": "Download entries already listed on the first check instead of only recording them."},
+			"enabled":                        map[string]any{"type": "boolean"},
+		},
+		"required": []string{"url"},
+	}
+}
+
+func libraryManageSubscriptionIDParamsSchema(subscriptionDescription string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id": map[string]any{
+				"type":        "string",
+				"description": subscriptionDescription,
+			},
+		},
+		"required": []string{"id"},
+	}
+}
+
+func libraryManageSubtitleOCRParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":           map[string]any{"type": "string", "description": "Video file with burned-in subtitles."},
+			"libraryId":        map[string]any{"type": "string"},
+			"language":         map[string]any{"type": "string", "description": "Tesseract language codes joined with +, e.g. eng or chi_sim+eng. Defaults to eng."},
+			"sampleIntervalMs": map[string]any{"type": "integer", "minimum": 0, "description": "Milliseconds between sampled frames. Defaults to 500."},
+			"regionTop":        map[string]any{"type": "number", "minimum": 0, "maximum": 1, "description": "Top of the subtitle region as a fraction of the frame height. Defaults to 0.75."},
+			"regionHeight":     map[string]any{"type": "number", "minimum": 0, "maximum": 1, "description": "Height of the subtitle region as a fraction of the frame height. Defaults to 0.25."},
+			"outputFormat":     map[string]any{"type": "string"},
+			"threads":          map[string]any{"type": "integer", "minimum": 0},
+			"source":           map[string]any{"type": "string"},
+			"sessionKey":       map[string]any{"type": "string"},
+			"runId":            map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageThumbnailCreateParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":         map[string]any{"type": "string", "description": "Library video to take thumbnails from."},
+			"count":          map[string]any{"type": "integer", "minimum": 1, "maximum": 24, "description": "Candidate frames to extract. Defaults to 9."},
+			"sceneThreshold": map[string]any{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1, "description": "Scene change score a frame must exceed. Defaults to 0.3; lower values find more scenes."},
+			"columns":        map[string]any{"type": "integer", "minimum": 0, "description": "Contact sheet columns. Defaults to a square grid; use count for a one-row storyboard."},
+			"coverIndex":     map[string]any{"type": "integer", "minimum": 0, "description": "1-based candidate to set as the file cover. Defaults to the most detailed frame."},
+			"priority":       map[string]any{"type": "integer"},
+			"source":         map[string]any{"type": "string"},
+			"sessionKey":     map[string]any{"type": "string"},
+			"runId":          map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageFileCoverSetParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":      map[string]any{"type": "string", "description": "Video or audio file whose cover is set."},
+			"coverFileId": map[string]any{"type": "string", "description": "Thumbnail file of the same library. Empty clears the cover."},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageSubtitleExtractParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId": map[string]any{"type": "string", "description": "Library video with embedded subtitle streams, listed in media.subtitleStreams."},
+			"streamIndexes": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "integer", "minimum": 0},
+				"description": "Absolute stream indexes to extract. Defaults to every text subtitle stream; bitmap streams such as PGS are rejected.",
+			},
+			"outputFormat": map[string]any{"type": "string", "enum": []string{"srt", "vtt", "ass"}, "description": "Defaults to ass for ASS/SSA streams and srt otherwise."},
+			"priority":     map[string]any{"type": "integer"},
+			"source":       map[string]any{"type": "string"},
+			"sessionKey":   map[string]any{"type": "string"},
+			"runId":        map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId"},
+	}
+}
+
+func libraryManageSubtitleTranscribeParamsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"fileId":       map[string]any{"type": "string", "description": "Video or audio file to transcribe."},
+			"libraryId":    map[string]any{"type": "string"},
+			"language":     map[string]any{"type": "string", "description": "Spoken language code, or auto to detect."},
+			"modelPath":    map[string]any{"type": "string", "description": "Path to a whisper.cpp ggml model. Defaults to the first model next to whisper-cli."},
+			"outputFormat": map[string]any{"type": "string"},
+			"prompt":       map[string]any{"type": "string"},
+			"threads":      map[string]any{"type": "integer", "minimum": 0},
+			"source":       map[string]any{"type": "string"},
+			"sessionKey":   map[string]any{"type": "string"},
+			"runId":        map[string]any{"type": "string"},
+		},
+		"required": []string{"fileId"},
+	}
+}
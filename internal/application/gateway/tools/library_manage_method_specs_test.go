@@ -82,6 +82,10 @@ func (stub *libraryManageServiceStub) PrepareYTDLPDownload(_ context.Context, re
 	return stub.prepareResponse, nil
 }
 
+func (stub *libraryManageServiceStub) CreateYTDLPPlaylistJob(context.Context, librarydto.CreateYTDLPPlaylistJobRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
 func (stub *libraryManageServiceStub) ParseYTDLPDownload(context.Context, librarydto.ParseYTDLPDownloadRequest) (librarydto.ParseYTDLPDownloadResponse, error) {
 	return librarydto.ParseYTDLPDownloadResponse{}, nil
 }
@@ -95,6 +99,22 @@ func (stub *libraryManageServiceStub) RetryYTDLPOperation(context.Context, libra
 	return librarydto.LibraryOperationDTO{}, nil
 }
 
+func (stub *libraryManageServiceStub) CreateClipJob(context.Context, librarydto.CreateClipJobRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateChapterSplitJob(context.Context, librarydto.CreateChapterSplitJobRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) UpdateFileChapters(context.Context, librarydto.UpdateFileChaptersRequest) (librarydto.LibraryFileDTO, error) {
+	return librarydto.LibraryFileDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) ExportFileChapters(context.Context, librarydto.ExportFileChaptersRequest) (librarydto.ExportFileChaptersResult, error) {
+	return librarydto.ExportFileChaptersResult{}, nil
+}
+
 func (stub *libraryManageServiceStub) CreateTranscodeJob(context.Context, librarydto.CreateTranscodeJobRequest) (librarydto.LibraryOperationDTO, error) {
 	return librarydto.LibraryOperationDTO{}, nil
 }
@@ -111,6 +131,66 @@ func (stub *libraryManageServiceStub) CreateSubtitleQAReviewJob(context.Context,
 	return librarydto.LibraryOperationDTO{}, nil
 }
 
+func (stub *libraryManageServiceStub) CreateSubtitleTranscribeJob(context.Context, librarydto.SubtitleTranscribeRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateSubtitleOCRJob(context.Context, librarydto.SubtitleOCRRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateThumbnailJob(context.Context, librarydto.CreateThumbnailJobRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) SetFileCover(context.Context, librarydto.SetFileCoverRequest) (librarydto.LibraryFileDTO, error) {
+	return librarydto.LibraryFileDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateSubtitleExtractJob(context.Context, librarydto.CreateSubtitleExtractJobRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateSubtitleAlignJob(context.Context, librarydto.SubtitleAlignRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) MergeBilingualSubtitles(context.Context, librarydto.SubtitleBilingualMergeRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) SplitBilingualSubtitle(context.Context, librarydto.SubtitleBilingualSplitRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateSubtitleGlossaryExtractJob(context.Context, librarydto.SubtitleGlossaryExtractRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) CreateSubtitlePipelineJob(context.Context, librarydto.SubtitlePipelineRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) ListSubscriptions(context.Context) ([]librarydto.LibrarySubscriptionDTO, error) {
+	return nil, nil
+}
+
+func (stub *libraryManageServiceStub) SaveSubscription(context.Context, librarydto.LibrarySubscriptionDTO) (librarydto.LibrarySubscriptionDTO, error) {
+	return librarydto.LibrarySubscriptionDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) DeleteSubscription(context.Context, librarydto.DeleteSubscriptionRequest) error {
+	return nil
+}
+
+func (stub *libraryManageServiceStub) PollSubscription(context.Context, librarydto.PollSubscriptionRequest) (librarydto.PollSubscriptionResult, error) {
+	return librarydto.PollSubscriptionResult{}, nil
+}
+
+func (stub *libraryManageServiceStub) RestoreSubtitleRevision(context.Context, librarydto.RestoreSubtitleRevisionRequest) (librarydto.RestoreSubtitleRevisionResult, error) {
+	return librarydto.RestoreSubtitleRevisionResult{}, nil
+}
+
 func (stub *libraryManageServiceStub) CancelOperation(_ context.Context, request librarydto.CancelOperationRequest) (librarydto.LibraryOperationDTO, error) {
 	stub.cancelRequest = request
 	return stub.cancelOperationResponse, nil
@@ -120,6 +200,18 @@ func (stub *libraryManageServiceStub) ResumeOperation(context.Context, librarydt
 	return librarydto.LibraryOperationDTO{}, nil
 }
 
+func (stub *libraryManageServiceStub) SetOperationPriority(context.Context, librarydto.SetOperationPriorityRequest) (librarydto.LibraryOperationDTO, error) {
+	return librarydto.LibraryOperationDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) GetOperationQueue(context.Context) (librarydto.OperationQueueStateDTO, error) {
+	return librarydto.OperationQueueStateDTO{}, nil
+}
+
+func (stub *libraryManageServiceStub) SetOperationQueuePaused(_ context.Context, request librarydto.SetOperationQueuePausedRequest) (librarydto.OperationQueueStateDTO, error) {
+	return librarydto.OperationQueueStateDTO{Paused: request.Paused}, nil
+}
+
 func TestRunLibraryManageToolReturnsAsyncAcceptedResult(t *testing.T) {
 	t.Parallel()
 
@@ -13,13 +13,36 @@ type libraryManageService interface {
 	PrepareYTDLPDownload(ctx context.Context, request librarydto.PrepareYTDLPDownloadRequest) (librarydto.PrepareYTDLPDownloadResponse, error)
 	ParseYTDLPDownload(ctx context.Context, request librarydto.ParseYTDLPDownloadRequest) (librarydto.ParseYTDLPDownloadResponse, error)
 	CreateYTDLPJob(ctx context.Context, request librarydto.CreateYTDLPJobRequest) (librarydto.LibraryOperationDTO, error)
+	CreateYTDLPPlaylistJob(ctx context.Context, request librarydto.CreateYTDLPPlaylistJobRequest) (librarydto.LibraryOperationDTO, error)
 	RetryYTDLPOperation(ctx context.Context, request librarydto.RetryYTDLPOperationRequest) (librarydto.LibraryOperationDTO, error)
 	CreateTranscodeJob(ctx context.Context, request librarydto.CreateTranscodeJobRequest) (librarydto.LibraryOperationDTO, error)
+	CreateClipJob(ctx context.Context, request librarydto.CreateClipJobRequest) (librarydto.LibraryOperationDTO, error)
+	CreateChapterSplitJob(ctx context.Context, request librarydto.CreateChapterSplitJobRequest) (librarydto.LibraryOperationDTO, error)
+	UpdateFileChapters(ctx context.Context, request librarydto.UpdateFileChaptersRequest) (librarydto.LibraryFileDTO, error)
+	ExportFileChapters(ctx context.Context, request librarydto.ExportFileChaptersRequest) (librarydto.ExportFileChaptersResult, error)
+	CreateThumbnailJob(ctx context.Context, request librarydto.CreateThumbnailJobRequest) (librarydto.LibraryOperationDTO, error)
+	SetFileCover(ctx context.Context, request librarydto.SetFileCoverRequest) (librarydto.LibraryFileDTO, error)
 	CreateSubtitleTranslateJob(ctx context.Context, request librarydto.SubtitleTranslateRequest) (librarydto.LibraryOperationDTO, error)
 	CreateSubtitleProofreadJob(ctx context.Context, request librarydto.SubtitleProofreadRequest) (librarydto.LibraryOperationDTO, error)
 	CreateSubtitleQAReviewJob(ctx context.Context, request librarydto.SubtitleQAReviewRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitleTranscribeJob(ctx context.Context, request librarydto.SubtitleTranscribeRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitleOCRJob(ctx context.Context, request librarydto.SubtitleOCRRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitleExtractJob(ctx context.Context, request librarydto.CreateSubtitleExtractJobRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitleAlignJob(ctx context.Context, request librarydto.SubtitleAlignRequest) (librarydto.LibraryOperationDTO, error)
+	MergeBilingualSubtitles(ctx context.Context, request librarydto.SubtitleBilingualMergeRequest) (librarydto.LibraryOperationDTO, error)
+	SplitBilingualSubtitle(ctx context.Context, request librarydto.SubtitleBilingualSplitRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitleGlossaryExtractJob(ctx context.Context, request librarydto.SubtitleGlossaryExtractRequest) (librarydto.LibraryOperationDTO, error)
+	CreateSubtitlePipelineJob(ctx context.Context, request librarydto.SubtitlePipelineRequest) (librarydto.LibraryOperationDTO, error)
+	RestoreSubtitleRevision(ctx context.Context, request librarydto.RestoreSubtitleRevisionRequest) (librarydto.RestoreSubtitleRevisionResult, error)
+	ListSubscriptions(ctx context.Context) ([]librarydto.LibrarySubscriptionDTO, error)
+	SaveSubscription(ctx context.Context, request librarydto.LibrarySubscriptionDTO) (librarydto.LibrarySubscriptionDTO, error)
+	DeleteSubscription(ctx context.Context, request librarydto.DeleteSubscriptionRequest) error
+	PollSubscription(ctx context.Context, request librarydto.PollSubscriptionRequest) (librarydto.PollSubscriptionResult, error)
 	CancelOperation(ctx context.Context, request librarydto.CancelOperationRequest) (librarydto.LibraryOperationDTO, error)
 	ResumeOperation(ctx context.Context, request librarydto.ResumeOperationRequest) (librarydto.LibraryOperationDTO, error)
+	SetOperationPriority(ctx context.Context, request librarydto.SetOperationPriorityRequest) (librarydto.LibraryOperationDTO, error)
+	GetOperationQueue(ctx context.Context) (librarydto.OperationQueueStateDTO, error)
+	SetOperationQueuePaused(ctx context.Context, request librarydto.SetOperationQueuePausedRequest) (librarydto.OperationQueueStateDTO, error)
 }
 
 type libraryManageToolResult struct {
@@ -72,18 +95,66 @@ func canonicalLibraryManageAction(value string) string {
 	switch normalized {
 	case "download":
 		return "download.create"
+	case "playlist", "download_playlist":
+		return "download.playlist.create"
 	case "transcode":
 		return "transcode.create"
+	case "clip", "cut", "trim":
+		return "clip.create"
+	case "split_chapters", "chapter_split":
+		return "chapters.split.create"
+	case "chapters", "set_chapters":
+		return "file.chapters.update"
+	case "export_chapters":
+		return "file.chapters.export"
+	case "thumbnails", "generate_thumbnails", "contact_sheet", "storyboard":
+		return "thumbnails.create"
+	case "cover", "set_cover":
+		return "file.cover.set"
 	case "translate":
 		return "subtitle.translate.create"
 	case "proofread":
 		return "subtitle.proofread.create"
 	case "qa", "review", "qa_review":
 		return "subtitle.qa_review.create"
+	case "transcribe", "asr":
+		return "subtitle.transcribe.create"
+	case "ocr", "hardsub_ocr":
+		return "subtitle.ocr.create"
+	case "extract_subtitles", "extract_embedded_subtitles":
+		return "subtitle.extract.create"
+	case "align", "sync", "autosync":
+		return "subtitle.align.create"
+	case "merge_bilingual", "bilingual_merge":
+		return "subtitle.bilingual_merge.create"
+	case "split_bilingual", "bilingual_split":
+		return "subtitle.bilingual_split.create"
+	case "glossary_extract", "extract_glossary", "extract_terms":
+		return "subtitle.glossary_extract.create"
+	case "pipeline", "batch", "subtitle_pipeline":
+		return "subtitle.pipeline.create"
+	case "restore_revision", "revision_restore", "rollback":
+		return "subtitle.revision.restore"
+	case "subscriptions", "subscription_list":
+		return "subscription.list"
+	case "subscribe", "subscription_save":
+		return "subscription.save"
+	case "unsubscribe", "subscription_delete":
+		return "subscription.delete"
+	case "subscription_poll":
+		return "subscription.poll"
 	case "cancel":
 		return "operation.cancel"
 	case "resume":
 		return "operation.resume"
+	case "priority", "set_priority":
+		return "operation.priority"
+	case "queue", "queue_status":
+		return "queue.get"
+	case "pause", "pause_queue":
+		return "queue.pause"
+	case "resume_queue":
+		return "queue.resume"
 	default:
 		return normalized
 	}
@@ -129,6 +200,16 @@ func runLibraryManageAction(ctx context.Context, library libraryManageService, a
 			return "", err
 		}
 		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "download.playlist.create":
+		request := librarydto.CreateYTDLPPlaylistJobRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateYTDLPPlaylistJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
 	case "download.retry":
 		request := librarydto.RetryYTDLPOperationRequest{}
 		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
@@ -149,6 +230,66 @@ func runLibraryManageAction(ctx context.Context, library libraryManageService, a
 			return "", err
 		}
 		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "clip.create":
+		request := librarydto.CreateClipJobRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateClipJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "chapters.split.create":
+		request := librarydto.CreateChapterSplitJobRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateChapterSplitJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "file.chapters.update":
+		request := librarydto.UpdateFileChaptersRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.UpdateFileChapters(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
+	case "file.chapters.export":
+		request := librarydto.ExportFileChaptersRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.ExportFileChapters(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
+	case "thumbnails.create":
+		request := librarydto.CreateThumbnailJobRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateThumbnailJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "file.cover.set":
+		request := librarydto.SetFileCoverRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.SetFileCover(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
 	case "subtitle.translate.create":
 		request := librarydto.SubtitleTranslateRequest{}
 		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
@@ -179,6 +320,131 @@ func runLibraryManageAction(ctx context.Context, library libraryManageService, a
 			return "", err
 		}
 		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.transcribe.create":
+		request := librarydto.SubtitleTranscribeRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitleTranscribeJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.ocr.create":
+		request := librarydto.SubtitleOCRRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitleOCRJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.extract.create":
+		request := librarydto.CreateSubtitleExtractJobRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitleExtractJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.align.create":
+		request := librarydto.SubtitleAlignRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitleAlignJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.bilingual_merge.create":
+		request := librarydto.SubtitleBilingualMergeRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.MergeBilingualSubtitles(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.bilingual_split.create":
+		request := librarydto.SubtitleBilingualSplitRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.SplitBilingualSubtitle(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.glossary_extract.create":
+		request := librarydto.SubtitleGlossaryExtractRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitleGlossaryExtractJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.pipeline.create":
+		request := librarydto.SubtitlePipelineRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.CreateSubtitlePipelineJob(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "subtitle.revision.restore":
+		request := librarydto.RestoreSubtitleRevisionRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.RestoreSubtitleRevision(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
+	case "subscription.list":
+		result, err := library.ListSubscriptions(ctx)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
+	case "subscription.save":
+		request := librarydto.LibrarySubscriptionDTO{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.SaveSubscription(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
+	case "subscription.delete":
+		request := librarydto.DeleteSubscriptionRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		if err := library.DeleteSubscription(ctx, request); err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, map[string]any{"id": strings.TrimSpace(request.ID), "deleted": true}), nil
+	case "subscription.poll":
+		request := librarydto.PollSubscriptionRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		result, err := library.PollSubscription(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, result), nil
 	case "operation.cancel":
 		request := librarydto.CancelOperationRequest{}
 		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
@@ -199,6 +465,28 @@ func runLibraryManageAction(ctx context.Context, library libraryManageService, a
 			return "", err
 		}
 		return marshalLibraryManageResult(action, true, buildLibraryManageAsyncAccepted(operation)), nil
+	case "operation.priority":
+		request := librarydto.SetOperationPriorityRequest{}
+		if err := decodeGatewayActionParams(action, enriched, &request); err != nil {
+			return "", err
+		}
+		operation, err := library.SetOperationPriority(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, operation), nil
+	case "queue.get":
+		state, err := library.GetOperationQueue(ctx)
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, state), nil
+	case "queue.pause", "queue.resume":
+		state, err := library.SetOperationQueuePaused(ctx, librarydto.SetOperationQueuePausedRequest{Paused: action == "queue.pause"})
+		if err != nil {
+			return "", err
+		}
+		return marshalLibraryManageResult(action, false, state), nil
 	default:
 		return "", fmt.Errorf("unsupported library_manage action: %s", action)
 	}
@@ -40,6 +40,16 @@ var libraryMethodDefinitions = []libraryMethodDefinition{
 		inputType:  reflect.TypeOf(librarydto.GetOperationRequest{}),
 		outputType: reflect.TypeOf(librarydto.LibraryOperationDTO{}),
 	},
+	{
+		name:       "subtitle_revisions",
+		inputType:  reflect.TypeOf(librarydto.ListSubtitleRevisionsRequest{}),
+		outputType: reflect.TypeOf([]librarydto.SubtitleRevisionDTO{}),
+	},
+	{
+		name:       "subtitle_revision_diff",
+		inputType:  reflect.TypeOf(librarydto.DiffSubtitleRevisionsRequest{}),
+		outputType: reflect.TypeOf(librarydto.SubtitleRevisionDiffResult{}),
+	},
 }
 
 func libraryMethodSpecs() []tooldto.ToolMethodSpec {
@@ -0,0 +1,827 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dreamcreator/internal/domain/assistant"
+	"dreamcreator/internal/infrastructure/processutil"
+)
+
+const (
+	mcpProtocolVersion      = "2025-06-18"
+	mcpClientName           = "dreamcreator"
+	mcpSessionIDHeader      = "Mcp-Session-Id"
+	mcpProtocolHeader       = "MCP-Protocol-Version"
+	mcpMaxListPages         = 20
+	mcpMaxStderrTailBytes   = 4096
+	mcpStdioShutdownTimeout = 2 * time.Second
+	mcpHTTPCloseTimeout     = 5 * time.Second
+)
+
+var errMCPTransportClosed = errors.New("mcp transport closed")
+
+type mcpRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type mcpMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *mcpRPCError    `json:"error,omitempty"`
+}
+
+type mcpRPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (err *mcpRPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", err.Code, err.Message)
+}
+
+func (message mcpMessage) isResponse() bool {
+	return message.Method == "" && len(message.ID) > 0
+}
+
+func (message mcpMessage) responseID() (int64, bool) {
+	id, err := strconv.ParseInt(strings.Trim(string(message.ID), `"`), 10, 64)
+	return id, err == nil
+}
+
+type mcpImplementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type mcpServerCapabilities struct {
+	Tools     json.RawMessage `json:"tools,omitempty"`
+	Resources json.RawMessage `json:"resources,omitempty"`
+	Prompts   json.RawMessage `json:"prompts,omitempty"`
+}
+
+type mcpInitializeResult struct {
+	ProtocolVersion string                `json:"protocolVersion"`
+	Capabilities    mcpServerCapabilities `json:"capabilities"`
+	ServerInfo      mcpImplementation     `json:"serverInfo"`
+	Instructions    string                `json:"instructions,omitempty"`
+}
+
+type mcpToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+type mcpTool struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	InputSchema json.RawMessage     `json:"inputSchema,omitempty"`
+	Annotations *mcpToolAnnotations `json:"annotations,omitempty"`
+}
+
+type mcpResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type mcpPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type mcpPrompt struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []mcpPromptArgument `json:"arguments,omitempty"`
+}
+
+type mcpContent struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Data     string          `json:"data,omitempty"`
+	URI      string          `json:"uri,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+type mcpCallToolResult struct {
+	Content           []mcpContent    `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+// mcpTransport carries JSON-RPC messages to a server. roundTrip waits for the
+// response matching request.ID, notify sends a message without an id.
+type mcpTransport interface {
+	roundTrip(ctx context.Context, request mcpRequest) (mcpMessage, error)
+	notify(ctx context.Context, request mcpRequest) error
+	setProtocolVersion(version string)
+	alive() bool
+	close() error
+}
+
+type mcpClient struct {
+	transport    mcpTransport
+	nextID       atomic.Int64
+	serverInfo   mcpImplementation
+	capabilities mcpServerCapabilities
+	instructions string
+}
+
+func newMCPClient(transport mcpTransport) *mcpClient {
+	return &mcpClient{transport: transport}
+}
+
+func dialMCPServer(ctx context.Context, server assistant.AssistantMCPServer) (*mcpClient, error) {
+	var transport mcpTransport
+	switch server.Transport {
+	case assistant.MCPTransportHTTP:
+		transport = newMCPHTTPTransport(server.URL, server.Headers, nil)
+	default:
+		stdio, err := startMCPStdioTransport(server)
+		if err != nil {
+			return nil, err
+		}
+		transport = stdio
+	}
+	client := newMCPClient(transport)
+	if err := client.initialize(ctx); err != nil {
+		_ = client.close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (client *mcpClient) call(ctx context.Context, method string, params any, result any) error {
+	if client == nil || client.transport == nil {
+		return errMCPTransportClosed
+	}
+	id := client.nextID.Add(1)
+	response, err := client.transport.roundTrip(ctx, mcpRequest{
+		JSONRPC: "2.0",
+		ID:      &id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if result == nil || len(response.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (client *mcpClient) initialize(ctx context.Context) error {
+	var result mcpInitializeResult
+	err := client.call(ctx, "initialize", map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      mcpImplementation{Name: mcpClientName},
+	}, &result)
+	if err != nil {
+		return err
+	}
+	version := strings.TrimSpace(result.ProtocolVersion)
+	if version == "" {
+		version = mcpProtocolVersion
+	}
+	client.transport.setProtocolVersion(version)
+	client.serverInfo = result.ServerInfo
+	client.capabilities = result.Capabilities
+	client.instructions = strings.TrimSpace(result.Instructions)
+	return client.transport.notify(ctx, mcpRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+func (client *mcpClient) listTools(ctx context.Context) ([]mcpTool, error) {
+	if len(client.capabilities.Tools) == 0 {
+		return nil, nil
+	}
+	var items []mcpTool
+	err := client.paginate(ctx, "tools/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Tools      []mcpTool `json:"tools"`
+			NextCursor string    `json:"nextCursor,omitempty"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		items = append(items, page.Tools...)
+		return page.NextCursor, nil
+	})
+	return items, err
+}
+
+func (client *mcpClient) listResources(ctx context.Context) ([]mcpResource, error) {
+	if len(client.capabilities.Resources) == 0 {
+		return nil, nil
+	}
+	var items []mcpResource
+	err := client.paginate(ctx, "resources/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Resources  []mcpResource `json:"resources"`
+			NextCursor string        `json:"nextCursor,omitempty"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		items = append(items, page.Resources...)
+		return page.NextCursor, nil
+	})
+	return items, err
+}
+
+func (client *mcpClient) listPrompts(ctx context.Context) ([]mcpPrompt, error) {
+	if len(client.capabilities.Prompts) == 0 {
+		return nil, nil
+	}
+	var items []mcpPrompt
+	err := client.paginate(ctx, "prompts/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Prompts    []mcpPrompt `json:"prompts"`
+			NextCursor string      `json:"nextCursor,omitempty"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return "", err
+		}
+		items = append(items, page.Prompts...)
+		return page.NextCursor, nil
+	})
+	return items, err
+}
+
+func (client *mcpClient) paginate(ctx context.Context, method string, collect func(raw json.RawMessage) (string, error)) error {
+	cursor := ""
+	for page := 0; page < mcpMaxListPages; page++ {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		var raw json.RawMessage
+		if err := client.call(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		next, err := collect(raw)
+		if err != nil {
+			return fmt.Errorf("decode %s result: %w", method, err)
+		}
+		if next == "" || next == cursor {
+			return nil
+		}
+		cursor = next
+	}
+	return nil
+}
+
+func (client *mcpClient) callTool(ctx context.Context, name string, arguments map[string]any) (mcpCallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	var result mcpCallToolResult
+	err := client.call(ctx, "tools/call", map[string]any{
+		"name":      name,
+		"arguments": arguments,
+	}, &result)
+	return result, err
+}
+
+func (client *mcpClient) readResource(ctx context.Context, uri string) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := client.call(ctx, "resources/read", map[string]any{"uri": uri}, &result)
+	return result, err
+}
+
+func (client *mcpClient) getPrompt(ctx context.Context, name string, arguments map[string]string) (json.RawMessage, error) {
+	params := map[string]any{"name": name}
+	if len(arguments) > 0 {
+		params["arguments"] = arguments
+	}
+	var result json.RawMessage
+	err := client.call(ctx, "prompts/get", params, &result)
+	return result, err
+}
+
+func (client *mcpClient) alive() bool {
+	return client != nil && client.transport != nil && client.transport.alive()
+}
+
+func (client *mcpClient) close() error {
+	if client == nil || client.transport == nil {
+		return nil
+	}
+	return client.transport.close()
+}
+
+// mcpStreamTransport speaks newline-delimited JSON-RPC over a byte stream,
+// which is how the stdio transport frames messages.
+type mcpStreamTransport struct {
+	writer   io.WriteCloser
+	shutdown func() error
+	stderr   *mcpTailBuffer
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[int64]chan mcpMessage
+	done    chan struct{}
+	err     error
+	closed  bool
+}
+
+func newMCPStreamTransport(reader io.Reader, writer io.WriteCloser, shutdown func() error, stderr *mcpTailBuffer) *mcpStreamTransport {
+	transport := &mcpStreamTransport{
+		writer:   writer,
+		shutdown: shutdown,
+		stderr:   stderr,
+		pending:  make(map[int64]chan mcpMessage),
+		done:     make(chan struct{}),
+	}
+	go transport.readLoop(reader)
+	return transport
+}
+
+func startMCPStdioTransport(server assistant.AssistantMCPServer) (*mcpStreamTransport, error) {
+	cmd := exec.Command(server.Command, server.Args...)
+	processutil.ConfigureCLI(cmd)
+	if server.WorkingDir != "" {
+		cmd.Dir = server.WorkingDir
+	}
+	if len(server.Env) > 0 {
+		env := append([]string{}, cmd.Environ()...)
+		for key, value := range server.Env {
+			env = append(env, key+"="+value)
+		}
+		cmd.Env = env
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	// A plain pipe instead of StdoutPipe so the reader can drain the stream
+	// while a separate goroutine waits for the process.
+	stdout, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = stdoutWriter
+	stderr := &mcpTailBuffer{limit: mcpMaxStderrTailBytes}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		_ = stdout.Close()
+		_ = stdoutWriter.Close()
+		return nil, fmt.Errorf("start mcp server %q: %w", server.Command, err)
+	}
+	_ = stdoutWriter.Close()
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	shutdown := func() error {
+		select {
+		case <-exited:
+			return nil
+		case <-time.After(mcpStdioShutdownTimeout):
+		}
+		if cmd.Process != nil {
+			_ = cmd.Process.Kill()
+		}
+		<-exited
+		return nil
+	}
+	return newMCPStreamTransport(stdout, stdin, shutdown, stderr), nil
+}
+
+func (transport *mcpStreamTransport) readLoop(reader io.Reader) {
+	buffered := bufio.NewReader(reader)
+	for {
+		line, err := buffered.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var message mcpMessage
+			if decodeErr := json.Unmarshal(trimmed, &message); decodeErr == nil {
+				transport.dispatch(message)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errMCPTransportClosed
+			}
+			transport.fail(err)
+			return
+		}
+	}
+}
+
+func (transport *mcpStreamTransport) dispatch(message mcpMessage) {
+	if message.isResponse() {
+		id, ok := message.responseID()
+		if !ok {
+			return
+		}
+		transport.mu.Lock()
+		ch := transport.pending[id]
+		delete(transport.pending, id)
+		transport.mu.Unlock()
+		if ch != nil {
+			ch <- message
+		}
+		return
+	}
+	if message.Method == "" || len(message.ID) == 0 {
+		// Server notifications (progress, list_changed, logging) are not consumed yet.
+		return
+	}
+	reply := map[string]any{"jsonrpc": "2.0", "id": message.ID}
+	if message.Method == "ping" {
+		reply["result"] = map[string]any{}
+	} else {
+		reply["error"] = mcpRPCError{Code: -32601, Message: "method not supported by client"}
+	}
+	_ = transport.write(reply)
+}
+
+func (transport *mcpStreamTransport) fail(err error) {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if transport.err != nil {
+		return
+	}
+	if tail := transport.stderr.String(); tail != "" && !transport.closed {
+		err = fmt.Errorf("%w: %s", err, tail)
+	}
+	transport.err = err
+	for id, ch := range transport.pending {
+		close(ch)
+		delete(transport.pending, id)
+	}
+	close(transport.done)
+}
+
+func (transport *mcpStreamTransport) failure() error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if transport.err != nil {
+		return transport.err
+	}
+	return errMCPTransportClosed
+}
+
+func (transport *mcpStreamTransport) write(value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	transport.writeMu.Lock()
+	defer transport.writeMu.Unlock()
+	_, err = transport.writer.Write(data)
+	return err
+}
+
+func (transport *mcpStreamTransport) roundTrip(ctx context.Context, request mcpRequest) (mcpMessage, error) {
+	if request.ID == nil {
+		return mcpMessage{}, errors.New("mcp request id is required")
+	}
+	ch := make(chan mcpMessage, 1)
+	transport.mu.Lock()
+	if transport.err != nil {
+		err := transport.err
+		transport.mu.Unlock()
+		return mcpMessage{}, err
+	}
+	transport.pending[*request.ID] = ch
+	transport.mu.Unlock()
+	if err := transport.write(request); err != nil {
+		transport.mu.Lock()
+		delete(transport.pending, *request.ID)
+		transport.mu.Unlock()
+		return mcpMessage{}, err
+	}
+	select {
+	case message, ok := <-ch:
+		if !ok {
+			return mcpMessage{}, transport.failure()
+		}
+		return message, nil
+	case <-ctx.Done():
+		transport.mu.Lock()
+		delete(transport.pending, *request.ID)
+		transport.mu.Unlock()
+		_ = transport.write(mcpRequest{
+			JSONRPC: "2.0",
+			Method:  "notifications/cancelled",
+			Params:  map[string]any{"requestId": *request.ID, "reason": ctx.Err().Error()},
+		})
+		return mcpMessage{}, ctx.Err()
+	}
+}
+
+func (transport *mcpStreamTransport) notify(_ context.Context, request mcpRequest) error {
+	select {
+	case <-transport.done:
+		return transport.failure()
+	default:
+	}
+	return transport.write(request)
+}
+
+func (transport *mcpStreamTransport) setProtocolVersion(string) {}
+
+func (transport *mcpStreamTransport) alive() bool {
+	select {
+	case <-transport.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (transport *mcpStreamTransport) close() error {
+	transport.mu.Lock()
+	if transport.closed {
+		transport.mu.Unlock()
+		return nil
+	}
+	transport.closed = true
+	transport.mu.Unlock()
+	err := transport.writer.Close()
+	if transport.shutdown != nil {
+		if shutdownErr := transport.shutdown(); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
+
+// mcpHTTPTransport implements the streamable HTTP transport: every message is
+// POSTed to the endpoint and the reply arrives as JSON or as an SSE stream.
+type mcpHTTPTransport struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+	closed          bool
+}
+
+func newMCPHTTPTransport(url string, headers map[string]string, client *http.Client) *mcpHTTPTransport {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &mcpHTTPTransport{client: client, url: url, headers: headers}
+}
+
+func (transport *mcpHTTPTransport) roundTrip(ctx context.Context, request mcpRequest) (mcpMessage, error) {
+	if request.ID == nil {
+		return mcpMessage{}, errors.New("mcp request id is required")
+	}
+	resp, err := transport.post(ctx, request)
+	if err != nil {
+		return mcpMessage{}, err
+	}
+	defer resp.Body.Close()
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return readMCPEventStream(resp.Body, *request.ID)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return mcpMessage{}, err
+	}
+	return decodeMCPResponse(data, *request.ID)
+}
+
+func (transport *mcpHTTPTransport) notify(ctx context.Context, request mcpRequest) error {
+	resp, err := transport.post(ctx, request)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+func (transport *mcpHTTPTransport) post(ctx context.Context, request mcpRequest) (*http.Response, error) {
+	transport.mu.Lock()
+	closed := transport.closed
+	sessionID := transport.sessionID
+	protocolVersion := transport.protocolVersion
+	transport.mu.Unlock()
+	if closed {
+		return nil, errMCPTransportClosed
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, transport.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range transport.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(mcpSessionIDHeader, sessionID)
+	}
+	if protocolVersion != "" {
+		req.Header.Set(mcpProtocolHeader, protocolVersion)
+	}
+	resp, err := transport.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if id := strings.TrimSpace(resp.Header.Get(mcpSessionIDHeader)); id != "" {
+		transport.mu.Lock()
+		transport.sessionID = id
+		transport.mu.Unlock()
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound && sessionID != "" {
+			// The server dropped the session; the manager reconnects on the next mount.
+			transport.mu.Lock()
+			transport.closed = true
+			transport.mu.Unlock()
+			return nil, errors.New("mcp session expired")
+		}
+		message := strings.TrimSpace(string(snippet))
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("mcp http status %d: %s", resp.StatusCode, message)
+	}
+	return resp, nil
+}
+
+func (transport *mcpHTTPTransport) setProtocolVersion(version string) {
+	transport.mu.Lock()
+	transport.protocolVersion = version
+	transport.mu.Unlock()
+}
+
+func (transport *mcpHTTPTransport) alive() bool {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	return !transport.closed
+}
+
+func (transport *mcpHTTPTransport) close() error {
+	transport.mu.Lock()
+	if transport.closed {
+		transport.mu.Unlock()
+		return nil
+	}
+	transport.closed = true
+	sessionID := transport.sessionID
+	transport.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mcpHTTPCloseTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, transport.url, nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range transport.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set(mcpSessionIDHeader, sessionID)
+	resp, err := transport.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func decodeMCPResponse(data []byte, id int64) (mcpMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return mcpMessage{}, errors.New("mcp server returned an empty response")
+	}
+	if trimmed[0] == '[' {
+		var batch []mcpMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return mcpMessage{}, err
+		}
+		for _, message := range batch {
+			if responseID, ok := message.responseID(); ok && message.isResponse() && responseID == id {
+				return message, nil
+			}
+		}
+		return mcpMessage{}, errors.New("mcp response missing from batch")
+	}
+	var message mcpMessage
+	if err := json.Unmarshal(trimmed, &message); err != nil {
+		return mcpMessage{}, err
+	}
+	return message, nil
+}
+
+func readMCPEventStream(reader io.Reader, id int64) (mcpMessage, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var data strings.Builder
+	flush := func() (mcpMessage, bool) {
+		payload := data.String()
+		data.Reset()
+		if strings.TrimSpace(payload) == "" {
+			return mcpMessage{}, false
+		}
+		var message mcpMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			return mcpMessage{}, false
+		}
+		responseID, ok := message.responseID()
+		return message, ok && message.isResponse() && responseID == id
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if message, ok := flush(); ok {
+				return message, nil
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+	if message, ok := flush(); ok {
+		return message, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return mcpMessage{}, err
+	}
+	return mcpMessage{}, errors.New("mcp event stream ended without a response")
+}
+
+// mcpTailBuffer keeps the last bytes a stdio server wrote to stderr so that
+// startup failures can be reported with the server's own message.
+type mcpTailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (buffer *mcpTailBuffer) Write(p []byte) (int, error) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	buffer.data = append(buffer.data, p...)
+	if overflow := len(buffer.data) - buffer.limit; buffer.limit > 0 && overflow > 0 {
+		buffer.data = append([]byte(nil), buffer.data[overflow:]...)
+	}
+	return len(p), nil
+}
+
+func (buffer *mcpTailBuffer) String() string {
+	if buffer == nil {
+		return ""
+	}
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	return strings.TrimSpace(string(buffer.data))
+}
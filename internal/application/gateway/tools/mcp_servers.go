@@ -0,0 +1,510 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	tooldto "dreamcreator/internal/application/tools/dto"
+	toolservice "dreamcreator/internal/application/tools/service"
+	"dreamcreator/internal/domain/assistant"
+)
+
+const (
+	MCPToolKind     = "mcp"
+	mcpToolPrefix   = "mcp__"
+	mcpToolSep      = "__"
+	mcpMaxToolName  = 64
+	mcpNameHashLen  = 8
+	mcpMaxListItems = 50
+
+	mcpReadResourceTool = "read_resource"
+	mcpGetPromptTool    = "get_prompt"
+
+	defaultMCPTimeout    = 30 * time.Second
+	mcpReconnectInterval = 30 * time.Second
+
+	MCPServerStatusReady      = "ready"
+	MCPServerStatusError      = "error"
+	MCPServerStatusConnecting = "connecting"
+)
+
+var errMCPServerNotMounted = errors.New("mcp server not mounted")
+
+// MCPServerHealth is the connection state of a mounted MCP server as reported
+// by external_tools_query.
+type MCPServerHealth struct {
+	ID            string     `json:"id"`
+	Transport     string     `json:"transport"`
+	Target        string     `json:"target,omitempty"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	ServerName    string     `json:"serverName,omitempty"`
+	ServerVersion string     `json:"serverVersion,omitempty"`
+	Tools         []string   `json:"tools,omitempty"`
+	Resources     int        `json:"resources"`
+	Prompts       int        `json:"prompts"`
+	Assistants    []string   `json:"assistants,omitempty"`
+	ConnectedAt   *time.Time `json:"connectedAt,omitempty"`
+	CheckedAt     *time.Time `json:"checkedAt,omitempty"`
+}
+
+// MCPManager mounts the MCP servers configured on assistants and exposes
+// their tools through the gateway tool registry. Server ids share one
+// namespace, so assistants that configure the same id share a connection; a
+// different configuration for an id another assistant has mounted is
+// rejected rather than replacing the shared server.
+type MCPManager struct {
+	tools    *toolservice.ToolService
+	executor *RegistryExecutor
+	dial     func(ctx context.Context, server assistant.AssistantMCPServer) (*mcpClient, error)
+	now      func() time.Time
+
+	mu      sync.Mutex
+	servers map[string]*mcpServerState
+}
+
+type mcpServerState struct {
+	config      assistant.AssistantMCPServer
+	fingerprint string
+	owners      map[string]struct{}
+
+	// connectMu serializes connection attempts; mu guards the fields below
+	// and is only held for short updates.
+	connectMu   sync.Mutex
+	mu          sync.Mutex
+	unmounted   bool
+	client      *mcpClient
+	toolNames   []string
+	resources   []mcpResource
+	prompts     []mcpPrompt
+	status      string
+	lastError   string
+	connectedAt time.Time
+	checkedAt   time.Time
+}
+
+func NewMCPManager(tools *toolservice.ToolService, executor *RegistryExecutor) *MCPManager {
+	return &MCPManager{
+		tools:    tools,
+		executor: executor,
+		dial:     dialMCPServer,
+		now:      time.Now,
+		servers:  make(map[string]*mcpServerState),
+	}
+}
+
+// MountAssistantServers reconciles the servers mounted for an assistant with
+// its configuration and connects any server that is not ready yet. Connection
+// failures are recorded as server health instead of failing the run. It
+// returns the ids of servers that were not mounted because another assistant
+// already mounted the id with a different configuration.
+func (manager *MCPManager) MountAssistantServers(ctx context.Context, assistantID string, servers []assistant.AssistantMCPServer) []string {
+	if manager == nil {
+		return nil
+	}
+	assistantID = strings.TrimSpace(assistantID)
+	desired := make(map[string]assistant.AssistantMCPServer, len(servers))
+	for _, server := range servers {
+		id := assistant.NormalizeMCPServerID(server.ID)
+		if id == "" || !server.Enabled {
+			continue
+		}
+		server.ID = id
+		desired[id] = server
+	}
+	mounted := make([]*mcpServerState, 0, len(desired))
+	rejected := make([]string, 0)
+	manager.mu.Lock()
+	for id, state := range manager.servers {
+		if _, ok := desired[id]; ok {
+			continue
+		}
+		delete(state.owners, assistantID)
+		if len(state.owners) == 0 {
+			manager.unmountLocked(id, state)
+		}
+	}
+	for id, server := range desired {
+		fingerprint := mcpServerFingerprint(server)
+		state := manager.servers[id]
+		if state != nil && state.fingerprint != fingerprint {
+			if state.sharedWith(assistantID) {
+				// Calls are routed by server id only, so remounting would send
+				// the other assistants' calls to this configuration.
+				zap.L().Warn("mcp server id already mounted with a different configuration",
+					zap.String("serverID", id),
+					zap.String("assistantID", assistantID),
+				)
+				delete(state.owners, assistantID)
+				rejected = append(rejected, id)
+				continue
+			}
+			manager.unmountLocked(id, state)
+			state = nil
+		}
+		if state == nil {
+			state = &mcpServerState{
+				config:      server,
+				fingerprint: fingerprint,
+				owners:      make(map[string]struct{}),
+				status:      MCPServerStatusConnecting,
+			}
+			manager.servers[id] = state
+		}
+		state.owners[assistantID] = struct{}{}
+		mounted = append(mounted, state)
+	}
+	manager.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, state := range mounted {
+		wg.Add(1)
+		go func(state *mcpServerState) {
+			defer wg.Done()
+			manager.ensureConnected(ctx, state)
+		}(state)
+	}
+	wg.Wait()
+	sort.Strings(rejected)
+	return rejected
+}
+
+// sharedWith reports whether any assistant other than assistantID has
+// mounted the server. Callers hold the manager lock.
+func (state *mcpServerState) sharedWith(assistantID string) bool {
+	for owner := range state.owners {
+		if owner != assistantID {
+			return true
+		}
+	}
+	return false
+}
+
+// Health returns the state of every mounted server, sorted by id.
+func (manager *MCPManager) Health() []MCPServerHealth {
+	if manager == nil {
+		return nil
+	}
+	manager.mu.Lock()
+	states := make([]*mcpServerState, 0, len(manager.servers))
+	owners := make(map[*mcpServerState][]string, len(manager.servers))
+	for _, state := range manager.servers {
+		states = append(states, state)
+		ids := make([]string, 0, len(state.owners))
+		for id := range state.owners {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		owners[state] = ids
+	}
+	manager.mu.Unlock()
+	result := make([]MCPServerHealth, 0, len(states))
+	for _, state := range states {
+		health := state.health()
+		health.Assistants = owners[state]
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Close disconnects every mounted server.
+func (manager *MCPManager) Close() {
+	if manager == nil {
+		return
+	}
+	manager.mu.Lock()
+	for id, state := range manager.servers {
+		manager.unmountLocked(id, state)
+	}
+	manager.mu.Unlock()
+}
+
+func (manager *MCPManager) unmountLocked(id string, state *mcpServerState) {
+	delete(manager.servers, id)
+	state.mu.Lock()
+	state.unmounted = true
+	names := state.toolNames
+	client := state.client
+	state.toolNames = nil
+	state.client = nil
+	state.mu.Unlock()
+	for _, name := range names {
+		manager.tools.UnregisterTool(context.Background(), tooldto.UnregisterToolRequest{ID: name})
+		manager.executor.Unregister(name)
+	}
+	if client != nil {
+		go func() { _ = client.close() }()
+	}
+}
+
+func (manager *MCPManager) ensureConnected(ctx context.Context, state *mcpServerState) {
+	state.connectMu.Lock()
+	defer state.connectMu.Unlock()
+	state.mu.Lock()
+	if state.unmounted || state.client.alive() {
+		state.mu.Unlock()
+		return
+	}
+	if state.status == MCPServerStatusError && manager.now().Sub(state.checkedAt) < mcpReconnectInterval {
+		state.mu.Unlock()
+		return
+	}
+	stale := state.client
+	state.client = nil
+	config := state.config
+	state.mu.Unlock()
+	if stale != nil {
+		go func() { _ = stale.close() }()
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, mcpServerTimeout(config))
+	defer cancel()
+	discovery, err := manager.connect(connectCtx, config)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.unmounted {
+		if discovery.client != nil {
+			go func() { _ = discovery.client.close() }()
+		}
+		return
+	}
+	state.checkedAt = manager.now()
+	if err != nil {
+		state.status = MCPServerStatusError
+		state.lastError = err.Error()
+		zap.L().Warn("mcp server unavailable", zap.String("serverID", config.ID), zap.Error(err))
+		return
+	}
+	state.client = discovery.client
+	state.resources = discovery.resources
+	state.prompts = discovery.prompts
+	state.status = MCPServerStatusReady
+	state.lastError = ""
+	state.connectedAt = state.checkedAt
+	manager.registerServerTools(ctx, state, discovery.tools)
+}
+
+type mcpServerDiscovery struct {
+	client    *mcpClient
+	tools     []mcpTool
+	resources []mcpResource
+	prompts   []mcpPrompt
+}
+
+func (manager *MCPManager) connect(ctx context.Context, server assistant.AssistantMCPServer) (mcpServerDiscovery, error) {
+	client, err := manager.dial(ctx, server)
+	if err != nil {
+		return mcpServerDiscovery{}, err
+	}
+	tools, err := client.listTools(ctx)
+	if err != nil {
+		_ = client.close()
+		return mcpServerDiscovery{}, fmt.Errorf("list tools: %w", err)
+	}
+	// Resources and prompts are optional extras; a server that fails to list
+	// them still mounts its tools.
+	resources, err := client.listResources(ctx)
+	if err != nil {
+		zap.L().Debug("mcp resources unavailable", zap.String("serverID", server.ID), zap.Error(err))
+	}
+	prompts, err := client.listPrompts(ctx)
+	if err != nil {
+		zap.L().Debug("mcp prompts unavailable", zap.String("serverID", server.ID), zap.Error(err))
+	}
+	return mcpServerDiscovery{
+		client:    client,
+		tools:     tools,
+		resources: resources,
+		prompts:   prompts,
+	}, nil
+}
+
+func (manager *MCPManager) registerServerTools(ctx context.Context, state *mcpServerState, tools []mcpTool) {
+	serverID := state.config.ID
+	specs := make([]toolSpec, 0, len(tools)+2)
+	handlers := make(map[string]func(ctx context.Context, args string) (string, error), len(tools)+2)
+	for _, tool := range tools {
+		name := MCPToolName(serverID, tool.Name)
+		if name == "" {
+			continue
+		}
+		if _, ok := handlers[name]; ok {
+			// Distinct tool names can sanitize to the same function name.
+			name = mcpDisambiguatedName(name, tool.Name)
+			if _, ok := handlers[name]; ok {
+				continue
+			}
+		}
+		specs = append(specs, buildMCPToolSpec(state.config, name, tool))
+		handlers[name] = manager.runMCPTool(serverID, tool.Name)
+	}
+	// Resources and prompts are exposed through one helper tool each, unless
+	// the server already has a tool by that name.
+	if len(state.resources) > 0 {
+		spec := buildMCPReadResourceSpec(state.config, state.resources)
+		if _, ok := handlers[spec.Name]; !ok {
+			specs = append(specs, spec)
+			handlers[spec.Name] = manager.runMCPReadResource(serverID)
+		}
+	}
+	if len(state.prompts) > 0 {
+		spec := buildMCPGetPromptSpec(state.config, state.prompts)
+		if _, ok := handlers[spec.Name]; !ok {
+			specs = append(specs, spec)
+			handlers[spec.Name] = manager.runMCPGetPrompt(serverID)
+		}
+	}
+	current := make(map[string]struct{}, len(specs))
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		registerTool(ctx, manager.tools, manager.executor, spec, handlers[spec.Name])
+		current[spec.Name] = struct{}{}
+		names = append(names, spec.Name)
+	}
+	for _, name := range state.toolNames {
+		if _, ok := current[name]; ok {
+			continue
+		}
+		manager.tools.UnregisterTool(ctx, tooldto.UnregisterToolRequest{ID: name})
+		manager.executor.Unregister(name)
+	}
+	sort.Strings(names)
+	state.toolNames = names
+}
+
+// server returns a connected client for the server id, reconnecting when the
+// previous connection dropped.
+func (manager *MCPManager) server(ctx context.Context, serverID string) (*mcpServerState, *mcpClient, error) {
+	manager.mu.Lock()
+	state := manager.servers[serverID]
+	manager.mu.Unlock()
+	if state == nil {
+		return nil, nil, errMCPServerNotMounted
+	}
+	manager.ensureConnected(ctx, state)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.client.alive() {
+		reason := state.lastError
+		if reason == "" {
+			reason = "mcp server disconnected"
+		}
+		return state, nil, errors.New(reason)
+	}
+	return state, state.client, nil
+}
+
+func (state *mcpServerState) health() MCPServerHealth {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	health := MCPServerHealth{
+		ID:        state.config.ID,
+		Transport: state.config.Transport,
+		Target:    mcpServerTarget(state.config),
+		Status:    state.status,
+		Error:     state.lastError,
+		Tools:     append([]string(nil), state.toolNames...),
+		Resources: len(state.resources),
+		Prompts:   len(state.prompts),
+	}
+	if state.status == MCPServerStatusReady && !state.client.alive() {
+		health.Status = MCPServerStatusError
+		health.Error = "mcp server disconnected"
+	}
+	if state.client != nil {
+		health.ServerName = state.client.serverInfo.Name
+		health.ServerVersion = state.client.serverInfo.Version
+	}
+	if !state.connectedAt.IsZero() {
+		connectedAt := state.connectedAt
+		health.ConnectedAt = &connectedAt
+	}
+	if !state.checkedAt.IsZero() {
+		checkedAt := state.checkedAt
+		health.CheckedAt = &checkedAt
+	}
+	return health
+}
+
+func mcpServerTimeout(server assistant.AssistantMCPServer) time.Duration {
+	if server.TimeoutSeconds > 0 {
+		return time.Duration(server.TimeoutSeconds) * time.Second
+	}
+	return defaultMCPTimeout
+}
+
+func mcpServerTarget(server assistant.AssistantMCPServer) string {
+	if server.Transport == assistant.MCPTransportHTTP {
+		return server.URL
+	}
+	return strings.TrimSpace(strings.Join(append([]string{server.Command}, server.Args...), " "))
+}
+
+func mcpServerFingerprint(server assistant.AssistantMCPServer) string {
+	data, _ := json.Marshal(server)
+	return string(data)
+}
+
+// MCPToolName namespaces an MCP tool as mcp__<server>__<tool>, keeping to the
+// characters and length model providers accept for function names. Names that
+// are too long are truncated and suffixed with a short hash of the full name
+// so tools sharing a long prefix stay distinct.
+func MCPToolName(serverID string, toolName string) string {
+	serverID = assistant.NormalizeMCPServerID(serverID)
+	var builder strings.Builder
+	for _, r := range strings.TrimSpace(toolName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	tool := strings.Trim(builder.String(), "_")
+	if serverID == "" || tool == "" {
+		return ""
+	}
+	name := mcpToolPrefix + serverID + mcpToolSep + tool
+	if len(name) > mcpMaxToolName {
+		name = mcpDisambiguatedName(name, name)
+	}
+	return name
+}
+
+// mcpDisambiguatedName suffixes name with a hash of key, truncating it so the
+// result stays within the function name limit.
+func mcpDisambiguatedName(name string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	suffix := "_" + hex.EncodeToString(sum[:])[:mcpNameHashLen]
+	if limit := mcpMaxToolName - len(suffix); len(name) > limit {
+		name = strings.TrimRight(name[:limit], "_")
+	}
+	return name + suffix
+}
+
+// MCPServerIDFromToolName returns the server id of a namespaced MCP tool.
+func MCPServerIDFromToolName(name string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(name), mcpToolPrefix)
+	if !ok {
+		return "", false
+	}
+	serverID, _, ok := strings.Cut(rest, mcpToolSep)
+	if !ok || serverID == "" {
+		return "", false
+	}
+	return serverID, true
+}
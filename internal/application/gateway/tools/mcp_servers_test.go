@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	tooldto "dreamcreator/internal/application/tools/dto"
+	toolservice "dreamcreator/internal/application/tools/service"
+	"dreamcreator/internal/domain/assistant"
+)
+
+func fakeMCPHandler(method string, params json.RawMessage) (any, *mcpRPCError) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+				"prompts":   map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": "fake", "version": "1.0.0"},
+		}, nil
+	case "tools/list":
+		var request struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(params, &request)
+		if request.Cursor == "" {
+			return map[string]any{
+				"tools": []any{map[string]any{
+					"name":        "echo",
+					"description": "Echo the text back.",
+					"inputSchema": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"text": map[string]any{"type": "string"}},
+					},
+					"annotations": map[string]any{"readOnlyHint": true},
+				}},
+				"nextCursor": "page-2",
+			}, nil
+		}
+		return map[string]any{
+			"tools": []any{map[string]any{
+				"name":        "delete.file",
+				"description": "Delete a file.",
+				"annotations": map[string]any{"destructiveHint": true},
+			}},
+		}, nil
+	case "resources/list":
+		return map[string]any{"resources": []any{map[string]any{"uri": "file:///notes.md", "name": "notes"}}}, nil
+	case "prompts/list":
+		return map[string]any{"prompts": []any{map[string]any{
+			"name":      "summarize",
+			"arguments": []any{map[string]any{"name": "topic", "required": true}},
+		}}}, nil
+	case "tools/call":
+		var request struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		_ = json.Unmarshal(params, &request)
+		if request.Name != "echo" {
+			return map[string]any{"content": []any{map[string]any{"type": "text", "text": "refused"}}, "isError": true}, nil
+		}
+		return map[string]any{"content": []any{
+			map[string]any{"type": "text", "text": fmt.Sprint(request.Arguments["text"])},
+			map[string]any{"type": "image", "mimeType": "image/png", "data": "aGVsbG8="},
+		}}, nil
+	case "resources/read":
+		return map[string]any{"contents": []any{map[string]any{"uri": "file:///notes.md", "text": "hello"}}}, nil
+	}
+	return nil, &mcpRPCError{Code: -32601, Message: "method not found"}
+}
+
+// newFakeStdioTransport connects a stream transport to an in-process server
+// that answers with fakeMCPHandler.
+func newFakeStdioTransport(t *testing.T) *mcpStreamTransport {
+	t.Helper()
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	go func() {
+		defer serverWriter.Close()
+		encoder := json.NewEncoder(serverWriter)
+		scanner := bufio.NewScanner(serverReader)
+		for scanner.Scan() {
+			var message mcpMessage
+			if err := json.Unmarshal(scanner.Bytes(), &message); err != nil || len(message.ID) == 0 {
+				continue
+			}
+			// Interleave a notification to make sure the client skips it.
+			_ = encoder.Encode(map[string]any{"jsonrpc": "2.0", "method": "notifications/message", "params": map[string]any{}})
+			result, rpcErr := fakeMCPHandler(message.Method, message.Params)
+			reply := map[string]any{"jsonrpc": "2.0", "id": message.ID}
+			if rpcErr != nil {
+				reply["error"] = rpcErr
+			} else {
+				reply["result"] = result
+			}
+			_ = encoder.Encode(reply)
+		}
+	}()
+	return newMCPStreamTransport(clientReader, clientWriter, nil, nil)
+}
+
+func newTestMCPManager(t *testing.T) (*MCPManager, *toolservice.ToolService) {
+	t.Helper()
+	toolSvc := toolservice.NewToolService()
+	executor := NewRegistryExecutor()
+	toolSvc.SetExecutor(executor)
+	manager := NewMCPManager(toolSvc, executor)
+	manager.dial = func(ctx context.Context, _ assistant.AssistantMCPServer) (*mcpClient, error) {
+		client := newMCPClient(newFakeStdioTransport(t))
+		if err := client.initialize(ctx); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	t.Cleanup(manager.Close)
+	return manager, toolSvc
+}
+
+func TestMCPManagerMountsServerTools(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager, toolSvc := newTestMCPManager(t)
+	manager.MountAssistantServers(ctx, "assistant-1", []assistant.AssistantMCPServer{
+		{ID: "files", Transport: assistant.MCPTransportStdio, Command: "fake", Enabled: true},
+		{ID: "disabled", Transport: assistant.MCPTransportStdio, Command: "fake"},
+	})
+
+	specs := make(map[string]tooldto.ToolSpec)
+	for _, spec := range toolSvc.ListTools() {
+		specs[spec.Name] = spec
+	}
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expected := []string{"mcp__files__delete_file", "mcp__files__echo", "mcp__files__get_prompt", "mcp__files__read_resource"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected mounted tools %v", names)
+	}
+	echo := specs["mcp__files__echo"]
+	if echo.Kind != MCPToolKind || echo.RiskLevel != "low" || echo.RequiresApproval {
+		t.Fatalf("unexpected echo spec %#v", echo)
+	}
+	if !strings.Contains(echo.SchemaJSON, `"text"`) {
+		t.Fatalf("expected the input schema to be kept, got %s", echo.SchemaJSON)
+	}
+	decision, err := NewPolicyPipeline(nil).Decide(ctx, specs["mcp__files__delete_file"], tooldto.ToolPolicyContext{})
+	if err != nil || decision.Decision != "ask" {
+		t.Fatalf("expected destructive tools to require approval, got %#v (%v)", decision, err)
+	}
+
+	result, err := toolSvc.ExecuteToolWithDecision(ctx, echo, tooldto.ToolPolicyDecision{Decision: "allow"}, tooldto.ToolInvocation{
+		ID:        "call-1",
+		ToolName:  echo.Name,
+		InputJSON: `{"text":"hello mcp"}`,
+	})
+	if err != nil {
+		t.Fatalf("execute echo failed: %v", err)
+	}
+	var output mcpToolCallResult
+	if err := json.Unmarshal([]byte(result.OutputJSON), &output); err != nil {
+		t.Fatalf("decode output failed: %v", err)
+	}
+	if !output.Ok || len(output.Content) != 2 || output.Content[0].Text != "hello mcp" {
+		t.Fatalf("unexpected echo output %s", result.OutputJSON)
+	}
+	if output.Content[1].Data != "" || !strings.Contains(output.Content[1].Text, "image/png") {
+		t.Fatalf("expected binary content to be omitted, got %#v", output.Content[1])
+	}
+
+	health := manager.Health()
+	if len(health) != 1 || health[0].Status != MCPServerStatusReady || health[0].ServerName != "fake" {
+		t.Fatalf("unexpected health %#v", health)
+	}
+	if health[0].Resources != 1 || health[0].Prompts != 1 || strings.Join(health[0].Assistants, ",") != "assistant-1" {
+		t.Fatalf("unexpected health details %#v", health[0])
+	}
+
+	manager.MountAssistantServers(ctx, "assistant-1", nil)
+	if remaining := toolSvc.ListTools(); len(remaining) != 0 {
+		t.Fatalf("expected tools to be unmounted, got %d", len(remaining))
+	}
+	if health := manager.Health(); len(health) != 0 {
+		t.Fatalf("expected no mounted servers, got %#v", health)
+	}
+}
+
+func TestMCPManagerReportsUnavailableServer(t *testing.T) {
+	t.Parallel()
+
+	manager, toolSvc := newTestMCPManager(t)
+	manager.dial = func(context.Context, assistant.AssistantMCPServer) (*mcpClient, error) {
+		return nil, errors.New("executable file not found")
+	}
+	manager.MountAssistantServers(context.Background(), "assistant-1", []assistant.AssistantMCPServer{
+		{ID: "broken", Transport: assistant.MCPTransportStdio, Command: "missing", Enabled: true},
+	})
+	if tools := toolSvc.ListTools(); len(tools) != 0 {
+		t.Fatalf("expected no tools for a failing server, got %d", len(tools))
+	}
+
+	var payload externalToolsToolResult
+	if err := json.Unmarshal([]byte(marshalMCPServerHealthResult(manager, "mcp", "broken")), &payload); err != nil {
+		t.Fatalf("decode health failed: %v", err)
+	}
+	if !payload.Ok || payload.Ready || payload.Reason != "executable file not found" {
+		t.Fatalf("unexpected health payload %#v", payload)
+	}
+}
+
+func TestMCPHTTPTransportStreamsResponses(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var sessions []string
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deleted = r.Header.Get(mcpSessionIDHeader) == "session-1"
+			mu.Unlock()
+			return
+		}
+		var message mcpMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		sessions = append(sessions, r.Header.Get(mcpSessionIDHeader))
+		mu.Unlock()
+		if len(message.ID) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		result, _ := fakeMCPHandler(message.Method, message.Params)
+		reply, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": message.ID, "result": result})
+		if message.Method == "initialize" {
+			w.Header().Set(mcpSessionIDHeader, "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(reply)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"notifications/progress","params":{}}`)
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", reply)
+	}))
+	defer server.Close()
+
+	client := newMCPClient(newMCPHTTPTransport(server.URL, map[string]string{"Authorization": "Bearer token"}, server.Client()))
+	ctx := context.Background()
+	if err := client.initialize(ctx); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	tools, err := client.listTools(ctx)
+	if err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "echo" || tools[1].Name != "delete.file" {
+		t.Fatalf("unexpected tools %#v", tools)
+	}
+	if err := client.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sessions) < 3 || sessions[0] != "" || sessions[1] != "session-1" || sessions[len(sessions)-1] != "session-1" {
+		t.Fatalf("expected the session id to be reused, got %v", sessions)
+	}
+	if !deleted {
+		t.Fatal("expected the session to be terminated on close")
+	}
+}
+
+func TestMCPToolNameNamespacing(t *testing.T) {
+	t.Parallel()
+
+	name := MCPToolName("My Server", "fs/read file")
+	if name != "mcp__my-server__fs_read_file" {
+		t.Fatalf("unexpected tool name %q", name)
+	}
+	if serverID, ok := MCPServerIDFromToolName(name); !ok || serverID != "my-server" {
+		t.Fatalf("unexpected server id %q", serverID)
+	}
+	if _, ok := MCPServerIDFromToolName("exec"); ok {
+		t.Fatal("expected builtin tools to have no server id")
+	}
+	long := MCPToolName("server", strings.Repeat("x", 100)+"_a")
+	if len(long) != mcpMaxToolName {
+		t.Fatalf("expected names to be capped, got %d chars", len(long))
+	}
+	if other := MCPToolName("server", strings.Repeat("x", 100)+"_b"); other == long {
+		t.Fatalf("expected truncated names to stay distinct, got %q twice", long)
+	}
+	if serverID, ok := MCPServerIDFromToolName(long); !ok || serverID != "server" {
+		t.Fatalf("unexpected server id %q for a truncated name", serverID)
+	}
+}
+
+func TestMCPManagerRejectsConflictingServerConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager, _ := newTestMCPManager(t)
+	manager.MountAssistantServers(ctx, "assistant-1", []assistant.AssistantMCPServer{
+		{ID: "files", Transport: assistant.MCPTransportStdio, Command: "fake", Enabled: true},
+	})
+	rejected := manager.MountAssistantServers(ctx, "assistant-2", []assistant.AssistantMCPServer{
+		{ID: "files", Transport: assistant.MCPTransportStdio, Command: "other", Enabled: true},
+	})
+	if strings.Join(rejected, ",") != "files" {
+		t.Fatalf("expected the conflicting server to be rejected, got %v", rejected)
+	}
+	health := manager.Health()
+	if len(health) != 1 || health[0].Target != "fake" || strings.Join(health[0].Assistants, ",") != "assistant-1" {
+		t.Fatalf("expected the first configuration to stay mounted, got %#v", health)
+	}
+
+	// The only owner may still change its own configuration.
+	if rejected := manager.MountAssistantServers(ctx, "assistant-1", []assistant.AssistantMCPServer{
+		{ID: "files", Transport: assistant.MCPTransportStdio, Command: "fake", Args: []string{"--ro"}, Enabled: true},
+	}); len(rejected) != 0 {
+		t.Fatalf("expected the owner to reconfigure its server, got %v", rejected)
+	}
+	if health := manager.Health(); len(health) != 1 || health[0].Target != "fake --ro" {
+		t.Fatalf("expected the server to be remounted, got %#v", health)
+	}
+}
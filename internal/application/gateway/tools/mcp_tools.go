@@ -0,0 +1,302 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"dreamcreator/internal/domain/assistant"
+)
+
+const mcpMaxDescriptionChars = 1024
+
+type mcpToolCallResult struct {
+	Ok                bool            `json:"ok"`
+	Server            string          `json:"server"`
+	Tool              string          `json:"tool,omitempty"`
+	URI               string          `json:"uri,omitempty"`
+	Prompt            string          `json:"prompt,omitempty"`
+	Content           []mcpContent    `json:"content,omitempty"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	Data              json.RawMessage `json:"data,omitempty"`
+	Error             string          `json:"error,omitempty"`
+}
+
+type mcpResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+func buildMCPToolSpec(server assistant.AssistantMCPServer, name string, tool mcpTool) toolSpec {
+	description := strings.TrimSpace(tool.Description)
+	if title := strings.TrimSpace(tool.Title); title != "" && description == "" {
+		description = title
+	}
+	if description == "" {
+		description = fmt.Sprintf("Call the %s tool.", tool.Name)
+	}
+	readOnly := false
+	destructive := false
+	if tool.Annotations != nil {
+		readOnly = tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+		destructive = tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+	}
+	riskLevel := "medium"
+	if readOnly {
+		riskLevel = "low"
+	}
+	return toolSpec{
+		ID:               name,
+		Name:             name,
+		Description:      truncateMCPDescription(fmt.Sprintf("[MCP %s] %s", server.ID, description)),
+		Kind:             MCPToolKind,
+		Category:         MCPToolKind,
+		SchemaJSON:       resolveMCPInputSchema(tool.InputSchema),
+		RiskLevel:        riskLevel,
+		RequiresApproval: server.RequireApproval || (destructive && !readOnly),
+		Enabled:          true,
+	}
+}
+
+func buildMCPReadResourceSpec(server assistant.AssistantMCPServer, resources []mcpResource) toolSpec {
+	lines := make([]string, 0, len(resources))
+	for index, resource := range resources {
+		if index >= mcpMaxListItems {
+			lines = append(lines, fmt.Sprintf("... %d more", len(resources)-index))
+			break
+		}
+		line := resource.URI
+		if label := firstNonEmpty(resource.Title, resource.Name); label != "" {
+			line += " (" + label + ")"
+		}
+		lines = append(lines, line)
+	}
+	name := MCPToolName(server.ID, mcpReadResourceTool)
+	return toolSpec{
+		ID:          name,
+		Name:        name,
+		Description: truncateMCPDescription(fmt.Sprintf("[MCP %s] Read a resource by uri. Available: %s", server.ID, strings.Join(lines, "; "))),
+		Kind:        MCPToolKind,
+		Category:    MCPToolKind,
+		SchemaJSON: schemaJSON(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"uri": map[string]any{"type": "string"},
+			},
+			"required": []string{"uri"},
+		}),
+		RiskLevel:        "low",
+		RequiresApproval: server.RequireApproval,
+		Enabled:          true,
+	}
+}
+
+func buildMCPGetPromptSpec(server assistant.AssistantMCPServer, prompts []mcpPrompt) toolSpec {
+	lines := make([]string, 0, len(prompts))
+	names := make([]string, 0, len(prompts))
+	for index, prompt := range prompts {
+		names = append(names, prompt.Name)
+		if index >= mcpMaxListItems {
+			continue
+		}
+		line := prompt.Name
+		if len(prompt.Arguments) > 0 {
+			args := make([]string, 0, len(prompt.Arguments))
+			for _, argument := range prompt.Arguments {
+				label := argument.Name
+				if argument.Required {
+					label += "*"
+				}
+				args = append(args, label)
+			}
+			line += "(" + strings.Join(args, ", ") + ")"
+		}
+		if description := strings.TrimSpace(prompt.Description); description != "" {
+			line += ": " + description
+		}
+		lines = append(lines, line)
+	}
+	name := MCPToolName(server.ID, mcpGetPromptTool)
+	return toolSpec{
+		ID:          name,
+		Name:        name,
+		Description: truncateMCPDescription(fmt.Sprintf("[MCP %s] Render a prompt template. Available: %s", server.ID, strings.Join(lines, "; "))),
+		Kind:        MCPToolKind,
+		Category:    MCPToolKind,
+		SchemaJSON: schemaJSON(map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type": "string",
+					"enum": names,
+				},
+				"arguments": map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+			},
+			"required": []string{"name"},
+		}),
+		RiskLevel:        "low",
+		RequiresApproval: server.RequireApproval,
+		Enabled:          true,
+	}
+}
+
+func (manager *MCPManager) runMCPTool(serverID string, toolName string) func(ctx context.Context, args string) (string, error) {
+	return func(ctx context.Context, args string) (string, error) {
+		payload, err := parseToolArgs(args)
+		if err != nil {
+			return "", err
+		}
+		_, client, err := manager.server(ctx, serverID)
+		if err != nil {
+			return "", err
+		}
+		result, err := client.callTool(ctx, toolName, payload)
+		if err != nil {
+			return "", err
+		}
+		output := mcpToolCallResult{
+			Ok:                !result.IsError,
+			Server:            serverID,
+			Tool:              toolName,
+			Content:           compactMCPContent(result.Content),
+			StructuredContent: result.StructuredContent,
+		}
+		if result.IsError {
+			output.Error = joinMCPText(result.Content)
+			if output.Error == "" {
+				output.Error = "mcp tool returned an error"
+			}
+		}
+		return marshalResult(output), nil
+	}
+}
+
+func (manager *MCPManager) runMCPReadResource(serverID string) func(ctx context.Context, args string) (string, error) {
+	return func(ctx context.Context, args string) (string, error) {
+		payload, err := parseToolArgs(args)
+		if err != nil {
+			return "", err
+		}
+		uri := getStringArg(payload, "uri")
+		if uri == "" {
+			return "", errors.New("uri is required")
+		}
+		_, client, err := manager.server(ctx, serverID)
+		if err != nil {
+			return "", err
+		}
+		raw, err := client.readResource(ctx, uri)
+		if err != nil {
+			return "", err
+		}
+		var decoded struct {
+			Contents []mcpResourceContents `json:"contents"`
+		}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return "", fmt.Errorf("decode resources/read result: %w", err)
+		}
+		for index, item := range decoded.Contents {
+			if item.Blob != "" {
+				decoded.Contents[index].Text = fmt.Sprintf("[binary content omitted: %d base64 chars]", len(item.Blob))
+				decoded.Contents[index].Blob = ""
+			}
+		}
+		data, _ := json.Marshal(decoded)
+		return marshalResult(mcpToolCallResult{
+			Ok:     true,
+			Server: serverID,
+			URI:    uri,
+			Data:   data,
+		}), nil
+	}
+}
+
+func (manager *MCPManager) runMCPGetPrompt(serverID string) func(ctx context.Context, args string) (string, error) {
+	return func(ctx context.Context, args string) (string, error) {
+		payload, err := parseToolArgs(args)
+		if err != nil {
+			return "", err
+		}
+		name := getStringArg(payload, "name", "prompt")
+		if name == "" {
+			return "", errors.New("name is required")
+		}
+		arguments := make(map[string]string)
+		for key, value := range getMapArg(payload, "arguments", "args") {
+			arguments[key] = toString(value)
+		}
+		_, client, err := manager.server(ctx, serverID)
+		if err != nil {
+			return "", err
+		}
+		raw, err := client.getPrompt(ctx, name, arguments)
+		if err != nil {
+			return "", err
+		}
+		return marshalResult(mcpToolCallResult{
+			Ok:     true,
+			Server: serverID,
+			Prompt: name,
+			Data:   raw,
+		}), nil
+	}
+}
+
+func resolveMCPInputSchema(raw json.RawMessage) string {
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil || schema == nil {
+		schema = map[string]any{}
+	}
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+	if _, ok := schema["properties"]; !ok {
+		schema["properties"] = map[string]any{}
+	}
+	return schemaJSON(schema)
+}
+
+// compactMCPContent drops inline binary payloads so images and audio returned
+// by a server do not flood the model context.
+func compactMCPContent(items []mcpContent) []mcpContent {
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]mcpContent, 0, len(items))
+	for _, item := range items {
+		if item.Data != "" {
+			item.Text = fmt.Sprintf("[%s content omitted: %d base64 chars]", firstNonEmpty(item.MimeType, item.Type), len(item.Data))
+			item.Data = ""
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func joinMCPText(items []mcpContent) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if text := strings.TrimSpace(item.Text); item.Type == "text" && text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func truncateMCPDescription(value string) string {
+	if len(value) <= mcpMaxDescriptionChars {
+		return value
+	}
+	runes := []rune(value)
+	if len(runes) <= mcpMaxDescriptionChars {
+		return value
+	}
+	return string(runes[:mcpMaxDescriptionChars-3]) + "..."
+}
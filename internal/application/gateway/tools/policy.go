@@ -2,14 +2,23 @@ package tools
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 
 	tooldto "dreamcreator/internal/application/tools/dto"
+	"dreamcreator/internal/infrastructure/toolpolicyrepo"
 )
 
 type PolicyPipeline struct {
 	settings             SettingsReader
 	requirementsResolver ToolRequirementResolver
+	rules                PolicyRuleEvaluator
+}
+
+// PolicyRuleEvaluator matches a tool call and its arguments against the
+// user-defined policy rules.
+type PolicyRuleEvaluator interface {
+	Evaluate(ctx context.Context, call toolpolicyrepo.ToolCall) (toolpolicyrepo.Verdict, error)
 }
 
 func NewPolicyPipeline(settings SettingsReader) *PolicyPipeline {
@@ -23,6 +32,13 @@ func (pipeline *PolicyPipeline) SetRequirementsResolver(resolver ToolRequirement
 	pipeline.requirementsResolver = resolver
 }
 
+func (pipeline *PolicyPipeline) SetRuleEvaluator(rules PolicyRuleEvaluator) {
+	if pipeline == nil {
+		return
+	}
+	pipeline.rules = rules
+}
+
 func (pipeline *PolicyPipeline) Decide(ctx context.Context, spec tooldto.ToolSpec, policyCtx tooldto.ToolPolicyContext) (tooldto.ToolPolicyDecision, error) {
 	if !spec.Enabled {
 		return tooldto.ToolPolicyDecision{
@@ -73,12 +89,151 @@ func (pipeline *PolicyPipeline) Decide(ctx context.Context, spec tooldto.ToolSpe
 		decision.SandboxRequired = false
 		decision.ApprovalRequired = false
 	}
+	if pipeline.rules != nil {
+		action, args := toolCallFromContext(ctx)
+		verdict, err := pipeline.rules.Evaluate(ctx, policyToolCall(spec.Name, action, args, policyPathAnchor(ctx, policyCtx)))
+		if err != nil {
+			return tooldto.ToolPolicyDecision{}, err
+		}
+		decision.MatchedRules = append(decision.MatchedRules, verdict.MatchedRules...)
+		switch verdict.Decision {
+		case toolpolicyrepo.RuleDecisionDeny:
+			decision.Decision = "deny"
+			decision.Reason = verdict.Reason
+			decision.ApprovalRequired = false
+			return decision, nil
+		case toolpolicyrepo.RuleDecisionAsk:
+			decision.ApprovalRequired = true
+			decision.Reason = verdict.Reason
+		case toolpolicyrepo.RuleDecisionAllow:
+			// An allow rule waives risk-based approval but not an approval the
+			// caller explicitly asked for.
+			if !policyCtx.RequireApproval {
+				decision.ApprovalRequired = false
+			}
+		}
+	}
 	if decision.ApprovalRequired {
 		decision.Decision = "ask"
 	}
 	return decision, nil
 }
 
+// policyPathAnchor returns the directory tools resolve relative paths
+// against: the workspace the sandbox confines the call to. It is empty when
+// the call has no workspace, in which case relative paths stay unresolved.
+func policyPathAnchor(ctx context.Context, policyCtx tooldto.ToolPolicyContext) string {
+	workspace := strings.TrimSpace(policyCtx.WorkspacePath)
+	if workspace == "" {
+		if resolution, ok := sandboxResolutionFromContext(ctx); ok {
+			workspace = resolution.Policy.WorkspaceDir
+		}
+	}
+	if workspace == "" {
+		return ""
+	}
+	if absolute, err := filepath.Abs(workspace); err == nil {
+		return absolute
+	}
+	return ""
+}
+
+// policyToolCall extracts the commands, paths and URLs that policy rules can
+// match from raw tool arguments. Relative paths are anchored at their root
+// argument, or at the workspace when there is none; without either they are
+// left relative for the rules to treat as unresolved.
+func policyToolCall(toolName string, action string, args string, workspace string) toolpolicyrepo.ToolCall {
+	call := toolpolicyrepo.ToolCall{Tool: strings.TrimSpace(toolName), Action: strings.TrimSpace(action)}
+	payload, err := parseToolArgs(args)
+	if err != nil {
+		return call
+	}
+	if call.Action == "" {
+		call.Action = getStringArg(payload, "action")
+	}
+	if argv := getStringSliceArg(payload, "cmd"); len(argv) > 0 {
+		call.Commands = append(call.Commands, strings.Join(argv, " "))
+	} else if command := getStringArg(payload, "command", "cmdline", "cmd"); command != "" {
+		call.Commands = append(call.Commands, command)
+	}
+	root := getStringArg(payload, "rootPath", "root", "baseDir", "basePath", "cwd", "workingDir", "workingDirectory")
+	base := strings.TrimSpace(workspace)
+	if root != "" {
+		root = policyAbsPath(base, root)
+		call.Paths = append(call.Paths, root)
+		base = root
+	}
+	paths := getStringSliceArg(payload, "paths")
+	if path := getStringArg(payload, "path", "file", "filePath", "filepath"); path != "" {
+		paths = append(paths, path)
+	}
+	if patch := getStringArg(payload, "patch", "diff"); patch != "" {
+		paths = append(paths, patchTargetPaths(patch)...)
+	}
+	for _, path := range paths {
+		call.Paths = append(call.Paths, policyAbsPath(base, path))
+	}
+	call.URLs = getStringSliceArg(payload, "urls")
+	if target := getStringArg(payload, "url", "targetUrl", "href"); target != "" {
+		call.URLs = append(call.URLs, target)
+	}
+	return call
+}
+
+// patchTargetPaths returns the files a patch touches, from both the
+// "*** Update File:" style headers and unified diff headers.
+func patchTargetPaths(patch string) []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0)
+	add := func(path string) {
+		path = strings.TrimSpace(path)
+		if index := strings.IndexByte(path, '\t'); index >= 0 {
+			path = path[:index]
+		}
+		path = strings.Trim(path, `"`)
+		if path == "" || path == "/dev/null" {
+			return
+		}
+		if _, ok := seen[path]; ok {
+			return
+		}
+		seen[path] = struct{}{}
+		result = append(result, path)
+	}
+	addDiffPath := func(value string, prefix string) {
+		if trimmed, ok := strings.CutPrefix(value, prefix); ok {
+			value = trimmed
+		}
+		add(value)
+	}
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for index, line := range lines {
+		for _, header := range []string{"*** Update File:", "*** Add File:", "*** Delete File:", "*** Move to:", "rename from ", "rename to ", "copy to "} {
+			if value, ok := strings.CutPrefix(line, header); ok {
+				add(value)
+			}
+		}
+		// A "---" line is only a file header when a "+++" line follows;
+		// otherwise it is a removed line inside a hunk.
+		oldPath, ok := strings.CutPrefix(line, "--- ")
+		if !ok || index+1 >= len(lines) {
+			continue
+		}
+		if newPath, ok := strings.CutPrefix(lines[index+1], "+++ "); ok {
+			addDiffPath(oldPath, "a/")
+			addDiffPath(newPath, "b/")
+		}
+	}
+	return result
+}
+
+func policyAbsPath(base string, path string) string {
+	if !filepath.IsAbs(path) && base != "" {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
+
 func (pipeline *PolicyPipeline) policyEnabled(ctx context.Context) bool {
 	return true
 }
@@ -0,0 +1,568 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	gatewayevents "dreamcreator/internal/application/gateway/events"
+	"dreamcreator/internal/infrastructure/processutil"
+)
+
+const (
+	ProcessStatusRunning = "running"
+	ProcessStatusExited  = "exited"
+	ProcessStatusKilled  = "killed"
+
+	processOutputBufferBytes = 64 * 1024
+	processMaxRunning        = 8
+	processKillWait          = 5 * time.Second
+	processMaxPollWait       = 30 * time.Second
+	// Exited processes stay pollable for processRetention, and a session
+	// keeps at most processMaxExited of them.
+	processRetention = 30 * time.Minute
+	processMaxExited = 32
+)
+
+var errProcessNotFound = errors.New("process not found")
+
+// ProcessInfo describes a process started by the process tool.
+type ProcessInfo struct {
+	ID         string     `json:"id"`
+	PID        int        `json:"pid"`
+	Command    string     `json:"command"`
+	Status     string     `json:"status"`
+	ExitCode   *int       `json:"exitCode,omitempty"`
+	ExitSignal string     `json:"exitSignal,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	ExitedAt   *time.Time `json:"exitedAt,omitempty"`
+}
+
+// ProcessOutput is the output a poll or log call returns. DroppedBytes
+// counts output that was overwritten in the ring buffer before it was read.
+type ProcessOutput struct {
+	ProcessInfo
+	Stdout       string `json:"stdout"`
+	Stderr       string `json:"stderr"`
+	DroppedBytes int64  `json:"droppedBytes,omitempty"`
+}
+
+type ProcessStartRequest struct {
+	Command string
+	Args    []string
+	Display string
+	Cwd     string
+	Env     map[string]any
+}
+
+// ProcessManager owns the background processes started by the process tool.
+// Processes belong to the runtime session that started them: other sessions
+// cannot see them, and they are killed with their process group when the
+// session is cleaned up or the app shuts down. Output is kept in bounded ring
+// buffers and exits are published to the run event stream.
+type ProcessManager struct {
+	events *gatewayevents.Broker
+	now    func() time.Time
+	newID  func() string
+
+	mu        sync.Mutex
+	processes map[string]*managedProcess
+	starting  map[string]int
+	closed    bool
+}
+
+type managedProcess struct {
+	id         string
+	sessionKey string
+	runID      string
+	command    string
+	cmd        *exec.Cmd
+	stdin      io.WriteCloser
+	stdout     *outputRing
+	stderr     *outputRing
+	startedAt  time.Time
+	done       chan struct{}
+
+	mu           sync.Mutex
+	stdoutCursor int64
+	stderrCursor int64
+	killed       bool
+	exitedAt     time.Time
+	exitCode     int
+	exitSignal   string
+	exitErr      string
+}
+
+func NewProcessManager(events *gatewayevents.Broker) *ProcessManager {
+	return &ProcessManager{
+		events:    events,
+		now:       time.Now,
+		newID:     uuid.NewString,
+		processes: make(map[string]*managedProcess),
+		starting:  make(map[string]int),
+	}
+}
+
+func (manager *ProcessManager) Start(ctx context.Context, request ProcessStartRequest) (ProcessInfo, error) {
+	if manager == nil {
+		return ProcessInfo{}, errors.New("process manager unavailable")
+	}
+	sessionKey, runID := RuntimeContextFromContext(ctx)
+	sessionKey = strings.TrimSpace(sessionKey)
+	manager.mu.Lock()
+	if manager.closed {
+		manager.mu.Unlock()
+		return ProcessInfo{}, errors.New("process manager closed")
+	}
+	manager.pruneLocked(sessionKey)
+	// Count starts in flight so concurrent calls cannot exceed the cap.
+	running := manager.starting[sessionKey]
+	for _, process := range manager.processes {
+		if process.sessionKey == sessionKey && !process.exited() {
+			running++
+		}
+	}
+	if running >= processMaxRunning {
+		manager.mu.Unlock()
+		return ProcessInfo{}, fmt.Errorf("too many running processes (max %d); kill one first", processMaxRunning)
+	}
+	manager.starting[sessionKey]++
+	manager.mu.Unlock()
+	registered := false
+	defer func() {
+		if registered {
+			return
+		}
+		manager.mu.Lock()
+		manager.releaseStartLocked(sessionKey)
+		manager.mu.Unlock()
+	}()
+
+	// The process outlives the tool call, so it must not be tied to the
+	// call's cancellation. The sandbox resolution on ctx still applies, minus
+	// its wall-clock timeout: the process lives until it is killed or the
+	// session ends.
+	startCtx := context.WithoutCancel(ctx)
+	if resolution, ok := sandboxResolutionFromContext(ctx); ok {
+		startCtx = withSandboxResolution(startCtx, resolution.WithoutTimeout())
+	}
+	cmd, err := newToolCommand(startCtx, request.Cwd, request.Command, request.Args...)
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	applyCommandEnv(cmd, request.Env)
+	processutil.ConfigureProcessGroup(cmd)
+	// Grandchildren that inherit the output pipes must not keep Wait from
+	// returning once the process itself is gone.
+	cmd.WaitDelay = processKillWait
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	process := &managedProcess{
+		id:         manager.newID(),
+		sessionKey: sessionKey,
+		runID:      strings.TrimSpace(runID),
+		command:    strings.TrimSpace(request.Display),
+		cmd:        cmd,
+		stdin:      stdin,
+		stdout:     newOutputRing(processOutputBufferBytes),
+		stderr:     newOutputRing(processOutputBufferBytes),
+		done:       make(chan struct{}),
+	}
+	if process.command == "" {
+		process.command = strings.TrimSpace(strings.Join(append([]string{request.Command}, request.Args...), " "))
+	}
+	cmd.Stdout = process.stdout
+	cmd.Stderr = process.stderr
+	if err := cmd.Start(); err != nil {
+		return ProcessInfo{}, err
+	}
+	process.startedAt = manager.now()
+
+	manager.mu.Lock()
+	if manager.closed {
+		manager.mu.Unlock()
+		_ = processutil.TerminateProcessGroup(cmd, 0)
+		_ = cmd.Wait()
+		return ProcessInfo{}, errors.New("process manager closed")
+	}
+	manager.releaseStartLocked(sessionKey)
+	manager.processes[process.id] = process
+	registered = true
+	manager.mu.Unlock()
+
+	go manager.wait(process)
+	return process.info(), nil
+}
+
+func (manager *ProcessManager) wait(process *managedProcess) {
+	err := process.cmd.Wait()
+	process.mu.Lock()
+	process.exitedAt = manager.now()
+	process.exitCode = -1
+	if state := process.cmd.ProcessState; state != nil {
+		process.exitCode = state.ExitCode()
+		if process.exitCode < 0 {
+			process.exitSignal = strings.TrimPrefix(state.String(), "signal: ")
+		}
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		process.exitErr = err.Error()
+	}
+	process.mu.Unlock()
+	close(process.done)
+	manager.publishExit(process)
+	manager.mu.Lock()
+	manager.pruneLocked(process.sessionKey)
+	manager.mu.Unlock()
+}
+
+func (manager *ProcessManager) releaseStartLocked(sessionKey string) {
+	if manager.starting[sessionKey] <= 1 {
+		delete(manager.starting, sessionKey)
+		return
+	}
+	manager.starting[sessionKey]--
+}
+
+// pruneLocked forgets exited processes past the retention window and the
+// oldest exited processes of a session above the per-session cap, stopping
+// anything they left running in their process group.
+func (manager *ProcessManager) pruneLocked(sessionKey string) {
+	cutoff := manager.now().Add(-processRetention)
+	exited := make([]*managedProcess, 0)
+	for id, process := range manager.processes {
+		if !process.exited() {
+			continue
+		}
+		exitedAt := process.exitTime()
+		if exitedAt.Before(cutoff) {
+			delete(manager.processes, id)
+			go process.terminate()
+			continue
+		}
+		if process.sessionKey == sessionKey {
+			exited = append(exited, process)
+		}
+	}
+	if len(exited) <= processMaxExited {
+		return
+	}
+	sort.Slice(exited, func(i, j int) bool { return exited[i].exitTime().Before(exited[j].exitTime()) })
+	for _, process := range exited[:len(exited)-processMaxExited] {
+		delete(manager.processes, process.id)
+		go process.terminate()
+	}
+}
+
+func (manager *ProcessManager) publishExit(process *managedProcess) {
+	if manager.events == nil {
+		return
+	}
+	info := process.info()
+	envelope := gatewayevents.Envelope{
+		Type:       "process.exited",
+		Topic:      "run",
+		SessionKey: process.sessionKey,
+		RunID:      process.runID,
+		Timestamp:  manager.now(),
+	}
+	_, _ = manager.events.Publish(context.Background(), envelope, info)
+}
+
+// List returns the processes of the calling session, oldest first.
+func (manager *ProcessManager) List(ctx context.Context) []ProcessInfo {
+	if manager == nil {
+		return nil
+	}
+	sessionKey, _ := RuntimeContextFromContext(ctx)
+	sessionKey = strings.TrimSpace(sessionKey)
+	manager.mu.Lock()
+	processes := make([]*managedProcess, 0, len(manager.processes))
+	for _, process := range manager.processes {
+		if process.sessionKey == sessionKey {
+			processes = append(processes, process)
+		}
+	}
+	manager.mu.Unlock()
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].startedAt.Before(processes[j].startedAt)
+	})
+	result := make([]ProcessInfo, 0, len(processes))
+	for _, process := range processes {
+		result = append(result, process.info())
+	}
+	return result
+}
+
+// Poll returns the output written since the previous poll. A positive wait
+// blocks until the process exits or the wait elapses.
+func (manager *ProcessManager) Poll(ctx context.Context, id string, wait time.Duration) (ProcessOutput, error) {
+	process, err := manager.lookup(ctx, id)
+	if err != nil {
+		return ProcessOutput{}, err
+	}
+	if wait > processMaxPollWait {
+		wait = processMaxPollWait
+	}
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-process.done:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		timer.Stop()
+	}
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	output := ProcessOutput{ProcessInfo: process.infoLocked()}
+	stdout, nextStdout, droppedStdout := process.stdout.readFrom(process.stdoutCursor)
+	stderr, nextStderr, droppedStderr := process.stderr.readFrom(process.stderrCursor)
+	process.stdoutCursor, process.stderrCursor = nextStdout, nextStderr
+	output.Stdout, output.Stderr = string(stdout), string(stderr)
+	output.DroppedBytes = droppedStdout + droppedStderr
+	return output, nil
+}
+
+// Log returns all output still held in the ring buffers without moving the
+// poll cursor.
+func (manager *ProcessManager) Log(ctx context.Context, id string) (ProcessOutput, error) {
+	process, err := manager.lookup(ctx, id)
+	if err != nil {
+		return ProcessOutput{}, err
+	}
+	stdout, _, droppedStdout := process.stdout.readFrom(0)
+	stderr, _, droppedStderr := process.stderr.readFrom(0)
+	return ProcessOutput{
+		ProcessInfo:  process.info(),
+		Stdout:       string(stdout),
+		Stderr:       string(stderr),
+		DroppedBytes: droppedStdout + droppedStderr,
+	}, nil
+}
+
+// Write sends input to the process; closeStdin signals end of input.
+func (manager *ProcessManager) Write(ctx context.Context, id string, input string, closeStdin bool) (ProcessInfo, error) {
+	process, err := manager.lookup(ctx, id)
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	if process.exited() {
+		return process.info(), errors.New("process has exited")
+	}
+	if input != "" {
+		if _, err := io.WriteString(process.stdin, input); err != nil {
+			return process.info(), err
+		}
+	}
+	if closeStdin {
+		if err := process.stdin.Close(); err != nil {
+			return process.info(), err
+		}
+	}
+	return process.info(), nil
+}
+
+// Kill terminates the process group and waits briefly for the exit.
+func (manager *ProcessManager) Kill(ctx context.Context, id string) (ProcessInfo, error) {
+	process, err := manager.lookup(ctx, id)
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	process.terminate()
+	select {
+	case <-process.done:
+	case <-time.After(processKillWait):
+	}
+	return process.info(), nil
+}
+
+// CleanupSession kills and forgets every process of a session.
+func (manager *ProcessManager) CleanupSession(sessionKey string) {
+	if manager == nil {
+		return
+	}
+	sessionKey = strings.TrimSpace(sessionKey)
+	manager.mu.Lock()
+	processes := make([]*managedProcess, 0)
+	for id, process := range manager.processes {
+		if process.sessionKey == sessionKey {
+			processes = append(processes, process)
+			delete(manager.processes, id)
+		}
+	}
+	manager.mu.Unlock()
+	for _, process := range processes {
+		go process.terminate()
+	}
+}
+
+// Close kills all processes so none survive the app.
+func (manager *ProcessManager) Close() {
+	if manager == nil {
+		return
+	}
+	manager.mu.Lock()
+	manager.closed = true
+	processes := manager.processes
+	manager.processes = make(map[string]*managedProcess)
+	manager.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, process := range processes {
+		wg.Add(1)
+		go func(process *managedProcess) {
+			defer wg.Done()
+			process.terminate()
+			select {
+			case <-process.done:
+			case <-time.After(processKillWait):
+			}
+		}(process)
+	}
+	wg.Wait()
+}
+
+func (manager *ProcessManager) lookup(ctx context.Context, id string) (*managedProcess, error) {
+	if manager == nil {
+		return nil, errors.New("process manager unavailable")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+	sessionKey, _ := RuntimeContextFromContext(ctx)
+	manager.mu.Lock()
+	process, ok := manager.processes[id]
+	manager.mu.Unlock()
+	if !ok || process.sessionKey != strings.TrimSpace(sessionKey) {
+		return nil, errProcessNotFound
+	}
+	return process, nil
+}
+
+func (process *managedProcess) exited() bool {
+	select {
+	case <-process.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// terminate signals the whole process group. It also runs after the leader
+// has exited, so background children it left in the group are stopped too.
+func (process *managedProcess) terminate() {
+	if !process.exited() {
+		process.mu.Lock()
+		process.killed = true
+		process.mu.Unlock()
+	}
+	_ = processutil.TerminateProcessGroup(process.cmd, processKillWait)
+}
+
+func (process *managedProcess) exitTime() time.Time {
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	return process.exitedAt
+}
+
+func (process *managedProcess) info() ProcessInfo {
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	return process.infoLocked()
+}
+
+func (process *managedProcess) infoLocked() ProcessInfo {
+	info := ProcessInfo{
+		ID:        process.id,
+		Command:   process.command,
+		Status:    ProcessStatusRunning,
+		StartedAt: process.startedAt,
+	}
+	if process.cmd.Process != nil {
+		info.PID = process.cmd.Process.Pid
+	}
+	if process.exitedAt.IsZero() {
+		return info
+	}
+	info.Status = ProcessStatusExited
+	if process.killed {
+		info.Status = ProcessStatusKilled
+	}
+	exitCode := process.exitCode
+	exitedAt := process.exitedAt
+	info.ExitCode = &exitCode
+	info.ExitSignal = process.exitSignal
+	info.Error = process.exitErr
+	info.ExitedAt = &exitedAt
+	return info
+}
+
+// outputRing keeps the most recent output of a stream in a fixed-size ring
+// and counts every byte written, so readers can resume from an offset.
+type outputRing struct {
+	mu    sync.Mutex
+	buf   []byte
+	total int64
+}
+
+func newOutputRing(size int) *outputRing {
+	return &outputRing{buf: make([]byte, size)}
+}
+
+func (ring *outputRing) Write(data []byte) (int, error) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	written := len(data)
+	size := len(ring.buf)
+	if len(data) > size {
+		ring.total += int64(len(data) - size)
+		data = data[len(data)-size:]
+	}
+	for len(data) > 0 {
+		position := int(ring.total % int64(size))
+		copied := copy(ring.buf[position:], data)
+		data = data[copied:]
+		ring.total += int64(copied)
+	}
+	return written, nil
+}
+
+// readFrom returns the bytes written after offset that are still buffered,
+// the offset to resume from, and how many bytes were lost to overwrites.
+func (ring *outputRing) readFrom(offset int64) ([]byte, int64, int64) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+	size := int64(len(ring.buf))
+	var dropped int64
+	if oldest := ring.total - size; offset < oldest {
+		dropped = oldest - offset
+		offset = oldest
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	result := make([]byte, 0, ring.total-offset)
+	for offset < ring.total {
+		position := offset % size
+		end := position + (ring.total - offset)
+		if end > size {
+			end = size
+		}
+		result = append(result, ring.buf[position:end]...)
+		offset += end - position
+	}
+	return result, ring.total, dropped
+}
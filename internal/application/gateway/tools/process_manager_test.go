@@ -0,0 +1,264 @@
+//go:build unix
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	gatewayevents "dreamcreator/internal/application/gateway/events"
+)
+
+func TestOutputRingKeepsRecentOutput(t *testing.T) {
+	ring := newOutputRing(8)
+	_, _ = ring.Write([]byte("hello"))
+	data, next, dropped := ring.readFrom(0)
+	if string(data) != "hello" || next != 5 || dropped != 0 {
+		t.Fatalf("unexpected read %q next=%d dropped=%d", data, next, dropped)
+	}
+	_, _ = ring.Write([]byte(" world!"))
+	data, next, dropped = ring.readFrom(next)
+	if string(data) != " world!" || next != 12 || dropped != 0 {
+		t.Fatalf("expected the read to wrap around, got %q next=%d dropped=%d", data, next, dropped)
+	}
+	_, _ = ring.Write([]byte("0123456789"))
+	data, _, dropped = ring.readFrom(next)
+	if string(data) != "23456789" || dropped != 2 {
+		t.Fatalf("expected overwritten output to be reported, got %q dropped=%d", data, dropped)
+	}
+}
+
+func TestProcessManagerPollsWritesAndKills(t *testing.T) {
+	events := gatewayevents.NewBroker(nil)
+	exited := make(chan gatewayevents.Record, 4)
+	unsubscribe := events.Subscribe(gatewayevents.Filter{Type: "process.exited"}, func(record gatewayevents.Record) {
+		exited <- record
+	})
+	defer unsubscribe()
+	manager := NewProcessManager(events)
+	defer manager.Close()
+	ctx := WithRuntimeContext(context.Background(), "session-a", "run-1")
+
+	echo, err := manager.Start(ctx, ProcessStartRequest{Command: "sh", Args: []string{"-c", "read line; echo got $line; echo oops >&2"}})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if echo.Status != ProcessStatusRunning || echo.PID == 0 {
+		t.Fatalf("unexpected start result %#v", echo)
+	}
+	if _, err := manager.Write(ctx, echo.ID, "ping\n", true); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	output, err := manager.Poll(ctx, echo.ID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if output.Status != ProcessStatusExited || output.ExitCode == nil || *output.ExitCode != 0 {
+		t.Fatalf("expected the process to exit, got %#v", output.ProcessInfo)
+	}
+	if output.Stdout != "got ping\n" || output.Stderr != "oops\n" {
+		t.Fatalf("unexpected output %q / %q", output.Stdout, output.Stderr)
+	}
+	if again, _ := manager.Poll(ctx, echo.ID, 0); again.Stdout != "" {
+		t.Fatalf("expected polls to only return new output, got %q", again.Stdout)
+	}
+	if logged, _ := manager.Log(ctx, echo.ID); logged.Stdout != "got ping\n" {
+		t.Fatalf("expected the log to return buffered output, got %q", logged.Stdout)
+	}
+	select {
+	case record := <-exited:
+		var info ProcessInfo
+		_ = json.Unmarshal(record.Payload, &info)
+		if record.Envelope.Topic != "run" || record.Envelope.RunID != "run-1" || info.ID != echo.ID {
+			t.Fatalf("unexpected exit event %#v", record)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected an exit event")
+	}
+
+	sleeper, err := manager.Start(ctx, ProcessStartRequest{Command: "sh", Args: []string{"-c", "sleep 30 & wait"}})
+	if err != nil {
+		t.Fatalf("start sleeper: %v", err)
+	}
+	other := WithRuntimeContext(context.Background(), "session-b", "")
+	if _, err := manager.Kill(other, sleeper.ID); err != errProcessNotFound {
+		t.Fatalf("expected other sessions not to see the process, got %v", err)
+	}
+	if items := manager.List(other); len(items) != 0 {
+		t.Fatalf("expected no processes for another session, got %#v", items)
+	}
+	if items := manager.List(ctx); len(items) != 2 || items[0].ID != echo.ID {
+		t.Fatalf("unexpected list %#v", items)
+	}
+	killed, err := manager.Kill(ctx, sleeper.ID)
+	if err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+	if killed.Status != ProcessStatusKilled {
+		t.Fatalf("expected the process group to be killed, got %#v", killed)
+	}
+}
+
+func TestProcessManagerCleanupSessionKillsProcesses(t *testing.T) {
+	manager := NewProcessManager(nil)
+	defer manager.Close()
+	ctx := WithRuntimeContext(context.Background(), "session-a", "")
+	started, err := manager.Start(ctx, ProcessStartRequest{Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	manager.mu.Lock()
+	process := manager.processes[started.ID]
+	manager.mu.Unlock()
+
+	manager.CleanupSession("session-a")
+	select {
+	case <-process.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the process to be killed")
+	}
+	if _, err := manager.Poll(ctx, started.ID, 0); err != errProcessNotFound {
+		t.Fatalf("expected the process to be forgotten, got %v", err)
+	}
+	if info := process.info(); info.Status != ProcessStatusKilled || info.ExitSignal == "" {
+		t.Fatalf("unexpected exit %#v", info)
+	}
+}
+
+func TestProcessManagerPrunesExitedProcesses(t *testing.T) {
+	manager := NewProcessManager(nil)
+	defer manager.Close()
+	now := time.Now()
+	var nowMu sync.Mutex
+	manager.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+	ctx := WithRuntimeContext(context.Background(), "session-a", "")
+	run := func() string {
+		t.Helper()
+		started, err := manager.Start(ctx, ProcessStartRequest{Command: "true"})
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		if output, err := manager.Poll(ctx, started.ID, 5*time.Second); err != nil || output.Status != ProcessStatusExited {
+			t.Fatalf("expected the process to exit, got %#v (%v)", output.ProcessInfo, err)
+		}
+		nowMu.Lock()
+		now = now.Add(time.Second)
+		nowMu.Unlock()
+		return started.ID
+	}
+
+	first := run()
+	for range processMaxExited {
+		run()
+	}
+	if _, err := manager.Poll(ctx, first, 0); err != errProcessNotFound {
+		t.Fatalf("expected the oldest exited process to be pruned, got %v", err)
+	}
+	if items := manager.List(ctx); len(items) != processMaxExited {
+		t.Fatalf("expected %d retained processes, got %d", processMaxExited, len(items))
+	}
+
+	nowMu.Lock()
+	now = now.Add(processRetention + time.Minute)
+	nowMu.Unlock()
+	last := run()
+	if items := manager.List(ctx); len(items) != 1 || items[0].ID != last {
+		t.Fatalf("expected expired processes to be pruned, got %#v", items)
+	}
+}
+
+func TestProcessManagerKillsBackgroundChildrenOfExitedProcesses(t *testing.T) {
+	manager := NewProcessManager(nil)
+	defer manager.Close()
+	ctx := WithRuntimeContext(context.Background(), "session-a", "")
+
+	startOrphan := func() (string, int) {
+		t.Helper()
+		started, err := manager.Start(ctx, ProcessStartRequest{Command: "sh", Args: []string{"-c", "sleep 100 >/dev/null 2>&1 & echo $!; exit 0"}})
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		output, err := manager.Poll(ctx, started.ID, 10*time.Second)
+		if err != nil || output.Status != ProcessStatusExited {
+			t.Fatalf("expected the shell to exit, got %#v (%v)", output.ProcessInfo, err)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(output.Stdout))
+		if err != nil {
+			t.Fatalf("unexpected output %q", output.Stdout)
+		}
+		if processGone(pid) {
+			t.Fatalf("expected the background sleep %d to outlive the shell", pid)
+		}
+		return started.ID, pid
+	}
+
+	killed, pid := startOrphan()
+	if _, err := manager.Kill(ctx, killed); err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+	waitProcessGone(t, pid)
+
+	_, pid = startOrphan()
+	manager.CleanupSession("session-a")
+	waitProcessGone(t, pid)
+}
+
+func TestProcessManagerCapsConcurrentStarts(t *testing.T) {
+	manager := NewProcessManager(nil)
+	defer manager.Close()
+	ctx := WithRuntimeContext(context.Background(), "session-a", "")
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started := 0
+	for range processMaxRunning * 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := manager.Start(ctx, ProcessStartRequest{Command: "sleep", Args: []string{"30"}}); err == nil {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if started != processMaxRunning {
+		t.Fatalf("expected %d processes to start, got %d", processMaxRunning, started)
+	}
+}
+
+// processGone reports whether pid has exited. Orphans reparented to a
+// reaper that never waits stay zombies, which count as gone.
+func processGone(pid int) bool {
+	if stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		return len(fields) > 0 && fields[0] == "Z"
+	}
+	return errors.Is(syscall.Kill(pid, 0), syscall.ESRCH)
+}
+
+func waitProcessGone(t *testing.T, pid int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !processGone(pid) {
+		if time.Now().After(deadline) {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("expected background process %d to be killed", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -31,6 +31,15 @@ func (executor *RegistryExecutor) Register(name string, fn func(ctx context.Cont
 	executor.mu.Unlock()
 }
 
+func (executor *RegistryExecutor) Unregister(name string) {
+	if executor == nil {
+		return
+	}
+	executor.mu.Lock()
+	delete(executor.tools, strings.TrimSpace(name))
+	executor.mu.Unlock()
+}
+
 func (executor *RegistryExecutor) Execute(ctx context.Context, spec tooldto.ToolSpec, args string) (string, error) {
 	if executor == nil {
 		return "", errors.New("executor unavailable")
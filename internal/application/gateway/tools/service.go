@@ -13,6 +13,7 @@ import (
 	gatewaysandbox "dreamcreator/internal/application/gateway/sandbox"
 	tooldto "dreamcreator/internal/application/tools/dto"
 	toolservice "dreamcreator/internal/application/tools/service"
+	"dreamcreator/internal/domain/assistant"
 )
 
 type Service struct {
@@ -23,6 +24,8 @@ type Service struct {
 	audit                PolicyAuditStore
 	events               *gatewayevents.Broker
 	requirementsResolver ToolRequirementResolver
+	mcp                  *MCPManager
+	processes            *ProcessManager
 	now                  func() time.Time
 	newID                func() string
 }
@@ -51,6 +54,31 @@ func (service *Service) SetRequirementsResolver(resolver ToolRequirementResolver
 	service.requirementsResolver = resolver
 }
 
+func (service *Service) SetMCPManager(manager *MCPManager) {
+	if service == nil {
+		return
+	}
+	service.mcp = manager
+}
+
+func (service *Service) SetProcessManager(manager *ProcessManager) {
+	if service == nil {
+		return
+	}
+	service.processes = manager
+}
+
+// MountMCPServers connects the assistant's MCP servers so their tools appear
+// in ListTools before a run resolves its tool set. It returns the ids of
+// servers that were rejected because another assistant mounted the id with a
+// different configuration.
+func (service *Service) MountMCPServers(ctx context.Context, assistantID string, servers []assistant.AssistantMCPServer) []string {
+	if service == nil || service.mcp == nil {
+		return nil
+	}
+	return service.mcp.MountAssistantServers(ctx, assistantID, servers)
+}
+
 func (service *Service) ListTools(ctx context.Context) []tooldto.ToolSpec {
 	if service == nil || service.tools == nil {
 		return nil
@@ -102,7 +130,7 @@ func (service *Service) InvokeWithPolicy(ctx context.Context, request tooldto.To
 	if policyCtx.Source == "" {
 		policyCtx.Source = "runtime"
 	}
-	spec, decision, err := service.tools.Decide(ctx, "", toolName, policyCtx)
+	spec, decision, err := service.tools.Decide(withToolCall(ctx, request.Action, request.Args), "", toolName, policyCtx)
 	if err != nil {
 		return tooldto.ToolsInvokeResponse{}, err
 	}
@@ -151,7 +179,10 @@ func (service *Service) InvokeWithPolicy(ctx context.Context, request tooldto.To
 		if service.sandbox == nil {
 			return response, errors.New("sandbox_unavailable")
 		}
-		resolution, err := service.sandbox.Resolve(ctx, gatewaysandbox.ResolveRequest{RequiresSandbox: true})
+		resolution, err := service.sandbox.Resolve(ctx, gatewaysandbox.ResolveRequest{
+			RequiresSandbox: true,
+			WorkspacePath:   strings.TrimSpace(policyCtx.WorkspacePath),
+		})
 		if err != nil {
 			return response, err
 		}
@@ -162,6 +193,7 @@ func (service *Service) InvokeWithPolicy(ctx context.Context, request tooldto.To
 			}
 			return response, errors.New(reason)
 		}
+		ctx = withSandboxResolution(ctx, resolution)
 	}
 	invocation := tooldto.ToolInvocation{
 		ID:        service.newID(),
@@ -185,6 +217,7 @@ func (service *Service) CleanupRuntimeSession(_ context.Context, sessionKey stri
 		return
 	}
 	cleanupBrowserToolSessions(sessionKey)
+	service.processes.CleanupSession(sessionKey)
 }
 
 func (service *Service) auditDecision(ctx context.Context, spec tooldto.ToolSpec, decision tooldto.ToolPolicyDecision, policyCtx tooldto.ToolPolicyContext) {
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -9,6 +10,7 @@ import (
 	settingsdto "dreamcreator/internal/application/settings/dto"
 	tooldto "dreamcreator/internal/application/tools/dto"
 	toolservice "dreamcreator/internal/application/tools/service"
+	"dreamcreator/internal/infrastructure/toolpolicyrepo"
 )
 
 type testToolExecutor struct {
@@ -203,3 +205,95 @@ func TestInvokeWithPolicyInjectsSessionKeyIntoRuntimeContext(t *testing.T) {
 		t.Fatalf("expected runtime context session key %q, got %q", requestSessionKey, executor.sessionKey)
 	}
 }
+
+type staticRuleEvaluator []toolpolicyrepo.PolicyRule
+
+func (rules staticRuleEvaluator) Evaluate(_ context.Context, call toolpolicyrepo.ToolCall) (toolpolicyrepo.Verdict, error) {
+	return toolpolicyrepo.EvaluateRules(rules, call), nil
+}
+
+func TestInvokeWithPolicyAppliesArgumentRules(t *testing.T) {
+	t.Parallel()
+
+	settings := gatewayToolSettingsStub{
+		settings: settingsdto.Settings{
+			Gateway: settingsdto.GatewaySettings{ControlPlaneEnabled: true},
+			Tools:   map[string]any{},
+		},
+	}
+	pipeline := NewPolicyPipeline(settings)
+	pipeline.SetRuleEvaluator(staticRuleEvaluator{
+		{ID: "git-read", Tool: "shell_tool", Decision: toolpolicyrepo.RuleDecisionAllow, CommandPrefixes: []string{"git status"}, Enabled: true},
+		{ID: "no-rm", Tool: "shell_tool", Decision: toolpolicyrepo.RuleDecisionDeny, CommandPrefixes: []string{"rm"}, Reason: "rm is blocked", Enabled: true},
+		{ID: "workspace-secrets", Decision: toolpolicyrepo.RuleDecisionDeny, PathGlobs: []string{"/workspace/**/.env"}, Enabled: true},
+	})
+	tools := toolservice.NewToolService()
+	tools.SetPolicy(pipeline)
+	executor := &testToolExecutor{output: `{"ok":true}`}
+	tools.SetExecutor(executor)
+	if _, err := tools.RegisterTool(context.Background(), tooldto.RegisterToolRequest{
+		Spec: tooldto.ToolSpec{
+			ID:               "shell_tool",
+			Name:             "shell_tool",
+			Enabled:          true,
+			RiskLevel:        "low",
+			RequiresApproval: true,
+		},
+	}); err != nil {
+		t.Fatalf("register shell_tool: %v", err)
+	}
+
+	service := NewService(tools, nil, nil, settings, nil, nil)
+	policyCtx := tooldto.ToolPolicyContext{SessionKey: "session-1", WorkspacePath: "/workspace"}
+	response, err := service.InvokeWithPolicy(context.Background(), tooldto.ToolsInvokeRequest{
+		Tool: "shell_tool",
+		Args: `{"command":"git status --short"}`,
+	}, policyCtx)
+	if err != nil {
+		t.Fatalf("invoke allowed command: %v", err)
+	}
+	if response.Policy.Decision != "allow" || !containsString(response.Policy.MatchedRules, "rule:git-read") {
+		t.Fatalf("expected the allow rule to waive approval, got %#v", response.Policy)
+	}
+
+	response, err = service.InvokeWithPolicy(context.Background(), tooldto.ToolsInvokeRequest{
+		Tool: "shell_tool",
+		Args: `{"command":"git status && rm -rf build"}`,
+	}, policyCtx)
+	if err == nil || err.Error() != "rm is blocked" || response.Policy.Decision != "deny" {
+		t.Fatalf("expected the deny rule to block the call, got %#v (%v)", response.Policy, err)
+	}
+
+	response, err = service.InvokeWithPolicy(context.Background(), tooldto.ToolsInvokeRequest{
+		Tool: "shell_tool",
+		Args: `{"path":"app/.env"}`,
+	}, policyCtx)
+	if err == nil || !containsString(response.Policy.MatchedRules, "rule:workspace-secrets") {
+		t.Fatalf("expected relative paths to resolve against the workspace, got %#v (%v)", response.Policy, err)
+	}
+
+	for _, patch := range []string{
+		"*** Begin Patch\n*** Update File: app/.env\n@@\n-A=1\n+A=2\n*** End Patch",
+		"diff --git a/app/.env b/app/.env\n--- a/app/.env\n+++ b/app/.env\n@@ -1 +1 @@\n-A=1\n+A=2\n",
+	} {
+		args, _ := json.Marshal(map[string]string{"patch": patch})
+		response, err = service.InvokeWithPolicy(context.Background(), tooldto.ToolsInvokeRequest{
+			Tool: "shell_tool",
+			Args: string(args),
+		}, policyCtx)
+		if err == nil || !containsString(response.Policy.MatchedRules, "rule:workspace-secrets") {
+			t.Fatalf("expected patch targets to be checked, got %#v (%v)", response.Policy, err)
+		}
+	}
+
+	response, err = service.InvokeWithPolicy(context.Background(), tooldto.ToolsInvokeRequest{
+		Tool: "shell_tool",
+		Args: `{"path":"app/.env"}`,
+	}, tooldto.ToolPolicyContext{SessionKey: "session-1"})
+	if err == nil || response.Policy.Decision != "ask" || !containsString(response.Policy.MatchedRules, "rule:workspace-secrets") {
+		t.Fatalf("expected unresolved relative paths to require approval, got %#v (%v)", response.Policy, err)
+	}
+	if executor.calls != 1 {
+		t.Fatalf("expected only the allowed call to execute, got %d", executor.calls)
+	}
+}
@@ -57,23 +57,44 @@ type LibraryImportOriginDTO struct {
 }
 
 type LibraryFileLineageDTO struct {
-	RootFileID string `json:"rootFileId,omitempty"`
+	RootFileID    string   `json:"rootFileId,omitempty"`
+	SourceFileIDs []string `json:"sourceFileIds,omitempty"`
 }
 
 type LibraryMediaInfoDTO struct {
-	Format      string   `json:"format,omitempty"`
-	Codec       string   `json:"codec,omitempty"`
-	VideoCodec  string   `json:"videoCodec,omitempty"`
-	AudioCodec  string   `json:"audioCodec,omitempty"`
-	DurationMs  *int64   `json:"durationMs,omitempty"`
-	Width       *int     `json:"width,omitempty"`
-	Height      *int     `json:"height,omitempty"`
-	FrameRate   *float64 `json:"frameRate,omitempty"`
-	BitrateKbps *int     `json:"bitrateKbps,omitempty"`
-	Channels    *int     `json:"channels,omitempty"`
-	SizeBytes   *int64   `json:"sizeBytes,omitempty"`
-	Language    string   `json:"language,omitempty"`
-	CueCount    *int     `json:"cueCount,omitempty"`
+	Format          string                          `json:"format,omitempty"`
+	Codec           string                          `json:"codec,omitempty"`
+	VideoCodec      string                          `json:"videoCodec,omitempty"`
+	AudioCodec      string                          `json:"audioCodec,omitempty"`
+	DurationMs      *int64                          `json:"durationMs,omitempty"`
+	Width           *int                            `json:"width,omitempty"`
+	Height          *int                            `json:"height,omitempty"`
+	FrameRate       *float64                        `json:"frameRate,omitempty"`
+	BitrateKbps     *int                            `json:"bitrateKbps,omitempty"`
+	Channels        *int                            `json:"channels,omitempty"`
+	SizeBytes       *int64                          `json:"sizeBytes,omitempty"`
+	Language        string                          `json:"language,omitempty"`
+	CueCount        *int                            `json:"cueCount,omitempty"`
+	LoudnessLUFS    *float64                        `json:"loudnessLufs,omitempty"`
+	Chapters        []LibraryMediaChapterDTO        `json:"chapters,omitempty"`
+	SubtitleStreams []LibraryMediaSubtitleStreamDTO `json:"subtitleStreams,omitempty"`
+	CoverFileID     string                          `json:"coverFileId,omitempty"`
+}
+
+type LibraryMediaSubtitleStreamDTO struct {
+	Index     int    `json:"index"`
+	Codec     string `json:"codec,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Default   bool   `json:"default,omitempty"`
+	Forced    bool   `json:"forced,omitempty"`
+	TextBased bool   `json:"textBased"`
+}
+
+type LibraryMediaChapterDTO struct {
+	StartMs int64  `json:"startMs"`
+	EndMs   int64  `json:"endMs,omitempty"`
+	Title   string `json:"title"`
 }
 
 type LibraryFileStateDTO struct {
@@ -170,6 +191,29 @@ type OperationListItemDTO struct {
 	StartedAt   string                   `json:"startedAt,omitempty"`
 	FinishedAt  string                   `json:"finishedAt,omitempty"`
 	CreatedAt   string                   `json:"createdAt"`
+	// Queue is set while a download or transcode operation waits for a free slot.
+	Queue *OperationQueueEntryDTO `json:"queue,omitempty"`
+}
+
+type OperationQueueEntryDTO struct {
+	Kind     string `json:"kind"`
+	Position int    `json:"position"`
+	Priority int    `json:"priority"`
+	Paused   bool   `json:"paused,omitempty"`
+}
+
+// OperationQueueStateDTO reports the slot usage of each queue kind.
+type OperationQueueStateDTO struct {
+	Paused    bool                    `json:"paused"`
+	RateLimit string                  `json:"rateLimit,omitempty"`
+	Kinds     []OperationQueueKindDTO `json:"kinds"`
+}
+
+type OperationQueueKindDTO struct {
+	Kind        string `json:"kind"`
+	Concurrency int    `json:"concurrency"`
+	Running     int    `json:"running"`
+	Waiting     int    `json:"waiting"`
 }
 
 type LibraryRecordsDTO struct {
@@ -221,6 +265,8 @@ type WorkspaceTrackTasksDTO struct {
 	Translate []WorkspaceTaskSummaryDTO `json:"translate,omitempty"`
 	Proofread *WorkspaceTaskSummaryDTO  `json:"proofread,omitempty"`
 	QA        *WorkspaceTaskSummaryDTO  `json:"qa,omitempty"`
+	Align     *WorkspaceTaskSummaryDTO  `json:"align,omitempty"`
+	Bilingual *WorkspaceTaskSummaryDTO  `json:"bilingual,omitempty"`
 }
 
 type WorkspaceTrackPendingReviewDTO struct {
@@ -335,7 +381,9 @@ type LibraryModuleConfigDTO struct {
 	TranslateLanguages LibraryTranslateLanguagesConfigDTO `json:"translateLanguages"`
 	LanguageAssets     LibraryLanguageAssetsConfigDTO     `json:"languageAssets"`
 	SubtitleStyles     LibrarySubtitleStyleConfigDTO      `json:"subtitleStyles"`
+	SubtitleDelivery   LibrarySubtitleDeliveryConfigDTO   `json:"subtitleDelivery"`
 	TaskRuntime        LibraryTaskRuntimeConfigDTO        `json:"taskRuntime"`
+	Queue              LibraryOperationQueueConfigDTO     `json:"queue"`
 }
 
 type LibraryWorkspaceConfigDTO struct {
@@ -382,6 +430,24 @@ type LibraryLanguageAssetsConfigDTO struct {
 	PromptProfiles   []LibraryPromptProfileDTO   `json:"promptProfiles"`
 }
 
+type LibraryDeliveryProfileDTO struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description,omitempty"`
+	MaxCPS          float64 `json:"maxCps"`
+	MaxCharsPerLine int     `json:"maxCharsPerLine"`
+	MaxLines        int     `json:"maxLines"`
+	MinGapMs        int64   `json:"minGapMs"`
+	MinDurationMs   int64   `json:"minDurationMs"`
+	MaxDurationMs   int64   `json:"maxDurationMs"`
+}
+
+type LibrarySubtitleDeliveryConfigDTO struct {
+	Builtin          []LibraryDeliveryProfileDTO `json:"builtin"`
+	Custom           []LibraryDeliveryProfileDTO `json:"custom"`
+	DefaultProfileID string                      `json:"defaultProfileId"`
+}
+
 type LibraryTaskRuntimeSettingsDTO struct {
 	StructuredOutputMode string `json:"structuredOutputMode"`
 	ThinkingMode         string `json:"thinkingMode"`
@@ -395,6 +461,12 @@ type LibraryTaskRuntimeConfigDTO struct {
 	Proofread LibraryTaskRuntimeSettingsDTO `json:"proofread"`
 }
 
+type LibraryOperationQueueConfigDTO struct {
+	DownloadConcurrency  int    `json:"downloadConcurrency"`
+	TranscodeConcurrency int    `json:"transcodeConcurrency"`
+	RateLimit            string `json:"rateLimit,omitempty"`
+}
+
 type LibrarySubtitleStyleDefaultsDTO struct {
 	MonoStyleID            string `json:"monoStyleId,omitempty"`
 	BilingualStyleID       string `json:"bilingualStyleId,omitempty"`
@@ -572,6 +644,15 @@ type CancelOperationRequest struct {
 	OperationID string `json:"operationId"`
 }
 
+type SetOperationQueuePausedRequest struct {
+	Paused bool `json:"paused"`
+}
+
+type SetOperationPriorityRequest struct {
+	OperationID string `json:"operationId"`
+	Priority    int    `json:"priority"`
+}
+
 type ResumeOperationRequest struct {
 	OperationID string `json:"operationId"`
 }
@@ -645,33 +726,43 @@ type OpenPathRequest struct {
 }
 
 type CreateYTDLPJobRequest struct {
-	URL                            string   `json:"url"`
-	LibraryID                      string   `json:"libraryId,omitempty"`
-	Title                          string   `json:"title"`
-	Extractor                      string   `json:"extractor,omitempty"`
-	Author                         string   `json:"author,omitempty"`
-	ThumbnailURL                   string   `json:"thumbnailUrl,omitempty"`
-	WriteThumbnail                 bool     `json:"writeThumbnail,omitempty"`
-	CookiesPath                    string   `json:"cookiesPath,omitempty"`
-	Source                         string   `json:"source,omitempty"`
-	Caller                         string   `json:"caller,omitempty"`
-	SessionKey                     string   `json:"sessionKey,omitempty"`
-	RunID                          string   `json:"runId,omitempty"`
-	RetryOf                        string   `json:"retryOf,omitempty"`
-	RetryCount                     int      `json:"retryCount,omitempty"`
-	Mode                           string   `json:"mode,omitempty"`
-	LogPolicy                      string   `json:"logPolicy,omitempty"`
-	Quality                        string   `json:"quality,omitempty"`
-	FormatID                       string   `json:"formatId,omitempty"`
-	AudioFormatID                  string   `json:"audioFormatId,omitempty"`
-	SubtitleLangs                  []string `json:"subtitleLangs,omitempty"`
-	SubtitleAuto                   bool     `json:"subtitleAuto,omitempty"`
-	SubtitleAll                    bool     `json:"subtitleAll,omitempty"`
-	SubtitleFormat                 string   `json:"subtitleFormat,omitempty"`
-	TranscodePresetID              string   `json:"transcodePresetId,omitempty"`
-	DeleteSourceFileAfterTranscode bool     `json:"deleteSourceFileAfterTranscode,omitempty"`
-	ConnectorID                    string   `json:"connectorId,omitempty"`
-	UseConnector                   bool     `json:"useConnector,omitempty"`
+	URL                            string                 `json:"url"`
+	LibraryID                      string                 `json:"libraryId,omitempty"`
+	Title                          string                 `json:"title"`
+	Extractor                      string                 `json:"extractor,omitempty"`
+	Author                         string                 `json:"author,omitempty"`
+	ThumbnailURL                   string                 `json:"thumbnailUrl,omitempty"`
+	WriteThumbnail                 bool                   `json:"writeThumbnail,omitempty"`
+	CookiesPath                    string                 `json:"cookiesPath,omitempty"`
+	Source                         string                 `json:"source,omitempty"`
+	Caller                         string                 `json:"caller,omitempty"`
+	SessionKey                     string                 `json:"sessionKey,omitempty"`
+	RunID                          string                 `json:"runId,omitempty"`
+	RetryOf                        string                 `json:"retryOf,omitempty"`
+	RetryCount                     int                    `json:"retryCount,omitempty"`
+	Mode                           string                 `json:"mode,omitempty"`
+	LogPolicy                      string                 `json:"logPolicy,omitempty"`
+	Quality                        string                 `json:"quality,omitempty"`
+	FormatID                       string                 `json:"formatId,omitempty"`
+	AudioFormatID                  string                 `json:"audioFormatId,omitempty"`
+	SubtitleLangs                  []string               `json:"subtitleLangs,omitempty"`
+	SubtitleAuto                   bool                   `json:"subtitleAuto,omitempty"`
+	SubtitleAll                    bool                   `json:"subtitleAll,omitempty"`
+	SubtitleFormat                 string                 `json:"subtitleFormat,omitempty"`
+	TranscodePresetID              string                 `json:"transcodePresetId,omitempty"`
+	DeleteSourceFileAfterTranscode bool                   `json:"deleteSourceFileAfterTranscode,omitempty"`
+	ConnectorID                    string                 `json:"connectorId,omitempty"`
+	UseConnector                   bool                   `json:"useConnector,omitempty"`
+	PlaylistItems                  string                 `json:"playlistItems,omitempty"`
+	DownloadSections               []YTDLPDownloadSection `json:"downloadSections,omitempty"`
+	Priority                       int                    `json:"priority,omitempty"`
+}
+
+// YTDLPDownloadSection is a time range passed to yt-dlp --download-sections. An
+// EndMs of zero downloads to the end of the media.
+type YTDLPDownloadSection struct {
+	StartMs int64 `json:"startMs"`
+	EndMs   int64 `json:"endMs,omitempty"`
 }
 
 type CheckYTDLPOperationFailureRequest struct {
@@ -721,6 +812,7 @@ type PrepareYTDLPDownloadResponse struct {
 	ConnectorID        string `json:"connectorId,omitempty"`
 	ConnectorAvailable bool   `json:"connectorAvailable"`
 	Reachable          bool   `json:"reachable,omitempty"`
+	Playlist           bool   `json:"playlist,omitempty"`
 }
 
 type ResolveDomainIconRequest struct {
@@ -733,9 +825,11 @@ type ResolveDomainIconResponse struct {
 }
 
 type ParseYTDLPDownloadRequest struct {
-	URL          string `json:"url"`
-	ConnectorID  string `json:"connectorId,omitempty"`
-	UseConnector bool   `json:"useConnector,omitempty"`
+	URL           string `json:"url"`
+	ConnectorID   string `json:"connectorId,omitempty"`
+	UseConnector  bool   `json:"useConnector,omitempty"`
+	Playlist      bool   `json:"playlist,omitempty"`
+	PlaylistLimit int    `json:"playlistLimit,omitempty"`
 }
 
 type YTDLPFormatOption struct {
@@ -766,6 +860,101 @@ type ParseYTDLPDownloadResponse struct {
 	ThumbnailURL string                `json:"thumbnailUrl,omitempty"`
 	Formats      []YTDLPFormatOption   `json:"formats"`
 	Subtitles    []YTDLPSubtitleOption `json:"subtitles"`
+	Playlist     *YTDLPPlaylistInfo    `json:"playlist,omitempty"`
+}
+
+type YTDLPPlaylistEntry struct {
+	Index           int     `json:"index"`
+	ID              string  `json:"id,omitempty"`
+	URL             string  `json:"url"`
+	Title           string  `json:"title,omitempty"`
+	Author          string  `json:"author,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	ThumbnailURL    string  `json:"thumbnailUrl,omitempty"`
+}
+
+type YTDLPPlaylistInfo struct {
+	ID         string               `json:"id,omitempty"`
+	Kind       string               `json:"kind"`
+	Title      string               `json:"title,omitempty"`
+	Author     string               `json:"author,omitempty"`
+	URL        string               `json:"url"`
+	EntryCount int                  `json:"entryCount"`
+	Truncated  bool                 `json:"truncated,omitempty"`
+	Entries    []YTDLPPlaylistEntry `json:"entries"`
+}
+
+// CreateYTDLPPlaylistJobRequest downloads the chosen playlist entries as child
+// download operations that share one set of format, subtitle and transcode settings.
+type CreateYTDLPPlaylistJobRequest struct {
+	URL                            string               `json:"url"`
+	LibraryID                      string               `json:"libraryId,omitempty"`
+	Title                          string               `json:"title"`
+	Extractor                      string               `json:"extractor,omitempty"`
+	Author                         string               `json:"author,omitempty"`
+	ThumbnailURL                   string               `json:"thumbnailUrl,omitempty"`
+	Entries                        []YTDLPPlaylistEntry `json:"entries"`
+	MaxConcurrency                 int                  `json:"maxConcurrency,omitempty"`
+	WriteThumbnail                 bool                 `json:"writeThumbnail,omitempty"`
+	Quality                        string               `json:"quality,omitempty"`
+	FormatID                       string               `json:"formatId,omitempty"`
+	SubtitleLangs                  []string             `json:"subtitleLangs,omitempty"`
+	SubtitleAuto                   bool                 `json:"subtitleAuto,omitempty"`
+	SubtitleAll                    bool                 `json:"subtitleAll,omitempty"`
+	SubtitleFormat                 string               `json:"subtitleFormat,omitempty"`
+	TranscodePresetID              string               `json:"transcodePresetId,omitempty"`
+	DeleteSourceFileAfterTranscode bool                 `json:"deleteSourceFileAfterTranscode,omitempty"`
+	ConnectorID                    string               `json:"connectorId,omitempty"`
+	UseConnector                   bool                 `json:"useConnector,omitempty"`
+	LogPolicy                      string               `json:"logPolicy,omitempty"`
+	Priority                       int                  `json:"priority,omitempty"`
+	Source                         string               `json:"source,omitempty"`
+	Caller                         string               `json:"caller,omitempty"`
+	SessionKey                     string               `json:"sessionKey,omitempty"`
+	RunID                          string               `json:"runId,omitempty"`
+}
+
+// LibrarySubscriptionDTO is a followed channel or playlist whose new entries are
+// downloaded with the stored format and transcode settings on every poll.
+type LibrarySubscriptionDTO struct {
+	ID                             string   `json:"id"`
+	Name                           string   `json:"name"`
+	URL                            string   `json:"url"`
+	LibraryID                      string   `json:"libraryId,omitempty"`
+	ConnectorID                    string   `json:"connectorId,omitempty"`
+	UseConnector                   bool     `json:"useConnector,omitempty"`
+	Quality                        string   `json:"quality,omitempty"`
+	FormatID                       string   `json:"formatId,omitempty"`
+	SubtitleLangs                  []string `json:"subtitleLangs,omitempty"`
+	SubtitleAuto                   bool     `json:"subtitleAuto,omitempty"`
+	SubtitleFormat                 string   `json:"subtitleFormat,omitempty"`
+	TranscodePresetID              string   `json:"transcodePresetId,omitempty"`
+	DeleteSourceFileAfterTranscode bool     `json:"deleteSourceFileAfterTranscode,omitempty"`
+	PollIntervalMinutes            int      `json:"pollIntervalMinutes"`
+	DownloadExisting               bool     `json:"downloadExisting,omitempty"`
+	Enabled                        bool     `json:"enabled"`
+	ArchivedCount                  int      `json:"archivedCount"`
+	LastCheckedAt                  string   `json:"lastCheckedAt,omitempty"`
+	LastError                      string   `json:"lastError,omitempty"`
+	CreatedAt                      string   `json:"createdAt,omitempty"`
+	UpdatedAt                      string   `json:"updatedAt,omitempty"`
+}
+
+type DeleteSubscriptionRequest struct {
+	ID string `json:"id"`
+}
+
+type PollSubscriptionRequest struct {
+	ID string `json:"id"`
+}
+
+type PollSubscriptionResult struct {
+	Subscription  LibrarySubscriptionDTO `json:"subscription"`
+	EntryCount    int                    `json:"entryCount"`
+	NewEntryCount int                    `json:"newEntryCount"`
+	SeededCount   int                    `json:"seededCount,omitempty"`
+	OperationIDs  []string               `json:"operationIds"`
+	Skipped       bool                   `json:"skipped,omitempty"`
 }
 
 type CreateSubtitleImportRequest struct {
@@ -778,12 +967,13 @@ type CreateSubtitleImportRequest struct {
 }
 
 type CreateVideoImportRequest struct {
-	Path       string `json:"path"`
-	LibraryID  string `json:"libraryId,omitempty"`
-	Title      string `json:"title"`
-	Source     string `json:"source,omitempty"`
-	SessionKey string `json:"sessionKey,omitempty"`
-	RunID      string `json:"runId,omitempty"`
+	Path             string `json:"path"`
+	LibraryID        string `json:"libraryId,omitempty"`
+	Title            string `json:"title"`
+	ExtractSubtitles bool   `json:"extractSubtitles,omitempty"`
+	Source           string `json:"source,omitempty"`
+	SessionKey       string `json:"sessionKey,omitempty"`
+	RunID            string `json:"runId,omitempty"`
 }
 
 type CreateTranscodeJobRequest struct {
@@ -807,6 +997,11 @@ type CreateTranscodeJobRequest struct {
 	Scale                                 string            `json:"scale,omitempty"`
 	Width                                 int               `json:"width,omitempty"`
 	Height                                int               `json:"height,omitempty"`
+	AspectRatio                           string            `json:"aspectRatio,omitempty"`
+	ReframeMode                           string            `json:"reframeMode,omitempty"`
+	MaxBitrateKbps                        int               `json:"maxBitrateKbps,omitempty"`
+	MaxFileSizeMB                         int               `json:"maxFileSizeMb,omitempty"`
+	VariantPresetIDs                      []string          `json:"variantPresetIds,omitempty"`
 	SubtitleHandling                      string            `json:"subtitleHandling,omitempty"`
 	SubtitleFileID                        string            `json:"subtitleFileId,omitempty"`
 	SecondarySubtitleFileID               string            `json:"secondarySubtitleFileId,omitempty"`
@@ -818,6 +1013,90 @@ type CreateTranscodeJobRequest struct {
 	GeneratedSubtitleDocument             *SubtitleDocument `json:"generatedSubtitleDocument,omitempty"`
 	GeneratedSubtitleContent              string            `json:"generatedSubtitleContent,omitempty"`
 	DeleteSourceFileAfterTranscode        bool              `json:"deleteSourceFileAfterTranscode,omitempty"`
+	EmbedCover                            bool              `json:"embedCover,omitempty"`
+	Priority                              int               `json:"priority,omitempty"`
+}
+
+// CreateClipJobRequest cuts [StartMs, EndMs) out of a library video or audio file.
+// Stream copy is used unless Reencode is set, so copied cuts snap to keyframes.
+type CreateClipJobRequest struct {
+	FileID          string `json:"fileId"`
+	StartMs         int64  `json:"startMs"`
+	EndMs           int64  `json:"endMs"`
+	Title           string `json:"title,omitempty"`
+	Reencode        bool   `json:"reencode,omitempty"`
+	IncludeSubtitle bool   `json:"includeSubtitle,omitempty"`
+	SubtitleFileID  string `json:"subtitleFileId,omitempty"`
+	Source          string `json:"source,omitempty"`
+	SessionKey      string `json:"sessionKey,omitempty"`
+	RunID           string `json:"runId,omitempty"`
+	Priority        int    `json:"priority,omitempty"`
+}
+
+// UpdateFileChaptersRequest replaces the chapters stored on a media file. When
+// Timestamps is set it is parsed as YouTube description timestamps instead.
+type UpdateFileChaptersRequest struct {
+	FileID     string                   `json:"fileId"`
+	Chapters   []LibraryMediaChapterDTO `json:"chapters,omitempty"`
+	Timestamps string                   `json:"timestamps,omitempty"`
+}
+
+type ExportFileChaptersRequest struct {
+	FileID string `json:"fileId"`
+	Format string `json:"format"`
+}
+
+type ExportFileChaptersResult struct {
+	FileID       string `json:"fileId"`
+	Format       string `json:"format"`
+	Content      string `json:"content"`
+	ChapterCount int    `json:"chapterCount"`
+}
+
+// CreateChapterSplitJobRequest writes one file per chapter of a library video or
+// audio file. Stream copy is used unless Reencode is set.
+type CreateChapterSplitJobRequest struct {
+	FileID     string `json:"fileId"`
+	Reencode   bool   `json:"reencode,omitempty"`
+	Source     string `json:"source,omitempty"`
+	SessionKey string `json:"sessionKey,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+	Priority   int    `json:"priority,omitempty"`
+}
+
+// CreateSubtitleExtractJobRequest converts embedded text subtitle streams of a
+// library video into subtitle files. An empty StreamIndexes extracts every text
+// stream; OutputFormat defaults to ass for ASS/SSA streams and srt otherwise.
+type CreateSubtitleExtractJobRequest struct {
+	FileID        string `json:"fileId"`
+	StreamIndexes []int  `json:"streamIndexes,omitempty"`
+	OutputFormat  string `json:"outputFormat,omitempty"`
+	Source        string `json:"source,omitempty"`
+	SessionKey    string `json:"sessionKey,omitempty"`
+	RunID         string `json:"runId,omitempty"`
+	Priority      int    `json:"priority,omitempty"`
+}
+
+// CreateThumbnailJobRequest extracts candidate frames at scene changes of a library
+// video, builds a contact sheet from them and sets one frame as the file cover.
+// CoverIndex picks a candidate (1-based); 0 picks the most detailed frame.
+type CreateThumbnailJobRequest struct {
+	FileID         string  `json:"fileId"`
+	Count          int     `json:"count,omitempty"`
+	SceneThreshold float64 `json:"sceneThreshold,omitempty"`
+	Columns        int     `json:"columns,omitempty"`
+	CoverIndex     int     `json:"coverIndex,omitempty"`
+	Source         string  `json:"source,omitempty"`
+	SessionKey     string  `json:"sessionKey,omitempty"`
+	RunID          string  `json:"runId,omitempty"`
+	Priority       int     `json:"priority,omitempty"`
+}
+
+// SetFileCoverRequest sets a thumbnail of the same library as the cover of a video
+// or audio file. An empty CoverFileID clears the cover.
+type SetFileCoverRequest struct {
+	FileID      string `json:"fileId"`
+	CoverFileID string `json:"coverFileId,omitempty"`
 }
 
 type ListTranscodePresetsForDownloadRequest struct {
@@ -843,8 +1122,22 @@ type TranscodePreset struct {
 	RequiresVideo    bool   `json:"requiresVideo,omitempty"`
 	RequiresAudio    bool   `json:"requiresAudio,omitempty"`
 	IsBuiltin        bool   `json:"isBuiltin,omitempty"`
-	CreatedAt        string `json:"createdAt,omitempty"`
-	UpdatedAt        string `json:"updatedAt,omitempty"`
+	// AspectRatio reframes video output to 16:9, 9:16, 1:1, 4:5 or 3:4 with the
+	// crop, pad or blur ReframeMode. The caps bound the encoded video bitrate.
+	AspectRatio    string `json:"aspectRatio,omitempty"`
+	ReframeMode    string `json:"reframeMode,omitempty"`
+	MaxBitrateKbps int    `json:"maxBitrateKbps,omitempty"`
+	MaxFileSizeMB  int    `json:"maxFileSizeMb,omitempty"`
+	// Audio processing. Loudness targets are LUFS, dBTP and LU; zero selects the
+	// EBU R128 defaults.
+	LoudnessNormalize bool    `json:"loudnessNormalize,omitempty"`
+	LoudnessTargetI   float64 `json:"loudnessTargetI,omitempty"`
+	LoudnessTruePeak  float64 `json:"loudnessTruePeak,omitempty"`
+	LoudnessRange     float64 `json:"loudnessRange,omitempty"`
+	NoiseReduction    bool    `json:"noiseReduction,omitempty"`
+	TrimSilence       bool    `json:"trimSilence,omitempty"`
+	CreatedAt         string  `json:"createdAt,omitempty"`
+	UpdatedAt         string  `json:"updatedAt,omitempty"`
 }
 
 type DeleteTranscodePresetRequest struct {
@@ -49,6 +49,9 @@ type SubtitleExportConfig struct {
 	ASS    *SubtitleASSExportConfig    `json:"ass,omitempty"`
 	ITT    *SubtitleITTExportConfig    `json:"itt,omitempty"`
 	FCPXML *SubtitleFCPXMLExportConfig `json:"fcpxml,omitempty"`
+	TTML   *SubtitleTTMLExportConfig   `json:"ttml,omitempty"`
+	SUB    *SubtitleSUBExportConfig    `json:"sub,omitempty"`
+	STL    *SubtitleSTLExportConfig    `json:"stl,omitempty"`
 }
 
 type SubtitleSRTExportConfig struct {
@@ -85,6 +88,20 @@ type SubtitleFCPXMLExportConfig struct {
 	StartTimecodeSeconds int64  `json:"startTimecodeSeconds,omitempty"`
 }
 
+type SubtitleTTMLExportConfig struct {
+	Language string `json:"language,omitempty"`
+}
+
+type SubtitleSUBExportConfig struct {
+	FrameRate float64 `json:"frameRate,omitempty"`
+}
+
+type SubtitleSTLExportConfig struct {
+	FrameRate int    `json:"frameRate,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
 type SubtitleExportRequest struct {
 	ExportPath           string                `json:"exportPath"`
 	FileID               string                `json:"fileId,omitempty"`
@@ -105,12 +122,14 @@ type SubtitleExportResult struct {
 }
 
 type SubtitleValidateRequest struct {
-	FileID     string            `json:"fileId,omitempty"`
-	DocumentID string            `json:"documentId,omitempty"`
-	Path       string            `json:"path,omitempty"`
-	Content    string            `json:"content,omitempty"`
-	Format     string            `json:"format,omitempty"`
-	Document   *SubtitleDocument `json:"document,omitempty"`
+	FileID            string                     `json:"fileId,omitempty"`
+	DocumentID        string                     `json:"documentId,omitempty"`
+	Path              string                     `json:"path,omitempty"`
+	Content           string                     `json:"content,omitempty"`
+	Format            string                     `json:"format,omitempty"`
+	Document          *SubtitleDocument          `json:"document,omitempty"`
+	DeliveryProfileID string                     `json:"deliveryProfileId,omitempty"`
+	DeliveryProfile   *LibraryDeliveryProfileDTO `json:"deliveryProfile,omitempty"`
 }
 
 type SubtitleValidateIssue struct {
@@ -121,19 +140,26 @@ type SubtitleValidateIssue struct {
 }
 
 type SubtitleValidateResult struct {
-	Valid      bool                    `json:"valid"`
-	IssueCount int                     `json:"issueCount"`
-	Issues     []SubtitleValidateIssue `json:"issues,omitempty"`
+	Valid             bool                    `json:"valid"`
+	IssueCount        int                     `json:"issueCount"`
+	Issues            []SubtitleValidateIssue `json:"issues,omitempty"`
+	DeliveryProfileID string                  `json:"deliveryProfileId,omitempty"`
 }
 
 type SubtitleReviewSuggestionDTO struct {
-	CueIndex      int      `json:"cueIndex"`
-	OriginalText  string   `json:"originalText"`
-	SuggestedText string   `json:"suggestedText"`
-	Categories    []string `json:"categories,omitempty"`
-	Reason        string   `json:"reason,omitempty"`
-	SourceCode    string   `json:"sourceCode,omitempty"`
-	Severity      string   `json:"severity,omitempty"`
+	CueIndex         int           `json:"cueIndex"`
+	OriginalText     string        `json:"originalText"`
+	SuggestedText    string        `json:"suggestedText"`
+	OriginalStart    string        `json:"originalStart,omitempty"`
+	OriginalEnd      string        `json:"originalEnd,omitempty"`
+	SuggestedStart   string        `json:"suggestedStart,omitempty"`
+	SuggestedEnd     string        `json:"suggestedEnd,omitempty"`
+	SuggestedCues    []SubtitleCue `json:"suggestedCues,omitempty"`
+	MergedCueIndexes []int         `json:"mergedCueIndexes,omitempty"`
+	Categories       []string      `json:"categories,omitempty"`
+	Reason           string        `json:"reason,omitempty"`
+	SourceCode       string        `json:"sourceCode,omitempty"`
+	Severity         string        `json:"severity,omitempty"`
 }
 
 type SubtitleReviewCueDecisionDTO struct {
@@ -192,6 +218,8 @@ type SubtitleSaveRequest struct {
 	TargetFormat string            `json:"targetFormat,omitempty"`
 	Content      string            `json:"content,omitempty"`
 	Document     *SubtitleDocument `json:"document,omitempty"`
+	SessionKey   string            `json:"sessionKey,omitempty"`
+	RunID        string            `json:"runId,omitempty"`
 }
 
 type SubtitleSaveResult struct {
@@ -200,23 +228,63 @@ type SubtitleSaveResult struct {
 	Bytes  int    `json:"bytes"`
 }
 
+type SubtitleRetimeAnchor struct {
+	CueIndex int   `json:"cueIndex,omitempty"`
+	SourceMs int64 `json:"sourceMs,omitempty"`
+	TargetMs int64 `json:"targetMs"`
+}
+
+type SubtitleRetimeRange struct {
+	StartMs  int64 `json:"startMs"`
+	EndMs    int64 `json:"endMs,omitempty"`
+	OffsetMs int64 `json:"offsetMs"`
+}
+
+type SubtitleRetimeRequest struct {
+	FileID     string                 `json:"fileId,omitempty"`
+	DocumentID string                 `json:"documentId,omitempty"`
+	Path       string                 `json:"path,omitempty"`
+	Content    string                 `json:"content,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	Document   *SubtitleDocument      `json:"document,omitempty"`
+	Mode       string                 `json:"mode"`
+	OffsetMs   int64                  `json:"offsetMs,omitempty"`
+	Anchors    []SubtitleRetimeAnchor `json:"anchors,omitempty"`
+	FromFPS    float64                `json:"fromFps,omitempty"`
+	ToFPS      float64                `json:"toFps,omitempty"`
+	Ranges     []SubtitleRetimeRange  `json:"ranges,omitempty"`
+	DryRun     bool                   `json:"dryRun,omitempty"`
+}
+
+type SubtitleRetimeResult struct {
+	FileID           string           `json:"fileId,omitempty"`
+	Format           string           `json:"format"`
+	Content          string           `json:"content"`
+	CueCount         int              `json:"cueCount"`
+	ChangedCueCount  int              `json:"changedCueCount"`
+	SourceRevisionID string           `json:"sourceRevisionId,omitempty"`
+	RevisionID       string           `json:"revisionId,omitempty"`
+	Document         SubtitleDocument `json:"document"`
+}
+
 type SubtitleTranslateRequest struct {
-	FileID                string   `json:"fileId,omitempty"`
-	DocumentID            string   `json:"documentId,omitempty"`
-	Path                  string   `json:"path,omitempty"`
-	LibraryID             string   `json:"libraryId,omitempty"`
-	RootFileID            string   `json:"rootFileId,omitempty"`
-	AssistantID           string   `json:"assistantId,omitempty"`
-	TargetLanguage        string   `json:"targetLanguage"`
-	OutputFormat          string   `json:"outputFormat,omitempty"`
-	Mode                  string   `json:"mode,omitempty"`
-	Source                string   `json:"source,omitempty"`
-	GlossaryProfileIDs    []string `json:"glossaryProfileIds,omitempty"`
-	ReferenceTrackFileIDs []string `json:"referenceTrackFileIds,omitempty"`
-	PromptProfileIDs      []string `json:"promptProfileIds,omitempty"`
-	InlinePrompt          string   `json:"inlinePrompt,omitempty"`
-	SessionKey            string   `json:"sessionKey,omitempty"`
-	RunID                 string   `json:"runId,omitempty"`
+	FileID                   string   `json:"fileId,omitempty"`
+	DocumentID               string   `json:"documentId,omitempty"`
+	Path                     string   `json:"path,omitempty"`
+	LibraryID                string   `json:"libraryId,omitempty"`
+	RootFileID               string   `json:"rootFileId,omitempty"`
+	AssistantID              string   `json:"assistantId,omitempty"`
+	TargetLanguage           string   `json:"targetLanguage"`
+	OutputFormat             string   `json:"outputFormat,omitempty"`
+	Mode                     string   `json:"mode,omitempty"`
+	Source                   string   `json:"source,omitempty"`
+	GlossaryProfileIDs       []string `json:"glossaryProfileIds,omitempty"`
+	ReferenceTrackFileIDs    []string `json:"referenceTrackFileIds,omitempty"`
+	PromptProfileIDs         []string `json:"promptProfileIds,omitempty"`
+	InlinePrompt             string   `json:"inlinePrompt,omitempty"`
+	DisableTranslationMemory bool     `json:"disableTranslationMemory,omitempty"`
+	SessionKey               string   `json:"sessionKey,omitempty"`
+	RunID                    string   `json:"runId,omitempty"`
 }
 
 type SubtitleProofreadRequest struct {
@@ -247,10 +315,136 @@ type SubtitleQAReviewRequest struct {
 	OutputFormat        string `json:"outputFormat,omitempty"`
 	Source              string `json:"source,omitempty"`
 	NormalizeWhitespace bool   `json:"normalizeWhitespace,omitempty"`
+	DeliveryProfileID   string `json:"deliveryProfileId,omitempty"`
+	DeliveryAutoFix     bool   `json:"deliveryAutoFix,omitempty"`
 	SessionKey          string `json:"sessionKey,omitempty"`
 	RunID               string `json:"runId,omitempty"`
 }
 
+type SubtitleTranscribeRequest struct {
+	FileID       string `json:"fileId"`
+	LibraryID    string `json:"libraryId,omitempty"`
+	Language     string `json:"language,omitempty"`
+	ModelPath    string `json:"modelPath,omitempty"`
+	OutputFormat string `json:"outputFormat,omitempty"`
+	Prompt       string `json:"prompt,omitempty"`
+	Threads      int    `json:"threads,omitempty"`
+	Source       string `json:"source,omitempty"`
+	SessionKey   string `json:"sessionKey,omitempty"`
+	RunID        string `json:"runId,omitempty"`
+}
+
+type SubtitleOCRRequest struct {
+	FileID           string  `json:"fileId"`
+	LibraryID        string  `json:"libraryId,omitempty"`
+	Language         string  `json:"language,omitempty"`
+	SampleIntervalMs int64   `json:"sampleIntervalMs,omitempty"`
+	RegionTop        float64 `json:"regionTop,omitempty"`
+	RegionHeight     float64 `json:"regionHeight,omitempty"`
+	OutputFormat     string  `json:"outputFormat,omitempty"`
+	Threads          int     `json:"threads,omitempty"`
+	Source           string  `json:"source,omitempty"`
+	SessionKey       string  `json:"sessionKey,omitempty"`
+	RunID            string  `json:"runId,omitempty"`
+}
+
+type SubtitleAlignRequest struct {
+	FileID         string  `json:"fileId,omitempty"`
+	DocumentID     string  `json:"documentId,omitempty"`
+	Path           string  `json:"path,omitempty"`
+	LibraryID      string  `json:"libraryId,omitempty"`
+	VideoFileID    string  `json:"videoFileId,omitempty"`
+	MaxOffsetMs    int64   `json:"maxOffsetMs,omitempty"`
+	NoiseThreshold float64 `json:"noiseThresholdDb,omitempty"`
+	MinSilenceMs   int64   `json:"minSilenceMs,omitempty"`
+	MinChangeMs    int64   `json:"minChangeMs,omitempty"`
+	Source         string  `json:"source,omitempty"`
+	SessionKey     string  `json:"sessionKey,omitempty"`
+	RunID          string  `json:"runId,omitempty"`
+}
+
+type SubtitleBilingualMergeRequest struct {
+	LibraryID       string  `json:"libraryId,omitempty"`
+	PrimaryFileID   string  `json:"primaryFileId"`
+	SecondaryFileID string  `json:"secondaryFileId"`
+	OutputFormat    string  `json:"outputFormat,omitempty"`
+	MinOverlapRatio float64 `json:"minOverlapRatio,omitempty"`
+	UnmatchedMode   string  `json:"unmatchedMode,omitempty"`
+	Source          string  `json:"source,omitempty"`
+	SessionKey      string  `json:"sessionKey,omitempty"`
+	RunID           string  `json:"runId,omitempty"`
+}
+
+type SubtitleBilingualSplitRequest struct {
+	FileID            string `json:"fileId,omitempty"`
+	DocumentID        string `json:"documentId,omitempty"`
+	Path              string `json:"path,omitempty"`
+	LibraryID         string `json:"libraryId,omitempty"`
+	SplitMode         string `json:"splitMode,omitempty"`
+	PrimaryLanguage   string `json:"primaryLanguage,omitempty"`
+	SecondaryLanguage string `json:"secondaryLanguage,omitempty"`
+	OutputFormat      string `json:"outputFormat,omitempty"`
+	Source            string `json:"source,omitempty"`
+	SessionKey        string `json:"sessionKey,omitempty"`
+	RunID             string `json:"runId,omitempty"`
+}
+
+type SubtitleGlossaryExtractRequest struct {
+	FileIDs           []string `json:"fileIds,omitempty"`
+	LibraryID         string   `json:"libraryId,omitempty"`
+	AssistantID       string   `json:"assistantId,omitempty"`
+	SourceLanguage    string   `json:"sourceLanguage,omitempty"`
+	TargetLanguage    string   `json:"targetLanguage"`
+	GlossaryProfileID string   `json:"glossaryProfileId,omitempty"`
+	MinOccurrences    int      `json:"minOccurrences,omitempty"`
+	MaxCandidates     int      `json:"maxCandidates,omitempty"`
+	Source            string   `json:"source,omitempty"`
+	SessionKey        string   `json:"sessionKey,omitempty"`
+	RunID             string   `json:"runId,omitempty"`
+}
+
+// SubtitlePipelineStep is one stage of a batch subtitle pipeline. Translate fans a
+// track out per target language, and later steps run on the translated tracks.
+// AutoApply accepts proofread or QA suggestions before the next step; otherwise
+// their review sessions stay pending.
+type SubtitlePipelineStep struct {
+	Kind            string                    `json:"kind"`
+	TargetLanguages []string                  `json:"targetLanguages,omitempty"`
+	AutoApply       bool                      `json:"autoApply,omitempty"`
+	ExportDirectory string                    `json:"exportDirectory,omitempty"`
+	ExportFormat    string                    `json:"exportFormat,omitempty"`
+	ExportConfig    *SubtitleExportConfig     `json:"exportConfig,omitempty"`
+	Translate       *SubtitleTranslateRequest `json:"translate,omitempty"`
+	Proofread       *SubtitleProofreadRequest `json:"proofread,omitempty"`
+	QAReview        *SubtitleQAReviewRequest  `json:"qaReview,omitempty"`
+}
+
+type SubtitlePipelineRequest struct {
+	FileIDs        []string               `json:"fileIds,omitempty"`
+	LibraryID      string                 `json:"libraryId,omitempty"`
+	Steps          []SubtitlePipelineStep `json:"steps"`
+	MaxConcurrency int                    `json:"maxConcurrency,omitempty"`
+	Source         string                 `json:"source,omitempty"`
+	SessionKey     string                 `json:"sessionKey,omitempty"`
+	RunID          string                 `json:"runId,omitempty"`
+}
+
+type SubtitleGlossaryTermCandidateDTO struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Category    string `json:"category,omitempty"`
+	Note        string `json:"note,omitempty"`
+	Occurrences int    `json:"occurrences"`
+	Example     string `json:"example,omitempty"`
+}
+
+type AcceptGlossaryTermCandidatesRequest struct {
+	OperationID       string                   `json:"operationId,omitempty"`
+	GlossaryProfileID string                   `json:"glossaryProfileId,omitempty"`
+	ProfileName       string                   `json:"profileName,omitempty"`
+	Terms             []LibraryGlossaryTermDTO `json:"terms,omitempty"`
+}
+
 type RestoreSubtitleOriginalRequest struct {
 	FileID     string `json:"fileId,omitempty"`
 	DocumentID string `json:"documentId,omitempty"`
@@ -262,3 +456,75 @@ type RestoreSubtitleOriginalResult struct {
 	Format string `json:"format"`
 	Bytes  int    `json:"bytes"`
 }
+
+type ListSubtitleRevisionsRequest struct {
+	FileID     string `json:"fileId,omitempty"`
+	DocumentID string `json:"documentId,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+type SubtitleRevisionDTO struct {
+	ID              string `json:"id"`
+	LibraryID       string `json:"libraryId"`
+	FileID          string `json:"fileId"`
+	Format          string `json:"format"`
+	SourceKind      string `json:"sourceKind"`
+	Actor           string `json:"actor"`
+	Candidate       bool   `json:"candidate"`
+	Current         bool   `json:"current"`
+	OperationID     string `json:"operationId,omitempty"`
+	OperationKind   string `json:"operationKind,omitempty"`
+	OperationName   string `json:"operationName,omitempty"`
+	ReviewSessionID string `json:"reviewSessionId,omitempty"`
+	CueCount        int    `json:"cueCount"`
+	Bytes           int    `json:"bytes"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+type DiffSubtitleRevisionsRequest struct {
+	FileID           string `json:"fileId,omitempty"`
+	FromRevisionID   string `json:"fromRevisionId"`
+	ToRevisionID     string `json:"toRevisionId,omitempty"`
+	IncludeUnchanged bool   `json:"includeUnchanged,omitempty"`
+}
+
+type SubtitleRevisionDiffCueDTO struct {
+	Status        string `json:"status"`
+	FromIndex     int    `json:"fromIndex,omitempty"`
+	ToIndex       int    `json:"toIndex,omitempty"`
+	FromStart     string `json:"fromStart,omitempty"`
+	FromEnd       string `json:"fromEnd,omitempty"`
+	ToStart       string `json:"toStart,omitempty"`
+	ToEnd         string `json:"toEnd,omitempty"`
+	FromText      string `json:"fromText,omitempty"`
+	ToText        string `json:"toText,omitempty"`
+	TextChanged   bool   `json:"textChanged,omitempty"`
+	TimingChanged bool   `json:"timingChanged,omitempty"`
+}
+
+type SubtitleRevisionDiffResult struct {
+	FileID         string                       `json:"fileId"`
+	FromRevisionID string                       `json:"fromRevisionId"`
+	ToRevisionID   string                       `json:"toRevisionId"`
+	AddedCount     int                          `json:"addedCount"`
+	RemovedCount   int                          `json:"removedCount"`
+	ModifiedCount  int                          `json:"modifiedCount"`
+	UnchangedCount int                          `json:"unchangedCount"`
+	Cues           []SubtitleRevisionDiffCueDTO `json:"cues"`
+}
+
+type RestoreSubtitleRevisionRequest struct {
+	FileID     string `json:"fileId,omitempty"`
+	RevisionID string `json:"revisionId"`
+	SessionKey string `json:"sessionKey,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+}
+
+type RestoreSubtitleRevisionResult struct {
+	FileID             string `json:"fileId"`
+	RestoredRevisionID string `json:"restoredRevisionId"`
+	RevisionID         string `json:"revisionId"`
+	SnapshotRevisionID string `json:"snapshotRevisionId,omitempty"`
+	Format             string `json:"format"`
+	Bytes              int    `json:"bytes"`
+}
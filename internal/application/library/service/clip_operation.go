@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+type clipOperationOutput struct {
+	FileID                 string `json:"fileId,omitempty"`
+	ClipFileID             string `json:"clipFileId,omitempty"`
+	SubtitleFileID         string `json:"subtitleFileId,omitempty"`
+	ClipSubtitleFileID     string `json:"clipSubtitleFileId,omitempty"`
+	ClipSubtitleDocumentID string `json:"clipSubtitleDocumentId,omitempty"`
+	StartMs                int64  `json:"startMs"`
+	EndMs                  int64  `json:"endMs"`
+	Reencode               bool   `json:"reencode,omitempty"`
+	SubtitleCueCount       int    `json:"subtitleCueCount,omitempty"`
+	OutputPath             string `json:"outputPath,omitempty"`
+	Status                 string `json:"status"`
+}
+
+// CreateClipJob queues an ffmpeg cut of an existing library media file. The clip is
+// registered as a new file whose lineage points back to the source.
+func (service *LibraryService) CreateClipJob(ctx context.Context, request dto.CreateClipJobRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeClipRequest(request)
+	if err := validateClipRange(request.StartMs, request.EndMs); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	sourceFile, err := service.resolveClipSourceFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if request.SubtitleFileID != "" {
+		if _, err := service.resolveClipSubtitleFile(ctx, sourceFile, request.SubtitleFileID); err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+	}
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "clip",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: resolveClipTitle(request, sourceFile),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  buildClipOperationOutput(clipOperationOutput{FileID: sourceFile.ID, Status: "queued"}, request),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.clipQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runClipOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runClipOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateClipJobRequest) {
+	request = normalizeClipRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		service.unregisterOperationRun(operation.ID)
+		cancel()
+	}()
+
+	output := clipOperationOutput{FileID: request.FileID, Status: "running"}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.FinishedAt = nil
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		1,
+		progressText("library.progressDetail.clipPreparing"),
+	)
+	operation.OutputJSON = buildClipOperationOutput(output, request)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	sourceFile, err := service.resolveClipSourceFile(ctx, request.FileID)
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+	endMs := request.EndMs
+	if probe.DurationMs > 0 {
+		if request.StartMs >= probe.DurationMs {
+			service.failClipOperation(ctx, operation, request, fmt.Errorf("clip starts after the end of the media"))
+			return
+		}
+		if endMs > probe.DurationMs {
+			endMs = probe.DurationMs
+		}
+	}
+	var subtitleFile *library.LibraryFile
+	if request.IncludeSubtitle || request.SubtitleFileID != "" {
+		resolved, err := service.resolveClipSubtitleFile(ctx, sourceFile, request.SubtitleFileID)
+		if err != nil {
+			service.failClipOperation(ctx, operation, request, err)
+			return
+		}
+		subtitleFile = &resolved
+		output.SubtitleFileID = resolved.ID
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+
+	displayName := resolveClipTitle(request, sourceFile)
+	outputPath, err := service.deriveManagedOutputPath(ctx, sourceFile.LibraryID, displayName, "", sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+	if err := ensureManagedOutputParentDir(outputPath); err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+
+	operation.Progress = buildOperationProgress(
+		service.now(),
+		progressText("library.progress.transcoding"),
+		0,
+		1,
+		progressText("library.progressDetail.clipExtracting"),
+	)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+	outputText, err := service.runFFmpegCommandWithProgress(
+		runCtx,
+		&operation,
+		ffmpegExecPath,
+		buildFFmpegClipArgs(sourceFile.Storage.LocalPath, outputPath, request.StartMs, endMs, request.Reencode),
+		filepath.Dir(sourceFile.Storage.LocalPath),
+		endMs-request.StartMs,
+	)
+	if err != nil {
+		if runCtx.Err() != nil {
+			_ = os.Remove(outputPath)
+			service.failClipOperation(ctx, operation, request, context.Canceled)
+			return
+		}
+		message := strings.TrimSpace(outputText)
+		if message == "" {
+			message = err.Error()
+		}
+		service.failClipOperation(ctx, operation, request, fmt.Errorf("ffmpeg clip failed: %s", message))
+		return
+	}
+	if !pathExists(outputPath) {
+		service.failClipOperation(ctx, operation, request, fmt.Errorf("ffmpeg produced no output file"))
+		return
+	}
+
+	finishedAt := service.now()
+	clipMedia := cloneMediaInfo(sourceFile.Media)
+	if clipMedia != nil {
+		clipMedia.Chapters = cropMediaChaptersToRange(clipMedia.Chapters, request.StartMs, endMs)
+	}
+	clipFile, err := service.registerManagedLocalOutputFile(ctx, registeredLocalOutputParams{
+		LibraryID:     sourceFile.LibraryID,
+		RootFileID:    rootFileID(sourceFile),
+		SourceFileIDs: []string{sourceFile.ID},
+		Name:          displayName,
+		Kind:          string(resolveClipFileKind(sourceFile)),
+		OperationID:   operation.ID,
+		OperationKind: "clip",
+		OutputPath:    outputPath,
+		SourceMedia:   clipMedia,
+		OccurredAt:    finishedAt,
+	})
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+	output.ClipFileID = clipFile.ID
+	output.OutputPath = outputPath
+	outputFiles := []library.LibraryFile{clipFile}
+	sourceFiles := []library.LibraryFile{sourceFile}
+
+	historySource := library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID}
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Category:    "operation",
+		Action:      "clip",
+		DisplayName: displayName,
+		Status:      string(library.OperationStatusSucceeded),
+		Source:      historySource,
+		OccurredAt:  &finishedAt,
+		CreatedAt:   &finishedAt,
+		UpdatedAt:   &finishedAt,
+	})
+	if err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+
+	if subtitleFile != nil {
+		subtitleSource, document, err := service.resolveSubtitleFileAndDocument(ctx, subtitleFile.ID, "", "")
+		if err != nil {
+			service.failClipOperation(ctx, operation, request, err)
+			return
+		}
+		sourceDocument, err := parseSubtitleBilingualSource(subtitleSource, document)
+		if err != nil {
+			service.failClipOperation(ctx, operation, request, err)
+			return
+		}
+		clipCues := cropSubtitleCuesToRange(sourceDocument.Cues, request.StartMs, endMs)
+		if len(clipCues) > 0 {
+			format := detectSubtitleFormat("", subtitleSource.Storage.LocalPath, document.Format)
+			clipDocument := dto.SubtitleDocument{
+				Format:   format,
+				Cues:     clipCues,
+				Metadata: cloneSubtitleDocumentMetadata(sourceDocument.Metadata),
+			}
+			clipSubtitle, _, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+				LibraryID:      sourceFile.LibraryID,
+				RootFileID:     clipFile.ID,
+				SourceFileIDs:  []string{subtitleSource.ID},
+				Name:           firstNonEmpty(strings.TrimSpace(subtitleSource.Name), displayName) + " (clip)",
+				OperationID:    operation.ID,
+				OperationKind:  "clip",
+				Format:         format,
+				SourceMedia:    subtitleSource.Media,
+				OriginalSource: renderSubtitleContent(clipDocument, format),
+				OccurredAt:     finishedAt,
+				HistorySource:  historySource,
+			})
+			if err != nil {
+				service.failClipOperation(ctx, operation, request, err)
+				return
+			}
+			output.ClipSubtitleFileID = clipSubtitle.ID
+			output.ClipSubtitleDocumentID = clipSubtitle.Storage.DocumentID
+			output.SubtitleCueCount = len(clipCues)
+			outputFiles = append(outputFiles, clipSubtitle)
+			sourceFiles = append(sourceFiles, subtitleSource)
+		}
+	}
+
+	output.Status = "completed"
+	service.completeClipOperation(ctx, operation, request, history, outputFiles, sourceFiles, output, finishedAt)
+}
+
+// completeClipOperation records the clip, and its cropped subtitle when one was
+// produced, on the operation and its history entry.
+func (service *LibraryService) completeClipOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	request dto.CreateClipJobRequest,
+	history library.HistoryRecord,
+	outputFiles []library.LibraryFile,
+	sourceFiles []library.LibraryFile,
+	output clipOperationOutput,
+	finishedAt time.Time,
+) {
+	for _, group := range [][]library.LibraryFile{outputFiles, sourceFiles} {
+		for index := range group {
+			group[index].LatestOperationID = operation.ID
+			group[index].UpdatedAt = finishedAt
+			if err := service.files.Save(ctx, group[index]); err != nil {
+				service.failClipOperation(ctx, operation, request, err)
+				return
+			}
+		}
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.OutputFiles = make([]library.OperationOutputFile, 0, len(outputFiles))
+	for index, item := range outputFiles {
+		operation.OutputFiles = append(operation.OutputFiles, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+			IsPrimary: index == 0,
+			Deleted:   item.State.Deleted,
+		})
+	}
+	operation.Metrics = buildOperationMetricsForOperation(outputFiles, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		1,
+		1,
+		progressText("library.progressDetail.clipCompleted"),
+	)
+	operation.OutputJSON = buildClipOperationOutput(output, request)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+
+	history.Refs = library.HistoryRecordRefs{OperationID: operation.ID, FileIDs: extractLibraryFileIDs(outputFiles)}
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "clip"}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, operation.LibraryID, finishedAt); err != nil {
+		service.failClipOperation(ctx, operation, request, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	for _, item := range sourceFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	for _, item := range outputFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	service.publishWorkspaceProjectUpdate(operation.LibraryID)
+}
+
+func (service *LibraryService) failClipOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateClipJobRequest, runErr error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	currentOperation := operation
+	if item, err := service.operations.Get(ctx, operation.ID); err == nil {
+		currentOperation = item
+	}
+	if currentOperation.Status == library.OperationStatusCanceled {
+		return
+	}
+	if errors.Is(runErr, context.Canceled) {
+		currentOperation.Status = library.OperationStatusCanceled
+		currentOperation.ErrorCode = "clip_canceled"
+		currentOperation.ErrorMessage = ""
+	} else {
+		currentOperation.Status = library.OperationStatusFailed
+		currentOperation.ErrorCode = "clip_failed"
+		currentOperation.ErrorMessage = strings.TrimSpace(runErr.Error())
+	}
+	now := service.now()
+	currentOperation.FinishedAt = &now
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText(progressStageLocaleKey(string(currentOperation.Status))),
+		0,
+		1,
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	currentOperation.OutputJSON = buildClipOperationOutput(clipOperationOutput{FileID: request.FileID, Status: string(currentOperation.Status)}, request)
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+}
+
+func (service *LibraryService) resolveClipSourceFile(ctx context.Context, fileID string) (library.LibraryFile, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("fileId is required")
+	}
+	item, err := service.files.Get(ctx, strings.TrimSpace(fileID))
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if !isSubtitleAlignMediaFile(item) {
+		return library.LibraryFile{}, fmt.Errorf("file %q is not a local video or audio file", item.Name)
+	}
+	if strings.TrimSpace(item.LibraryID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("source file is not attached to a library")
+	}
+	return item, nil
+}
+
+// resolveClipSubtitleFile returns the explicit subtitle file, or the newest subtitle
+// that shares the source media's lineage root.
+func (service *LibraryService) resolveClipSubtitleFile(ctx context.Context, sourceFile library.LibraryFile, subtitleFileID string) (library.LibraryFile, error) {
+	if trimmed := strings.TrimSpace(subtitleFileID); trimmed != "" {
+		item, err := service.files.Get(ctx, trimmed)
+		if err != nil {
+			return library.LibraryFile{}, err
+		}
+		if item.Kind != library.FileKindSubtitle || item.State.Deleted {
+			return library.LibraryFile{}, fmt.Errorf("file %q is not a subtitle file", item.Name)
+		}
+		return item, nil
+	}
+	items, err := service.files.ListByLibraryID(ctx, sourceFile.LibraryID)
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	sourceRoot := rootFileID(sourceFile)
+	candidates := make([]library.LibraryFile, 0, len(items))
+	for _, item := range items {
+		if item.Kind != library.FileKindSubtitle || item.State.Deleted {
+			continue
+		}
+		if rootFileID(item) == sourceRoot || item.Lineage.RootFileID == sourceFile.ID {
+			candidates = append(candidates, item)
+		}
+	}
+	if len(candidates) == 0 {
+		return library.LibraryFile{}, fmt.Errorf("no subtitle linked to %q", sourceFile.Name)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+	return candidates[0], nil
+}
+
+// buildFFmpegClipArgs seeks on the input so stream copies start at the nearest
+// keyframe; re-encoding gives a frame-accurate cut instead.
+func buildFFmpegClipArgs(inputPath string, outputPath string, startMs int64, endMs int64, reencode bool) []string {
+	args := []string{
+		"-y",
+		"-ss", formatFFmpegSeconds(startMs),
+		"-i", inputPath,
+		"-t", formatFFmpegSeconds(endMs - startMs),
+		"-map", "0:v:0?", "-map", "0:a?",
+	}
+	if reencode {
+		args = append(args, "-c:v", "libx264", "-crf", "18", "-preset", "veryfast", "-c:a", "aac", "-b:a", "192k")
+	} else {
+		args = append(args, "-c", "copy", "-avoid_negative_ts", "make_zero")
+	}
+	return append(args, outputPath)
+}
+
+func formatFFmpegSeconds(valueMs int64) string {
+	if valueMs < 0 {
+		valueMs = 0
+	}
+	return fmt.Sprintf("%d.%03d", valueMs/1000, valueMs%1000)
+}
+
+// cropSubtitleCuesToRange keeps the cues that overlap [startMs, endMs), clamps them to
+// the range and shifts them so the clip starts at zero.
+func cropSubtitleCuesToRange(cues []dto.SubtitleCue, startMs int64, endMs int64) []dto.SubtitleCue {
+	result := make([]dto.SubtitleCue, 0, len(cues))
+	for _, cue := range cues {
+		cueStart := cueStartMS(cue)
+		cueEnd := cueEndMS(cue)
+		if cueEnd <= startMs || cueStart >= endMs {
+			continue
+		}
+		next := cue
+		next.Index = len(result) + 1
+		next.Start = formatSubtitleRetimeTimestamp(cue.Start, maxInt64(cueStart, startMs)-startMs)
+		next.End = formatSubtitleRetimeTimestamp(cue.End, minInt64(cueEnd, endMs)-startMs)
+		result = append(result, next)
+	}
+	return result
+}
+
+func resolveClipFileKind(sourceFile library.LibraryFile) library.FileKind {
+	if sourceFile.Kind == library.FileKindAudio {
+		return library.FileKindAudio
+	}
+	return library.FileKindVideo
+}
+
+func resolveClipTitle(request dto.CreateClipJobRequest, sourceFile library.LibraryFile) string {
+	if title := strings.TrimSpace(request.Title); title != "" {
+		return title
+	}
+	return fmt.Sprintf(
+		"%s (%s-%s)",
+		firstNonEmpty(sourceFile.Name, "clip"),
+		formatSRTTimestamp(request.StartMs)[:8],
+		formatSRTTimestamp(request.EndMs)[:8],
+	)
+}
+
+func validateClipRange(startMs int64, endMs int64) error {
+	if startMs < 0 {
+		return fmt.Errorf("startMs must not be negative")
+	}
+	if endMs <= startMs {
+		return fmt.Errorf("endMs must be after startMs")
+	}
+	return nil
+}
+
+func normalizeClipRequest(request dto.CreateClipJobRequest) dto.CreateClipJobRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.SubtitleFileID = strings.TrimSpace(request.SubtitleFileID)
+	request.Title = strings.TrimSpace(request.Title)
+	request.Source = strings.TrimSpace(request.Source)
+	request.RunID = strings.TrimSpace(request.RunID)
+	return request
+}
+
+func buildClipOperationOutput(output clipOperationOutput, request dto.CreateClipJobRequest) string {
+	output.StartMs = request.StartMs
+	output.EndMs = request.EndMs
+	output.Reencode = request.Reencode
+	return marshalJSON(output)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestCropSubtitleCuesToRangeClampsAndShifts(t *testing.T) {
+	t.Parallel()
+
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:04,000", Text: "before"},
+		{Index: 2, Start: "00:00:09,000", End: "00:00:11,500", Text: "straddles start"},
+		{Index: 3, Start: "00:00:12,000", End: "00:00:14,000", Text: "inside"},
+		{Index: 4, Start: "00:00:19,000", End: "00:00:22,000", Text: "straddles end"},
+		{Index: 5, Start: "00:00:20,000", End: "00:00:25,000", Text: "after"},
+	}
+	cropped := cropSubtitleCuesToRange(cues, 10_000, 20_000)
+	if len(cropped) != 3 {
+		t.Fatalf("expected 3 cues inside the range, got %#v", cropped)
+	}
+	expected := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:00,000", End: "00:00:01,500", Text: "straddles start"},
+		{Index: 2, Start: "00:00:02,000", End: "00:00:04,000", Text: "inside"},
+		{Index: 3, Start: "00:00:09,000", End: "00:00:10,000", Text: "straddles end"},
+	}
+	for index, cue := range expected {
+		if cropped[index] != cue {
+			t.Fatalf("cue %d: expected %#v, got %#v", index, cue, cropped[index])
+		}
+	}
+}
+
+func TestBuildFFmpegClipArgsCopiesUnlessReencoding(t *testing.T) {
+	t.Parallel()
+
+	copied := strings.Join(buildFFmpegClipArgs("in.mp4", "out.mp4", 61_250, 121_250, false), " ")
+	if !strings.Contains(copied, "-ss 61.250 -i in.mp4 -t 60.000") || !strings.Contains(copied, "-c copy") {
+		t.Fatalf("unexpected stream copy args %s", copied)
+	}
+	if !strings.HasSuffix(copied, "out.mp4") {
+		t.Fatalf("expected the output path last, got %s", copied)
+	}
+	reencoded := strings.Join(buildFFmpegClipArgs("in.mp4", "out.mp4", 0, 5_000, true), " ")
+	if strings.Contains(reencoded, "-c copy") || !strings.Contains(reencoded, "-c:v libx264") {
+		t.Fatalf("unexpected re-encode args %s", reencoded)
+	}
+	if err := validateClipRange(5_000, 5_000); err == nil {
+		t.Fatalf("expected an empty clip range to be rejected")
+	}
+}
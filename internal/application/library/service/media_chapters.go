@@ -0,0 +1,634 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	chapterExportFormatYouTube    = "youtube"
+	chapterExportFormatFFMetadata = "ffmetadata"
+)
+
+// youtubeChapterLinePattern matches description lines such as "0:00 Intro",
+// "- 01:02:03 - Outro" or "(12:30) Q&A".
+var youtubeChapterLinePattern = regexp.MustCompile(`^\s*(?:[-*•]\s*)?\(?((?:\d{1,2}:)?\d{1,2}:\d{2})\)?\s*(?:[-–—:|]\s*)?(.*)$`)
+
+type chapterSplitOperationOutput struct {
+	FileID         string   `json:"fileId,omitempty"`
+	ChapterCount   int      `json:"chapterCount"`
+	CompletedCount int      `json:"completedCount"`
+	OutputFileIDs  []string `json:"outputFileIds,omitempty"`
+	Reencode       bool     `json:"reencode,omitempty"`
+	Status         string   `json:"status"`
+}
+
+// UpdateFileChapters replaces the chapters of a video or audio file. An empty list
+// clears them.
+func (service *LibraryService) UpdateFileChapters(ctx context.Context, request dto.UpdateFileChaptersRequest) (dto.LibraryFileDTO, error) {
+	fileItem, err := service.resolveChapterMediaFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryFileDTO{}, err
+	}
+	chapters := fromMediaChapterDTOs(request.Chapters)
+	if strings.TrimSpace(request.Timestamps) != "" {
+		chapters, err = parseYouTubeChapterTimestamps(request.Timestamps)
+		if err != nil {
+			return dto.LibraryFileDTO{}, err
+		}
+	}
+	normalized, err := library.NormalizeMediaChapters(chapters, mediaDurationMs(fileItem.Media))
+	if err != nil {
+		return dto.LibraryFileDTO{}, err
+	}
+	if fileItem.Media == nil {
+		fileItem.Media = &library.MediaInfo{}
+	}
+	fileItem.Media.Chapters = normalized
+	fileItem.UpdatedAt = service.now()
+	if err := service.files.Save(ctx, fileItem); err != nil {
+		return dto.LibraryFileDTO{}, err
+	}
+	result := service.mustBuildFileDTO(ctx, fileItem)
+	service.publishFileUpdate(result)
+	return result, nil
+}
+
+// ExportFileChapters renders the stored chapters as YouTube description timestamps or
+// as an ffmpeg FFMETADATA file.
+func (service *LibraryService) ExportFileChapters(ctx context.Context, request dto.ExportFileChaptersRequest) (dto.ExportFileChaptersResult, error) {
+	fileItem, err := service.resolveChapterMediaFile(ctx, request.FileID)
+	if err != nil {
+		return dto.ExportFileChaptersResult{}, err
+	}
+	var chapters []library.MediaChapter
+	if fileItem.Media != nil {
+		chapters = fileItem.Media.Chapters
+	}
+	format := strings.ToLower(strings.TrimSpace(request.Format))
+	if format == "" {
+		format = chapterExportFormatYouTube
+	}
+	var content string
+	switch format {
+	case chapterExportFormatYouTube:
+		content = formatYouTubeChapters(chapters)
+	case chapterExportFormatFFMetadata:
+		content = buildFFMetadataChapters(chapters)
+	default:
+		return dto.ExportFileChaptersResult{}, fmt.Errorf("unsupported chapter export format %q", request.Format)
+	}
+	return dto.ExportFileChaptersResult{
+		FileID:       fileItem.ID,
+		Format:       format,
+		Content:      content,
+		ChapterCount: len(chapters),
+	}, nil
+}
+
+// CreateChapterSplitJob queues a job that cuts every chapter of a library media file
+// into its own file. Each output keeps the source's lineage root.
+func (service *LibraryService) CreateChapterSplitJob(ctx context.Context, request dto.CreateChapterSplitJobRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeChapterSplitRequest(request)
+	sourceFile, err := service.resolveChapterSplitSourceFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	chapterCount := len(sourceFile.Media.Chapters)
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "chapter_split",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: firstNonEmpty(strings.TrimSpace(sourceFile.Name), "chapters"),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON: marshalJSON(chapterSplitOperationOutput{
+			FileID:       sourceFile.ID,
+			ChapterCount: chapterCount,
+			Reencode:     request.Reencode,
+			Status:       "queued",
+		}),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			chapterCount,
+			progressText("library.progressDetail.chapterSplitQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runChapterSplitOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runChapterSplitOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateChapterSplitJobRequest) {
+	request = normalizeChapterSplitRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		service.unregisterOperationRun(operation.ID)
+		cancel()
+	}()
+
+	output := chapterSplitOperationOutput{FileID: request.FileID, Reencode: request.Reencode, Status: "running"}
+	var outputFiles []library.LibraryFile
+	sourceFile, err := service.resolveChapterSplitSourceFile(ctx, request.FileID)
+	if err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+	chapters := sourceFile.Media.Chapters
+	output.ChapterCount = len(chapters)
+
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.FinishedAt = nil
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.Progress = buildChapterSplitProgress(now, 0, chapters)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+	sourceMedia := cloneMediaInfo(sourceFile.Media)
+	sourceMedia.Chapters = nil
+	sourceName := firstNonEmpty(strings.TrimSpace(sourceFile.Name), strings.TrimSuffix(filepath.Base(sourceFile.Storage.LocalPath), filepath.Ext(sourceFile.Storage.LocalPath)))
+
+	for index, chapter := range chapters {
+		displayName := fmt.Sprintf("%s - %02d %s", sourceName, index+1, chapter.Title)
+		outputPath, err := service.deriveManagedOutputPath(ctx, sourceFile.LibraryID, displayName, "", sourceFile.Storage.LocalPath)
+		if err != nil {
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+			return
+		}
+		if err := ensureManagedOutputParentDir(outputPath); err != nil {
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+			return
+		}
+
+		operation.Progress = buildChapterSplitProgress(service.now(), index, chapters)
+		if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+			return
+		}
+		reporter := newFFmpegProgressReporter(service, &operation, chapter.EndMs-chapter.StartMs)
+		reporter.message = operation.Progress.Message
+		outputText, err := service.runFFmpegCommandWithReporter(
+			runCtx,
+			reporter,
+			ffmpegExecPath,
+			buildFFmpegClipArgs(sourceFile.Storage.LocalPath, outputPath, chapter.StartMs, chapter.EndMs, request.Reencode),
+			filepath.Dir(sourceFile.Storage.LocalPath),
+		)
+		if err != nil {
+			if runCtx.Err() != nil {
+				_ = os.Remove(outputPath)
+				service.failChapterSplitOperation(ctx, operation, output, outputFiles, context.Canceled)
+				return
+			}
+			message := strings.TrimSpace(outputText)
+			if message == "" {
+				message = err.Error()
+			}
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, fmt.Errorf("ffmpeg split of chapter %d failed: %s", index+1, message))
+			return
+		}
+		if !pathExists(outputPath) {
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, fmt.Errorf("ffmpeg produced no output file for chapter %d", index+1))
+			return
+		}
+		chapterFile, err := service.registerManagedLocalOutputFile(ctx, registeredLocalOutputParams{
+			LibraryID:     sourceFile.LibraryID,
+			RootFileID:    rootFileID(sourceFile),
+			SourceFileIDs: []string{sourceFile.ID},
+			Name:          displayName,
+			Kind:          string(resolveClipFileKind(sourceFile)),
+			OperationID:   operation.ID,
+			OperationKind: "chapter_split",
+			OutputPath:    outputPath,
+			SourceMedia:   sourceMedia,
+			OccurredAt:    service.now(),
+		})
+		if err != nil {
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+			return
+		}
+		outputFiles = append(outputFiles, chapterFile)
+		output.CompletedCount = len(outputFiles)
+		output.OutputFileIDs = extractLibraryFileIDs(outputFiles)
+		operation.OutputJSON = marshalJSON(output)
+	}
+
+	output.Status = "completed"
+	service.completeChapterSplitOperation(ctx, operation, request, sourceFile, outputFiles, output)
+}
+
+func (service *LibraryService) completeChapterSplitOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	request dto.CreateChapterSplitJobRequest,
+	sourceFile library.LibraryFile,
+	outputFiles []library.LibraryFile,
+	output chapterSplitOperationOutput,
+) {
+	finishedAt := service.now()
+	sourceFile.LatestOperationID = operation.ID
+	sourceFile.UpdatedAt = finishedAt
+	if err := service.files.Save(ctx, sourceFile); err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+	for index := range outputFiles {
+		outputFiles[index].LatestOperationID = operation.ID
+		outputFiles[index].UpdatedAt = finishedAt
+		if err := service.files.Save(ctx, outputFiles[index]); err != nil {
+			service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+			return
+		}
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.OutputFiles = buildChapterSplitOutputFiles(outputFiles)
+	operation.Metrics = buildOperationMetricsForOperation(outputFiles, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		len(outputFiles),
+		len(outputFiles),
+		progressText("library.progressDetail.chapterSplitCompleted"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Category:    "operation",
+		Action:      "chapter_split",
+		DisplayName: operation.DisplayName,
+		Status:      string(operation.Status),
+		Source:      library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID},
+		Refs: library.HistoryRecordRefs{
+			OperationID: operation.ID,
+			FileIDs:     extractLibraryFileIDs(outputFiles),
+		},
+		OccurredAt: &finishedAt,
+		CreatedAt:  &finishedAt,
+		UpdatedAt:  &finishedAt,
+	})
+	if err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "chapter_split"}
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, sourceFile.LibraryID, finishedAt); err != nil {
+		service.failChapterSplitOperation(ctx, operation, output, outputFiles, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	service.publishFileUpdate(service.mustBuildFileDTO(ctx, sourceFile))
+	for _, item := range outputFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	service.publishWorkspaceProjectUpdate(operation.LibraryID)
+}
+
+// failChapterSplitOperation keeps the chapters that were already written on the
+// operation so a partial split stays visible.
+func (service *LibraryService) failChapterSplitOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	output chapterSplitOperationOutput,
+	outputFiles []library.LibraryFile,
+	runErr error,
+) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	currentOperation := operation
+	if item, err := service.operations.Get(ctx, operation.ID); err == nil {
+		currentOperation = item
+	}
+	if currentOperation.Status == library.OperationStatusCanceled {
+		return
+	}
+	if errors.Is(runErr, context.Canceled) {
+		currentOperation.Status = library.OperationStatusCanceled
+		currentOperation.ErrorCode = "chapter_split_canceled"
+		currentOperation.ErrorMessage = ""
+	} else {
+		currentOperation.Status = library.OperationStatusFailed
+		currentOperation.ErrorCode = "chapter_split_failed"
+		currentOperation.ErrorMessage = strings.TrimSpace(runErr.Error())
+	}
+	now := service.now()
+	currentOperation.FinishedAt = &now
+	currentOperation.OutputFiles = buildChapterSplitOutputFiles(outputFiles)
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText(progressStageLocaleKey(string(currentOperation.Status))),
+		len(outputFiles),
+		max(output.ChapterCount, 1),
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	output.Status = string(currentOperation.Status)
+	currentOperation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+	for _, item := range outputFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+}
+
+func (service *LibraryService) resolveChapterMediaFile(ctx context.Context, fileID string) (library.LibraryFile, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("fileId is required")
+	}
+	item, err := service.files.Get(ctx, strings.TrimSpace(fileID))
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if item.State.Deleted || subtitleAlignMediaRank(item.Kind) < 0 {
+		return library.LibraryFile{}, fmt.Errorf("file %q is not a video or audio file", item.Name)
+	}
+	return item, nil
+}
+
+func (service *LibraryService) resolveChapterSplitSourceFile(ctx context.Context, fileID string) (library.LibraryFile, error) {
+	item, err := service.resolveClipSourceFile(ctx, fileID)
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if item.Media == nil || len(item.Media.Chapters) == 0 {
+		return library.LibraryFile{}, fmt.Errorf("file %q has no chapters", item.Name)
+	}
+	return item, nil
+}
+
+func buildChapterSplitProgress(now time.Time, index int, chapters []library.MediaChapter) *library.OperationProgress {
+	title := ""
+	if index < len(chapters) {
+		title = chapters[index].Title
+	}
+	return buildOperationProgress(
+		now,
+		progressText("library.progress.transcoding"),
+		index,
+		max(len(chapters), 1),
+		progressTextTemplate("library.progressDetail.chapterSplitProgress", map[string]string{
+			"current": strconv.Itoa(index + 1),
+			"total":   strconv.Itoa(len(chapters)),
+			"title":   title,
+		}),
+	)
+}
+
+func buildChapterSplitOutputFiles(files []library.LibraryFile) []library.OperationOutputFile {
+	result := make([]library.OperationOutputFile, 0, len(files))
+	for index, item := range files {
+		result = append(result, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+			IsPrimary: index == 0,
+			Deleted:   item.State.Deleted,
+		})
+	}
+	return result
+}
+
+func normalizeChapterSplitRequest(request dto.CreateChapterSplitJobRequest) dto.CreateChapterSplitJobRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.Source = strings.TrimSpace(request.Source)
+	request.RunID = strings.TrimSpace(request.RunID)
+	return request
+}
+
+// resolveYTDLPChapters reads the chapters of the downloaded entry from the yt-dlp info
+// JSON. Timestamps there are seconds as floats.
+func resolveYTDLPChapters(metadata []map[string]any, durationMs int64) []library.MediaChapter {
+	for index := len(metadata) - 1; index >= 0; index-- {
+		items, ok := metadata[index]["chapters"].([]any)
+		if !ok || len(items) == 0 {
+			continue
+		}
+		chapters := make([]library.MediaChapter, 0, len(items))
+		for _, raw := range items {
+			item, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			startSeconds, ok := item["start_time"].(float64)
+			if !ok {
+				continue
+			}
+			endSeconds, _ := item["end_time"].(float64)
+			chapters = append(chapters, library.MediaChapter{
+				StartMs: int64(startSeconds * 1000),
+				EndMs:   int64(endSeconds * 1000),
+				Title:   getString(item, "title"),
+			})
+		}
+		normalized, err := library.NormalizeMediaChapters(chapters, durationMs)
+		if err != nil {
+			return nil
+		}
+		return normalized
+	}
+	return nil
+}
+
+// parseYouTubeChapterTimestamps reads chapters from description text. Lines without
+// a leading timestamp are ignored.
+func parseYouTubeChapterTimestamps(text string) ([]library.MediaChapter, error) {
+	chapters := make([]library.MediaChapter, 0)
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		match := youtubeChapterLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		startMs, ok := parseYouTubeChapterTimestamp(match[1])
+		if !ok {
+			continue
+		}
+		chapters = append(chapters, library.MediaChapter{StartMs: startMs, Title: strings.TrimSpace(match[2])})
+	}
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("%w: no timestamps found", library.ErrInvalidMediaChapters)
+	}
+	return chapters, nil
+}
+
+func parseYouTubeChapterTimestamp(value string) (int64, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	var totalSeconds int64
+	for index, part := range parts {
+		number, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || number < 0 {
+			return 0, false
+		}
+		if index > 0 && number >= 60 {
+			return 0, false
+		}
+		totalSeconds = totalSeconds*60 + number
+	}
+	return totalSeconds * 1000, true
+}
+
+// formatYouTubeChapters writes one "M:SS Title" line per chapter, switching every line
+// to "H:MM:SS" once a chapter starts past the first hour.
+func formatYouTubeChapters(chapters []library.MediaChapter) string {
+	withHours := len(chapters) > 0 && chapters[len(chapters)-1].StartMs >= int64(time.Hour/time.Millisecond)
+	var builder strings.Builder
+	for _, chapter := range chapters {
+		totalSeconds := chapter.StartMs / 1000
+		if withHours {
+			fmt.Fprintf(&builder, "%d:%02d:%02d", totalSeconds/3600, totalSeconds/60%60, totalSeconds%60)
+		} else {
+			fmt.Fprintf(&builder, "%d:%02d", totalSeconds/60, totalSeconds%60)
+		}
+		builder.WriteString(" ")
+		builder.WriteString(chapter.Title)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// buildFFMetadataChapters renders chapters in ffmpeg's FFMETADATA1 format, which
+// ffmpeg reads back with -map_chapters.
+func buildFFMetadataChapters(chapters []library.MediaChapter) string {
+	var builder strings.Builder
+	builder.WriteString(";FFMETADATA1\n")
+	for _, chapter := range chapters {
+		builder.WriteString("\n[CHAPTER]\nTIMEBASE=1/1000\n")
+		fmt.Fprintf(&builder, "START=%d\nEND=%d\n", chapter.StartMs, chapter.EndMs)
+		builder.WriteString("title=")
+		builder.WriteString(escapeFFMetadataValue(chapter.Title))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func escapeFFMetadataValue(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"=", `\=`,
+		";", `\;`,
+		"#", `\#`,
+		"\n", "\\\n",
+	)
+	return replacer.Replace(value)
+}
+
+// cropMediaChaptersToRange keeps the chapters overlapping [startMs, endMs), clamped to
+// the range and shifted so the range starts at zero.
+func cropMediaChaptersToRange(chapters []library.MediaChapter, startMs int64, endMs int64) []library.MediaChapter {
+	result := make([]library.MediaChapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		if chapter.EndMs <= startMs || chapter.StartMs >= endMs {
+			continue
+		}
+		result = append(result, library.MediaChapter{
+			StartMs: maxInt64(chapter.StartMs, startMs) - startMs,
+			EndMs:   minInt64(chapter.EndMs, endMs) - startMs,
+			Title:   chapter.Title,
+		})
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// transcodeContainerSupportsChapters reports whether ffmpeg can write chapters to
+// the container.
+func transcodeContainerSupportsChapters(container string) bool {
+	switch normalizeContainer(container) {
+	case "mp4", "m4v", "m4a", "mov", "mkv", "mka", "webm":
+		return true
+	default:
+		return false
+	}
+}
+
+func mediaDurationMs(media *library.MediaInfo) int64 {
+	if media == nil || media.DurationMs == nil {
+		return 0
+	}
+	return *media.DurationMs
+}
+
+func toMediaChapterDTOs(chapters []library.MediaChapter) []dto.LibraryMediaChapterDTO {
+	if len(chapters) == 0 {
+		return nil
+	}
+	result := make([]dto.LibraryMediaChapterDTO, 0, len(chapters))
+	for _, chapter := range chapters {
+		result = append(result, dto.LibraryMediaChapterDTO{StartMs: chapter.StartMs, EndMs: chapter.EndMs, Title: chapter.Title})
+	}
+	return result
+}
+
+func fromMediaChapterDTOs(chapters []dto.LibraryMediaChapterDTO) []library.MediaChapter {
+	result := make([]library.MediaChapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		result = append(result, library.MediaChapter{StartMs: chapter.StartMs, EndMs: chapter.EndMs, Title: chapter.Title})
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+func TestResolveYTDLPChaptersReadsInfoJSON(t *testing.T) {
+	t.Parallel()
+
+	metadata := []map[string]any{{
+		"id": "abc",
+		"chapters": []any{
+			map[string]any{"start_time": 95.5, "end_time": 300.0, "title": "Main part"},
+			map[string]any{"start_time": 0.0, "end_time": 95.5, "title": "Intro"},
+			map[string]any{"title": "missing start"},
+		},
+	}}
+	chapters := resolveYTDLPChapters(metadata, 300000)
+	if len(chapters) != 2 {
+		t.Fatalf("expected 2 chapters, got %#v", chapters)
+	}
+	if chapters[0].Title != "Intro" || chapters[0].StartMs != 0 || chapters[0].EndMs != 95500 {
+		t.Fatalf("unexpected first chapter %#v", chapters[0])
+	}
+	if chapters[1].StartMs != 95500 || chapters[1].EndMs != 300000 {
+		t.Fatalf("unexpected second chapter %#v", chapters[1])
+	}
+	if resolveYTDLPChapters([]map[string]any{{"id": "abc"}}, 300000) != nil {
+		t.Fatalf("expected no chapters without a chapters field")
+	}
+}
+
+func TestParseFFprobeMediaProbeReadsChapters(t *testing.T) {
+	t.Parallel()
+
+	output := []byte(`{
+		"streams": [{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080}],
+		"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "120.000000"},
+		"chapters": [
+			{"start_time": "0.000000", "end_time": "60.000000", "tags": {"title": "Opening"}},
+			{"start_time": "60.000000", "end_time": "120.000000", "tags": {}}
+		]
+	}`)
+	probe, err := parseFFprobeMediaProbe(output, "/tmp/input.mp4")
+	if err != nil {
+		t.Fatalf("parse ffprobe output: %v", err)
+	}
+	if len(probe.Chapters) != 2 {
+		t.Fatalf("expected 2 chapters, got %#v", probe.Chapters)
+	}
+	if probe.Chapters[0].Title != "Opening" || probe.Chapters[1].Title != "Chapter 2" || probe.Chapters[1].EndMs != 120000 {
+		t.Fatalf("unexpected chapters %#v", probe.Chapters)
+	}
+	if media := probe.toMediaInfo(); len(media.Chapters) != 2 {
+		t.Fatalf("expected chapters to reach the media info, got %#v", media.Chapters)
+	}
+}
+
+func TestParseYouTubeChapterTimestamps(t *testing.T) {
+	t.Parallel()
+
+	chapters, err := parseYouTubeChapterTimestamps("Watch the full episode!\n0:00 Intro\n- 02:15 - Setup\r\n(1:05:09) Q&A\nno timestamp here\n")
+	if err != nil {
+		t.Fatalf("parse timestamps: %v", err)
+	}
+	if len(chapters) != 3 {
+		t.Fatalf("expected 3 chapters, got %#v", chapters)
+	}
+	if chapters[1].StartMs != 135000 || chapters[1].Title != "Setup" {
+		t.Fatalf("unexpected second chapter %#v", chapters[1])
+	}
+	if chapters[2].StartMs != 3909000 || chapters[2].Title != "Q&A" {
+		t.Fatalf("unexpected third chapter %#v", chapters[2])
+	}
+	if _, err := parseYouTubeChapterTimestamps("just a description"); !errors.Is(err, library.ErrInvalidMediaChapters) {
+		t.Fatalf("expected invalid chapters error, got %v", err)
+	}
+}
+
+func TestFormatChapterExports(t *testing.T) {
+	t.Parallel()
+
+	chapters := []library.MediaChapter{
+		{StartMs: 0, EndMs: 65000, Title: "Intro"},
+		{StartMs: 65000, EndMs: 3700000, Title: "Part 1; a=b"},
+		{StartMs: 3700000, EndMs: 3800000, Title: "Outro"},
+	}
+	youtube := formatYouTubeChapters(chapters)
+	if youtube != "0:00:00 Intro\n0:01:05 Part 1; a=b\n1:01:40 Outro\n" {
+		t.Fatalf("unexpected youtube chapters %q", youtube)
+	}
+	if short := formatYouTubeChapters(chapters[:1]); short != "0:00 Intro\n" {
+		t.Fatalf("unexpected short youtube chapters %q", short)
+	}
+
+	metadata := buildFFMetadataChapters(chapters)
+	if !strings.HasPrefix(metadata, ";FFMETADATA1\n") {
+		t.Fatalf("expected ffmetadata header, got %q", metadata)
+	}
+	if !strings.Contains(metadata, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=65000\nEND=3700000\ntitle=Part 1\\; a\\=b\n") {
+		t.Fatalf("expected an escaped chapter block, got %q", metadata)
+	}
+}
+
+func TestCropMediaChaptersToRange(t *testing.T) {
+	t.Parallel()
+
+	chapters := []library.MediaChapter{
+		{StartMs: 0, EndMs: 10000, Title: "A"},
+		{StartMs: 10000, EndMs: 20000, Title: "B"},
+		{StartMs: 20000, EndMs: 30000, Title: "C"},
+	}
+	cropped := cropMediaChaptersToRange(chapters, 5000, 15000)
+	if len(cropped) != 2 {
+		t.Fatalf("expected 2 cropped chapters, got %#v", cropped)
+	}
+	if cropped[0] != (library.MediaChapter{StartMs: 0, EndMs: 5000, Title: "A"}) || cropped[1] != (library.MediaChapter{StartMs: 5000, EndMs: 10000, Title: "B"}) {
+		t.Fatalf("unexpected cropped chapters %#v", cropped)
+	}
+}
+
+func TestBuildFFmpegTranscodeArgsMapsChapterMetadata(t *testing.T) {
+	t.Parallel()
+
+	plan := transcodePlan{
+		request: dto.CreateTranscodeJobRequest{
+			Format:     "mkv",
+			VideoCodec: "h264",
+			AudioCodec: "aac",
+		},
+		outputType: library.TranscodeOutputVideo,
+	}
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mkv", "", "/tmp/subtitles.srt", "srt", "embed", "/tmp/chapters.ffmetadata")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-i /tmp/subtitles.srt -f ffmetadata -i /tmp/chapters.ffmetadata") {
+		t.Fatalf("expected the chapter metadata as the third input, got %q", joined)
+	}
+	if !strings.Contains(joined, "-map_chapters 2") {
+		t.Fatalf("expected chapters to be mapped from the metadata input, got %q", joined)
+	}
+	if !transcodeContainerSupportsChapters("mkv") || transcodeContainerSupportsChapters("mp3") {
+		t.Fatalf("unexpected chapter container support")
+	}
+}
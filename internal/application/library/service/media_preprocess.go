@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,25 +19,35 @@ import (
 )
 
 type mediaProbe struct {
-	Format      string
-	Codec       string
-	VideoCodec  string
-	AudioCodec  string
-	DurationMs  int64
-	Width       int
-	Height      int
-	FrameRate   float64
-	BitrateKbps int
-	Channels    int
-	SizeBytes   int64
+	Format          string
+	Codec           string
+	VideoCodec      string
+	AudioCodec      string
+	DurationMs      int64
+	Width           int
+	Height          int
+	FrameRate       float64
+	BitrateKbps     int
+	Channels        int
+	SizeBytes       int64
+	Chapters        []library.MediaChapter
+	SubtitleStreams []library.MediaSubtitleStream
 }
 
 type ffprobePayload struct {
-	Streams []ffprobeStream `json:"streams"`
-	Format  ffprobeFormat   `json:"format"`
+	Streams  []ffprobeStream  `json:"streams"`
+	Format   ffprobeFormat    `json:"format"`
+	Chapters []ffprobeChapter `json:"chapters"`
+}
+
+type ffprobeChapter struct {
+	StartTime string            `json:"start_time"`
+	EndTime   string            `json:"end_time"`
+	Tags      map[string]string `json:"tags"`
 }
 
 type ffprobeStream struct {
+	Index        int               `json:"index"`
 	CodecType    string            `json:"codec_type"`
 	CodecName    string            `json:"codec_name"`
 	Width        int               `json:"width"`
@@ -46,6 +57,7 @@ type ffprobeStream struct {
 	RFrameRate   string            `json:"r_frame_rate"`
 	BitRate      string            `json:"bit_rate"`
 	Tags         map[string]string `json:"tags"`
+	Disposition  map[string]int    `json:"disposition"`
 }
 
 type ffprobeFormat struct {
@@ -90,6 +102,12 @@ func (probe mediaProbe) toMediaInfo() library.MediaInfo {
 		value := probe.SizeBytes
 		result.SizeBytes = &value
 	}
+	if len(probe.Chapters) > 0 {
+		result.Chapters = append([]library.MediaChapter(nil), probe.Chapters...)
+	}
+	if len(probe.SubtitleStreams) > 0 {
+		result.SubtitleStreams = append([]library.MediaSubtitleStream(nil), probe.SubtitleStreams...)
+	}
 	return result
 }
 
@@ -175,7 +193,7 @@ func probeLocalMedia(path string) mediaProbe {
 	case "mp3", "m4a", "wav", "flac", "aac", "opus", "ogg":
 		probe.AudioCodec = probe.Format
 		probe.Codec = probe.Format
-	case "srt", "vtt", "ass", "ssa", "ttml", "xml":
+	case "srt", "vtt", "ass", "ssa", "ttml", "xml", "sbv", "sub", "lrc", "stl":
 		probe.Codec = probe.Format
 	default:
 		probe.VideoCodec = probe.Format
@@ -195,6 +213,7 @@ func (service *LibraryService) ffprobeLocalMedia(ctx context.Context, path strin
 		"-print_format", "json",
 		"-show_streams",
 		"-show_format",
+		"-show_chapters",
 		strings.TrimSpace(path),
 	)
 	configureProcessGroup(command)
@@ -250,11 +269,14 @@ func parseFFprobeMediaProbe(output []byte, path string) (mediaProbe, error) {
 			if result.BitrateKbps == 0 {
 				result.BitrateKbps = parseFFprobeBitrateKbps(stream.BitRate)
 			}
+		case "subtitle":
+			result.SubtitleStreams = append(result.SubtitleStreams, parseFFprobeSubtitleStream(stream))
 		}
 	}
 	if result.Codec == "" {
 		result.Codec = firstNonEmpty(result.VideoCodec, result.AudioCodec)
 	}
+	result.Chapters = parseFFprobeChapters(payload.Chapters, result.DurationMs)
 	if result.SizeBytes == 0 {
 		if info, err := os.Stat(strings.TrimSpace(path)); err == nil {
 			result.SizeBytes = info.Size()
@@ -298,6 +320,12 @@ func mergeMediaProbe(base mediaProbe, override mediaProbe) mediaProbe {
 	if override.SizeBytes > 0 {
 		result.SizeBytes = override.SizeBytes
 	}
+	if len(override.Chapters) > 0 {
+		result.Chapters = override.Chapters
+	}
+	if len(override.SubtitleStreams) > 0 {
+		result.SubtitleStreams = override.SubtitleStreams
+	}
 	if strings.TrimSpace(result.Codec) == "" {
 		result.Codec = firstNonEmpty(result.VideoCodec, result.AudioCodec)
 	}
@@ -324,6 +352,49 @@ func parseFFprobeDurationMillis(value string) int64 {
 	return int64(durationSeconds * 1000)
 }
 
+func parseFFprobeSubtitleStream(stream ffprobeStream) library.MediaSubtitleStream {
+	codec := strings.ToLower(strings.TrimSpace(stream.CodecName))
+	language := strings.ToLower(strings.TrimSpace(stream.Tags["language"]))
+	if language == "und" {
+		language = ""
+	}
+	return library.MediaSubtitleStream{
+		Index:     stream.Index,
+		Codec:     codec,
+		Language:  language,
+		Title:     strings.TrimSpace(stream.Tags["title"]),
+		Default:   stream.Disposition["default"] > 0,
+		Forced:    stream.Disposition["forced"] > 0,
+		TextBased: library.IsTextSubtitleCodec(codec),
+	}
+}
+
+// parseFFprobeChapters converts ffprobe chapters to library chapters. Chapters that
+// do not form a valid list are dropped rather than failing the probe.
+func parseFFprobeChapters(items []ffprobeChapter, durationMs int64) []library.MediaChapter {
+	if len(items) == 0 {
+		return nil
+	}
+	chapters := make([]library.MediaChapter, 0, len(items))
+	for _, item := range items {
+		startSeconds, err := strconv.ParseFloat(strings.TrimSpace(item.StartTime), 64)
+		if err != nil {
+			return nil
+		}
+		endSeconds, _ := strconv.ParseFloat(strings.TrimSpace(item.EndTime), 64)
+		chapters = append(chapters, library.MediaChapter{
+			StartMs: int64(math.Round(startSeconds * 1000)),
+			EndMs:   int64(math.Round(endSeconds * 1000)),
+			Title:   firstNonEmpty(item.Tags["title"], item.Tags["TITLE"]),
+		})
+	}
+	normalized, err := library.NormalizeMediaChapters(chapters, durationMs)
+	if err != nil {
+		return nil
+	}
+	return normalized
+}
+
 func parseFFprobeBitrateKbps(value string) int {
 	bitrate, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
 	if err != nil || bitrate <= 0 {
@@ -379,7 +450,7 @@ func normalizeFileExtension(path string) string {
 
 func isSubtitleFormat(format string) bool {
 	switch normalizeSubtitleFormat(format) {
-	case "srt", "vtt", "ass", "ssa", "itt", "fcpxml":
+	case "srt", "vtt", "ass", "ssa", "itt", "fcpxml", "ttml", "sbv", "sub", "lrc", "stl":
 		return true
 	default:
 		return false
@@ -222,6 +222,28 @@ func terminalProgressMessage(kind string, status library.OperationStatus) string
 			return progressText("library.progressDetail.subtitleProofreadCanceled")
 		case "subtitle_qa_review":
 			return progressText("library.progressDetail.subtitleQaReviewCanceled")
+		case "subtitle_transcribe":
+			return progressText("library.progressDetail.subtitleTranscribeCanceled")
+		case "subtitle_align":
+			return progressText("library.progressDetail.subtitleAlignCanceled")
+		case "subtitle_bilingual_merge", "subtitle_bilingual_split":
+			return progressText("library.progressDetail.subtitleBilingualCanceled")
+		case "subtitle_glossary_extract":
+			return progressText("library.progressDetail.subtitleGlossaryExtractCanceled")
+		case "subtitle_pipeline":
+			return progressText("library.progressDetail.subtitlePipelineCanceled")
+		case "download_playlist":
+			return progressText("library.progressDetail.downloadPlaylistCanceled")
+		case "clip":
+			return progressText("library.progressDetail.clipCanceled")
+		case "chapter_split":
+			return progressText("library.progressDetail.chapterSplitCanceled")
+		case "subtitle_ocr":
+			return progressText("library.progressDetail.subtitleOcrCanceled")
+		case "subtitle_extract":
+			return progressText("library.progressDetail.subtitleExtractCanceled")
+		case "thumbnail_generate":
+			return progressText("library.progressDetail.thumbnailGenerateCanceled")
 		default:
 			return progressText("library.progressDetail.operationCanceled")
 		}
@@ -237,6 +259,30 @@ func terminalProgressMessage(kind string, status library.OperationStatus) string
 			return progressText("library.progressDetail.subtitleProofreadFailed")
 		case "subtitle_qa_review":
 			return progressText("library.progressDetail.subtitleQaReviewFailed")
+		case "subtitle_transcribe":
+			return progressText("library.progressDetail.subtitleTranscribeFailed")
+		case "subtitle_align":
+			return progressText("library.progressDetail.subtitleAlignFailed")
+		case "subtitle_bilingual_merge":
+			return progressText("library.progressDetail.subtitleBilingualMergeFailed")
+		case "subtitle_bilingual_split":
+			return progressText("library.progressDetail.subtitleBilingualSplitFailed")
+		case "subtitle_glossary_extract":
+			return progressText("library.progressDetail.subtitleGlossaryExtractFailed")
+		case "subtitle_pipeline":
+			return progressText("library.progressDetail.subtitlePipelineFailed")
+		case "download_playlist":
+			return progressText("library.progressDetail.downloadPlaylistFailed")
+		case "clip":
+			return progressText("library.progressDetail.clipFailed")
+		case "chapter_split":
+			return progressText("library.progressDetail.chapterSplitFailed")
+		case "subtitle_ocr":
+			return progressText("library.progressDetail.subtitleOcrFailed")
+		case "subtitle_extract":
+			return progressText("library.progressDetail.subtitleExtractFailed")
+		case "thumbnail_generate":
+			return progressText("library.progressDetail.thumbnailGenerateFailed")
 		default:
 			return progressText("library.progressDetail.operationFailed")
 		}
@@ -348,6 +394,10 @@ func progressStageLocaleKey(code string) string {
 		return "library.progress.proofreading"
 	case "qa_reviewing":
 		return "library.progress.qaReviewing"
+	case "transcribing":
+		return "library.progress.transcribing"
+	case "aligning":
+		return "library.progress.aligning"
 	case "transcoding":
 		return "library.progress.transcoding"
 	case "downloading":
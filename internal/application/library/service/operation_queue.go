@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	operationQueueDownload  = "download"
+	operationQueueTranscode = "transcode"
+)
+
+var errOperationQueueCanceled = errors.New("operation was removed from the queue")
+
+// operationQueue hands out run slots for download and transcode operations. Waiting
+// operations are granted slots by priority, highest first, then in arrival order.
+// Pausing only holds back waiting operations; running ones finish.
+type operationQueue struct {
+	mu       sync.Mutex
+	paused   bool
+	config   *library.OperationQueueConfig
+	running  map[string]int
+	waiting  []*operationQueueTicket
+	sequence int64
+}
+
+type operationQueueTicket struct {
+	operationID string
+	kind        string
+	priority    int
+	sequence    int64
+	ready       chan struct{}
+	canceled    bool
+}
+
+// operationQueueKind maps an operation kind to the queue it waits in. Parent
+// operations such as playlists are not queued; their child downloads are.
+func operationQueueKind(kind string) string {
+	switch strings.TrimSpace(kind) {
+	case "download":
+		return operationQueueDownload
+	case "transcode", "clip", "chapter_split", "subtitle_extract", "thumbnail_generate",
+		"subtitle_ocr", "subtitle_transcribe", "subtitle_align":
+		return operationQueueTranscode
+	default:
+		return ""
+	}
+}
+
+func (queue *operationQueue) configure(config library.OperationQueueConfig) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.config = &config
+	queue.dispatchLocked()
+}
+
+// acquire blocks until the operation may run and returns the function that frees
+// its slot. It fails when the context ends or the operation is canceled while queued.
+func (queue *operationQueue) acquire(ctx context.Context, operationID string, kind string, priority int) (func(), error) {
+	ticket := &operationQueueTicket{
+		operationID: strings.TrimSpace(operationID),
+		kind:        kind,
+		priority:    priority,
+		ready:       make(chan struct{}),
+	}
+	queue.mu.Lock()
+	queue.sequence++
+	ticket.sequence = queue.sequence
+	queue.waiting = append(queue.waiting, ticket)
+	queue.dispatchLocked()
+	queue.mu.Unlock()
+
+	select {
+	case <-ticket.ready:
+	case <-ctx.Done():
+		queue.mu.Lock()
+		removed := queue.removeLocked(ticket)
+		queue.mu.Unlock()
+		if removed {
+			return nil, ctx.Err()
+		}
+		<-ticket.ready
+	}
+	if ticket.canceled {
+		return nil, errOperationQueueCanceled
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			if queue.running[kind] > 0 {
+				queue.running[kind]--
+			}
+			queue.dispatchLocked()
+		})
+	}, nil
+}
+
+func (queue *operationQueue) cancel(operationID string) bool {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	ticket := queue.findLocked(operationID)
+	if ticket == nil {
+		return false
+	}
+	queue.removeLocked(ticket)
+	ticket.canceled = true
+	close(ticket.ready)
+	return true
+}
+
+func (queue *operationQueue) setPriority(operationID string, priority int) bool {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	ticket := queue.findLocked(operationID)
+	if ticket == nil {
+		return false
+	}
+	ticket.priority = priority
+	queue.dispatchLocked()
+	return true
+}
+
+func (queue *operationQueue) setPaused(paused bool) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.paused = paused
+	queue.dispatchLocked()
+}
+
+func (queue *operationQueue) state() dto.OperationQueueStateDTO {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	config := queue.configLocked()
+	waiting := make(map[string]int)
+	for _, ticket := range queue.waiting {
+		waiting[ticket.kind]++
+	}
+	return dto.OperationQueueStateDTO{
+		Paused:    queue.paused,
+		RateLimit: config.RateLimit,
+		Kinds: []dto.OperationQueueKindDTO{
+			{
+				Kind:        operationQueueDownload,
+				Concurrency: config.DownloadConcurrency,
+				Running:     queue.running[operationQueueDownload],
+				Waiting:     waiting[operationQueueDownload],
+			},
+			{
+				Kind:        operationQueueTranscode,
+				Concurrency: config.TranscodeConcurrency,
+				Running:     queue.running[operationQueueTranscode],
+				Waiting:     waiting[operationQueueTranscode],
+			},
+		},
+	}
+}
+
+// entries returns the queue position of every waiting operation, counted from one
+// within its queue kind.
+func (queue *operationQueue) entries() map[string]dto.OperationQueueEntryDTO {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.sortLocked()
+	result := make(map[string]dto.OperationQueueEntryDTO, len(queue.waiting))
+	positions := make(map[string]int)
+	for _, ticket := range queue.waiting {
+		positions[ticket.kind]++
+		result[ticket.operationID] = dto.OperationQueueEntryDTO{
+			Kind:     ticket.kind,
+			Position: positions[ticket.kind],
+			Priority: ticket.priority,
+			Paused:   queue.paused,
+		}
+	}
+	return result
+}
+
+func (queue *operationQueue) dispatchLocked() {
+	if queue.paused || len(queue.waiting) == 0 {
+		return
+	}
+	if queue.running == nil {
+		queue.running = make(map[string]int)
+	}
+	queue.sortLocked()
+	remaining := queue.waiting[:0]
+	for _, ticket := range queue.waiting {
+		if queue.running[ticket.kind] < queue.limitLocked(ticket.kind) {
+			queue.running[ticket.kind]++
+			close(ticket.ready)
+			continue
+		}
+		remaining = append(remaining, ticket)
+	}
+	for index := len(remaining); index < len(queue.waiting); index++ {
+		queue.waiting[index] = nil
+	}
+	queue.waiting = remaining
+}
+
+func (queue *operationQueue) sortLocked() {
+	sort.SliceStable(queue.waiting, func(i, j int) bool {
+		left, right := queue.waiting[i], queue.waiting[j]
+		if left.priority != right.priority {
+			return left.priority > right.priority
+		}
+		return left.sequence < right.sequence
+	})
+}
+
+func (queue *operationQueue) limitLocked(kind string) int {
+	config := queue.configLocked()
+	switch kind {
+	case operationQueueDownload:
+		return config.DownloadConcurrency
+	case operationQueueTranscode:
+		return config.TranscodeConcurrency
+	default:
+		return 1
+	}
+}
+
+func (queue *operationQueue) configLocked() library.OperationQueueConfig {
+	if queue.config == nil {
+		return library.DefaultModuleConfig().Queue
+	}
+	return *queue.config
+}
+
+func (queue *operationQueue) findLocked(operationID string) *operationQueueTicket {
+	trimmed := strings.TrimSpace(operationID)
+	for _, ticket := range queue.waiting {
+		if ticket.operationID == trimmed {
+			return ticket
+		}
+	}
+	return nil
+}
+
+func (queue *operationQueue) removeLocked(target *operationQueueTicket) bool {
+	for index, ticket := range queue.waiting {
+		if ticket == target {
+			queue.waiting = append(queue.waiting[:index], queue.waiting[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// acquireOperationSlot waits for a run slot when the operation kind is queued and
+// returns a no-op release for every other kind.
+func (service *LibraryService) acquireOperationSlot(ctx context.Context, operation library.LibraryOperation, priority int) (func(), error) {
+	kind := operationQueueKind(operation.Kind)
+	if kind == "" {
+		return func() {}, nil
+	}
+	if config, err := service.getModuleConfig(ctx); err == nil {
+		service.queue.configure(config.Queue)
+	}
+	return service.queue.acquire(ctx, operation.ID, kind, priority)
+}
+
+func (service *LibraryService) GetOperationQueue(ctx context.Context) (dto.OperationQueueStateDTO, error) {
+	config, err := service.getModuleConfig(ctx)
+	if err != nil {
+		return dto.OperationQueueStateDTO{}, err
+	}
+	service.queue.configure(config.Queue)
+	return service.queue.state(), nil
+}
+
+// SetOperationQueuePaused pauses or resumes the whole queue. Operations that already
+// hold a slot keep running.
+func (service *LibraryService) SetOperationQueuePaused(ctx context.Context, request dto.SetOperationQueuePausedRequest) (dto.OperationQueueStateDTO, error) {
+	service.queue.setPaused(request.Paused)
+	state, err := service.GetOperationQueue(ctx)
+	if err != nil {
+		return dto.OperationQueueStateDTO{}, err
+	}
+	service.publishQueueUpdate(state)
+	return state, nil
+}
+
+// SetOperationPriority changes the priority of a queued download or transcode. The
+// priority is stored with the operation input so it survives a restart.
+func (service *LibraryService) SetOperationPriority(ctx context.Context, request dto.SetOperationPriorityRequest) (dto.LibraryOperationDTO, error) {
+	operationID := strings.TrimSpace(request.OperationID)
+	if operationID == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("operationId is required")
+	}
+	item, err := service.operations.Get(ctx, operationID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if operationQueueKind(item.Kind) == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("operation kind %q is not queued", item.Kind)
+	}
+	if item.Status != library.OperationStatusQueued {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("operation status %q does not support priority changes", item.Status)
+	}
+	inputJSON, err := withOperationInputPriority(item.InputJSON, request.Priority)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	item.InputJSON = inputJSON
+	if err := service.operations.Save(ctx, item); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	service.queue.setPriority(item.ID, request.Priority)
+	result := toOperationDTO(item)
+	service.publishOperationUpdate(result)
+	if state, err := service.GetOperationQueue(ctx); err == nil {
+		service.publishQueueUpdate(state)
+	}
+	return result, nil
+}
+
+func (service *LibraryService) markQueuedOperationCanceled(ctx context.Context, item library.LibraryOperation) (library.LibraryOperation, error) {
+	now := service.now()
+	item.Status = library.OperationStatusCanceled
+	item.FinishedAt = &now
+	item.ErrorCode = "operation_canceled"
+	item.ErrorMessage = ""
+	item.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.canceled"),
+		0,
+		progressTotal(item.Progress),
+		progressText("library.progressDetail.canceledByUser"),
+	)
+	if err := service.operations.Save(ctx, item); err != nil {
+		return library.LibraryOperation{}, err
+	}
+	service.publishOperationUpdate(toOperationDTO(item))
+	return item, nil
+}
+
+func withOperationInputPriority(inputJSON string, priority int) (string, error) {
+	payload := make(map[string]any)
+	if trimmed := strings.TrimSpace(inputJSON); trimmed != "" {
+		if err := json.Unmarshal([]byte(trimmed), &payload); err != nil {
+			return "", err
+		}
+	}
+	if priority == 0 {
+		delete(payload, "priority")
+	} else {
+		payload["priority"] = priority
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// resolveYTDLPRateLimit returns the configured --limit-rate for yt-dlp runs.
+func (service *LibraryService) resolveYTDLPRateLimit(ctx context.Context) string {
+	config, err := service.getModuleConfig(ctx)
+	if err != nil {
+		return ""
+	}
+	return config.Queue.RateLimit
+}
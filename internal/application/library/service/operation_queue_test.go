@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dreamcreator/internal/domain/library"
+)
+
+func acquireInBackground(queue *operationQueue, operationID string, kind string, priority int) (<-chan func(), <-chan error) {
+	granted := make(chan func(), 1)
+	failed := make(chan error, 1)
+	go func() {
+		release, err := queue.acquire(context.Background(), operationID, kind, priority)
+		if err != nil {
+			failed <- err
+			return
+		}
+		granted <- release
+	}()
+	return granted, failed
+}
+
+func waitForQueued(t *testing.T, queue *operationQueue, operationID string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := queue.entries()[operationID]; ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("operation %s never entered the queue", operationID)
+}
+
+func TestOperationQueueGrantsSlotsByPriority(t *testing.T) {
+	t.Parallel()
+
+	queue := &operationQueue{}
+	queue.configure(library.OperationQueueConfig{DownloadConcurrency: 1, TranscodeConcurrency: 1})
+
+	releaseFirst, err := queue.acquire(context.Background(), "op-1", operationQueueDownload, 0)
+	if err != nil {
+		t.Fatalf("acquire first slot: %v", err)
+	}
+	lowGranted, _ := acquireInBackground(queue, "op-low", operationQueueDownload, 0)
+	waitForQueued(t, queue, "op-low")
+	highGranted, _ := acquireInBackground(queue, "op-high", operationQueueDownload, 5)
+	waitForQueued(t, queue, "op-high")
+
+	entries := queue.entries()
+	if entries["op-high"].Position != 1 || entries["op-low"].Position != 2 {
+		t.Fatalf("expected the high priority download first, got %#v", entries)
+	}
+	transcodeRelease, err := queue.acquire(context.Background(), "op-transcode", operationQueueTranscode, 0)
+	if err != nil {
+		t.Fatalf("expected a free transcode slot while downloads wait: %v", err)
+	}
+	transcodeRelease()
+
+	releaseFirst()
+	select {
+	case releaseHigh := <-highGranted:
+		releaseHigh()
+	case <-lowGranted:
+		t.Fatalf("expected the high priority download to start before the low one")
+	case <-time.After(2 * time.Second):
+		t.Fatalf("high priority download never started")
+	}
+	select {
+	case releaseLow := <-lowGranted:
+		releaseLow()
+	case <-time.After(2 * time.Second):
+		t.Fatalf("low priority download never started")
+	}
+	if state := queue.state(); state.Kinds[0].Running != 0 || state.Kinds[0].Waiting != 0 {
+		t.Fatalf("expected an idle download queue, got %#v", state.Kinds[0])
+	}
+}
+
+func TestOperationQueuePauseAndCancel(t *testing.T) {
+	t.Parallel()
+
+	queue := &operationQueue{}
+	queue.setPaused(true)
+	granted, _ := acquireInBackground(queue, "op-paused", operationQueueDownload, 0)
+	waitForQueued(t, queue, "op-paused")
+	_, canceled := acquireInBackground(queue, "op-canceled", operationQueueDownload, 0)
+	waitForQueued(t, queue, "op-canceled")
+
+	if !queue.entries()["op-paused"].Paused {
+		t.Fatalf("expected queued entries to report the paused queue")
+	}
+	select {
+	case <-granted:
+		t.Fatalf("expected the paused queue to hold the download")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if !queue.cancel("op-canceled") {
+		t.Fatalf("expected the queued operation to be removed")
+	}
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, errOperationQueueCanceled) {
+			t.Fatalf("unexpected cancel error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("canceled operation never returned")
+	}
+
+	queue.setPaused(false)
+	select {
+	case release := <-granted:
+		release()
+	case <-time.After(2 * time.Second):
+		t.Fatalf("download never started after resume")
+	}
+}
+
+func TestWithOperationInputPriorityKeepsOtherFields(t *testing.T) {
+	t.Parallel()
+
+	updated, err := withOperationInputPriority(`{"url":"https://example.com/v","title":"Episode"}`, 3)
+	if err != nil {
+		t.Fatalf("update priority: %v", err)
+	}
+	if updated != `{"priority":3,"title":"Episode","url":"https://example.com/v"}` {
+		t.Fatalf("unexpected input json %s", updated)
+	}
+	cleared, err := withOperationInputPriority(updated, 0)
+	if err != nil {
+		t.Fatalf("clear priority: %v", err)
+	}
+	if cleared != `{"title":"Episode","url":"https://example.com/v"}` {
+		t.Fatalf("unexpected cleared input json %s", cleared)
+	}
+}
+
+func TestOperationQueueKindCoversFFmpegJobs(t *testing.T) {
+	for _, kind := range []string{"transcode", "clip", "chapter_split", "subtitle_extract", "thumbnail_generate", "subtitle_ocr", "subtitle_transcribe", "subtitle_align"} {
+		if got := operationQueueKind(kind); got != operationQueueTranscode {
+			t.Fatalf("expected %s to use the transcode queue, got %q", kind, got)
+		}
+	}
+	if got := operationQueueKind("download"); got != operationQueueDownload {
+		t.Fatalf("expected downloads to use the download queue, got %q", got)
+	}
+	if got := operationQueueKind("subtitle_translate"); got != "" {
+		t.Fatalf("expected non-ffmpeg jobs to skip the queue, got %q", got)
+	}
+}
@@ -16,6 +16,8 @@ const (
 	libraryTopicWorkspace        = "library.workspace"
 	libraryTopicWorkspaceProject = "library.workspace_project"
 	libraryTopicEvent            = "library.file_event"
+	libraryTopicSubscription     = "library.subscription"
+	libraryTopicQueue            = "library.queue"
 	libraryEventUpsert           = "upsert"
 	libraryEventDelete           = "delete"
 )
@@ -71,6 +73,22 @@ func (service *LibraryService) publishFileEventUpdate(item dto.FileEventRecordDT
 	service.publishEvent(libraryTopicEvent, libraryEventUpsert, item)
 }
 
+func (service *LibraryService) publishSubscriptionUpdate(item dto.LibrarySubscriptionDTO) {
+	service.publishEvent(libraryTopicSubscription, libraryEventUpsert, item)
+}
+
+func (service *LibraryService) publishSubscriptionDelete(id string) {
+	trimmed := strings.TrimSpace(id)
+	if trimmed == "" {
+		return
+	}
+	service.publishEvent(libraryTopicSubscription, libraryEventDelete, map[string]string{"id": trimmed})
+}
+
+func (service *LibraryService) publishQueueUpdate(state dto.OperationQueueStateDTO) {
+	service.publishEvent(libraryTopicQueue, libraryEventUpsert, state)
+}
+
 func (service *LibraryService) trackCompletedOperation(ctx context.Context, operation library.LibraryOperation) {
 	if service == nil || service.telemetry == nil {
 		return
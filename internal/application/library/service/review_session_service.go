@@ -171,18 +171,55 @@ func (service *LibraryService) ApplySubtitleReviewSession(
 	for _, cue := range candidateDocument.Cues {
 		candidateByIndex[cue.Index] = cue
 	}
+	suggestionByIndex := make(map[int]library.SubtitleReviewSuggestion, len(session.Suggestions))
+	mergedAway := make(map[int]bool)
+	for _, suggestion := range session.Suggestions {
+		suggestionByIndex[suggestion.CueIndex] = suggestion
+		if acceptAll || accepted[suggestion.CueIndex] {
+			for _, cueIndex := range suggestion.MergedCueIndexes {
+				mergedAway[cueIndex] = true
+			}
+		}
+	}
 	appliedCues := make([]dto.SubtitleCue, 0, len(sourceDocument.Cues))
 	appliedChangeCount := 0
+	restructured := false
 	for _, cue := range sourceDocument.Cues {
+		if mergedAway[cue.Index] {
+			restructured = true
+			continue
+		}
+		if suggestion, ok := suggestionByIndex[cue.Index]; ok && len(suggestion.SuggestedCues) > 0 && (acceptAll || accepted[cue.Index]) {
+			for _, item := range suggestion.SuggestedCues {
+				appliedCues = append(appliedCues, dto.SubtitleCue{Start: item.Start, End: item.End, Text: item.Text})
+			}
+			appliedChangeCount++
+			restructured = true
+			continue
+		}
 		next := cue
 		if candidateCue, ok := candidateByIndex[cue.Index]; ok && (acceptAll || accepted[cue.Index]) {
+			changed := false
 			if cue.Text != candidateCue.Text {
 				next.Text = candidateCue.Text
+				changed = true
+			}
+			if cueStartMS(cue) != cueStartMS(candidateCue) || cueEndMS(cue) != cueEndMS(candidateCue) {
+				next.Start = candidateCue.Start
+				next.End = candidateCue.End
+				changed = true
+			}
+			if changed {
 				appliedChangeCount++
 			}
 		}
 		appliedCues = append(appliedCues, next)
 	}
+	if restructured {
+		for index := range appliedCues {
+			appliedCues[index].Index = index + 1
+		}
+	}
 	appliedDocument := dto.SubtitleDocument{
 		Format:   detectSubtitleFormat(document.Format, file.Storage.LocalPath, sourceRevision.Format),
 		Cues:     appliedCues,
@@ -224,6 +261,7 @@ func (service *LibraryService) ApplySubtitleReviewSession(
 	if err := service.reviews.Save(ctx, session); err != nil {
 		return dto.ApplySubtitleReviewSessionResult{}, err
 	}
+	service.recordReviewedTranslationMemory(ctx, file, session.OperationID, appliedCues)
 	_ = service.touchLibrary(ctx, file.LibraryID, service.now())
 	service.publishFileUpdate(service.mustBuildFileDTO(ctx, file))
 	service.publishWorkspaceProjectUpdate(file.LibraryID)
@@ -259,13 +297,35 @@ func toSubtitleReviewSuggestionDTOs(items []library.SubtitleReviewSuggestion) []
 	result := make([]dto.SubtitleReviewSuggestionDTO, 0, len(items))
 	for _, item := range items {
 		result = append(result, dto.SubtitleReviewSuggestionDTO{
-			CueIndex:      item.CueIndex,
-			OriginalText:  item.OriginalText,
-			SuggestedText: item.SuggestedText,
-			Categories:    append([]string(nil), item.Categories...),
-			Reason:        item.Reason,
-			SourceCode:    item.SourceCode,
-			Severity:      item.Severity,
+			CueIndex:         item.CueIndex,
+			OriginalText:     item.OriginalText,
+			SuggestedText:    item.SuggestedText,
+			OriginalStart:    item.OriginalStart,
+			OriginalEnd:      item.OriginalEnd,
+			SuggestedStart:   item.SuggestedStart,
+			SuggestedEnd:     item.SuggestedEnd,
+			SuggestedCues:    toSubtitleReviewSuggestedCueDTOs(item.SuggestedCues),
+			MergedCueIndexes: append([]int(nil), item.MergedCueIndexes...),
+			Categories:       append([]string(nil), item.Categories...),
+			Reason:           item.Reason,
+			SourceCode:       item.SourceCode,
+			Severity:         item.Severity,
+		})
+	}
+	return result
+}
+
+func toSubtitleReviewSuggestedCueDTOs(items []library.SubtitleReviewSuggestedCue) []dto.SubtitleCue {
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]dto.SubtitleCue, 0, len(items))
+	for index, item := range items {
+		result = append(result, dto.SubtitleCue{
+			Index: index + 1,
+			Start: item.Start,
+			End:   item.End,
+			Text:  item.Text,
 		})
 	}
 	return result
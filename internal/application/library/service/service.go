@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 
 	connectorsdto "dreamcreator/internal/application/connectors/dto"
 	connectorsservice "dreamcreator/internal/application/connectors/service"
@@ -19,6 +20,7 @@ import (
 	runtimedto "dreamcreator/internal/application/gateway/runtime/dto"
 	"dreamcreator/internal/application/library/dto"
 	settingsdto "dreamcreator/internal/application/settings/dto"
+	"dreamcreator/internal/application/ytdlp"
 	"dreamcreator/internal/domain/externaltools"
 	"dreamcreator/internal/domain/library"
 	"dreamcreator/internal/infrastructure/opener"
@@ -63,6 +65,8 @@ type LibraryService struct {
 	subtitles       library.SubtitleDocumentRepository
 	revisions       library.SubtitleRevisionRepository
 	reviews         library.SubtitleReviewSessionRepository
+	memory          library.TranslationMemoryRepository
+	subscriptions   library.SubscriptionRepository
 	presets         library.TranscodePresetRepository
 	settings        settingsReader
 	iconResolver    iconResolver
@@ -75,6 +79,11 @@ type LibraryService struct {
 	nowFunc         func() time.Time
 	runMu           sync.Mutex
 	runCancels      map[string]context.CancelFunc
+
+	subscriptionScheduler SubscriptionScheduler
+	subscriptionPollMu    sync.Mutex
+	subscriptionPolls     map[string]*subscriptionPollLock
+	queue                 operationQueue
 }
 
 func NewLibraryService(
@@ -89,6 +98,8 @@ func NewLibraryService(
 	subtitles library.SubtitleDocumentRepository,
 	revisions library.SubtitleRevisionRepository,
 	reviews library.SubtitleReviewSessionRepository,
+	memory library.TranslationMemoryRepository,
+	subscriptions library.SubscriptionRepository,
 	presets library.TranscodePresetRepository,
 	settings settingsReader,
 	iconResolver iconResolver,
@@ -110,6 +121,8 @@ func NewLibraryService(
 		subtitles:       subtitles,
 		revisions:       revisions,
 		reviews:         reviews,
+		memory:          memory,
+		subscriptions:   subscriptions,
 		presets:         presets,
 		settings:        settings,
 		iconResolver:    iconResolver,
@@ -206,6 +219,9 @@ func (service *LibraryService) RecoverPendingJobs(ctx context.Context) {
 		if item.Status != library.OperationStatusQueued && item.Status != library.OperationStatusRunning {
 			continue
 		}
+		if service.isActiveParentChild(ctx, item) {
+			continue
+		}
 		switch item.Kind {
 		case "download":
 			request := dto.CreateYTDLPJobRequest{}
@@ -235,12 +251,84 @@ func (service *LibraryService) RecoverPendingJobs(ctx context.Context) {
 				continue
 			}
 			go service.runSubtitleQAReviewOperation(context.Background(), item, request)
+		case "subtitle_transcribe":
+			request := dto.SubtitleTranscribeRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleTranscribeOperation(context.Background(), item, request)
+		case "subtitle_ocr":
+			request := dto.SubtitleOCRRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleOCROperation(context.Background(), item, request)
+		case "subtitle_align":
+			request := dto.SubtitleAlignRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleAlignOperation(context.Background(), item, request)
+		case "subtitle_bilingual_merge":
+			request := dto.SubtitleBilingualMergeRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleBilingualMergeOperation(context.Background(), item, request)
+		case "subtitle_bilingual_split":
+			request := dto.SubtitleBilingualSplitRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleBilingualSplitOperation(context.Background(), item, request)
+		case "subtitle_glossary_extract":
+			request := dto.SubtitleGlossaryExtractRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleGlossaryExtractOperation(context.Background(), item, request)
+		case "subtitle_pipeline":
+			request := dto.SubtitlePipelineRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitlePipelineOperation(context.Background(), item, request)
+		case "download_playlist":
+			request := dto.CreateYTDLPPlaylistJobRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runYTDLPPlaylistOperation(context.Background(), item, request)
 		case "transcode":
 			request := dto.CreateTranscodeJobRequest{}
 			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
 				continue
 			}
 			go service.runTranscodeOperation(context.Background(), item, request)
+		case "clip":
+			request := dto.CreateClipJobRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runClipOperation(context.Background(), item, request)
+		case "chapter_split":
+			request := dto.CreateChapterSplitJobRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runChapterSplitOperation(context.Background(), item, request)
+		case "subtitle_extract":
+			request := dto.CreateSubtitleExtractJobRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runSubtitleExtractOperation(context.Background(), item, request)
+		case "thumbnail_generate":
+			request := dto.CreateThumbnailJobRequest{}
+			if err := json.Unmarshal([]byte(item.InputJSON), &request); err != nil {
+				continue
+			}
+			go service.runThumbnailOperation(context.Background(), item, request)
 		}
 	}
 }
@@ -373,6 +461,8 @@ func (service *LibraryService) UpdateModuleConfig(ctx context.Context, request d
 	if err := service.moduleConfig.Save(ctx, config); err != nil {
 		return dto.LibraryModuleConfigDTO{}, err
 	}
+	service.queue.configure(config.Queue)
+	service.publishQueueUpdate(service.queue.state())
 	return toModuleConfigDTO(config), nil
 }
 
@@ -399,6 +489,7 @@ func (service *LibraryService) ListOperations(ctx context.Context, request dto.L
 	statuses := toLookup(request.Status)
 	kinds := toLookup(request.Kinds)
 	query := strings.ToLower(strings.TrimSpace(request.Query))
+	queued := service.queue.entries()
 	result := make([]dto.OperationListItemDTO, 0, len(items))
 	for _, item := range items {
 		if len(statuses) > 0 {
@@ -424,7 +515,11 @@ func (service *LibraryService) ListOperations(ctx context.Context, request dto.L
 				continue
 			}
 		}
-		result = append(result, toOperationListItemDTO(item, libraryNames[item.LibraryID]))
+		listItem := toOperationListItemDTO(item, libraryNames[item.LibraryID])
+		if entry, ok := queued[item.ID]; ok {
+			listItem.Queue = &entry
+		}
+		result = append(result, listItem)
 	}
 	return paginateOperationList(result, request.Offset, request.Limit), nil
 }
@@ -446,7 +541,14 @@ func (service *LibraryService) CancelOperation(ctx context.Context, request dto.
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
-	if !isResumableSubtitleOperation(item.Kind) {
+	if item.Status == library.OperationStatusQueued && service.queue.cancel(item.ID) {
+		item, err = service.markQueuedOperationCanceled(ctx, item)
+		if err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+		return toOperationDTO(item), nil
+	}
+	if !isCancelableOperation(item.Kind) {
 		return dto.LibraryOperationDTO{}, fmt.Errorf("operation kind %q does not support cancel", item.Kind)
 	}
 	if item.Status != library.OperationStatusQueued && item.Status != library.OperationStatusRunning {
@@ -475,7 +577,7 @@ func (service *LibraryService) ResumeOperation(ctx context.Context, request dto.
 	if item.Status != library.OperationStatusFailed && item.Status != library.OperationStatusCanceled {
 		return dto.LibraryOperationDTO{}, fmt.Errorf("operation status %q does not support resume", item.Status)
 	}
-	if service.runtime == nil {
+	if service.runtime == nil && item.Kind != "subtitle_transcribe" {
 		return dto.LibraryOperationDTO{}, fmt.Errorf("subtitle language runtime unavailable")
 	}
 	now := service.now()
@@ -506,6 +608,9 @@ func (service *LibraryService) ResumeOperation(ctx context.Context, request dto.
 	case "subtitle_proofread":
 		resumeRequest := extractSubtitleProofreadRequest(item.InputJSON)
 		go service.runSubtitleProofreadOperation(context.Background(), item, resumeRequest)
+	case "subtitle_transcribe":
+		resumeRequest := extractSubtitleTranscribeRequest(item.InputJSON)
+		go service.runSubtitleTranscribeOperation(context.Background(), item, resumeRequest)
 	}
 	return operationDTO, nil
 }
@@ -774,7 +879,10 @@ func (service *LibraryService) OpenPath(_ context.Context, request dto.OpenPathR
 }
 
 func (service *LibraryService) CreateYTDLPJob(ctx context.Context, request dto.CreateYTDLPJobRequest) (dto.LibraryOperationDTO, error) {
-	operation, history, _, err := service.createDownloadOperation(ctx, request)
+	if err := ytdlp.ValidateDownloadSections(request.DownloadSections); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, history, _, err := service.createDownloadOperation(ctx, request, "")
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
@@ -815,6 +923,16 @@ func (service *LibraryService) CreateVideoImport(ctx context.Context, request dt
 	service.publishFileUpdate(service.mustBuildFileDTO(ctx, fileItem))
 	service.publishHistoryUpdate(toHistoryDTO(history))
 	service.publishFileEventUpdate(toFileEventDTO(eventRecord))
+	if request.ExtractSubtitles && hasTextSubtitleStreams(fileItem.Media) {
+		if _, err := service.CreateSubtitleExtractJob(ctx, dto.CreateSubtitleExtractJobRequest{
+			FileID:     fileItem.ID,
+			Source:     request.Source,
+			SessionKey: request.SessionKey,
+			RunID:      request.RunID,
+		}); err != nil {
+			zap.L().Warn("queue embedded subtitle extraction failed", zap.String("fileId", fileItem.ID), zap.Error(err))
+		}
+	}
 	return service.mustBuildFileDTO(ctx, fileItem), nil
 }
 
@@ -866,6 +984,14 @@ func (service *LibraryService) CreateTranscodeJob(ctx context.Context, request d
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
+	if err := service.validateTranscodeVariantPresets(ctx, request); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if request.EmbedCover {
+		if _, err := service.resolveFileCoverPath(ctx, sourceFile); err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+	}
 	now := service.now()
 	operationID := uuid.NewString()
 	displayName := resolveTranscodeTitle(request, sourceFile.Storage.LocalPath, plan.preset)
@@ -905,18 +1031,31 @@ func (service *LibraryService) CreateTranscodeJob(ctx context.Context, request d
 
 func (service *LibraryService) CreateSubtitleTranslateJob(ctx context.Context, request dto.SubtitleTranslateRequest) (dto.LibraryOperationDTO, error) {
 	request = normalizeSubtitleTranslateRequest(request)
-	if err := validateSubtitleTranslateSourceAndTarget(request); err != nil {
+	operation, err := service.queueSubtitleTranslateOperation(ctx, request, library.OperationCorrelation{RunID: request.RunID})
+	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
+	go service.runSubtitleTranslateOperation(context.Background(), operation, request)
+	return toOperationDTO(operation), nil
+}
+
+func (service *LibraryService) queueSubtitleTranslateOperation(
+	ctx context.Context,
+	request dto.SubtitleTranslateRequest,
+	correlation library.OperationCorrelation,
+) (library.LibraryOperation, error) {
+	if err := validateSubtitleTranslateSourceAndTarget(request); err != nil {
+		return library.LibraryOperation{}, err
+	}
 	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	if strings.TrimSpace(sourceFile.LibraryID) == "" {
-		return dto.LibraryOperationDTO{}, fmt.Errorf("source file is not attached to a library")
+		return library.LibraryOperation{}, fmt.Errorf("source file is not attached to a library")
 	}
 	if service.runtime == nil {
-		return dto.LibraryOperationDTO{}, fmt.Errorf("subtitle translate runtime unavailable")
+		return library.LibraryOperation{}, fmt.Errorf("subtitle translate runtime unavailable")
 	}
 	targetLanguage := request.TargetLanguage
 
@@ -924,7 +1063,7 @@ func (service *LibraryService) CreateSubtitleTranslateJob(ctx context.Context, r
 	operationID := uuid.NewString()
 	inputJSON, err := json.Marshal(request)
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
 		ID:          operationID,
@@ -932,7 +1071,7 @@ func (service *LibraryService) CreateSubtitleTranslateJob(ctx context.Context, r
 		Kind:        "subtitle_translate",
 		Status:      string(library.OperationStatusQueued),
 		DisplayName: buildSubtitleTranslateOutputName(sourceFile.Name, targetLanguage),
-		Correlation: library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID)},
+		Correlation: correlation,
 		InputJSON:   string(inputJSON),
 		OutputJSON:  marshalJSON(buildSubtitleTranslateOutput(request, "queued", 0, 0, "", "", "", runtimedto.RuntimeUsage{}, subtitleTaskRunState{})),
 		Progress: buildOperationProgress(
@@ -945,35 +1084,46 @@ func (service *LibraryService) CreateSubtitleTranslateJob(ctx context.Context, r
 		CreatedAt: &now,
 	})
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	if err := service.operations.Save(ctx, operation); err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
-	operationDTO := toOperationDTO(operation)
-	service.publishOperationUpdate(operationDTO)
-	go service.runSubtitleTranslateOperation(context.Background(), operation, request)
-	return operationDTO, nil
+	service.publishOperationUpdate(toOperationDTO(operation))
+	return operation, nil
 }
 
 func (service *LibraryService) CreateSubtitleProofreadJob(ctx context.Context, request dto.SubtitleProofreadRequest) (dto.LibraryOperationDTO, error) {
 	request = normalizeSubtitleProofreadRequest(request)
-	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	operation, err := service.queueSubtitleProofreadOperation(ctx, request, library.OperationCorrelation{RunID: request.RunID})
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
+	go service.runSubtitleProofreadOperation(context.Background(), operation, request)
+	return toOperationDTO(operation), nil
+}
+
+func (service *LibraryService) queueSubtitleProofreadOperation(
+	ctx context.Context,
+	request dto.SubtitleProofreadRequest,
+	correlation library.OperationCorrelation,
+) (library.LibraryOperation, error) {
+	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		return library.LibraryOperation{}, err
+	}
 	if strings.TrimSpace(sourceFile.LibraryID) == "" {
-		return dto.LibraryOperationDTO{}, fmt.Errorf("source file is not attached to a library")
+		return library.LibraryOperation{}, fmt.Errorf("source file is not attached to a library")
 	}
 	if service.runtime == nil {
-		return dto.LibraryOperationDTO{}, fmt.Errorf("subtitle proofread runtime unavailable")
+		return library.LibraryOperation{}, fmt.Errorf("subtitle proofread runtime unavailable")
 	}
 
 	now := service.now()
 	operationID := uuid.NewString()
 	inputJSON, err := json.Marshal(request)
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
 		ID:          operationID,
@@ -981,7 +1131,7 @@ func (service *LibraryService) CreateSubtitleProofreadJob(ctx context.Context, r
 		Kind:        "subtitle_proofread",
 		Status:      string(library.OperationStatusQueued),
 		DisplayName: buildSubtitleProofreadOutputName(sourceFile.Name),
-		Correlation: library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID)},
+		Correlation: correlation,
 		InputJSON:   string(inputJSON),
 		OutputJSON:  marshalJSON(buildSubtitleProofreadOutput(request, "queued", 0, 0, "", "", "", "", "", "", 0, runtimedto.RuntimeUsage{}, subtitleTaskRunState{})),
 		Progress: buildOperationProgress(
@@ -994,15 +1144,13 @@ func (service *LibraryService) CreateSubtitleProofreadJob(ctx context.Context, r
 		CreatedAt: &now,
 	})
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	if err := service.operations.Save(ctx, operation); err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
-	operationDTO := toOperationDTO(operation)
-	service.publishOperationUpdate(operationDTO)
-	go service.runSubtitleProofreadOperation(context.Background(), operation, request)
-	return operationDTO, nil
+	service.publishOperationUpdate(toOperationDTO(operation))
+	return operation, nil
 }
 
 type ensureLibraryParams struct {
@@ -1306,6 +1454,7 @@ func (service *LibraryService) createDerivedLocalFile(ctx context.Context, param
 type derivedSubtitleParams struct {
 	LibraryID      string
 	RootFileID     string
+	SourceFileIDs  []string
 	Name           string
 	OperationID    string
 	OperationKind  string
@@ -1334,7 +1483,7 @@ func (service *LibraryService) createDerivedSubtitleFile(ctx context.Context, pa
 		Name:      params.Name,
 		Storage:   library.FileStorage{Mode: "hybrid", LocalPath: params.LocalPath, DocumentID: documentID},
 		Origin:    library.FileOrigin{Kind: firstNonEmpty(strings.TrimSpace(params.OperationKind), "subtitle_translate"), OperationID: params.OperationID},
-		Lineage:   library.FileLineage{RootFileID: strings.TrimSpace(params.RootFileID)},
+		Lineage:   library.FileLineage{RootFileID: strings.TrimSpace(params.RootFileID), SourceFileIDs: params.SourceFileIDs},
 		Media:     media,
 		State:     library.FileState{Status: "active"},
 		CreatedAt: &now,
@@ -1515,6 +1664,11 @@ func toModuleConfigDTO(config library.ModuleConfig) dto.LibraryModuleConfigDTO {
 			PromptProfiles:   toPromptProfileDTOs(config.LanguageAssets.PromptProfiles),
 		},
 		SubtitleStyles: toSubtitleStyleConfigDTO(config.SubtitleStyles),
+		SubtitleDelivery: dto.LibrarySubtitleDeliveryConfigDTO{
+			Builtin:          toDeliveryProfileDTOs(config.SubtitleDelivery.Builtin),
+			Custom:           toDeliveryProfileDTOs(config.SubtitleDelivery.Custom),
+			DefaultProfileID: config.SubtitleDelivery.DefaultProfileID,
+		},
 		TaskRuntime: dto.LibraryTaskRuntimeConfigDTO{
 			Translate: dto.LibraryTaskRuntimeSettingsDTO{
 				StructuredOutputMode: config.TaskRuntime.Translate.StructuredOutputMode,
@@ -1531,6 +1685,11 @@ func toModuleConfigDTO(config library.ModuleConfig) dto.LibraryModuleConfigDTO {
 				RetryTokenStep:       config.TaskRuntime.Proofread.RetryTokenStep,
 			},
 		},
+		Queue: dto.LibraryOperationQueueConfigDTO{
+			DownloadConcurrency:  config.Queue.DownloadConcurrency,
+			TranscodeConcurrency: config.Queue.TranscodeConcurrency,
+			RateLimit:            config.Queue.RateLimit,
+		},
 	}
 }
 
@@ -1546,6 +1705,10 @@ func toDomainModuleConfig(config dto.LibraryModuleConfigDTO) library.ModuleConfi
 		PromptProfiles:   toPromptProfiles(config.LanguageAssets.PromptProfiles),
 	}
 	result.SubtitleStyles = toSubtitleStyleConfig(config.SubtitleStyles)
+	result.SubtitleDelivery = library.SubtitleDeliveryConfig{
+		Custom:           toDeliveryProfiles(config.SubtitleDelivery.Custom),
+		DefaultProfileID: strings.TrimSpace(config.SubtitleDelivery.DefaultProfileID),
+	}
 	result.TaskRuntime = library.LanguageTaskRuntimeConfig{
 		Translate: library.LanguageTaskRuntimeSettings{
 			StructuredOutputMode: strings.TrimSpace(config.TaskRuntime.Translate.StructuredOutputMode),
@@ -1562,9 +1725,58 @@ func toDomainModuleConfig(config dto.LibraryModuleConfigDTO) library.ModuleConfi
 			RetryTokenStep:       config.TaskRuntime.Proofread.RetryTokenStep,
 		},
 	}
+	result.Queue = library.OperationQueueConfig{
+		DownloadConcurrency:  config.Queue.DownloadConcurrency,
+		TranscodeConcurrency: config.Queue.TranscodeConcurrency,
+		RateLimit:            config.Queue.RateLimit,
+	}
 	return library.NormalizeModuleConfig(result)
 }
 
+func toDeliveryProfileDTOs(values []library.SubtitleDeliveryProfile) []dto.LibraryDeliveryProfileDTO {
+	result := make([]dto.LibraryDeliveryProfileDTO, 0, len(values))
+	for _, value := range values {
+		result = append(result, toDeliveryProfileDTO(value))
+	}
+	return result
+}
+
+func toDeliveryProfileDTO(value library.SubtitleDeliveryProfile) dto.LibraryDeliveryProfileDTO {
+	return dto.LibraryDeliveryProfileDTO{
+		ID:              value.ID,
+		Name:            value.Name,
+		Description:     value.Description,
+		MaxCPS:          value.MaxCPS,
+		MaxCharsPerLine: value.MaxCharsPerLine,
+		MaxLines:        value.MaxLines,
+		MinGapMs:        value.MinGapMs,
+		MinDurationMs:   value.MinDurationMs,
+		MaxDurationMs:   value.MaxDurationMs,
+	}
+}
+
+func toDeliveryProfiles(values []dto.LibraryDeliveryProfileDTO) []library.SubtitleDeliveryProfile {
+	result := make([]library.SubtitleDeliveryProfile, 0, len(values))
+	for _, value := range values {
+		result = append(result, toDeliveryProfile(value))
+	}
+	return result
+}
+
+func toDeliveryProfile(value dto.LibraryDeliveryProfileDTO) library.SubtitleDeliveryProfile {
+	return library.SubtitleDeliveryProfile{
+		ID:              strings.TrimSpace(value.ID),
+		Name:            strings.TrimSpace(value.Name),
+		Description:     strings.TrimSpace(value.Description),
+		MaxCPS:          value.MaxCPS,
+		MaxCharsPerLine: value.MaxCharsPerLine,
+		MaxLines:        value.MaxLines,
+		MinGapMs:        value.MinGapMs,
+		MinDurationMs:   value.MinDurationMs,
+		MaxDurationMs:   value.MaxDurationMs,
+	}
+}
+
 func toTranslateLanguageDTOs(values []library.TranslateLanguage) []dto.LibraryTranslateLanguageDTO {
 	result := make([]dto.LibraryTranslateLanguageDTO, 0, len(values))
 	for _, value := range values {
@@ -2058,6 +2270,23 @@ func toSubtitleExportConfigDTO(value library.SubtitleExportConfig) dto.SubtitleE
 			StartTimecodeSeconds: value.FCPXML.StartTimecodeSeconds,
 		}
 	}
+	if value.TTML != nil {
+		result.TTML = &dto.SubtitleTTMLExportConfig{
+			Language: value.TTML.Language,
+		}
+	}
+	if value.SUB != nil {
+		result.SUB = &dto.SubtitleSUBExportConfig{
+			FrameRate: value.SUB.FrameRate,
+		}
+	}
+	if value.STL != nil {
+		result.STL = &dto.SubtitleSTLExportConfig{
+			FrameRate: value.STL.FrameRate,
+			Language:  value.STL.Language,
+			Title:     value.STL.Title,
+		}
+	}
 	return result
 }
 
@@ -2102,6 +2331,23 @@ func toSubtitleExportConfig(value dto.SubtitleExportConfig) library.SubtitleExpo
 			StartTimecodeSeconds: value.FCPXML.StartTimecodeSeconds,
 		}
 	}
+	if value.TTML != nil {
+		result.TTML = &library.SubtitleTTMLExportConfig{
+			Language: strings.TrimSpace(value.TTML.Language),
+		}
+	}
+	if value.SUB != nil {
+		result.SUB = &library.SubtitleSUBExportConfig{
+			FrameRate: value.SUB.FrameRate,
+		}
+	}
+	if value.STL != nil {
+		result.STL = &library.SubtitleSTLExportConfig{
+			FrameRate: value.STL.FrameRate,
+			Language:  strings.TrimSpace(value.STL.Language),
+			Title:     strings.TrimSpace(value.STL.Title),
+		}
+	}
 	return result
 }
 
@@ -2230,7 +2476,7 @@ func toLibraryFileDTO(item library.LibraryFile) dto.LibraryFileDTO {
 		Kind:              string(item.Kind),
 		Name:              item.Name,
 		Storage:           dto.LibraryFileStorageDTO{Mode: item.Storage.Mode, LocalPath: item.Storage.LocalPath, DocumentID: item.Storage.DocumentID},
-		Lineage:           dto.LibraryFileLineageDTO{RootFileID: item.Lineage.RootFileID},
+		Lineage:           dto.LibraryFileLineageDTO{RootFileID: item.Lineage.RootFileID, SourceFileIDs: append([]string(nil), item.Lineage.SourceFileIDs...)},
 		LatestOperationID: item.LatestOperationID,
 		State: dto.LibraryFileStateDTO{
 			Status:      item.State.Status,
@@ -2257,17 +2503,21 @@ func toLibraryFileDTO(item library.LibraryFile) dto.LibraryFileDTO {
 	}
 	if item.Media != nil {
 		result.Media = &dto.LibraryMediaInfoDTO{
-			Format:      item.Media.Format,
-			Codec:       item.Media.Codec,
-			VideoCodec:  item.Media.VideoCodec,
-			AudioCodec:  item.Media.AudioCodec,
-			DurationMs:  item.Media.DurationMs,
-			Width:       item.Media.Width,
-			Height:      item.Media.Height,
-			FrameRate:   item.Media.FrameRate,
-			BitrateKbps: item.Media.BitrateKbps,
-			Channels:    item.Media.Channels,
-			SizeBytes:   item.Media.SizeBytes,
+			Format:          item.Media.Format,
+			Codec:           item.Media.Codec,
+			VideoCodec:      item.Media.VideoCodec,
+			AudioCodec:      item.Media.AudioCodec,
+			DurationMs:      item.Media.DurationMs,
+			Width:           item.Media.Width,
+			Height:          item.Media.Height,
+			FrameRate:       item.Media.FrameRate,
+			BitrateKbps:     item.Media.BitrateKbps,
+			Channels:        item.Media.Channels,
+			SizeBytes:       item.Media.SizeBytes,
+			LoudnessLUFS:    item.Media.LoudnessLUFS,
+			Chapters:        toMediaChapterDTOs(item.Media.Chapters),
+			SubtitleStreams: toMediaSubtitleStreamDTOs(item.Media.SubtitleStreams),
+			CoverFileID:     item.Media.CoverFileID,
 		}
 	}
 	if format := strings.TrimSpace(mediaFormatFromFile(item)); format != "" {
@@ -2501,6 +2751,8 @@ func cloneMediaInfo(value *library.MediaInfo) *library.MediaInfo {
 		return nil
 	}
 	cloned := *value
+	cloned.Chapters = append([]library.MediaChapter(nil), value.Chapters...)
+	cloned.SubtitleStreams = append([]library.MediaSubtitleStream(nil), value.SubtitleStreams...)
 	return &cloned
 }
 
@@ -2554,6 +2806,19 @@ func mergeMediaInfo(base *library.MediaInfo, override *library.MediaInfo) *libra
 		value := *override.SizeBytes
 		base.SizeBytes = &value
 	}
+	if override.LoudnessLUFS != nil {
+		value := *override.LoudnessLUFS
+		base.LoudnessLUFS = &value
+	}
+	if len(override.Chapters) > 0 {
+		base.Chapters = append([]library.MediaChapter(nil), override.Chapters...)
+	}
+	if len(override.SubtitleStreams) > 0 {
+		base.SubtitleStreams = append([]library.MediaSubtitleStream(nil), override.SubtitleStreams...)
+	}
+	if strings.TrimSpace(override.CoverFileID) != "" {
+		base.CoverFileID = override.CoverFileID
+	}
 	if strings.TrimSpace(base.Codec) == "" {
 		base.Codec = firstNonEmpty(base.VideoCodec, base.AudioCodec)
 	}
@@ -26,6 +26,20 @@ func getString(values map[string]any, keys ...string) string {
 	return ""
 }
 
+func getBool(values map[string]any, key string) bool {
+	if values == nil {
+		return false
+	}
+	switch value := values[key].(type) {
+	case bool:
+		return value
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		return err == nil && parsed
+	}
+	return false
+}
+
 func getStringSlice(values map[string]any, keys ...string) []string {
 	if values == nil {
 		return nil
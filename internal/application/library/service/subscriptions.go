@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	connectorsservice "dreamcreator/internal/application/connectors/service"
+	"dreamcreator/internal/application/library/dto"
+	appytdlp "dreamcreator/internal/application/ytdlp"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subscriptionSource = "subscription"
+	// subscriptionMaxDownloadsPerPoll bounds how many downloads a single poll queues.
+	// Entries over the limit stay out of the archive and are picked up next time.
+	subscriptionMaxDownloadsPerPoll = 20
+)
+
+// SubscriptionScheduler keeps one periodic poll job per subscription.
+type SubscriptionScheduler interface {
+	ScheduleSubscription(ctx context.Context, item library.Subscription) error
+	UnscheduleSubscription(ctx context.Context, subscriptionID string) error
+}
+
+func (service *LibraryService) SetSubscriptionScheduler(scheduler SubscriptionScheduler) {
+	if service == nil {
+		return
+	}
+	service.subscriptionScheduler = scheduler
+}
+
+func (service *LibraryService) ListSubscriptions(ctx context.Context) ([]dto.LibrarySubscriptionDTO, error) {
+	if service.subscriptions == nil {
+		return nil, fmt.Errorf("subscription repository not configured")
+	}
+	items, err := service.subscriptions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.LibrarySubscriptionDTO, 0, len(items))
+	for _, item := range items {
+		result = append(result, service.buildSubscriptionDTO(ctx, item))
+	}
+	return result, nil
+}
+
+func (service *LibraryService) SaveSubscription(ctx context.Context, request dto.LibrarySubscriptionDTO) (dto.LibrarySubscriptionDTO, error) {
+	if service.subscriptions == nil {
+		return dto.LibrarySubscriptionDTO{}, fmt.Errorf("subscription repository not configured")
+	}
+	resolvedURL, _, err := validateDownloadURL(request.URL)
+	if err != nil {
+		return dto.LibrarySubscriptionDTO{}, err
+	}
+	if presetID := strings.TrimSpace(request.TranscodePresetID); presetID != "" {
+		if _, err := service.getTranscodePreset(ctx, presetID); err != nil {
+			return dto.LibrarySubscriptionDTO{}, err
+		}
+	}
+	now := service.now()
+	id := strings.TrimSpace(request.ID)
+	createdAt := now
+	var lastCheckedAt *time.Time
+	lastError := ""
+	if id != "" {
+		existing, err := service.subscriptions.Get(ctx, id)
+		if err != nil {
+			return dto.LibrarySubscriptionDTO{}, err
+		}
+		createdAt = existing.CreatedAt
+		lastCheckedAt = existing.LastCheckedAt
+		lastError = existing.LastError
+	} else {
+		id = uuid.NewString()
+	}
+	item, err := library.NewSubscription(library.SubscriptionParams{
+		ID:                             id,
+		Name:                           request.Name,
+		URL:                            normalizeYTDLPPlaylistURL(resolvedURL),
+		LibraryID:                      request.LibraryID,
+		ConnectorID:                    request.ConnectorID,
+		UseConnector:                   request.UseConnector,
+		Quality:                        request.Quality,
+		FormatID:                       request.FormatID,
+		SubtitleLangs:                  request.SubtitleLangs,
+		SubtitleAuto:                   request.SubtitleAuto,
+		SubtitleFormat:                 request.SubtitleFormat,
+		TranscodePresetID:              request.TranscodePresetID,
+		DeleteSourceFileAfterTranscode: request.DeleteSourceFileAfterTranscode,
+		PollIntervalMinutes:            request.PollIntervalMinutes,
+		DownloadExisting:               request.DownloadExisting,
+		Enabled:                        request.Enabled,
+		LastCheckedAt:                  lastCheckedAt,
+		LastError:                      lastError,
+		CreatedAt:                      &createdAt,
+		UpdatedAt:                      &now,
+	})
+	if err != nil {
+		return dto.LibrarySubscriptionDTO{}, err
+	}
+	if err := service.subscriptions.Save(ctx, item); err != nil {
+		return dto.LibrarySubscriptionDTO{}, err
+	}
+	if service.subscriptionScheduler != nil {
+		if err := service.subscriptionScheduler.ScheduleSubscription(ctx, item); err != nil {
+			return dto.LibrarySubscriptionDTO{}, err
+		}
+	}
+	result := service.buildSubscriptionDTO(ctx, item)
+	service.publishSubscriptionUpdate(result)
+	return result, nil
+}
+
+func (service *LibraryService) DeleteSubscription(ctx context.Context, request dto.DeleteSubscriptionRequest) error {
+	if service.subscriptions == nil {
+		return fmt.Errorf("subscription repository not configured")
+	}
+	id := strings.TrimSpace(request.ID)
+	if id == "" {
+		return fmt.Errorf("subscription id is required")
+	}
+	if _, err := service.subscriptions.Get(ctx, id); err != nil {
+		return err
+	}
+	if service.subscriptionScheduler != nil {
+		if err := service.subscriptionScheduler.UnscheduleSubscription(ctx, id); err != nil {
+			return err
+		}
+	}
+	if err := service.subscriptions.Delete(ctx, id); err != nil {
+		return err
+	}
+	service.publishSubscriptionDelete(id)
+	return nil
+}
+
+// PollSubscription lists the subscription URL and queues a download for every entry
+// missing from its archive. The first poll only records existing entries unless the
+// subscription asks to download them. Scheduled polls skip disabled subscriptions.
+func (service *LibraryService) PollSubscription(ctx context.Context, request dto.PollSubscriptionRequest) (dto.PollSubscriptionResult, error) {
+	return service.pollSubscription(ctx, strings.TrimSpace(request.ID), false)
+}
+
+func (service *LibraryService) PollScheduledSubscription(ctx context.Context, subscriptionID string) (dto.PollSubscriptionResult, error) {
+	return service.pollSubscription(ctx, strings.TrimSpace(subscriptionID), true)
+}
+
+func (service *LibraryService) pollSubscription(ctx context.Context, id string, scheduled bool) (dto.PollSubscriptionResult, error) {
+	if service.subscriptions == nil {
+		return dto.PollSubscriptionResult{}, fmt.Errorf("subscription repository not configured")
+	}
+	if id == "" {
+		return dto.PollSubscriptionResult{}, fmt.Errorf("subscription id is required")
+	}
+	unlock := service.lockSubscriptionPoll(id)
+	defer unlock()
+
+	item, err := service.subscriptions.Get(ctx, id)
+	if err != nil {
+		return dto.PollSubscriptionResult{}, err
+	}
+	if scheduled && !item.Enabled {
+		return dto.PollSubscriptionResult{
+			Subscription: service.buildSubscriptionDTO(ctx, item),
+			OperationIDs: []string{},
+			Skipped:      true,
+		}, nil
+	}
+
+	result, pollErr := service.runSubscriptionPoll(ctx, &item)
+	item.UpdatedAt = service.now()
+	item.LastError = ""
+	if pollErr != nil {
+		item.LastError = pollErr.Error()
+	}
+	if err := service.subscriptions.Save(ctx, item); err != nil && pollErr == nil {
+		pollErr = err
+	}
+	result.Subscription = service.buildSubscriptionDTO(ctx, item)
+	service.publishSubscriptionUpdate(result.Subscription)
+	return result, pollErr
+}
+
+type subscriptionPollLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockSubscriptionPoll serializes polls of one subscription so overlapping
+// manual and scheduled polls do not queue the same entries twice, while
+// other subscriptions keep polling in parallel.
+func (service *LibraryService) lockSubscriptionPoll(id string) func() {
+	service.subscriptionPollMu.Lock()
+	if service.subscriptionPolls == nil {
+		service.subscriptionPolls = make(map[string]*subscriptionPollLock)
+	}
+	lock := service.subscriptionPolls[id]
+	if lock == nil {
+		lock = &subscriptionPollLock{}
+		service.subscriptionPolls[id] = lock
+	}
+	lock.refs++
+	service.subscriptionPollMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		service.subscriptionPollMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(service.subscriptionPolls, id)
+		}
+		service.subscriptionPollMu.Unlock()
+	}
+}
+
+func (service *LibraryService) runSubscriptionPoll(ctx context.Context, item *library.Subscription) (dto.PollSubscriptionResult, error) {
+	result := dto.PollSubscriptionResult{OperationIDs: []string{}}
+	playlist, err := service.fetchSubscriptionPlaylist(ctx, *item)
+	if err != nil {
+		return result, err
+	}
+	// Only a successful listing counts as a check, so a failed first poll still seeds.
+	firstPoll := item.LastCheckedAt == nil
+	now := service.now()
+	item.LastCheckedAt = &now
+	archived, err := service.subscriptions.ListArchiveEntryIDs(ctx, item.ID)
+	if err != nil {
+		return result, err
+	}
+	result.EntryCount = len(playlist.Entries)
+	pending := selectSubscriptionEntries(playlist.Entries, archived)
+	result.NewEntryCount = len(pending)
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	if firstPoll && !item.DownloadExisting {
+		seeded := make([]library.SubscriptionArchiveEntry, 0, len(pending))
+		for _, entry := range pending {
+			seeded = append(seeded, library.SubscriptionArchiveEntry{SubscriptionID: item.ID, EntryID: subscriptionEntryKey(entry), CreatedAt: now})
+		}
+		result.SeededCount = len(seeded)
+		return result, service.subscriptions.SaveArchiveEntries(ctx, seeded)
+	}
+
+	// Channels list newest uploads first; queue the oldest pending entries first so
+	// the library fills in upload order.
+	if len(pending) > subscriptionMaxDownloadsPerPoll {
+		pending = pending[len(pending)-subscriptionMaxDownloadsPerPoll:]
+	}
+	var failures []string
+	for index := len(pending) - 1; index >= 0; index-- {
+		entry := pending[index]
+		operation, err := service.CreateYTDLPJob(ctx, buildSubscriptionDownloadRequest(*item, playlist.URL, entry))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", firstNonEmpty(entry.Title, entry.URL, entry.ID), err))
+			continue
+		}
+		if item.LibraryID == "" {
+			// Later uploads join the library created for the first download.
+			item.LibraryID = operation.LibraryID
+		}
+		result.OperationIDs = append(result.OperationIDs, operation.ID)
+		if err := service.subscriptions.SaveArchiveEntries(ctx, []library.SubscriptionArchiveEntry{{
+			SubscriptionID: item.ID,
+			EntryID:        subscriptionEntryKey(entry),
+			OperationID:    operation.ID,
+			CreatedAt:      now,
+		}}); err != nil {
+			return result, err
+		}
+	}
+	if len(failures) > 0 {
+		return result, errors.New(strings.Join(failures, "; "))
+	}
+	return result, nil
+}
+
+func (service *LibraryService) fetchSubscriptionPlaylist(ctx context.Context, item library.Subscription) (*dto.YTDLPPlaylistInfo, error) {
+	cookiesPath := ""
+	if item.UseConnector && item.ConnectorID != "" && service.connectors != nil {
+		if exported, err := service.connectors.ExportConnectorCookies(ctx, item.ConnectorID, connectorsservice.CookiesExportTXT); err == nil {
+			cookiesPath = exported
+			defer os.Remove(exported)
+		}
+	}
+	targetURL := normalizeYTDLPPlaylistURL(item.URL)
+	limit := normalizeYTDLPPlaylistLimit(0)
+	info, err := appytdlp.FetchInfo(ctx, appytdlp.InfoOptions{
+		Tools:       service.tools,
+		URL:         targetURL,
+		CookiesPath: cookiesPath,
+		ProxyURL:    service.resolveYTDLPProxy(targetURL),
+		Playlist:    true,
+		PlaylistEnd: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	playlist := buildYTDLPPlaylistInfo(info, targetURL, resolveYTDLPPlaylistURLKind(targetURL), limit)
+	if playlist == nil {
+		return nil, fmt.Errorf("subscription url does not resolve to a playlist or channel")
+	}
+	return playlist, nil
+}
+
+// selectSubscriptionEntries keeps the entries whose IDs are not in the archive, in
+// listing order.
+func selectSubscriptionEntries(entries []dto.YTDLPPlaylistEntry, archived []string) []dto.YTDLPPlaylistEntry {
+	seen := make(map[string]struct{}, len(archived)+len(entries))
+	for _, id := range archived {
+		seen[strings.TrimSpace(id)] = struct{}{}
+	}
+	pending := make([]dto.YTDLPPlaylistEntry, 0)
+	for _, entry := range entries {
+		key := subscriptionEntryKey(entry)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		pending = append(pending, entry)
+	}
+	return pending
+}
+
+func subscriptionEntryKey(entry dto.YTDLPPlaylistEntry) string {
+	return firstNonEmpty(strings.TrimSpace(entry.ID), strings.TrimSpace(entry.URL))
+}
+
+func buildSubscriptionDownloadRequest(item library.Subscription, playlistURL string, entry dto.YTDLPPlaylistEntry) dto.CreateYTDLPJobRequest {
+	request := dto.CreateYTDLPJobRequest{
+		URL:                            entry.URL,
+		LibraryID:                      item.LibraryID,
+		Title:                          entry.Title,
+		Author:                         entry.Author,
+		ThumbnailURL:                   entry.ThumbnailURL,
+		Source:                         subscriptionSource,
+		Caller:                         "subscription:" + item.ID,
+		Quality:                        item.Quality,
+		FormatID:                       item.FormatID,
+		SubtitleLangs:                  append([]string(nil), item.SubtitleLangs...),
+		SubtitleAuto:                   item.SubtitleAuto,
+		SubtitleFormat:                 item.SubtitleFormat,
+		TranscodePresetID:              item.TranscodePresetID,
+		DeleteSourceFileAfterTranscode: item.DeleteSourceFileAfterTranscode,
+		ConnectorID:                    item.ConnectorID,
+		UseConnector:                   item.UseConnector,
+	}
+	if _, _, err := validateDownloadURL(entry.URL); err != nil {
+		request.URL = firstNonEmpty(playlistURL, item.URL)
+		request.PlaylistItems = strconv.Itoa(entry.Index)
+	}
+	return request
+}
+
+func (service *LibraryService) buildSubscriptionDTO(ctx context.Context, item library.Subscription) dto.LibrarySubscriptionDTO {
+	archivedCount := 0
+	if service.subscriptions != nil {
+		if ids, err := service.subscriptions.ListArchiveEntryIDs(ctx, item.ID); err == nil {
+			archivedCount = len(ids)
+		}
+	}
+	lastCheckedAt := ""
+	if item.LastCheckedAt != nil {
+		lastCheckedAt = item.LastCheckedAt.Format(time.RFC3339)
+	}
+	return dto.LibrarySubscriptionDTO{
+		ID:                             item.ID,
+		Name:                           item.Name,
+		URL:                            item.URL,
+		LibraryID:                      item.LibraryID,
+		ConnectorID:                    item.ConnectorID,
+		UseConnector:                   item.UseConnector,
+		Quality:                        item.Quality,
+		FormatID:                       item.FormatID,
+		SubtitleLangs:                  append([]string(nil), item.SubtitleLangs...),
+		SubtitleAuto:                   item.SubtitleAuto,
+		SubtitleFormat:                 item.SubtitleFormat,
+		TranscodePresetID:              item.TranscodePresetID,
+		DeleteSourceFileAfterTranscode: item.DeleteSourceFileAfterTranscode,
+		PollIntervalMinutes:            item.PollIntervalMinutes,
+		DownloadExisting:               item.DownloadExisting,
+		Enabled:                        item.Enabled,
+		ArchivedCount:                  archivedCount,
+		LastCheckedAt:                  lastCheckedAt,
+		LastError:                      item.LastError,
+		CreatedAt:                      item.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:                      item.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+	"dreamcreator/internal/infrastructure/libraryrepo"
+	"dreamcreator/internal/infrastructure/persistence"
+)
+
+type subscriptionSchedulerStub struct {
+	scheduled   []library.Subscription
+	unscheduled []string
+}
+
+func (stub *subscriptionSchedulerStub) ScheduleSubscription(_ context.Context, item library.Subscription) error {
+	stub.scheduled = append(stub.scheduled, item)
+	return nil
+}
+
+func (stub *subscriptionSchedulerStub) UnscheduleSubscription(_ context.Context, subscriptionID string) error {
+	stub.unscheduled = append(stub.unscheduled, subscriptionID)
+	return nil
+}
+
+func TestSelectSubscriptionEntriesSkipsArchivedAndDuplicates(t *testing.T) {
+	t.Parallel()
+
+	entries := []dto.YTDLPPlaylistEntry{
+		{Index: 1, ID: "a", URL: "https://www.youtube.com/watch?v=a"},
+		{Index: 2, ID: "b", URL: "https://www.youtube.com/watch?v=b"},
+		{Index: 3, ID: "b", URL: "https://www.youtube.com/watch?v=b"},
+		{Index: 4, URL: "https://www.youtube.com/watch?v=c"},
+		{Index: 5},
+	}
+	pending := selectSubscriptionEntries(entries, []string{"a"})
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending entries, got %#v", pending)
+	}
+	if pending[0].ID != "b" || subscriptionEntryKey(pending[1]) != "https://www.youtube.com/watch?v=c" {
+		t.Fatalf("unexpected pending entries %#v", pending)
+	}
+}
+
+func TestBuildSubscriptionDownloadRequestFallsBackToPlaylistItem(t *testing.T) {
+	t.Parallel()
+
+	item := library.Subscription{
+		ID:                "sub-1",
+		URL:               "https://www.youtube.com/@creator/videos",
+		LibraryID:         "lib-1",
+		Quality:           "best",
+		SubtitleLangs:     []string{"en"},
+		TranscodePresetID: "preset-1",
+	}
+	direct := buildSubscriptionDownloadRequest(item, item.URL, dto.YTDLPPlaylistEntry{
+		Index: 2,
+		ID:    "abc",
+		URL:   "https://www.youtube.com/watch?v=abc",
+		Title: "Episode",
+	})
+	if direct.URL != "https://www.youtube.com/watch?v=abc" || direct.PlaylistItems != "" {
+		t.Fatalf("expected a direct entry download, got %#v", direct)
+	}
+	if direct.Source != subscriptionSource || direct.Caller != "subscription:sub-1" || direct.LibraryID != "lib-1" {
+		t.Fatalf("unexpected request attribution %#v", direct)
+	}
+	if direct.TranscodePresetID != "preset-1" || len(direct.SubtitleLangs) != 1 {
+		t.Fatalf("expected subscription presets to carry over, got %#v", direct)
+	}
+
+	fallback := buildSubscriptionDownloadRequest(item, item.URL, dto.YTDLPPlaylistEntry{Index: 7, ID: "xyz", URL: "xyz"})
+	if fallback.URL != item.URL || fallback.PlaylistItems != "7" {
+		t.Fatalf("expected a playlist item download, got %#v", fallback)
+	}
+}
+
+func TestSaveAndDeleteSubscriptionWithSQLiteRepo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := persistence.OpenSQLite(ctx, persistence.SQLiteConfig{
+		Path: filepath.Join(t.TempDir(), "library-subscriptions.db"),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	now := time.Date(2026, 4, 3, 9, 0, 0, 0, time.UTC)
+	repo := libraryrepo.NewSQLiteSubscriptionRepository(db.Bun)
+	scheduler := &subscriptionSchedulerStub{}
+	service := &LibraryService{
+		subscriptions: repo,
+		nowFunc:       func() time.Time { return now },
+		runCancels:    make(map[string]context.CancelFunc),
+	}
+	service.SetSubscriptionScheduler(scheduler)
+
+	if _, err := service.SaveSubscription(ctx, dto.LibrarySubscriptionDTO{URL: "https://www.youtube.com/@creator", PollIntervalMinutes: 5}); !errors.Is(err, library.ErrInvalidSubscription) {
+		t.Fatalf("expected an interval below the minimum to be rejected, got %v", err)
+	}
+
+	saved, err := service.SaveSubscription(ctx, dto.LibrarySubscriptionDTO{
+		URL:           "https://www.youtube.com/@creator",
+		SubtitleLangs: []string{"en", "en", " ja "},
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("save subscription: %v", err)
+	}
+	if saved.ID == "" || saved.URL != "https://www.youtube.com/@creator/videos" {
+		t.Fatalf("unexpected saved subscription %#v", saved)
+	}
+	if saved.PollIntervalMinutes != library.DefaultSubscriptionPollIntervalMinutes {
+		t.Fatalf("expected the default poll interval, got %d", saved.PollIntervalMinutes)
+	}
+	if len(scheduler.scheduled) != 1 || scheduler.scheduled[0].ID != saved.ID {
+		t.Fatalf("expected the subscription to be scheduled, got %#v", scheduler.scheduled)
+	}
+
+	if err := repo.SaveArchiveEntries(ctx, []library.SubscriptionArchiveEntry{
+		{SubscriptionID: saved.ID, EntryID: "a", OperationID: "op-1"},
+		{SubscriptionID: saved.ID, EntryID: "b"},
+	}); err != nil {
+		t.Fatalf("save archive entries: %v", err)
+	}
+	if err := repo.SaveArchiveEntries(ctx, []library.SubscriptionArchiveEntry{{SubscriptionID: saved.ID, EntryID: "a"}}); err != nil {
+		t.Fatalf("re-save archive entry: %v", err)
+	}
+	items, err := service.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("list subscriptions: %v", err)
+	}
+	if len(items) != 1 || items[0].ArchivedCount != 2 || len(items[0].SubtitleLangs) != 2 {
+		t.Fatalf("unexpected listed subscriptions %#v", items)
+	}
+
+	if err := service.DeleteSubscription(ctx, dto.DeleteSubscriptionRequest{ID: saved.ID}); err != nil {
+		t.Fatalf("delete subscription: %v", err)
+	}
+	if len(scheduler.unscheduled) != 1 || scheduler.unscheduled[0] != saved.ID {
+		t.Fatalf("expected the subscription to be unscheduled, got %#v", scheduler.unscheduled)
+	}
+	if _, err := repo.Get(ctx, saved.ID); !errors.Is(err, library.ErrSubscriptionNotFound) {
+		t.Fatalf("expected subscription to be deleted, got %v", err)
+	}
+	archived, err := repo.ListArchiveEntryIDs(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("list archive entries: %v", err)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("expected archive entries to cascade, got %#v", archived)
+	}
+}
+
+func TestLockSubscriptionPollIsPerSubscription(t *testing.T) {
+	service := &LibraryService{}
+	unlockA := service.lockSubscriptionPoll("sub-a")
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock := service.lockSubscriptionPoll("sub-b")
+		unlock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("expected another subscription to poll concurrently")
+	}
+
+	blocked := make(chan struct{})
+	go func() {
+		unlock := service.lockSubscriptionPoll("sub-a")
+		unlock()
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+		t.Fatal("expected a second poll of the same subscription to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockA()
+	<-blocked
+	if len(service.subscriptionPolls) != 0 {
+		t.Fatalf("expected released locks to be dropped, got %d", len(service.subscriptionPolls))
+	}
+}
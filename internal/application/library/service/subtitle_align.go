@@ -0,0 +1,598 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleAlignDefaultMaxOffsetMs    = int64(10000)
+	subtitleAlignDefaultNoiseThreshold = -35.0
+	subtitleAlignDefaultMinSilenceMs   = int64(300)
+	subtitleAlignDefaultMinChangeMs    = int64(60)
+	subtitleAlignLocalWindowMs         = int64(750)
+	subtitleAlignResolutionMs          = int64(10)
+	subtitleAlignGlobalStepMs          = int64(20)
+)
+
+type subtitleAlignOutput struct {
+	FileID              string `json:"fileId,omitempty"`
+	DocumentID          string `json:"documentId,omitempty"`
+	MediaFileID         string `json:"mediaFileId,omitempty"`
+	ReviewSessionID     string `json:"reviewSessionId,omitempty"`
+	SourceRevisionID    string `json:"sourceRevisionId,omitempty"`
+	CandidateRevisionID string `json:"candidateRevisionId,omitempty"`
+	Status              string `json:"status"`
+	CueCount            int    `json:"cueCount"`
+	ChangedCueCount     int    `json:"changedCueCount"`
+	SpeechSegmentCount  int    `json:"speechSegmentCount"`
+	GlobalOffsetMs      int64  `json:"globalOffsetMs"`
+	DurationMs          int64  `json:"durationMs,omitempty"`
+}
+
+type subtitleSpeechSegment struct {
+	StartMs int64
+	EndMs   int64
+}
+
+type subtitleAlignOptions struct {
+	MaxOffsetMs   int64
+	LocalWindowMs int64
+	MinChangeMs   int64
+}
+
+func (service *LibraryService) CreateSubtitleAlignJob(ctx context.Context, request dto.SubtitleAlignRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleAlignRequest(request)
+	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if strings.TrimSpace(sourceFile.LibraryID) == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("source file is not attached to a library")
+	}
+	if _, err := service.resolveSubtitleAlignMediaFile(ctx, sourceFile, request.VideoFileID); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	request.FileID = sourceFile.ID
+	now := service.now()
+	operationID := uuid.NewString()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          operationID,
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "subtitle_align",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleAlignOutputName(sourceFile.Name),
+		Correlation: library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID)},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  marshalJSON(subtitleAlignOutput{FileID: sourceFile.ID, Status: "queued"}),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			2,
+			progressText("library.progressDetail.subtitleAlignQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleAlignOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleAlignOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleAlignRequest) {
+	request = normalizeSubtitleAlignRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, 0)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	sourceFile, document, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	sourceContent := strings.TrimSpace(document.WorkingContent)
+	if sourceContent == "" {
+		sourceContent = strings.TrimSpace(document.OriginalContent)
+	}
+	if sourceContent == "" {
+		service.failSubtitleAlignOperation(ctx, operation, errors.New("subtitle content is empty"))
+		return
+	}
+	sourceFormat := detectSubtitleFormat(document.Format, sourceFile.Storage.LocalPath, document.Format)
+	sourceDocument := parseSubtitleDocument(sourceContent, sourceFormat)
+	if len(sourceDocument.Cues) == 0 {
+		service.failSubtitleAlignOperation(ctx, operation, errors.New("subtitle document has no cues"))
+		return
+	}
+	mediaFile, err := service.resolveSubtitleAlignMediaFile(ctx, sourceFile, request.VideoFileID)
+	if err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, mediaFile.Storage.LocalPath)
+	if err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	if probe.DurationMs <= 0 {
+		service.failSubtitleAlignOperation(ctx, operation, errors.New("source media duration is unknown"))
+		return
+	}
+
+	output := subtitleAlignOutput{
+		FileID:      sourceFile.ID,
+		DocumentID:  sourceFile.Storage.DocumentID,
+		MediaFileID: mediaFile.ID,
+		Status:      "running",
+		CueCount:    len(sourceDocument.Cues),
+		DurationMs:  probe.DurationMs,
+	}
+	operation.Status = library.OperationStatusRunning
+	now := service.now()
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		2,
+		progressText("library.progressDetail.preparingSubtitleAlign"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	reporter := newFFmpegProgressReporter(service, &operation, probe.DurationMs)
+	reporter.stage = progressText("library.progress.aligning")
+	reporter.message = progressText("library.progressDetail.detectingSpeech")
+	outputText, err := service.runFFmpegCommandWithReporter(
+		runCtx,
+		reporter,
+		ffmpegExecPath,
+		buildSubtitleAlignSilenceDetectArgs(mediaFile.Storage.LocalPath, request),
+		"",
+	)
+	if err != nil {
+		message := strings.TrimSpace(outputText)
+		if message == "" || runCtx.Err() != nil {
+			message = err.Error()
+		}
+		service.failSubtitleAlignOperation(ctx, operation, fmt.Errorf("ffmpeg speech detection failed: %s", tailWhisperCPPOutput(message, 5)))
+		return
+	}
+	speech := parseFFmpegSilenceDetectOutput(outputText, probe.DurationMs)
+	if len(speech) == 0 {
+		service.failSubtitleAlignOperation(ctx, operation, errors.New("no speech detected in source media"))
+		return
+	}
+	output.SpeechSegmentCount = len(speech)
+
+	aligned, globalOffsetMs := alignSubtitleCuesToSpeech(sourceDocument.Cues, speech, probe.DurationMs, subtitleAlignOptions{
+		MaxOffsetMs:   request.MaxOffsetMs,
+		LocalWindowMs: subtitleAlignLocalWindowMs,
+		MinChangeMs:   request.MinChangeMs,
+	})
+	output.GlobalOffsetMs = globalOffsetMs
+	candidateDocument := sourceDocument
+	candidateDocument.Cues = aligned
+	suggestions := buildSubtitleAlignSuggestions(sourceDocument, candidateDocument)
+	output.ChangedCueCount = len(suggestions)
+
+	if len(suggestions) > 0 {
+		sourceRevision, err := service.createSubtitleRevision(runCtx, sourceFile, sourceFormat, sourceContent, "snapshot", operation.ID, "")
+		if err != nil {
+			service.failSubtitleAlignOperation(ctx, operation, err)
+			return
+		}
+		candidateContent := renderSubtitleContent(candidateDocument, sourceFormat)
+		if preserved, ok := renderSubtitleContentPreservingSource(candidateDocument, sourceFormat, nil, "", sourceContent); ok {
+			candidateContent = preserved
+		}
+		candidateRevision, err := service.createSubtitleRevision(runCtx, sourceFile, sourceFormat, candidateContent, "align_candidate", operation.ID, "")
+		if err != nil {
+			service.failSubtitleAlignOperation(ctx, operation, err)
+			return
+		}
+		session, err := service.createPendingSubtitleReviewSession(runCtx, sourceFile, "align", operation.ID, sourceRevision, candidateRevision, suggestions)
+		if err != nil {
+			service.failSubtitleAlignOperation(ctx, operation, err)
+			return
+		}
+		output.ReviewSessionID = session.ID
+		output.SourceRevisionID = sourceRevision.ID
+		output.CandidateRevisionID = candidateRevision.ID
+	}
+
+	finishedAt := service.now()
+	output.Status = "completed"
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.Metrics = buildOperationMetricsForOperation(nil, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		2,
+		2,
+		progressText("library.progressDetail.subtitleAlignCompleted"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Category:    "operation",
+		Action:      operation.Kind,
+		DisplayName: operation.DisplayName,
+		Status:      string(operation.Status),
+		Source: library.HistoryRecordSource{
+			Kind:  resolveHistorySourceKind(request.Source),
+			RunID: strings.TrimSpace(request.RunID),
+		},
+		Refs:          library.HistoryRecordRefs{OperationID: operation.ID},
+		Metrics:       operation.Metrics,
+		OperationMeta: &library.OperationRecordMeta{Kind: operation.Kind},
+		OccurredAt:    &finishedAt,
+		CreatedAt:     &finishedAt,
+		UpdatedAt:     &finishedAt,
+	})
+	if err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleAlignOperation(ctx, operation, err)
+		return
+	}
+	_ = service.touchLibrary(ctx, sourceFile.LibraryID, finishedAt)
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	service.publishWorkspaceProjectUpdate(sourceFile.LibraryID)
+}
+
+// resolveSubtitleAlignMediaFile picks the media track to align against: an explicit
+// file, then the subtitle's lineage root, then the newest media file in the library.
+func (service *LibraryService) resolveSubtitleAlignMediaFile(ctx context.Context, subtitleFile library.LibraryFile, mediaFileID string) (library.LibraryFile, error) {
+	if trimmed := strings.TrimSpace(mediaFileID); trimmed != "" {
+		item, err := service.files.Get(ctx, trimmed)
+		if err != nil {
+			return library.LibraryFile{}, err
+		}
+		if !isSubtitleAlignMediaFile(item) {
+			return library.LibraryFile{}, fmt.Errorf("file %q is not a local video or audio file", item.Name)
+		}
+		return item, nil
+	}
+	if rootID := strings.TrimSpace(subtitleFile.Lineage.RootFileID); rootID != "" && rootID != subtitleFile.ID {
+		if item, err := service.files.Get(ctx, rootID); err == nil && isSubtitleAlignMediaFile(item) {
+			return item, nil
+		}
+	}
+	items, err := service.files.ListByLibraryID(ctx, subtitleFile.LibraryID)
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	candidates := make([]library.LibraryFile, 0, len(items))
+	for _, item := range items {
+		if isSubtitleAlignMediaFile(item) {
+			candidates = append(candidates, item)
+		}
+	}
+	if len(candidates) == 0 {
+		return library.LibraryFile{}, fmt.Errorf("no video or audio file found to align against")
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		leftRank := subtitleAlignMediaRank(candidates[i].Kind)
+		rightRank := subtitleAlignMediaRank(candidates[j].Kind)
+		if leftRank != rightRank {
+			return leftRank < rightRank
+		}
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+	return candidates[0], nil
+}
+
+func isSubtitleAlignMediaFile(item library.LibraryFile) bool {
+	if item.State.Deleted || strings.TrimSpace(item.Storage.LocalPath) == "" {
+		return false
+	}
+	return subtitleAlignMediaRank(item.Kind) >= 0
+}
+
+func subtitleAlignMediaRank(kind library.FileKind) int {
+	switch kind {
+	case library.FileKindVideo:
+		return 0
+	case library.FileKindTranscode:
+		return 1
+	case library.FileKindAudio:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// buildSubtitleAlignSilenceDetectArgs band-limits the audio to the voice range before
+// running silencedetect so music beds and rumble are less likely to count as speech.
+func buildSubtitleAlignSilenceDetectArgs(inputPath string, request dto.SubtitleAlignRequest) []string {
+	filter := fmt.Sprintf(
+		"highpass=f=200,lowpass=f=3400,silencedetect=noise=%sdB:d=%s",
+		strconv.FormatFloat(request.NoiseThreshold, 'f', -1, 64),
+		strconv.FormatFloat(float64(request.MinSilenceMs)/1000, 'f', 3, 64),
+	)
+	return []string{
+		"-hide_banner",
+		"-i", inputPath,
+		"-vn",
+		"-af", filter,
+		"-f", "null",
+		"-",
+	}
+}
+
+// parseFFmpegSilenceDetectOutput turns silencedetect log lines into the complementary
+// list of speech segments within [0, durationMs].
+func parseFFmpegSilenceDetectOutput(output string, durationMs int64) []subtitleSpeechSegment {
+	result := make([]subtitleSpeechSegment, 0)
+	cursor := int64(0)
+	inSilence := false
+	for _, line := range strings.Split(normalizeSubtitleNewlines(output), "\n") {
+		if value, ok := parseSilenceDetectValue(line, "silence_start:"); ok {
+			if !inSilence && value > cursor {
+				result = append(result, subtitleSpeechSegment{StartMs: cursor, EndMs: value})
+			}
+			inSilence = true
+			continue
+		}
+		if value, ok := parseSilenceDetectValue(line, "silence_end:"); ok {
+			cursor = value
+			inSilence = false
+		}
+	}
+	if !inSilence && durationMs > cursor {
+		result = append(result, subtitleSpeechSegment{StartMs: cursor, EndMs: durationMs})
+	}
+	return result
+}
+
+func parseSilenceDetectValue(line string, marker string) (int64, bool) {
+	index := strings.Index(line, marker)
+	if index < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(line[index+len(marker):])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return maxInt64(0, int64(math.Round(seconds*1000))), true
+}
+
+// alignSubtitleCuesToSpeech first finds the constant offset that maximises the overlap
+// between cues and detected speech, then nudges each cue within a small window.
+func alignSubtitleCuesToSpeech(cues []dto.SubtitleCue, speech []subtitleSpeechSegment, durationMs int64, options subtitleAlignOptions) ([]dto.SubtitleCue, int64) {
+	result := append([]dto.SubtitleCue(nil), cues...)
+	if len(cues) == 0 || len(speech) == 0 || durationMs <= 0 {
+		return result, 0
+	}
+	prefix := buildSubtitleSpeechPrefix(speech, durationMs)
+	overlap := func(startMs int64, endMs int64) int64 {
+		return subtitleSpeechOverlap(prefix, startMs, endMs)
+	}
+	globalOffset := int64(0)
+	bestScore := int64(-1)
+	for offset := -options.MaxOffsetMs; offset <= options.MaxOffsetMs; offset += subtitleAlignGlobalStepMs {
+		score := int64(0)
+		for _, cue := range cues {
+			score += overlap(cueStartMS(cue)+offset, cueEndMS(cue)+offset)
+		}
+		if score > bestScore || (score == bestScore && absInt64(offset) < absInt64(globalOffset)) {
+			bestScore = score
+			globalOffset = offset
+		}
+	}
+	if bestScore <= 0 {
+		globalOffset = 0
+	}
+
+	previousEnd := int64(-1)
+	previousOriginalEnd := int64(-1)
+	for index, cue := range cues {
+		startMs := cueStartMS(cue)
+		endMs := cueEndMS(cue)
+		baseStart := startMs + globalOffset
+		baseEnd := endMs + globalOffset
+		localOffset := int64(0)
+		localScore := overlap(baseStart, baseEnd)
+		for offset := -options.LocalWindowMs; offset <= options.LocalWindowMs; offset += subtitleAlignResolutionMs {
+			score := overlap(baseStart+offset, baseEnd+offset)
+			if score > localScore || (score == localScore && absInt64(offset) < absInt64(localOffset)) {
+				localScore = score
+				localOffset = offset
+			}
+		}
+		nextStart := maxInt64(0, baseStart+localOffset)
+		nextEnd := maxInt64(nextStart, baseEnd+localOffset)
+		if previousEnd >= 0 && startMs >= previousOriginalEnd && nextStart < previousEnd {
+			nextEnd += previousEnd - nextStart
+			nextStart = previousEnd
+		}
+		previousEnd = nextEnd
+		previousOriginalEnd = endMs
+		if absInt64(nextStart-startMs) < options.MinChangeMs && absInt64(nextEnd-endMs) < options.MinChangeMs {
+			continue
+		}
+		result[index].Start = formatSubtitleRetimeTimestamp(cue.Start, nextStart)
+		result[index].End = formatSubtitleRetimeTimestamp(cue.End, nextEnd)
+	}
+	return result, globalOffset
+}
+
+func buildSubtitleSpeechPrefix(speech []subtitleSpeechSegment, durationMs int64) []int64 {
+	frames := int(durationMs/subtitleAlignResolutionMs) + 1
+	active := make([]bool, frames)
+	for _, segment := range speech {
+		from := int(maxInt64(0, segment.StartMs/subtitleAlignResolutionMs))
+		to := int(minInt64(int64(frames), segment.EndMs/subtitleAlignResolutionMs))
+		for frame := from; frame < to; frame++ {
+			active[frame] = true
+		}
+	}
+	prefix := make([]int64, frames+1)
+	for frame, speaking := range active {
+		prefix[frame+1] = prefix[frame]
+		if speaking {
+			prefix[frame+1]++
+		}
+	}
+	return prefix
+}
+
+func subtitleSpeechOverlap(prefix []int64, startMs int64, endMs int64) int64 {
+	last := int64(len(prefix) - 1)
+	from := minInt64(last, maxInt64(0, startMs/subtitleAlignResolutionMs))
+	to := minInt64(last, maxInt64(0, endMs/subtitleAlignResolutionMs))
+	if to <= from {
+		return 0
+	}
+	return prefix[to] - prefix[from]
+}
+
+func buildSubtitleAlignSuggestions(sourceDocument dto.SubtitleDocument, candidateDocument dto.SubtitleDocument) []library.SubtitleReviewSuggestion {
+	candidateByIndex := make(map[int]dto.SubtitleCue, len(candidateDocument.Cues))
+	for _, cue := range candidateDocument.Cues {
+		candidateByIndex[cue.Index] = cue
+	}
+	result := make([]library.SubtitleReviewSuggestion, 0)
+	for _, cue := range sourceDocument.Cues {
+		candidateCue, ok := candidateByIndex[cue.Index]
+		if !ok || (cue.Start == candidateCue.Start && cue.End == candidateCue.End) {
+			continue
+		}
+		deltaMs := cueStartMS(candidateCue) - cueStartMS(cue)
+		severity := "info"
+		if absInt64(deltaMs) >= 500 {
+			severity = "warning"
+		}
+		result = append(result, library.SubtitleReviewSuggestion{
+			CueIndex:       cue.Index,
+			OriginalText:   cue.Text,
+			SuggestedText:  candidateCue.Text,
+			OriginalStart:  cue.Start,
+			OriginalEnd:    cue.End,
+			SuggestedStart: candidateCue.Start,
+			SuggestedEnd:   candidateCue.End,
+			Categories:     []string{"timing"},
+			Reason:         fmt.Sprintf("Shift %+d ms to match detected speech.", deltaMs),
+			SourceCode:     "audio_alignment",
+			Severity:       severity,
+		})
+	}
+	return result
+}
+
+func (service *LibraryService) failSubtitleAlignOperation(ctx context.Context, operation library.LibraryOperation, err error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	now := service.now()
+	operation.Status = library.OperationStatusFailed
+	operation.ErrorCode = "subtitle_align_failed"
+	operation.ErrorMessage = strings.TrimSpace(err.Error())
+	operation.FinishedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		0,
+		2,
+		progressText("library.progressDetail.subtitleAlignFailed"),
+	)
+	operation.OutputJSON = marshalJSON(subtitleAlignOutput{FileID: extractSubtitleAlignRequest(operation.InputJSON).FileID, Status: "failed"})
+	if saveErr := service.operations.Save(ctx, operation); saveErr != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+func buildSubtitleAlignOutputName(name string) string {
+	base := strings.TrimSpace(name)
+	if base == "" {
+		base = "Subtitle"
+	}
+	if strings.HasSuffix(strings.ToLower(base), "(auto sync)") {
+		return base
+	}
+	return fmt.Sprintf("%s (Auto Sync)", base)
+}
+
+func normalizeSubtitleAlignRequest(request dto.SubtitleAlignRequest) dto.SubtitleAlignRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.DocumentID = strings.TrimSpace(request.DocumentID)
+	request.Path = strings.TrimSpace(request.Path)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.VideoFileID = strings.TrimSpace(request.VideoFileID)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.MaxOffsetMs <= 0 {
+		request.MaxOffsetMs = subtitleAlignDefaultMaxOffsetMs
+	}
+	if request.NoiseThreshold >= 0 {
+		request.NoiseThreshold = subtitleAlignDefaultNoiseThreshold
+	}
+	if request.MinSilenceMs <= 0 {
+		request.MinSilenceMs = subtitleAlignDefaultMinSilenceMs
+	}
+	if request.MinChangeMs <= 0 {
+		request.MinChangeMs = subtitleAlignDefaultMinChangeMs
+	}
+	return request
+}
+
+func extractSubtitleAlignRequest(inputJSON string) dto.SubtitleAlignRequest {
+	request := dto.SubtitleAlignRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleAlignRequest{}
+	}
+	return normalizeSubtitleAlignRequest(request)
+}
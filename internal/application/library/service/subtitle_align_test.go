@@ -0,0 +1,97 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestParseFFmpegSilenceDetectOutput(t *testing.T) {
+	t.Parallel()
+
+	output := strings.Join([]string{
+		"Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'video.mp4':",
+		"[silencedetect @ 0x7f8] silence_start: 0",
+		"[silencedetect @ 0x7f8] silence_end: 1.5 | silence_duration: 1.5",
+		"[silencedetect @ 0x7f8] silence_start: 4.25",
+		"[silencedetect @ 0x7f8] silence_end: 6 | silence_duration: 1.75",
+		"[silencedetect @ 0x7f8] silence_start: 9.5",
+	}, "\n")
+	segments := parseFFmpegSilenceDetectOutput(output, 12000)
+	expected := []subtitleSpeechSegment{{StartMs: 1500, EndMs: 4250}, {StartMs: 6000, EndMs: 9500}}
+	if len(segments) != len(expected) {
+		t.Fatalf("expected %d speech segments, got %#v", len(expected), segments)
+	}
+	for index, segment := range segments {
+		if segment != expected[index] {
+			t.Fatalf("segment %d: expected %#v, got %#v", index, expected[index], segment)
+		}
+	}
+
+	if got := parseFFmpegSilenceDetectOutput("", 3000); len(got) != 1 || got[0].EndMs != 3000 {
+		t.Fatalf("expected silence-free media to be a single speech segment, got %#v", got)
+	}
+}
+
+func TestAlignSubtitleCuesToSpeechRecoversConstantOffset(t *testing.T) {
+	t.Parallel()
+
+	speech := []subtitleSpeechSegment{
+		{StartMs: 2000, EndMs: 4000},
+		{StartMs: 10000, EndMs: 13000},
+		{StartMs: 20000, EndMs: 21500},
+	}
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:00,800", End: "00:00:02,800", Text: "One"},
+		{Index: 2, Start: "00:00:08,800", End: "00:00:11,800", Text: "Two"},
+		{Index: 3, Start: "00:00:18,800", End: "00:00:20,300", Text: "Three"},
+	}
+	aligned, offset := alignSubtitleCuesToSpeech(cues, speech, 30000, subtitleAlignOptions{
+		MaxOffsetMs:   5000,
+		LocalWindowMs: subtitleAlignLocalWindowMs,
+		MinChangeMs:   subtitleAlignDefaultMinChangeMs,
+	})
+	if offset != 1200 {
+		t.Fatalf("expected global offset 1200ms, got %d", offset)
+	}
+	expectedStarts := []string{"00:00:02,000", "00:00:10,000", "00:00:20,000"}
+	for index, cue := range aligned {
+		if cue.Start != expectedStarts[index] {
+			t.Fatalf("cue %d: expected start %s, got %s", index+1, expectedStarts[index], cue.Start)
+		}
+	}
+
+	suggestions := buildSubtitleAlignSuggestions(dto.SubtitleDocument{Cues: cues}, dto.SubtitleDocument{Cues: aligned})
+	if len(suggestions) != 3 {
+		t.Fatalf("expected 3 timing suggestions, got %d", len(suggestions))
+	}
+	first := suggestions[0]
+	if first.OriginalStart != "00:00:00,800" || first.SuggestedStart != "00:00:02,000" || first.Severity != "warning" {
+		t.Fatalf("unexpected suggestion: %#v", first)
+	}
+	if first.OriginalText != first.SuggestedText {
+		t.Fatalf("expected alignment to leave cue text unchanged, got %#v", first)
+	}
+}
+
+func TestAlignSubtitleCuesToSpeechKeepsInSyncCues(t *testing.T) {
+	t.Parallel()
+
+	speech := []subtitleSpeechSegment{{StartMs: 1000, EndMs: 3000}, {StartMs: 5000, EndMs: 7000}}
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01.000", End: "00:00:03.000", Text: "In sync"},
+		{Index: 2, Start: "00:00:05.020", End: "00:00:07.000", Text: "Close enough"},
+	}
+	aligned, offset := alignSubtitleCuesToSpeech(cues, speech, 10000, subtitleAlignOptions{
+		MaxOffsetMs:   2000,
+		LocalWindowMs: subtitleAlignLocalWindowMs,
+		MinChangeMs:   subtitleAlignDefaultMinChangeMs,
+	})
+	if offset != 0 {
+		t.Fatalf("expected no global offset, got %d", offset)
+	}
+	if suggestions := buildSubtitleAlignSuggestions(dto.SubtitleDocument{Cues: cues}, dto.SubtitleDocument{Cues: aligned}); len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions for in-sync cues, got %#v", suggestions)
+	}
+}
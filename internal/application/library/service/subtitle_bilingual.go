@@ -0,0 +1,882 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleBilingualDefaultMinOverlapRatio = 0.3
+	subtitleBilingualUnmatchedKeep          = "keep"
+	subtitleBilingualUnmatchedDrop          = "drop"
+	subtitleBilingualSplitAuto              = "auto"
+	subtitleBilingualSplitScript            = "script"
+	subtitleBilingualSplitHalves            = "halves"
+)
+
+type subtitleBilingualMergeOutput struct {
+	PrimaryFileID           string `json:"primaryFileId,omitempty"`
+	SecondaryFileID         string `json:"secondaryFileId,omitempty"`
+	FileID                  string `json:"fileId,omitempty"`
+	DocumentID              string `json:"documentId,omitempty"`
+	Status                  string `json:"status"`
+	CueCount                int    `json:"cueCount"`
+	PairedCueCount          int    `json:"pairedCueCount"`
+	UnmatchedPrimaryCount   int    `json:"unmatchedPrimaryCount"`
+	UnmatchedSecondaryCount int    `json:"unmatchedSecondaryCount"`
+}
+
+type subtitleBilingualSplitOutput struct {
+	FileID              string `json:"fileId,omitempty"`
+	PrimaryFileID       string `json:"primaryFileId,omitempty"`
+	PrimaryDocumentID   string `json:"primaryDocumentId,omitempty"`
+	SecondaryFileID     string `json:"secondaryFileId,omitempty"`
+	SecondaryDocumentID string `json:"secondaryDocumentId,omitempty"`
+	SplitMode           string `json:"splitMode,omitempty"`
+	Status              string `json:"status"`
+	CueCount            int    `json:"cueCount"`
+	PrimaryCueCount     int    `json:"primaryCueCount"`
+	SecondaryCueCount   int    `json:"secondaryCueCount"`
+}
+
+type subtitleBilingualMergeStats struct {
+	Paired             int
+	UnmatchedPrimary   int
+	UnmatchedSecondary int
+}
+
+type subtitleBilingualEntry struct {
+	StartMS       int64
+	EndMS         int64
+	Order         int
+	PrimaryText   string
+	SecondaryText string
+}
+
+func (service *LibraryService) MergeBilingualSubtitles(ctx context.Context, request dto.SubtitleBilingualMergeRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleBilingualMergeRequest(request)
+	primaryFile, secondaryFile, err := service.resolveSubtitleBilingualMergeSources(ctx, request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	request.LibraryID = primaryFile.LibraryID
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   primaryFile.LibraryID,
+		Kind:        "subtitle_bilingual_merge",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleBilingualMergeOutputName(primaryFile.Name),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON: marshalJSON(subtitleBilingualMergeOutput{
+			PrimaryFileID:   primaryFile.ID,
+			SecondaryFileID: secondaryFile.ID,
+			Status:          "queued",
+		}),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.subtitleBilingualMergeQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleBilingualMergeOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) SplitBilingualSubtitle(ctx context.Context, request dto.SubtitleBilingualSplitRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleBilingualSplitRequest(request)
+	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if strings.TrimSpace(sourceFile.LibraryID) == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("source file is not attached to a library")
+	}
+	request.FileID = sourceFile.ID
+	request.LibraryID = sourceFile.LibraryID
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "subtitle_bilingual_split",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleBilingualSplitDisplayName(sourceFile.Name),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  marshalJSON(subtitleBilingualSplitOutput{FileID: sourceFile.ID, SplitMode: request.SplitMode, Status: "queued"}),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.subtitleBilingualSplitQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleBilingualSplitOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleBilingualMergeOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleBilingualMergeRequest) {
+	request = normalizeSubtitleBilingualMergeRequest(request)
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	primaryFile, secondaryFile, err := service.resolveSubtitleBilingualMergeSources(ctx, request)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	_, primaryDocument, err := service.resolveSubtitleFileAndDocument(ctx, primaryFile.ID, "", "")
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	_, secondaryDocument, err := service.resolveSubtitleFileAndDocument(ctx, secondaryFile.ID, "", "")
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	primaryParsed, err := parseSubtitleBilingualSource(primaryFile, primaryDocument)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, fmt.Errorf("primary subtitle: %w", err))
+		return
+	}
+	secondaryParsed, err := parseSubtitleBilingualSource(secondaryFile, secondaryDocument)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, fmt.Errorf("secondary subtitle: %w", err))
+		return
+	}
+
+	output := subtitleBilingualMergeOutput{
+		PrimaryFileID:   primaryFile.ID,
+		SecondaryFileID: secondaryFile.ID,
+		Status:          "running",
+	}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		1,
+		progressText("library.progressDetail.mergingBilingualSubtitles"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	merged, stats := mergeBilingualSubtitleDocuments(
+		primaryParsed,
+		secondaryParsed,
+		request.MinOverlapRatio,
+		request.UnmatchedMode == subtitleBilingualUnmatchedKeep,
+	)
+	if len(merged.Cues) == 0 {
+		service.failSubtitleBilingualOperation(ctx, operation, errors.New("no cues left after pairing"))
+		return
+	}
+	output.CueCount = len(merged.Cues)
+	output.PairedCueCount = stats.Paired
+	output.UnmatchedPrimaryCount = stats.UnmatchedPrimary
+	output.UnmatchedSecondaryCount = stats.UnmatchedSecondary
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+
+	outputFormat := detectSubtitleFormat(request.OutputFormat, primaryFile.Storage.LocalPath, primaryDocument.Format)
+	merged.Format = outputFormat
+	finishedAt := service.now()
+	historySource := library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID}
+	mergedFile, history, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+		LibraryID:      primaryFile.LibraryID,
+		RootFileID:     rootFileID(primaryFile),
+		SourceFileIDs:  []string{primaryFile.ID, secondaryFile.ID},
+		Name:           buildSubtitleBilingualMergeOutputName(primaryFile.Name),
+		OperationID:    operation.ID,
+		OperationKind:  operation.Kind,
+		Format:         outputFormat,
+		SourceMedia:    primaryFile.Media,
+		OriginalSource: renderSubtitleContent(merged, outputFormat),
+		LocalPath:      strings.TrimSpace(primaryFile.Storage.LocalPath),
+		OccurredAt:     finishedAt,
+		HistorySource:  historySource,
+	})
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	output.FileID = mergedFile.ID
+	output.DocumentID = mergedFile.Storage.DocumentID
+	output.Status = "completed"
+	service.completeSubtitleBilingualOperation(
+		ctx,
+		operation,
+		history,
+		[]library.LibraryFile{mergedFile},
+		[]library.LibraryFile{primaryFile, secondaryFile},
+		marshalJSON(output),
+		"library.progressDetail.subtitleBilingualMergeCompleted",
+		finishedAt,
+	)
+}
+
+func (service *LibraryService) runSubtitleBilingualSplitOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleBilingualSplitRequest) {
+	request = normalizeSubtitleBilingualSplitRequest(request)
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	sourceFile, document, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	sourceDocument, err := parseSubtitleBilingualSource(sourceFile, document)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+
+	output := subtitleBilingualSplitOutput{
+		FileID:    sourceFile.ID,
+		SplitMode: request.SplitMode,
+		Status:    "running",
+		CueCount:  len(sourceDocument.Cues),
+	}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		1,
+		progressText("library.progressDetail.splittingBilingualSubtitle"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	primaryCues, secondaryCues, splitMode, err := splitBilingualSubtitleCues(sourceDocument.Cues, request.SplitMode)
+	if err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	if len(primaryCues) == 0 || len(secondaryCues) == 0 {
+		service.failSubtitleBilingualOperation(ctx, operation, errors.New("subtitle does not contain two language tracks"))
+		return
+	}
+	output.SplitMode = splitMode
+	output.PrimaryCueCount = len(primaryCues)
+	output.SecondaryCueCount = len(secondaryCues)
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+
+	outputFormat := detectSubtitleFormat(request.OutputFormat, sourceFile.Storage.LocalPath, document.Format)
+	finishedAt := service.now()
+	historySource := library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID}
+	tracks := []struct {
+		cues     []dto.SubtitleCue
+		language string
+		fallback string
+	}{
+		{cues: primaryCues, language: request.PrimaryLanguage, fallback: "Primary"},
+		{cues: secondaryCues, language: request.SecondaryLanguage, fallback: "Secondary"},
+	}
+	outputFiles := make([]library.LibraryFile, 0, len(tracks))
+	var history library.HistoryRecord
+	for index, track := range tracks {
+		metadata := cloneSubtitleDocumentMetadata(sourceDocument.Metadata)
+		if track.language != "" {
+			metadata["language"] = track.language
+		}
+		trackDocument := dto.SubtitleDocument{Format: outputFormat, Cues: track.cues, Metadata: metadata}
+		trackFile, trackHistory, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+			LibraryID:      sourceFile.LibraryID,
+			RootFileID:     rootFileID(sourceFile),
+			SourceFileIDs:  []string{sourceFile.ID},
+			Name:           buildSubtitleBilingualSplitOutputName(sourceFile.Name, track.language, track.fallback),
+			OperationID:    operation.ID,
+			OperationKind:  operation.Kind,
+			Format:         outputFormat,
+			SourceMedia:    sourceFile.Media,
+			OriginalSource: renderSubtitleContent(trackDocument, outputFormat),
+			LocalPath:      strings.TrimSpace(sourceFile.Storage.LocalPath),
+			OccurredAt:     finishedAt,
+			HistorySource:  historySource,
+		})
+		if err != nil {
+			service.failSubtitleBilingualOperation(ctx, operation, err)
+			return
+		}
+		if index == 0 {
+			history = trackHistory
+		}
+		outputFiles = append(outputFiles, trackFile)
+	}
+	output.PrimaryFileID = outputFiles[0].ID
+	output.PrimaryDocumentID = outputFiles[0].Storage.DocumentID
+	output.SecondaryFileID = outputFiles[1].ID
+	output.SecondaryDocumentID = outputFiles[1].Storage.DocumentID
+	output.Status = "completed"
+	service.completeSubtitleBilingualOperation(
+		ctx,
+		operation,
+		history,
+		outputFiles,
+		[]library.LibraryFile{sourceFile},
+		marshalJSON(output),
+		"library.progressDetail.subtitleBilingualSplitCompleted",
+		finishedAt,
+	)
+}
+
+// completeSubtitleBilingualOperation records the derived tracks on the operation and its
+// history entry; the first output file is treated as the primary result.
+func (service *LibraryService) completeSubtitleBilingualOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	history library.HistoryRecord,
+	outputFiles []library.LibraryFile,
+	sourceFiles []library.LibraryFile,
+	outputJSON string,
+	completedDetailKey string,
+	finishedAt time.Time,
+) {
+	for index := range outputFiles {
+		outputFiles[index].LatestOperationID = operation.ID
+		outputFiles[index].UpdatedAt = finishedAt
+		if err := service.files.Save(ctx, outputFiles[index]); err != nil {
+			service.failSubtitleBilingualOperation(ctx, operation, err)
+			return
+		}
+	}
+	for index := range sourceFiles {
+		sourceFiles[index].LatestOperationID = operation.ID
+		sourceFiles[index].UpdatedAt = finishedAt
+		if err := service.files.Save(ctx, sourceFiles[index]); err != nil {
+			service.failSubtitleBilingualOperation(ctx, operation, err)
+			return
+		}
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.OutputFiles = make([]library.OperationOutputFile, 0, len(outputFiles))
+	fileIDs := make([]string, 0, len(outputFiles))
+	for index, item := range outputFiles {
+		operation.OutputFiles = append(operation.OutputFiles, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+			IsPrimary: index == 0,
+			Deleted:   item.State.Deleted,
+		})
+		fileIDs = append(fileIDs, item.ID)
+	}
+	operation.Metrics = buildOperationMetricsForOperation(outputFiles, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		1,
+		1,
+		progressText(completedDetailKey),
+	)
+	operation.OutputJSON = outputJSON
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+
+	history.Refs.OperationID = operation.ID
+	history.Refs.FileIDs = fileIDs
+	history.Action = operation.Kind
+	history.DisplayName = operation.DisplayName
+	history.Status = string(operation.Status)
+	history.OperationMeta = &library.OperationRecordMeta{Kind: operation.Kind}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, operation.LibraryID, finishedAt); err != nil {
+		service.failSubtitleBilingualOperation(ctx, operation, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	for _, item := range sourceFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	for _, item := range outputFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	service.publishWorkspaceProjectUpdate(operation.LibraryID)
+}
+
+func (service *LibraryService) resolveSubtitleBilingualMergeSources(ctx context.Context, request dto.SubtitleBilingualMergeRequest) (library.LibraryFile, library.LibraryFile, error) {
+	if request.PrimaryFileID == "" || request.SecondaryFileID == "" {
+		return library.LibraryFile{}, library.LibraryFile{}, fmt.Errorf("primaryFileId and secondaryFileId are required")
+	}
+	if request.PrimaryFileID == request.SecondaryFileID {
+		return library.LibraryFile{}, library.LibraryFile{}, fmt.Errorf("primary and secondary subtitles must be different files")
+	}
+	primaryFile, err := service.files.Get(ctx, request.PrimaryFileID)
+	if err != nil {
+		return library.LibraryFile{}, library.LibraryFile{}, err
+	}
+	secondaryFile, err := service.files.Get(ctx, request.SecondaryFileID)
+	if err != nil {
+		return library.LibraryFile{}, library.LibraryFile{}, err
+	}
+	for _, item := range []library.LibraryFile{primaryFile, secondaryFile} {
+		if item.Kind != library.FileKindSubtitle || item.State.Deleted {
+			return library.LibraryFile{}, library.LibraryFile{}, fmt.Errorf("file %q is not an available subtitle", item.Name)
+		}
+	}
+	if strings.TrimSpace(primaryFile.LibraryID) == "" || primaryFile.LibraryID != secondaryFile.LibraryID {
+		return library.LibraryFile{}, library.LibraryFile{}, fmt.Errorf("primary and secondary subtitles must belong to the same library")
+	}
+	if request.LibraryID != "" && request.LibraryID != primaryFile.LibraryID {
+		return library.LibraryFile{}, library.LibraryFile{}, fmt.Errorf("subtitles do not belong to library %q", request.LibraryID)
+	}
+	return primaryFile, secondaryFile, nil
+}
+
+func parseSubtitleBilingualSource(file library.LibraryFile, document library.SubtitleDocument) (dto.SubtitleDocument, error) {
+	content := strings.TrimSpace(document.WorkingContent)
+	if content == "" {
+		content = strings.TrimSpace(document.OriginalContent)
+	}
+	if content == "" {
+		return dto.SubtitleDocument{}, errors.New("subtitle content is empty")
+	}
+	parsed := parseSubtitleDocument(content, detectSubtitleFormat(document.Format, file.Storage.LocalPath, document.Format))
+	if len(parsed.Cues) == 0 {
+		return dto.SubtitleDocument{}, errors.New("subtitle document has no cues")
+	}
+	return parsed, nil
+}
+
+// mergeBilingualSubtitleDocuments attaches every secondary cue to the primary cue it
+// overlaps most, so a primary line the other track split in two still receives both
+// halves. Paired cues keep the primary timing; unmatched cues keep their own.
+func mergeBilingualSubtitleDocuments(
+	primary dto.SubtitleDocument,
+	secondary dto.SubtitleDocument,
+	minOverlapRatio float64,
+	keepUnmatched bool,
+) (dto.SubtitleDocument, subtitleBilingualMergeStats) {
+	stats := subtitleBilingualMergeStats{}
+	assigned := make([][]int, len(primary.Cues))
+	secondaryMatched := make([]bool, len(secondary.Cues))
+	for secondaryIndex, secondaryCue := range secondary.Cues {
+		secondaryStart, secondaryEnd := cueStartMS(secondaryCue), cueEndMS(secondaryCue)
+		bestIndex := -1
+		bestOverlap := int64(0)
+		for primaryIndex, primaryCue := range primary.Cues {
+			overlap := minInt64(cueEndMS(primaryCue), secondaryEnd) - maxInt64(cueStartMS(primaryCue), secondaryStart)
+			if overlap > bestOverlap {
+				bestOverlap = overlap
+				bestIndex = primaryIndex
+			}
+		}
+		if bestIndex < 0 {
+			continue
+		}
+		shorter := minInt64(cueDurationMS(primary.Cues[bestIndex]), secondaryEnd-secondaryStart)
+		if shorter <= 0 || float64(bestOverlap)/float64(shorter) < minOverlapRatio {
+			continue
+		}
+		assigned[bestIndex] = append(assigned[bestIndex], secondaryIndex)
+		secondaryMatched[secondaryIndex] = true
+	}
+
+	entries := make([]subtitleBilingualEntry, 0, len(primary.Cues)+len(secondary.Cues))
+	for primaryIndex, primaryCue := range primary.Cues {
+		texts := make([]string, 0, len(assigned[primaryIndex]))
+		for _, secondaryIndex := range assigned[primaryIndex] {
+			if text := normalizeSubtitleText(secondary.Cues[secondaryIndex].Text); strings.TrimSpace(text) != "" {
+				texts = append(texts, text)
+			}
+		}
+		if len(texts) == 0 {
+			stats.UnmatchedPrimary++
+			if !keepUnmatched {
+				continue
+			}
+		} else {
+			stats.Paired++
+		}
+		entries = append(entries, subtitleBilingualEntry{
+			StartMS:       cueStartMS(primaryCue),
+			EndMS:         cueEndMS(primaryCue),
+			Order:         primaryIndex,
+			PrimaryText:   normalizeSubtitleText(primaryCue.Text),
+			SecondaryText: strings.Join(texts, "\n"),
+		})
+	}
+	for secondaryIndex, secondaryCue := range secondary.Cues {
+		if secondaryMatched[secondaryIndex] {
+			continue
+		}
+		stats.UnmatchedSecondary++
+		if !keepUnmatched {
+			continue
+		}
+		entries = append(entries, subtitleBilingualEntry{
+			StartMS:       cueStartMS(secondaryCue),
+			EndMS:         cueEndMS(secondaryCue),
+			Order:         len(primary.Cues) + secondaryIndex,
+			SecondaryText: normalizeSubtitleText(secondaryCue.Text),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].StartMS != entries[j].StartMS {
+			return entries[i].StartMS < entries[j].StartMS
+		}
+		return entries[i].Order < entries[j].Order
+	})
+
+	cues := make([]dto.SubtitleCue, 0, len(entries))
+	primaryTexts := make([]string, 0, len(entries))
+	secondaryTexts := make([]string, 0, len(entries))
+	for index, entry := range entries {
+		cues = append(cues, dto.SubtitleCue{
+			Index: index + 1,
+			Start: formatSRTTimestamp(entry.StartMS),
+			End:   formatSRTTimestamp(entry.EndMS),
+			Text:  joinSubtitleExportText(entry.PrimaryText, entry.SecondaryText),
+		})
+		primaryTexts = append(primaryTexts, entry.PrimaryText)
+		secondaryTexts = append(secondaryTexts, entry.SecondaryText)
+	}
+	metadata := cloneSubtitleDocumentMetadata(primary.Metadata)
+	metadata[subtitleExportDisplayModeKey] = "bilingual"
+	metadata[subtitleExportPrimaryTextsKey] = primaryTexts
+	metadata[subtitleExportSecondaryTextsKey] = secondaryTexts
+	return dto.SubtitleDocument{Format: primary.Format, Cues: cues, Metadata: metadata}, stats
+}
+
+// splitBilingualSubtitleCues separates stacked bilingual lines. "script" assigns lines by
+// writing system once a dominant primary/secondary script pair is detected; "halves"
+// gives the upper half of each cue to the primary track; "auto" prefers script.
+func splitBilingualSubtitleCues(cues []dto.SubtitleCue, mode string) ([]dto.SubtitleCue, []dto.SubtitleCue, string, error) {
+	primaryScript, secondaryScript, detected := detectBilingualSubtitleScripts(cues)
+	resolved := mode
+	switch mode {
+	case subtitleBilingualSplitScript:
+		if !detected {
+			return nil, nil, "", errors.New("could not detect two distinct scripts in subtitle lines")
+		}
+	case subtitleBilingualSplitHalves:
+	default:
+		resolved = subtitleBilingualSplitHalves
+		if detected {
+			resolved = subtitleBilingualSplitScript
+		}
+	}
+	primary := make([]dto.SubtitleCue, 0, len(cues))
+	secondary := make([]dto.SubtitleCue, 0, len(cues))
+	for _, cue := range cues {
+		lines := splitSubtitleBilingualLines(cue.Text)
+		primaryLines := make([]string, 0, len(lines))
+		secondaryLines := make([]string, 0, len(lines))
+		if resolved == subtitleBilingualSplitScript {
+			current := primaryScript
+			for _, line := range lines {
+				if script := subtitleLineScript(line); script != "" {
+					current = script
+				}
+				if current == secondaryScript {
+					secondaryLines = append(secondaryLines, line)
+				} else {
+					primaryLines = append(primaryLines, line)
+				}
+			}
+		} else {
+			middle := (len(lines) + 1) / 2
+			primaryLines = append(primaryLines, lines[:middle]...)
+			secondaryLines = append(secondaryLines, lines[middle:]...)
+		}
+		if len(primaryLines) > 0 {
+			primary = append(primary, dto.SubtitleCue{Index: len(primary) + 1, Start: cue.Start, End: cue.End, Text: strings.Join(primaryLines, "\n")})
+		}
+		if len(secondaryLines) > 0 {
+			secondary = append(secondary, dto.SubtitleCue{Index: len(secondary) + 1, Start: cue.Start, End: cue.End, Text: strings.Join(secondaryLines, "\n")})
+		}
+	}
+	return primary, secondary, resolved, nil
+}
+
+// detectBilingualSubtitleScripts looks at multi-line cues for the most common
+// "first script, then a different script" pattern and accepts it when at least half
+// of those cues follow it.
+func detectBilingualSubtitleScripts(cues []dto.SubtitleCue) (string, string, bool) {
+	counts := make(map[[2]string]int)
+	multiLine := 0
+	for _, cue := range cues {
+		lines := splitSubtitleBilingualLines(cue.Text)
+		if len(lines) < 2 {
+			continue
+		}
+		multiLine++
+		first := ""
+		for _, line := range lines {
+			script := subtitleLineScript(line)
+			if script == "" {
+				continue
+			}
+			if first == "" {
+				first = script
+				continue
+			}
+			if script != first {
+				counts[[2]string{first, script}]++
+				break
+			}
+		}
+	}
+	best := [2]string{}
+	bestCount := 0
+	for pair, count := range counts {
+		if count > bestCount || (count == bestCount && pair[0]+pair[1] < best[0]+best[1]) {
+			best = pair
+			bestCount = count
+		}
+	}
+	if bestCount == 0 || bestCount*2 < multiLine {
+		return "", "", false
+	}
+	return best[0], best[1], true
+}
+
+func splitSubtitleBilingualLines(text string) []string {
+	lines := strings.Split(normalizeSubtitleText(text), "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+var subtitleScriptTables = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{name: "cjk", tables: []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana}},
+	{name: "hangul", tables: []*unicode.RangeTable{unicode.Hangul}},
+	{name: "cyrillic", tables: []*unicode.RangeTable{unicode.Cyrillic}},
+	{name: "arabic", tables: []*unicode.RangeTable{unicode.Arabic}},
+	{name: "hebrew", tables: []*unicode.RangeTable{unicode.Hebrew}},
+	{name: "greek", tables: []*unicode.RangeTable{unicode.Greek}},
+	{name: "thai", tables: []*unicode.RangeTable{unicode.Thai}},
+	{name: "devanagari", tables: []*unicode.RangeTable{unicode.Devanagari}},
+	{name: "latin", tables: []*unicode.RangeTable{unicode.Latin}},
+}
+
+// subtitleLineScript returns the writing system that covers most letters of a line,
+// or "" for lines without letters (music notes, numbers, punctuation).
+func subtitleLineScript(line string) string {
+	counts := make([]int, len(subtitleScriptTables))
+	for _, character := range subtitleDeliveryMarkupPattern.ReplaceAllString(line, "") {
+		for index, script := range subtitleScriptTables {
+			if unicode.IsOneOf(script.tables, character) {
+				counts[index]++
+				break
+			}
+		}
+	}
+	best := -1
+	for index, count := range counts {
+		if count > 0 && (best < 0 || count > counts[best]) {
+			best = index
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return subtitleScriptTables[best].name
+}
+
+func (service *LibraryService) failSubtitleBilingualOperation(ctx context.Context, operation library.LibraryOperation, err error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	now := service.now()
+	detailKey := "library.progressDetail.subtitleBilingualMergeFailed"
+	outputJSON := marshalJSON(subtitleBilingualMergeOutput{
+		PrimaryFileID:   extractSubtitleBilingualMergeRequest(operation.InputJSON).PrimaryFileID,
+		SecondaryFileID: extractSubtitleBilingualMergeRequest(operation.InputJSON).SecondaryFileID,
+		Status:          "failed",
+	})
+	if operation.Kind == "subtitle_bilingual_split" {
+		detailKey = "library.progressDetail.subtitleBilingualSplitFailed"
+		request := extractSubtitleBilingualSplitRequest(operation.InputJSON)
+		outputJSON = marshalJSON(subtitleBilingualSplitOutput{FileID: request.FileID, SplitMode: request.SplitMode, Status: "failed"})
+	}
+	operation.Status = library.OperationStatusFailed
+	operation.ErrorCode = operation.Kind + "_failed"
+	operation.ErrorMessage = strings.TrimSpace(err.Error())
+	operation.FinishedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		0,
+		1,
+		progressText(detailKey),
+	)
+	operation.OutputJSON = outputJSON
+	if saveErr := service.operations.Save(ctx, operation); saveErr != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+func buildSubtitleBilingualMergeOutputName(name string) string {
+	base := strings.TrimSpace(name)
+	if base == "" {
+		base = "Subtitle"
+	}
+	if strings.HasSuffix(strings.ToLower(base), "(bilingual)") {
+		return base
+	}
+	return fmt.Sprintf("%s (Bilingual)", base)
+}
+
+func buildSubtitleBilingualSplitDisplayName(name string) string {
+	base := strings.TrimSpace(name)
+	if base == "" {
+		base = "Subtitle"
+	}
+	return fmt.Sprintf("%s (Split)", base)
+}
+
+func buildSubtitleBilingualSplitOutputName(name string, language string, fallback string) string {
+	base := strings.TrimSpace(name)
+	if base == "" {
+		base = "Subtitle"
+	}
+	if trimmed := strings.TrimSuffix(base, " (Bilingual)"); trimmed != "" {
+		base = trimmed
+	}
+	label := strings.TrimSpace(language)
+	if label == "" {
+		label = fallback
+	}
+	return fmt.Sprintf("%s (%s)", base, label)
+}
+
+func normalizeSubtitleBilingualMergeRequest(request dto.SubtitleBilingualMergeRequest) dto.SubtitleBilingualMergeRequest {
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.PrimaryFileID = strings.TrimSpace(request.PrimaryFileID)
+	request.SecondaryFileID = strings.TrimSpace(request.SecondaryFileID)
+	request.OutputFormat = strings.TrimSpace(request.OutputFormat)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.MinOverlapRatio <= 0 || request.MinOverlapRatio > 1 {
+		request.MinOverlapRatio = subtitleBilingualDefaultMinOverlapRatio
+	}
+	switch strings.ToLower(strings.TrimSpace(request.UnmatchedMode)) {
+	case subtitleBilingualUnmatchedDrop:
+		request.UnmatchedMode = subtitleBilingualUnmatchedDrop
+	default:
+		request.UnmatchedMode = subtitleBilingualUnmatchedKeep
+	}
+	return request
+}
+
+func normalizeSubtitleBilingualSplitRequest(request dto.SubtitleBilingualSplitRequest) dto.SubtitleBilingualSplitRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.DocumentID = strings.TrimSpace(request.DocumentID)
+	request.Path = strings.TrimSpace(request.Path)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.PrimaryLanguage = strings.TrimSpace(request.PrimaryLanguage)
+	request.SecondaryLanguage = strings.TrimSpace(request.SecondaryLanguage)
+	request.OutputFormat = strings.TrimSpace(request.OutputFormat)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	switch strings.ToLower(strings.TrimSpace(request.SplitMode)) {
+	case subtitleBilingualSplitScript:
+		request.SplitMode = subtitleBilingualSplitScript
+	case subtitleBilingualSplitHalves, "lines":
+		request.SplitMode = subtitleBilingualSplitHalves
+	default:
+		request.SplitMode = subtitleBilingualSplitAuto
+	}
+	return request
+}
+
+func extractSubtitleBilingualMergeRequest(inputJSON string) dto.SubtitleBilingualMergeRequest {
+	request := dto.SubtitleBilingualMergeRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleBilingualMergeRequest{}
+	}
+	return normalizeSubtitleBilingualMergeRequest(request)
+}
+
+func extractSubtitleBilingualSplitRequest(inputJSON string) dto.SubtitleBilingualSplitRequest {
+	request := dto.SubtitleBilingualSplitRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleBilingualSplitRequest{}
+	}
+	return normalizeSubtitleBilingualSplitRequest(request)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestMergeBilingualSubtitleDocumentsPairsByOverlap(t *testing.T) {
+	t.Parallel()
+
+	primary := dto.SubtitleDocument{Format: "srt", Cues: []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:04,000", Text: "Hello there, how are you?"},
+		{Index: 2, Start: "00:00:05,000", End: "00:00:06,000", Text: "Fine."},
+		{Index: 3, Start: "00:00:10,000", End: "00:00:11,000", Text: "Untranslated"},
+	}}
+	secondary := dto.SubtitleDocument{Format: "vtt", Cues: []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01.100", End: "00:00:02.400", Text: "你好，"},
+		{Index: 2, Start: "00:00:02.500", End: "00:00:04.100", Text: "你好吗？"},
+		{Index: 3, Start: "00:00:05.050", End: "00:00:06.200", Text: "很好。"},
+		{Index: 4, Start: "00:00:07.000", End: "00:00:08.000", Text: "（音乐）"},
+	}}
+
+	merged, stats := mergeBilingualSubtitleDocuments(primary, secondary, subtitleBilingualDefaultMinOverlapRatio, true)
+	if stats.Paired != 2 || stats.UnmatchedPrimary != 1 || stats.UnmatchedSecondary != 1 {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+	if len(merged.Cues) != 4 {
+		t.Fatalf("expected 4 merged cues, got %#v", merged.Cues)
+	}
+	if merged.Cues[0].Text != "Hello there, how are you?\n你好，\n你好吗？" {
+		t.Fatalf("expected both secondary halves on the first cue, got %q", merged.Cues[0].Text)
+	}
+	if merged.Cues[0].Start != "00:00:01,000" || merged.Cues[0].End != "00:00:04,000" {
+		t.Fatalf("expected paired cue to keep primary timing, got %s --> %s", merged.Cues[0].Start, merged.Cues[0].End)
+	}
+	if merged.Cues[2].Text != "（音乐）" || merged.Cues[2].Start != "00:00:07,000" {
+		t.Fatalf("expected unmatched secondary cue in timeline order, got %#v", merged.Cues[2])
+	}
+	if merged.Cues[3].Text != "Untranslated" {
+		t.Fatalf("expected unmatched primary cue to be kept, got %#v", merged.Cues[3])
+	}
+	secondaryTexts := subtitleExportMetadataStringList(merged.Metadata, subtitleExportSecondaryTextsKey)
+	if len(secondaryTexts) != 4 || secondaryTexts[1] != "很好。" || secondaryTexts[3] != "" {
+		t.Fatalf("unexpected secondary export texts %#v", secondaryTexts)
+	}
+
+	dropped, _ := mergeBilingualSubtitleDocuments(primary, secondary, subtitleBilingualDefaultMinOverlapRatio, false)
+	if len(dropped.Cues) != 2 || dropped.Cues[1].Index != 2 {
+		t.Fatalf("expected only paired cues when dropping unmatched, got %#v", dropped.Cues)
+	}
+}
+
+func TestSplitBilingualSubtitleCuesByScript(t *testing.T) {
+	t.Parallel()
+
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "我们走吧\nLet's go"},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "等一下，\n你听到了吗？\nWait,\ndid you hear that?"},
+		{Index: 3, Start: "00:00:05,000", End: "00:00:06,000", Text: "♪"},
+		{Index: 4, Start: "00:00:07,000", End: "00:00:08,000", Text: "OK"},
+	}
+	primary, secondary, mode, err := splitBilingualSubtitleCues(cues, subtitleBilingualSplitAuto)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if mode != subtitleBilingualSplitScript {
+		t.Fatalf("expected script mode to be detected, got %q", mode)
+	}
+	if len(primary) != 3 || primary[1].Text != "等一下，\n你听到了吗？" || primary[2].Text != "♪" {
+		t.Fatalf("unexpected primary cues %#v", primary)
+	}
+	if len(secondary) != 3 || secondary[1].Text != "Wait,\ndid you hear that?" || secondary[2].Text != "OK" {
+		t.Fatalf("unexpected secondary cues %#v", secondary)
+	}
+	if _, _, _, err := splitBilingualSubtitleCues([]dto.SubtitleCue{{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "Bonjour\nHello"}}, subtitleBilingualSplitScript); err == nil {
+		t.Fatalf("expected script mode to fail for same-script lines")
+	}
+}
+
+func TestSplitBilingualSubtitleCuesByHalves(t *testing.T) {
+	t.Parallel()
+
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "Bonjour\nHello"},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "Merci\nbeaucoup\nThank you"},
+	}
+	primary, secondary, mode, err := splitBilingualSubtitleCues(cues, subtitleBilingualSplitAuto)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if mode != subtitleBilingualSplitHalves {
+		t.Fatalf("expected halves fallback, got %q", mode)
+	}
+	if primary[1].Text != "Merci\nbeaucoup" || secondary[1].Text != "Thank you" {
+		t.Fatalf("unexpected halves split %#v / %#v", primary, secondary)
+	}
+}
@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	// subtitleDeliveryCPSErrorRatio is how far over the reading-speed limit a
+	// cue may go before the violation is reported as an error.
+	subtitleDeliveryCPSErrorRatio = 1.1
+	// subtitleDeliveryMergeMaxGapMs is the largest gap a short cue may be merged
+	// across; wider gaps usually mark a change of speaker or scene.
+	subtitleDeliveryMergeMaxGapMs = 1000
+)
+
+var subtitleDeliveryMarkupPattern = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+
+// subtitleDeliveryFix is the auto-fix result for one source cue. cue stays
+// aligned with the source document so review sessions can compare by index.
+type subtitleDeliveryFix struct {
+	cue      dto.SubtitleCue
+	parts    []dto.SubtitleCue
+	merged   []int
+	absorbed bool
+	actions  []string
+}
+
+func (service *LibraryService) resolveSubtitleDeliveryProfile(ctx context.Context, profileID string, inline *dto.LibraryDeliveryProfileDTO) (library.SubtitleDeliveryProfile, error) {
+	if inline != nil {
+		profile := toDeliveryProfile(*inline)
+		if profile.ID == "" {
+			profile.ID = "custom"
+		}
+		return profile, nil
+	}
+	config, err := service.getModuleConfig(ctx)
+	if err != nil {
+		return library.SubtitleDeliveryProfile{}, err
+	}
+	profile, ok := config.SubtitleDelivery.ResolveSubtitleDeliveryProfile(profileID)
+	if !ok {
+		return library.SubtitleDeliveryProfile{}, fmt.Errorf("delivery profile not found: %s", strings.TrimSpace(profileID))
+	}
+	return profile, nil
+}
+
+// validateSubtitleDeliveryCues reports reading-speed, layout and timing
+// violations of the delivery profile. Cues with unparsable timing are left to
+// validateSubtitleDocument.
+func validateSubtitleDeliveryCues(document dto.SubtitleDocument, profile library.SubtitleDeliveryProfile) []dto.SubtitleValidateIssue {
+	issues := make([]dto.SubtitleValidateIssue, 0)
+	previousEnd := int64(-1)
+	for _, cue := range document.Cues {
+		startMS, okStart := parseTimestampToMilliseconds(cue.Start)
+		endMS, okEnd := parseTimestampToMilliseconds(cue.End)
+		if !okStart || !okEnd {
+			previousEnd = -1
+			continue
+		}
+		lines := subtitleDeliveryLines(cue.Text)
+		if profile.MaxLines > 0 && len(lines) > profile.MaxLines {
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: "error",
+				Code:     "too_many_lines",
+				Message:  fmt.Sprintf("subtitle cue has %d lines (max %d)", len(lines), profile.MaxLines),
+				CueIndex: cue.Index,
+			})
+		}
+		if longest := subtitleDeliveryLongestLine(lines); profile.MaxCharsPerLine > 0 && longest > profile.MaxCharsPerLine {
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: "error",
+				Code:     "line_too_long",
+				Message:  fmt.Sprintf("subtitle line has %d characters (max %d)", longest, profile.MaxCharsPerLine),
+				CueIndex: cue.Index,
+			})
+		}
+		durationMS := endMS - startMS
+		if cps := subtitleDeliveryCPS(lines, durationMS); profile.MaxCPS > 0 && cps > profile.MaxCPS {
+			severity := "warning"
+			if cps > profile.MaxCPS*subtitleDeliveryCPSErrorRatio {
+				severity = "error"
+			}
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: severity,
+				Code:     "reading_speed",
+				Message:  fmt.Sprintf("reading speed is %.1f characters per second (max %.1f)", cps, profile.MaxCPS),
+				CueIndex: cue.Index,
+			})
+		}
+		if profile.MinDurationMs > 0 && durationMS > 0 && durationMS < profile.MinDurationMs {
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: "warning",
+				Code:     "duration_too_short",
+				Message:  fmt.Sprintf("subtitle cue lasts %dms (min %dms)", durationMS, profile.MinDurationMs),
+				CueIndex: cue.Index,
+			})
+		}
+		if profile.MaxDurationMs > 0 && durationMS > profile.MaxDurationMs {
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: "error",
+				Code:     "duration_too_long",
+				Message:  fmt.Sprintf("subtitle cue lasts %dms (max %dms)", durationMS, profile.MaxDurationMs),
+				CueIndex: cue.Index,
+			})
+		}
+		if gap := startMS - previousEnd; profile.MinGapMs > 0 && previousEnd >= 0 && gap >= 0 && gap < profile.MinGapMs {
+			issues = append(issues, dto.SubtitleValidateIssue{
+				Severity: "warning",
+				Code:     "gap_too_short",
+				Message:  fmt.Sprintf("gap to the previous cue is %dms (min %dms)", gap, profile.MinGapMs),
+				CueIndex: cue.Index,
+			})
+		}
+		previousEnd = endMS
+	}
+	return issues
+}
+
+// fixSubtitleDeliveryCues re-wraps lines, splits cues that cannot fit the line
+// limit, merges cues too short to read and adjusts end times for reading speed,
+// duration and gap limits.
+func fixSubtitleDeliveryCues(cues []dto.SubtitleCue, profile library.SubtitleDeliveryProfile) []subtitleDeliveryFix {
+	fixes := make([]subtitleDeliveryFix, 0, len(cues))
+	for _, cue := range cues {
+		fixes = append(fixes, subtitleDeliveryFix{cue: cue})
+	}
+	for index := range fixes {
+		fixSubtitleDeliveryLayout(&fixes[index], profile)
+	}
+	for index := 0; index+1 < len(fixes); index++ {
+		if mergeSubtitleDeliveryCue(fixes, index, profile) {
+			index++
+		}
+	}
+	for index := range fixes {
+		fixSubtitleDeliveryTiming(fixes, index, profile)
+	}
+	return fixes
+}
+
+func fixSubtitleDeliveryLayout(fix *subtitleDeliveryFix, profile library.SubtitleDeliveryProfile) {
+	if !subtitleDeliveryCueTimingValid(fix.cue) || subtitleDeliveryMarkupPattern.MatchString(fix.cue.Text) {
+		return
+	}
+	lines := subtitleDeliveryLines(fix.cue.Text)
+	tooManyLines := profile.MaxLines > 0 && len(lines) > profile.MaxLines
+	tooLong := profile.MaxCharsPerLine > 0 && subtitleDeliveryLongestLine(lines) > profile.MaxCharsPerLine
+	if !tooManyLines && !tooLong {
+		return
+	}
+	wrapped, ok := wrapSubtitleDeliveryText(fix.cue.Text, profile.MaxCharsPerLine, profile.MaxLines)
+	if ok {
+		fix.cue.Text = strings.Join(wrapped, "\n")
+		fix.actions = append(fix.actions, "rewrap")
+		return
+	}
+	if len(wrapped) == 0 || profile.MaxLines <= 0 {
+		return
+	}
+	fix.parts = splitSubtitleDeliveryCue(fix.cue, wrapped, profile)
+	fix.cue.Text = strings.Join(wrapped, "\n")
+	fix.actions = append(fix.actions, "split")
+}
+
+// splitSubtitleDeliveryCue groups wrapped lines into cues of at most MaxLines
+// lines and divides the display time by character count.
+func splitSubtitleDeliveryCue(cue dto.SubtitleCue, lines []string, profile library.SubtitleDeliveryProfile) []dto.SubtitleCue {
+	groups := make([][]string, 0, len(lines)/profile.MaxLines+1)
+	for start := 0; start < len(lines); start += profile.MaxLines {
+		end := minInt(start+profile.MaxLines, len(lines))
+		groups = append(groups, lines[start:end])
+	}
+	startMS := cueStartMS(cue)
+	endMS := cueEndMS(cue)
+	totalChars := subtitleDeliveryCharCount(lines)
+	parts := make([]dto.SubtitleCue, 0, len(groups))
+	consumed := 0
+	partStart := startMS
+	for index, group := range groups {
+		consumed += subtitleDeliveryCharCount(group)
+		partEnd := endMS
+		nextStart := endMS
+		if index < len(groups)-1 {
+			nextStart = startMS + int64(math.Round(float64(endMS-startMS)*float64(consumed)/float64(maxInt(totalChars, 1))))
+			partEnd = nextStart
+			if nextStart-profile.MinGapMs > partStart {
+				partEnd = nextStart - profile.MinGapMs
+			}
+		}
+		parts = append(parts, dto.SubtitleCue{
+			Index: index + 1,
+			Start: formatSubtitleRetimeTimestamp(cue.Start, partStart),
+			End:   formatSubtitleRetimeTimestamp(cue.End, partEnd),
+			Text:  strings.Join(group, "\n"),
+		})
+		partStart = nextStart
+	}
+	return parts
+}
+
+// mergeSubtitleDeliveryCue folds the next cue into fixes[index] when the cue is
+// below the minimum duration and cannot simply be extended.
+func mergeSubtitleDeliveryCue(fixes []subtitleDeliveryFix, index int, profile library.SubtitleDeliveryProfile) bool {
+	current := &fixes[index]
+	next := &fixes[index+1]
+	if profile.MinDurationMs <= 0 || current.absorbed || len(current.parts) > 0 || len(next.parts) > 0 {
+		return false
+	}
+	if !subtitleDeliveryCueTimingValid(current.cue) || !subtitleDeliveryCueTimingValid(next.cue) {
+		return false
+	}
+	startMS := cueStartMS(current.cue)
+	endMS := cueEndMS(current.cue)
+	nextStartMS := cueStartMS(next.cue)
+	nextEndMS := cueEndMS(next.cue)
+	if endMS-startMS >= profile.MinDurationMs || nextStartMS-profile.MinGapMs-startMS >= profile.MinDurationMs {
+		return false
+	}
+	if gap := nextStartMS - endMS; gap < 0 || gap > subtitleDeliveryMergeMaxGapMs {
+		return false
+	}
+	if profile.MaxDurationMs > 0 && nextEndMS-startMS > profile.MaxDurationMs {
+		return false
+	}
+	if subtitleDeliveryMarkupPattern.MatchString(current.cue.Text) || subtitleDeliveryMarkupPattern.MatchString(next.cue.Text) {
+		return false
+	}
+	separator := " "
+	if subtitleDeliveryIsSpaceless(current.cue.Text) && subtitleDeliveryIsSpaceless(next.cue.Text) {
+		separator = ""
+	}
+	mergedText := strings.Join(subtitleDeliveryLines(current.cue.Text), " ") + separator + strings.Join(subtitleDeliveryLines(next.cue.Text), " ")
+	wrapped, ok := wrapSubtitleDeliveryText(mergedText, profile.MaxCharsPerLine, profile.MaxLines)
+	if !ok {
+		return false
+	}
+	if profile.MaxCPS > 0 && subtitleDeliveryCPS(wrapped, nextEndMS-startMS) > profile.MaxCPS {
+		return false
+	}
+	current.cue.Text = strings.Join(wrapped, "\n")
+	current.cue.End = formatSubtitleRetimeTimestamp(current.cue.End, nextEndMS)
+	current.merged = append(current.merged, next.cue.Index)
+	current.actions = append(current.actions, "merge")
+	next.absorbed = true
+	return true
+}
+
+func fixSubtitleDeliveryTiming(fixes []subtitleDeliveryFix, index int, profile library.SubtitleDeliveryProfile) {
+	fix := &fixes[index]
+	if fix.absorbed || len(fix.parts) > 0 || !subtitleDeliveryCueTimingValid(fix.cue) {
+		return
+	}
+	startMS := cueStartMS(fix.cue)
+	endMS := cueEndMS(fix.cue)
+	if endMS <= startMS {
+		return
+	}
+	limitMS := int64(math.MaxInt64)
+	nextStartMS := int64(-1)
+	for nextIndex := index + 1; nextIndex < len(fixes); nextIndex++ {
+		if fixes[nextIndex].absorbed {
+			continue
+		}
+		if subtitleDeliveryCueTimingValid(fixes[nextIndex].cue) {
+			nextStartMS = cueStartMS(fixes[nextIndex].cue)
+			limitMS = nextStartMS - profile.MinGapMs
+		}
+		break
+	}
+	nextEndMS := endMS
+	targetMS := endMS
+	if profile.MinDurationMs > 0 && endMS-startMS < profile.MinDurationMs {
+		targetMS = startMS + profile.MinDurationMs
+	}
+	if chars := subtitleDeliveryCharCount(subtitleDeliveryLines(fix.cue.Text)); profile.MaxCPS > 0 && chars > 0 {
+		targetMS = maxInt64(targetMS, startMS+int64(math.Ceil(float64(chars)*1000/profile.MaxCPS)))
+	}
+	if profile.MaxDurationMs > 0 {
+		targetMS = minInt64(targetMS, startMS+profile.MaxDurationMs)
+	}
+	if extended := minInt64(targetMS, limitMS); extended > endMS {
+		nextEndMS = extended
+		fix.actions = append(fix.actions, "extend")
+	}
+	if profile.MaxDurationMs > 0 && nextEndMS-startMS > profile.MaxDurationMs {
+		nextEndMS = startMS + profile.MaxDurationMs
+		fix.actions = append(fix.actions, "trim")
+	}
+	if profile.MinGapMs > 0 && nextStartMS >= 0 && nextStartMS-nextEndMS < profile.MinGapMs && limitMS > startMS {
+		nextEndMS = limitMS
+		fix.actions = append(fix.actions, "gap")
+	}
+	if nextEndMS != endMS {
+		fix.cue.End = formatSubtitleRetimeTimestamp(fix.cue.End, nextEndMS)
+	}
+}
+
+// buildSubtitleDeliverySuggestions turns auto-fix results into review
+// suggestions keyed by the source cue they replace.
+func buildSubtitleDeliverySuggestions(sourceCues []dto.SubtitleCue, fixes []subtitleDeliveryFix, profile library.SubtitleDeliveryProfile, issues []dto.SubtitleValidateIssue) []library.SubtitleReviewSuggestion {
+	severityByCue := make(map[int]string, len(issues))
+	for _, issue := range issues {
+		if severityByCue[issue.CueIndex] != "error" {
+			severityByCue[issue.CueIndex] = issue.Severity
+		}
+	}
+	result := make([]library.SubtitleReviewSuggestion, 0)
+	for index, fix := range fixes {
+		if index >= len(sourceCues) || fix.absorbed || len(fix.actions) == 0 {
+			continue
+		}
+		source := sourceCues[index]
+		severity := severityByCue[source.Index]
+		if severity == "" {
+			severity = "warning"
+		}
+		suggestion := library.SubtitleReviewSuggestion{
+			CueIndex:         source.Index,
+			OriginalText:     source.Text,
+			SuggestedText:    fix.cue.Text,
+			MergedCueIndexes: append([]int(nil), fix.merged...),
+			Categories:       subtitleDeliveryCategories(fix.actions),
+			Reason:           buildSubtitleDeliveryReason(fix, profile),
+			SourceCode:       "delivery_" + fix.actions[0],
+			Severity:         severity,
+		}
+		if cueStartMS(source) != cueStartMS(fix.cue) || cueEndMS(source) != cueEndMS(fix.cue) {
+			suggestion.OriginalStart = source.Start
+			suggestion.OriginalEnd = source.End
+			suggestion.SuggestedStart = fix.cue.Start
+			suggestion.SuggestedEnd = fix.cue.End
+		}
+		for _, part := range fix.parts {
+			suggestion.SuggestedCues = append(suggestion.SuggestedCues, library.SubtitleReviewSuggestedCue{
+				Start: part.Start,
+				End:   part.End,
+				Text:  part.Text,
+			})
+		}
+		result = append(result, suggestion)
+	}
+	return result
+}
+
+func subtitleDeliveryPreviewCues(fixes []subtitleDeliveryFix) []dto.SubtitleCue {
+	result := make([]dto.SubtitleCue, 0, len(fixes))
+	for _, fix := range fixes {
+		result = append(result, fix.cue)
+	}
+	return result
+}
+
+func subtitleDeliveryCategories(actions []string) []string {
+	result := make([]string, 0, 2)
+	seen := map[string]struct{}{}
+	for _, action := range actions {
+		category := "timing"
+		if action == "rewrap" || action == "split" {
+			category = "layout"
+		}
+		if _, exists := seen[category]; exists {
+			continue
+		}
+		seen[category] = struct{}{}
+		result = append(result, category)
+	}
+	return result
+}
+
+func buildSubtitleDeliveryReason(fix subtitleDeliveryFix, profile library.SubtitleDeliveryProfile) string {
+	parts := make([]string, 0, len(fix.actions))
+	for _, action := range fix.actions {
+		switch action {
+		case "rewrap":
+			parts = append(parts, fmt.Sprintf("Re-wrap lines to fit %d characters per line.", profile.MaxCharsPerLine))
+		case "split":
+			parts = append(parts, fmt.Sprintf("Split into %d cues of at most %d lines.", len(fix.parts), profile.MaxLines))
+		case "merge":
+			parts = append(parts, fmt.Sprintf("Merge with cue %d to reach the minimum duration.", fix.merged[len(fix.merged)-1]))
+		case "extend":
+			parts = append(parts, "Extend display time for reading speed and minimum duration.")
+		case "trim":
+			parts = append(parts, fmt.Sprintf("Shorten display time to %dms.", profile.MaxDurationMs))
+		case "gap":
+			parts = append(parts, fmt.Sprintf("Leave a %dms gap before the next cue.", profile.MinGapMs))
+		}
+	}
+	return fmt.Sprintf("%s: %s", firstNonEmpty(profile.Name, profile.ID), strings.Join(parts, " "))
+}
+
+// wrapSubtitleDeliveryText balances text over as few lines of at most
+// maxChars characters as possible. ok is false when the result still needs
+// more than maxLines lines or a single word is longer than a line.
+func wrapSubtitleDeliveryText(text string, maxChars int, maxLines int) ([]string, bool) {
+	tokens, separator := subtitleDeliveryTokens(text)
+	if len(tokens) == 0 {
+		return nil, false
+	}
+	total := utf8.RuneCountInString(strings.Join(tokens, separator))
+	width := maxChars
+	if width <= 0 {
+		width = total
+	}
+	lines := wrapSubtitleDeliveryTokens(tokens, separator, width)
+	if subtitleDeliveryLongestLine(lines) > width {
+		return nil, false
+	}
+	count := len(lines)
+	for candidate := (total + count - 1) / count; candidate < width; candidate++ {
+		balanced := wrapSubtitleDeliveryTokens(tokens, separator, candidate)
+		if len(balanced) <= count && subtitleDeliveryLongestLine(balanced) <= candidate {
+			lines = balanced
+			break
+		}
+	}
+	return lines, maxLines <= 0 || len(lines) <= maxLines
+}
+
+func wrapSubtitleDeliveryTokens(tokens []string, separator string, width int) []string {
+	lines := make([]string, 0, 2)
+	current := ""
+	currentLength := 0
+	separatorLength := utf8.RuneCountInString(separator)
+	for _, token := range tokens {
+		tokenLength := utf8.RuneCountInString(token)
+		if currentLength > 0 && currentLength+separatorLength+tokenLength > width {
+			lines = append(lines, current)
+			current = ""
+			currentLength = 0
+		}
+		if currentLength > 0 {
+			current += separator
+			currentLength += separatorLength
+		}
+		current += token
+		currentLength += tokenLength
+	}
+	if currentLength > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// subtitleDeliveryTokens splits text into words, or into characters for
+// scripts written without spaces between words.
+func subtitleDeliveryTokens(text string) ([]string, string) {
+	words := strings.Fields(normalizeSubtitleNewlines(text))
+	if len(words) != 1 || !subtitleDeliveryIsSpaceless(words[0]) {
+		return words, " "
+	}
+	tokens := make([]string, 0, utf8.RuneCountInString(words[0]))
+	for _, value := range words[0] {
+		tokens = append(tokens, string(value))
+	}
+	return tokens, ""
+}
+
+func subtitleDeliveryIsSpaceless(text string) bool {
+	for _, value := range text {
+		if unicode.In(value, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai) {
+			return true
+		}
+	}
+	return false
+}
+
+func subtitleDeliveryLines(text string) []string {
+	plain := subtitleDeliveryMarkupPattern.ReplaceAllString(normalizeSubtitleNewlines(text), "")
+	result := make([]string, 0, 2)
+	for _, line := range strings.Split(plain, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+func subtitleDeliveryLongestLine(lines []string) int {
+	longest := 0
+	for _, line := range lines {
+		longest = maxInt(longest, utf8.RuneCountInString(line))
+	}
+	return longest
+}
+
+func subtitleDeliveryCharCount(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		count += utf8.RuneCountInString(line)
+	}
+	return count
+}
+
+func subtitleDeliveryCPS(lines []string, durationMS int64) float64 {
+	if durationMS <= 0 {
+		return 0
+	}
+	return float64(subtitleDeliveryCharCount(lines)) * 1000 / float64(durationMS)
+}
+
+func subtitleDeliveryCueTimingValid(cue dto.SubtitleCue) bool {
+	startMS, okStart := parseTimestampToMilliseconds(cue.Start)
+	endMS, okEnd := parseTimestampToMilliseconds(cue.End)
+	return okStart && okEnd && endMS > startMS
+}
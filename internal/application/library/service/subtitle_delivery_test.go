@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+var subtitleDeliveryTestProfile = library.SubtitleDeliveryProfile{
+	ID:              "test",
+	Name:            "Test",
+	MaxCPS:          20,
+	MaxCharsPerLine: 42,
+	MaxLines:        2,
+	MinGapMs:        83,
+	MinDurationMs:   833,
+	MaxDurationMs:   7000,
+}
+
+func TestLibraryServiceValidateSubtitleReportsDeliveryViolations(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Join([]string{
+		"1",
+		"00:00:01,000 --> 00:00:01,500",
+		"Hi",
+		"",
+		"2",
+		"00:00:01,520 --> 00:00:03,000",
+		"This line is definitely much longer than forty-two characters",
+		"",
+		"3",
+		"00:00:10,000 --> 00:00:19,000",
+		"One",
+		"Two",
+		"Three",
+		"",
+	}, "\n")
+	profile := toDeliveryProfileDTO(subtitleDeliveryTestProfile)
+	result, err := (&LibraryService{}).ValidateSubtitle(context.Background(), dto.SubtitleValidateRequest{
+		Content:         content,
+		Format:          "srt",
+		DeliveryProfile: &profile,
+	})
+	if err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if result.Valid || result.DeliveryProfileID != "test" {
+		t.Fatalf("expected invalid result for profile test, got %#v", result)
+	}
+	expected := []struct {
+		code     string
+		cueIndex int
+		severity string
+	}{
+		{"duration_too_short", 1, "warning"},
+		{"line_too_long", 2, "error"},
+		{"reading_speed", 2, "error"},
+		{"gap_too_short", 2, "warning"},
+		{"too_many_lines", 3, "error"},
+		{"duration_too_long", 3, "error"},
+	}
+	if len(result.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %#v", len(expected), result.Issues)
+	}
+	for index, issue := range result.Issues {
+		want := expected[index]
+		if issue.Code != want.code || issue.CueIndex != want.cueIndex || issue.Severity != want.severity {
+			t.Fatalf("issue %d: expected %+v, got %#v", index, want, issue)
+		}
+	}
+}
+
+func TestFixSubtitleDeliveryCues(t *testing.T) {
+	t.Parallel()
+
+	longText := "The quick brown fox jumps over the lazy dog while the cat watches from the warm windowsill and the bird sings a very long song"
+	cues := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:04,000", Text: "This line is definitely much longer than forty-two characters"},
+		{Index: 2, Start: "00:00:10,000", End: "00:00:10,400", Text: "Hi"},
+		{Index: 3, Start: "00:00:10,600", End: "00:00:12,000", Text: "there"},
+		{Index: 4, Start: "00:00:20,000", End: "00:00:22,000", Text: "Line one\nLine two\nLine three"},
+		{Index: 5, Start: "00:00:22,050", End: "00:00:30,000", Text: longText},
+	}
+	fixes := fixSubtitleDeliveryCues(cues, subtitleDeliveryTestProfile)
+	if len(fixes) != len(cues) {
+		t.Fatalf("expected fixes to stay aligned with source cues, got %d", len(fixes))
+	}
+	if got := fixes[0].cue.Text; got != "This line is definitely much\nlonger than forty-two characters" {
+		t.Fatalf("expected balanced re-wrap, got %q", got)
+	}
+	if got := fixes[1]; got.cue.Text != "Hi there" || got.cue.End != "00:00:12,000" || len(got.merged) != 1 || got.merged[0] != 3 {
+		t.Fatalf("expected short cue to merge with the next cue, got %#v", got)
+	}
+	if !fixes[2].absorbed {
+		t.Fatalf("expected merged cue to be absorbed")
+	}
+	if got := fixes[3].cue; got.Text != "Line one Line two Line three" || got.End != "00:00:21,967" {
+		t.Fatalf("expected three lines to join and leave the minimum gap, got %#v", got)
+	}
+	parts := fixes[4].parts
+	if len(parts) != 2 {
+		t.Fatalf("expected long cue to split in two, got %#v", parts)
+	}
+	if parts[0].Start != "00:00:22,050" || parts[1].End != "00:00:30,000" || cueEndMS(parts[0])+83 != cueStartMS(parts[1]) {
+		t.Fatalf("unexpected split timing: %#v", parts)
+	}
+	words := make([]string, 0)
+	for _, part := range parts {
+		lines := strings.Split(part.Text, "\n")
+		if len(lines) > 2 || subtitleDeliveryLongestLine(lines) > 42 {
+			t.Fatalf("split part exceeds layout limits: %q", part.Text)
+		}
+		words = append(words, strings.Fields(part.Text)...)
+	}
+	if strings.Join(words, " ") != longText {
+		t.Fatalf("expected split to keep every word, got %q", strings.Join(words, " "))
+	}
+
+	suggestions := buildSubtitleDeliverySuggestions(cues, fixes, subtitleDeliveryTestProfile, validateSubtitleDeliveryCues(dto.SubtitleDocument{Cues: cues}, subtitleDeliveryTestProfile))
+	byIndex := make(map[int]library.SubtitleReviewSuggestion, len(suggestions))
+	for _, suggestion := range suggestions {
+		byIndex[suggestion.CueIndex] = suggestion
+	}
+	if _, exists := byIndex[3]; exists || len(suggestions) != 4 {
+		t.Fatalf("expected four suggestions without the absorbed cue, got %#v", suggestions)
+	}
+	if got := byIndex[2]; got.SourceCode != "delivery_merge" || got.SuggestedEnd != "00:00:12,000" || len(got.MergedCueIndexes) != 1 {
+		t.Fatalf("unexpected merge suggestion: %#v", got)
+	}
+	if got := byIndex[5]; got.SourceCode != "delivery_split" || len(got.SuggestedCues) != 2 || got.Severity != "error" {
+		t.Fatalf("unexpected split suggestion: %#v", got)
+	}
+}
+
+func TestWrapSubtitleDeliveryTextWithoutSpaces(t *testing.T) {
+	t.Parallel()
+
+	text := "这是一个非常长的中文字幕句子需要被重新换行以便符合每行字数的限制"
+	lines, ok := wrapSubtitleDeliveryText(text, 16, 2)
+	if !ok || len(lines) != 2 {
+		t.Fatalf("expected two lines, got %v (ok=%v)", lines, ok)
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > 16 {
+			t.Fatalf("line too long: %q", line)
+		}
+	}
+	if strings.Join(lines, "") != text {
+		t.Fatalf("expected wrapped text to keep every character, got %v", lines)
+	}
+	if _, ok := wrapSubtitleDeliveryText(text, 10, 2); ok {
+		t.Fatalf("expected text to need more than two lines of ten characters")
+	}
+}
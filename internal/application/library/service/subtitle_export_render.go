@@ -58,6 +58,16 @@ func renderSubtitleContentWithConfig(
 		return renderITTFromSegments(segments, config, styleDocumentContent)
 	case "fcpxml":
 		return renderFCPXMLFromSegments(segments, config, styleDocumentContent)
+	case "ttml":
+		return renderTTMLFromSegments(segments, config)
+	case "sbv":
+		return renderSBVFromSegments(segments)
+	case "lrc":
+		return renderLRCFromSegments(segments, nil)
+	case "sub":
+		return renderMicroDVDFromSegments(segments, resolveSubtitleSUBFrameRate(document, config))
+	case "stl":
+		return renderEBUSTLFromSegments(segments, resolveSubtitleSTLExportConfig(document, config))
 	default:
 		return renderSRTFromSegments(segments)
 	}
@@ -1009,6 +1019,52 @@ func normalizeITTFrameRateMultiplier(value string) string {
 	return fmt.Sprintf("%d %d", numerator, denominator)
 }
 
+func resolveSubtitleSUBFrameRate(document dto.SubtitleDocument, config *dto.SubtitleExportConfig) float64 {
+	if config != nil && config.SUB != nil && config.SUB.FrameRate > 0 {
+		return config.SUB.FrameRate
+	}
+	if frameRate := subtitleDocumentFrameRate(document); frameRate > 0 {
+		return frameRate
+	}
+	return defaultMicroDVDFrameRate
+}
+
+// resolveSubtitleSTLExportConfig fills in the EBU-STL frame rate from the
+// source document when the export config leaves it unset. STL only knows 25
+// and 30 fps, so other source rates snap to the nearer of the two.
+func resolveSubtitleSTLExportConfig(document dto.SubtitleDocument, config *dto.SubtitleExportConfig) dto.SubtitleSTLExportConfig {
+	result := dto.SubtitleSTLExportConfig{}
+	if config != nil && config.STL != nil {
+		result = *config.STL
+	}
+	if result.FrameRate == 25 || result.FrameRate == 30 {
+		return result
+	}
+	result.FrameRate = ebuSTLDefaultFrameRate
+	if subtitleDocumentFrameRate(document) >= 27.5 {
+		result.FrameRate = 30
+	}
+	return result
+}
+
+func subtitleDocumentFrameRate(document dto.SubtitleDocument) float64 {
+	if document.Metadata == nil {
+		return 0
+	}
+	switch value := document.Metadata[subtitleFrameRateMetadataKey].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err == nil {
+			return parsed
+		}
+	}
+	return 0
+}
+
 func maxInt64(left int64, right int64) int64 {
 	if left > right {
 		return left
@@ -1016,6 +1072,20 @@ func maxInt64(left int64, right int64) int64 {
 	return right
 }
 
+func minInt64(left int64, right int64) int64 {
+	if left < right {
+		return left
+	}
+	return right
+}
+
+func absInt64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
 func maxInt(left int, right int) int {
 	if left > right {
 		return left
@@ -675,3 +675,112 @@ func TestRenderSubtitleContentWithConfigITT(t *testing.T) {
 		t.Fatalf("expected itt round-trip cue text to stay accurate, got %#v", roundTrip.Cues)
 	}
 }
+
+func TestRenderSubtitleContentWithConfigRoundTripsTextFormats(t *testing.T) {
+	t.Parallel()
+
+	document := dto.SubtitleDocument{
+		Format: "srt",
+		Cues: []dto.SubtitleCue{
+			{Index: 1, Start: "00:00:01,000", End: "00:00:02,600", Text: "Hello"},
+			{Index: 2, Start: "00:00:03,000", End: "00:00:05,000", Text: "Second & last"},
+			{Index: 3, Start: "01:02:03,000", End: "01:02:04,000", Text: "Late cue"},
+		},
+	}
+	config := &dto.SubtitleExportConfig{
+		TTML: &dto.SubtitleTTMLExportConfig{Language: "fr-FR"},
+		SUB:  &dto.SubtitleSUBExportConfig{FrameRate: 25},
+	}
+	for _, format := range []string{"sbv", "sub", "lrc", "ttml"} {
+		content := renderSubtitleContentWithConfig(document, format, config, "")
+		roundTrip := parseSubtitleDocument(content, format)
+		if roundTrip.Format != format || len(roundTrip.Cues) != len(document.Cues) {
+			t.Fatalf("%s: expected %d cues after round trip, got %#v\n%s", format, len(document.Cues), roundTrip.Cues, content)
+		}
+		for index, cue := range roundTrip.Cues {
+			source := document.Cues[index]
+			if cueStartMS(cue) != cueStartMS(source) || cueEndMS(cue) != cueEndMS(source) || cue.Text != source.Text {
+				t.Fatalf("%s: cue %d expected %#v, got %#v", format, index+1, source, cue)
+			}
+		}
+	}
+
+	sub := renderSubtitleContentWithConfig(document, "sub", config, "")
+	if !strings.HasPrefix(sub, "{1}{1}25\n{25}{65}Hello\n") {
+		t.Fatalf("expected microdvd frame header and frame timing, got %q", sub)
+	}
+	if got := renderSubtitleContentWithConfig(dto.SubtitleDocument{
+		Format:   "sub",
+		Cues:     document.Cues[:1],
+		Metadata: map[string]any{subtitleFrameRateMetadataKey: 30.0},
+	}, "sub", nil, ""); !strings.HasPrefix(got, "{1}{1}30\n{30}{78}") {
+		t.Fatalf("expected microdvd export to reuse the source frame rate, got %q", got)
+	}
+
+	ttml := renderSubtitleContentWithConfig(document, "ttml", config, "")
+	rootAttrs, _ := parseRenderedITTAttrs(t, ttml)
+	if got := xmlAttributeValue(rootAttrs, "profile"); got != ttmlIMSC1TextProfile {
+		t.Fatalf("expected imsc1 text profile, got %q", got)
+	}
+	if got := xmlAttributeValue(rootAttrs, "lang"); got != "fr-FR" {
+		t.Fatalf("expected ttml language config, got %q", got)
+	}
+
+	lrc := renderSubtitleContentWithConfig(document, "lrc", nil, "")
+	if !strings.HasPrefix(lrc, "[00:01.00]Hello\n[00:02.60]\n[00:03.00]Second & last\n") {
+		t.Fatalf("expected lrc end terminators between gapped cues, got %q", lrc)
+	}
+}
+
+func TestRenderSubtitleContentWithConfigEBUSTLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	longText := strings.Repeat("A long teletext row. ", 8)
+	document := dto.SubtitleDocument{
+		Format: "srt",
+		Cues: []dto.SubtitleCue{
+			{Index: 1, Start: "00:00:01,000", End: "00:00:02,500", Text: "<i>Café</i>\nçà et là"},
+			{Index: 2, Start: "00:00:10,000", End: "00:00:12,000", Text: strings.TrimSpace(longText)},
+		},
+	}
+	content := renderSubtitleContentWithConfig(document, "stl", &dto.SubtitleExportConfig{
+		STL: &dto.SubtitleSTLExportConfig{FrameRate: 30, Language: "fr-FR", Title: "Demo"},
+	}, "")
+	if len(content) != ebuSTLGSIBlockSize+3*ebuSTLTTIBlockSize {
+		t.Fatalf("expected gsi block plus three tti blocks, got %d bytes", len(content))
+	}
+	if got := content[3:11]; got != "STL30.01" {
+		t.Fatalf("expected 30fps disk format code, got %q", got)
+	}
+	if got := content[14:16]; got != "0F" {
+		t.Fatalf("expected french language code, got %q", got)
+	}
+	if got := content[238:243]; got != "00003" {
+		t.Fatalf("expected tti block count, got %q", got)
+	}
+
+	roundTrip := parseSubtitleDocument(content, "stl")
+	if len(roundTrip.Cues) != 2 {
+		t.Fatalf("expected two stl cues, got %#v", roundTrip.Cues)
+	}
+	if got := roundTrip.Metadata[subtitleFrameRateMetadataKey]; got != 30.0 {
+		t.Fatalf("expected stl frame rate metadata, got %#v", got)
+	}
+	if cue := roundTrip.Cues[0]; cue.Start != "00:00:01.000" || cue.End != "00:00:02.500" || cue.Text != "<i>Café</i>\nçà et là" {
+		t.Fatalf("unexpected first stl cue: %#v", cue)
+	}
+	if cue := roundTrip.Cues[1]; cue.Text != strings.TrimSpace(longText) || cue.Start != "00:00:10.000" {
+		t.Fatalf("expected extension blocks to join back into one cue, got %#v", cue)
+	}
+
+	shifted := []byte(content)
+	copy(shifted[256:264], "00000000")
+	for offset := ebuSTLGSIBlockSize; offset < len(shifted); offset += ebuSTLTTIBlockSize {
+		shifted[offset+5] = 10
+		shifted[offset+9] = 10
+	}
+	copy(shifted[256:264], "10000000")
+	if cue := parseSubtitleDocument(string(shifted), "stl").Cues[0]; cue.Start != "00:00:01.000" {
+		t.Fatalf("expected start-of-programme timecode to be removed, got %#v", cue)
+	}
+}
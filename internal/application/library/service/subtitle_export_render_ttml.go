@@ -0,0 +1,41 @@
+package service
+
+import (
+	"html"
+	"strings"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+const ttmlIMSC1TextProfile = "http://www.w3.org/ns/ttml/profile/imsc1/text"
+
+// renderTTMLFromSegments writes a TTML document that stays within the IMSC1
+// text profile: clock-time media timing, a single bottom region and generic
+// font families, so broadcast and streaming validators accept it unchanged.
+func renderTTMLFromSegments(segments []subtitleCueSegment, config *dto.SubtitleExportConfig) string {
+	language := ""
+	if config != nil && config.TTML != nil {
+		language = strings.TrimSpace(config.TTML.Language)
+	}
+	if language == "" {
+		language = "en"
+	}
+	var builder strings.Builder
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	builder.WriteString(`<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" xmlns:tts="http://www.w3.org/ns/ttml#styling" xml:lang="`)
+	builder.WriteString(html.EscapeString(language))
+	builder.WriteString(`" ttp:timeBase="media" ttp:profile="`)
+	builder.WriteString(ttmlIMSC1TextProfile)
+	builder.WriteString(`">` + "\n")
+	builder.WriteString("  <head>\n    <styling>\n")
+	builder.WriteString(`      <style xml:id="s1" tts:color="white" tts:fontFamily="proportionalSansSerif" tts:fontSize="100%" tts:textAlign="center" tts:textOutline="black 5%"/>` + "\n")
+	builder.WriteString("    </styling>\n    <layout>\n")
+	builder.WriteString(`      <region xml:id="bottom" tts:origin="10% 10%" tts:extent="80% 80%" tts:displayAlign="after"/>` + "\n")
+	builder.WriteString("    </layout>\n  </head>\n")
+	builder.WriteString("  <body style=\"s1\" region=\"bottom\">\n    <div>\n")
+	for _, segment := range segments {
+		writeITTParagraph(&builder, "s1", segment.StartMS, segment.EndMS, segment.Text, false)
+	}
+	builder.WriteString("    </div>\n  </body>\n</tt>\n")
+	return builder.String()
+}
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+type subtitleExtractOperationOutput struct {
+	FileID        string   `json:"fileId,omitempty"`
+	StreamIndexes []int    `json:"streamIndexes,omitempty"`
+	OutputFormat  string   `json:"outputFormat,omitempty"`
+	OutputFileIDs []string `json:"outputFileIds,omitempty"`
+	SkippedCount  int      `json:"skippedCount,omitempty"`
+	Status        string   `json:"status"`
+}
+
+type subtitleExtractTarget struct {
+	Stream     library.MediaSubtitleStream
+	Format     string
+	OutputPath string
+}
+
+// CreateSubtitleExtractJob queues the conversion of embedded text subtitle streams
+// into subtitle files linked to the source video.
+func (service *LibraryService) CreateSubtitleExtractJob(ctx context.Context, request dto.CreateSubtitleExtractJobRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleExtractRequest(request)
+	sourceFile, err := service.resolveSubtitleExtractSourceFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if sourceFile.Media != nil && len(sourceFile.Media.SubtitleStreams) > 0 {
+		if _, err := selectSubtitleExtractStreams(sourceFile.Media.SubtitleStreams, request.StreamIndexes); err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+	}
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "subtitle_extract",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: sourceFile.Name,
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  buildSubtitleExtractOperationOutput(subtitleExtractOperationOutput{FileID: sourceFile.ID, Status: "queued"}, request),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.subtitleExtractQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleExtractOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleExtractOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateSubtitleExtractJobRequest) {
+	request = normalizeSubtitleExtractRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		service.unregisterOperationRun(operation.ID)
+		cancel()
+	}()
+
+	output := subtitleExtractOperationOutput{FileID: request.FileID, Status: "running"}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.FinishedAt = nil
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		1,
+		progressText("library.progressDetail.subtitleExtractPreparing"),
+	)
+	operation.OutputJSON = buildSubtitleExtractOperationOutput(output, request)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	sourceFile, err := service.resolveSubtitleExtractSourceFile(ctx, request.FileID)
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	streams, err := selectSubtitleExtractStreams(probe.SubtitleStreams, request.StreamIndexes)
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	tempDir, err := os.MkdirTemp("", "dreamcreator-subtitle-extract-*")
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	targets := make([]subtitleExtractTarget, 0, len(streams))
+	for _, stream := range streams {
+		format := resolveSubtitleExtractFormat(request.OutputFormat, stream.Codec)
+		targets = append(targets, subtitleExtractTarget{
+			Stream:     stream,
+			Format:     format,
+			OutputPath: filepath.Join(tempDir, fmt.Sprintf("stream_%d.%s", stream.Index, format)),
+		})
+	}
+	reporter := newFFmpegProgressReporter(service, &operation, probe.DurationMs)
+	reporter.stage = progressText("library.progress.extractingSubtitles")
+	reporter.message = progressTextTemplate("library.progressDetail.subtitleExtractRunning", map[string]string{
+		"count": strconv.Itoa(len(targets)),
+	})
+	outputText, err := service.runFFmpegCommandWithReporter(
+		runCtx,
+		reporter,
+		ffmpegExecPath,
+		buildFFmpegSubtitleExtractArgs(sourceFile.Storage.LocalPath, targets),
+		tempDir,
+	)
+	if err != nil {
+		if runCtx.Err() != nil {
+			service.failSubtitleExtractOperation(ctx, operation, request, context.Canceled)
+			return
+		}
+		message := strings.TrimSpace(outputText)
+		if message == "" {
+			message = err.Error()
+		}
+		service.failSubtitleExtractOperation(ctx, operation, request, fmt.Errorf("ffmpeg subtitle extraction failed: %s", message))
+		return
+	}
+
+	finishedAt := service.now()
+	historySource := library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID}
+	outputFiles := make([]library.LibraryFile, 0, len(targets))
+	for _, target := range targets {
+		content, err := os.ReadFile(target.OutputPath)
+		if err != nil {
+			service.failSubtitleExtractOperation(ctx, operation, request, err)
+			return
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			output.SkippedCount++
+			continue
+		}
+		subtitleFile, _, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+			LibraryID:      sourceFile.LibraryID,
+			RootFileID:     rootFileID(sourceFile),
+			SourceFileIDs:  []string{sourceFile.ID},
+			Name:           buildSubtitleExtractOutputName(sourceFile.Name, target.Stream),
+			OperationID:    operation.ID,
+			OperationKind:  "subtitle_extract",
+			Format:         target.Format,
+			OriginalSource: string(content),
+			OccurredAt:     finishedAt,
+			HistorySource:  historySource,
+		})
+		if err != nil {
+			service.failSubtitleExtractOperation(ctx, operation, request, err)
+			return
+		}
+		output.OutputFileIDs = append(output.OutputFileIDs, subtitleFile.ID)
+		outputFiles = append(outputFiles, subtitleFile)
+	}
+	if len(outputFiles) == 0 {
+		service.failSubtitleExtractOperation(ctx, operation, request, fmt.Errorf("the selected subtitle streams are empty"))
+		return
+	}
+	sourceFile.Media = mergeMediaInfo(cloneMediaInfo(sourceFile.Media), &library.MediaInfo{SubtitleStreams: probe.SubtitleStreams})
+
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Category:    "operation",
+		Action:      "subtitle_extract",
+		DisplayName: sourceFile.Name,
+		Status:      string(library.OperationStatusSucceeded),
+		Source:      historySource,
+		OccurredAt:  &finishedAt,
+		CreatedAt:   &finishedAt,
+		UpdatedAt:   &finishedAt,
+	})
+	if err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	output.Status = "completed"
+	service.completeSubtitleExtractOperation(ctx, operation, request, history, outputFiles, sourceFile, output, finishedAt)
+}
+
+func (service *LibraryService) completeSubtitleExtractOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	request dto.CreateSubtitleExtractJobRequest,
+	history library.HistoryRecord,
+	outputFiles []library.LibraryFile,
+	sourceFile library.LibraryFile,
+	output subtitleExtractOperationOutput,
+	finishedAt time.Time,
+) {
+	savedFiles := append(append([]library.LibraryFile(nil), outputFiles...), sourceFile)
+	for index := range savedFiles {
+		savedFiles[index].LatestOperationID = operation.ID
+		savedFiles[index].UpdatedAt = finishedAt
+		if err := service.files.Save(ctx, savedFiles[index]); err != nil {
+			service.failSubtitleExtractOperation(ctx, operation, request, err)
+			return
+		}
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.OutputFiles = make([]library.OperationOutputFile, 0, len(outputFiles))
+	for index, item := range outputFiles {
+		operation.OutputFiles = append(operation.OutputFiles, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+			IsPrimary: index == 0,
+			Deleted:   item.State.Deleted,
+		})
+	}
+	operation.Metrics = buildOperationMetricsForOperation(outputFiles, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		1,
+		1,
+		progressText("library.progressDetail.subtitleExtractCompleted"),
+	)
+	operation.OutputJSON = buildSubtitleExtractOperationOutput(output, request)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+
+	history.Refs = library.HistoryRecordRefs{OperationID: operation.ID, FileIDs: extractLibraryFileIDs(outputFiles)}
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "subtitle_extract"}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, operation.LibraryID, finishedAt); err != nil {
+		service.failSubtitleExtractOperation(ctx, operation, request, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	for _, item := range savedFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	service.publishWorkspaceProjectUpdate(operation.LibraryID)
+}
+
+func (service *LibraryService) failSubtitleExtractOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateSubtitleExtractJobRequest, runErr error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	currentOperation := operation
+	if item, err := service.operations.Get(ctx, operation.ID); err == nil {
+		currentOperation = item
+	}
+	if currentOperation.Status == library.OperationStatusCanceled {
+		return
+	}
+	if errors.Is(runErr, context.Canceled) {
+		currentOperation.Status = library.OperationStatusCanceled
+		currentOperation.ErrorCode = "subtitle_extract_canceled"
+		currentOperation.ErrorMessage = ""
+	} else {
+		currentOperation.Status = library.OperationStatusFailed
+		currentOperation.ErrorCode = "subtitle_extract_failed"
+		currentOperation.ErrorMessage = strings.TrimSpace(runErr.Error())
+	}
+	now := service.now()
+	currentOperation.FinishedAt = &now
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText(progressStageLocaleKey(string(currentOperation.Status))),
+		0,
+		1,
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	currentOperation.OutputJSON = buildSubtitleExtractOperationOutput(subtitleExtractOperationOutput{FileID: request.FileID, Status: string(currentOperation.Status)}, request)
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+}
+
+func (service *LibraryService) resolveSubtitleExtractSourceFile(ctx context.Context, fileID string) (library.LibraryFile, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("fileId is required")
+	}
+	item, err := service.files.Get(ctx, strings.TrimSpace(fileID))
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if item.State.Deleted || strings.TrimSpace(item.Storage.LocalPath) == "" {
+		return library.LibraryFile{}, fmt.Errorf("file %q has no local media", item.Name)
+	}
+	switch item.Kind {
+	case library.FileKindVideo, library.FileKindTranscode:
+	default:
+		return library.LibraryFile{}, fmt.Errorf("file kind %q does not contain subtitle streams", item.Kind)
+	}
+	if strings.TrimSpace(item.LibraryID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("source file is not attached to a library")
+	}
+	return item, nil
+}
+
+// selectSubtitleExtractStreams resolves the requested stream indexes, or every text
+// stream when none are requested. Bitmap streams cannot be converted to text.
+func selectSubtitleExtractStreams(streams []library.MediaSubtitleStream, indexes []int) ([]library.MediaSubtitleStream, error) {
+	if len(indexes) == 0 {
+		result := make([]library.MediaSubtitleStream, 0, len(streams))
+		for _, stream := range streams {
+			if stream.TextBased {
+				result = append(result, stream)
+			}
+		}
+		if len(result) == 0 {
+			return nil, fmt.Errorf("no text subtitle streams found")
+		}
+		return result, nil
+	}
+	byIndex := make(map[int]library.MediaSubtitleStream, len(streams))
+	for _, stream := range streams {
+		byIndex[stream.Index] = stream
+	}
+	result := make([]library.MediaSubtitleStream, 0, len(indexes))
+	for _, index := range indexes {
+		stream, ok := byIndex[index]
+		if !ok {
+			return nil, fmt.Errorf("subtitle stream %d not found", index)
+		}
+		if !stream.TextBased {
+			return nil, fmt.Errorf("subtitle stream %d (%s) is a bitmap subtitle, use subtitle OCR instead", index, stream.Codec)
+		}
+		result = append(result, stream)
+	}
+	return result, nil
+}
+
+func buildFFmpegSubtitleExtractArgs(inputPath string, targets []subtitleExtractTarget) []string {
+	args := []string{"-y", "-i", inputPath}
+	for _, target := range targets {
+		encoder, muxer := subtitleExtractCodec(target.Format)
+		args = append(args,
+			"-map", fmt.Sprintf("0:%d", target.Stream.Index),
+			"-c:s", encoder,
+			"-f", muxer,
+			target.OutputPath,
+		)
+	}
+	return args
+}
+
+func subtitleExtractCodec(format string) (string, string) {
+	switch format {
+	case "ass":
+		return "ass", "ass"
+	case "vtt":
+		return "webvtt", "webvtt"
+	default:
+		return "subrip", "srt"
+	}
+}
+
+// resolveSubtitleExtractFormat keeps ASS styling and WebVTT as-is and converts other
+// text codecs to SRT unless an output format is requested.
+func resolveSubtitleExtractFormat(requested string, codec string) string {
+	switch format := strings.ToLower(strings.TrimSpace(requested)); format {
+	case "srt", "vtt", "ass":
+		return format
+	}
+	switch strings.ToLower(strings.TrimSpace(codec)) {
+	case "ass", "ssa":
+		return "ass"
+	case "webvtt":
+		return "vtt"
+	default:
+		return "srt"
+	}
+}
+
+func buildSubtitleExtractOutputName(name string, stream library.MediaSubtitleStream) string {
+	base := strings.TrimSpace(strings.TrimSuffix(name, filepath.Ext(name)))
+	if base == "" {
+		base = "Subtitles"
+	}
+	parts := make([]string, 0, 3)
+	if stream.Language != "" {
+		parts = append(parts, strings.ToUpper(stream.Language))
+	}
+	if stream.Title != "" {
+		parts = append(parts, stream.Title)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("Track %d", stream.Index))
+	}
+	label := strings.Join(parts, " - ")
+	if stream.Forced {
+		label += ", forced"
+	}
+	return fmt.Sprintf("%s (%s)", base, label)
+}
+
+func buildSubtitleExtractOperationOutput(output subtitleExtractOperationOutput, request dto.CreateSubtitleExtractJobRequest) string {
+	output.StreamIndexes = request.StreamIndexes
+	output.OutputFormat = request.OutputFormat
+	return marshalJSON(output)
+}
+
+func normalizeSubtitleExtractRequest(request dto.CreateSubtitleExtractJobRequest) dto.CreateSubtitleExtractJobRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.OutputFormat = strings.ToLower(strings.TrimSpace(request.OutputFormat))
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if len(request.StreamIndexes) > 0 {
+		seen := make(map[int]struct{}, len(request.StreamIndexes))
+		indexes := make([]int, 0, len(request.StreamIndexes))
+		for _, index := range request.StreamIndexes {
+			if _, ok := seen[index]; ok || index < 0 {
+				continue
+			}
+			seen[index] = struct{}{}
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		request.StreamIndexes = indexes
+	}
+	return request
+}
+
+func hasTextSubtitleStreams(media *library.MediaInfo) bool {
+	if media == nil {
+		return false
+	}
+	for _, stream := range media.SubtitleStreams {
+		if stream.TextBased {
+			return true
+		}
+	}
+	return false
+}
+
+func toMediaSubtitleStreamDTOs(streams []library.MediaSubtitleStream) []dto.LibraryMediaSubtitleStreamDTO {
+	if len(streams) == 0 {
+		return nil
+	}
+	result := make([]dto.LibraryMediaSubtitleStreamDTO, 0, len(streams))
+	for _, stream := range streams {
+		result = append(result, dto.LibraryMediaSubtitleStreamDTO{
+			Index:     stream.Index,
+			Codec:     stream.Codec,
+			Language:  stream.Language,
+			Title:     stream.Title,
+			Default:   stream.Default,
+			Forced:    stream.Forced,
+			TextBased: stream.TextBased,
+		})
+	}
+	return result
+}
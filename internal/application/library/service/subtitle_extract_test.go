@@ -0,0 +1,99 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+func TestParseFFprobeMediaProbeListsSubtitleStreams(t *testing.T) {
+	t.Parallel()
+
+	output := []byte(`{
+		"streams": [
+			{"index": 0, "codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080},
+			{"index": 1, "codec_type": "audio", "codec_name": "aac", "channels": 2},
+			{"index": 2, "codec_type": "subtitle", "codec_name": "subrip", "tags": {"language": "eng", "title": "Full"}, "disposition": {"default": 1, "forced": 0}},
+			{"index": 3, "codec_type": "subtitle", "codec_name": "ass", "tags": {"language": "und"}},
+			{"index": 4, "codec_type": "subtitle", "codec_name": "hdmv_pgs_subtitle", "tags": {"language": "jpn"}, "disposition": {"forced": 1}}
+		],
+		"format": {"format_name": "matroska,webm", "duration": "60.000000"}
+	}`)
+	probe, err := parseFFprobeMediaProbe(output, "/tmp/input.mkv")
+	if err != nil {
+		t.Fatalf("parse ffprobe output: %v", err)
+	}
+	if len(probe.SubtitleStreams) != 3 {
+		t.Fatalf("expected 3 subtitle streams, got %#v", probe.SubtitleStreams)
+	}
+	first := probe.SubtitleStreams[0]
+	if first != (library.MediaSubtitleStream{Index: 2, Codec: "subrip", Language: "eng", Title: "Full", Default: true, TextBased: true}) {
+		t.Fatalf("unexpected first stream %#v", first)
+	}
+	if probe.SubtitleStreams[1].Language != "" || !probe.SubtitleStreams[1].TextBased {
+		t.Fatalf("unexpected ass stream %#v", probe.SubtitleStreams[1])
+	}
+	if pgs := probe.SubtitleStreams[2]; pgs.TextBased || !pgs.Forced {
+		t.Fatalf("unexpected pgs stream %#v", pgs)
+	}
+	if media := probe.toMediaInfo(); len(media.SubtitleStreams) != 3 || !hasTextSubtitleStreams(&media) {
+		t.Fatalf("expected subtitle streams to reach the media info, got %#v", media.SubtitleStreams)
+	}
+}
+
+func TestSelectSubtitleExtractStreams(t *testing.T) {
+	t.Parallel()
+
+	streams := []library.MediaSubtitleStream{
+		{Index: 2, Codec: "subrip", TextBased: true},
+		{Index: 3, Codec: "hdmv_pgs_subtitle"},
+		{Index: 4, Codec: "mov_text", TextBased: true},
+	}
+	selected, err := selectSubtitleExtractStreams(streams, nil)
+	if err != nil || len(selected) != 2 || selected[0].Index != 2 || selected[1].Index != 4 {
+		t.Fatalf("expected every text stream, got %#v, %v", selected, err)
+	}
+	if _, err := selectSubtitleExtractStreams(streams, []int{3}); err == nil || !strings.Contains(err.Error(), "bitmap") {
+		t.Fatalf("expected bitmap streams to be rejected, got %v", err)
+	}
+	if _, err := selectSubtitleExtractStreams(streams, []int{9}); err == nil {
+		t.Fatalf("expected unknown streams to be rejected")
+	}
+	if _, err := selectSubtitleExtractStreams(streams[1:2], nil); err == nil {
+		t.Fatalf("expected an error without text streams")
+	}
+	request := normalizeSubtitleExtractRequest(dto.CreateSubtitleExtractJobRequest{StreamIndexes: []int{4, 2, 4, -1}})
+	if len(request.StreamIndexes) != 2 || request.StreamIndexes[0] != 2 || request.StreamIndexes[1] != 4 {
+		t.Fatalf("unexpected normalized indexes %#v", request.StreamIndexes)
+	}
+}
+
+func TestBuildFFmpegSubtitleExtractArgs(t *testing.T) {
+	t.Parallel()
+
+	targets := []subtitleExtractTarget{
+		{Stream: library.MediaSubtitleStream{Index: 2, Codec: "mov_text"}, Format: resolveSubtitleExtractFormat("", "mov_text"), OutputPath: "/tmp/stream_2.srt"},
+		{Stream: library.MediaSubtitleStream{Index: 3, Codec: "ssa"}, Format: resolveSubtitleExtractFormat("", "ssa"), OutputPath: "/tmp/stream_3.ass"},
+	}
+	args := strings.Join(buildFFmpegSubtitleExtractArgs("/tmp/input.mkv", targets), " ")
+	expected := "-y -i /tmp/input.mkv -map 0:2 -c:s subrip -f srt /tmp/stream_2.srt -map 0:3 -c:s ass -f ass /tmp/stream_3.ass"
+	if args != expected {
+		t.Fatalf("unexpected ffmpeg args %q", args)
+	}
+	if format := resolveSubtitleExtractFormat("VTT", "ass"); format != "vtt" {
+		t.Fatalf("expected the requested format to win, got %q", format)
+	}
+}
+
+func TestBuildSubtitleExtractOutputName(t *testing.T) {
+	t.Parallel()
+
+	if name := buildSubtitleExtractOutputName("Movie.mkv", library.MediaSubtitleStream{Index: 2, Language: "eng", Title: "SDH"}); name != "Movie (ENG - SDH)" {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if name := buildSubtitleExtractOutputName("Movie", library.MediaSubtitleStream{Index: 5, Forced: true}); name != "Movie (Track 5, forced)" {
+		t.Fatalf("unexpected name %q", name)
+	}
+}
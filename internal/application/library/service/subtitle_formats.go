@@ -14,6 +14,7 @@ import (
 const (
 	subtitleSourceContentMetadataKey = "sourceContent"
 	subtitleSourceFormatMetadataKey  = "sourceFormat"
+	subtitleFrameRateMetadataKey     = "frameRate"
 )
 
 var (
@@ -54,8 +55,14 @@ func normalizeSubtitleFormat(value string) string {
 	switch normalizeTranscodeFormat(value) {
 	case "webvtt":
 		return "vtt"
-	case "dfxp", "ttml", "xml":
+	case "xml":
 		return "itt"
+	case "dfxp", "imsc", "imsc1":
+		return "ttml"
+	case "microdvd":
+		return "sub"
+	case "ebu-stl":
+		return "stl"
 	default:
 		return normalizeTranscodeFormat(value)
 	}
@@ -105,10 +112,20 @@ func parseSubtitleDocument(content string, format string) dto.SubtitleDocument {
 		return subtitleDocumentWithSource(content, normalized, parseVTTSubtitleCues(content), nil)
 	case "ass", "ssa":
 		return subtitleDocumentWithSource(content, normalized, parseASSSubtitleCues(content), nil)
-	case "itt":
+	case "itt", "ttml":
 		return subtitleDocumentWithSource(content, normalized, parseITTSubtitleCues(content), nil)
 	case "fcpxml":
 		return subtitleDocumentWithSource(content, normalized, parseFCPXMLSubtitleCues(content), nil)
+	case "sbv":
+		return subtitleDocumentWithSource(content, normalized, parseSBVSubtitleCues(content), nil)
+	case "sub":
+		cues, frameRate := parseMicroDVDDocument(content)
+		return subtitleDocumentWithSource(content, normalized, cues, map[string]any{subtitleFrameRateMetadataKey: frameRate})
+	case "lrc":
+		return subtitleDocumentWithSource(content, normalized, parseLRCSubtitleCues(content), nil)
+	case "stl":
+		cues, frameRate := parseEBUSTLDocument(content)
+		return subtitleDocumentWithSource(content, normalized, cues, map[string]any{subtitleFrameRateMetadataKey: float64(frameRate)})
 	default:
 		return subtitleDocumentWithSource(content, normalized, parseSRTSubtitleCues(content), nil)
 	}
@@ -527,7 +544,7 @@ func renderSubtitleContentPreservingSource(
 		return renderASSFromSource(document, originalContent, false), true
 	case "ssa":
 		return renderASSFromSource(document, originalContent, true), true
-	case "itt":
+	case "itt", "ttml":
 		if content, ok := renderITTFromSource(document, originalContent); ok {
 			return content, true
 		}
@@ -535,6 +552,10 @@ func renderSubtitleContentPreservingSource(
 		if content, ok := renderFCPXMLFromSource(document, originalContent); ok {
 			return content, true
 		}
+	case "sbv":
+		return renderSBVFromSegments(normalizeSubtitleSegments(document)), true
+	case "lrc":
+		return renderLRCFromSegments(normalizeSubtitleSegments(document), parseLRCHeaderLines(originalContent)), true
 	}
 	return "", false
 }
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+const (
+	ebuSTLGSIBlockSize     = 1024
+	ebuSTLTTIBlockSize     = 128
+	ebuSTLTextFieldSize    = 112
+	ebuSTLDefaultFrameRate = 25
+	ebuSTLLastExtension    = 0xFF
+	ebuSTLUserDataBlock    = 0xFE
+	ebuSTLLineBreak        = 0x8A
+	ebuSTLPadding          = 0x8F
+)
+
+// ebuSTLDiacritics maps the ISO 6937 non-spacing prefixes of the EBU Latin
+// character table onto Unicode combining marks.
+var ebuSTLDiacritics = map[byte]rune{
+	0xC1: '\u0300',
+	0xC2: '\u0301',
+	0xC3: '\u0302',
+	0xC4: '\u0303',
+	0xC5: '\u0304',
+	0xC6: '\u0306',
+	0xC7: '\u0307',
+	0xC8: '\u0308',
+	0xCA: '\u030A',
+	0xCB: '\u0327',
+	0xCD: '\u030B',
+	0xCE: '\u0328',
+	0xCF: '\u030C',
+}
+
+var ebuSTLCharacters = map[byte]rune{
+	0xA1: '¡', 0xA2: '¢', 0xA3: '£', 0xA4: '$', 0xA5: '¥', 0xA7: '§', 0xA8: '¤',
+	0xA9: '‘', 0xAA: '“', 0xAB: '«', 0xAC: '←', 0xAD: '↑', 0xAE: '→', 0xAF: '↓',
+	0xB0: '°', 0xB1: '±', 0xB2: '²', 0xB3: '³', 0xB4: '×', 0xB5: 'µ', 0xB6: '¶',
+	0xB7: '·', 0xB8: '÷', 0xB9: '’', 0xBA: '”', 0xBB: '»', 0xBC: '¼', 0xBD: '½',
+	0xBE: '¾', 0xBF: '¿', 0xD0: '―', 0xD1: '¹', 0xD2: '®', 0xD3: '©', 0xD4: '™',
+	0xD5: '♪', 0xE0: 'Ω', 0xE1: 'Æ', 0xE2: 'Đ', 0xE3: 'ª', 0xE4: 'Ħ', 0xE6: 'Ĳ',
+	0xE7: 'Ŀ', 0xE8: 'Ł', 0xE9: 'Ø', 0xEA: 'Œ', 0xEB: 'º', 0xEC: 'Þ', 0xED: 'Ŧ',
+	0xEE: 'Ŋ', 0xEF: 'ŉ', 0xF0: 'ĸ', 0xF1: 'æ', 0xF2: 'đ', 0xF3: 'ð', 0xF4: 'ħ',
+	0xF5: 'ı', 0xF6: 'ĳ', 0xF7: 'ŀ', 0xF8: 'ł', 0xF9: 'ø', 0xFA: 'œ', 0xFB: 'ß',
+	0xFC: 'þ', 0xFD: 'ŧ', 0xFE: 'ŋ',
+}
+
+var ebuSTLLanguageCodes = map[string]string{
+	"da": "07",
+	"de": "08",
+	"en": "09",
+	"es": "0A",
+	"fr": "0F",
+	"it": "15",
+	"nl": "1D",
+	"no": "1E",
+	"pl": "20",
+	"pt": "21",
+	"fi": "27",
+	"sv": "28",
+}
+
+var (
+	ebuSTLCharacterBytes  = reverseEBUSTLTable(ebuSTLCharacters)
+	ebuSTLDiacriticPrefix = reverseEBUSTLTable(ebuSTLDiacritics)
+)
+
+func reverseEBUSTLTable(table map[byte]rune) map[rune]byte {
+	result := make(map[rune]byte, len(table))
+	for code, value := range table {
+		if value < utf8.RuneSelf {
+			continue
+		}
+		result[value] = code
+	}
+	return result
+}
+
+// parseEBUSTLDocument decodes an EBU Tech 3264 file: a 1024-byte GSI header
+// followed by 128-byte TTI blocks. Cue times are frame-based timecodes at the
+// rate given by the disk format code; when every cue sits after the declared
+// start-of-programme timecode, that offset is removed.
+func parseEBUSTLDocument(content string) ([]dto.SubtitleCue, int) {
+	data := []byte(content)
+	if len(data) < ebuSTLGSIBlockSize {
+		return nil, ebuSTLDefaultFrameRate
+	}
+	frameRate := ebuSTLFrameRateFromDiskFormat(string(data[3:11]))
+	programmeStartMS, hasProgrammeStart := parseEBUSTLGSITimecode(string(data[256:264]), frameRate)
+	type stlCue struct {
+		startMS int64
+		endMS   int64
+		text    string
+	}
+	parsed := make([]stlCue, 0)
+	pending := make([]byte, 0, ebuSTLTextFieldSize)
+	for offset := ebuSTLGSIBlockSize; offset+ebuSTLTTIBlockSize <= len(data); offset += ebuSTLTTIBlockSize {
+		block := data[offset : offset+ebuSTLTTIBlockSize]
+		extension := block[3]
+		if block[15] != 0 || extension == ebuSTLUserDataBlock {
+			continue
+		}
+		pending = append(pending, block[16:]...)
+		if extension != ebuSTLLastExtension {
+			continue
+		}
+		text := decodeEBUSTLText(pending)
+		pending = pending[:0]
+		if text == "" {
+			continue
+		}
+		parsed = append(parsed, stlCue{
+			startMS: ebuSTLTimecodeToMilliseconds(block[5:9], frameRate),
+			endMS:   ebuSTLTimecodeToMilliseconds(block[9:13], frameRate),
+			text:    text,
+		})
+	}
+	shiftMS := int64(0)
+	if hasProgrammeStart && programmeStartMS > 0 && len(parsed) > 0 {
+		shiftMS = programmeStartMS
+		for _, cue := range parsed {
+			if cue.startMS < programmeStartMS {
+				shiftMS = 0
+				break
+			}
+		}
+	}
+	cues := make([]dto.SubtitleCue, 0, len(parsed))
+	for _, cue := range parsed {
+		cues = append(cues, dto.SubtitleCue{
+			Index: len(cues) + 1,
+			Start: formatVTTTimestamp(cue.startMS - shiftMS),
+			End:   formatVTTTimestamp(cue.endMS - shiftMS),
+			Text:  cue.text,
+		})
+	}
+	return cues, frameRate
+}
+
+func ebuSTLFrameRateFromDiskFormat(value string) int {
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(value)), "STL30") {
+		return 30
+	}
+	return ebuSTLDefaultFrameRate
+}
+
+func parseEBUSTLGSITimecode(value string, frameRate int) (int64, bool) {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) != 8 {
+		return 0, false
+	}
+	parts := make([]byte, 4)
+	for index := range parts {
+		number, err := strconv.Atoi(trimmed[index*2 : index*2+2])
+		if err != nil {
+			return 0, false
+		}
+		parts[index] = byte(number)
+	}
+	return ebuSTLTimecodeToMilliseconds(parts, frameRate), true
+}
+
+func ebuSTLTimecodeToMilliseconds(value []byte, frameRate int) int64 {
+	if len(value) < 4 {
+		return 0
+	}
+	seconds := int64(value[0])*3600 + int64(value[1])*60 + int64(value[2])
+	return seconds*1000 + subtitleFramesToMilliseconds(int64(value[3]), float64(frameRate))
+}
+
+func ebuSTLTimecodeFromMilliseconds(ms int64, frameRate int) [4]byte {
+	frames := subtitleMillisecondsToFrames(ms, float64(frameRate))
+	perSecond := int64(frameRate)
+	totalSeconds := frames / perSecond
+	hours := minInt64(totalSeconds/3600, 23)
+	return [4]byte{
+		byte(hours),
+		byte((totalSeconds % 3600) / 60),
+		byte(totalSeconds % 60),
+		byte(frames % perSecond),
+	}
+}
+
+func decodeEBUSTLText(raw []byte) string {
+	var builder strings.Builder
+	var pendingMark rune
+	for _, value := range raw {
+		var decoded rune
+		switch {
+		case value == ebuSTLPadding:
+			continue
+		case value == ebuSTLLineBreak:
+			builder.WriteByte('\n')
+			continue
+		case value == 0x80:
+			builder.WriteString("<i>")
+			continue
+		case value == 0x81:
+			builder.WriteString("</i>")
+			continue
+		case value == 0x82:
+			builder.WriteString("<u>")
+			continue
+		case value == 0x83:
+			builder.WriteString("</u>")
+			continue
+		case value < 0x20 || value == 0x7F || (value >= 0x84 && value <= 0x9F):
+			continue
+		case value < 0x80:
+			decoded = rune(value)
+		default:
+			if mark, ok := ebuSTLDiacritics[value]; ok {
+				pendingMark = mark
+				continue
+			}
+			character, ok := ebuSTLCharacters[value]
+			if !ok {
+				continue
+			}
+			decoded = character
+		}
+		if pendingMark != 0 {
+			builder.WriteString(norm.NFC.String(string([]rune{decoded, pendingMark})))
+			pendingMark = 0
+			continue
+		}
+		builder.WriteRune(decoded)
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(builder.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// encodeEBUSTLText writes cue text in the EBU Latin table. Accented letters
+// are split into a diacritic prefix and their base letter; characters the
+// table cannot express are dropped.
+func encodeEBUSTLText(text string) []byte {
+	replacer := strings.NewReplacer("<i>", "\uE080", "</i>", "\uE081", "<u>", "\uE082", "</u>", "\uE083")
+	stripped := subtitleDeliveryMarkupPattern.ReplaceAllString(replacer.Replace(normalizeSubtitleText(text)), "")
+	result := make([]byte, 0, len(stripped))
+	for index, line := range strings.Split(stripped, "\n") {
+		if index > 0 {
+			result = append(result, ebuSTLLineBreak)
+		}
+		for _, character := range strings.TrimSpace(line) {
+			switch {
+			case character >= 0xE080 && character <= 0xE083:
+				result = append(result, byte(character-0xE000))
+				continue
+			case character >= 0x20 && character < 0x7F:
+				result = append(result, byte(character))
+				continue
+			}
+			if code, ok := ebuSTLCharacterBytes[character]; ok {
+				result = append(result, code)
+				continue
+			}
+			decomposed := []rune(norm.NFD.String(string(character)))
+			if len(decomposed) != 2 || decomposed[0] >= 0x7F {
+				continue
+			}
+			if prefix, ok := ebuSTLDiacriticPrefix[decomposed[1]]; ok {
+				result = append(result, prefix, byte(decomposed[0]))
+			}
+		}
+	}
+	return result
+}
+
+func renderEBUSTLFromSegments(segments []subtitleCueSegment, config dto.SubtitleSTLExportConfig) string {
+	frameRate := config.FrameRate
+	if frameRate != 25 && frameRate != 30 {
+		frameRate = ebuSTLDefaultFrameRate
+	}
+	blocks := make([]byte, 0, len(segments)*ebuSTLTTIBlockSize)
+	blockCount := 0
+	maxRowChars := 1
+	for index, segment := range segments {
+		text := encodeEBUSTLText(segment.Text)
+		for _, row := range strings.Split(string(text), string([]byte{ebuSTLLineBreak})) {
+			maxRowChars = maxInt(maxRowChars, len(row))
+		}
+		rows := strings.Count(string(text), string([]byte{ebuSTLLineBreak})) + 1
+		timecodeIn := ebuSTLTimecodeFromMilliseconds(segment.StartMS, frameRate)
+		timecodeOut := ebuSTLTimecodeFromMilliseconds(segment.EndMS, frameRate)
+		for extension := 0; extension == 0 || len(text) > 0; extension++ {
+			chunk := text
+			if len(chunk) > ebuSTLTextFieldSize {
+				chunk = chunk[:ebuSTLTextFieldSize]
+			}
+			text = text[len(chunk):]
+			block := make([]byte, ebuSTLTTIBlockSize)
+			block[1] = byte((index + 1) & 0xFF)
+			block[2] = byte(((index + 1) >> 8) & 0xFF)
+			block[3] = byte(extension)
+			if len(text) == 0 {
+				block[3] = ebuSTLLastExtension
+			}
+			copy(block[5:9], timecodeIn[:])
+			copy(block[9:13], timecodeOut[:])
+			block[13] = byte(22 - 2*minInt(rows-1, 10))
+			block[14] = 2
+			copy(block[16:], chunk)
+			for position := 16 + len(chunk); position < ebuSTLTTIBlockSize; position++ {
+				block[position] = ebuSTLPadding
+			}
+			blocks = append(blocks, block...)
+			blockCount++
+		}
+	}
+	firstCue := "00000000"
+	if len(segments) > 0 {
+		firstCue = formatEBUSTLGSITimecode(ebuSTLTimecodeFromMilliseconds(segments[0].StartMS, frameRate))
+	}
+	today := time.Now().UTC().Format("060102")
+	gsi := []byte(strings.Repeat(" ", ebuSTLGSIBlockSize))
+	writeEBUSTLField(gsi, 0, 3, "850")
+	writeEBUSTLField(gsi, 3, 8, fmt.Sprintf("STL%d.01", frameRate))
+	writeEBUSTLField(gsi, 11, 1, "0")
+	writeEBUSTLField(gsi, 12, 2, "00")
+	writeEBUSTLField(gsi, 14, 2, resolveEBUSTLLanguageCode(config.Language))
+	writeEBUSTLField(gsi, 16, 32, config.Title)
+	writeEBUSTLField(gsi, 224, 6, today)
+	writeEBUSTLField(gsi, 230, 6, today)
+	writeEBUSTLField(gsi, 236, 2, "00")
+	writeEBUSTLField(gsi, 238, 5, fmt.Sprintf("%05d", blockCount))
+	writeEBUSTLField(gsi, 243, 5, fmt.Sprintf("%05d", len(segments)))
+	writeEBUSTLField(gsi, 248, 3, "001")
+	writeEBUSTLField(gsi, 251, 2, fmt.Sprintf("%02d", minInt(maxRowChars, 99)))
+	writeEBUSTLField(gsi, 253, 2, "23")
+	writeEBUSTLField(gsi, 255, 1, "1")
+	writeEBUSTLField(gsi, 256, 8, "00000000")
+	writeEBUSTLField(gsi, 264, 8, firstCue)
+	writeEBUSTLField(gsi, 272, 1, "1")
+	writeEBUSTLField(gsi, 273, 1, "1")
+	return string(gsi) + string(blocks)
+}
+
+func writeEBUSTLField(gsi []byte, offset int, size int, value string) {
+	field := make([]byte, 0, size)
+	for _, character := range value {
+		if len(field) == size {
+			break
+		}
+		if character >= 0x20 && character < 0x7F {
+			field = append(field, byte(character))
+		}
+	}
+	copy(gsi[offset:offset+size], field)
+}
+
+func formatEBUSTLGSITimecode(value [4]byte) string {
+	return fmt.Sprintf("%02d%02d%02d%02d", value[0], value[1], value[2], value[3])
+}
+
+func resolveEBUSTLLanguageCode(language string) string {
+	primary := strings.ToLower(strings.TrimSpace(language))
+	if index := strings.IndexAny(primary, "-_"); index >= 0 {
+		primary = primary[:index]
+	}
+	if code, ok := ebuSTLLanguageCodes[primary]; ok {
+		return code
+	}
+	return "00"
+}
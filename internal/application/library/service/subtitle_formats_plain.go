@@ -0,0 +1,342 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+const (
+	defaultMicroDVDFrameRate = 23.976
+	defaultOpenEndedCueMS    = int64(3000)
+)
+
+var (
+	microDVDLinePattern        = regexp.MustCompile(`^\{(\d+)\}\{(\d*)\}(.*)$`)
+	microDVDControlCodePattern = regexp.MustCompile(`\{[A-Za-z]:[^}]*\}`)
+	lrcTimeTagPattern          = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcMetadataTagPattern      = regexp.MustCompile(`^\[([A-Za-z]+):(.*)\]$`)
+	lrcWordTimeTagPattern      = regexp.MustCompile(`<\d+:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+type lrcEntry struct {
+	StartMS int64
+	Text    string
+}
+
+func parseSBVSubtitleCues(content string) []dto.SubtitleCue {
+	normalized := strings.TrimPrefix(normalizeSubtitleNewlines(content), "\ufeff")
+	if strings.TrimSpace(normalized) == "" {
+		return nil
+	}
+	blocks := splitSubtitleBlocks(normalized)
+	cues := make([]dto.SubtitleCue, 0, len(blocks))
+	for _, block := range blocks {
+		lines := blockLines(block)
+		if len(lines) == 0 {
+			continue
+		}
+		start, end, ok := parseSBVTimingLine(lines[0])
+		if !ok {
+			continue
+		}
+		cues = append(cues, dto.SubtitleCue{
+			Index: len(cues) + 1,
+			Start: start,
+			End:   end,
+			Text:  strings.Join(lines[1:], "\n"),
+		})
+	}
+	return cues
+}
+
+func parseSBVTimingLine(line string) (string, string, bool) {
+	parts := strings.Split(strings.TrimSpace(line), ",")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	start := strings.TrimSpace(parts[0])
+	end := strings.TrimSpace(parts[1])
+	if _, ok := parseTimestampToMilliseconds(start); !ok {
+		return "", "", false
+	}
+	if _, ok := parseTimestampToMilliseconds(end); !ok {
+		return "", "", false
+	}
+	return start, end, true
+}
+
+func renderSBVFromSegments(segments []subtitleCueSegment) string {
+	var builder strings.Builder
+	for index, segment := range segments {
+		if index > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(formatSBVTimestamp(segment.StartMS))
+		builder.WriteString(",")
+		builder.WriteString(formatSBVTimestamp(segment.EndMS))
+		builder.WriteString("\n")
+		builder.WriteString(segment.Text)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func formatSBVTimestamp(ms int64) string {
+	ms = maxInt64(0, ms)
+	totalSeconds := ms / 1000
+	milliseconds := ms % 1000
+	hours := totalSeconds / 3600
+	minutes := (totalSeconds % 3600) / 60
+	seconds := totalSeconds % 60
+	return fmt.Sprintf("%d:%02d:%02d.%03d", hours, minutes, seconds, milliseconds)
+}
+
+// parseMicroDVDDocument reads {start}{end}text frame cues. A leading {1}{1}fps
+// line declares the frame rate; otherwise the common 23.976 default applies.
+func parseMicroDVDDocument(content string) ([]dto.SubtitleCue, float64) {
+	type frameCue struct {
+		start int64
+		end   int64
+		text  string
+	}
+	frameRate := 0.0
+	frames := make([]frameCue, 0)
+	for _, raw := range strings.Split(normalizeSubtitleNewlines(content), "\n") {
+		line := strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+		match := microDVDLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		start, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		end, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			end = -1
+		}
+		if frameRate == 0 && len(frames) == 0 && start <= 1 && end <= 1 {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(match[3]), 64); err == nil && value > 0 {
+				frameRate = value
+				continue
+			}
+		}
+		frames = append(frames, frameCue{start: start, end: end, text: match[3]})
+	}
+	if frameRate <= 0 {
+		frameRate = defaultMicroDVDFrameRate
+	}
+	cues := make([]dto.SubtitleCue, 0, len(frames))
+	for index, frame := range frames {
+		startMS := subtitleFramesToMilliseconds(frame.start, frameRate)
+		endMS := subtitleFramesToMilliseconds(frame.end, frameRate)
+		if frame.end < frame.start {
+			endMS = startMS + defaultOpenEndedCueMS
+			if index+1 < len(frames) {
+				endMS = subtitleFramesToMilliseconds(frames[index+1].start, frameRate)
+			}
+		}
+		cues = append(cues, dto.SubtitleCue{
+			Index: len(cues) + 1,
+			Start: formatVTTTimestamp(startMS),
+			End:   formatVTTTimestamp(endMS),
+			Text:  microDVDTextToText(frame.text),
+		})
+	}
+	return cues, frameRate
+}
+
+func microDVDTextToText(value string) string {
+	lines := strings.Split(value, "|")
+	italicAll := false
+	for index, line := range lines {
+		italic := italicAll
+		for _, code := range microDVDControlCodePattern.FindAllString(line, -1) {
+			if !strings.EqualFold(code, "{y:i}") {
+				continue
+			}
+			italic = true
+			if code == "{Y:i}" || code == "{Y:I}" {
+				italicAll = true
+			}
+		}
+		line = strings.TrimSpace(microDVDControlCodePattern.ReplaceAllString(line, ""))
+		if italic && line != "" {
+			line = "<i>" + line + "</i>"
+		}
+		lines[index] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func textToMicroDVDText(value string) string {
+	lines := strings.Split(normalizeSubtitleText(value), "\n")
+	for index, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "<i>") && strings.HasSuffix(trimmed, "</i>") && strings.Count(trimmed, "<i>") == 1 {
+			trimmed = "{y:i}" + strings.TrimSuffix(strings.TrimPrefix(trimmed, "<i>"), "</i>")
+		}
+		lines[index] = trimmed
+	}
+	return strings.Join(lines, "|")
+}
+
+func renderMicroDVDFromSegments(segments []subtitleCueSegment, frameRate float64) string {
+	if frameRate <= 0 {
+		frameRate = defaultMicroDVDFrameRate
+	}
+	var builder strings.Builder
+	builder.WriteString("{1}{1}")
+	builder.WriteString(strconv.FormatFloat(frameRate, 'f', -1, 64))
+	builder.WriteString("\n")
+	for _, segment := range segments {
+		startFrame := subtitleMillisecondsToFrames(segment.StartMS, frameRate)
+		endFrame := maxInt64(startFrame, subtitleMillisecondsToFrames(segment.EndMS, frameRate))
+		builder.WriteString(fmt.Sprintf("{%d}{%d}%s\n", startFrame, endFrame, textToMicroDVDText(segment.Text)))
+	}
+	return builder.String()
+}
+
+func subtitleFramesToMilliseconds(frames int64, frameRate float64) int64 {
+	if frameRate <= 0 || frames <= 0 {
+		return 0
+	}
+	return int64(math.Round(float64(frames) * 1000 / frameRate))
+}
+
+func subtitleMillisecondsToFrames(ms int64, frameRate float64) int64 {
+	if frameRate <= 0 || ms <= 0 {
+		return 0
+	}
+	return int64(math.Round(float64(ms) * frameRate / 1000))
+}
+
+// parseLRCSubtitleCues turns timed lyric lines into cues. LRC only carries start
+// times, so each cue ends where the next line (or an empty terminator line)
+// begins. The [offset:] tag shifts every line earlier by its value.
+func parseLRCSubtitleCues(content string) []dto.SubtitleCue {
+	offsetMS := int64(0)
+	entries := make([]lrcEntry, 0)
+	for _, raw := range strings.Split(normalizeSubtitleNewlines(content), "\n") {
+		line := strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+		if line == "" {
+			continue
+		}
+		starts := make([]int64, 0, 1)
+		for {
+			match := lrcTimeTagPattern.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+			if value, ok := parseLRCTimeTag(match); ok {
+				starts = append(starts, value)
+			}
+			line = line[len(match[0]):]
+		}
+		if len(starts) == 0 {
+			if match := lrcMetadataTagPattern.FindStringSubmatch(line); match != nil && strings.EqualFold(match[1], "offset") {
+				if value, err := strconv.ParseInt(strings.TrimSpace(match[2]), 10, 64); err == nil {
+					offsetMS = value
+				}
+			}
+			continue
+		}
+		text := strings.TrimSpace(lrcWordTimeTagPattern.ReplaceAllString(line, ""))
+		for _, start := range starts {
+			entries = append(entries, lrcEntry{StartMS: start, Text: text})
+		}
+	}
+	sort.SliceStable(entries, func(left int, right int) bool {
+		return entries[left].StartMS < entries[right].StartMS
+	})
+	cues := make([]dto.SubtitleCue, 0, len(entries))
+	for index, entry := range entries {
+		if entry.Text == "" {
+			continue
+		}
+		endMS := entry.StartMS + defaultOpenEndedCueMS
+		if index+1 < len(entries) {
+			endMS = entries[index+1].StartMS
+		}
+		cues = append(cues, dto.SubtitleCue{
+			Index: len(cues) + 1,
+			Start: formatVTTTimestamp(maxInt64(0, entry.StartMS-offsetMS)),
+			End:   formatVTTTimestamp(maxInt64(0, endMS-offsetMS)),
+			Text:  entry.Text,
+		})
+	}
+	return cues
+}
+
+func parseLRCTimeTag(match []string) (int64, bool) {
+	minutes, errMinutes := strconv.ParseInt(match[1], 10, 64)
+	seconds, errSeconds := strconv.ParseInt(match[2], 10, 64)
+	if errMinutes != nil || errSeconds != nil || seconds >= 60 {
+		return 0, false
+	}
+	milliseconds := int64(0)
+	if fraction := match[3]; fraction != "" {
+		for len(fraction) < 3 {
+			fraction += "0"
+		}
+		value, err := strconv.ParseInt(fraction, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		milliseconds = value
+	}
+	return minutes*60*1000 + seconds*1000 + milliseconds, true
+}
+
+// parseLRCHeaderLines keeps the ID tags of a lyric file so a re-render can
+// carry them over. The offset tag is dropped because parsed times already
+// include it.
+func parseLRCHeaderLines(content string) []string {
+	result := make([]string, 0)
+	for _, raw := range strings.Split(normalizeSubtitleNewlines(content), "\n") {
+		line := strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+		if lrcTimeTagPattern.MatchString(line) {
+			continue
+		}
+		match := lrcMetadataTagPattern.FindStringSubmatch(line)
+		if match == nil || strings.EqualFold(match[1], "offset") {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+func renderLRCFromSegments(segments []subtitleCueSegment, headerLines []string) string {
+	lines := make([]string, 0, len(headerLines)+len(segments)*2)
+	lines = append(lines, headerLines...)
+	for index, segment := range segments {
+		text := strings.Join(strings.Fields(strings.ReplaceAll(segment.Text, "\n", " ")), " ")
+		lines = append(lines, formatLRCTimestamp(segment.StartMS)+text)
+		nextStartMS := int64(-1)
+		if index+1 < len(segments) {
+			nextStartMS = segments[index+1].StartMS
+		}
+		if segment.EndMS > segment.StartMS && (nextStartMS < 0 || segment.EndMS < nextStartMS) {
+			lines = append(lines, formatLRCTimestamp(segment.EndMS))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatLRCTimestamp(ms int64) string {
+	ms = maxInt64(0, ms)
+	minutes := ms / 60000
+	seconds := (ms % 60000) / 1000
+	centiseconds := (ms % 1000) / 10
+	return fmt.Sprintf("[%02d:%02d.%02d]", minutes, seconds, centiseconds)
+}
@@ -14,8 +14,11 @@ func TestDetectSubtitleFormatNormalizesAliases(t *testing.T) {
 	if got := detectSubtitleFormat("", "track.webvtt", ""); got != "vtt" {
 		t.Fatalf("expected webvtt alias to normalize to vtt, got %q", got)
 	}
-	if got := detectSubtitleFormat("", "track.dfxp", ""); got != "itt" {
-		t.Fatalf("expected dfxp alias to normalize to itt, got %q", got)
+	if got := detectSubtitleFormat("", "track.dfxp", ""); got != "ttml" {
+		t.Fatalf("expected dfxp alias to normalize to ttml, got %q", got)
+	}
+	if got := detectSubtitleFormat("", "track.xml", ""); got != "itt" {
+		t.Fatalf("expected xml extension to stay itt, got %q", got)
 	}
 	if got := detectSubtitleFormat("", "track.itt", ""); got != "itt" {
 		t.Fatalf("expected itt extension to stay itt, got %q", got)
@@ -45,6 +48,71 @@ func TestParseSubtitleDocumentVTT(t *testing.T) {
 	}
 }
 
+func TestParseSubtitleDocumentSBVMicroDVDAndLRC(t *testing.T) {
+	t.Parallel()
+
+	sbv := "0:00:01.000,0:00:02.500\nHello\nSBV\n\n0:01:02.250,0:01:04.000\nSecond\n"
+	sbvDocument := parseSubtitleDocument(sbv, "sbv")
+	if sbvDocument.Format != "sbv" || len(sbvDocument.Cues) != 2 {
+		t.Fatalf("expected two sbv cues, got %#v", sbvDocument)
+	}
+	if cue := sbvDocument.Cues[1]; cueStartMS(cue) != 62250 || cueEndMS(cue) != 64000 || cue.Text != "Second" {
+		t.Fatalf("unexpected sbv cue: %#v", cue)
+	}
+	if sbvDocument.Cues[0].Text != "Hello\nSBV" {
+		t.Fatalf("expected sbv line breaks to survive, got %#v", sbvDocument.Cues[0])
+	}
+
+	microDVD := "{1}{1}25\n{25}{75}{y:i}Hello|World\n{100}{}Open ended\n{150}{200}Last\n"
+	subDocument := parseSubtitleDocument(microDVD, "microdvd")
+	if subDocument.Format != "sub" || len(subDocument.Cues) != 3 {
+		t.Fatalf("expected three microdvd cues, got %#v", subDocument)
+	}
+	if got := subDocument.Metadata[subtitleFrameRateMetadataKey]; got != 25.0 {
+		t.Fatalf("expected microdvd frame rate header to be kept, got %#v", got)
+	}
+	if cue := subDocument.Cues[0]; cue.Start != "00:00:01.000" || cue.End != "00:00:03.000" || cue.Text != "<i>Hello</i>\nWorld" {
+		t.Fatalf("unexpected first microdvd cue: %#v", cue)
+	}
+	if cue := subDocument.Cues[1]; cue.End != "00:00:06.000" {
+		t.Fatalf("expected open-ended microdvd cue to run until the next cue, got %#v", cue)
+	}
+	if got := parseSubtitleDocument("{24}{48}One second\n", "sub").Cues[0]; got.Start != "00:00:01.001" {
+		t.Fatalf("expected microdvd without header to assume 23.976fps, got %#v", got)
+	}
+
+	lrc := strings.Join([]string{
+		"[ti:Song]",
+		"[offset:500]",
+		"[00:12.00][00:30.50]Chorus",
+		"[00:14.00]",
+		"[00:20.25]Verse <00:21.00>line",
+		"",
+	}, "\n")
+	lrcDocument := parseSubtitleDocument(lrc, "lrc")
+	if len(lrcDocument.Cues) != 3 {
+		t.Fatalf("expected three lrc cues, got %#v", lrcDocument.Cues)
+	}
+	expected := []struct {
+		start string
+		end   string
+		text  string
+	}{
+		{"00:00:11.500", "00:00:13.500", "Chorus"},
+		{"00:00:19.750", "00:00:30.000", "Verse line"},
+		{"00:00:30.000", "00:00:33.000", "Chorus"},
+	}
+	for index, want := range expected {
+		cue := lrcDocument.Cues[index]
+		if cue.Start != want.start || cue.End != want.end || cue.Text != want.text {
+			t.Fatalf("lrc cue %d: expected %+v, got %#v", index+1, want, cue)
+		}
+	}
+	if got := parseLRCHeaderLines(lrc); len(got) != 1 || got[0] != "[ti:Song]" {
+		t.Fatalf("expected lrc header tags without offset, got %#v", got)
+	}
+}
+
 func TestParseSubtitleDocumentASSIgnoresComments(t *testing.T) {
 	t.Parallel()
 
@@ -75,8 +143,8 @@ func TestParseSubtitleDocumentITTAndFCPXML(t *testing.T) {
   <body><div><p begin="00:00:01.000" end="00:00:02.000">Hello<br/>TTML</p></div></body>
 </tt>`
 	ittDocument := parseSubtitleDocument(itt, "ttml")
-	if ittDocument.Format != "itt" || len(ittDocument.Cues) != 1 {
-		t.Fatalf("expected itt cue parse, got %#v", ittDocument)
+	if ittDocument.Format != "ttml" || len(ittDocument.Cues) != 1 {
+		t.Fatalf("expected ttml cue parse, got %#v", ittDocument)
 	}
 	if ittDocument.Cues[0].Text != "Hello\nTTML" {
 		t.Fatalf("expected ttml line breaks to normalize, got %#v", ittDocument.Cues[0])
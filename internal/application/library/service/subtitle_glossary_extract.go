@@ -0,0 +1,924 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	runtimedto "dreamcreator/internal/application/gateway/runtime/dto"
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleGlossaryDefaultMinOccurrences = 3
+	subtitleGlossaryDefaultMaxCandidates  = 60
+	subtitleGlossaryMaxCandidatesLimit    = 200
+	subtitleGlossaryClassifyBatchSize     = 30
+	subtitleGlossaryMaxPhraseWords        = 4
+	subtitleGlossaryMinCJKGram            = 2
+	subtitleGlossaryMaxCJKGram            = 4
+	subtitleGlossaryExampleRuneLimit      = 120
+)
+
+type subtitleGlossaryExtractOutput struct {
+	FileIDs           []string                               `json:"fileIds,omitempty"`
+	GlossaryProfileID string                                 `json:"glossaryProfileId,omitempty"`
+	Status            string                                 `json:"status"`
+	CueCount          int                                    `json:"cueCount"`
+	CandidateCount    int                                    `json:"candidateCount"`
+	TermCount         int                                    `json:"termCount"`
+	Terms             []dto.SubtitleGlossaryTermCandidateDTO `json:"terms,omitempty"`
+	AcceptedProfileID string                                 `json:"acceptedProfileId,omitempty"`
+	AcceptedTermCount int                                    `json:"acceptedTermCount,omitempty"`
+	Usage             runtimedto.RuntimeUsage                `json:"usage,omitempty"`
+}
+
+type subtitleGlossaryCandidate struct {
+	Source      string
+	Occurrences int
+	MidSentence int
+	Example     string
+	Order       int
+	CJK         bool
+}
+
+type subtitleGlossaryClassifyResponse struct {
+	Terms []subtitleGlossaryClassifiedTerm `json:"terms"`
+}
+
+type subtitleGlossaryClassifiedTerm struct {
+	Source   string `json:"source"`
+	Keep     bool   `json:"keep"`
+	Category string `json:"category"`
+	Target   string `json:"target"`
+	Note     string `json:"note"`
+}
+
+type subtitleGlossaryToken struct {
+	Text          string
+	CJK           bool
+	SentenceStart bool
+	BreakBefore   bool
+}
+
+func (service *LibraryService) CreateSubtitleGlossaryExtractJob(ctx context.Context, request dto.SubtitleGlossaryExtractRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleGlossaryExtractRequest(request)
+	if request.TargetLanguage == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("target language is required")
+	}
+	if service.runtime == nil {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("subtitle glossary runtime unavailable")
+	}
+	files, err := service.resolveSubtitleGlossaryExtractFiles(ctx, request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if request.GlossaryProfileID != "" {
+		moduleConfig, err := service.getModuleConfig(ctx)
+		if err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+		if _, err := pickGlossaryProfilesByID(moduleConfig.LanguageAssets.GlossaryProfiles, []string{request.GlossaryProfileID}); err != nil {
+			return dto.LibraryOperationDTO{}, err
+		}
+	}
+	request.FileIDs = make([]string, 0, len(files))
+	for _, item := range files {
+		request.FileIDs = append(request.FileIDs, item.ID)
+	}
+	request.LibraryID = files[0].LibraryID
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   request.LibraryID,
+		Kind:        "subtitle_glossary_extract",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleGlossaryExtractDisplayName(files),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON: marshalJSON(subtitleGlossaryExtractOutput{
+			FileIDs:           request.FileIDs,
+			GlossaryProfileID: request.GlossaryProfileID,
+			Status:            "queued",
+		}),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.subtitleGlossaryExtractQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleGlossaryExtractOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleGlossaryExtractOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleGlossaryExtractRequest) {
+	request = normalizeSubtitleGlossaryExtractRequest(request)
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	if service.runtime == nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, errors.New("subtitle glossary runtime unavailable"), runtimedto.RuntimeUsage{})
+		return
+	}
+	files, err := service.resolveSubtitleGlossaryExtractFiles(ctx, request)
+	if err != nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, err, runtimedto.RuntimeUsage{})
+		return
+	}
+	cues := make([]dto.SubtitleCue, 0)
+	for _, item := range files {
+		_, document, err := service.resolveSubtitleFileAndDocument(ctx, item.ID, "", "")
+		if err != nil {
+			service.failSubtitleGlossaryExtractOperation(ctx, operation, err, runtimedto.RuntimeUsage{})
+			return
+		}
+		content := strings.TrimSpace(document.WorkingContent)
+		if content == "" {
+			content = strings.TrimSpace(document.OriginalContent)
+		}
+		if content == "" {
+			continue
+		}
+		parsed := parseSubtitleDocument(content, detectSubtitleFormat(document.Format, item.Storage.LocalPath, document.Format))
+		cues = append(cues, parsed.Cues...)
+	}
+	if len(cues) == 0 {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, errors.New("subtitle documents have no cues"), runtimedto.RuntimeUsage{})
+		return
+	}
+	moduleConfig, err := service.getModuleConfig(ctx)
+	if err != nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, err, runtimedto.RuntimeUsage{})
+		return
+	}
+	existingTerms := make(map[string]struct{})
+	if request.GlossaryProfileID != "" {
+		profiles, err := pickGlossaryProfilesByID(moduleConfig.LanguageAssets.GlossaryProfiles, []string{request.GlossaryProfileID})
+		if err != nil {
+			service.failSubtitleGlossaryExtractOperation(ctx, operation, err, runtimedto.RuntimeUsage{})
+			return
+		}
+		for _, term := range profiles[0].Terms {
+			existingTerms[strings.ToLower(strings.TrimSpace(term.Source))] = struct{}{}
+		}
+	}
+	runtimeConfig := resolveSubtitleTaskRuntimeSettings(moduleConfig.TaskRuntime.Translate)
+
+	candidates := extractSubtitleGlossaryCandidates(cues, request.MinOccurrences, request.MaxCandidates, existingTerms)
+	batchCount := (len(candidates) + subtitleGlossaryClassifyBatchSize - 1) / subtitleGlossaryClassifyBatchSize
+	output := subtitleGlossaryExtractOutput{
+		FileIDs:           request.FileIDs,
+		GlossaryProfileID: request.GlossaryProfileID,
+		Status:            "running",
+		CueCount:          len(cues),
+		CandidateCount:    len(candidates),
+	}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.extractingTerms"),
+		0,
+		max(batchCount, 1),
+		progressText("library.progressDetail.extractingGlossaryTerms"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	var totalUsage runtimedto.RuntimeUsage
+	terms := make([]dto.SubtitleGlossaryTermCandidateDTO, 0)
+	for batchIndex := 0; batchIndex < batchCount; batchIndex++ {
+		if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+			_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+			return
+		}
+		start := batchIndex * subtitleGlossaryClassifyBatchSize
+		end := min(start+subtitleGlossaryClassifyBatchSize, len(candidates))
+		batchCtx, batchCancel := context.WithTimeout(runCtx, subtitleTranslateChunkTimeout)
+		batchTerms, usage, err := service.classifySubtitleGlossaryCandidates(batchCtx, request, candidates[start:end], runtimeConfig)
+		batchCancel()
+		totalUsage = addRuntimeUsage(totalUsage, usage)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || runCtx.Err() == context.Canceled || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+				_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+				return
+			}
+			service.failSubtitleGlossaryExtractOperation(ctx, operation, fmt.Errorf("batch %d/%d failed: %w", batchIndex+1, batchCount, err), totalUsage)
+			return
+		}
+		terms = append(terms, batchTerms...)
+		output.Terms = terms
+		output.TermCount = len(terms)
+		output.Usage = totalUsage
+		progressTime := service.now()
+		operation.Progress = buildOperationProgress(
+			progressTime,
+			progressText("library.progress.extractingTerms"),
+			batchIndex+1,
+			batchCount,
+			progressTextTemplate("library.progressDetail.classifiedGlossaryBatch", map[string]string{
+				"current": fmt.Sprintf("%d", batchIndex+1),
+				"total":   fmt.Sprintf("%d", batchCount),
+			}),
+		)
+		operation.OutputJSON = marshalJSON(output)
+		if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+			return
+		}
+	}
+
+	finishedAt := service.now()
+	output.Status = "completed"
+	output.Terms = terms
+	output.TermCount = len(terms)
+	output.Usage = totalUsage
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.Metrics = buildOperationMetricsForOperation(nil, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		max(batchCount, 1),
+		max(batchCount, 1),
+		progressText("library.progressDetail.subtitleGlossaryExtractCompleted"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, err, totalUsage)
+		return
+	}
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   operation.LibraryID,
+		Category:    "operation",
+		Action:      operation.Kind,
+		DisplayName: operation.DisplayName,
+		Status:      string(operation.Status),
+		Source: library.HistoryRecordSource{
+			Kind:  resolveHistorySourceKind(request.Source),
+			RunID: request.RunID,
+		},
+		Refs:          library.HistoryRecordRefs{OperationID: operation.ID},
+		Metrics:       operation.Metrics,
+		OperationMeta: &library.OperationRecordMeta{Kind: operation.Kind},
+		OccurredAt:    &finishedAt,
+		CreatedAt:     &finishedAt,
+		UpdatedAt:     &finishedAt,
+	})
+	if err != nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, err, totalUsage)
+		return
+	}
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleGlossaryExtractOperation(ctx, operation, err, totalUsage)
+		return
+	}
+	_ = service.touchLibrary(ctx, operation.LibraryID, finishedAt)
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+}
+
+// AcceptGlossaryTermCandidates writes reviewed terms into a glossary profile. Terms
+// default to every candidate of the extraction operation; a missing profile is created.
+func (service *LibraryService) AcceptGlossaryTermCandidates(ctx context.Context, request dto.AcceptGlossaryTermCandidatesRequest) (dto.LibraryModuleConfigDTO, error) {
+	request.OperationID = strings.TrimSpace(request.OperationID)
+	request.GlossaryProfileID = strings.TrimSpace(request.GlossaryProfileID)
+	request.ProfileName = strings.TrimSpace(request.ProfileName)
+	terms := append([]dto.LibraryGlossaryTermDTO(nil), request.Terms...)
+	extractRequest := dto.SubtitleGlossaryExtractRequest{}
+	var (
+		operation    library.LibraryOperation
+		output       subtitleGlossaryExtractOutput
+		hasOperation bool
+	)
+	if request.OperationID != "" {
+		item, err := service.operations.Get(ctx, request.OperationID)
+		if err != nil {
+			return dto.LibraryModuleConfigDTO{}, err
+		}
+		if item.Kind != "subtitle_glossary_extract" || item.Status != library.OperationStatusSucceeded {
+			return dto.LibraryModuleConfigDTO{}, fmt.Errorf("operation %q is not a completed glossary extraction", item.ID)
+		}
+		operation = item
+		hasOperation = true
+		extractRequest = extractSubtitleGlossaryExtractRequest(item.InputJSON)
+		if err := json.Unmarshal([]byte(item.OutputJSON), &output); err != nil {
+			return dto.LibraryModuleConfigDTO{}, err
+		}
+		if len(terms) == 0 {
+			for _, term := range output.Terms {
+				terms = append(terms, dto.LibraryGlossaryTermDTO{Source: term.Source, Target: term.Target, Note: term.Note})
+			}
+		}
+	}
+	config, err := service.GetModuleConfig(ctx)
+	if err != nil {
+		return dto.LibraryModuleConfigDTO{}, err
+	}
+	profiles, profileID, accepted := mergeGlossaryProfileTerms(
+		config.LanguageAssets.GlossaryProfiles,
+		dto.LibraryGlossaryProfileDTO{
+			ID:             firstNonEmpty(request.GlossaryProfileID, extractRequest.GlossaryProfileID),
+			Name:           request.ProfileName,
+			SourceLanguage: extractRequest.SourceLanguage,
+			TargetLanguage: extractRequest.TargetLanguage,
+		},
+		terms,
+	)
+	if accepted == 0 {
+		return dto.LibraryModuleConfigDTO{}, fmt.Errorf("no glossary terms to accept")
+	}
+	config.LanguageAssets.GlossaryProfiles = profiles
+	updated, err := service.UpdateModuleConfig(ctx, dto.UpdateLibraryModuleConfigRequest{Config: config})
+	if err != nil {
+		return dto.LibraryModuleConfigDTO{}, err
+	}
+	if hasOperation {
+		output.AcceptedProfileID = profileID
+		output.AcceptedTermCount = accepted
+		operation.OutputJSON = marshalJSON(output)
+		if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+			return dto.LibraryModuleConfigDTO{}, err
+		}
+	}
+	return updated, nil
+}
+
+// mergeGlossaryProfileTerms upserts terms by source text into the matching profile,
+// appending a new profile when none matches. It returns the profile ID it wrote to.
+func mergeGlossaryProfileTerms(
+	profiles []dto.LibraryGlossaryProfileDTO,
+	target dto.LibraryGlossaryProfileDTO,
+	terms []dto.LibraryGlossaryTermDTO,
+) ([]dto.LibraryGlossaryProfileDTO, string, int) {
+	result := append([]dto.LibraryGlossaryProfileDTO(nil), profiles...)
+	profileIndex := -1
+	if target.ID != "" {
+		for index, profile := range result {
+			if profile.ID == target.ID {
+				profileIndex = index
+				break
+			}
+		}
+	}
+	if profileIndex < 0 {
+		if target.ID == "" {
+			target.ID = fmt.Sprintf("glossary-%s", uuid.NewString()[:8])
+		}
+		if target.Name == "" {
+			target.Name = "Extracted terms"
+		}
+		target.Category = "all"
+		result = append(result, target)
+		profileIndex = len(result) - 1
+	}
+	profile := result[profileIndex]
+	merged := append([]dto.LibraryGlossaryTermDTO(nil), profile.Terms...)
+	positionBySource := make(map[string]int, len(merged))
+	for index, term := range merged {
+		positionBySource[strings.ToLower(strings.TrimSpace(term.Source))] = index
+	}
+	accepted := 0
+	for _, term := range terms {
+		source := strings.TrimSpace(term.Source)
+		targetText := strings.TrimSpace(term.Target)
+		if source == "" || targetText == "" {
+			continue
+		}
+		next := dto.LibraryGlossaryTermDTO{Source: source, Target: targetText, Note: strings.TrimSpace(term.Note)}
+		key := strings.ToLower(source)
+		if index, ok := positionBySource[key]; ok {
+			merged[index] = next
+		} else {
+			positionBySource[key] = len(merged)
+			merged = append(merged, next)
+		}
+		accepted++
+	}
+	profile.Terms = merged
+	result[profileIndex] = profile
+	return result, profile.ID, accepted
+}
+
+func (service *LibraryService) classifySubtitleGlossaryCandidates(
+	ctx context.Context,
+	request dto.SubtitleGlossaryExtractRequest,
+	candidates []subtitleGlossaryCandidate,
+	runtimeConfig subtitleTaskRuntimeSettings,
+) ([]dto.SubtitleGlossaryTermCandidateDTO, runtimedto.RuntimeUsage, error) {
+	var (
+		lastErr      error
+		totalUsage   runtimedto.RuntimeUsage
+		repairPrompt string
+	)
+	for attempt := 0; attempt < subtitleTranslateMaxRetries; attempt++ {
+		systemPrompt, userPrompt := buildSubtitleGlossaryClassifyPrompts(request, candidates, repairPrompt)
+		maxTokens := min(max(runtimeConfig.MaxTokensFloor, len(candidates)*96+512)+attempt*runtimeConfig.RetryTokenStep, runtimeConfig.MaxTokensCeiling)
+		result, err := service.runtime.RunOneShot(ctx, runtimedto.RuntimeRunRequest{
+			AssistantID: request.AssistantID,
+			RunKind:     "one-shot",
+			PromptMode:  "none",
+			Input: runtimedto.RuntimeInput{
+				Messages: []runtimedto.Message{{
+					Role:    "user",
+					Content: userPrompt,
+				}},
+			},
+			Thinking: runtimedto.ThinkingConfig{Mode: runtimeConfig.ThinkingMode},
+			Tools: runtimedto.ToolExecutionConfig{
+				Mode: "disabled",
+			},
+			Metadata: map[string]any{
+				"channel":           "library",
+				"useQueue":          true,
+				"runLane":           "subagent",
+				"oneShotKind":       "subtitle_glossary_extract",
+				"temperature":       0.2,
+				"maxTokens":         maxTokens,
+				"extraSystemPrompt": systemPrompt,
+				"structuredOutput":  buildSubtitleGlossaryStructuredOutputMetadata(runtimeConfig),
+			},
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) || ctx.Err() == context.Canceled {
+				return nil, totalUsage, context.Canceled
+			}
+			lastErr = err
+			continue
+		}
+		totalUsage = addRuntimeUsage(totalUsage, result.Usage)
+		terms, parseErr := parseSubtitleGlossaryClassifiedTerms(result.AssistantMessage.Content, candidates)
+		if parseErr != nil && isTokenLimitFinishReason(result.FinishReason) {
+			parseErr = fmt.Errorf("model output truncated at max token limit: %w", parseErr)
+		}
+		if parseErr == nil {
+			return terms, totalUsage, nil
+		}
+		lastErr = parseErr
+		repairPrompt = fmt.Sprintf("The previous output was invalid. Fix it and return valid JSON only. Validation error: %s\nPrevious output:\n%s", parseErr.Error(), result.AssistantMessage.Content)
+	}
+	if lastErr == nil {
+		lastErr = errors.New("glossary term classification failed")
+	}
+	return nil, totalUsage, lastErr
+}
+
+func buildSubtitleGlossaryClassifyPrompts(
+	request dto.SubtitleGlossaryExtractRequest,
+	candidates []subtitleGlossaryCandidate,
+	repairPrompt string,
+) (string, string) {
+	var systemBuilder strings.Builder
+	systemBuilder.WriteString("You build a glossary that keeps subtitle translations consistent across episodes.\n")
+	systemBuilder.WriteString("Return only valid JSON with this shape: {\"terms\":[{\"source\":\"...\",\"keep\":true,\"category\":\"person\",\"target\":\"...\",\"note\":\"...\"}]}\n")
+	systemBuilder.WriteString("Rules:\n")
+	systemBuilder.WriteString("- Return one item per candidate, copying the candidate source text exactly.\n")
+	systemBuilder.WriteString("- Keep proper nouns and recurring domain terms: people, places, organizations, titles, products, invented words, and catchphrases.\n")
+	systemBuilder.WriteString("- Reject common words, greetings, interjections, and sentence fragments by setting keep to false.\n")
+	systemBuilder.WriteString("- Category is one of person, place, organization, title, term, or other.\n")
+	systemBuilder.WriteString("- For kept terms, target is the translation that should be used every time; use an established official translation when one exists.\n")
+	systemBuilder.WriteString("- Note is optional and should be a few words of context for a human reviewer.\n")
+	systemBuilder.WriteString("- Do not output markdown, prose, or explanations.\n")
+	if repairPrompt != "" {
+		systemBuilder.WriteString("- Repair the previous invalid output and still obey every rule above.\n")
+	}
+
+	payload := make([]map[string]any, 0, len(candidates))
+	for _, candidate := range candidates {
+		payload = append(payload, map[string]any{
+			"source":      candidate.Source,
+			"occurrences": candidate.Occurrences,
+			"example":     candidate.Example,
+		})
+	}
+	var userBuilder strings.Builder
+	if request.SourceLanguage != "" {
+		userBuilder.WriteString(fmt.Sprintf("Source language: %s\n", request.SourceLanguage))
+	}
+	userBuilder.WriteString(fmt.Sprintf("Target language: %s\n", request.TargetLanguage))
+	userBuilder.WriteString("Classify the following candidate terms extracted from subtitles.\n")
+	if repairPrompt != "" {
+		userBuilder.WriteString(repairPrompt)
+		userBuilder.WriteString("\n")
+	}
+	userBuilder.WriteString("Candidates JSON:\n")
+	userBuilder.WriteString(marshalJSON(map[string]any{"candidates": payload}))
+	return strings.TrimSpace(systemBuilder.String()), strings.TrimSpace(userBuilder.String())
+}
+
+func buildSubtitleGlossaryStructuredOutputMetadata(runtimeConfig subtitleTaskRuntimeSettings) map[string]any {
+	return map[string]any{
+		"mode":   strings.TrimSpace(runtimeConfig.StructuredOutputMode),
+		"name":   "library_subtitle_glossary_terms",
+		"strict": true,
+		"schema": map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"required":             []string{"terms"},
+			"properties": map[string]any{
+				"terms": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":                 "object",
+						"additionalProperties": false,
+						"required":             []string{"source", "keep", "category", "target", "note"},
+						"properties": map[string]any{
+							"source":   map[string]any{"type": "string"},
+							"keep":     map[string]any{"type": "boolean"},
+							"category": map[string]any{"type": "string"},
+							"target":   map[string]any{"type": "string"},
+							"note":     map[string]any{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseSubtitleGlossaryClassifiedTerms keeps only terms the model accepted and maps
+// them back onto the candidate list so occurrence counts and examples survive.
+func parseSubtitleGlossaryClassifiedTerms(raw string, candidates []subtitleGlossaryCandidate) ([]dto.SubtitleGlossaryTermCandidateDTO, error) {
+	content := strings.TrimSpace(raw)
+	if content == "" {
+		return nil, errors.New("model returned empty output")
+	}
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+	if start := strings.Index(content, "{"); start >= 0 {
+		if end := strings.LastIndex(content, "}"); end > start {
+			content = content[start : end+1]
+		}
+	}
+	response := subtitleGlossaryClassifyResponse{}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return nil, fmt.Errorf("invalid json output: %w", err)
+	}
+	if len(response.Terms) == 0 && len(candidates) > 0 {
+		return nil, errors.New("model returned no terms")
+	}
+	candidateByKey := make(map[string]subtitleGlossaryCandidate, len(candidates))
+	for _, candidate := range candidates {
+		candidateByKey[strings.ToLower(candidate.Source)] = candidate
+	}
+	result := make([]dto.SubtitleGlossaryTermCandidateDTO, 0, len(response.Terms))
+	seen := make(map[string]struct{}, len(response.Terms))
+	for _, term := range response.Terms {
+		key := strings.ToLower(strings.TrimSpace(term.Source))
+		candidate, ok := candidateByKey[key]
+		if !ok || !term.Keep || strings.TrimSpace(term.Target) == "" {
+			continue
+		}
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, dto.SubtitleGlossaryTermCandidateDTO{
+			Source:      candidate.Source,
+			Target:      strings.TrimSpace(term.Target),
+			Category:    normalizeSubtitleGlossaryCategory(term.Category),
+			Note:        strings.TrimSpace(term.Note),
+			Occurrences: candidate.Occurrences,
+			Example:     candidate.Example,
+		})
+	}
+	return result, nil
+}
+
+func normalizeSubtitleGlossaryCategory(value string) string {
+	switch trimmed := strings.ToLower(strings.TrimSpace(value)); trimmed {
+	case "person", "place", "organization", "title", "term":
+		return trimmed
+	default:
+		return "other"
+	}
+}
+
+// extractSubtitleGlossaryCandidates ranks recurring terms by frequency. Cased scripts
+// contribute runs of capitalized words that also appear mid-sentence, so ordinary
+// sentence openers drop out; Katakana runs count as whole words, and Han/Hangul text
+// contributes n-grams that are not fully explained by a longer recurring n-gram.
+func extractSubtitleGlossaryCandidates(
+	cues []dto.SubtitleCue,
+	minOccurrences int,
+	maxCandidates int,
+	existing map[string]struct{},
+) []subtitleGlossaryCandidate {
+	if minOccurrences <= 0 {
+		minOccurrences = subtitleGlossaryDefaultMinOccurrences
+	}
+	if maxCandidates <= 0 {
+		maxCandidates = subtitleGlossaryDefaultMaxCandidates
+	}
+	byKey := make(map[string]*subtitleGlossaryCandidate)
+	order := 0
+	record := func(source string, midSentence bool, cjk bool, example string) {
+		key := strings.ToLower(source)
+		if _, skip := existing[key]; skip {
+			return
+		}
+		item, ok := byKey[key]
+		if !ok {
+			order++
+			item = &subtitleGlossaryCandidate{Source: source, Example: example, Order: order, CJK: cjk}
+			byKey[key] = item
+		}
+		item.Occurrences++
+		if midSentence {
+			item.MidSentence++
+		}
+	}
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(subtitleDeliveryMarkupPattern.ReplaceAllString(cue.Text, " ")), " ")
+		if text == "" {
+			continue
+		}
+		example := truncateSubtitleGlossaryExample(text)
+		tokens := tokenizeSubtitleGlossaryText(text)
+		for index := 0; index < len(tokens); index++ {
+			token := tokens[index]
+			if token.CJK {
+				for _, gram := range subtitleGlossaryCJKTerms(token.Text) {
+					record(gram, true, true, example)
+				}
+				continue
+			}
+			if !isSubtitleGlossaryCapitalized(token.Text) {
+				continue
+			}
+			run := []subtitleGlossaryToken{token}
+			for next := index + 1; next < len(tokens) && len(run) < subtitleGlossaryMaxPhraseWords; next++ {
+				if tokens[next].CJK || tokens[next].BreakBefore || tokens[next].SentenceStart || !isSubtitleGlossaryCapitalized(tokens[next].Text) {
+					break
+				}
+				run = append(run, tokens[next])
+			}
+			index += len(run) - 1
+			record(joinSubtitleGlossaryTokens(run), !run[0].SentenceStart, false, example)
+			if run[0].SentenceStart && len(run) > 1 {
+				record(joinSubtitleGlossaryTokens(run[1:]), true, false, example)
+			}
+		}
+	}
+
+	items := make([]subtitleGlossaryCandidate, 0, len(byKey))
+	for _, item := range byKey {
+		if item.Occurrences < minOccurrences || item.MidSentence == 0 {
+			continue
+		}
+		if !item.CJK && len([]rune(item.Source)) < 2 {
+			continue
+		}
+		items = append(items, *item)
+	}
+	items = pruneSubsumedSubtitleGlossaryCandidates(items)
+	sort.SliceStable(items, func(left int, right int) bool {
+		leftScore := items[left].Occurrences * len([]rune(items[left].Source))
+		rightScore := items[right].Occurrences * len([]rune(items[right].Source))
+		if leftScore != rightScore {
+			return leftScore > rightScore
+		}
+		return items[left].Order < items[right].Order
+	})
+	if len(items) > maxCandidates {
+		items = items[:maxCandidates]
+	}
+	return items
+}
+
+// pruneSubsumedSubtitleGlossaryCandidates drops a candidate when a longer candidate
+// containing it occurs almost as often, e.g. "Rex" inside "Captain Rex".
+func pruneSubsumedSubtitleGlossaryCandidates(items []subtitleGlossaryCandidate) []subtitleGlossaryCandidate {
+	result := make([]subtitleGlossaryCandidate, 0, len(items))
+	for _, item := range items {
+		key := strings.ToLower(item.Source)
+		subsumed := false
+		for _, other := range items {
+			if len(other.Source) <= len(item.Source) || other.CJK != item.CJK {
+				continue
+			}
+			if strings.Contains(strings.ToLower(other.Source), key) && other.Occurrences*5 >= item.Occurrences*4 {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func tokenizeSubtitleGlossaryText(text string) []subtitleGlossaryToken {
+	tokens := make([]subtitleGlossaryToken, 0)
+	var (
+		builder       strings.Builder
+		currentCJK    bool
+		sentenceStart = true
+		breakBefore   bool
+	)
+	flush := func() {
+		if builder.Len() == 0 {
+			return
+		}
+		value := strings.TrimRight(builder.String(), "'’-")
+		builder.Reset()
+		if value == "" {
+			return
+		}
+		tokens = append(tokens, subtitleGlossaryToken{Text: value, CJK: currentCJK, SentenceStart: sentenceStart, BreakBefore: breakBefore})
+		sentenceStart = false
+		breakBefore = false
+	}
+	for _, char := range text {
+		cjk := isSubtitleGlossaryCJKRune(char)
+		switch {
+		case cjk || unicode.IsLetter(char) || unicode.IsDigit(char):
+			if builder.Len() > 0 && cjk != currentCJK {
+				flush()
+			}
+			currentCJK = cjk
+			builder.WriteRune(char)
+		case (char == '\'' || char == '’' || char == '-') && builder.Len() > 0 && !currentCJK:
+			builder.WriteRune(char)
+		default:
+			flush()
+			switch {
+			case strings.ContainsRune(".!?…。！？", char):
+				sentenceStart = true
+			case !unicode.IsSpace(char):
+				breakBefore = true
+			}
+		}
+	}
+	flush()
+	return tokens
+}
+
+func subtitleGlossaryCJKTerms(run string) []string {
+	runes := []rune(run)
+	if len(runes) < subtitleGlossaryMinCJKGram {
+		return nil
+	}
+	if isSubtitleGlossaryKanaWord(runes) || unicode.Is(unicode.Hangul, runes[0]) {
+		return []string{run}
+	}
+	result := make([]string, 0)
+	seen := make(map[string]struct{})
+	for size := subtitleGlossaryMinCJKGram; size <= subtitleGlossaryMaxCJKGram && size <= len(runes); size++ {
+		for start := 0; start+size <= len(runes); start++ {
+			gram := string(runes[start : start+size])
+			if _, ok := seen[gram]; ok {
+				continue
+			}
+			seen[gram] = struct{}{}
+			result = append(result, gram)
+		}
+	}
+	return result
+}
+
+func isSubtitleGlossaryKanaWord(runes []rune) bool {
+	for _, char := range runes {
+		if !unicode.Is(unicode.Katakana, char) && char != 'ー' && char != '・' {
+			return false
+		}
+	}
+	return true
+}
+
+func isSubtitleGlossaryCJKRune(char rune) bool {
+	return unicode.Is(unicode.Han, char) ||
+		unicode.Is(unicode.Katakana, char) ||
+		unicode.Is(unicode.Hangul, char) ||
+		char == 'ー'
+}
+
+func isSubtitleGlossaryCapitalized(value string) bool {
+	for _, char := range value {
+		return unicode.IsUpper(char) || unicode.IsTitle(char)
+	}
+	return false
+}
+
+func joinSubtitleGlossaryTokens(tokens []subtitleGlossaryToken) string {
+	values := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		values = append(values, token.Text)
+	}
+	return strings.Join(values, " ")
+}
+
+func truncateSubtitleGlossaryExample(text string) string {
+	runes := []rune(text)
+	if len(runes) <= subtitleGlossaryExampleRuneLimit {
+		return text
+	}
+	return string(runes[:subtitleGlossaryExampleRuneLimit]) + "…"
+}
+
+// resolveSubtitleGlossaryExtractFiles returns the explicit subtitle files, or every
+// live subtitle in the library when no file is named.
+func (service *LibraryService) resolveSubtitleGlossaryExtractFiles(ctx context.Context, request dto.SubtitleGlossaryExtractRequest) ([]library.LibraryFile, error) {
+	return service.resolveLibrarySubtitleFiles(ctx, request.FileIDs, request.LibraryID)
+}
+
+func (service *LibraryService) failSubtitleGlossaryExtractOperation(ctx context.Context, operation library.LibraryOperation, err error, usage runtimedto.RuntimeUsage) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	request := extractSubtitleGlossaryExtractRequest(operation.InputJSON)
+	now := service.now()
+	operation.Status = library.OperationStatusFailed
+	operation.ErrorCode = "subtitle_glossary_extract_failed"
+	operation.ErrorMessage = strings.TrimSpace(err.Error())
+	operation.FinishedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		0,
+		1,
+		progressText("library.progressDetail.subtitleGlossaryExtractFailed"),
+	)
+	operation.OutputJSON = marshalJSON(subtitleGlossaryExtractOutput{
+		FileIDs:           request.FileIDs,
+		GlossaryProfileID: request.GlossaryProfileID,
+		Status:            "failed",
+		Usage:             usage,
+	})
+	if saveErr := service.operations.Save(ctx, operation); saveErr != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+func buildSubtitleGlossaryExtractDisplayName(files []library.LibraryFile) string {
+	if len(files) == 1 {
+		base := strings.TrimSpace(files[0].Name)
+		if base == "" {
+			base = "Subtitle"
+		}
+		return fmt.Sprintf("%s (Glossary Terms)", base)
+	}
+	return fmt.Sprintf("%d subtitles (Glossary Terms)", len(files))
+}
+
+func normalizeSubtitleGlossaryExtractRequest(request dto.SubtitleGlossaryExtractRequest) dto.SubtitleGlossaryExtractRequest {
+	request.FileIDs = uniqueTrimmedStrings(request.FileIDs)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.AssistantID = strings.TrimSpace(request.AssistantID)
+	request.SourceLanguage = strings.TrimSpace(request.SourceLanguage)
+	request.TargetLanguage = strings.TrimSpace(request.TargetLanguage)
+	request.GlossaryProfileID = strings.TrimSpace(request.GlossaryProfileID)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.MinOccurrences <= 0 {
+		request.MinOccurrences = subtitleGlossaryDefaultMinOccurrences
+	}
+	if request.MaxCandidates <= 0 {
+		request.MaxCandidates = subtitleGlossaryDefaultMaxCandidates
+	}
+	if request.MaxCandidates > subtitleGlossaryMaxCandidatesLimit {
+		request.MaxCandidates = subtitleGlossaryMaxCandidatesLimit
+	}
+	return request
+}
+
+func extractSubtitleGlossaryExtractRequest(inputJSON string) dto.SubtitleGlossaryExtractRequest {
+	request := dto.SubtitleGlossaryExtractRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleGlossaryExtractRequest{}
+	}
+	return normalizeSubtitleGlossaryExtractRequest(request)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestExtractSubtitleGlossaryCandidatesPrefersMidSentenceNames(t *testing.T) {
+	t.Parallel()
+
+	cues := []dto.SubtitleCue{
+		{Index: 1, Text: "Where is Captain Rex?"},
+		{Index: 2, Text: "We follow Captain Rex."},
+		{Index: 3, Text: "Captain Rex, wait!"},
+		{Index: 4, Text: "The ship leaves for Kamino tonight."},
+		{Index: 5, Text: "The clones of Kamino are ready."},
+		{Index: 6, Text: "Tell Captain Rex about Kamino."},
+		{Index: 7, Text: "The end. The <i>end</i>."},
+		{Index: 8, Text: "Obi-Wan knows. Ask Obi-Wan."},
+		{Index: 9, Text: "Only Obi-Wan can help."},
+	}
+	candidates := extractSubtitleGlossaryCandidates(cues, 3, 10, map[string]struct{}{"obi-wan": {}})
+	sources := make(map[string]int, len(candidates))
+	for _, item := range candidates {
+		sources[item.Source] = item.Occurrences
+	}
+	if sources["Captain Rex"] != 4 {
+		t.Fatalf("expected Captain Rex with 4 occurrences, got %#v", candidates)
+	}
+	if sources["Kamino"] != 3 {
+		t.Fatalf("expected Kamino with 3 occurrences, got %#v", candidates)
+	}
+	if _, ok := sources["The"]; ok {
+		t.Fatalf("did not expect sentence openers as candidates, got %#v", candidates)
+	}
+	if _, ok := sources["Rex"]; ok {
+		t.Fatalf("expected Rex to be subsumed by Captain Rex, got %#v", candidates)
+	}
+	if _, ok := sources["Obi-Wan"]; ok {
+		t.Fatalf("expected existing glossary terms to be skipped, got %#v", candidates)
+	}
+	if candidates[0].Source != "Captain Rex" {
+		t.Fatalf("expected longer frequent phrase to rank first, got %#v", candidates)
+	}
+}
+
+func TestExtractSubtitleGlossaryCandidatesHandlesCJK(t *testing.T) {
+	t.Parallel()
+
+	cues := []dto.SubtitleCue{
+		{Index: 1, Text: "ルフィはどこだ？"},
+		{Index: 2, Text: "ルフィ、海賊王になる！"},
+		{Index: 3, Text: "海賊王の船だ。ルフィ！"},
+		{Index: 4, Text: "我要成为海贼王。"},
+	}
+	candidates := extractSubtitleGlossaryCandidates(cues, 3, 10, nil)
+	sources := make(map[string]int, len(candidates))
+	for _, item := range candidates {
+		sources[item.Source] = item.Occurrences
+	}
+	if sources["ルフィ"] != 3 {
+		t.Fatalf("expected katakana name ルフィ, got %#v", candidates)
+	}
+	if _, ok := sources["海賊"]; ok {
+		t.Fatalf("expected 海賊 to be subsumed by 海賊王 or dropped, got %#v", candidates)
+	}
+}
+
+func TestParseSubtitleGlossaryClassifiedTermsKeepsAcceptedCandidates(t *testing.T) {
+	t.Parallel()
+
+	candidates := []subtitleGlossaryCandidate{
+		{Source: "Captain Rex", Occurrences: 4, Example: "Tell Captain Rex about Kamino."},
+		{Source: "Okay", Occurrences: 9},
+	}
+	raw := "```json\n{\"terms\":[{\"source\":\"captain rex\",\"keep\":true,\"category\":\"Person\",\"target\":\"雷克斯上尉\",\"note\":\"clone officer\"},{\"source\":\"Okay\",\"keep\":false,\"category\":\"other\",\"target\":\"\",\"note\":\"\"},{\"source\":\"Invented\",\"keep\":true,\"category\":\"term\",\"target\":\"x\",\"note\":\"\"}]}\n```"
+	terms, err := parseSubtitleGlossaryClassifiedTerms(raw, candidates)
+	if err != nil {
+		t.Fatalf("parse terms: %v", err)
+	}
+	if len(terms) != 1 {
+		t.Fatalf("expected only the accepted known candidate, got %#v", terms)
+	}
+	if terms[0].Source != "Captain Rex" || terms[0].Category != "person" || terms[0].Occurrences != 4 || terms[0].Example == "" {
+		t.Fatalf("unexpected term %#v", terms[0])
+	}
+	if _, err := parseSubtitleGlossaryClassifiedTerms("not json", candidates); err == nil {
+		t.Fatalf("expected invalid output to fail")
+	}
+}
+
+func TestMergeGlossaryProfileTermsUpsertsBySource(t *testing.T) {
+	t.Parallel()
+
+	profiles := []dto.LibraryGlossaryProfileDTO{{
+		ID:    "series",
+		Name:  "Series",
+		Terms: []dto.LibraryGlossaryTermDTO{{Source: "Kamino", Target: "卡米诺"}},
+	}}
+	terms := []dto.LibraryGlossaryTermDTO{
+		{Source: "kamino", Target: "卡米诺星"},
+		{Source: "Captain Rex", Target: "雷克斯上尉"},
+		{Source: "Empty", Target: " "},
+	}
+	merged, profileID, accepted := mergeGlossaryProfileTerms(profiles, dto.LibraryGlossaryProfileDTO{ID: "series"}, terms)
+	if profileID != "series" || accepted != 2 || len(merged) != 1 {
+		t.Fatalf("unexpected merge result %q %d %#v", profileID, accepted, merged)
+	}
+	if len(merged[0].Terms) != 2 || merged[0].Terms[0].Target != "卡米诺星" || merged[0].Terms[1].Source != "Captain Rex" {
+		t.Fatalf("unexpected merged terms %#v", merged[0].Terms)
+	}
+	if len(profiles[0].Terms) != 1 || profiles[0].Terms[0].Target != "卡米诺" {
+		t.Fatalf("expected input profiles to stay untouched, got %#v", profiles[0].Terms)
+	}
+
+	created, createdID, _ := mergeGlossaryProfileTerms(profiles, dto.LibraryGlossaryProfileDTO{Name: "Season 2", TargetLanguage: "zh-CN"}, terms[1:2])
+	if len(created) != 2 || created[1].ID != createdID || created[1].Name != "Season 2" || created[1].TargetLanguage != "zh-CN" {
+		t.Fatalf("expected a new profile to be appended, got %#v", created)
+	}
+}
@@ -0,0 +1,732 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/externaltools"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleOCRDefaultLanguage       = "eng"
+	subtitleOCRDefaultIntervalMs     = int64(500)
+	subtitleOCRMinIntervalMs         = int64(100)
+	subtitleOCRMaxIntervalMs         = int64(5000)
+	subtitleOCRDefaultRegionTop      = 0.75
+	subtitleOCRDefaultRegionHeight   = 0.25
+	subtitleOCRMaxThreads            = 4
+	subtitleOCRProgressStep          = 20
+	subtitleOCRSimilarityThreshold   = 0.8
+	subtitleOCRMaxGapFrames          = 1
+	subtitleOCRFrameNamePattern      = "frame_%06d.png"
+	subtitleOCRFrameGlob             = "frame_*.png"
+	subtitleOCRPageSegmentationBlock = "6"
+)
+
+var subtitleOCRLanguagePattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\+[A-Za-z0-9_]+)*$`)
+
+type subtitleOCROutput struct {
+	FileID               string `json:"fileId,omitempty"`
+	DocumentID           string `json:"documentId,omitempty"`
+	SourceFileID         string `json:"sourceFileId,omitempty"`
+	Status               string `json:"status"`
+	Language             string `json:"language,omitempty"`
+	OutputFormat         string `json:"outputFormat,omitempty"`
+	SampleIntervalMs     int64  `json:"sampleIntervalMs,omitempty"`
+	FrameCount           int    `json:"frameCount,omitempty"`
+	RecognizedFrameCount int    `json:"recognizedFrameCount,omitempty"`
+	CueCount             int    `json:"cueCount,omitempty"`
+}
+
+func (service *LibraryService) CreateSubtitleOCRJob(ctx context.Context, request dto.SubtitleOCRRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleOCRRequest(request)
+	if request.FileID == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("fileId is required")
+	}
+	if !subtitleOCRLanguagePattern.MatchString(request.Language) {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("invalid ocr language %q", request.Language)
+	}
+	sourceFile, err := service.files.Get(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := validateSubtitleOCRSource(sourceFile); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "subtitle_ocr",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleOCROutputName(sourceFile.Name),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  marshalJSON(buildSubtitleOCROutput(request, "queued")),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			0,
+			progressText("library.progressDetail.subtitleOcrQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleOCROperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleOCROperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleOCRRequest) {
+	request = normalizeSubtitleOCRRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, 0)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+
+	sourceFile, err := service.files.Get(ctx, request.FileID)
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	if err := validateSubtitleOCRSource(sourceFile); err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	tesseractExecPath, err := resolveTesseractExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	if probe.DurationMs <= 0 {
+		service.failSubtitleOCROperation(ctx, operation, errors.New("source media duration is unknown"))
+		return
+	}
+	if probe.Width <= 0 || probe.Height <= 0 {
+		service.failSubtitleOCROperation(ctx, operation, errors.New("source media has no video stream"))
+		return
+	}
+
+	output := buildSubtitleOCROutput(request, "running")
+	output.SourceFileID = sourceFile.ID
+	operation.Status = library.OperationStatusRunning
+	now := service.now()
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		0,
+		progressText("library.progressDetail.preparingSubtitleOcr"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	tempDir, err := os.MkdirTemp("", "dreamcreator-ocr-*")
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	reporter := newFFmpegProgressReporter(service, &operation, probe.DurationMs)
+	reporter.stage = progressText("library.progress.preparing")
+	reporter.message = progressText("library.progressDetail.subtitleOcrExtractingFrames")
+	outputText, err := service.runFFmpegCommandWithReporter(
+		runCtx,
+		reporter,
+		ffmpegExecPath,
+		buildSubtitleOCRFrameExtractArgs(sourceFile.Storage.LocalPath, filepath.Join(tempDir, subtitleOCRFrameNamePattern), request),
+		tempDir,
+	)
+	if err != nil {
+		if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+			_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+			return
+		}
+		message := strings.TrimSpace(outputText)
+		if message == "" {
+			message = err.Error()
+		}
+		service.failSubtitleOCROperation(ctx, operation, fmt.Errorf("ffmpeg frame extraction failed: %s", message))
+		return
+	}
+	framePaths, err := filepath.Glob(filepath.Join(tempDir, subtitleOCRFrameGlob))
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	sort.Strings(framePaths)
+	if len(framePaths) == 0 {
+		service.failSubtitleOCROperation(ctx, operation, errors.New("no frames were sampled from the source video"))
+		return
+	}
+	output.FrameCount = len(framePaths)
+	operation.OutputJSON = marshalJSON(output)
+
+	var progressMu sync.Mutex
+	completedFrames := 0
+	frameTexts, err := runTesseractFrames(runCtx, tesseractExecPath, framePaths, request, func() {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		completedFrames++
+		if completedFrames%subtitleOCRProgressStep != 0 && completedFrames != len(framePaths) {
+			return
+		}
+		operation.Progress = buildOperationProgress(
+			service.now(),
+			progressText("library.progress.recognizing"),
+			completedFrames,
+			len(framePaths),
+			progressTextTemplate("library.progressDetail.subtitleOcrProgress", map[string]string{
+				"current": strconv.Itoa(completedFrames),
+				"total":   strconv.Itoa(len(framePaths)),
+			}),
+		)
+		_ = service.saveAndPublishOperation(ctx, operation)
+	})
+	if err != nil {
+		if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+			_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+			return
+		}
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+
+	recognizedFrameCount := 0
+	for _, text := range frameTexts {
+		if text != "" {
+			recognizedFrameCount++
+		}
+	}
+	cues := buildSubtitleOCRCues(frameTexts, request.SampleIntervalMs, probe.DurationMs)
+	if len(cues) == 0 {
+		service.failSubtitleOCROperation(ctx, operation, errors.New("no subtitle text was recognized"))
+		return
+	}
+
+	outputFormat := resolveSubtitleTranscribeOutputFormat(request.OutputFormat)
+	ocrDocument := dto.SubtitleDocument{
+		Format: outputFormat,
+		Cues:   cues,
+		Metadata: map[string]any{
+			"ocr":      true,
+			"language": request.Language,
+		},
+	}
+	ocrContent := renderSubtitleContent(ocrDocument, outputFormat)
+	finishedAt := service.now()
+	ocrFile, history, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+		LibraryID:      sourceFile.LibraryID,
+		RootFileID:     rootFileID(sourceFile),
+		SourceFileIDs:  []string{sourceFile.ID},
+		Name:           buildSubtitleOCROutputName(sourceFile.Name),
+		OperationID:    operation.ID,
+		OperationKind:  "subtitle_ocr",
+		Format:         outputFormat,
+		OriginalSource: ocrContent,
+		OccurredAt:     finishedAt,
+		HistorySource:  library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID},
+	})
+	if err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	ocrFile.LatestOperationID = operation.ID
+	ocrFile.UpdatedAt = finishedAt
+	if err := service.files.Save(ctx, ocrFile); err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.OutputFiles = []library.OperationOutputFile{{
+		FileID:    ocrFile.ID,
+		Kind:      string(ocrFile.Kind),
+		Format:    mediaFormatFromFile(ocrFile),
+		SizeBytes: mediaSizeFromFile(ocrFile),
+		IsPrimary: true,
+		Deleted:   ocrFile.State.Deleted,
+	}}
+	operation.Metrics = buildOperationMetricsForOperation([]library.LibraryFile{ocrFile}, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		len(framePaths),
+		len(framePaths),
+		progressText("library.progressDetail.subtitleOcrCompleted"),
+	)
+	output.Status = "completed"
+	output.FileID = ocrFile.ID
+	output.DocumentID = ocrFile.Storage.DocumentID
+	output.OutputFormat = outputFormat
+	output.RecognizedFrameCount = recognizedFrameCount
+	output.CueCount = len(cues)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+
+	history.Refs.OperationID = operation.ID
+	history.Action = "subtitle_ocr"
+	history.Status = string(operation.Status)
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "subtitle_ocr"}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, sourceFile.LibraryID, finishedAt); err != nil {
+		service.failSubtitleOCROperation(ctx, operation, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	service.publishFileUpdate(service.mustBuildFileDTO(ctx, ocrFile))
+	service.publishWorkspaceProjectUpdate(sourceFile.LibraryID)
+}
+
+// runTesseractFrames recognizes every sampled frame on a small worker pool and
+// returns the normalized text per frame, keeping the frame order.
+func runTesseractFrames(
+	ctx context.Context,
+	execPath string,
+	framePaths []string,
+	request dto.SubtitleOCRRequest,
+	onFrame func(),
+) ([]string, error) {
+	workers := request.Threads
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+		if workers > subtitleOCRMaxThreads {
+			workers = subtitleOCRMaxThreads
+		}
+	}
+	if workers > len(framePaths) {
+		workers = len(framePaths)
+	}
+	texts := make([]string, len(framePaths))
+	jobs := make(chan int)
+	var firstErr error
+	var errOnce sync.Once
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				text, err := runTesseractFrame(workerCtx, execPath, framePaths[index], request.Language)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				texts[index] = normalizeSubtitleOCRText(text)
+				if onFrame != nil {
+					onFrame()
+				}
+			}
+		}()
+	}
+	for index := range framePaths {
+		if workerCtx.Err() != nil {
+			break
+		}
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return texts, nil
+}
+
+func runTesseractFrame(ctx context.Context, execPath string, framePath string, language string) (string, error) {
+	command := exec.CommandContext(ctx, execPath, buildTesseractArgs(framePath, language)...)
+	command.Dir = filepath.Dir(framePath)
+	// Frames are already processed in parallel, so keep each tesseract process
+	// single-threaded instead of oversubscribing the CPU through OpenMP.
+	command.Env = append(os.Environ(), "OMP_THREAD_LIMIT=1")
+	configureProcessGroup(command)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("tesseract recognition failed: %s", tailWhisperCPPOutput(message, 5))
+	}
+	return stdout.String(), nil
+}
+
+func buildTesseractArgs(framePath string, language string) []string {
+	return []string{
+		framePath,
+		"stdout",
+		"-l", firstNonEmpty(language, subtitleOCRDefaultLanguage),
+		"--psm", subtitleOCRPageSegmentationBlock,
+	}
+}
+
+// buildSubtitleOCRFrameExtractArgs samples one grayscale frame per interval and
+// crops it to the subtitle band, so frame N starts at (N-1)*interval.
+func buildSubtitleOCRFrameExtractArgs(inputPath string, outputPattern string, request dto.SubtitleOCRRequest) []string {
+	filters := []string{
+		fmt.Sprintf("fps=1000/%d", request.SampleIntervalMs),
+		fmt.Sprintf(
+			"crop=iw:ih*%s:0:ih*%s",
+			strconv.FormatFloat(request.RegionHeight, 'f', -1, 64),
+			strconv.FormatFloat(request.RegionTop, 'f', -1, 64),
+		),
+		"format=gray",
+	}
+	return []string{
+		"-y",
+		"-i", inputPath,
+		"-an",
+		"-sn",
+		"-vf", strings.Join(filters, ","),
+		outputPattern,
+	}
+}
+
+// normalizeSubtitleOCRText trims the recognized lines and drops results that
+// carry no letters or digits, which tesseract reports for empty subtitle bands.
+func normalizeSubtitleOCRText(text string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	result := strings.Join(lines, "\n")
+	meaningful := 0
+	for _, r := range result {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			meaningful++
+		}
+	}
+	if meaningful == 0 || (utf8.RuneCountInString(result) == 1 && result[0] < utf8.RuneSelf) {
+		return ""
+	}
+	return result
+}
+
+// buildSubtitleOCRCues merges runs of matching frame texts into timed cues. Small
+// OCR differences and single blank frames do not split a run; the most frequent
+// spelling in a run becomes the cue text.
+func buildSubtitleOCRCues(frameTexts []string, intervalMs int64, durationMs int64) []dto.SubtitleCue {
+	if intervalMs <= 0 {
+		intervalMs = subtitleOCRDefaultIntervalMs
+	}
+	type ocrRun struct {
+		first    int
+		last     int
+		lastText string
+		counts   map[string]int
+		order    []string
+	}
+	cues := make([]dto.SubtitleCue, 0)
+	var current *ocrRun
+	flush := func() {
+		if current == nil {
+			return
+		}
+		text := current.order[0]
+		for _, candidate := range current.order[1:] {
+			if current.counts[candidate] > current.counts[text] {
+				text = candidate
+			}
+		}
+		startMs := int64(current.first) * intervalMs
+		endMs := int64(current.last+1) * intervalMs
+		if durationMs > 0 && endMs > durationMs {
+			endMs = durationMs
+		}
+		if endMs > startMs {
+			cues = append(cues, dto.SubtitleCue{
+				Index: len(cues) + 1,
+				Start: formatSRTTimestamp(startMs),
+				End:   formatSRTTimestamp(endMs),
+				Text:  text,
+			})
+		}
+		current = nil
+	}
+	for index, text := range frameTexts {
+		if text == "" {
+			if current != nil && index-current.last > subtitleOCRMaxGapFrames {
+				flush()
+			}
+			continue
+		}
+		if current != nil && subtitleOCRTextSimilarity(current.lastText, text) < subtitleOCRSimilarityThreshold {
+			flush()
+		}
+		if current == nil {
+			current = &ocrRun{first: index, counts: make(map[string]int)}
+		}
+		if _, ok := current.counts[text]; !ok {
+			current.order = append(current.order, text)
+		}
+		current.counts[text]++
+		current.last = index
+		current.lastText = text
+	}
+	flush()
+	return cues
+}
+
+// subtitleOCRTextSimilarity compares two recognized texts ignoring case and
+// whitespace, returning 1 for identical text and 0 for unrelated text.
+func subtitleOCRTextSimilarity(left string, right string) float64 {
+	normalize := func(value string) []rune {
+		result := make([]rune, 0, len(value))
+		for _, r := range strings.ToLower(value) {
+			if !unicode.IsSpace(r) {
+				result = append(result, r)
+			}
+		}
+		return result
+	}
+	a := normalize(left)
+	b := normalize(right)
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 1
+	}
+	previous := make([]int, len(b)+1)
+	currentRow := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		currentRow[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			currentRow[j] = min(previous[j]+1, currentRow[j-1]+1, previous[j-1]+cost)
+		}
+		previous, currentRow = currentRow, previous
+	}
+	return 1 - float64(previous[len(b)])/float64(longest)
+}
+
+func resolveTesseractExecPath(ctx context.Context, resolver ToolResolver) (string, error) {
+	if resolver == nil {
+		return "", fmt.Errorf("tesseract is not installed")
+	}
+	ready, reason, err := resolver.ToolReadiness(ctx, externaltools.ToolTesseract)
+	if err != nil {
+		return "", err
+	}
+	if !ready {
+		switch strings.TrimSpace(reason) {
+		case "invalid":
+			return "", fmt.Errorf("tesseract is invalid")
+		case "missing_exec_path", "exec_not_found", "not_found", "not_installed", "":
+			return "", fmt.Errorf("tesseract is not installed")
+		default:
+			return "", fmt.Errorf("tesseract is not ready: %s", reason)
+		}
+	}
+	return resolver.ResolveExecPath(ctx, externaltools.ToolTesseract)
+}
+
+func validateSubtitleOCRSource(sourceFile library.LibraryFile) error {
+	if strings.TrimSpace(sourceFile.LibraryID) == "" {
+		return fmt.Errorf("source file is not attached to a library")
+	}
+	switch sourceFile.Kind {
+	case library.FileKindVideo, library.FileKindTranscode:
+	default:
+		return fmt.Errorf("file kind %q does not support subtitle ocr", sourceFile.Kind)
+	}
+	if strings.TrimSpace(sourceFile.Storage.LocalPath) == "" {
+		return fmt.Errorf("source media path is empty")
+	}
+	return nil
+}
+
+func (service *LibraryService) failSubtitleOCROperation(ctx context.Context, operation library.LibraryOperation, err error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	if errors.Is(err, context.Canceled) || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+	currentOperation := operation
+	if item, getErr := service.operations.Get(ctx, operation.ID); getErr == nil {
+		currentOperation = item
+		if currentOperation.Status == library.OperationStatusCanceled {
+			return
+		}
+	}
+	now := service.now()
+	currentOperation.Status = library.OperationStatusFailed
+	currentOperation.ErrorCode = "subtitle_ocr_failed"
+	currentOperation.ErrorMessage = strings.TrimSpace(err.Error())
+	currentOperation.FinishedAt = &now
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		progressCurrent(currentOperation.Progress),
+		progressTotal(currentOperation.Progress),
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	currentOperation.OutputJSON = buildTerminalSubtitleOCROutput(currentOperation.InputJSON, currentOperation.OutputJSON, "failed")
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+}
+
+func buildTerminalSubtitleOCROutput(inputJSON string, currentOutputJSON string, status string) string {
+	output := buildSubtitleOCROutput(extractSubtitleOCRRequest(inputJSON), status)
+	existing := subtitleOCROutput{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(currentOutputJSON)), &existing); err == nil {
+		existing.Status = status
+		existing.FileID = ""
+		existing.DocumentID = ""
+		output = existing
+	}
+	return marshalJSON(output)
+}
+
+func buildSubtitleOCROutput(request dto.SubtitleOCRRequest, status string) subtitleOCROutput {
+	return subtitleOCROutput{
+		SourceFileID:     request.FileID,
+		Status:           status,
+		Language:         request.Language,
+		OutputFormat:     resolveSubtitleTranscribeOutputFormat(request.OutputFormat),
+		SampleIntervalMs: request.SampleIntervalMs,
+	}
+}
+
+func buildSubtitleOCROutputName(name string) string {
+	base := strings.TrimSpace(strings.TrimSuffix(name, filepath.Ext(name)))
+	if base == "" {
+		base = "Subtitles"
+	}
+	return fmt.Sprintf("%s (OCR)", base)
+}
+
+func normalizeSubtitleOCRRequest(request dto.SubtitleOCRRequest) dto.SubtitleOCRRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.Language = firstNonEmpty(strings.TrimSpace(request.Language), subtitleOCRDefaultLanguage)
+	request.OutputFormat = strings.TrimSpace(request.OutputFormat)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	switch {
+	case request.SampleIntervalMs <= 0:
+		request.SampleIntervalMs = subtitleOCRDefaultIntervalMs
+	case request.SampleIntervalMs < subtitleOCRMinIntervalMs:
+		request.SampleIntervalMs = subtitleOCRMinIntervalMs
+	case request.SampleIntervalMs > subtitleOCRMaxIntervalMs:
+		request.SampleIntervalMs = subtitleOCRMaxIntervalMs
+	}
+	if request.RegionTop <= 0 && request.RegionHeight <= 0 {
+		request.RegionTop = subtitleOCRDefaultRegionTop
+		request.RegionHeight = subtitleOCRDefaultRegionHeight
+	}
+	if request.RegionTop < 0 || request.RegionTop >= 1 {
+		request.RegionTop = subtitleOCRDefaultRegionTop
+	}
+	if request.RegionHeight <= 0 || request.RegionTop+request.RegionHeight > 1 {
+		request.RegionHeight = 1 - request.RegionTop
+	}
+	if request.Threads < 0 {
+		request.Threads = 0
+	}
+	return request
+}
+
+func extractSubtitleOCRRequest(inputJSON string) dto.SubtitleOCRRequest {
+	request := dto.SubtitleOCRRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleOCRRequest{}
+	}
+	return normalizeSubtitleOCRRequest(request)
+}
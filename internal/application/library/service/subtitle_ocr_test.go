@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestBuildSubtitleOCRCuesMergesConsecutiveFrames(t *testing.T) {
+	t.Parallel()
+
+	frames := []string{
+		"",
+		"Hello there",
+		"Hello there",
+		"Hel1o there",
+		"",
+		"Hello there",
+		"",
+		"",
+		"General Kenobi",
+		"General Kenobi",
+		"Something else entirely",
+	}
+	cues := buildSubtitleOCRCues(frames, 500, 5200)
+	if len(cues) != 3 {
+		t.Fatalf("expected 3 cues, got %#v", cues)
+	}
+	if cues[0].Text != "Hello there" || cues[0].Start != "00:00:00,500" || cues[0].End != "00:00:03,000" {
+		t.Fatalf("unexpected first cue %#v", cues[0])
+	}
+	if cues[1].Text != "General Kenobi" || cues[1].Start != "00:00:04,000" || cues[1].End != "00:00:05,000" {
+		t.Fatalf("unexpected second cue %#v", cues[1])
+	}
+	if cues[2].Index != 3 || cues[2].End != "00:00:05,200" {
+		t.Fatalf("expected the last cue to be clamped to the duration, got %#v", cues[2])
+	}
+}
+
+func TestNormalizeSubtitleOCRText(t *testing.T) {
+	t.Parallel()
+
+	if text := normalizeSubtitleOCRText("  Hello   world \n\n  second line\r\n\f"); text != "Hello world\nsecond line" {
+		t.Fatalf("unexpected normalized text %q", text)
+	}
+	for _, noise := range []string{"", " | ", "—", "~ .", "i"} {
+		if text := normalizeSubtitleOCRText(noise); text != "" {
+			t.Fatalf("expected %q to be dropped, got %q", noise, text)
+		}
+	}
+	if text := normalizeSubtitleOCRText("好"); text != "好" {
+		t.Fatalf("expected a single CJK character to be kept, got %q", text)
+	}
+}
+
+func TestNormalizeSubtitleOCRRequestDefaults(t *testing.T) {
+	t.Parallel()
+
+	request := normalizeSubtitleOCRRequest(dto.SubtitleOCRRequest{FileID: " file-1 "})
+	if request.FileID != "file-1" || request.Language != "eng" || request.SampleIntervalMs != 500 {
+		t.Fatalf("unexpected defaults %#v", request)
+	}
+	if request.RegionTop != 0.75 || request.RegionHeight != 0.25 {
+		t.Fatalf("unexpected default region %#v", request)
+	}
+	request = normalizeSubtitleOCRRequest(dto.SubtitleOCRRequest{SampleIntervalMs: 20, RegionTop: 0.8, RegionHeight: 0.5})
+	if request.SampleIntervalMs != 100 || request.RegionHeight < 0.19 || request.RegionHeight > 0.21 {
+		t.Fatalf("expected the interval and region to be clamped, got %#v", request)
+	}
+}
+
+func TestBuildSubtitleOCRArgs(t *testing.T) {
+	t.Parallel()
+
+	request := normalizeSubtitleOCRRequest(dto.SubtitleOCRRequest{FileID: "file-1"})
+	args := strings.Join(buildSubtitleOCRFrameExtractArgs("/tmp/input.mp4", "/tmp/ocr/frame_%06d.png", request), " ")
+	if !strings.Contains(args, "-vf fps=1000/500,crop=iw:ih*0.25:0:ih*0.75,format=gray /tmp/ocr/frame_%06d.png") {
+		t.Fatalf("unexpected ffmpeg args %q", args)
+	}
+	tesseract := strings.Join(buildTesseractArgs("/tmp/ocr/frame_000001.png", "chi_sim+eng"), " ")
+	if tesseract != "/tmp/ocr/frame_000001.png stdout -l chi_sim+eng --psm 6" {
+		t.Fatalf("unexpected tesseract args %q", tesseract)
+	}
+	if subtitleOCRLanguagePattern.MatchString("eng --oem") {
+		t.Fatalf("expected option injection in the language to be rejected")
+	}
+}
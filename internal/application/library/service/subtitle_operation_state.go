@@ -11,13 +11,24 @@ import (
 
 func isResumableSubtitleOperation(kind string) bool {
 	switch strings.TrimSpace(kind) {
-	case "subtitle_translate", "subtitle_proofread":
+	case "subtitle_translate", "subtitle_proofread", "subtitle_transcribe":
 		return true
 	default:
 		return false
 	}
 }
 
+// isCancelableOperation extends the resumable kinds with parent operations, which
+// stop their child operations but restart from their task list.
+func isCancelableOperation(kind string) bool {
+	switch strings.TrimSpace(kind) {
+	case "subtitle_pipeline", "download_playlist", "clip", "chapter_split", "subtitle_ocr", "subtitle_extract", "thumbnail_generate":
+		return true
+	default:
+		return isResumableSubtitleOperation(kind)
+	}
+}
+
 func progressCurrent(progress *library.OperationProgress) int {
 	if progress == nil || progress.Current == nil {
 		return 0
@@ -72,6 +83,24 @@ func buildCanceledSubtitleOperationOutput(kind string, inputJSON string, current
 		}
 		output.Status = "canceled"
 		return marshalJSON(output)
+	case "subtitle_transcribe":
+		return buildTerminalSubtitleTranscribeOutput(inputJSON, currentOutputJSON, "canceled")
+	case "subtitle_ocr":
+		return buildTerminalSubtitleOCROutput(inputJSON, currentOutputJSON, "canceled")
+	case "subtitle_pipeline":
+		output, ok := parseSubtitlePipelineOutput(currentOutputJSON)
+		if !ok {
+			output = newSubtitlePipelineOutput(extractSubtitlePipelineRequest(inputJSON), "", 0)
+		}
+		output.Status = "canceled"
+		return marshalJSON(output)
+	case "download_playlist":
+		output, ok := parseYTDLPPlaylistOutput(currentOutputJSON)
+		if !ok {
+			output = newYTDLPPlaylistOutput(extractYTDLPPlaylistRequest(inputJSON), "")
+		}
+		output.Status = "canceled"
+		return marshalJSON(output)
 	default:
 		return strings.TrimSpace(currentOutputJSON)
 	}
@@ -115,6 +144,17 @@ func buildQueuedSubtitleOperationOutput(kind string, inputJSON string, currentOu
 			output.ResumedFromCheckpoint = existing.CompletedChunkCount > 0 || existing.ResumedFromCheckpoint
 		}
 		return marshalJSON(output)
+	case "subtitle_transcribe":
+		output := buildSubtitleTranscribeOutput(extractSubtitleTranscribeRequest(inputJSON), "queued")
+		if existing, ok := parseSubtitleTranscribeOutput(currentOutputJSON); ok {
+			output.Model = existing.Model
+			output.DurationMs = existing.DurationMs
+			output.ChunkCount = existing.ChunkCount
+			output.CompletedChunkCount = existing.CompletedChunkCount
+			output.RequestHash = existing.RequestHash
+			output.ResumedFromCheckpoint = existing.CompletedChunkCount > 0 || existing.ResumedFromCheckpoint
+		}
+		return marshalJSON(output)
 	default:
 		return strings.TrimSpace(currentOutputJSON)
 	}
@@ -155,7 +195,7 @@ func (service *LibraryService) markSubtitleOperationCanceled(ctx context.Context
 	if err != nil {
 		return library.LibraryOperation{}, err
 	}
-	if !isResumableSubtitleOperation(item.Kind) {
+	if !isCancelableOperation(item.Kind) {
 		return item, nil
 	}
 	now := service.now()
@@ -88,7 +88,20 @@ func (service *LibraryService) ValidateSubtitle(ctx context.Context, request dto
 	if trimmed == "" {
 		return dto.SubtitleValidateResult{Valid: false, IssueCount: 1, Issues: []dto.SubtitleValidateIssue{{Severity: "error", Code: "empty_content", Message: "subtitle content is empty"}}}, nil
 	}
-	return validateSubtitleDocument(parseSubtitleDocument(content, format)), nil
+	document := parseSubtitleDocument(content, format)
+	result := validateSubtitleDocument(document)
+	if strings.TrimSpace(request.DeliveryProfileID) == "" && request.DeliveryProfile == nil {
+		return result, nil
+	}
+	profile, err := service.resolveSubtitleDeliveryProfile(ctx, request.DeliveryProfileID, request.DeliveryProfile)
+	if err != nil {
+		return dto.SubtitleValidateResult{}, err
+	}
+	result.Issues = append(result.Issues, validateSubtitleDeliveryCues(document, profile)...)
+	result.IssueCount = len(result.Issues)
+	result.Valid = result.IssueCount == 0
+	result.DeliveryProfileID = profile.ID
+	return result, nil
 }
 
 func (service *LibraryService) FixSubtitleTypos(ctx context.Context, request dto.SubtitleFixTyposRequest) (dto.SubtitleFixTyposResult, error) {
@@ -140,24 +153,12 @@ func (service *LibraryService) SaveSubtitle(ctx context.Context, request dto.Sub
 	} else if strings.TrimSpace(request.TargetFormat) != "" {
 		format = detectSubtitleFormat(request.TargetFormat, "", format)
 	}
-	if documentItem != nil {
-		documentItem.Format = format
-		documentItem.WorkingContent = content
-		documentItem.UpdatedAt = service.now()
-		if err := service.subtitles.Save(ctx, *documentItem); err != nil {
-			return dto.SubtitleSaveResult{}, err
-		}
-	}
-	if fileItem != nil {
-		fileItem.UpdatedAt = service.now()
-		if fileItem.Media == nil {
-			fileItem.Media = libraryMediaInfo(format, int64(len(content)))
-		} else {
-			fileItem.Media.Format = format
-			sizeValue := int64(len(content))
-			fileItem.Media.SizeBytes = &sizeValue
+	if documentItem != nil && fileItem != nil {
+		sourceKind := "manual_edit"
+		if strings.TrimSpace(request.SessionKey) != "" || strings.TrimSpace(request.RunID) != "" {
+			sourceKind = "agent_edit"
 		}
-		if err := service.files.Save(ctx, *fileItem); err != nil {
+		if _, _, err := service.replaceSubtitleWorkingContent(ctx, fileItem, documentItem, format, content, sourceKind); err != nil {
 			return dto.SubtitleSaveResult{}, err
 		}
 	}
@@ -169,12 +170,10 @@ func (service *LibraryService) RestoreSubtitleOriginal(ctx context.Context, requ
 	if err != nil {
 		return dto.RestoreSubtitleOriginalResult{}, err
 	}
-	if documentItem == nil {
+	if documentItem == nil || fileItem == nil {
 		return dto.RestoreSubtitleOriginalResult{}, fmt.Errorf("subtitle document not found")
 	}
-	documentItem.WorkingContent = documentItem.OriginalContent
-	documentItem.UpdatedAt = service.now()
-	if err := service.subtitles.Save(ctx, *documentItem); err != nil {
+	if _, _, err := service.replaceSubtitleWorkingContent(ctx, fileItem, documentItem, documentItem.Format, documentItem.OriginalContent, "restore"); err != nil {
 		return dto.RestoreSubtitleOriginalResult{}, err
 	}
 	return dto.RestoreSubtitleOriginalResult{FileID: subtitleResultFileID(fileItem), Format: documentItem.Format, Bytes: len(documentItem.WorkingContent)}, nil
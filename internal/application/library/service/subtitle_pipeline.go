@@ -0,0 +1,934 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	runtimedto "dreamcreator/internal/application/gateway/runtime/dto"
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitlePipelineDefaultConcurrency = 2
+	subtitlePipelineMaxConcurrency     = 8
+	subtitlePipelineMaxSteps           = 16
+	subtitlePipelineDefaultFormat      = "srt"
+)
+
+const (
+	subtitlePipelineStepProofread = "proofread"
+	subtitlePipelineStepTranslate = "translate"
+	subtitlePipelineStepQAReview  = "qa_review"
+	subtitlePipelineStepExport    = "export"
+)
+
+type subtitlePipelineOutput struct {
+	FileIDs            []string                `json:"fileIds,omitempty"`
+	Steps              []string                `json:"steps,omitempty"`
+	Status             string                  `json:"status"`
+	MaxConcurrency     int                     `json:"maxConcurrency,omitempty"`
+	TotalTaskCount     int                     `json:"totalTaskCount"`
+	CompletedTaskCount int                     `json:"completedTaskCount"`
+	FailedTaskCount    int                     `json:"failedTaskCount"`
+	SkippedTaskCount   int                     `json:"skippedTaskCount"`
+	ChildOperationIDs  []string                `json:"childOperationIds,omitempty"`
+	OutputFileIDs      []string                `json:"outputFileIds,omitempty"`
+	ExportPaths        []string                `json:"exportPaths,omitempty"`
+	Tasks              []subtitlePipelineTask  `json:"tasks,omitempty"`
+	Usage              runtimedto.RuntimeUsage `json:"usage,omitempty"`
+}
+
+// subtitlePipelineTask records one step applied to one track. The key is stable
+// across restarts so a recovered pipeline can reuse finished child operations.
+type subtitlePipelineTask struct {
+	Key             string                  `json:"key"`
+	RootFileID      string                  `json:"rootFileId"`
+	FileID          string                  `json:"fileId"`
+	StepIndex       int                     `json:"stepIndex"`
+	Kind            string                  `json:"kind"`
+	Language        string                  `json:"language,omitempty"`
+	Status          string                  `json:"status"`
+	OperationID     string                  `json:"operationId,omitempty"`
+	OutputFileID    string                  `json:"outputFileId,omitempty"`
+	ReviewSessionID string                  `json:"reviewSessionId,omitempty"`
+	Applied         bool                    `json:"applied,omitempty"`
+	ExportPath      string                  `json:"exportPath,omitempty"`
+	Error           string                  `json:"error,omitempty"`
+	Usage           runtimedto.RuntimeUsage `json:"usage,omitempty"`
+}
+
+type subtitlePipelineRun struct {
+	service    *LibraryService
+	ctx        context.Context
+	runCtx     context.Context
+	request    dto.SubtitlePipelineRequest
+	slots      chan struct{}
+	previous   map[string]subtitlePipelineTask
+	mu         sync.Mutex
+	operation  library.LibraryOperation
+	output     subtitlePipelineOutput
+	taskIndex  map[string]int
+	active     map[string]struct{}
+	doneUnits  int
+	totalUnits int
+	exportMu   sync.Mutex
+}
+
+func (service *LibraryService) CreateSubtitlePipelineJob(ctx context.Context, request dto.SubtitlePipelineRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitlePipelineRequest(request)
+	if err := validateSubtitlePipelineSteps(request.Steps); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if service.runtime == nil && subtitlePipelineNeedsRuntime(request.Steps) {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("subtitle language runtime unavailable")
+	}
+	files, err := service.resolveLibrarySubtitleFiles(ctx, request.FileIDs, request.LibraryID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	request.FileIDs = make([]string, 0, len(files))
+	for _, item := range files {
+		request.FileIDs = append(request.FileIDs, item.ID)
+	}
+	request.LibraryID = files[0].LibraryID
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	totalUnits := len(files) * countSubtitlePipelineUnits(request.Steps)
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   request.LibraryID,
+		Kind:        "subtitle_pipeline",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitlePipelineDisplayName(files),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  marshalJSON(newSubtitlePipelineOutput(request, "queued", totalUnits)),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			totalUnits,
+			progressText("library.progressDetail.subtitlePipelineQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitlePipelineOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+// runSubtitlePipelineOperation fans every file out into child operations. Each
+// file walks the steps on its own goroutine, translate branches per language,
+// and all child operations share one concurrency limit.
+func (service *LibraryService) runSubtitlePipelineOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitlePipelineRequest) {
+	request = normalizeSubtitlePipelineRequest(request)
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	if err := validateSubtitlePipelineSteps(request.Steps); err != nil {
+		service.failSubtitlePipelineOperation(ctx, operation, err)
+		return
+	}
+	files, err := service.resolveLibrarySubtitleFiles(ctx, request.FileIDs, request.LibraryID)
+	if err != nil {
+		service.failSubtitlePipelineOperation(ctx, operation, err)
+		return
+	}
+	previous := make(map[string]subtitlePipelineTask)
+	if existing, ok := parseSubtitlePipelineOutput(operation.OutputJSON); ok {
+		for _, task := range existing.Tasks {
+			previous[task.Key] = task
+		}
+	}
+
+	totalUnits := len(files) * countSubtitlePipelineUnits(request.Steps)
+	run := &subtitlePipelineRun{
+		service:    service,
+		ctx:        ctx,
+		runCtx:     runCtx,
+		request:    request,
+		slots:      make(chan struct{}, request.MaxConcurrency),
+		previous:   previous,
+		operation:  operation,
+		output:     newSubtitlePipelineOutput(request, "running", totalUnits),
+		taskIndex:  make(map[string]int),
+		active:     make(map[string]struct{}),
+		totalUnits: totalUnits,
+	}
+	now := service.now()
+	run.operation.Status = library.OperationStatusRunning
+	run.operation.StartedAt = &now
+	run.operation.Progress = run.buildProgress(now)
+	run.operation.OutputJSON = marshalJSON(run.output)
+	if err := service.saveAndPublishOperation(ctx, run.operation); err != nil {
+		return
+	}
+
+	go run.watchCancel()
+	var wg sync.WaitGroup
+	for _, item := range files {
+		wg.Add(1)
+		go func(fileID string) {
+			defer wg.Done()
+			run.runTrack(fileID, fileID, fileID, "", 0)
+		}(item.ID)
+	}
+	wg.Wait()
+
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+	service.finishSubtitlePipelineOperation(ctx, run, files)
+}
+
+func (run *subtitlePipelineRun) runTrack(rootFileID string, fileID string, trackKey string, language string, stepIndex int) {
+	for ; stepIndex < len(run.request.Steps); stepIndex++ {
+		if run.canceled() {
+			return
+		}
+		step := run.request.Steps[stepIndex]
+		if step.Kind != subtitlePipelineStepTranslate {
+			task := run.runTask(rootFileID, fileID, trackKey, language, stepIndex)
+			if task.Status != "succeeded" {
+				run.skipUnits(countSubtitlePipelineUnits(run.request.Steps[stepIndex+1:]))
+				return
+			}
+			continue
+		}
+		var wg sync.WaitGroup
+		for _, targetLanguage := range step.TargetLanguages {
+			wg.Add(1)
+			go func(targetLanguage string) {
+				defer wg.Done()
+				task := run.runTask(rootFileID, fileID, trackKey, targetLanguage, stepIndex)
+				if task.Status != "succeeded" {
+					run.skipUnits(countSubtitlePipelineUnits(run.request.Steps[stepIndex+1:]))
+					return
+				}
+				run.runTrack(rootFileID, task.OutputFileID, trackKey+">"+targetLanguage, targetLanguage, stepIndex+1)
+			}(targetLanguage)
+		}
+		wg.Wait()
+		return
+	}
+}
+
+func (run *subtitlePipelineRun) runTask(rootFileID string, fileID string, trackKey string, language string, stepIndex int) subtitlePipelineTask {
+	step := run.request.Steps[stepIndex]
+	task := subtitlePipelineTask{
+		Key:        fmt.Sprintf("%s#%d", trackKey, stepIndex),
+		RootFileID: rootFileID,
+		FileID:     fileID,
+		StepIndex:  stepIndex,
+		Kind:       step.Kind,
+		Language:   language,
+		Status:     "running",
+	}
+	if step.Kind == subtitlePipelineStepTranslate {
+		task.Key = fmt.Sprintf("%s#%d>%s", trackKey, stepIndex, language)
+	}
+	if previous, ok := run.previous[task.Key]; ok && previous.Status == "succeeded" && previous.FileID == fileID {
+		run.completeTask(previous)
+		return previous
+	}
+	if !run.acquire() {
+		return task
+	}
+	defer run.release()
+
+	if step.Kind == subtitlePipelineStepExport {
+		return run.completeTask(run.exportTrack(task, step))
+	}
+	var operation library.LibraryOperation
+	if previous, ok := run.previous[task.Key]; ok && previous.OperationID != "" && previous.FileID == fileID {
+		if item, err := run.service.operations.Get(run.ctx, previous.OperationID); err == nil && item.Status != library.OperationStatusFailed && item.Status != library.OperationStatusCanceled {
+			operation = item
+		}
+	}
+	if operation.ID == "" {
+		item, err := run.queueChild(task, step)
+		if err != nil {
+			task.Status = "failed"
+			task.Error = err.Error()
+			return run.completeTask(task)
+		}
+		operation = item
+	}
+	task.OperationID = operation.ID
+	run.trackTask(task)
+	if operation.Status == library.OperationStatusQueued || operation.Status == library.OperationStatusRunning {
+		run.setActive(operation.ID, true)
+		run.runChild(operation)
+		run.setActive(operation.ID, false)
+		if item, err := run.service.operations.Get(run.ctx, operation.ID); err == nil {
+			operation = item
+		}
+	}
+	return run.completeTask(run.collectChild(task, step, operation))
+}
+
+func (run *subtitlePipelineRun) queueChild(task subtitlePipelineTask, step dto.SubtitlePipelineStep) (library.LibraryOperation, error) {
+	correlation := library.OperationCorrelation{RunID: run.request.RunID, ParentOperationID: run.operation.ID}
+	switch step.Kind {
+	case subtitlePipelineStepTranslate:
+		return run.service.queueSubtitleTranslateOperation(run.ctx, run.translateRequest(task, step), correlation)
+	case subtitlePipelineStepProofread:
+		return run.service.queueSubtitleProofreadOperation(run.ctx, run.proofreadRequest(task, step), correlation)
+	case subtitlePipelineStepQAReview:
+		return run.service.queueSubtitleQAReviewOperation(run.ctx, run.qaReviewRequest(task, step), correlation)
+	default:
+		return library.LibraryOperation{}, fmt.Errorf("unsupported pipeline step %q", step.Kind)
+	}
+}
+
+func (run *subtitlePipelineRun) runChild(operation library.LibraryOperation) {
+	switch operation.Kind {
+	case "subtitle_translate":
+		run.service.runSubtitleTranslateOperation(context.Background(), operation, extractSubtitleTranslateRequest(operation.InputJSON))
+	case "subtitle_proofread":
+		run.service.runSubtitleProofreadOperation(context.Background(), operation, extractSubtitleProofreadRequest(operation.InputJSON))
+	case "subtitle_qa_review":
+		run.service.runSubtitleQAReviewOperation(context.Background(), operation, extractSubtitleQAReviewRequest(operation.InputJSON))
+	}
+}
+
+func (run *subtitlePipelineRun) collectChild(task subtitlePipelineTask, step dto.SubtitlePipelineStep, operation library.LibraryOperation) subtitlePipelineTask {
+	switch operation.Status {
+	case library.OperationStatusSucceeded:
+	case library.OperationStatusCanceled:
+		task.Status = "failed"
+		task.Error = "child operation canceled"
+		return task
+	default:
+		task.Status = "failed"
+		task.Error = firstNonEmpty(strings.TrimSpace(operation.ErrorMessage), fmt.Sprintf("child operation ended as %s", operation.Status))
+		return task
+	}
+	switch step.Kind {
+	case subtitlePipelineStepTranslate:
+		output, _ := parseSubtitleTranslateOutput(operation.OutputJSON)
+		task.Usage = output.Usage
+		task.OutputFileID = strings.TrimSpace(output.FileID)
+		if task.OutputFileID == "" {
+			task.Status = "failed"
+			task.Error = "translation produced no subtitle file"
+			return task
+		}
+	case subtitlePipelineStepProofread:
+		output, _ := parseSubtitleProofreadOutput(operation.OutputJSON)
+		task.Usage = output.Usage
+		task.ReviewSessionID = strings.TrimSpace(output.ReviewSessionID)
+	case subtitlePipelineStepQAReview:
+		output := subtitleQAReviewOutput{}
+		_ = json.Unmarshal([]byte(strings.TrimSpace(operation.OutputJSON)), &output)
+		task.ReviewSessionID = strings.TrimSpace(output.ReviewSessionID)
+	}
+	if step.AutoApply && task.ReviewSessionID != "" {
+		applied, err := run.applyReviewSession(task.ReviewSessionID)
+		if err != nil {
+			task.Status = "failed"
+			task.Error = err.Error()
+			return task
+		}
+		task.Applied = applied
+	}
+	task.Status = "succeeded"
+	return task
+}
+
+// applyReviewSession accepts every suggestion of a pending session. Sessions that
+// were already applied by an earlier attempt count as applied.
+func (run *subtitlePipelineRun) applyReviewSession(sessionID string) (bool, error) {
+	if run.service.reviews == nil {
+		return false, fmt.Errorf("subtitle review unavailable")
+	}
+	session, err := run.service.reviews.Get(run.ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	switch session.Status {
+	case "applied":
+		return true, nil
+	case "pending":
+	default:
+		return false, nil
+	}
+	if _, err := run.service.ApplySubtitleReviewSession(run.ctx, dto.ApplySubtitleReviewSessionRequest{SessionID: sessionID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (run *subtitlePipelineRun) exportTrack(task subtitlePipelineTask, step dto.SubtitlePipelineStep) subtitlePipelineTask {
+	file, err := run.service.files.Get(run.ctx, task.FileID)
+	if err != nil {
+		task.Status = "failed"
+		task.Error = err.Error()
+		return task
+	}
+	baseName := sanitizeFileName(strings.TrimSuffix(file.Name, filepath.Ext(file.Name)))
+	if baseName == "" {
+		baseName = file.ID
+	}
+	if task.Language != "" && !strings.Contains(strings.ToLower(baseName), strings.ToLower(task.Language)) {
+		baseName = fmt.Sprintf("%s.%s", baseName, sanitizeFileName(task.Language))
+	}
+	run.exportMu.Lock()
+	exportPath, err := reserveSubtitlePipelineExportPath(step.ExportDirectory, baseName, step.ExportFormat)
+	run.exportMu.Unlock()
+	if err != nil {
+		task.Status = "failed"
+		task.Error = err.Error()
+		return task
+	}
+	result, err := run.service.ExportSubtitle(run.ctx, dto.SubtitleExportRequest{
+		ExportPath:   exportPath,
+		FileID:       file.ID,
+		TargetFormat: step.ExportFormat,
+		ExportConfig: step.ExportConfig,
+	})
+	if err != nil {
+		_ = os.Remove(exportPath)
+		task.Status = "failed"
+		task.Error = err.Error()
+		return task
+	}
+	task.ExportPath = result.ExportPath
+	task.Status = "succeeded"
+	return task
+}
+
+func (run *subtitlePipelineRun) translateRequest(task subtitlePipelineTask, step dto.SubtitlePipelineStep) dto.SubtitleTranslateRequest {
+	request := dto.SubtitleTranslateRequest{}
+	if step.Translate != nil {
+		request = *step.Translate
+	}
+	request.FileID = task.FileID
+	request.DocumentID = ""
+	request.Path = ""
+	request.RootFileID = ""
+	request.LibraryID = run.request.LibraryID
+	request.TargetLanguage = task.Language
+	request.Source = run.request.Source
+	request.SessionKey = run.request.SessionKey
+	request.RunID = run.request.RunID
+	return normalizeSubtitleTranslateRequest(request)
+}
+
+func (run *subtitlePipelineRun) proofreadRequest(task subtitlePipelineTask, step dto.SubtitlePipelineStep) dto.SubtitleProofreadRequest {
+	request := dto.SubtitleProofreadRequest{}
+	if step.Proofread != nil {
+		request = *step.Proofread
+	}
+	request.FileID = task.FileID
+	request.DocumentID = ""
+	request.Path = ""
+	request.RootFileID = ""
+	request.LibraryID = run.request.LibraryID
+	if task.Language != "" {
+		request.Language = task.Language
+	}
+	request.Source = run.request.Source
+	request.SessionKey = run.request.SessionKey
+	request.RunID = run.request.RunID
+	return normalizeSubtitleProofreadRequest(request)
+}
+
+func (run *subtitlePipelineRun) qaReviewRequest(task subtitlePipelineTask, step dto.SubtitlePipelineStep) dto.SubtitleQAReviewRequest {
+	request := dto.SubtitleQAReviewRequest{}
+	if step.QAReview != nil {
+		request = *step.QAReview
+	}
+	request.FileID = task.FileID
+	request.DocumentID = ""
+	request.Path = ""
+	request.LibraryID = run.request.LibraryID
+	request.Source = run.request.Source
+	request.SessionKey = run.request.SessionKey
+	request.RunID = run.request.RunID
+	return normalizeSubtitleQAReviewRequest(request)
+}
+
+func (run *subtitlePipelineRun) acquire() bool {
+	select {
+	case <-run.runCtx.Done():
+		return false
+	case run.slots <- struct{}{}:
+		if run.canceled() {
+			<-run.slots
+			return false
+		}
+		return true
+	}
+}
+
+func (run *subtitlePipelineRun) release() {
+	<-run.slots
+}
+
+func (run *subtitlePipelineRun) canceled() bool {
+	return run.runCtx.Err() != nil || run.service.isSubtitleOperationCanceled(run.ctx, run.operation.ID)
+}
+
+func (run *subtitlePipelineRun) setActive(operationID string, active bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if active {
+		run.active[operationID] = struct{}{}
+		return
+	}
+	delete(run.active, operationID)
+}
+
+// watchCancel forwards a pipeline cancel to the child operations that are running.
+// The run context also ends when the pipeline returns, with no children active.
+func (run *subtitlePipelineRun) watchCancel() {
+	<-run.runCtx.Done()
+	run.mu.Lock()
+	active := make([]string, 0, len(run.active))
+	for operationID := range run.active {
+		active = append(active, operationID)
+	}
+	run.mu.Unlock()
+	for _, operationID := range active {
+		_, _ = run.service.markSubtitleOperationCanceled(run.ctx, operationID)
+		run.service.cancelOperationRun(operationID)
+	}
+}
+
+func (run *subtitlePipelineRun) trackTask(task subtitlePipelineTask) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.storeTaskLocked(task)
+	run.persistLocked()
+}
+
+func (run *subtitlePipelineRun) completeTask(task subtitlePipelineTask) subtitlePipelineTask {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.storeTaskLocked(task)
+	run.doneUnits++
+	switch task.Status {
+	case "succeeded":
+		run.output.CompletedTaskCount++
+	default:
+		run.output.FailedTaskCount++
+	}
+	run.output.Usage = addRuntimeUsage(run.output.Usage, task.Usage)
+	if task.OutputFileID != "" {
+		run.output.OutputFileIDs = append(run.output.OutputFileIDs, task.OutputFileID)
+	}
+	if task.ExportPath != "" {
+		run.output.ExportPaths = append(run.output.ExportPaths, task.ExportPath)
+	}
+	run.persistLocked()
+	return task
+}
+
+func (run *subtitlePipelineRun) skipUnits(count int) {
+	if count <= 0 {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.doneUnits += count
+	run.output.SkippedTaskCount += count
+	run.persistLocked()
+}
+
+func (run *subtitlePipelineRun) storeTaskLocked(task subtitlePipelineTask) {
+	if task.OperationID != "" && !slices.Contains(run.output.ChildOperationIDs, task.OperationID) {
+		run.output.ChildOperationIDs = append(run.output.ChildOperationIDs, task.OperationID)
+	}
+	if index, ok := run.taskIndex[task.Key]; ok {
+		run.output.Tasks[index] = task
+		return
+	}
+	run.taskIndex[task.Key] = len(run.output.Tasks)
+	run.output.Tasks = append(run.output.Tasks, task)
+}
+
+func (run *subtitlePipelineRun) persistLocked() {
+	if run.service.isSubtitleOperationCanceled(run.ctx, run.operation.ID) {
+		return
+	}
+	run.operation.Progress = run.buildProgress(run.service.now())
+	run.operation.OutputJSON = marshalJSON(run.output)
+	_ = run.service.saveAndPublishOperation(run.ctx, run.operation)
+}
+
+func (run *subtitlePipelineRun) buildProgress(now time.Time) *library.OperationProgress {
+	return buildOperationProgress(
+		now,
+		progressText("library.progress.runningPipeline"),
+		run.doneUnits,
+		max(run.totalUnits, 1),
+		progressTextTemplate("library.progressDetail.subtitlePipelineProgress", map[string]string{
+			"current": fmt.Sprintf("%d", run.doneUnits),
+			"total":   fmt.Sprintf("%d", run.totalUnits),
+			"failed":  fmt.Sprintf("%d", run.output.FailedTaskCount),
+		}),
+	)
+}
+
+func (service *LibraryService) finishSubtitlePipelineOperation(ctx context.Context, run *subtitlePipelineRun, files []library.LibraryFile) {
+	run.mu.Lock()
+	operation := run.operation
+	output := run.output
+	run.mu.Unlock()
+
+	processed := append([]library.LibraryFile(nil), files...)
+	outputFiles := make([]library.OperationOutputFile, 0, len(output.OutputFileIDs))
+	for _, fileID := range output.OutputFileIDs {
+		item, err := service.files.Get(ctx, fileID)
+		if err != nil {
+			continue
+		}
+		processed = append(processed, item)
+		outputFiles = append(outputFiles, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+		})
+	}
+	finishedAt := service.now()
+	operation.FinishedAt = &finishedAt
+	operation.OutputFiles = outputFiles
+	operation.Metrics = buildOperationMetricsForOperation(processed, operation.StartedAt, &finishedAt)
+	if output.FailedTaskCount > 0 {
+		output.Status = "failed"
+		operation.Status = library.OperationStatusFailed
+		operation.ErrorCode = "subtitle_pipeline_failed"
+		operation.ErrorMessage = fmt.Sprintf("%d of %d pipeline tasks failed", output.FailedTaskCount, output.TotalTaskCount)
+		operation.Progress = buildOperationProgress(
+			finishedAt,
+			progressText("library.status.failed"),
+			run.totalUnits,
+			max(run.totalUnits, 1),
+			progressTextTemplate("library.progressDetail.subtitlePipelinePartiallyFailed", map[string]string{
+				"failed": fmt.Sprintf("%d", output.FailedTaskCount),
+				"total":  fmt.Sprintf("%d", output.TotalTaskCount),
+			}),
+		)
+	} else {
+		output.Status = "completed"
+		operation.Status = library.OperationStatusSucceeded
+		operation.Progress = buildOperationProgress(
+			finishedAt,
+			progressText("library.status.succeeded"),
+			run.totalUnits,
+			max(run.totalUnits, 1),
+			progressText("library.progressDetail.subtitlePipelineCompleted"),
+		)
+	}
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return
+	}
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   operation.LibraryID,
+		Category:    "operation",
+		Action:      operation.Kind,
+		DisplayName: operation.DisplayName,
+		Status:      string(operation.Status),
+		Source: library.HistoryRecordSource{
+			Kind:  resolveHistorySourceKind(run.request.Source),
+			RunID: run.request.RunID,
+		},
+		Refs:    library.HistoryRecordRefs{OperationID: operation.ID},
+		Files:   operation.OutputFiles,
+		Metrics: operation.Metrics,
+		OperationMeta: &library.OperationRecordMeta{
+			Kind:         operation.Kind,
+			ErrorCode:    operation.ErrorCode,
+			ErrorMessage: operation.ErrorMessage,
+		},
+		OccurredAt: &finishedAt,
+		CreatedAt:  &finishedAt,
+		UpdatedAt:  &finishedAt,
+	})
+	if err == nil && service.histories.Save(ctx, history) == nil {
+		service.publishHistoryUpdate(toHistoryDTO(history))
+	}
+	_ = service.touchLibrary(ctx, operation.LibraryID, finishedAt)
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+func (service *LibraryService) failSubtitlePipelineOperation(ctx context.Context, operation library.LibraryOperation, err error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	request := extractSubtitlePipelineRequest(operation.InputJSON)
+	now := service.now()
+	operation.Status = library.OperationStatusFailed
+	operation.ErrorCode = "subtitle_pipeline_failed"
+	operation.ErrorMessage = strings.TrimSpace(err.Error())
+	operation.FinishedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		0,
+		1,
+		progressText("library.progressDetail.subtitlePipelineFailed"),
+	)
+	operation.OutputJSON = marshalJSON(newSubtitlePipelineOutput(request, "failed", 0))
+	if saveErr := service.operations.Save(ctx, operation); saveErr != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+// isActiveParentChild reports whether an unfinished operation is driven by a
+// pipeline or playlist that is still queued or running; the parent resumes it on recovery.
+func (service *LibraryService) isActiveParentChild(ctx context.Context, operation library.LibraryOperation) bool {
+	parentID := strings.TrimSpace(operation.Correlation.ParentOperationID)
+	if parentID == "" {
+		return false
+	}
+	parent, err := service.operations.Get(ctx, parentID)
+	if err != nil || (parent.Kind != "subtitle_pipeline" && parent.Kind != "download_playlist") {
+		return false
+	}
+	return parent.Status == library.OperationStatusQueued || parent.Status == library.OperationStatusRunning
+}
+
+// resolveLibrarySubtitleFiles resolves explicit file ids, or every subtitle of a
+// library, to available subtitle files that all belong to one library.
+func (service *LibraryService) resolveLibrarySubtitleFiles(ctx context.Context, fileIDs []string, libraryID string) ([]library.LibraryFile, error) {
+	items := make([]library.LibraryFile, 0, len(fileIDs))
+	if len(fileIDs) > 0 {
+		for _, fileID := range fileIDs {
+			item, err := service.files.Get(ctx, fileID)
+			if err != nil {
+				return nil, err
+			}
+			if item.Kind != library.FileKindSubtitle || item.State.Deleted {
+				return nil, fmt.Errorf("file %q is not an available subtitle", item.Name)
+			}
+			items = append(items, item)
+		}
+	} else if libraryID != "" {
+		libraryFiles, err := service.files.ListByLibraryID(ctx, libraryID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range libraryFiles {
+			if item.Kind == library.FileKindSubtitle && !item.State.Deleted {
+				items = append(items, item)
+			}
+		}
+	} else {
+		return nil, fmt.Errorf("fileIds or libraryId is required")
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no subtitle files to process")
+	}
+	resolvedLibraryID := strings.TrimSpace(items[0].LibraryID)
+	if resolvedLibraryID == "" {
+		return nil, fmt.Errorf("source file is not attached to a library")
+	}
+	for _, item := range items {
+		if item.LibraryID != resolvedLibraryID {
+			return nil, fmt.Errorf("subtitles must belong to the same library")
+		}
+	}
+	if libraryID != "" && libraryID != resolvedLibraryID {
+		return nil, fmt.Errorf("subtitles do not belong to library %q", libraryID)
+	}
+	return items, nil
+}
+
+// reserveSubtitlePipelineExportPath picks a free "<name>.<format>" path in the export
+// directory and creates it so concurrent tracks with the same name do not collide.
+func reserveSubtitlePipelineExportPath(directory string, baseName string, format string) (string, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return "", err
+	}
+	for attempt := 0; attempt < 1000; attempt++ {
+		name := fmt.Sprintf("%s.%s", baseName, format)
+		if attempt > 0 {
+			name = fmt.Sprintf("%s (%d).%s", baseName, attempt+1, format)
+		}
+		path := filepath.Join(directory, name)
+		handle, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = handle.Close()
+			return path, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no free export path for %q", baseName)
+}
+
+// countSubtitlePipelineUnits returns how many tasks the steps produce for one input
+// track; translate multiplies the tracks that later steps run on.
+func countSubtitlePipelineUnits(steps []dto.SubtitlePipelineStep) int {
+	units := 0
+	tracks := 1
+	for _, step := range steps {
+		if step.Kind == subtitlePipelineStepTranslate {
+			tracks *= max(len(step.TargetLanguages), 1)
+		}
+		units += tracks
+	}
+	return units
+}
+
+func subtitlePipelineNeedsRuntime(steps []dto.SubtitlePipelineStep) bool {
+	for _, step := range steps {
+		if step.Kind == subtitlePipelineStepTranslate || step.Kind == subtitlePipelineStepProofread {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSubtitlePipelineSteps(steps []dto.SubtitlePipelineStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("pipeline steps are required")
+	}
+	if len(steps) > subtitlePipelineMaxSteps {
+		return fmt.Errorf("pipeline supports at most %d steps", subtitlePipelineMaxSteps)
+	}
+	for index, step := range steps {
+		switch step.Kind {
+		case subtitlePipelineStepProofread, subtitlePipelineStepQAReview:
+		case subtitlePipelineStepTranslate:
+			if len(step.TargetLanguages) == 0 {
+				return fmt.Errorf("step %d: target languages are required", index+1)
+			}
+		case subtitlePipelineStepExport:
+			if step.ExportDirectory == "" {
+				return fmt.Errorf("step %d: export directory is required", index+1)
+			}
+			if !filepath.IsAbs(step.ExportDirectory) {
+				return fmt.Errorf("step %d: export directory must be absolute", index+1)
+			}
+			if !isSubtitleFormat(step.ExportFormat) {
+				return fmt.Errorf("step %d: unsupported export format %q", index+1, step.ExportFormat)
+			}
+		case "":
+			return fmt.Errorf("step %d: kind is required", index+1)
+		default:
+			return fmt.Errorf("step %d: unsupported kind %q", index+1, step.Kind)
+		}
+	}
+	return nil
+}
+
+func normalizeSubtitlePipelineStepKind(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(normalized)
+	switch normalized {
+	case "proofread", "subtitle_proofread":
+		return subtitlePipelineStepProofread
+	case "translate", "translation", "subtitle_translate":
+		return subtitlePipelineStepTranslate
+	case "qa", "qa_review", "qareview", "review", "subtitle_qa_review":
+		return subtitlePipelineStepQAReview
+	case "export", "subtitle_export":
+		return subtitlePipelineStepExport
+	default:
+		return normalized
+	}
+}
+
+func normalizeSubtitlePipelineRequest(request dto.SubtitlePipelineRequest) dto.SubtitlePipelineRequest {
+	request.FileIDs = uniqueTrimmedStrings(request.FileIDs)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.MaxConcurrency <= 0 {
+		request.MaxConcurrency = subtitlePipelineDefaultConcurrency
+	}
+	if request.MaxConcurrency > subtitlePipelineMaxConcurrency {
+		request.MaxConcurrency = subtitlePipelineMaxConcurrency
+	}
+	steps := make([]dto.SubtitlePipelineStep, 0, len(request.Steps))
+	for _, step := range request.Steps {
+		step.Kind = normalizeSubtitlePipelineStepKind(step.Kind)
+		step.TargetLanguages = uniqueTrimmedStrings(step.TargetLanguages)
+		if len(step.TargetLanguages) == 0 && step.Translate != nil && strings.TrimSpace(step.Translate.TargetLanguage) != "" {
+			step.TargetLanguages = []string{strings.TrimSpace(step.Translate.TargetLanguage)}
+		}
+		step.ExportDirectory = strings.TrimSpace(step.ExportDirectory)
+		step.ExportFormat = normalizeSubtitleFormat(step.ExportFormat)
+		if step.Kind == subtitlePipelineStepExport && step.ExportFormat == "" {
+			step.ExportFormat = subtitlePipelineDefaultFormat
+		}
+		steps = append(steps, step)
+	}
+	request.Steps = steps
+	return request
+}
+
+func newSubtitlePipelineOutput(request dto.SubtitlePipelineRequest, status string, totalUnits int) subtitlePipelineOutput {
+	steps := make([]string, 0, len(request.Steps))
+	for _, step := range request.Steps {
+		steps = append(steps, step.Kind)
+	}
+	return subtitlePipelineOutput{
+		FileIDs:        request.FileIDs,
+		Steps:          steps,
+		Status:         status,
+		MaxConcurrency: request.MaxConcurrency,
+		TotalTaskCount: totalUnits,
+	}
+}
+
+func buildSubtitlePipelineDisplayName(files []library.LibraryFile) string {
+	if len(files) == 1 {
+		base := strings.TrimSpace(files[0].Name)
+		if base == "" {
+			base = "Subtitle"
+		}
+		return fmt.Sprintf("%s (Pipeline)", base)
+	}
+	return fmt.Sprintf("%d subtitles (Pipeline)", len(files))
+}
+
+func parseSubtitlePipelineOutput(raw string) (subtitlePipelineOutput, bool) {
+	output := subtitlePipelineOutput{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &output); err != nil {
+		return subtitlePipelineOutput{}, false
+	}
+	return output, true
+}
+
+func extractSubtitlePipelineRequest(inputJSON string) dto.SubtitlePipelineRequest {
+	request := dto.SubtitlePipelineRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitlePipelineRequest{}
+	}
+	return normalizeSubtitlePipelineRequest(request)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+	"dreamcreator/internal/infrastructure/libraryrepo"
+	"dreamcreator/internal/infrastructure/persistence"
+)
+
+func TestNormalizeSubtitlePipelineRequestCountsFanOutUnits(t *testing.T) {
+	t.Parallel()
+
+	request := normalizeSubtitlePipelineRequest(dto.SubtitlePipelineRequest{
+		FileIDs:        []string{" file-1 ", "file-1", "file-2"},
+		MaxConcurrency: 99,
+		Steps: []dto.SubtitlePipelineStep{
+			{Kind: "Proofread"},
+			{Kind: "translate", TargetLanguages: []string{"zh-CN", " ja ", "fr", "ja"}},
+			{Kind: "qa-review"},
+			{Kind: "export", ExportDirectory: "/tmp/exports", ExportFormat: "ASS"},
+		},
+	})
+	if len(request.FileIDs) != 2 || request.MaxConcurrency != subtitlePipelineMaxConcurrency {
+		t.Fatalf("unexpected normalized request %#v", request)
+	}
+	if request.Steps[2].Kind != subtitlePipelineStepQAReview || request.Steps[3].ExportFormat != "ass" {
+		t.Fatalf("unexpected normalized steps %#v", request.Steps)
+	}
+	if err := validateSubtitlePipelineSteps(request.Steps); err != nil {
+		t.Fatalf("validate steps: %v", err)
+	}
+	// proofread once, translate into 3 languages, then QA and export each translation.
+	if units := countSubtitlePipelineUnits(request.Steps); units != 1+3+3+3 {
+		t.Fatalf("expected 10 units per file, got %d", units)
+	}
+	if units := countSubtitlePipelineUnits(request.Steps[2:]); units != 2 {
+		t.Fatalf("expected 2 units for a single track tail, got %d", units)
+	}
+
+	invalid := normalizeSubtitlePipelineRequest(dto.SubtitlePipelineRequest{Steps: []dto.SubtitlePipelineStep{{Kind: "translate"}}})
+	if err := validateSubtitlePipelineSteps(invalid.Steps); err == nil {
+		t.Fatalf("expected translate without target languages to be rejected")
+	}
+	invalid = normalizeSubtitlePipelineRequest(dto.SubtitlePipelineRequest{Steps: []dto.SubtitlePipelineStep{{Kind: "export", ExportDirectory: "relative"}}})
+	if err := validateSubtitlePipelineSteps(invalid.Steps); err == nil {
+		t.Fatalf("expected a relative export directory to be rejected")
+	}
+}
+
+func TestSubtitlePipelineExportsEveryFileWithSQLiteRepos(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 4, 3, 9, 0, 0, 0, time.UTC)
+	db, err := persistence.OpenSQLite(ctx, persistence.SQLiteConfig{
+		Path: filepath.Join(t.TempDir(), "library-subtitle-pipeline.db"),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	libraries := libraryrepo.NewSQLiteLibraryRepository(db.Bun)
+	files := libraryrepo.NewSQLiteFileRepository(db.Bun)
+	subtitles := libraryrepo.NewSQLiteSubtitleDocumentRepository(db.Bun)
+	operations := libraryrepo.NewSQLiteOperationRepository(db.Bun)
+	histories := libraryrepo.NewSQLiteHistoryRepository(db.Bun)
+
+	libraryItem := mustNewLibrary(t, "lib-1", now)
+	if err := libraries.Save(ctx, libraryItem); err != nil {
+		t.Fatalf("save library: %v", err)
+	}
+	content := "1\n00:00:01,000 --> 00:00:02,000\nhello\n"
+	for _, fileID := range []string{"file-1", "file-2"} {
+		fileItem := mustNewSubtitleFile(t, fileID, libraryItem.ID, "op-1", "/tmp/"+fileID+"/episode.srt", "doc-"+fileID, now)
+		if err := files.Save(ctx, fileItem); err != nil {
+			t.Fatalf("save file: %v", err)
+		}
+		documentItem, err := library.NewSubtitleDocument(library.SubtitleDocumentParams{
+			ID:              fileItem.Storage.DocumentID,
+			FileID:          fileItem.ID,
+			LibraryID:       fileItem.LibraryID,
+			Format:          "srt",
+			OriginalContent: content,
+			WorkingContent:  content,
+			CreatedAt:       &now,
+			UpdatedAt:       &now,
+		})
+		if err != nil {
+			t.Fatalf("new subtitle document: %v", err)
+		}
+		if err := subtitles.Save(ctx, documentItem); err != nil {
+			t.Fatalf("save subtitle document: %v", err)
+		}
+	}
+
+	service := &LibraryService{
+		libraries:  libraries,
+		files:      files,
+		operations: operations,
+		histories:  histories,
+		workspace:  &deleteRuleWorkspaceRepo{},
+		fileEvents: &deleteRuleFileEventRepo{},
+		subtitles:  subtitles,
+		runCancels: make(map[string]context.CancelFunc),
+		nowFunc:    func() time.Time { return now },
+	}
+
+	exportDir := filepath.Join(t.TempDir(), "exports")
+	created, err := service.CreateSubtitlePipelineJob(ctx, dto.SubtitlePipelineRequest{
+		LibraryID: libraryItem.ID,
+		Steps:     []dto.SubtitlePipelineStep{{Kind: "export", ExportDirectory: exportDir, ExportFormat: "vtt"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateSubtitlePipelineJob: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	var operation library.LibraryOperation
+	for {
+		operation, err = operations.Get(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("get operation: %v", err)
+		}
+		if operation.Status == library.OperationStatusSucceeded || operation.Status == library.OperationStatusFailed {
+			if records, _ := histories.ListByLibraryID(ctx, libraryItem.ID); len(records) > 0 {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pipeline did not finish, status %q", operation.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if operation.Status != library.OperationStatusSucceeded {
+		t.Fatalf("expected pipeline to succeed, got %q: %s", operation.Status, operation.ErrorMessage)
+	}
+	if operation.Progress == nil || progressCurrent(operation.Progress) != 2 || progressTotal(operation.Progress) != 2 {
+		t.Fatalf("expected 2/2 progress, got %#v", operation.Progress)
+	}
+	if operation.Metrics.FileCount != 2 {
+		t.Fatalf("expected metrics over both files, got %#v", operation.Metrics)
+	}
+	output, ok := parseSubtitlePipelineOutput(operation.OutputJSON)
+	if !ok || output.Status != "completed" || output.CompletedTaskCount != 2 || len(output.ExportPaths) != 2 {
+		t.Fatalf("unexpected pipeline output %#v", output)
+	}
+	entries, err := os.ReadDir(exportDir)
+	if err != nil {
+		t.Fatalf("read export dir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected both same-named subtitles to be exported side by side, got %d files", len(entries))
+	}
+	exported, err := os.ReadFile(output.ExportPaths[0])
+	if err != nil || len(exported) == 0 || string(exported[:6]) != "WEBVTT" {
+		t.Fatalf("expected a VTT export, got %q %v", exported, err)
+	}
+}
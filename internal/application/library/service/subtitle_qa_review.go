@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
@@ -27,18 +28,31 @@ type subtitleQAReviewOutput struct {
 
 func (service *LibraryService) CreateSubtitleQAReviewJob(ctx context.Context, request dto.SubtitleQAReviewRequest) (dto.LibraryOperationDTO, error) {
 	request = normalizeSubtitleQAReviewRequest(request)
-	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	operation, err := service.queueSubtitleQAReviewOperation(ctx, request, library.OperationCorrelation{RunID: request.RunID})
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
+	go service.runSubtitleQAReviewOperation(context.Background(), operation, request)
+	return toOperationDTO(operation), nil
+}
+
+func (service *LibraryService) queueSubtitleQAReviewOperation(
+	ctx context.Context,
+	request dto.SubtitleQAReviewRequest,
+	correlation library.OperationCorrelation,
+) (library.LibraryOperation, error) {
+	sourceFile, _, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		return library.LibraryOperation{}, err
+	}
 	if strings.TrimSpace(sourceFile.LibraryID) == "" {
-		return dto.LibraryOperationDTO{}, fmt.Errorf("source file is not attached to a library")
+		return library.LibraryOperation{}, fmt.Errorf("source file is not attached to a library")
 	}
 	now := service.now()
 	operationID := uuid.NewString()
 	inputJSON, err := json.Marshal(request)
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
 		ID:          operationID,
@@ -46,7 +60,7 @@ func (service *LibraryService) CreateSubtitleQAReviewJob(ctx context.Context, re
 		Kind:        "subtitle_qa_review",
 		Status:      string(library.OperationStatusQueued),
 		DisplayName: buildSubtitleQAReviewOutputName(sourceFile.Name),
-		Correlation: library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID)},
+		Correlation: correlation,
 		InputJSON:   string(inputJSON),
 		OutputJSON:  marshalJSON(subtitleQAReviewOutput{Status: "queued"}),
 		Progress: buildOperationProgress(
@@ -59,15 +73,13 @@ func (service *LibraryService) CreateSubtitleQAReviewJob(ctx context.Context, re
 		CreatedAt: &now,
 	})
 	if err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
 	if err := service.operations.Save(ctx, operation); err != nil {
-		return dto.LibraryOperationDTO{}, err
+		return library.LibraryOperation{}, err
 	}
-	operationDTO := toOperationDTO(operation)
-	service.publishOperationUpdate(operationDTO)
-	go service.runSubtitleQAReviewOperation(context.Background(), operation, request)
-	return operationDTO, nil
+	service.publishOperationUpdate(toOperationDTO(operation))
+	return operation, nil
 }
 
 func (service *LibraryService) runSubtitleQAReviewOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleQAReviewRequest) {
@@ -119,6 +131,24 @@ func (service *LibraryService) runSubtitleQAReviewOperation(ctx context.Context,
 	changeCount = changes
 	validationResult := validateSubtitleDocument(sourceDocument)
 	suggestions := buildSubtitleQAReviewSuggestions(sourceDocument, candidateDocument)
+	if request.DeliveryProfileID != "" || request.DeliveryAutoFix {
+		profile, err := service.resolveSubtitleDeliveryProfile(ctx, request.DeliveryProfileID, nil)
+		if err != nil {
+			service.failSubtitleQAReviewOperation(ctx, operation, err)
+			return
+		}
+		deliveryIssues := validateSubtitleDeliveryCues(sourceDocument, profile)
+		validationResult.IssueCount += len(deliveryIssues)
+		if request.DeliveryAutoFix {
+			fixes := fixSubtitleDeliveryCues(candidateDocument.Cues, profile)
+			candidateDocument.Cues = subtitleDeliveryPreviewCues(fixes)
+			suggestions = mergeSubtitleReviewSuggestions(
+				suggestions,
+				buildSubtitleDeliverySuggestions(sourceDocument.Cues, fixes, profile, deliveryIssues),
+			)
+			changeCount = len(suggestions)
+		}
+	}
 	finishedAt := service.now()
 
 	var sessionID string
@@ -251,6 +281,29 @@ func buildSubtitleQAReviewSuggestions(sourceDocument dto.SubtitleDocument, candi
 	return result
 }
 
+// mergeSubtitleReviewSuggestions keeps one suggestion per cue, preferring the
+// override because it was built on top of the base candidate text.
+func mergeSubtitleReviewSuggestions(base []library.SubtitleReviewSuggestion, override []library.SubtitleReviewSuggestion) []library.SubtitleReviewSuggestion {
+	if len(override) == 0 {
+		return base
+	}
+	overrideByIndex := make(map[int]library.SubtitleReviewSuggestion, len(override))
+	for _, item := range override {
+		overrideByIndex[item.CueIndex] = item
+	}
+	result := make([]library.SubtitleReviewSuggestion, 0, len(base)+len(override))
+	for _, item := range base {
+		if _, exists := overrideByIndex[item.CueIndex]; !exists {
+			result = append(result, item)
+		}
+	}
+	result = append(result, override...)
+	sort.SliceStable(result, func(left, right int) bool {
+		return result[left].CueIndex < result[right].CueIndex
+	})
+	return result
+}
+
 func (service *LibraryService) failSubtitleQAReviewOperation(ctx context.Context, operation library.LibraryOperation, err error) {
 	if service == nil || service.operations == nil {
 		return
@@ -291,6 +344,7 @@ func normalizeSubtitleQAReviewRequest(request dto.SubtitleQAReviewRequest) dto.S
 	request.Path = strings.TrimSpace(request.Path)
 	request.LibraryID = strings.TrimSpace(request.LibraryID)
 	request.OutputFormat = strings.TrimSpace(request.OutputFormat)
+	request.DeliveryProfileID = strings.TrimSpace(request.DeliveryProfileID)
 	request.Source = strings.TrimSpace(request.Source)
 	request.SessionKey = strings.TrimSpace(request.SessionKey)
 	request.RunID = strings.TrimSpace(request.RunID)
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+const (
+	subtitleRetimeModeOffset    = "offset"
+	subtitleRetimeModeStretch   = "stretch"
+	subtitleRetimeModeFramerate = "framerate"
+	subtitleRetimeModeRanges    = "ranges"
+)
+
+var subtitleRetimeASSTimestampPattern = regexp.MustCompile(`^\d:\d{2}:\d{2}\.\d{2}$`)
+
+func (service *LibraryService) RetimeSubtitle(ctx context.Context, request dto.SubtitleRetimeRequest) (dto.SubtitleRetimeResult, error) {
+	content, format, fileItem, documentItem, err := service.resolveSubtitleContent(ctx, request.FileID, request.DocumentID, request.Path, request.Content, request.Format)
+	if err != nil && request.Document == nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	document := dto.SubtitleDocument{}
+	if request.Document != nil {
+		document = *request.Document
+		format = detectSubtitleFormat(request.Format, "", firstNonEmpty(document.Format, format))
+		document.Format = format
+	} else {
+		document = parseSubtitleDocument(content, format)
+	}
+	if len(document.Cues) == 0 {
+		return dto.SubtitleRetimeResult{}, fmt.Errorf("subtitle has no cues to retime")
+	}
+	cues, changed, err := retimeSubtitleCues(document.Cues, request)
+	if err != nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	document.Cues = cues
+	originalContent := firstNonEmpty(subtitleDocumentSourceContent(document), content)
+	retimedContent := renderSubtitleContent(document, format)
+	if preserved, ok := renderSubtitleContentPreservingSource(document, format, nil, "", originalContent); ok {
+		retimedContent = preserved
+	}
+	result := dto.SubtitleRetimeResult{
+		FileID:          subtitleResultFileID(fileItem),
+		Format:          format,
+		Content:         retimedContent,
+		CueCount:        len(cues),
+		ChangedCueCount: changed,
+		Document:        subtitleDocumentWithSource(retimedContent, format, cues, document.Metadata),
+	}
+	if request.DryRun || fileItem == nil || documentItem == nil || changed == 0 {
+		return result, nil
+	}
+	sourceRevision, err := service.createSubtitleRevision(ctx, *fileItem, documentItem.Format, content, "snapshot", "", "")
+	if err != nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	documentItem.Format = format
+	documentItem.WorkingContent = retimedContent
+	documentItem.UpdatedAt = service.now()
+	if err := service.subtitles.Save(ctx, *documentItem); err != nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	fileItem.UpdatedAt = service.now()
+	if fileItem.Media == nil {
+		fileItem.Media = libraryMediaInfo(format, int64(len(retimedContent)))
+	} else {
+		fileItem.Media.Format = format
+		sizeValue := int64(len(retimedContent))
+		fileItem.Media.SizeBytes = &sizeValue
+	}
+	if err := service.files.Save(ctx, *fileItem); err != nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	revision, err := service.createSubtitleRevision(ctx, *fileItem, format, retimedContent, "retime", "", "")
+	if err != nil {
+		return dto.SubtitleRetimeResult{}, err
+	}
+	result.SourceRevisionID = sourceRevision.ID
+	result.RevisionID = revision.ID
+	_ = service.touchLibrary(ctx, fileItem.LibraryID, service.now())
+	service.publishFileUpdate(service.mustBuildFileDTO(ctx, *fileItem))
+	service.publishWorkspaceProjectUpdate(fileItem.LibraryID)
+	return result, nil
+}
+
+// retimeSubtitleCues applies the requested timing transform and returns the
+// shifted cues together with the number of cues whose timing changed.
+func retimeSubtitleCues(cues []dto.SubtitleCue, request dto.SubtitleRetimeRequest) ([]dto.SubtitleCue, int, error) {
+	mapTime, err := buildSubtitleRetimeMapper(cues, request)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := make([]dto.SubtitleCue, 0, len(cues))
+	changed := 0
+	for _, cue := range cues {
+		startMs := cueStartMS(cue)
+		endMs := cueEndMS(cue)
+		nextStart := maxInt64(0, mapTime(startMs, startMs))
+		nextEnd := maxInt64(nextStart, mapTime(endMs, startMs))
+		next := cue
+		if nextStart != startMs || nextEnd != endMs {
+			next.Start = formatSubtitleRetimeTimestamp(cue.Start, nextStart)
+			next.End = formatSubtitleRetimeTimestamp(cue.End, nextEnd)
+			changed++
+		}
+		result = append(result, next)
+	}
+	return result, changed, nil
+}
+
+// buildSubtitleRetimeMapper returns a function mapping a timestamp to its new
+// position. cueStartMs is passed so range shifts move whole cues together.
+func buildSubtitleRetimeMapper(cues []dto.SubtitleCue, request dto.SubtitleRetimeRequest) (func(valueMs int64, cueStartMs int64) int64, error) {
+	switch strings.ToLower(strings.TrimSpace(request.Mode)) {
+	case subtitleRetimeModeOffset:
+		if request.OffsetMs == 0 {
+			return nil, fmt.Errorf("offsetMs is required for offset retiming")
+		}
+		offset := request.OffsetMs
+		return func(valueMs int64, _ int64) int64 {
+			return valueMs + offset
+		}, nil
+	case subtitleRetimeModeStretch:
+		if len(request.Anchors) != 2 {
+			return nil, fmt.Errorf("stretch retiming requires exactly two anchors")
+		}
+		firstSource, err := resolveSubtitleRetimeAnchorSource(cues, request.Anchors[0])
+		if err != nil {
+			return nil, err
+		}
+		secondSource, err := resolveSubtitleRetimeAnchorSource(cues, request.Anchors[1])
+		if err != nil {
+			return nil, err
+		}
+		if firstSource == secondSource {
+			return nil, fmt.Errorf("stretch anchors must reference different source times")
+		}
+		firstTarget := request.Anchors[0].TargetMs
+		scale := float64(request.Anchors[1].TargetMs-firstTarget) / float64(secondSource-firstSource)
+		if scale <= 0 {
+			return nil, fmt.Errorf("stretch anchors must keep cue order")
+		}
+		return func(valueMs int64, _ int64) int64 {
+			return firstTarget + int64(math.Round(float64(valueMs-firstSource)*scale))
+		}, nil
+	case subtitleRetimeModeFramerate:
+		fromFPS := normalizeSubtitleRetimeFPS(request.FromFPS)
+		toFPS := normalizeSubtitleRetimeFPS(request.ToFPS)
+		if fromFPS <= 0 || toFPS <= 0 {
+			return nil, fmt.Errorf("fromFps and toFps are required for framerate retiming")
+		}
+		scale := fromFPS / toFPS
+		return func(valueMs int64, _ int64) int64 {
+			return int64(math.Round(float64(valueMs) * scale))
+		}, nil
+	case subtitleRetimeModeRanges:
+		if len(request.Ranges) == 0 {
+			return nil, fmt.Errorf("ranges are required for range retiming")
+		}
+		for _, item := range request.Ranges {
+			if item.StartMs < 0 || (item.EndMs > 0 && item.EndMs <= item.StartMs) {
+				return nil, fmt.Errorf("invalid retime range %d-%d", item.StartMs, item.EndMs)
+			}
+		}
+		ranges := append([]dto.SubtitleRetimeRange(nil), request.Ranges...)
+		return func(valueMs int64, cueStartMs int64) int64 {
+			for _, item := range ranges {
+				if cueStartMs >= item.StartMs && (item.EndMs <= 0 || cueStartMs < item.EndMs) {
+					return valueMs + item.OffsetMs
+				}
+			}
+			return valueMs
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported retime mode: %s", request.Mode)
+	}
+}
+
+func resolveSubtitleRetimeAnchorSource(cues []dto.SubtitleCue, anchor dto.SubtitleRetimeAnchor) (int64, error) {
+	if anchor.CueIndex <= 0 {
+		return anchor.SourceMs, nil
+	}
+	for _, cue := range cues {
+		if cue.Index == anchor.CueIndex {
+			return cueStartMS(cue), nil
+		}
+	}
+	return 0, fmt.Errorf("anchor cue %d not found", anchor.CueIndex)
+}
+
+// normalizeSubtitleRetimeFPS expands the rounded NTSC rates to their exact
+// 1000/1001 values so long files do not drift after conversion.
+func normalizeSubtitleRetimeFPS(value float64) float64 {
+	for _, base := range []float64{24, 30, 60} {
+		exact := base * 1000 / 1001
+		if math.Abs(value-exact) < 0.01 {
+			return exact
+		}
+	}
+	return value
+}
+
+// formatSubtitleRetimeTimestamp writes ms in the same notation as the original
+// cue timestamp so source-preserving renderers keep the file's style.
+func formatSubtitleRetimeTimestamp(reference string, ms int64) string {
+	trimmed := strings.TrimSpace(reference)
+	switch {
+	case strings.HasSuffix(trimmed, "s") && strings.Contains(trimmed, "/"):
+		return formatFCPXMLDuration(ms)
+	case strings.HasSuffix(trimmed, "s"):
+		return strconv.FormatFloat(float64(maxInt64(0, ms))/1000, 'f', 3, 64) + "s"
+	case subtitleRetimeASSTimestampPattern.MatchString(trimmed):
+		return formatASSTimestamp(ms)
+	case strings.Contains(trimmed, "."):
+		return formatVTTTimestamp(ms)
+	default:
+		return formatSRTTimestamp(ms)
+	}
+}
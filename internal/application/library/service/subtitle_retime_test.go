@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+const subtitleRetimeTestSRT = "1\n00:00:01,000 --> 00:00:02,000\nFirst\n\n2\n00:00:10,000 --> 00:00:12,000\nSecond\n\n3\n00:01:00,000 --> 00:01:02,500\nThird\n"
+
+func TestLibraryServiceRetimeSubtitleModes(t *testing.T) {
+	t.Parallel()
+
+	service := &LibraryService{}
+	cases := []struct {
+		name    string
+		request dto.SubtitleRetimeRequest
+		starts  []string
+		ends    []string
+		changed int
+	}{
+		{
+			name:    "offset clamps at zero",
+			request: dto.SubtitleRetimeRequest{Mode: "offset", OffsetMs: -1500},
+			starts:  []string{"00:00:00,000", "00:00:08,500", "00:00:58,500"},
+			ends:    []string{"00:00:00,500", "00:00:10,500", "00:01:01,000"},
+			changed: 3,
+		},
+		{
+			name: "two point stretch",
+			request: dto.SubtitleRetimeRequest{Mode: "stretch", Anchors: []dto.SubtitleRetimeAnchor{
+				{CueIndex: 1, TargetMs: 2000},
+				{SourceMs: 60000, TargetMs: 120000},
+			}},
+			starts:  []string{"00:00:02,000", "00:00:20,000", "00:02:00,000"},
+			ends:    []string{"00:00:04,000", "00:00:24,000", "00:02:05,000"},
+			changed: 3,
+		},
+		{
+			name:    "ntsc film to pal",
+			request: dto.SubtitleRetimeRequest{Mode: "framerate", FromFPS: 23.976, ToFPS: 25},
+			starts:  []string{"00:00:00,959", "00:00:09,590", "00:00:57,542"},
+			ends:    []string{"00:00:01,918", "00:00:11,508", "00:00:59,940"},
+			changed: 3,
+		},
+		{
+			name: "per range shifts",
+			request: dto.SubtitleRetimeRequest{Mode: "ranges", Ranges: []dto.SubtitleRetimeRange{
+				{StartMs: 5000, EndMs: 30000, OffsetMs: 250},
+				{StartMs: 30000, OffsetMs: -1000},
+			}},
+			starts:  []string{"00:00:01,000", "00:00:10,250", "00:00:59,000"},
+			ends:    []string{"00:00:02,000", "00:00:12,250", "00:01:01,500"},
+			changed: 2,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := tc.request
+			request.Content = subtitleRetimeTestSRT
+			request.Format = "srt"
+			result, err := service.RetimeSubtitle(context.Background(), request)
+			if err != nil {
+				t.Fatalf("retime failed: %v", err)
+			}
+			if result.ChangedCueCount != tc.changed {
+				t.Fatalf("expected %d changed cues, got %d", tc.changed, result.ChangedCueCount)
+			}
+			cues := parseSubtitleDocument(result.Content, "srt").Cues
+			if len(cues) != len(tc.starts) {
+				t.Fatalf("expected %d cues, got %d", len(tc.starts), len(cues))
+			}
+			for index, cue := range cues {
+				if cue.Start != tc.starts[index] || cue.End != tc.ends[index] {
+					t.Fatalf("cue %d: expected %s --> %s, got %s --> %s", index+1, tc.starts[index], tc.ends[index], cue.Start, cue.End)
+				}
+			}
+		})
+	}
+}
+
+func TestLibraryServiceRetimeSubtitlePreservesASSTimestamps(t *testing.T) {
+	t.Parallel()
+
+	source := strings.Join([]string{
+		"[Script Info]",
+		"ScriptType: v4.00+",
+		"",
+		"[Events]",
+		"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text",
+		"Dialogue: 0,0:00:01.00,0:00:02.50,Sign,,0,0,0,,Hello",
+	}, "\n")
+	result, err := (&LibraryService{}).RetimeSubtitle(context.Background(), dto.SubtitleRetimeRequest{
+		Content:  source,
+		Format:   "ass",
+		Mode:     "offset",
+		OffsetMs: 500,
+	})
+	if err != nil {
+		t.Fatalf("retime failed: %v", err)
+	}
+	if !strings.Contains(result.Content, "Dialogue: 0,0:00:01.50,0:00:03.00,Sign,,0,0,0,,Hello") {
+		t.Fatalf("expected ass dialogue to keep style and shift timing, got:\n%s", result.Content)
+	}
+}
+
+func TestLibraryServiceRetimeSubtitleRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	service := &LibraryService{}
+	for _, request := range []dto.SubtitleRetimeRequest{
+		{Mode: "offset"},
+		{Mode: "stretch", Anchors: []dto.SubtitleRetimeAnchor{{SourceMs: 1000, TargetMs: 0}}},
+		{Mode: "stretch", Anchors: []dto.SubtitleRetimeAnchor{{SourceMs: 1000, TargetMs: 5000}, {SourceMs: 2000, TargetMs: 1000}}},
+		{Mode: "framerate", FromFPS: 25},
+		{Mode: "ranges", Ranges: []dto.SubtitleRetimeRange{{StartMs: 5000, EndMs: 1000, OffsetMs: 100}}},
+		{Mode: "warp"},
+	} {
+		request.Content = subtitleRetimeTestSRT
+		request.Format = "srt"
+		if _, err := service.RetimeSubtitle(context.Background(), request); err == nil {
+			t.Fatalf("expected %+v to be rejected", request)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleRevisionOriginalID = "original"
+	subtitleRevisionWorkingID  = "working"
+
+	// subtitleRevisionDiffMaxCells bounds the LCS table used to align cues; larger
+	// edits fall back to pairing cues by timing.
+	subtitleRevisionDiffMaxCells = 4_000_000
+)
+
+func (service *LibraryService) ListSubtitleRevisions(ctx context.Context, request dto.ListSubtitleRevisionsRequest) ([]dto.SubtitleRevisionDTO, error) {
+	if service == nil || service.revisions == nil || service.subtitles == nil {
+		return nil, fmt.Errorf("subtitle revisions unavailable")
+	}
+	file, document, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, "")
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := service.revisions.ListByFileID(ctx, file.ID, request.Limit)
+	if err != nil {
+		return nil, err
+	}
+	workingContent := subtitleDocumentWorkingContent(document)
+	operations := make(map[string]*library.LibraryOperation)
+	currentFound := false
+	result := make([]dto.SubtitleRevisionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		item := dto.SubtitleRevisionDTO{
+			ID:              revision.ID,
+			LibraryID:       revision.LibraryID,
+			FileID:          revision.FileID,
+			Format:          revision.Format,
+			SourceKind:      revision.SourceKind,
+			Actor:           resolveSubtitleRevisionActor(revision),
+			Candidate:       isSubtitleCandidateRevision(revision.SourceKind),
+			OperationID:     revision.SourceOperation,
+			ReviewSessionID: revision.ReviewSessionID,
+			CueCount:        len(parseSubtitleDocument(revision.Content, detectSubtitleFormat(revision.Format, file.Storage.LocalPath, revision.Format)).Cues),
+			Bytes:           len(revision.Content),
+			CreatedAt:       revision.CreatedAt.Format(time.RFC3339),
+		}
+		if !currentFound && !item.Candidate && revision.Content == workingContent {
+			item.Current = true
+			currentFound = true
+		}
+		if operation := service.lookupSubtitleRevisionOperation(ctx, operations, revision.SourceOperation); operation != nil {
+			item.OperationKind = operation.Kind
+			item.OperationName = operation.DisplayName
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (service *LibraryService) DiffSubtitleRevisions(ctx context.Context, request dto.DiffSubtitleRevisionsRequest) (dto.SubtitleRevisionDiffResult, error) {
+	if service == nil || service.revisions == nil || service.subtitles == nil {
+		return dto.SubtitleRevisionDiffResult{}, fmt.Errorf("subtitle revisions unavailable")
+	}
+	fromID := strings.TrimSpace(request.FromRevisionID)
+	if fromID == "" {
+		return dto.SubtitleRevisionDiffResult{}, fmt.Errorf("fromRevisionId is required")
+	}
+	toID := strings.TrimSpace(request.ToRevisionID)
+	if toID == "" {
+		toID = subtitleRevisionWorkingID
+	}
+	fileID := strings.TrimSpace(request.FileID)
+	revisions := make(map[string]library.SubtitleRevision, 2)
+	for _, id := range []string{fromID, toID} {
+		if id == subtitleRevisionOriginalID || id == subtitleRevisionWorkingID {
+			continue
+		}
+		revision, err := service.revisions.Get(ctx, id)
+		if err != nil {
+			return dto.SubtitleRevisionDiffResult{}, err
+		}
+		if fileID == "" {
+			fileID = revision.FileID
+		}
+		if revision.FileID != fileID {
+			return dto.SubtitleRevisionDiffResult{}, fmt.Errorf("revision %s does not belong to file %s", id, fileID)
+		}
+		revisions[id] = revision
+	}
+	if fileID == "" {
+		return dto.SubtitleRevisionDiffResult{}, fmt.Errorf("fileId is required")
+	}
+	file, document, err := service.resolveSubtitleFileAndDocument(ctx, fileID, "", "")
+	if err != nil {
+		return dto.SubtitleRevisionDiffResult{}, err
+	}
+	resolveCues := func(id string) []dto.SubtitleCue {
+		format, content := document.Format, ""
+		switch id {
+		case subtitleRevisionOriginalID:
+			content = document.OriginalContent
+		case subtitleRevisionWorkingID:
+			content = subtitleDocumentWorkingContent(document)
+		default:
+			format, content = revisions[id].Format, revisions[id].Content
+		}
+		return parseSubtitleDocument(content, detectSubtitleFormat(format, file.Storage.LocalPath, format)).Cues
+	}
+	entries := diffSubtitleCues(resolveCues(fromID), resolveCues(toID))
+	result := dto.SubtitleRevisionDiffResult{
+		FileID:         file.ID,
+		FromRevisionID: fromID,
+		ToRevisionID:   toID,
+		Cues:           make([]dto.SubtitleRevisionDiffCueDTO, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		switch entry.Status {
+		case "added":
+			result.AddedCount++
+		case "removed":
+			result.RemovedCount++
+		case "modified":
+			result.ModifiedCount++
+		default:
+			result.UnchangedCount++
+			if !request.IncludeUnchanged {
+				continue
+			}
+		}
+		result.Cues = append(result.Cues, entry)
+	}
+	return result, nil
+}
+
+func (service *LibraryService) RestoreSubtitleRevision(ctx context.Context, request dto.RestoreSubtitleRevisionRequest) (dto.RestoreSubtitleRevisionResult, error) {
+	if service == nil || service.revisions == nil || service.subtitles == nil {
+		return dto.RestoreSubtitleRevisionResult{}, fmt.Errorf("subtitle revisions unavailable")
+	}
+	revision, err := service.revisions.Get(ctx, strings.TrimSpace(request.RevisionID))
+	if err != nil {
+		return dto.RestoreSubtitleRevisionResult{}, err
+	}
+	if fileID := strings.TrimSpace(request.FileID); fileID != "" && fileID != revision.FileID {
+		return dto.RestoreSubtitleRevisionResult{}, fmt.Errorf("revision %s does not belong to file %s", revision.ID, fileID)
+	}
+	file, document, err := service.resolveSubtitleFileAndDocument(ctx, revision.FileID, "", "")
+	if err != nil {
+		return dto.RestoreSubtitleRevisionResult{}, err
+	}
+	sourceKind := "restore"
+	if strings.TrimSpace(request.SessionKey) != "" || strings.TrimSpace(request.RunID) != "" {
+		sourceKind = "agent_edit"
+	}
+	format := detectSubtitleFormat(revision.Format, file.Storage.LocalPath, document.Format)
+	snapshot, restored, err := service.replaceSubtitleWorkingContent(ctx, &file, &document, format, revision.Content, sourceKind)
+	if err != nil {
+		return dto.RestoreSubtitleRevisionResult{}, err
+	}
+	_ = service.touchLibrary(ctx, file.LibraryID, service.now())
+	service.publishFileUpdate(service.mustBuildFileDTO(ctx, file))
+	service.publishWorkspaceProjectUpdate(file.LibraryID)
+	return dto.RestoreSubtitleRevisionResult{
+		FileID:             file.ID,
+		RestoredRevisionID: revision.ID,
+		RevisionID:         restored.ID,
+		SnapshotRevisionID: snapshot.ID,
+		Format:             format,
+		Bytes:              len(revision.Content),
+	}, nil
+}
+
+// replaceSubtitleWorkingContent writes new working content and records a snapshot
+// of the replaced content plus a revision of the new one. The snapshot is skipped
+// when the latest revision already holds the replaced content.
+func (service *LibraryService) replaceSubtitleWorkingContent(
+	ctx context.Context,
+	file *library.LibraryFile,
+	document *library.SubtitleDocument,
+	format string,
+	content string,
+	sourceKind string,
+) (library.SubtitleRevision, library.SubtitleRevision, error) {
+	previousFormat := document.Format
+	previousContent := subtitleDocumentWorkingContent(*document)
+	document.Format = format
+	document.WorkingContent = content
+	document.UpdatedAt = service.now()
+	if err := service.subtitles.Save(ctx, *document); err != nil {
+		return library.SubtitleRevision{}, library.SubtitleRevision{}, err
+	}
+	file.UpdatedAt = service.now()
+	if file.Media == nil {
+		file.Media = libraryMediaInfo(format, int64(len(content)))
+	} else {
+		file.Media.Format = format
+		sizeValue := int64(len(content))
+		file.Media.SizeBytes = &sizeValue
+	}
+	if err := service.files.Save(ctx, *file); err != nil {
+		return library.SubtitleRevision{}, library.SubtitleRevision{}, err
+	}
+	return service.recordSubtitleEditRevisions(ctx, *file, previousFormat, previousContent, format, content, sourceKind)
+}
+
+func (service *LibraryService) recordSubtitleEditRevisions(
+	ctx context.Context,
+	file library.LibraryFile,
+	previousFormat string,
+	previousContent string,
+	format string,
+	content string,
+	sourceKind string,
+) (library.SubtitleRevision, library.SubtitleRevision, error) {
+	if service == nil || service.revisions == nil || previousContent == content {
+		return library.SubtitleRevision{}, library.SubtitleRevision{}, nil
+	}
+	snapshot := library.SubtitleRevision{}
+	latest, err := service.revisions.ListByFileID(ctx, file.ID, 1)
+	if err != nil {
+		return library.SubtitleRevision{}, library.SubtitleRevision{}, err
+	}
+	if strings.TrimSpace(previousContent) != "" && (len(latest) == 0 || latest[0].Content != previousContent) {
+		snapshot, err = service.createSubtitleRevision(ctx, file, previousFormat, previousContent, "snapshot", "", "")
+		if err != nil {
+			return library.SubtitleRevision{}, library.SubtitleRevision{}, err
+		}
+	}
+	revision, err := service.createSubtitleRevision(ctx, file, format, content, sourceKind, "", "")
+	if err != nil {
+		return library.SubtitleRevision{}, library.SubtitleRevision{}, err
+	}
+	return snapshot, revision, nil
+}
+
+func (service *LibraryService) lookupSubtitleRevisionOperation(
+	ctx context.Context,
+	cache map[string]*library.LibraryOperation,
+	operationID string,
+) *library.LibraryOperation {
+	operationID = strings.TrimSpace(operationID)
+	if operationID == "" || service.operations == nil {
+		return nil
+	}
+	if operation, ok := cache[operationID]; ok {
+		return operation
+	}
+	var found *library.LibraryOperation
+	if operation, err := service.operations.Get(ctx, operationID); err == nil {
+		found = &operation
+	}
+	cache[operationID] = found
+	return found
+}
+
+func subtitleDocumentWorkingContent(document library.SubtitleDocument) string {
+	if strings.TrimSpace(document.WorkingContent) != "" {
+		return document.WorkingContent
+	}
+	return document.OriginalContent
+}
+
+func isSubtitleCandidateRevision(sourceKind string) bool {
+	return strings.HasSuffix(strings.TrimSpace(sourceKind), "_candidate")
+}
+
+// resolveSubtitleRevisionActor reports who produced a revision: a library
+// operation, the user, an agent tool call, or the system snapshotting content
+// before it gets replaced.
+func resolveSubtitleRevisionActor(revision library.SubtitleRevision) string {
+	switch revision.SourceKind {
+	case "snapshot":
+		return "system"
+	case "agent_edit":
+		return "agent"
+	case "manual_edit", "restore", "retime":
+		return "user"
+	}
+	if strings.TrimSpace(revision.SourceOperation) != "" {
+		return "operation"
+	}
+	return "user"
+}
+
+// diffSubtitleCues aligns two cue lists. Identical cues are matched first, then
+// cues whose text survived a retime, and the remaining gaps are paired by
+// overlapping timing so edited cues show up as modified instead of removed+added.
+func diffSubtitleCues(from []dto.SubtitleCue, to []dto.SubtitleCue) []dto.SubtitleRevisionDiffCueDTO {
+	return diffSubtitleCueRange(from, to, 0)
+}
+
+func diffSubtitleCueRange(from []dto.SubtitleCue, to []dto.SubtitleCue, level int) []dto.SubtitleRevisionDiffCueDTO {
+	result := make([]dto.SubtitleRevisionDiffCueDTO, 0, max(len(from), len(to)))
+	if level >= 2 {
+		return append(result, pairSubtitleCuesByTiming(from, to)...)
+	}
+	key := subtitleCueExactKey
+	if level == 1 {
+		key = subtitleCueTextKey
+	}
+	fromIndex, toIndex := 0, 0
+	for _, pair := range alignSubtitleCues(from, to, key) {
+		result = append(result, diffSubtitleCueRange(from[fromIndex:pair[0]], to[toIndex:pair[1]], level+1)...)
+		result = append(result, buildSubtitleCueDiffEntry(from[pair[0]], to[pair[1]]))
+		fromIndex, toIndex = pair[0]+1, pair[1]+1
+	}
+	return append(result, diffSubtitleCueRange(from[fromIndex:], to[toIndex:], level+1)...)
+}
+
+// alignSubtitleCues returns the index pairs of the longest common subsequence of
+// cue keys. Common prefixes and suffixes are matched directly to keep the table small.
+func alignSubtitleCues(from []dto.SubtitleCue, to []dto.SubtitleCue, key func(dto.SubtitleCue) string) [][2]int {
+	fromKeys := make([]string, len(from))
+	for index, cue := range from {
+		fromKeys[index] = key(cue)
+	}
+	toKeys := make([]string, len(to))
+	for index, cue := range to {
+		toKeys[index] = key(cue)
+	}
+	prefix := 0
+	for prefix < len(fromKeys) && prefix < len(toKeys) && fromKeys[prefix] == toKeys[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(fromKeys)-prefix && suffix < len(toKeys)-prefix &&
+		fromKeys[len(fromKeys)-1-suffix] == toKeys[len(toKeys)-1-suffix] {
+		suffix++
+	}
+	pairs := make([][2]int, 0, prefix+suffix)
+	for index := 0; index < prefix; index++ {
+		pairs = append(pairs, [2]int{index, index})
+	}
+	middleFrom := fromKeys[prefix : len(fromKeys)-suffix]
+	middleTo := toKeys[prefix : len(toKeys)-suffix]
+	if len(middleFrom) > 0 && len(middleTo) > 0 && len(middleFrom)*len(middleTo) <= subtitleRevisionDiffMaxCells {
+		lengths := make([][]int32, len(middleFrom)+1)
+		for row := range lengths {
+			lengths[row] = make([]int32, len(middleTo)+1)
+		}
+		for row := len(middleFrom) - 1; row >= 0; row-- {
+			for column := len(middleTo) - 1; column >= 0; column-- {
+				if middleFrom[row] == middleTo[column] {
+					lengths[row][column] = lengths[row+1][column+1] + 1
+				} else {
+					lengths[row][column] = max(lengths[row+1][column], lengths[row][column+1])
+				}
+			}
+		}
+		row, column := 0, 0
+		for row < len(middleFrom) && column < len(middleTo) {
+			switch {
+			case middleFrom[row] == middleTo[column]:
+				pairs = append(pairs, [2]int{prefix + row, prefix + column})
+				row++
+				column++
+			case lengths[row+1][column] >= lengths[row][column+1]:
+				row++
+			default:
+				column++
+			}
+		}
+	}
+	for index := suffix; index > 0; index-- {
+		pairs = append(pairs, [2]int{len(fromKeys) - index, len(toKeys) - index})
+	}
+	return pairs
+}
+
+func pairSubtitleCuesByTiming(from []dto.SubtitleCue, to []dto.SubtitleCue) []dto.SubtitleRevisionDiffCueDTO {
+	result := make([]dto.SubtitleRevisionDiffCueDTO, 0, max(len(from), len(to)))
+	fromIndex, toIndex := 0, 0
+	for fromIndex < len(from) && toIndex < len(to) {
+		fromCue, toCue := from[fromIndex], to[toIndex]
+		switch {
+		case subtitleCuesOverlap(fromCue, toCue):
+			result = append(result, buildSubtitleCueDiffEntry(fromCue, toCue))
+			fromIndex++
+			toIndex++
+		case cueStartMS(fromCue) < cueStartMS(toCue):
+			result = append(result, buildSubtitleCueRemovedEntry(fromCue))
+			fromIndex++
+		default:
+			result = append(result, buildSubtitleCueAddedEntry(toCue))
+			toIndex++
+		}
+	}
+	for ; fromIndex < len(from); fromIndex++ {
+		result = append(result, buildSubtitleCueRemovedEntry(from[fromIndex]))
+	}
+	for ; toIndex < len(to); toIndex++ {
+		result = append(result, buildSubtitleCueAddedEntry(to[toIndex]))
+	}
+	return result
+}
+
+func subtitleCuesOverlap(left dto.SubtitleCue, right dto.SubtitleCue) bool {
+	leftStart, leftEnd := cueStartMS(left), cueEndMS(left)
+	rightStart, rightEnd := cueStartMS(right), cueEndMS(right)
+	if leftStart == rightStart {
+		return true
+	}
+	return leftStart < rightEnd && rightStart < leftEnd
+}
+
+func buildSubtitleCueDiffEntry(from dto.SubtitleCue, to dto.SubtitleCue) dto.SubtitleRevisionDiffCueDTO {
+	entry := dto.SubtitleRevisionDiffCueDTO{
+		Status:        "unchanged",
+		FromIndex:     from.Index,
+		ToIndex:       to.Index,
+		FromStart:     from.Start,
+		FromEnd:       from.End,
+		ToStart:       to.Start,
+		ToEnd:         to.End,
+		FromText:      from.Text,
+		ToText:        to.Text,
+		TextChanged:   from.Text != to.Text,
+		TimingChanged: cueStartMS(from) != cueStartMS(to) || cueEndMS(from) != cueEndMS(to),
+	}
+	if entry.TextChanged || entry.TimingChanged {
+		entry.Status = "modified"
+	}
+	return entry
+}
+
+func buildSubtitleCueRemovedEntry(cue dto.SubtitleCue) dto.SubtitleRevisionDiffCueDTO {
+	return dto.SubtitleRevisionDiffCueDTO{Status: "removed", FromIndex: cue.Index, FromStart: cue.Start, FromEnd: cue.End, FromText: cue.Text}
+}
+
+func buildSubtitleCueAddedEntry(cue dto.SubtitleCue) dto.SubtitleRevisionDiffCueDTO {
+	return dto.SubtitleRevisionDiffCueDTO{Status: "added", ToIndex: cue.Index, ToStart: cue.Start, ToEnd: cue.End, ToText: cue.Text}
+}
+
+func subtitleCueExactKey(cue dto.SubtitleCue) string {
+	return fmt.Sprintf("%d|%d|%s", cueStartMS(cue), cueEndMS(cue), cue.Text)
+}
+
+func subtitleCueTextKey(cue dto.SubtitleCue) string {
+	return strings.Join(strings.Fields(cue.Text), " ")
+}
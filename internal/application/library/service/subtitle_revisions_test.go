@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+	"dreamcreator/internal/infrastructure/libraryrepo"
+	"dreamcreator/internal/infrastructure/persistence"
+)
+
+func TestDiffSubtitleCuesAlignsEditsRetimesAndInsertions(t *testing.T) {
+	t.Parallel()
+
+	from := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "Hello."},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "How are you?"},
+		{Index: 3, Start: "00:00:05,000", End: "00:00:06,000", Text: "Fine."},
+		{Index: 4, Start: "00:00:07,000", End: "00:00:08,000", Text: "Goodbye."},
+		{Index: 5, Start: "00:00:09,000", End: "00:00:10,000", Text: "Dropped."},
+	}
+	to := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "Hello."},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "How are you doing?"},
+		{Index: 3, Start: "00:00:04,500", End: "00:00:04,900", Text: "Inserted."},
+		{Index: 4, Start: "00:00:05,200", End: "00:00:06,200", Text: "Fine."},
+		{Index: 5, Start: "00:00:07,000", End: "00:00:08,000", Text: "Goodbye."},
+	}
+
+	entries := diffSubtitleCues(from, to)
+	statuses := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		statuses = append(statuses, entry.Status)
+	}
+	expected := []string{"unchanged", "modified", "added", "modified", "unchanged", "removed"}
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected statuses %v, got %v", expected, statuses)
+	}
+	for index := range expected {
+		if statuses[index] != expected[index] {
+			t.Fatalf("expected statuses %v, got %v", expected, statuses)
+		}
+	}
+	if !entries[1].TextChanged || entries[1].TimingChanged {
+		t.Fatalf("expected cue 2 to be a text edit, got %#v", entries[1])
+	}
+	if entries[3].TextChanged || !entries[3].TimingChanged || entries[3].FromIndex != 3 || entries[3].ToIndex != 4 {
+		t.Fatalf("expected cue 3 to be retimed into cue 4, got %#v", entries[3])
+	}
+	if entries[5].FromText != "Dropped." {
+		t.Fatalf("expected the last cue to be removed, got %#v", entries[5])
+	}
+}
+
+func TestRestoreSubtitleRevisionRecordsHistoryWithSQLiteRepos(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2026, 4, 3, 9, 0, 0, 0, time.UTC)
+	db, err := persistence.OpenSQLite(ctx, persistence.SQLiteConfig{
+		Path: filepath.Join(t.TempDir(), "library-subtitle-revisions.db"),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	libraries := libraryrepo.NewSQLiteLibraryRepository(db.Bun)
+	files := libraryrepo.NewSQLiteFileRepository(db.Bun)
+	subtitles := libraryrepo.NewSQLiteSubtitleDocumentRepository(db.Bun)
+	revisions := libraryrepo.NewSQLiteSubtitleRevisionRepository(db.Bun)
+
+	libraryItem := mustNewLibrary(t, "lib-1", now)
+	if err := libraries.Save(ctx, libraryItem); err != nil {
+		t.Fatalf("save library: %v", err)
+	}
+	fileItem := mustNewSubtitleFile(t, "file-1", libraryItem.ID, "op-1", "/tmp/episode.srt", "doc-1", now)
+	if err := files.Save(ctx, fileItem); err != nil {
+		t.Fatalf("save file: %v", err)
+	}
+	original := "1\n00:00:01,000 --> 00:00:02,000\nhello\n"
+	documentItem, err := library.NewSubtitleDocument(library.SubtitleDocumentParams{
+		ID:              fileItem.Storage.DocumentID,
+		FileID:          fileItem.ID,
+		LibraryID:       fileItem.LibraryID,
+		Format:          "srt",
+		OriginalContent: original,
+		WorkingContent:  original,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
+	})
+	if err != nil {
+		t.Fatalf("new subtitle document: %v", err)
+	}
+	if err := subtitles.Save(ctx, documentItem); err != nil {
+		t.Fatalf("save subtitle document: %v", err)
+	}
+
+	tick := now
+	service := &LibraryService{
+		libraries:  libraries,
+		files:      files,
+		workspace:  &deleteRuleWorkspaceRepo{},
+		fileEvents: &deleteRuleFileEventRepo{},
+		subtitles:  subtitles,
+		revisions:  revisions,
+		nowFunc: func() time.Time {
+			tick = tick.Add(time.Second)
+			return tick
+		},
+	}
+
+	if _, err := service.SaveSubtitle(ctx, dto.SubtitleSaveRequest{FileID: fileItem.ID, Document: &dto.SubtitleDocument{
+		Format: "srt",
+		Cues:   []dto.SubtitleCue{{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "hello there"}},
+	}}); err != nil {
+		t.Fatalf("SaveSubtitle: %v", err)
+	}
+	if _, err := service.SaveSubtitle(ctx, dto.SubtitleSaveRequest{FileID: fileItem.ID, RunID: "run-1", Document: &dto.SubtitleDocument{
+		Format: "srt",
+		Cues:   []dto.SubtitleCue{{Index: 1, Start: "00:00:01,500", End: "00:00:02,000", Text: "hello there"}},
+	}}); err != nil {
+		t.Fatalf("SaveSubtitle agent: %v", err)
+	}
+
+	items, err := service.ListSubtitleRevisions(ctx, dto.ListSubtitleRevisionsRequest{FileID: fileItem.ID})
+	if err != nil {
+		t.Fatalf("ListSubtitleRevisions: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected snapshot, manual and agent revisions, got %#v", items)
+	}
+	if items[0].SourceKind != "agent_edit" || items[0].Actor != "agent" || !items[0].Current {
+		t.Fatalf("expected the newest revision to be the current agent edit, got %#v", items[0])
+	}
+	if items[1].SourceKind != "manual_edit" || items[2].SourceKind != "snapshot" || items[2].Actor != "system" {
+		t.Fatalf("unexpected revision history %#v", items)
+	}
+
+	diff, err := service.DiffSubtitleRevisions(ctx, dto.DiffSubtitleRevisionsRequest{FromRevisionID: "original", ToRevisionID: items[0].ID, FileID: fileItem.ID})
+	if err != nil {
+		t.Fatalf("DiffSubtitleRevisions: %v", err)
+	}
+	if diff.ModifiedCount != 1 || len(diff.Cues) != 1 || !diff.Cues[0].TextChanged || !diff.Cues[0].TimingChanged {
+		t.Fatalf("unexpected diff %#v", diff)
+	}
+
+	restored, err := service.RestoreSubtitleRevision(ctx, dto.RestoreSubtitleRevisionRequest{FileID: fileItem.ID, RevisionID: items[1].ID})
+	if err != nil {
+		t.Fatalf("RestoreSubtitleRevision: %v", err)
+	}
+	if restored.SnapshotRevisionID != "" || restored.RestoredRevisionID != items[1].ID {
+		t.Fatalf("expected no extra snapshot when the agent edit is already recorded, got %#v", restored)
+	}
+	manualEdit, err := revisions.Get(ctx, items[1].ID)
+	if err != nil {
+		t.Fatalf("get revision: %v", err)
+	}
+	document, err := subtitles.GetByFileID(ctx, fileItem.ID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+	if document.WorkingContent != manualEdit.Content || document.WorkingContent == original {
+		t.Fatalf("expected manual edit to be restored, got %q", document.WorkingContent)
+	}
+	latest, err := revisions.ListByFileID(ctx, fileItem.ID, 1)
+	if err != nil || len(latest) != 1 || latest[0].ID != restored.RevisionID || latest[0].SourceKind != "restore" {
+		t.Fatalf("expected a restore revision on top of the history, got %#v %v", latest, err)
+	}
+	if _, err := service.RestoreSubtitleRevision(ctx, dto.RestoreSubtitleRevisionRequest{FileID: "file-2", RevisionID: items[1].ID}); err == nil {
+		t.Fatalf("expected a revision from another file to be rejected")
+	}
+}
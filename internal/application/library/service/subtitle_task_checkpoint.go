@@ -22,6 +22,8 @@ type subtitleTaskRunState struct {
 	RequestHash           string `json:"requestHash,omitempty"`
 	PromptHash            string `json:"promptHash,omitempty"`
 	ResumedFromCheckpoint bool   `json:"resumedFromCheckpoint,omitempty"`
+	MemoryReusedCueCount  int    `json:"memoryReusedCueCount,omitempty"`
+	MemoryRecordedCount   int    `json:"memoryRecordedCount,omitempty"`
 }
 
 type subtitleChunkCheckpointState struct {
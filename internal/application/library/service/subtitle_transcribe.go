@@ -0,0 +1,838 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/externaltools"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleTranscribeWindowMs      int64 = 10 * 60 * 1000
+	subtitleTranscribeSampleRate          = 16000
+	subtitleTranscribeDefaultFormat       = "srt"
+)
+
+var whisperSegmentLinePattern = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2}[.,]\d{3})\s*-->\s*(\d{2}:\d{2}:\d{2}[.,]\d{3})\]\s*(.*)$`)
+
+type subtitleTranscribeOutput struct {
+	FileID                string `json:"fileId,omitempty"`
+	DocumentID            string `json:"documentId,omitempty"`
+	SourceFileID          string `json:"sourceFileId,omitempty"`
+	Status                string `json:"status"`
+	Language              string `json:"language,omitempty"`
+	Model                 string `json:"model,omitempty"`
+	OutputFormat          string `json:"outputFormat,omitempty"`
+	DurationMs            int64  `json:"durationMs,omitempty"`
+	ChunkCount            int    `json:"chunkCount,omitempty"`
+	CompletedChunkCount   int    `json:"completedChunkCount,omitempty"`
+	CueCount              int    `json:"cueCount,omitempty"`
+	RequestHash           string `json:"requestHash,omitempty"`
+	ResumedFromCheckpoint bool   `json:"resumedFromCheckpoint,omitempty"`
+}
+
+type subtitleTranscribeWindow struct {
+	Sequence int
+	StartMs  int64
+	EndMs    int64
+}
+
+type subtitleTranscribeCheckpointState struct {
+	CuesByChunkIndex      map[int][]dto.SubtitleCue
+	CompletedChunkCount   int
+	ResumedFromCheckpoint bool
+}
+
+func (service *LibraryService) CreateSubtitleTranscribeJob(ctx context.Context, request dto.SubtitleTranscribeRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeSubtitleTranscribeRequest(request)
+	if request.FileID == "" {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("fileId is required")
+	}
+	sourceFile, err := service.files.Get(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := validateSubtitleTranscribeSource(sourceFile); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "subtitle_transcribe",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: buildSubtitleTranscribeOutputName(sourceFile.Name, request.Language),
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  marshalJSON(buildSubtitleTranscribeOutput(request, "queued")),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			0,
+			progressText("library.progressDetail.subtitleTranscribeQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runSubtitleTranscribeOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runSubtitleTranscribeOperation(ctx context.Context, operation library.LibraryOperation, request dto.SubtitleTranscribeRequest) {
+	request = normalizeSubtitleTranscribeRequest(request)
+	release, err := service.acquireOperationSlot(ctx, operation, 0)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+
+	sourceFile, err := service.files.Get(ctx, request.FileID)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	if err := validateSubtitleTranscribeSource(sourceFile); err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	whisperExecPath, err := resolveWhisperCPPExecPath(ctx, service.tools)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	modelPath, err := resolveWhisperCPPModelPath(ctx, service.tools, request.ModelPath)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	if probe.DurationMs <= 0 {
+		service.failSubtitleTranscribeOperation(ctx, operation, errors.New("source media duration is unknown"))
+		return
+	}
+
+	windows := buildSubtitleTranscribeWindows(probe.DurationMs, subtitleTranscribeWindowMs)
+	requestHash := hashStructuredPayload(struct {
+		Request dto.SubtitleTranscribeRequest `json:"request"`
+		Model   string                        `json:"model"`
+	}{Request: request, Model: filepath.Base(modelPath)})
+	sourceHash := hashStructuredPayload([]any{sourceFile.Storage.LocalPath, probe.SizeBytes, probe.DurationMs})
+	checkpointState, err := service.loadSubtitleTranscribeCheckpointState(ctx, operation.ID, windows, requestHash, sourceHash)
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+
+	output := buildSubtitleTranscribeOutput(request, "running")
+	output.SourceFileID = sourceFile.ID
+	output.Model = filepath.Base(modelPath)
+	output.DurationMs = probe.DurationMs
+	output.ChunkCount = len(windows)
+	output.CompletedChunkCount = checkpointState.CompletedChunkCount
+	output.RequestHash = requestHash
+	output.ResumedFromCheckpoint = checkpointState.ResumedFromCheckpoint
+
+	operation.Status = library.OperationStatusRunning
+	now := service.now()
+	operation.StartedAt = &now
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		0,
+		progressText("library.progressDetail.preparingSubtitleTranscribe"),
+	)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	tempDir, err := os.MkdirTemp("", "dreamcreator-transcribe-*")
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	audioPath := ""
+	if checkpointState.CompletedChunkCount < len(windows) {
+		audioPath = filepath.Join(tempDir, "audio.wav")
+		reporter := newFFmpegProgressReporter(service, &operation, probe.DurationMs)
+		reporter.stage = progressText("library.progress.preparing")
+		reporter.message = progressText("library.progressDetail.extractingAudio")
+		outputText, err := service.runFFmpegCommandWithReporter(
+			runCtx,
+			reporter,
+			ffmpegExecPath,
+			buildWhisperAudioExtractArgs(sourceFile.Storage.LocalPath, audioPath),
+			tempDir,
+		)
+		if err != nil {
+			if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+				_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+				return
+			}
+			message := strings.TrimSpace(outputText)
+			if message == "" {
+				message = err.Error()
+			}
+			service.failSubtitleTranscribeOperation(ctx, operation, fmt.Errorf("ffmpeg audio extraction failed: %s", message))
+			return
+		}
+	}
+
+	transcribedCues := make([]dto.SubtitleCue, 0)
+	completedChunkCount := checkpointState.CompletedChunkCount
+	for _, window := range windows {
+		if restored, ok := checkpointState.CuesByChunkIndex[window.Sequence]; ok {
+			transcribedCues = append(transcribedCues, restored...)
+			continue
+		}
+		if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+			_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+			return
+		}
+
+		startedAt := service.now()
+		if err := service.saveSubtitleTranscribeCheckpoint(ctx, operation, window, library.OperationChunkStatusRunning, requestHash, sourceHash, nil, "", &startedAt, nil); err != nil {
+			service.failSubtitleTranscribeOperation(ctx, operation, err)
+			return
+		}
+		baseCueCount := len(transcribedCues)
+		windowCues, err := service.runWhisperCPPWindow(runCtx, whisperExecPath, modelPath, audioPath, tempDir, request, window, func(cue dto.SubtitleCue, count int) {
+			progressTime := service.now()
+			operation.Progress = buildOperationProgress(
+				progressTime,
+				progressText("library.progress.transcribing"),
+				int(cueEndMS(cue)),
+				int(probe.DurationMs),
+				progressTextTemplate("library.progressDetail.transcribedCues", map[string]string{
+					"count": strconv.Itoa(baseCueCount + count),
+				}),
+			)
+			_ = service.saveAndPublishOperation(ctx, operation)
+		})
+		finishedAt := service.now()
+		if err != nil {
+			checkpointStatus := library.OperationChunkStatusFailed
+			if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+				checkpointStatus = library.OperationChunkStatusCanceled
+			}
+			if saveErr := service.saveSubtitleTranscribeCheckpoint(ctx, operation, window, checkpointStatus, requestHash, sourceHash, nil, err.Error(), &startedAt, &finishedAt); saveErr != nil {
+				service.failSubtitleTranscribeOperation(ctx, operation, saveErr)
+				return
+			}
+			if checkpointStatus == library.OperationChunkStatusCanceled {
+				_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+				return
+			}
+			service.failSubtitleTranscribeOperation(ctx, operation, fmt.Errorf("chunk %d/%d failed: %w", window.Sequence, len(windows), err))
+			return
+		}
+		if err := service.saveSubtitleTranscribeCheckpoint(ctx, operation, window, library.OperationChunkStatusSucceeded, requestHash, sourceHash, windowCues, "", &startedAt, &finishedAt); err != nil {
+			service.failSubtitleTranscribeOperation(ctx, operation, err)
+			return
+		}
+		transcribedCues = append(transcribedCues, windowCues...)
+		completedChunkCount++
+		output.CompletedChunkCount = completedChunkCount
+		output.CueCount = len(transcribedCues)
+		operation.OutputJSON = marshalJSON(output)
+		if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+			return
+		}
+	}
+
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+	if len(transcribedCues) == 0 {
+		service.failSubtitleTranscribeOperation(ctx, operation, errors.New("no speech was recognized"))
+		return
+	}
+	for index := range transcribedCues {
+		transcribedCues[index].Index = index + 1
+	}
+
+	outputFormat := resolveSubtitleTranscribeOutputFormat(request.OutputFormat)
+	transcribedDocument := dto.SubtitleDocument{
+		Format: outputFormat,
+		Cues:   transcribedCues,
+		Metadata: map[string]any{
+			"transcribed": true,
+			"language":    request.Language,
+		},
+	}
+	transcribedContent := renderSubtitleContent(transcribedDocument, outputFormat)
+	finishedAt := service.now()
+	transcribedFile, history, err := service.createDerivedSubtitleFile(ctx, derivedSubtitleParams{
+		LibraryID:      sourceFile.LibraryID,
+		RootFileID:     rootFileID(sourceFile),
+		Name:           buildSubtitleTranscribeOutputName(sourceFile.Name, request.Language),
+		OperationID:    operation.ID,
+		OperationKind:  "subtitle_transcribe",
+		Format:         outputFormat,
+		OriginalSource: transcribedContent,
+		OccurredAt:     finishedAt,
+		HistorySource:  library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID},
+	})
+	if err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	transcribedFile.LatestOperationID = operation.ID
+	transcribedFile.UpdatedAt = finishedAt
+	if err := service.files.Save(ctx, transcribedFile); err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.OutputFiles = []library.OperationOutputFile{{
+		FileID:    transcribedFile.ID,
+		Kind:      string(transcribedFile.Kind),
+		Format:    mediaFormatFromFile(transcribedFile),
+		SizeBytes: mediaSizeFromFile(transcribedFile),
+		IsPrimary: true,
+		Deleted:   transcribedFile.State.Deleted,
+	}}
+	operation.Metrics = buildOperationMetricsForOperation([]library.LibraryFile{transcribedFile}, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		len(windows),
+		len(windows),
+		progressText("library.progressDetail.subtitleTranscribeCompleted"),
+	)
+	output.Status = "completed"
+	output.FileID = transcribedFile.ID
+	output.DocumentID = transcribedFile.Storage.DocumentID
+	output.OutputFormat = outputFormat
+	output.CompletedChunkCount = completedChunkCount
+	output.CueCount = len(transcribedCues)
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+
+	history.Refs.OperationID = operation.ID
+	history.Action = "subtitle_transcribe"
+	history.Status = string(operation.Status)
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "subtitle_transcribe"}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, sourceFile.LibraryID, finishedAt); err != nil {
+		service.failSubtitleTranscribeOperation(ctx, operation, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	service.publishFileUpdate(service.mustBuildFileDTO(ctx, transcribedFile))
+	service.publishWorkspaceProjectUpdate(sourceFile.LibraryID)
+}
+
+func (service *LibraryService) runWhisperCPPWindow(
+	ctx context.Context,
+	execPath string,
+	modelPath string,
+	audioPath string,
+	workDir string,
+	request dto.SubtitleTranscribeRequest,
+	window subtitleTranscribeWindow,
+	onCue func(cue dto.SubtitleCue, count int),
+) ([]dto.SubtitleCue, error) {
+	command := exec.CommandContext(ctx, execPath, buildWhisperCPPArgs(modelPath, audioPath, request, window)...)
+	command.Dir = strings.TrimSpace(workDir)
+	configureProcessGroup(command)
+
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := command.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+
+	cues := make([]dto.SubtitleCue, 0)
+	var stderrBuilder strings.Builder
+	var stdoutErr error
+	var stderrErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		stdoutErr = scanWhisperCPPSegments(stdout, func(cue dto.SubtitleCue) {
+			cue = shiftWhisperCueIntoWindow(cue, window)
+			cues = append(cues, cue)
+			if onCue != nil {
+				onCue(cue, len(cues))
+			}
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		stderrErr = scanFFmpegOutput(stderr, &stderrBuilder, nil)
+	}()
+
+	waitErr := command.Wait()
+	wg.Wait()
+
+	if waitErr != nil {
+		message := strings.TrimSpace(stderrBuilder.String())
+		if message == "" {
+			message = waitErr.Error()
+		}
+		return nil, fmt.Errorf("whisper.cpp transcription failed: %s", tailWhisperCPPOutput(message, 5))
+	}
+	if stdoutErr != nil {
+		return nil, stdoutErr
+	}
+	if stderrErr != nil {
+		return nil, stderrErr
+	}
+	return cues, nil
+}
+
+func tailWhisperCPPOutput(output string, limit int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func scanWhisperCPPSegments(reader io.Reader, handler func(dto.SubtitleCue)) error {
+	if reader == nil {
+		return nil
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		cue, ok := parseWhisperCPPSegmentLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		handler(cue)
+	}
+	return scanner.Err()
+}
+
+func parseWhisperCPPSegmentLine(line string) (dto.SubtitleCue, bool) {
+	matches := whisperSegmentLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if len(matches) != 4 {
+		return dto.SubtitleCue{}, false
+	}
+	text := strings.TrimSpace(matches[3])
+	if text == "" || isWhisperCPPNonSpeechText(text) {
+		return dto.SubtitleCue{}, false
+	}
+	startMs, ok := parseTimestampToMilliseconds(strings.ReplaceAll(matches[1], ",", "."))
+	if !ok {
+		return dto.SubtitleCue{}, false
+	}
+	endMs, ok := parseTimestampToMilliseconds(strings.ReplaceAll(matches[2], ",", "."))
+	if !ok || endMs < startMs {
+		return dto.SubtitleCue{}, false
+	}
+	return dto.SubtitleCue{
+		Start: formatSRTTimestamp(startMs),
+		End:   formatSRTTimestamp(endMs),
+		Text:  text,
+	}, true
+}
+
+func isWhisperCPPNonSpeechText(text string) bool {
+	switch strings.ToUpper(strings.Trim(strings.TrimSpace(text), "[]() ")) {
+	case "BLANK_AUDIO", "MUSIC", "SILENCE", "NO SPEECH":
+		return true
+	default:
+		return false
+	}
+}
+
+// shiftWhisperCueIntoWindow keeps cue timestamps absolute. Recent whisper.cpp builds
+// already report offset-relative segments as absolute times, older ones start at zero.
+func shiftWhisperCueIntoWindow(cue dto.SubtitleCue, window subtitleTranscribeWindow) dto.SubtitleCue {
+	startMs := cueStartMS(cue)
+	endMs := cueEndMS(cue)
+	if window.StartMs <= 0 || startMs >= window.StartMs {
+		return cue
+	}
+	cue.Start = formatSRTTimestamp(startMs + window.StartMs)
+	cue.End = formatSRTTimestamp(endMs + window.StartMs)
+	return cue
+}
+
+func buildSubtitleTranscribeWindows(durationMs int64, windowMs int64) []subtitleTranscribeWindow {
+	if durationMs <= 0 {
+		return nil
+	}
+	if windowMs <= 0 {
+		windowMs = durationMs
+	}
+	result := make([]subtitleTranscribeWindow, 0, int(durationMs/windowMs)+1)
+	for startMs := int64(0); startMs < durationMs; startMs += windowMs {
+		endMs := startMs + windowMs
+		if endMs > durationMs {
+			endMs = durationMs
+		}
+		result = append(result, subtitleTranscribeWindow{
+			Sequence: len(result) + 1,
+			StartMs:  startMs,
+			EndMs:    endMs,
+		})
+	}
+	return result
+}
+
+func buildWhisperAudioExtractArgs(inputPath string, outputPath string) []string {
+	return []string{
+		"-y",
+		"-i", inputPath,
+		"-vn",
+		"-ac", "1",
+		"-ar", strconv.Itoa(subtitleTranscribeSampleRate),
+		"-c:a", "pcm_s16le",
+		outputPath,
+	}
+}
+
+func buildWhisperCPPArgs(modelPath string, audioPath string, request dto.SubtitleTranscribeRequest, window subtitleTranscribeWindow) []string {
+	args := []string{
+		"-m", modelPath,
+		"-f", audioPath,
+		"-l", firstNonEmpty(request.Language, "auto"),
+		"-ot", strconv.FormatInt(window.StartMs, 10),
+		"-d", strconv.FormatInt(window.EndMs-window.StartMs, 10),
+	}
+	if request.Threads > 0 {
+		args = append(args, "-t", strconv.Itoa(request.Threads))
+	}
+	if request.Prompt != "" {
+		args = append(args, "--prompt", request.Prompt)
+	}
+	return args
+}
+
+func resolveWhisperCPPExecPath(ctx context.Context, resolver ToolResolver) (string, error) {
+	if resolver == nil {
+		return "", fmt.Errorf("whisper.cpp is not installed")
+	}
+	ready, reason, err := resolver.ToolReadiness(ctx, externaltools.ToolWhisperCPP)
+	if err != nil {
+		return "", err
+	}
+	if !ready {
+		switch strings.TrimSpace(reason) {
+		case "invalid":
+			return "", fmt.Errorf("whisper.cpp is invalid")
+		case "missing_exec_path", "exec_not_found", "not_found", "not_installed", "":
+			return "", fmt.Errorf("whisper.cpp is not installed")
+		default:
+			return "", fmt.Errorf("whisper.cpp is not ready: %s", reason)
+		}
+	}
+	return resolver.ResolveExecPath(ctx, externaltools.ToolWhisperCPP)
+}
+
+// resolveWhisperCPPModelPath prefers an explicit model path and otherwise picks the
+// first ggml model found next to the whisper-cli binary.
+func resolveWhisperCPPModelPath(ctx context.Context, resolver ToolResolver, requested string) (string, error) {
+	if trimmed := strings.TrimSpace(requested); trimmed != "" {
+		info, err := os.Stat(trimmed)
+		if err != nil || info.IsDir() {
+			return "", fmt.Errorf("whisper.cpp model not found: %s", trimmed)
+		}
+		return trimmed, nil
+	}
+	if resolver != nil {
+		if dir, err := resolver.ResolveToolDirectory(ctx, externaltools.ToolWhisperCPP); err == nil && strings.TrimSpace(dir) != "" {
+			dir = strings.TrimSpace(dir)
+			for _, candidateDir := range []string{dir, filepath.Join(dir, "models"), filepath.Join(filepath.Dir(dir), "models")} {
+				matches, _ := filepath.Glob(filepath.Join(candidateDir, "ggml-*.bin"))
+				sort.Strings(matches)
+				if len(matches) > 0 {
+					return matches[0], nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("whisper.cpp model not found, set modelPath to a ggml model file")
+}
+
+func validateSubtitleTranscribeSource(sourceFile library.LibraryFile) error {
+	if strings.TrimSpace(sourceFile.LibraryID) == "" {
+		return fmt.Errorf("source file is not attached to a library")
+	}
+	switch sourceFile.Kind {
+	case library.FileKindVideo, library.FileKindAudio, library.FileKindTranscode:
+	default:
+		return fmt.Errorf("file kind %q does not support transcription", sourceFile.Kind)
+	}
+	if strings.TrimSpace(sourceFile.Storage.LocalPath) == "" {
+		return fmt.Errorf("source media path is empty")
+	}
+	return nil
+}
+
+func (service *LibraryService) loadSubtitleTranscribeCheckpointState(
+	ctx context.Context,
+	operationID string,
+	windows []subtitleTranscribeWindow,
+	requestHash string,
+	sourceHash string,
+) (subtitleTranscribeCheckpointState, error) {
+	state := subtitleTranscribeCheckpointState{CuesByChunkIndex: make(map[int][]dto.SubtitleCue)}
+	if service == nil || service.operationChunks == nil {
+		return state, nil
+	}
+	rows, err := service.operationChunks.ListByOperationID(ctx, operationID)
+	if err != nil {
+		return state, err
+	}
+	windowMap := make(map[int]subtitleTranscribeWindow, len(windows))
+	for _, window := range windows {
+		windowMap[window.Sequence] = window
+	}
+	for _, row := range rows {
+		if row.Status != library.OperationChunkStatusSucceeded {
+			continue
+		}
+		window, ok := windowMap[row.ChunkIndex]
+		if !ok {
+			continue
+		}
+		if row.RequestHash != "" && requestHash != "" && row.RequestHash != requestHash {
+			return state, fmt.Errorf("checkpoint request hash mismatch for chunk %d", row.ChunkIndex)
+		}
+		if row.InputHash != "" && row.InputHash != hashSubtitleTranscribeWindowInput(sourceHash, window) {
+			return state, fmt.Errorf("checkpoint source changed for chunk %d", row.ChunkIndex)
+		}
+		cues := make([]dto.SubtitleCue, 0)
+		if strings.TrimSpace(row.ResultJSON) != "" {
+			if err := json.Unmarshal([]byte(row.ResultJSON), &cues); err != nil {
+				return state, fmt.Errorf("checkpoint parse failed for chunk %d: %w", row.ChunkIndex, err)
+			}
+		}
+		state.CuesByChunkIndex[row.ChunkIndex] = cues
+		state.CompletedChunkCount++
+	}
+	state.ResumedFromCheckpoint = state.CompletedChunkCount > 0
+	return state, nil
+}
+
+func (service *LibraryService) saveSubtitleTranscribeCheckpoint(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	window subtitleTranscribeWindow,
+	status library.OperationChunkStatus,
+	requestHash string,
+	sourceHash string,
+	cues []dto.SubtitleCue,
+	errorMessage string,
+	startedAt *time.Time,
+	finishedAt *time.Time,
+) error {
+	if service == nil || service.operationChunks == nil {
+		return nil
+	}
+	now := service.now()
+	resultJSON := ""
+	responseHash := ""
+	if status == library.OperationChunkStatusSucceeded {
+		if cues == nil {
+			cues = []dto.SubtitleCue{}
+		}
+		resultJSON = marshalJSON(cues)
+		responseHash = hashStructuredPayload(cues)
+	}
+	chunkItem, err := library.NewOperationChunk(library.OperationChunkParams{
+		ID:           uuid.NewString(),
+		OperationID:  operation.ID,
+		LibraryID:    operation.LibraryID,
+		ChunkIndex:   window.Sequence,
+		Status:       string(status),
+		SourceRange:  fmt.Sprintf("%s-%s", formatSRTTimestamp(window.StartMs), formatSRTTimestamp(window.EndMs)),
+		InputHash:    hashSubtitleTranscribeWindowInput(sourceHash, window),
+		RequestHash:  strings.TrimSpace(requestHash),
+		ResponseHash: responseHash,
+		ResultJSON:   resultJSON,
+		ErrorMessage: strings.TrimSpace(errorMessage),
+		StartedAt:    startedAt,
+		FinishedAt:   finishedAt,
+		CreatedAt:    &now,
+		UpdatedAt:    &now,
+	})
+	if err != nil {
+		return err
+	}
+	return service.operationChunks.Save(ctx, chunkItem)
+}
+
+func hashSubtitleTranscribeWindowInput(sourceHash string, window subtitleTranscribeWindow) string {
+	return hashStructuredPayload([]any{sourceHash, window.StartMs, window.EndMs})
+}
+
+func (service *LibraryService) failSubtitleTranscribeOperation(ctx context.Context, operation library.LibraryOperation, err error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	if errors.Is(err, context.Canceled) || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+	currentOperation := operation
+	if item, getErr := service.operations.Get(ctx, operation.ID); getErr == nil {
+		currentOperation = item
+		if currentOperation.Status == library.OperationStatusCanceled {
+			return
+		}
+	}
+	now := service.now()
+	currentOperation.Status = library.OperationStatusFailed
+	currentOperation.ErrorCode = "subtitle_transcribe_failed"
+	currentOperation.ErrorMessage = strings.TrimSpace(err.Error())
+	currentOperation.FinishedAt = &now
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText("library.status.failed"),
+		progressCurrent(currentOperation.Progress),
+		progressTotal(currentOperation.Progress),
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	currentOperation.OutputJSON = buildTerminalSubtitleTranscribeOutput(currentOperation.InputJSON, currentOperation.OutputJSON, "failed")
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+}
+
+func buildTerminalSubtitleTranscribeOutput(inputJSON string, currentOutputJSON string, status string) string {
+	output := buildSubtitleTranscribeOutput(extractSubtitleTranscribeRequest(inputJSON), status)
+	if existing, ok := parseSubtitleTranscribeOutput(currentOutputJSON); ok {
+		existing.Status = status
+		existing.FileID = ""
+		existing.DocumentID = ""
+		output = existing
+	}
+	return marshalJSON(output)
+}
+
+func buildSubtitleTranscribeOutput(request dto.SubtitleTranscribeRequest, status string) subtitleTranscribeOutput {
+	return subtitleTranscribeOutput{
+		SourceFileID: request.FileID,
+		Status:       status,
+		Language:     firstNonEmpty(request.Language, "auto"),
+		OutputFormat: resolveSubtitleTranscribeOutputFormat(request.OutputFormat),
+	}
+}
+
+func parseSubtitleTranscribeOutput(raw string) (subtitleTranscribeOutput, bool) {
+	output := subtitleTranscribeOutput{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &output); err != nil {
+		return subtitleTranscribeOutput{}, false
+	}
+	return output, true
+}
+
+func resolveSubtitleTranscribeOutputFormat(value string) string {
+	switch format := strings.ToLower(strings.TrimSpace(value)); format {
+	case "srt", "vtt", "ass", "ssa", "itt", "fcpxml", "ttml", "sbv", "sub", "lrc":
+		return format
+	default:
+		return subtitleTranscribeDefaultFormat
+	}
+}
+
+func buildSubtitleTranscribeOutputName(name string, language string) string {
+	base := strings.TrimSpace(strings.TrimSuffix(name, filepath.Ext(name)))
+	if base == "" {
+		base = "Transcript"
+	}
+	language = strings.ToUpper(strings.TrimSpace(language))
+	if language == "" || language == "AUTO" {
+		return fmt.Sprintf("%s (Transcript)", base)
+	}
+	return fmt.Sprintf("%s (Transcript %s)", base, language)
+}
+
+func normalizeSubtitleTranscribeRequest(request dto.SubtitleTranscribeRequest) dto.SubtitleTranscribeRequest {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.Language = strings.ToLower(strings.TrimSpace(request.Language))
+	request.ModelPath = strings.TrimSpace(request.ModelPath)
+	request.OutputFormat = strings.TrimSpace(request.OutputFormat)
+	request.Prompt = strings.TrimSpace(request.Prompt)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.Threads < 0 {
+		request.Threads = 0
+	}
+	return request
+}
+
+func extractSubtitleTranscribeRequest(inputJSON string) dto.SubtitleTranscribeRequest {
+	request := dto.SubtitleTranscribeRequest{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request); err != nil {
+		return dto.SubtitleTranscribeRequest{}
+	}
+	return normalizeSubtitleTranscribeRequest(request)
+}
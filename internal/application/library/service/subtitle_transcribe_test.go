@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestParseWhisperCPPSegmentLine(t *testing.T) {
+	t.Parallel()
+
+	cue, ok := parseWhisperCPPSegmentLine("[00:01:02.500 --> 00:01:04.250]   Hello there.")
+	if !ok {
+		t.Fatalf("expected segment line to parse")
+	}
+	if cue.Start != "00:01:02,500" || cue.End != "00:01:04,250" {
+		t.Fatalf("unexpected cue timing: %s --> %s", cue.Start, cue.End)
+	}
+	if cue.Text != "Hello there." {
+		t.Fatalf("unexpected cue text: %q", cue.Text)
+	}
+
+	for _, line := range []string{
+		"whisper_init_from_file_with_params_no_state: loading model",
+		"[00:00:00.000 --> 00:00:02.000]   [BLANK_AUDIO]",
+		"[00:00:03.000 --> 00:00:02.000]   backwards",
+	} {
+		if _, ok := parseWhisperCPPSegmentLine(line); ok {
+			t.Fatalf("expected %q to be ignored", line)
+		}
+	}
+}
+
+func TestScanWhisperCPPSegmentsShiftsRelativeWindowCues(t *testing.T) {
+	t.Parallel()
+
+	window := subtitleTranscribeWindow{Sequence: 2, StartMs: 600000, EndMs: 900000}
+	output := strings.Join([]string{
+		"[00:00:01.000 --> 00:00:02.000]   relative",
+		"[00:10:05.000 --> 00:10:06.000]   absolute",
+	}, "\n")
+	cues := make([]dto.SubtitleCue, 0)
+	if err := scanWhisperCPPSegments(strings.NewReader(output), func(cue dto.SubtitleCue) {
+		cues = append(cues, shiftWhisperCueIntoWindow(cue, window))
+	}); err != nil {
+		t.Fatalf("scan segments failed: %v", err)
+	}
+	if len(cues) != 2 {
+		t.Fatalf("expected 2 cues, got %d", len(cues))
+	}
+	if cues[0].Start != "00:10:01,000" || cues[0].End != "00:10:02,000" {
+		t.Fatalf("expected relative cue to be shifted into window, got %s --> %s", cues[0].Start, cues[0].End)
+	}
+	if cues[1].Start != "00:10:05,000" {
+		t.Fatalf("expected absolute cue to stay put, got %s", cues[1].Start)
+	}
+}
+
+func TestBuildSubtitleTranscribeWindows(t *testing.T) {
+	t.Parallel()
+
+	windows := buildSubtitleTranscribeWindows(25*60*1000, subtitleTranscribeWindowMs)
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(windows))
+	}
+	if windows[0].Sequence != 1 || windows[0].StartMs != 0 || windows[0].EndMs != subtitleTranscribeWindowMs {
+		t.Fatalf("unexpected first window: %+v", windows[0])
+	}
+	if windows[2].StartMs != 2*subtitleTranscribeWindowMs || windows[2].EndMs != 25*60*1000 {
+		t.Fatalf("unexpected last window: %+v", windows[2])
+	}
+
+	args := buildWhisperCPPArgs("/models/ggml-base.bin", "/tmp/audio.wav", dto.SubtitleTranscribeRequest{Threads: 4}, windows[2])
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-l auto") || !strings.Contains(joined, "-ot 1200000") || !strings.Contains(joined, "-d 300000") || !strings.Contains(joined, "-t 4") {
+		t.Fatalf("unexpected whisper args: %s", joined)
+	}
+}
@@ -27,8 +27,9 @@ const (
 )
 
 type subtitleTranslateChunk struct {
-	Sequence int
-	Cues     []dto.SubtitleCue
+	Sequence      int
+	Cues          []dto.SubtitleCue
+	MemoryMatches []subtitleTranslationMemoryMatch
 }
 
 type subtitleTranslateResponse struct {
@@ -59,6 +60,8 @@ type subtitleTranslateOutput struct {
 	RequestHash           string                  `json:"requestHash,omitempty"`
 	PromptHash            string                  `json:"promptHash,omitempty"`
 	ResumedFromCheckpoint bool                    `json:"resumedFromCheckpoint,omitempty"`
+	MemoryReusedCueCount  int                     `json:"memoryReusedCueCount,omitempty"`
+	MemoryRecordedCount   int                     `json:"memoryRecordedCount,omitempty"`
 	Usage                 runtimedto.RuntimeUsage `json:"usage,omitempty"`
 }
 
@@ -205,9 +208,21 @@ func (service *LibraryService) runSubtitleTranslateOperation(ctx context.Context
 			return
 		}
 
-		chunkCtx, chunkCancel := context.WithTimeout(runCtx, subtitleTranslateChunkTimeout)
-		translatedItems, usage, attemptsUsed, err := service.translateSubtitleChunk(chunkCtx, request, chunk, constraints, runtimeConfig)
-		chunkCancel()
+		memoryPlan := service.planSubtitleTranslationMemory(runCtx, request, chunk)
+		var (
+			translatedItems []subtitleTranslateItem
+			usage           runtimedto.RuntimeUsage
+			attemptsUsed    int
+			err             error
+		)
+		if len(memoryPlan.Pending.Cues) > 0 {
+			chunkCtx, chunkCancel := context.WithTimeout(runCtx, subtitleTranslateChunkTimeout)
+			translatedItems, usage, attemptsUsed, err = service.translateSubtitleChunk(chunkCtx, request, memoryPlan.Pending, constraints, runtimeConfig)
+			chunkCancel()
+		}
+		if err == nil {
+			translatedItems, err = memoryPlan.mergeItems(chunk, translatedItems)
+		}
 		totalUsage = addRuntimeUsage(totalUsage, usage)
 		finishedAt := service.now()
 		if err != nil {
@@ -266,6 +281,8 @@ func (service *LibraryService) runSubtitleTranslateOperation(ctx context.Context
 		}
 
 		runState.CompletedChunkCount++
+		runState.MemoryReusedCueCount += len(memoryPlan.Reused)
+		service.markTranslationMemoryUsed(ctx, memoryPlan.UsedEntryIDs)
 		translatedCues = appendChunkItemsAsCues(translatedCues, chunk, translatedItems)
 		if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
 			_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
@@ -342,6 +359,19 @@ func (service *LibraryService) runSubtitleTranslateOperation(ctx context.Context
 		return
 	}
 
+	if !request.DisableTranslationMemory {
+		runState.MemoryRecordedCount = service.recordTranslationMemory(
+			ctx,
+			targetLanguage,
+			"translate",
+			sourceFile.LibraryID,
+			translatedFile.ID,
+			operation.ID,
+			sourceDocument.Cues,
+			translatedCues,
+		)
+	}
+
 	operation.Status = library.OperationStatusSucceeded
 	operation.FinishedAt = &finishedAt
 	operation.OutputFiles = []library.OperationOutputFile{{
@@ -528,6 +558,11 @@ func buildSubtitleTranslatePrompts(
 		systemBuilder.WriteString(promptText)
 		systemBuilder.WriteString("\n")
 	}
+	if memoryText := renderTranslationMemoryPrompt(chunk); memoryText != "" {
+		systemBuilder.WriteString("\nTranslation memory:\n")
+		systemBuilder.WriteString(memoryText)
+		systemBuilder.WriteString("\n")
+	}
 	if inlinePrompt := strings.TrimSpace(constraints.InlinePrompt); inlinePrompt != "" {
 		systemBuilder.WriteString("\nInline task note:\n")
 		systemBuilder.WriteString(inlinePrompt)
@@ -554,6 +589,11 @@ func buildSubtitleTranslatePrompts(
 		userBuilder.WriteString(marshalJSON(map[string]any{"tracks": referencePayload}))
 		userBuilder.WriteString("\n")
 	}
+	if memoryPayload := buildTranslationMemoryPromptPayload(chunk); len(memoryPayload) > 0 {
+		userBuilder.WriteString("Translation memory matches (previously accepted translations of similar cues):\n")
+		userBuilder.WriteString(marshalJSON(map[string]any{"matches": memoryPayload}))
+		userBuilder.WriteString("\n")
+	}
 	userBuilder.WriteString("Input JSON:\n")
 	userBuilder.WriteString(marshalJSON(map[string]any{"items": payload}))
 	return strings.TrimSpace(systemBuilder.String()), strings.TrimSpace(userBuilder.String())
@@ -890,6 +930,8 @@ func buildSubtitleTranslateOutput(
 		RequestHash:           strings.TrimSpace(runState.RequestHash),
 		PromptHash:            strings.TrimSpace(runState.PromptHash),
 		ResumedFromCheckpoint: runState.ResumedFromCheckpoint,
+		MemoryReusedCueCount:  runState.MemoryReusedCueCount,
+		MemoryRecordedCount:   runState.MemoryRecordedCount,
 		Usage:                 usage,
 	}
 }
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	subtitleTranslationMemoryMinFuzzyLength   = 8
+	subtitleTranslationMemoryMinSimilarity    = 0.75
+	subtitleTranslationMemoryCandidateLimit   = 500
+	subtitleTranslationMemoryMaxPromptMatches = 8
+)
+
+type subtitleTranslationMemoryMatch struct {
+	Index      int
+	SourceText string
+	TargetText string
+	Similarity float64
+}
+
+// subtitleTranslationMemoryPlan splits one chunk into cues answered verbatim by the
+// memory and the pending sub-chunk that still needs a model call.
+type subtitleTranslationMemoryPlan struct {
+	Reused       map[int]string
+	Pending      subtitleTranslateChunk
+	UsedEntryIDs []string
+}
+
+func (plan subtitleTranslationMemoryPlan) mergeItems(chunk subtitleTranslateChunk, modelItems []subtitleTranslateItem) ([]subtitleTranslateItem, error) {
+	modelTextByIndex := make(map[int]string, len(modelItems))
+	for _, item := range modelItems {
+		modelTextByIndex[item.Index] = item.Text
+	}
+	items := make([]subtitleTranslateItem, 0, len(chunk.Cues))
+	for _, cue := range chunk.Cues {
+		if text, ok := plan.Reused[cue.Index]; ok {
+			items = append(items, subtitleTranslateItem{Index: cue.Index, Text: text})
+			continue
+		}
+		text, ok := modelTextByIndex[cue.Index]
+		if !ok {
+			return nil, fmt.Errorf("missing translated cue %d", cue.Index)
+		}
+		items = append(items, subtitleTranslateItem{Index: cue.Index, Text: text})
+	}
+	return items, nil
+}
+
+func (service *LibraryService) planSubtitleTranslationMemory(
+	ctx context.Context,
+	request dto.SubtitleTranslateRequest,
+	chunk subtitleTranslateChunk,
+) subtitleTranslationMemoryPlan {
+	plan := subtitleTranslationMemoryPlan{Pending: chunk}
+	if service == nil || service.memory == nil || request.DisableTranslationMemory {
+		return plan
+	}
+	targetLanguage := request.TargetLanguage
+	keys := make([]string, 0, len(chunk.Cues))
+	for _, cue := range chunk.Cues {
+		if key := library.NormalizeTranslationMemoryKey(cue.Text); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	exactEntries, err := service.memory.FindBySourceKeys(ctx, targetLanguage, keys)
+	if err != nil {
+		return plan
+	}
+	entryByKey := make(map[string]library.TranslationMemoryEntry, len(exactEntries))
+	for _, entry := range exactEntries {
+		entryByKey[entry.SourceKey] = entry
+	}
+	plan.Reused = make(map[int]string)
+	pendingCues := make([]dto.SubtitleCue, 0, len(chunk.Cues))
+	usedIDs := make(map[string]struct{})
+	for _, cue := range chunk.Cues {
+		entry, ok := entryByKey[library.NormalizeTranslationMemoryKey(cue.Text)]
+		if !ok {
+			pendingCues = append(pendingCues, cue)
+			continue
+		}
+		plan.Reused[cue.Index] = entry.TargetText
+		if _, seen := usedIDs[entry.ID]; !seen {
+			usedIDs[entry.ID] = struct{}{}
+			plan.UsedEntryIDs = append(plan.UsedEntryIDs, entry.ID)
+		}
+	}
+	plan.Pending = subtitleTranslateChunk{Sequence: chunk.Sequence, Cues: pendingCues}
+	if len(pendingCues) == 0 {
+		return plan
+	}
+
+	minLength, maxLength := 0, 0
+	for _, cue := range pendingCues {
+		length := len([]rune(library.NormalizeTranslationMemoryKey(cue.Text)))
+		if length < subtitleTranslationMemoryMinFuzzyLength {
+			continue
+		}
+		if minLength == 0 || length < minLength {
+			minLength = length
+		}
+		if length > maxLength {
+			maxLength = length
+		}
+	}
+	if maxLength == 0 {
+		return plan
+	}
+	candidates, err := service.memory.ListCandidates(
+		ctx,
+		targetLanguage,
+		int(float64(minLength)*subtitleTranslationMemoryMinSimilarity),
+		int(float64(maxLength)/subtitleTranslationMemoryMinSimilarity)+1,
+		subtitleTranslationMemoryCandidateLimit,
+	)
+	if err != nil {
+		return plan
+	}
+	plan.Pending.MemoryMatches = findSubtitleTranslationMemoryMatches(pendingCues, candidates)
+	return plan
+}
+
+// findSubtitleTranslationMemoryMatches picks the closest candidate for each cue and
+// keeps the strongest matches so the prompt stays small.
+func findSubtitleTranslationMemoryMatches(cues []dto.SubtitleCue, candidates []library.TranslationMemoryEntry) []subtitleTranslationMemoryMatch {
+	if len(cues) == 0 || len(candidates) == 0 {
+		return nil
+	}
+	matches := make([]subtitleTranslationMemoryMatch, 0)
+	for _, cue := range cues {
+		key := library.NormalizeTranslationMemoryKey(cue.Text)
+		if len([]rune(key)) < subtitleTranslationMemoryMinFuzzyLength {
+			continue
+		}
+		best := subtitleTranslationMemoryMatch{}
+		for _, candidate := range candidates {
+			if candidate.SourceKey == key {
+				continue
+			}
+			similarity := subtitleTextSimilarity(key, candidate.SourceKey)
+			if similarity < subtitleTranslationMemoryMinSimilarity || similarity <= best.Similarity {
+				continue
+			}
+			best = subtitleTranslationMemoryMatch{
+				Index:      cue.Index,
+				SourceText: candidate.SourceText,
+				TargetText: candidate.TargetText,
+				Similarity: similarity,
+			}
+		}
+		if best.Similarity > 0 {
+			matches = append(matches, best)
+		}
+	}
+	if len(matches) > subtitleTranslationMemoryMaxPromptMatches {
+		sort.SliceStable(matches, func(left int, right int) bool {
+			return matches[left].Similarity > matches[right].Similarity
+		})
+		matches = matches[:subtitleTranslationMemoryMaxPromptMatches]
+	}
+	return matches
+}
+
+// subtitleTextSimilarity returns 1 minus the normalized rune-level edit distance.
+func subtitleTextSimilarity(left string, right string) float64 {
+	leftRunes := []rune(left)
+	rightRunes := []rune(right)
+	longest := max(len(leftRunes), len(rightRunes))
+	if longest == 0 {
+		return 1
+	}
+	previous := make([]int, len(rightRunes)+1)
+	current := make([]int, len(rightRunes)+1)
+	for column := range previous {
+		previous[column] = column
+	}
+	for row := 1; row <= len(leftRunes); row++ {
+		current[0] = row
+		for column := 1; column <= len(rightRunes); column++ {
+			cost := 1
+			if leftRunes[row-1] == rightRunes[column-1] {
+				cost = 0
+			}
+			current[column] = min(previous[column]+1, current[column-1]+1, previous[column-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(rightRunes)])/float64(longest)
+}
+
+func renderTranslationMemoryPrompt(chunk subtitleTranslateChunk) string {
+	if len(chunk.MemoryMatches) == 0 {
+		return ""
+	}
+	return strings.Join([]string{
+		"- Translation memory lists previously accepted translations of similar cues.",
+		"- Reuse their terminology, names, and phrasing where the source wording matches; translate any differences faithfully.",
+	}, "\n")
+}
+
+func buildTranslationMemoryPromptPayload(chunk subtitleTranslateChunk) []map[string]any {
+	if len(chunk.MemoryMatches) == 0 {
+		return nil
+	}
+	items := make([]map[string]any, 0, len(chunk.MemoryMatches))
+	for _, match := range chunk.MemoryMatches {
+		items = append(items, map[string]any{
+			"index":      match.Index,
+			"source":     match.SourceText,
+			"target":     match.TargetText,
+			"similarity": fmt.Sprintf("%.2f", match.Similarity),
+		})
+	}
+	return items
+}
+
+func (service *LibraryService) markTranslationMemoryUsed(ctx context.Context, ids []string) {
+	if service == nil || service.memory == nil || len(ids) == 0 {
+		return
+	}
+	_ = service.memory.MarkUsed(ctx, ids, service.now())
+}
+
+// recordTranslationMemory stores accepted cue pairs. It is best effort: a failure
+// to update the memory never fails the job or the review that produced the pairs.
+func (service *LibraryService) recordTranslationMemory(
+	ctx context.Context,
+	targetLanguage string,
+	originKind string,
+	libraryID string,
+	fileID string,
+	operationID string,
+	sourceCues []dto.SubtitleCue,
+	targetCues []dto.SubtitleCue,
+) int {
+	if service == nil || service.memory == nil {
+		return 0
+	}
+	targetByTiming := make(map[[2]int64]string, len(targetCues))
+	for _, cue := range targetCues {
+		targetByTiming[[2]int64{cueStartMS(cue), cueEndMS(cue)}] = cue.Text
+	}
+	now := service.now()
+	recorded := 0
+	seen := make(map[string]struct{}, len(sourceCues))
+	for _, cue := range sourceCues {
+		targetText, ok := targetByTiming[[2]int64{cueStartMS(cue), cueEndMS(cue)}]
+		if !ok || strings.TrimSpace(targetText) == "" {
+			continue
+		}
+		key := library.NormalizeTranslationMemoryKey(cue.Text)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		entry, err := library.NewTranslationMemoryEntry(library.TranslationMemoryEntryParams{
+			ID:             uuid.NewString(),
+			TargetLanguage: targetLanguage,
+			SourceText:     cue.Text,
+			TargetText:     targetText,
+			OriginKind:     originKind,
+			LibraryID:      libraryID,
+			FileID:         fileID,
+			OperationID:    operationID,
+			CreatedAt:      &now,
+			UpdatedAt:      &now,
+		})
+		if err != nil {
+			continue
+		}
+		if err := service.memory.Save(ctx, entry); err != nil {
+			return recorded
+		}
+		recorded++
+	}
+	return recorded
+}
+
+// recordReviewedTranslationMemory feeds reviewed cues of a translated subtitle back
+// into the memory, pairing them with the translation source by cue timing.
+func (service *LibraryService) recordReviewedTranslationMemory(
+	ctx context.Context,
+	file library.LibraryFile,
+	operationID string,
+	appliedCues []dto.SubtitleCue,
+) {
+	if service == nil || service.memory == nil || service.operations == nil {
+		return
+	}
+	if strings.TrimSpace(file.Origin.Kind) != "subtitle_translate" || strings.TrimSpace(file.Origin.OperationID) == "" {
+		return
+	}
+	origin, err := service.operations.Get(ctx, file.Origin.OperationID)
+	if err != nil {
+		return
+	}
+	request := extractSubtitleTranslateRequest(origin.InputJSON)
+	if strings.TrimSpace(request.TargetLanguage) == "" || request.DisableTranslationMemory {
+		return
+	}
+	sourceFile, sourceDocument, err := service.resolveSubtitleFileAndDocument(ctx, request.FileID, request.DocumentID, request.Path)
+	if err != nil {
+		return
+	}
+	sourceContent := strings.TrimSpace(sourceDocument.WorkingContent)
+	if sourceContent == "" {
+		sourceContent = strings.TrimSpace(sourceDocument.OriginalContent)
+	}
+	parsed := parseSubtitleDocument(sourceContent, detectSubtitleFormat(sourceDocument.Format, sourceFile.Storage.LocalPath, sourceDocument.Format))
+	service.recordTranslationMemory(ctx, request.TargetLanguage, "review_apply", file.LibraryID, file.ID, operationID, parsed.Cues, appliedCues)
+}
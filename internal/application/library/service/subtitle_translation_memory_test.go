@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+type translationMemoryRepoStub struct {
+	entries []library.TranslationMemoryEntry
+	saved   []library.TranslationMemoryEntry
+	usedIDs []string
+}
+
+func (stub *translationMemoryRepoStub) FindBySourceKeys(_ context.Context, targetLanguage string, sourceKeys []string) ([]library.TranslationMemoryEntry, error) {
+	keys := make(map[string]struct{}, len(sourceKeys))
+	for _, key := range sourceKeys {
+		keys[key] = struct{}{}
+	}
+	result := make([]library.TranslationMemoryEntry, 0)
+	for _, entry := range stub.entries {
+		if _, ok := keys[entry.SourceKey]; ok && entry.TargetLanguage == library.NormalizeTranslationMemoryLanguage(targetLanguage) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (stub *translationMemoryRepoStub) ListCandidates(_ context.Context, targetLanguage string, minSourceLength int, maxSourceLength int, _ int) ([]library.TranslationMemoryEntry, error) {
+	result := make([]library.TranslationMemoryEntry, 0)
+	for _, entry := range stub.entries {
+		if entry.TargetLanguage == library.NormalizeTranslationMemoryLanguage(targetLanguage) &&
+			entry.SourceLength >= minSourceLength && entry.SourceLength <= maxSourceLength {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (stub *translationMemoryRepoStub) Save(_ context.Context, item library.TranslationMemoryEntry) error {
+	stub.saved = append(stub.saved, item)
+	return nil
+}
+
+func (stub *translationMemoryRepoStub) MarkUsed(_ context.Context, ids []string, _ time.Time) error {
+	stub.usedIDs = append(stub.usedIDs, ids...)
+	return nil
+}
+
+func mustNewTranslationMemoryEntry(t *testing.T, id string, source string, target string) library.TranslationMemoryEntry {
+	t.Helper()
+	entry, err := library.NewTranslationMemoryEntry(library.TranslationMemoryEntryParams{
+		ID:             id,
+		TargetLanguage: "zh-CN",
+		SourceText:     source,
+		TargetText:     target,
+		OriginKind:     "translate",
+	})
+	if err != nil {
+		t.Fatalf("new translation memory entry: %v", err)
+	}
+	return entry
+}
+
+func TestPlanSubtitleTranslationMemoryReusesExactAndSuggestsFuzzyMatches(t *testing.T) {
+	t.Parallel()
+
+	memory := &translationMemoryRepoStub{entries: []library.TranslationMemoryEntry{
+		mustNewTranslationMemoryEntry(t, "tm-1", "Previously on\nDreamCreator", "前情提要"),
+		mustNewTranslationMemoryEntry(t, "tm-2", "We need to find the captain now.", "我们现在得找到船长。"),
+	}}
+	service := &LibraryService{memory: memory}
+	chunk := subtitleTranslateChunk{
+		Sequence: 1,
+		Cues: []dto.SubtitleCue{
+			{Index: 1, Text: "previously on  DreamCreator"},
+			{Index: 2, Text: "We need to find the captain."},
+			{Index: 3, Text: "Run!"},
+		},
+	}
+
+	plan := service.planSubtitleTranslationMemory(context.Background(), dto.SubtitleTranslateRequest{TargetLanguage: "zh-CN"}, chunk)
+	if plan.Reused[1] != "前情提要" || len(plan.Reused) != 1 {
+		t.Fatalf("expected exact reuse of cue 1, got %#v", plan.Reused)
+	}
+	if len(plan.Pending.Cues) != 2 || plan.Pending.Cues[0].Index != 2 {
+		t.Fatalf("expected cues 2 and 3 to remain pending, got %#v", plan.Pending.Cues)
+	}
+	if len(plan.Pending.MemoryMatches) != 1 || plan.Pending.MemoryMatches[0].Index != 2 || plan.Pending.MemoryMatches[0].TargetText != "我们现在得找到船长。" {
+		t.Fatalf("expected a fuzzy match for cue 2, got %#v", plan.Pending.MemoryMatches)
+	}
+	if len(plan.UsedEntryIDs) != 1 || plan.UsedEntryIDs[0] != "tm-1" {
+		t.Fatalf("expected tm-1 to be marked used, got %#v", plan.UsedEntryIDs)
+	}
+
+	items, err := plan.mergeItems(chunk, []subtitleTranslateItem{{Index: 2, Text: "我们得找到船长。"}, {Index: 3, Text: "快跑！"}})
+	if err != nil {
+		t.Fatalf("merge items: %v", err)
+	}
+	if len(items) != 3 || items[0].Text != "前情提要" || items[2].Text != "快跑！" {
+		t.Fatalf("unexpected merged items %#v", items)
+	}
+	if _, err := plan.mergeItems(chunk, nil); err == nil {
+		t.Fatalf("expected missing model items to fail the merge")
+	}
+
+	disabled := service.planSubtitleTranslationMemory(context.Background(), dto.SubtitleTranslateRequest{TargetLanguage: "zh-CN", DisableTranslationMemory: true}, chunk)
+	if len(disabled.Reused) != 0 || len(disabled.Pending.Cues) != 3 {
+		t.Fatalf("expected disabled memory to leave the chunk untouched, got %#v", disabled)
+	}
+}
+
+func TestBuildSubtitleTranslatePromptsIncludesTranslationMemory(t *testing.T) {
+	t.Parallel()
+
+	chunk := subtitleTranslateChunk{
+		Sequence: 1,
+		Cues:     []dto.SubtitleCue{{Index: 2, Text: "We need to find the captain."}},
+		MemoryMatches: []subtitleTranslationMemoryMatch{{
+			Index:      2,
+			SourceText: "We need to find the captain now.",
+			TargetText: "我们现在得找到船长。",
+			Similarity: 0.88,
+		}},
+	}
+	systemPrompt, userPrompt := buildSubtitleTranslatePrompts(dto.SubtitleTranslateRequest{TargetLanguage: "zh-CN"}, chunk, subtitleTranslateConstraints{}, "")
+	if !strings.Contains(systemPrompt, "Translation memory:") {
+		t.Fatalf("expected translation memory guidance\n%s", systemPrompt)
+	}
+	for _, needle := range []string{"Translation memory matches", `"target":"我们现在得找到船长。"`, `"similarity":"0.88"`} {
+		if !strings.Contains(userPrompt, needle) {
+			t.Fatalf("expected user prompt to contain %q\n%s", needle, userPrompt)
+		}
+	}
+
+	chunk.MemoryMatches = nil
+	systemPrompt, userPrompt = buildSubtitleTranslatePrompts(dto.SubtitleTranslateRequest{TargetLanguage: "zh-CN"}, chunk, subtitleTranslateConstraints{}, "")
+	if strings.Contains(systemPrompt, "Translation memory") || strings.Contains(userPrompt, "Translation memory") {
+		t.Fatalf("did not expect translation memory sections without matches")
+	}
+}
+
+func TestRecordTranslationMemoryPairsCuesByTiming(t *testing.T) {
+	t.Parallel()
+
+	memory := &translationMemoryRepoStub{}
+	service := &LibraryService{memory: memory, nowFunc: func() time.Time { return time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC) }}
+	source := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "Hello."},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "hello."},
+		{Index: 3, Start: "00:00:05,000", End: "00:00:06,000", Text: "Dropped by review"},
+	}
+	target := []dto.SubtitleCue{
+		{Index: 1, Start: "00:00:01,000", End: "00:00:02,000", Text: "你好。"},
+		{Index: 2, Start: "00:00:03,000", End: "00:00:04,000", Text: "你好。"},
+	}
+
+	recorded := service.recordTranslationMemory(context.Background(), "zh-CN", "review_apply", "lib-1", "file-1", "op-1", source, target)
+	if recorded != 1 || len(memory.saved) != 1 {
+		t.Fatalf("expected one deduplicated entry, got %d %#v", recorded, memory.saved)
+	}
+	if memory.saved[0].SourceKey != "hello." || memory.saved[0].OriginKind != "review_apply" || memory.saved[0].TargetLanguage != "zh-cn" {
+		t.Fatalf("unexpected recorded entry %#v", memory.saved[0])
+	}
+}
+
+func TestSubtitleTextSimilarity(t *testing.T) {
+	t.Parallel()
+
+	if got := subtitleTextSimilarity("kitten", "sitting"); got < 0.57 || got > 0.58 {
+		t.Fatalf("expected kitten/sitting similarity near 0.571, got %f", got)
+	}
+	if got := subtitleTextSimilarity("船长来了", "船长来了"); got != 1 {
+		t.Fatalf("expected identical strings to match fully, got %f", got)
+	}
+}
@@ -0,0 +1,694 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	defaultThumbnailCount          = 9
+	maxThumbnailCount              = 24
+	defaultThumbnailSceneThreshold = 0.3
+	thumbnailSceneProbeWidth       = 320
+	contactSheetTileWidth          = 320
+)
+
+var showinfoPTSTimePattern = regexp.MustCompile(`pts_time:\s*([0-9]+(?:\.[0-9]+)?)`)
+
+type thumbnailOperationOutput struct {
+	FileID             string   `json:"fileId,omitempty"`
+	Count              int      `json:"count,omitempty"`
+	SceneCount         int      `json:"sceneCount,omitempty"`
+	FrameFileIDs       []string `json:"frameFileIds,omitempty"`
+	ContactSheetFileID string   `json:"contactSheetFileId,omitempty"`
+	CoverFileID        string   `json:"coverFileId,omitempty"`
+	Status             string   `json:"status"`
+}
+
+// CreateThumbnailJob queues the extraction of candidate thumbnails at scene changes,
+// a contact sheet built from them and the selection of the file cover.
+func (service *LibraryService) CreateThumbnailJob(ctx context.Context, request dto.CreateThumbnailJobRequest) (dto.LibraryOperationDTO, error) {
+	request, err := normalizeThumbnailRequest(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	sourceFile, err := service.resolveThumbnailSourceFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	now := service.now()
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Kind:        "thumbnail_generate",
+		Status:      string(library.OperationStatusQueued),
+		DisplayName: sourceFile.Name,
+		Correlation: library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:   string(inputJSON),
+		OutputJSON:  buildThumbnailOperationOutput(thumbnailOperationOutput{FileID: sourceFile.ID, Status: "queued"}, request),
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			1,
+			progressText("library.progressDetail.thumbnailGenerateQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runThumbnailOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+func (service *LibraryService) runThumbnailOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateThumbnailJobRequest) {
+	request, err := normalizeThumbnailRequest(request)
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		service.unregisterOperationRun(operation.ID)
+		cancel()
+	}()
+
+	output := thumbnailOperationOutput{FileID: request.FileID, Status: "running"}
+	now := service.now()
+	operation.Status = library.OperationStatusRunning
+	operation.StartedAt = &now
+	operation.FinishedAt = nil
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.Progress = buildOperationProgress(
+		now,
+		progressText("library.progress.preparing"),
+		0,
+		1,
+		progressText("library.progressDetail.thumbnailGeneratePreparing"),
+	)
+	operation.OutputJSON = buildThumbnailOperationOutput(output, request)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+
+	sourceFile, err := service.resolveThumbnailSourceFile(ctx, request.FileID)
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	probe, err := service.probeRequiredMedia(ctx, sourceFile.Storage.LocalPath)
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	if strings.TrimSpace(probe.VideoCodec) == "" && probe.Width <= 0 {
+		service.failThumbnailOperation(ctx, operation, request, fmt.Errorf("file %q has no video stream", sourceFile.Name))
+		return
+	}
+	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	tempDir, err := os.MkdirTemp("", "dreamcreator-thumbnail-*")
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	reporter := newFFmpegProgressReporter(service, &operation, probe.DurationMs)
+	reporter.stage = progressText("library.progress.detectingScenes")
+	reporter.message = progressText("library.progressDetail.thumbnailGenerateDetectingScenes")
+	sceneOutput, err := service.runFFmpegCommandWithReporter(
+		runCtx,
+		reporter,
+		ffmpegExecPath,
+		buildFFmpegSceneDetectArgs(sourceFile.Storage.LocalPath, request.SceneThreshold),
+		tempDir,
+	)
+	if err != nil {
+		service.failThumbnailFFmpegStep(ctx, runCtx, operation, request, "ffmpeg scene detection failed", sceneOutput, err)
+		return
+	}
+	scenes := parseShowinfoTimestamps(sceneOutput)
+	output.SceneCount = len(scenes)
+	timestamps := chooseThumbnailTimestamps(scenes, probe.DurationMs, request.Count)
+
+	framePaths := make([]string, 0, len(timestamps))
+	for index, timestampMs := range timestamps {
+		operation.Progress = buildOperationProgress(
+			service.now(),
+			progressText("library.progress.extractingFrames"),
+			index,
+			len(timestamps),
+			progressTextTemplate("library.progressDetail.thumbnailGenerateExtractingFrame", map[string]string{
+				"current": strconv.Itoa(index + 1),
+				"total":   strconv.Itoa(len(timestamps)),
+			}),
+		)
+		if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+			return
+		}
+		framePath := filepath.Join(tempDir, fmt.Sprintf("frame_%02d.jpg", index+1))
+		outputText, err := service.runFFmpegCommandWithReporter(
+			runCtx,
+			nil,
+			ffmpegExecPath,
+			buildFFmpegThumbnailFrameArgs(sourceFile.Storage.LocalPath, timestampMs, framePath),
+			tempDir,
+		)
+		if err != nil {
+			service.failThumbnailFFmpegStep(ctx, runCtx, operation, request, "ffmpeg frame extraction failed", outputText, err)
+			return
+		}
+		if !pathExists(framePath) {
+			// Seeking past the last decodable frame yields no image; the remaining
+			// candidates still make a usable set.
+			break
+		}
+		framePaths = append(framePaths, framePath)
+	}
+	if len(framePaths) == 0 {
+		service.failThumbnailOperation(ctx, operation, request, fmt.Errorf("ffmpeg produced no thumbnail frames"))
+		return
+	}
+
+	operation.Progress = buildOperationProgress(
+		service.now(),
+		progressText("library.progress.buildingContactSheet"),
+		len(framePaths),
+		len(framePaths),
+		progressText("library.progressDetail.thumbnailGenerateBuildingContactSheet"),
+	)
+	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
+		return
+	}
+	sheetPath := filepath.Join(tempDir, "contact_sheet.jpg")
+	sheetOutput, err := service.runFFmpegCommandWithReporter(
+		runCtx,
+		nil,
+		ffmpegExecPath,
+		buildFFmpegContactSheetArgs(filepath.Join(tempDir, "frame_%02d.jpg"), len(framePaths), request.Columns, sheetPath),
+		tempDir,
+	)
+	if err != nil {
+		service.failThumbnailFFmpegStep(ctx, runCtx, operation, request, "ffmpeg contact sheet failed", sheetOutput, err)
+		return
+	}
+
+	finishedAt := service.now()
+	baseName := strings.TrimSpace(strings.TrimSuffix(sourceFile.Name, filepath.Ext(sourceFile.Name)))
+	if baseName == "" {
+		baseName = "Thumbnail"
+	}
+	frameFiles := make([]library.LibraryFile, 0, len(framePaths))
+	for index, framePath := range framePaths {
+		name := fmt.Sprintf("%s (frame %s)", baseName, formatThumbnailTimestamp(timestamps[index]))
+		frameFile, err := service.registerThumbnailOutput(ctx, operation, sourceFile, name, fmt.Sprintf("%s-%02d", operation.ID[:8], index+1), framePath, finishedAt)
+		if err != nil {
+			service.failThumbnailOperation(ctx, operation, request, err)
+			return
+		}
+		frameFiles = append(frameFiles, frameFile)
+		output.FrameFileIDs = append(output.FrameFileIDs, frameFile.ID)
+	}
+	sheetFile, err := service.registerThumbnailOutput(ctx, operation, sourceFile, baseName+" (contact sheet)", operation.ID[:8]+"-sheet", sheetPath, finishedAt)
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	output.ContactSheetFileID = sheetFile.ID
+
+	cover := frameFiles[selectThumbnailCoverIndex(frameFiles, request.CoverIndex)]
+	if sourceFile.Media == nil {
+		sourceFile.Media = &library.MediaInfo{}
+	}
+	sourceFile.Media.CoverFileID = cover.ID
+	output.CoverFileID = cover.ID
+	output.Count = len(frameFiles)
+
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   sourceFile.LibraryID,
+		Category:    "operation",
+		Action:      "thumbnail_generate",
+		DisplayName: sourceFile.Name,
+		Status:      string(library.OperationStatusSucceeded),
+		Source:      library.HistoryRecordSource{Kind: resolveHistorySourceKind(request.Source), RunID: request.RunID},
+		OccurredAt:  &finishedAt,
+		CreatedAt:   &finishedAt,
+		UpdatedAt:   &finishedAt,
+	})
+	if err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	output.Status = "completed"
+	outputFiles := append(append([]library.LibraryFile{cover}, excludeLibraryFile(frameFiles, cover.ID)...), sheetFile)
+	service.completeThumbnailOperation(ctx, operation, request, history, outputFiles, sourceFile, output, finishedAt)
+}
+
+// registerThumbnailOutput moves a rendered image into the library directory and
+// registers it as a thumbnail derived from the source file.
+func (service *LibraryService) registerThumbnailOutput(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	sourceFile library.LibraryFile,
+	name string,
+	suffix string,
+	renderedPath string,
+	occurredAt time.Time,
+) (library.LibraryFile, error) {
+	outputPath, err := service.deriveManagedOutputPath(ctx, sourceFile.LibraryID, sanitizeFileName(name)+"-"+suffix, "jpg", renderedPath)
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if err := ensureManagedOutputParentDir(outputPath); err != nil {
+		return library.LibraryFile{}, err
+	}
+	if err := moveFileWithCopyFallback(renderedPath, outputPath); err != nil {
+		return library.LibraryFile{}, err
+	}
+	return service.registerManagedLocalOutputFile(ctx, registeredLocalOutputParams{
+		LibraryID:     sourceFile.LibraryID,
+		RootFileID:    rootFileID(sourceFile),
+		SourceFileIDs: []string{sourceFile.ID},
+		Name:          name,
+		Kind:          string(library.FileKindThumbnail),
+		OperationID:   operation.ID,
+		OperationKind: "thumbnail_generate",
+		OutputPath:    outputPath,
+		OccurredAt:    occurredAt,
+	})
+}
+
+func (service *LibraryService) completeThumbnailOperation(
+	ctx context.Context,
+	operation library.LibraryOperation,
+	request dto.CreateThumbnailJobRequest,
+	history library.HistoryRecord,
+	outputFiles []library.LibraryFile,
+	sourceFile library.LibraryFile,
+	output thumbnailOperationOutput,
+	finishedAt time.Time,
+) {
+	savedFiles := append(append([]library.LibraryFile(nil), outputFiles...), sourceFile)
+	for index := range savedFiles {
+		savedFiles[index].LatestOperationID = operation.ID
+		savedFiles[index].UpdatedAt = finishedAt
+		if err := service.files.Save(ctx, savedFiles[index]); err != nil {
+			service.failThumbnailOperation(ctx, operation, request, err)
+			return
+		}
+	}
+
+	operation.Status = library.OperationStatusSucceeded
+	operation.FinishedAt = &finishedAt
+	operation.ErrorCode = ""
+	operation.ErrorMessage = ""
+	operation.OutputFiles = make([]library.OperationOutputFile, 0, len(outputFiles))
+	for index, item := range outputFiles {
+		operation.OutputFiles = append(operation.OutputFiles, library.OperationOutputFile{
+			FileID:    item.ID,
+			Kind:      string(item.Kind),
+			Format:    mediaFormatFromFile(item),
+			SizeBytes: mediaSizeFromFile(item),
+			IsPrimary: index == 0,
+			Deleted:   item.State.Deleted,
+		})
+	}
+	operation.Metrics = buildOperationMetricsForOperation(outputFiles, operation.StartedAt, &finishedAt)
+	operation.Progress = buildOperationProgress(
+		finishedAt,
+		progressText("library.status.succeeded"),
+		1,
+		1,
+		progressText("library.progressDetail.thumbnailGenerateCompleted"),
+	)
+	operation.OutputJSON = buildThumbnailOperationOutput(output, request)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+
+	history.Refs = library.HistoryRecordRefs{OperationID: operation.ID, FileIDs: extractLibraryFileIDs(outputFiles)}
+	history.OperationMeta = &library.OperationRecordMeta{Kind: "thumbnail_generate"}
+	history.Files = operation.OutputFiles
+	history.Metrics = operation.Metrics
+	if err := service.histories.Save(ctx, history); err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+	if err := service.touchLibrary(ctx, operation.LibraryID, finishedAt); err != nil {
+		service.failThumbnailOperation(ctx, operation, request, err)
+		return
+	}
+
+	service.publishOperationUpdate(toOperationDTO(operation))
+	service.publishHistoryUpdate(toHistoryDTO(history))
+	for _, item := range savedFiles {
+		service.publishFileUpdate(service.mustBuildFileDTO(ctx, item))
+	}
+	service.publishWorkspaceProjectUpdate(operation.LibraryID)
+}
+
+func (service *LibraryService) failThumbnailFFmpegStep(
+	ctx context.Context,
+	runCtx context.Context,
+	operation library.LibraryOperation,
+	request dto.CreateThumbnailJobRequest,
+	step string,
+	outputText string,
+	runErr error,
+) {
+	if runCtx.Err() != nil {
+		service.failThumbnailOperation(ctx, operation, request, context.Canceled)
+		return
+	}
+	message := strings.TrimSpace(outputText)
+	if message == "" {
+		message = runErr.Error()
+	}
+	service.failThumbnailOperation(ctx, operation, request, fmt.Errorf("%s: %s", step, message))
+}
+
+func (service *LibraryService) failThumbnailOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateThumbnailJobRequest, runErr error) {
+	if service == nil || service.operations == nil {
+		return
+	}
+	currentOperation := operation
+	if item, err := service.operations.Get(ctx, operation.ID); err == nil {
+		currentOperation = item
+	}
+	if currentOperation.Status == library.OperationStatusCanceled {
+		return
+	}
+	if errors.Is(runErr, context.Canceled) {
+		currentOperation.Status = library.OperationStatusCanceled
+		currentOperation.ErrorCode = "thumbnail_generate_canceled"
+		currentOperation.ErrorMessage = ""
+	} else {
+		currentOperation.Status = library.OperationStatusFailed
+		currentOperation.ErrorCode = "thumbnail_generate_failed"
+		currentOperation.ErrorMessage = strings.TrimSpace(runErr.Error())
+	}
+	now := service.now()
+	currentOperation.FinishedAt = &now
+	currentOperation.Progress = buildOperationProgress(
+		now,
+		progressText(progressStageLocaleKey(string(currentOperation.Status))),
+		0,
+		1,
+		terminalProgressMessage(currentOperation.Kind, currentOperation.Status),
+	)
+	currentOperation.OutputJSON = buildThumbnailOperationOutput(thumbnailOperationOutput{FileID: request.FileID, Status: string(currentOperation.Status)}, request)
+	if err := service.operations.Save(ctx, currentOperation); err != nil {
+		return
+	}
+	service.publishOperationUpdate(toOperationDTO(currentOperation))
+}
+
+// SetFileCover sets or clears the cover of a video or audio file. The cover must be
+// a thumbnail of the same library.
+func (service *LibraryService) SetFileCover(ctx context.Context, request dto.SetFileCoverRequest) (dto.LibraryFileDTO, error) {
+	fileItem, err := service.resolveChapterMediaFile(ctx, request.FileID)
+	if err != nil {
+		return dto.LibraryFileDTO{}, err
+	}
+	coverFileID := strings.TrimSpace(request.CoverFileID)
+	if coverFileID != "" {
+		cover, err := service.files.Get(ctx, coverFileID)
+		if err != nil {
+			return dto.LibraryFileDTO{}, err
+		}
+		if err := validateCoverFile(fileItem, cover); err != nil {
+			return dto.LibraryFileDTO{}, err
+		}
+	}
+	if fileItem.Media == nil {
+		fileItem.Media = &library.MediaInfo{}
+	}
+	fileItem.Media.CoverFileID = coverFileID
+	fileItem.UpdatedAt = service.now()
+	if err := service.files.Save(ctx, fileItem); err != nil {
+		return dto.LibraryFileDTO{}, err
+	}
+	result := service.mustBuildFileDTO(ctx, fileItem)
+	service.publishFileUpdate(result)
+	return result, nil
+}
+
+// resolveFileCoverPath returns the local image of the cover set on a file.
+func (service *LibraryService) resolveFileCoverPath(ctx context.Context, fileItem library.LibraryFile) (string, error) {
+	if fileItem.Media == nil || strings.TrimSpace(fileItem.Media.CoverFileID) == "" {
+		return "", fmt.Errorf("file %q has no cover, generate thumbnails first", fileItem.Name)
+	}
+	cover, err := service.files.Get(ctx, fileItem.Media.CoverFileID)
+	if err != nil {
+		return "", err
+	}
+	if err := validateCoverFile(fileItem, cover); err != nil {
+		return "", err
+	}
+	if !pathExists(cover.Storage.LocalPath) {
+		return "", fmt.Errorf("cover image %q is missing", cover.Name)
+	}
+	return cover.Storage.LocalPath, nil
+}
+
+func validateCoverFile(fileItem library.LibraryFile, cover library.LibraryFile) error {
+	if cover.Kind != library.FileKindThumbnail {
+		return fmt.Errorf("file %q is not a thumbnail", cover.Name)
+	}
+	if cover.State.Deleted || strings.TrimSpace(cover.Storage.LocalPath) == "" {
+		return fmt.Errorf("thumbnail %q has no local image", cover.Name)
+	}
+	if cover.LibraryID != fileItem.LibraryID {
+		return fmt.Errorf("thumbnail %q belongs to another library", cover.Name)
+	}
+	return nil
+}
+
+func (service *LibraryService) resolveThumbnailSourceFile(ctx context.Context, fileID string) (library.LibraryFile, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("fileId is required")
+	}
+	item, err := service.files.Get(ctx, strings.TrimSpace(fileID))
+	if err != nil {
+		return library.LibraryFile{}, err
+	}
+	if item.State.Deleted || strings.TrimSpace(item.Storage.LocalPath) == "" {
+		return library.LibraryFile{}, fmt.Errorf("file %q has no local media", item.Name)
+	}
+	switch item.Kind {
+	case library.FileKindVideo, library.FileKindTranscode:
+	default:
+		return library.LibraryFile{}, fmt.Errorf("file kind %q has no video frames", item.Kind)
+	}
+	if strings.TrimSpace(item.LibraryID) == "" {
+		return library.LibraryFile{}, fmt.Errorf("source file is not attached to a library")
+	}
+	return item, nil
+}
+
+// buildFFmpegSceneDetectArgs decodes a downscaled copy of the video and prints the
+// timestamps of frames whose scene score exceeds the threshold.
+func buildFFmpegSceneDetectArgs(inputPath string, threshold float64) []string {
+	filter := fmt.Sprintf("scale=%d:-2,select='gt(scene,%.3f)',showinfo", thumbnailSceneProbeWidth, threshold)
+	return []string{"-y", "-i", inputPath, "-an", "-sn", "-dn", "-vf", filter, "-f", "null", "-"}
+}
+
+func buildFFmpegThumbnailFrameArgs(inputPath string, timestampMs int64, outputPath string) []string {
+	return []string{
+		"-y",
+		"-ss", formatFFmpegSeconds(timestampMs),
+		"-i", inputPath,
+		"-an", "-sn", "-dn",
+		"-frames:v", "1",
+		"-q:v", "2",
+		outputPath,
+	}
+}
+
+// buildFFmpegContactSheetArgs tiles the numbered frames into one image. Columns
+// default to a square-ish grid.
+func buildFFmpegContactSheetArgs(framePattern string, frameCount int, columns int, outputPath string) []string {
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(frameCount))))
+	}
+	columns = minInt(maxInt(columns, 1), maxInt(frameCount, 1))
+	rows := (frameCount + columns - 1) / columns
+	filter := fmt.Sprintf("scale=%d:-2,tile=%dx%d:padding=4:margin=4", contactSheetTileWidth, columns, rows)
+	return []string{
+		"-y",
+		"-framerate", "1",
+		"-start_number", "1",
+		"-i", framePattern,
+		"-vf", filter,
+		"-frames:v", "1",
+		"-q:v", "3",
+		outputPath,
+	}
+}
+
+// parseShowinfoTimestamps collects the frame timestamps printed by the showinfo
+// filter, in milliseconds.
+func parseShowinfoTimestamps(output string) []int64 {
+	matches := showinfoPTSTimePattern.FindAllStringSubmatch(output, -1)
+	result := make([]int64, 0, len(matches))
+	for _, match := range matches {
+		seconds, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, int64(math.Round(seconds*1000)))
+	}
+	return result
+}
+
+// chooseThumbnailTimestamps spreads the candidates over the detected scenes. Videos
+// with fewer scene changes than requested are filled with evenly spaced frames that
+// keep clear of the scenes already picked.
+func chooseThumbnailTimestamps(scenes []int64, durationMs int64, count int) []int64 {
+	if count <= 0 {
+		count = defaultThumbnailCount
+	}
+	valid := make([]int64, 0, len(scenes))
+	for _, scene := range scenes {
+		if scene < 0 || (durationMs > 0 && scene >= durationMs) {
+			continue
+		}
+		valid = append(valid, scene)
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i] < valid[j] })
+	if len(valid) >= count {
+		result := make([]int64, 0, count)
+		for index := 0; index < count; index++ {
+			result = append(result, valid[index*len(valid)/count])
+		}
+		return result
+	}
+	if durationMs <= 0 {
+		if len(valid) == 0 {
+			return []int64{0}
+		}
+		return valid
+	}
+	result := append([]int64(nil), valid...)
+	minGap := durationMs / int64(count*2)
+	for index := 1; index <= count && len(result) < count; index++ {
+		candidate := durationMs * int64(index) / int64(count+1)
+		tooClose := false
+		for _, existing := range valid {
+			if absInt64(existing-candidate) < minGap {
+				tooClose = true
+				break
+			}
+		}
+		if !tooClose {
+			result = append(result, candidate)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// selectThumbnailCoverIndex honours the requested 1-based candidate, otherwise it
+// picks the largest JPEG, which is usually the most detailed frame.
+func selectThumbnailCoverIndex(frames []library.LibraryFile, coverIndex int) int {
+	if coverIndex > 0 && coverIndex <= len(frames) {
+		return coverIndex - 1
+	}
+	selected := 0
+	var selectedSize int64 = -1
+	for index, frame := range frames {
+		size := mediaSizeFromFile(frame)
+		if size != nil && *size > selectedSize {
+			selected = index
+			selectedSize = *size
+		}
+	}
+	return selected
+}
+
+func excludeLibraryFile(files []library.LibraryFile, fileID string) []library.LibraryFile {
+	result := make([]library.LibraryFile, 0, len(files))
+	for _, item := range files {
+		if item.ID != fileID {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func formatThumbnailTimestamp(ms int64) string {
+	totalSeconds := ms / 1000
+	return fmt.Sprintf("%02d:%02d:%02d", totalSeconds/3600, (totalSeconds/60)%60, totalSeconds%60)
+}
+
+func buildThumbnailOperationOutput(output thumbnailOperationOutput, request dto.CreateThumbnailJobRequest) string {
+	if output.Count == 0 {
+		output.Count = request.Count
+	}
+	return marshalJSON(output)
+}
+
+func normalizeThumbnailRequest(request dto.CreateThumbnailJobRequest) (dto.CreateThumbnailJobRequest, error) {
+	request.FileID = strings.TrimSpace(request.FileID)
+	request.Source = strings.TrimSpace(request.Source)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.Count <= 0 {
+		request.Count = defaultThumbnailCount
+	}
+	if request.Count > maxThumbnailCount {
+		return request, fmt.Errorf("at most %d thumbnails are supported", maxThumbnailCount)
+	}
+	if request.SceneThreshold == 0 {
+		request.SceneThreshold = defaultThumbnailSceneThreshold
+	}
+	if request.SceneThreshold <= 0 || request.SceneThreshold >= 1 {
+		return request, fmt.Errorf("sceneThreshold must be between 0 and 1")
+	}
+	if request.Columns < 0 || request.Columns > request.Count {
+		return request, fmt.Errorf("columns must be between 1 and %d", request.Count)
+	}
+	if request.CoverIndex < 0 || request.CoverIndex > request.Count {
+		return request, fmt.Errorf("coverIndex must be between 1 and %d", request.Count)
+	}
+	return request, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+func TestParseShowinfoTimestamps(t *testing.T) {
+	t.Parallel()
+
+	output := strings.Join([]string{
+		"[Parsed_showinfo_2 @ 0x6000] n:   0 pts:  12012 pts_time:12.012  duration:   1001 duration_time:1.001",
+		"[Parsed_showinfo_2 @ 0x6000] n:   1 pts:  45045 pts_time:45.045  duration:   1001 duration_time:1.001",
+		"frame=    2 fps=0.0 q=-0.0 size=N/A time=00:01:00.00",
+		"[Parsed_showinfo_2 @ 0x6000] n:   2 pts: 90000 pts_time:90      duration:   1001 duration_time:1.001",
+	}, "\n")
+	timestamps := parseShowinfoTimestamps(output)
+	if !reflect.DeepEqual(timestamps, []int64{12012, 45045, 90000}) {
+		t.Fatalf("unexpected timestamps %v", timestamps)
+	}
+}
+
+func TestChooseThumbnailTimestamps(t *testing.T) {
+	t.Parallel()
+
+	scenes := []int64{1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000}
+	if picked := chooseThumbnailTimestamps(scenes, 10000, 4); !reflect.DeepEqual(picked, []int64{1000, 3000, 5000, 7000}) {
+		t.Fatalf("expected scenes to be spread evenly, got %v", picked)
+	}
+	// Evenly spaced frames fill the gaps but keep clear of the detected scene.
+	if picked := chooseThumbnailTimestamps([]int64{26000}, 100000, 3); !reflect.DeepEqual(picked, []int64{26000, 50000, 75000}) {
+		t.Fatalf("expected filler frames around the scene, got %v", picked)
+	}
+	if picked := chooseThumbnailTimestamps(nil, 0, 3); !reflect.DeepEqual(picked, []int64{0}) {
+		t.Fatalf("expected the first frame without a duration, got %v", picked)
+	}
+}
+
+func TestBuildFFmpegThumbnailArgs(t *testing.T) {
+	t.Parallel()
+
+	scene := strings.Join(buildFFmpegSceneDetectArgs("/tmp/input.mp4", 0.3), " ")
+	if scene != "-y -i /tmp/input.mp4 -an -sn -dn -vf scale=320:-2,select='gt(scene,0.300)',showinfo -f null -" {
+		t.Fatalf("unexpected scene detection args %q", scene)
+	}
+	frame := strings.Join(buildFFmpegThumbnailFrameArgs("/tmp/input.mp4", 65432, "/tmp/frame_01.jpg"), " ")
+	if frame != "-y -ss 65.432 -i /tmp/input.mp4 -an -sn -dn -frames:v 1 -q:v 2 /tmp/frame_01.jpg" {
+		t.Fatalf("unexpected frame args %q", frame)
+	}
+	sheet := strings.Join(buildFFmpegContactSheetArgs("/tmp/frame_%02d.jpg", 7, 0, "/tmp/sheet.jpg"), " ")
+	if !strings.Contains(sheet, "-vf scale=320:-2,tile=3x3:padding=4:margin=4 -frames:v 1") {
+		t.Fatalf("expected a 3x3 grid for seven frames, got %q", sheet)
+	}
+	sheet = strings.Join(buildFFmpegContactSheetArgs("/tmp/frame_%02d.jpg", 6, 6, "/tmp/sheet.jpg"), " ")
+	if !strings.Contains(sheet, "tile=6x1") {
+		t.Fatalf("expected a single row storyboard, got %q", sheet)
+	}
+}
+
+func TestSelectThumbnailCoverIndex(t *testing.T) {
+	t.Parallel()
+
+	sizes := []int64{1200, 5400, 3100}
+	frames := make([]library.LibraryFile, 0, len(sizes))
+	for _, size := range sizes {
+		value := size
+		frames = append(frames, library.LibraryFile{Media: &library.MediaInfo{SizeBytes: &value}})
+	}
+	if index := selectThumbnailCoverIndex(frames, 0); index != 1 {
+		t.Fatalf("expected the largest frame, got %d", index)
+	}
+	if index := selectThumbnailCoverIndex(frames, 3); index != 2 {
+		t.Fatalf("expected the requested frame, got %d", index)
+	}
+}
+
+func TestBuildFFmpegTranscodeArgsEmbedsCover(t *testing.T) {
+	t.Parallel()
+
+	plan := transcodePlan{
+		request: dto.CreateTranscodeJobRequest{
+			Format:     "mp4",
+			VideoCodec: "h264",
+			AudioCodec: "aac",
+			Scale:      "720p",
+		},
+		outputType: library.TranscodeOutputVideo,
+		coverPath:  "/tmp/cover.jpg",
+	}
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mp4", "", "", "", "none", "/tmp/chapters.ffmetadata")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, expected := range []string{
+		"-f ffmetadata -i /tmp/chapters.ffmetadata -i /tmp/cover.jpg -filter:v:0 scale=",
+		"-map 0:v:0? -map 0:a:0? -map 2:0 -c:v libx264 -c:v:1 copy -disposition:v:1 attached_pic",
+		"-map_chapters 1",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Fatalf("expected %q in %q", expected, joined)
+		}
+	}
+	if strings.Contains(joined, "-vf") {
+		t.Fatalf("expected the frame filter to skip the cover stream, got %q", joined)
+	}
+
+	plan.request.Format = "mkv"
+	plan.coverPath = "/tmp/cover.png"
+	args, err = buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mkv", "", "", "", "none", "")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	joined = strings.Join(args, " ")
+	if !strings.Contains(joined, "-attach /tmp/cover.png -metadata:s:t mimetype=image/png -metadata:s:t filename=cover.png /tmp/output.mkv") {
+		t.Fatalf("expected the cover to be attached to the mkv, got %q", joined)
+	}
+}
+
+func TestNormalizeThumbnailRequest(t *testing.T) {
+	t.Parallel()
+
+	request, err := normalizeThumbnailRequest(dto.CreateThumbnailJobRequest{FileID: " file-1 "})
+	if err != nil {
+		t.Fatalf("expected defaults to be valid: %v", err)
+	}
+	if request.FileID != "file-1" || request.Count != defaultThumbnailCount || request.SceneThreshold != defaultThumbnailSceneThreshold {
+		t.Fatalf("unexpected defaults %#v", request)
+	}
+	for _, invalid := range []dto.CreateThumbnailJobRequest{
+		{FileID: "file-1", Count: maxThumbnailCount + 1},
+		{FileID: "file-1", SceneThreshold: 1.5},
+		{FileID: "file-1", Count: 4, CoverIndex: 5},
+		{FileID: "file-1", Count: 4, Columns: 6},
+	} {
+		if _, err := normalizeThumbnailRequest(invalid); err == nil {
+			t.Fatalf("expected %#v to be rejected", invalid)
+		}
+	}
+}
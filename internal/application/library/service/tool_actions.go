@@ -49,6 +49,19 @@ func (service *LibraryService) HandleToolRequest(ctx context.Context, request dt
 		operation, getErr := service.GetOperation(ctx, dto.GetOperationRequest{OperationID: getString(payload, "operationId", "operationID", "id")})
 		output = operation
 		err = getErr
+	case "library.subtitle_revisions", "subtitle_revisions":
+		output, err = service.ListSubtitleRevisions(ctx, dto.ListSubtitleRevisionsRequest{
+			FileID:     getString(payload, "fileId", "fileID"),
+			DocumentID: getString(payload, "documentId", "documentID"),
+			Limit:      getInt(payload, "limit"),
+		})
+	case "library.subtitle_revision_diff", "subtitle_revision_diff":
+		output, err = service.DiffSubtitleRevisions(ctx, dto.DiffSubtitleRevisionsRequest{
+			FileID:           getString(payload, "fileId", "fileID"),
+			FromRevisionID:   getString(payload, "fromRevisionId", "fromRevisionID", "from"),
+			ToRevisionID:     getString(payload, "toRevisionId", "toRevisionID", "to"),
+			IncludeUnchanged: getBool(payload, "includeUnchanged"),
+		})
 	default:
 		err = fmt.Errorf("unsupported library action: %s", action)
 	}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	// loudnorm resamples to 192 kHz internally, so normalized audio is brought back
+	// to a sample rate every supported encoder accepts.
+	loudnormOutputSampleRate = 48000
+	noiseReductionFilter     = "afftdn=nf=-25"
+	// Trims leading silence and shortens pauses longer than a second to half a second.
+	trimSilenceFilter = "silenceremove=start_periods=1:start_duration=0.1:start_threshold=-50dB:" +
+		"stop_periods=-1:stop_duration=1:stop_threshold=-50dB:stop_silence=0.5"
+)
+
+var errLoudnessNotMeasurable = errors.New("input audio is silent, loudness cannot be measured")
+
+type loudnormMeasurement struct {
+	InputI       float64
+	InputTP      float64
+	InputLRA     float64
+	InputThresh  float64
+	OutputI      float64
+	TargetOffset float64
+}
+
+// measureTranscodeLoudness runs the first loudnorm pass. The audio goes through the
+// same pre-filters as the encode so the measured values match the second pass input.
+func (service *LibraryService) measureTranscodeLoudness(
+	ctx context.Context,
+	operation *library.LibraryOperation,
+	execPath string,
+	inputPath string,
+	workDir string,
+	processing library.TranscodeAudioProcessing,
+	durationMs int64,
+) (loudnormMeasurement, error) {
+	operation.Progress = buildOperationProgress(
+		service.now(),
+		progressText("library.progress.measuringLoudness"),
+		0,
+		1,
+		progressText("library.progressDetail.ffmpegMeasuringLoudness"),
+	)
+	if err := service.saveAndPublishOperation(ctx, *operation); err != nil {
+		return loudnormMeasurement{}, err
+	}
+	reporter := newFFmpegProgressReporter(service, operation, durationMs)
+	reporter.stage = progressText("library.progress.measuringLoudness")
+	reporter.message = progressText("library.progressDetail.ffmpegMeasuringLoudness")
+	outputText, err := service.runFFmpegCommandWithReporter(
+		ctx,
+		reporter,
+		execPath,
+		buildFFmpegLoudnessMeasureArgs(inputPath, processing),
+		workDir,
+	)
+	if err != nil {
+		message := strings.TrimSpace(outputText)
+		if message == "" {
+			message = err.Error()
+		}
+		return loudnormMeasurement{}, fmt.Errorf("ffmpeg loudness measurement failed: %s", message)
+	}
+	return parseLoudnormMeasurement(outputText)
+}
+
+func buildFFmpegLoudnessMeasureArgs(inputPath string, processing library.TranscodeAudioProcessing) []string {
+	filters := append(buildTranscodeAudioPreFilters(processing), fmt.Sprintf(
+		"loudnorm=I=%s:TP=%s:LRA=%s:print_format=json",
+		formatLoudnormValue(processing.LoudnessTargetI),
+		formatLoudnormValue(processing.LoudnessTruePeak),
+		formatLoudnormValue(processing.LoudnessRange),
+	))
+	return []string{
+		"-y",
+		"-i", inputPath,
+		"-map", "0:a:0",
+		"-vn", "-sn", "-dn",
+		"-af", strings.Join(filters, ","),
+		"-f", "null",
+		"-",
+	}
+}
+
+// buildTranscodeAudioFilter returns the -af chain for the encode pass. A nil
+// measurement leaves loudness untouched, which is how silent inputs are handled.
+func buildTranscodeAudioFilter(processing library.TranscodeAudioProcessing, measurement *loudnormMeasurement) string {
+	filters := buildTranscodeAudioPreFilters(processing)
+	if processing.LoudnessNormalize && measurement != nil {
+		filters = append(filters, fmt.Sprintf(
+			"loudnorm=I=%s:TP=%s:LRA=%s:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true:print_format=json",
+			formatLoudnormValue(processing.LoudnessTargetI),
+			formatLoudnormValue(processing.LoudnessTruePeak),
+			formatLoudnormValue(processing.LoudnessRange),
+			formatLoudnormValue(measurement.InputI),
+			formatLoudnormValue(measurement.InputTP),
+			formatLoudnormValue(measurement.InputLRA),
+			formatLoudnormValue(measurement.InputThresh),
+			formatLoudnormValue(measurement.TargetOffset),
+		))
+		filters = append(filters, fmt.Sprintf("aresample=%d", loudnormOutputSampleRate))
+	}
+	return strings.Join(filters, ",")
+}
+
+func buildTranscodeAudioPreFilters(processing library.TranscodeAudioProcessing) []string {
+	filters := make([]string, 0, 2)
+	if processing.NoiseReduction {
+		filters = append(filters, noiseReductionFilter)
+	}
+	if processing.TrimSilence {
+		filters = append(filters, trimSilenceFilter)
+	}
+	return filters
+}
+
+// parseLoudnormMeasurement reads the JSON block loudnorm prints at the end of a run.
+// ffmpeg prefixes it with a log line, so the last object in the output is used.
+func parseLoudnormMeasurement(output string) (loudnormMeasurement, error) {
+	end := strings.LastIndex(output, "}")
+	if end < 0 {
+		return loudnormMeasurement{}, fmt.Errorf("loudness measurement not found in ffmpeg output")
+	}
+	start := strings.LastIndex(output[:end], "{")
+	if start < 0 {
+		return loudnormMeasurement{}, fmt.Errorf("loudness measurement not found in ffmpeg output")
+	}
+	values := make(map[string]string)
+	if err := json.Unmarshal([]byte(output[start:end+1]), &values); err != nil {
+		return loudnormMeasurement{}, fmt.Errorf("decode loudness measurement: %w", err)
+	}
+	if _, ok := values["input_i"]; !ok {
+		return loudnormMeasurement{}, fmt.Errorf("loudness measurement not found in ffmpeg output")
+	}
+	parse := func(key string) (float64, error) {
+		value, err := strconv.ParseFloat(strings.TrimSpace(values[key]), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid loudness value %s: %q", key, values[key])
+		}
+		return value, nil
+	}
+	var result loudnormMeasurement
+	var err error
+	for key, target := range map[string]*float64{
+		"input_i":       &result.InputI,
+		"input_tp":      &result.InputTP,
+		"input_lra":     &result.InputLRA,
+		"input_thresh":  &result.InputThresh,
+		"output_i":      &result.OutputI,
+		"target_offset": &result.TargetOffset,
+	} {
+		if *target, err = parse(key); err != nil {
+			return loudnormMeasurement{}, err
+		}
+		// Silent audio is reported as -inf, which loudnorm cannot take as input.
+		if math.IsInf(*target, 0) || math.IsNaN(*target) {
+			return loudnormMeasurement{}, errLoudnessNotMeasurable
+		}
+	}
+	return result, nil
+}
+
+func formatLoudnormValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
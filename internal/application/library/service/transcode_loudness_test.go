@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const loudnormTestOutput = `[Parsed_loudnorm_0 @ 0x600000c3c000]
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}`
+
+func TestParseLoudnormMeasurement(t *testing.T) {
+	t.Parallel()
+
+	measurement, err := parseLoudnormMeasurement("size=N/A time=00:01:00.00\n" + loudnormTestOutput)
+	if err != nil {
+		t.Fatalf("parse loudnorm output: %v", err)
+	}
+	expected := loudnormMeasurement{InputI: -27.61, InputTP: -4.47, InputLRA: 18.06, InputThresh: -39.2, OutputI: -16.58, TargetOffset: 0.58}
+	if measurement != expected {
+		t.Fatalf("unexpected measurement %#v", measurement)
+	}
+	if _, err := parseLoudnormMeasurement("Output #0, null, to 'pipe:':"); err == nil {
+		t.Fatalf("expected an error without loudnorm statistics")
+	}
+	silent := strings.NewReplacer(`"-27.61"`, `"-inf"`, `"-39.20"`, `"-inf"`).Replace(loudnormTestOutput)
+	if _, err := parseLoudnormMeasurement(silent); !errors.Is(err, errLoudnessNotMeasurable) {
+		t.Fatalf("expected silent input to be reported as not measurable, got %v", err)
+	}
+}
+
+func TestBuildLoudnessNormalizationFilters(t *testing.T) {
+	t.Parallel()
+
+	processing := library.TranscodeAudioProcessing{
+		LoudnessNormalize: true,
+		LoudnessTargetI:   -16,
+		LoudnessTruePeak:  -1.5,
+		LoudnessRange:     11,
+		NoiseReduction:    true,
+	}
+	measure := strings.Join(buildFFmpegLoudnessMeasureArgs("/tmp/input.mp4", processing), " ")
+	if measure != "-y -i /tmp/input.mp4 -map 0:a:0 -vn -sn -dn -af afftdn=nf=-25,loudnorm=I=-16:TP=-1.5:LRA=11:print_format=json -f null -" {
+		t.Fatalf("unexpected measurement args %q", measure)
+	}
+	measurement := loudnormMeasurement{InputI: -27.61, InputTP: -4.47, InputLRA: 18.06, InputThresh: -39.2, TargetOffset: 0.58}
+	filter := buildTranscodeAudioFilter(processing, &measurement)
+	expected := "afftdn=nf=-25,loudnorm=I=-16:TP=-1.5:LRA=11:measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:measured_thresh=-39.2:offset=0.58:linear=true:print_format=json,aresample=48000"
+	if filter != expected {
+		t.Fatalf("unexpected audio filter %q", filter)
+	}
+	if filter := buildTranscodeAudioFilter(processing, nil); filter != "afftdn=nf=-25" {
+		t.Fatalf("expected loudnorm to be skipped without a measurement, got %q", filter)
+	}
+}
+
+func TestBuildFFmpegTranscodeArgsAudioFilterReencodesCopiedAudio(t *testing.T) {
+	t.Parallel()
+
+	plan := transcodePlan{
+		request: dto.CreateTranscodeJobRequest{
+			Format:     "mkv",
+			VideoCodec: "copy",
+			AudioCodec: "copy",
+		},
+		outputType:  library.TranscodeOutputVideo,
+		audioFilter: "afftdn=nf=-25",
+	}
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mkv", "/tmp/output.mkv", "", "", "", "none", "")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-c:v copy") || !strings.Contains(joined, "-af afftdn=nf=-25 -c:a aac") {
+		t.Fatalf("expected filtered audio to be re-encoded, got %q", joined)
+	}
+}
+
+func TestNewTranscodePresetNormalizesAudioProcessing(t *testing.T) {
+	t.Parallel()
+
+	preset, err := library.NewTranscodePreset(library.TranscodePresetParams{
+		ID:         "preset-1",
+		Name:       "Podcast",
+		OutputType: "audio",
+		Container:  "mp3",
+		Audio:      library.TranscodeAudioProcessing{LoudnessNormalize: true, TrimSilence: true},
+	})
+	if err != nil {
+		t.Fatalf("expected preset to be valid: %v", err)
+	}
+	if preset.Audio.LoudnessTargetI != library.DefaultLoudnessTargetLUFS ||
+		preset.Audio.LoudnessTruePeak != library.DefaultLoudnessTruePeakDBTP ||
+		preset.Audio.LoudnessRange != library.DefaultLoudnessRangeLU {
+		t.Fatalf("expected EBU R128 defaults, got %#v", preset.Audio)
+	}
+	if _, err := library.NewTranscodePreset(library.TranscodePresetParams{
+		ID:         "preset-2",
+		Name:       "Loud",
+		OutputType: "audio",
+		Container:  "mp3",
+		Audio:      library.TranscodeAudioProcessing{LoudnessNormalize: true, LoudnessTargetI: -2},
+	}); !errors.Is(err, library.ErrInvalidPreset) {
+		t.Fatalf("expected out of range loudness targets to be rejected, got %v", err)
+	}
+	if _, err := library.NewTranscodePreset(library.TranscodePresetParams{
+		ID:         "preset-3",
+		Name:       "Video",
+		OutputType: "video",
+		Container:  "mp4",
+		Audio:      library.TranscodeAudioProcessing{TrimSilence: true},
+	}); !errors.Is(err, library.ErrInvalidPreset) {
+		t.Fatalf("expected silence trimming to be rejected for video presets, got %v", err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 type registeredLocalOutputParams struct {
 	LibraryID     string
 	RootFileID    string
+	SourceFileIDs []string
 	Name          string
 	Kind          string
 	OperationID   string
@@ -39,6 +40,8 @@ type ffmpegProgressReporter struct {
 	service     *LibraryService
 	operation   *library.LibraryOperation
 	durationMs  int64
+	stage       string
+	message     string
 	mu          sync.Mutex
 	currentMs   int64
 	speed       string
@@ -53,6 +56,8 @@ func newFFmpegProgressReporter(service *LibraryService, operation *library.Libra
 		service:     service,
 		operation:   operation,
 		durationMs:  durationMs,
+		stage:       progressText("library.progress.transcoding"),
+		message:     progressText("library.progressDetail.ffmpegRenderingOutput"),
 		lastPercent: -1,
 	}
 }
@@ -116,8 +121,8 @@ func (reporter *ffmpegProgressReporter) persistLocked(completed bool) {
 
 	now := reporter.service.now().Format(time.RFC3339)
 	progress := &library.OperationProgress{
-		Stage:     progressText("library.progress.transcoding"),
-		Message:   progressText("library.progressDetail.ffmpegRenderingOutput"),
+		Stage:     reporter.stage,
+		Message:   reporter.message,
 		Speed:     speed,
 		UpdatedAt: now,
 	}
@@ -149,6 +154,12 @@ func (reporter *ffmpegProgressReporter) persistLocked(completed bool) {
 }
 
 func (service *LibraryService) runTranscodeOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateTranscodeJobRequest) {
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		return
+	}
+	defer release()
+
 	ctx, cancel := context.WithCancel(ctx)
 	service.registerOperationRun(operation.ID, cancel)
 	defer func() {
@@ -184,28 +195,12 @@ func (service *LibraryService) runTranscodeOperation(ctx context.Context, operat
 		service.failTranscodeOperation(ctx, operation, request, err)
 		return
 	}
-	plan, err := service.resolveTranscodePlan(ctx, request, probe)
-	if err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
 	ffmpegExecPath, err := resolveFFmpegExecPath(ctx, service.tools)
 	if err != nil {
 		service.failTranscodeOperation(ctx, operation, request, err)
 		return
 	}
 
-	displayName := resolveTranscodeTitle(request, sourceFile.Storage.LocalPath, plan.preset)
-	outputPath, err := service.deriveManagedOutputPath(ctx, sourceFile.LibraryID, displayName, plan.request.Format, sourceFile.Storage.LocalPath)
-	if err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
-	if err := ensureManagedOutputParentDir(outputPath); err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
-
 	tempDir, err := os.MkdirTemp("", "dreamcreator-transcode-*")
 	if err != nil {
 		service.failTranscodeOperation(ctx, operation, request, err)
@@ -259,86 +254,57 @@ func (service *LibraryService) runTranscodeOperation(ctx context.Context, operat
 		}
 	}
 
-	ffmpegArgs, err := buildFFmpegTranscodeArgs(
-		plan,
-		sourceFile.Storage.LocalPath,
-		outputPath,
-		burninSubtitlePath,
-		embeddedSubtitlePath,
-		subtitleFormat,
-		subtitleHandling,
-	)
-	if err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
-
-	progressTime := service.now()
-	operation.Progress = buildOperationProgress(
-		progressTime,
-		progressText("library.progress.transcoding"),
-		0,
-		1,
-		progressText("library.progressDetail.ffmpegRenderingOutput"),
-	)
-	if err := service.saveAndPublishOperation(ctx, operation); err != nil {
-		return
+	coverPath := ""
+	if request.EmbedCover {
+		coverPath, err = service.resolveFileCoverPath(ctx, sourceFile)
+		if err != nil {
+			service.failTranscodeOperation(ctx, operation, request, err)
+			return
+		}
 	}
 
-	outputText, err := service.runFFmpegCommandWithProgress(
-		ctx,
-		&operation,
-		ffmpegExecPath,
-		ffmpegArgs,
-		filepath.Dir(sourceFile.Storage.LocalPath),
-		probe.DurationMs,
-	)
-	if err != nil {
-		message := strings.TrimSpace(outputText)
-		if message == "" {
-			message = err.Error()
+	// Variants render one after another from the same probe and subtitle files; the
+	// first output is the primary one.
+	variantRequests := buildTranscodeVariantRequests(request)
+	files := make([]library.LibraryFile, 0, len(variantRequests))
+	operationOutputs := make([]library.OperationOutputFile, 0, len(variantRequests))
+	displayName := ""
+	outputPath := ""
+	for index, variantRequest := range variantRequests {
+		rendered, err := service.renderTranscodeOutput(ctx, &operation, transcodeRenderParams{
+			request:              variantRequest,
+			sourceFile:           sourceFile,
+			probe:                probe,
+			ffmpegExecPath:       ffmpegExecPath,
+			tempDir:              tempDir,
+			burninSubtitlePath:   burninSubtitlePath,
+			embeddedSubtitlePath: embeddedSubtitlePath,
+			subtitleFormat:       subtitleFormat,
+			subtitleHandling:     subtitleHandling,
+			coverPath:            coverPath,
+			variantIndex:         index,
+			variantCount:         len(variantRequests),
+		})
+		if err != nil {
+			service.failTranscodeOperation(ctx, operation, request, err)
+			return
 		}
-		service.failTranscodeOperation(ctx, operation, request, fmt.Errorf("ffmpeg transcode failed: %s", message))
-		return
-	}
-	if !pathExists(outputPath) {
-		service.failTranscodeOperation(ctx, operation, request, fmt.Errorf("ffmpeg produced no output file"))
-		return
+		if index == 0 {
+			displayName = rendered.displayName
+			outputPath = rendered.outputPath
+		}
+		files = append(files, rendered.file)
+		operationOutputs = append(operationOutputs, library.OperationOutputFile{
+			FileID:    rendered.file.ID,
+			Kind:      string(rendered.file.Kind),
+			Format:    mediaFormatFromFile(rendered.file),
+			SizeBytes: mediaSizeFromFile(rendered.file),
+			IsPrimary: index == 0,
+			Deleted:   rendered.file.State.Deleted,
+		})
 	}
 
 	finishedAt := service.now()
-	outputFile, err := service.registerManagedLocalOutputFile(ctx, registeredLocalOutputParams{
-		LibraryID:     sourceFile.LibraryID,
-		RootFileID:    rootFileID(sourceFile),
-		Name:          displayName,
-		Kind:          string(library.FileKindTranscode),
-		OperationID:   operation.ID,
-		OperationKind: "transcode",
-		OutputPath:    outputPath,
-		SourceMedia:   sourceFile.Media,
-		OccurredAt:    finishedAt,
-	})
-	if err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
-	outputFile.LatestOperationID = operation.ID
-	outputFile.UpdatedAt = finishedAt
-	if err := service.files.Save(ctx, outputFile); err != nil {
-		service.failTranscodeOperation(ctx, operation, request, err)
-		return
-	}
-
-	files := []library.LibraryFile{outputFile}
-	operationOutputs := []library.OperationOutputFile{{
-		FileID:    outputFile.ID,
-		Kind:      string(outputFile.Kind),
-		Format:    mediaFormatFromFile(outputFile),
-		SizeBytes: mediaSizeFromFile(outputFile),
-		IsPrimary: true,
-		Deleted:   outputFile.State.Deleted,
-	}}
-
 	sourceFile.LatestOperationID = operation.ID
 	sourceFile.UpdatedAt = finishedAt
 	if err := service.files.Save(ctx, sourceFile); err != nil {
@@ -590,7 +556,7 @@ func (service *LibraryService) registerManagedLocalOutputFile(ctx context.Contex
 		Name:      params.Name,
 		Storage:   library.FileStorage{Mode: "local_path", LocalPath: params.OutputPath},
 		Origin:    library.FileOrigin{Kind: params.OperationKind, OperationID: params.OperationID},
-		Lineage:   library.FileLineage{RootFileID: strings.TrimSpace(params.RootFileID)},
+		Lineage:   library.FileLineage{RootFileID: strings.TrimSpace(params.RootFileID), SourceFileIDs: params.SourceFileIDs},
 		Media:     media,
 		State:     library.FileState{Status: "active"},
 		CreatedAt: &now,
@@ -612,6 +578,17 @@ func (service *LibraryService) runFFmpegCommandWithProgress(
 	args []string,
 	workDir string,
 	durationMs int64,
+) (string, error) {
+	reporter := newFFmpegProgressReporter(service, operation, durationMs)
+	return service.runFFmpegCommandWithReporter(ctx, reporter, execPath, args, workDir)
+}
+
+func (service *LibraryService) runFFmpegCommandWithReporter(
+	ctx context.Context,
+	reporter *ffmpegProgressReporter,
+	execPath string,
+	args []string,
+	workDir string,
 ) (string, error) {
 	commandArgs := withFFmpegProgressArgs(args)
 	command := exec.CommandContext(ctx, execPath, commandArgs...)
@@ -630,7 +607,6 @@ func (service *LibraryService) runFFmpegCommandWithProgress(
 		return "", err
 	}
 
-	reporter := newFFmpegProgressReporter(service, operation, durationMs)
 	var stderrBuilder strings.Builder
 	var stdoutErr error
 	var stderrErr error
@@ -992,16 +968,31 @@ func buildFFmpegTranscodeArgs(
 	embeddedSubtitlePath string,
 	embeddedSubtitleFormat string,
 	subtitleHandling string,
+	chaptersMetadataPath string,
 ) ([]string, error) {
 	args := []string{"-y", "-i", inputPath}
+	nextInputIndex := 1
 	if subtitleHandling == "embed" && strings.TrimSpace(embeddedSubtitlePath) != "" {
 		args = append(args, "-i", embeddedSubtitlePath)
+		nextInputIndex++
+	}
+	chaptersInputIndex := -1
+	if strings.TrimSpace(chaptersMetadataPath) != "" {
+		args = append(args, "-f", "ffmetadata", "-i", chaptersMetadataPath)
+		chaptersInputIndex = nextInputIndex
+		nextInputIndex++
+	}
+	container := normalizeContainer(plan.request.Format)
+	coverInputIndex := -1
+	if strings.TrimSpace(plan.coverPath) != "" && (container == "mp4" || container == "mov") {
+		args = append(args, "-i", plan.coverPath)
+		coverInputIndex = nextInputIndex
 	}
 	filters := make([]string, 0, 2)
 	if subtitleHandling == "burnin" && strings.TrimSpace(burninSubtitlePath) != "" {
 		filters = append(filters, "ass="+quoteFFmpegFilterPath(burninSubtitlePath))
 	}
-	scaleFilter, err := buildFFmpegScaleFilter(plan.request)
+	scaleFilter, err := buildFFmpegFrameFilter(plan)
 	if err != nil {
 		return nil, err
 	}
@@ -1009,11 +1000,15 @@ func buildFFmpegTranscodeArgs(
 		filters = append(filters, scaleFilter)
 	}
 	if len(filters) > 0 {
-		args = append(args, "-vf", strings.Join(filters, ","))
+		// The cover is a second video stream, so filters only target the main one.
+		if coverInputIndex >= 0 {
+			args = append(args, "-filter:v:0", strings.Join(filters, ","))
+		} else {
+			args = append(args, "-vf", strings.Join(filters, ","))
+		}
 	}
 
 	outputType := plan.outputType
-	container := normalizeContainer(plan.request.Format)
 	if outputType == library.TranscodeOutputAudio || isAudioContainer(container) {
 		args = append(args, "-map", "0:a:0?", "-vn")
 	} else {
@@ -1028,24 +1023,43 @@ func buildFFmpegTranscodeArgs(
 		if subtitleHandling == "embed" && strings.TrimSpace(embeddedSubtitlePath) != "" {
 			args = append(args, "-map", "1:0")
 		}
+		if coverInputIndex >= 0 {
+			args = append(args, "-map", fmt.Sprintf("%d:0", coverInputIndex))
+		}
 		selectedVideoCodec := ffmpegVideoCodec(videoCodec)
 		args = append(args, "-c:v", selectedVideoCodec)
+		if coverInputIndex >= 0 {
+			args = append(args, "-c:v:1", ffmpegCoverCodec(plan.coverPath), "-disposition:v:1", "attached_pic")
+		}
 		isHardwareVideoCodec := ffmpegIsHardwareVideoCodec(selectedVideoCodec)
 		if videoCodec != "copy" {
 			args = appendVideoCodecQualityArgs(args, videoCodec, plan.request, isHardwareVideoCodec)
+			maxBitrate := resolveTranscodeMaxVideoBitrateKbps(plan)
 			qualityMode := strings.ToLower(strings.TrimSpace(plan.request.QualityMode))
 			switch qualityMode {
 			case "bitrate":
-				if plan.request.BitrateKbps > 0 {
-					args = append(args, "-b:v", fmt.Sprintf("%dk", plan.request.BitrateKbps))
+				bitrate := plan.request.BitrateKbps
+				if maxBitrate > 0 && (bitrate <= 0 || bitrate > maxBitrate) {
+					bitrate = maxBitrate
+				}
+				if bitrate > 0 {
+					args = append(args, "-b:v", fmt.Sprintf("%dk", bitrate))
 				}
 			default:
 				if plan.request.CRF > 0 && !isHardwareVideoCodec {
 					if videoCodec == "vp9" {
-						args = append(args, "-b:v", "0")
+						// libvpx only honours a cap in constrained quality mode.
+						if maxBitrate > 0 {
+							args = append(args, "-b:v", fmt.Sprintf("%dk", maxBitrate))
+						} else {
+							args = append(args, "-b:v", "0")
+						}
 					}
 					args = append(args, "-crf", strconv.Itoa(plan.request.CRF))
 				}
+				if maxBitrate > 0 {
+					args = append(args, "-maxrate", fmt.Sprintf("%dk", maxBitrate), "-bufsize", fmt.Sprintf("%dk", maxBitrate*2))
+				}
 			}
 		}
 	}
@@ -1058,6 +1072,13 @@ func buildFFmpegTranscodeArgs(
 			audioCodec = firstNonEmpty(defaultAudioCodecForContainer(container), "aac")
 		}
 	}
+	if strings.TrimSpace(plan.audioFilter) != "" {
+		// Filtered audio has to be re-encoded.
+		if audioCodec == "copy" {
+			audioCodec = firstNonEmpty(defaultAudioCodecForContainer(container), defaultFilteredAudioCodec(container))
+		}
+		args = append(args, "-af", plan.audioFilter)
+	}
 	if audioCodec != "" {
 		args = append(args, "-c:a", ffmpegAudioCodec(audioCodec))
 		if ffmpegAudioCodecSupportsBitrate(audioCodec) && plan.request.AudioBitrateKbps > 0 {
@@ -1071,6 +1092,18 @@ func buildFFmpegTranscodeArgs(
 		}
 		args = append(args, "-c:s", subtitleCodec)
 	}
+	if chaptersInputIndex >= 0 {
+		args = append(args, "-map_chapters", strconv.Itoa(chaptersInputIndex))
+	}
+	if strings.TrimSpace(plan.coverPath) != "" && container == "mkv" {
+		// Matroska players read cover art from an attachment named cover.*.
+		extension := strings.ToLower(filepath.Ext(plan.coverPath))
+		args = append(args,
+			"-attach", plan.coverPath,
+			"-metadata:s:t", "mimetype="+coverImageMimeType(extension),
+			"-metadata:s:t", "filename=cover"+extension,
+		)
+	}
 	if container == "mp4" || container == "mov" {
 		args = append(args, "-movflags", "+faststart")
 	}
@@ -1079,6 +1112,37 @@ func buildFFmpegTranscodeArgs(
 	return args, nil
 }
 
+func transcodeContainerSupportsCover(format string) bool {
+	switch normalizeContainer(format) {
+	case "mp4", "mov", "mkv":
+		return true
+	default:
+		return false
+	}
+}
+
+// ffmpegCoverCodec copies JPEG and PNG covers and converts other images to JPEG,
+// the formats MP4 accepts as attached pictures.
+func ffmpegCoverCodec(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png":
+		return "copy"
+	default:
+		return "mjpeg"
+	}
+}
+
+func coverImageMimeType(extension string) string {
+	switch extension {
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
 func appendVideoCodecQualityArgs(
 	args []string,
 	videoCodec string,
@@ -1173,6 +1237,13 @@ func ffmpegAudioCodec(codec string) string {
 	}
 }
 
+func defaultFilteredAudioCodec(container string) string {
+	if normalizeContainer(container) == "webm" {
+		return "opus"
+	}
+	return "aac"
+}
+
 func ffmpegAudioCodecSupportsBitrate(codec string) bool {
 	switch normalizeAudioCodecName(codec) {
 	case "aac", "mp3", "opus":
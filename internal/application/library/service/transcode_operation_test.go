@@ -65,6 +65,7 @@ func TestBuildFFmpegTranscodeArgsBurnInEscapesASSPathAndForcesCodec(t *testing.T
 		"",
 		"",
 		"burnin",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
@@ -108,6 +109,7 @@ func TestBuildFFmpegTranscodeArgsEmbedMapsSubtitleTrackAndUsesContainerCodec(t *
 		"/tmp/subtitles.srt",
 		"srt",
 		"embed",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
@@ -133,7 +135,7 @@ func TestBuildFFmpegTranscodeArgsAudioOutputDisablesVideo(t *testing.T) {
 		outputType: library.TranscodeOutputAudio,
 	}
 
-	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mp3", "", "", "", "none")
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mp3", "", "", "", "none", "")
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
 	}
@@ -170,7 +172,7 @@ func TestBuildFFmpegTranscodeArgsExpandedAudioContainersUseExpectedCodec(t *test
 				outputType: library.TranscodeOutputAudio,
 			}
 
-			args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output."+testCase.format, "", "", "", "none")
+			args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output."+testCase.format, "", "", "", "none", "")
 			if err != nil {
 				t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
 			}
@@ -265,6 +267,7 @@ func TestBuildFFmpegTranscodeArgsVP9UsesLibvpxBestPracticeFlags(t *testing.T) {
 		"",
 		"",
 		"none",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
@@ -292,7 +295,7 @@ func TestBuildFFmpegTranscodeArgsLosslessAudioSkipsBitrateFlags(t *testing.T) {
 		outputType: library.TranscodeOutputAudio,
 	}
 
-	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.wav", "/tmp/output.flac", "", "", "", "none")
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.wav", "/tmp/output.flac", "", "", "", "none", "")
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
 	}
@@ -594,6 +597,7 @@ func TestBurninASSTranscodeUsesSourceCanvasAndScalesAfterSubtitles(t *testing.T)
 		"",
 		"",
 		"burnin",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
@@ -11,9 +11,16 @@ import (
 )
 
 type transcodePlan struct {
-	request    dto.CreateTranscodeJobRequest
-	preset     *library.TranscodePreset
-	outputType library.TranscodeOutputType
+	request     dto.CreateTranscodeJobRequest
+	preset      *library.TranscodePreset
+	outputType  library.TranscodeOutputType
+	audioFilter string
+	// source is the probed input. It is empty when the plan was resolved without a
+	// probe, in which case reframing falls back to 1080p and size caps are skipped.
+	source mediaProbe
+	// coverPath is the image embedded as cover art, set only for containers that
+	// support it.
+	coverPath string
 }
 
 type containerCompat struct {
@@ -60,7 +67,7 @@ func (service *LibraryService) resolveTranscodePlan(ctx context.Context, request
 			return transcodePlan{}, err
 		}
 		resolved := applyPresetToRequest(request, preset)
-		return transcodePlan{request: resolved, preset: &preset, outputType: preset.OutputType}, nil
+		return transcodePlan{request: resolved, preset: &preset, outputType: preset.OutputType, source: probe}, nil
 	}
 
 	if hasManualTranscodeConfig(request) {
@@ -71,7 +78,7 @@ func (service *LibraryService) resolveTranscodePlan(ctx context.Context, request
 		if err := validatePresetForProbe(preset, probe); err != nil {
 			return transcodePlan{}, err
 		}
-		return transcodePlan{request: request, preset: &preset, outputType: preset.OutputType}, nil
+		return transcodePlan{request: request, preset: &preset, outputType: preset.OutputType, source: probe}, nil
 	}
 
 	preset, err := service.selectDefaultPreset(ctx, probe)
@@ -82,7 +89,7 @@ func (service *LibraryService) resolveTranscodePlan(ctx context.Context, request
 		return transcodePlan{}, err
 	}
 	resolved := applyPresetToRequest(request, preset)
-	return transcodePlan{request: resolved, preset: &preset, outputType: preset.OutputType}, nil
+	return transcodePlan{request: resolved, preset: &preset, outputType: preset.OutputType, source: probe}, nil
 }
 
 func (service *LibraryService) resolveTranscodePlanWithoutProbe(ctx context.Context, request dto.CreateTranscodeJobRequest, sourcePath string) (transcodePlan, error) {
@@ -220,6 +227,9 @@ func hasManualTranscodeConfig(request dto.CreateTranscodeJobRequest) bool {
 	if strings.TrimSpace(request.Preset) != "" {
 		return true
 	}
+	if strings.TrimSpace(request.AspectRatio) != "" || request.MaxBitrateKbps > 0 || request.MaxFileSizeMB > 0 {
+		return true
+	}
 	return false
 }
 
@@ -248,6 +258,10 @@ func presetFromRequest(request dto.CreateTranscodeJobRequest) (library.Transcode
 		Width:            request.Width,
 		Height:           request.Height,
 		FFmpegPreset:     strings.TrimSpace(request.Preset),
+		AspectRatio:      request.AspectRatio,
+		ReframeMode:      request.ReframeMode,
+		MaxBitrateKbps:   request.MaxBitrateKbps,
+		MaxFileSizeMB:    request.MaxFileSizeMB,
 		AllowUpscale:     false,
 		RequiresVideo:    outputType == library.TranscodeOutputVideo,
 		RequiresAudio:    outputType == library.TranscodeOutputAudio,
@@ -268,6 +282,10 @@ func applyPresetToRequest(request dto.CreateTranscodeJobRequest, preset library.
 	resolved.Width = preset.Width
 	resolved.Height = preset.Height
 	resolved.Preset = preset.FFmpegPreset
+	resolved.AspectRatio = preset.AspectRatio
+	resolved.ReframeMode = preset.ReframeMode
+	resolved.MaxBitrateKbps = preset.MaxBitrateKbps
+	resolved.MaxFileSizeMB = preset.MaxFileSizeMB
 	if preset.OutputType == library.TranscodeOutputAudio {
 		resolved.VideoCodec = ""
 		resolved.QualityMode = ""
@@ -277,6 +295,10 @@ func applyPresetToRequest(request dto.CreateTranscodeJobRequest, preset library.
 		resolved.Width = 0
 		resolved.Height = 0
 		resolved.Preset = ""
+		resolved.AspectRatio = ""
+		resolved.ReframeMode = ""
+		resolved.MaxBitrateKbps = 0
+		resolved.MaxFileSizeMB = 0
 	}
 	return resolved
 }
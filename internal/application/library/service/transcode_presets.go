@@ -38,6 +38,19 @@ type builtinVideoScaleSpec struct {
 	scale      string
 }
 
+// builtinPlatformPresetSpec is an H.264 MP4 preset tuned for a publishing platform:
+// the frame is reframed to the platform aspect ratio and the bitrate stays within
+// its upload recommendations.
+type builtinPlatformPresetSpec struct {
+	id          string
+	name        string
+	aspectRatio string
+	reframeMode string
+	scale       string
+	maxBitrate  int
+	maxFileSize int
+}
+
 type builtinAudioPresetSpec struct {
 	id         string
 	name       string
@@ -65,6 +78,18 @@ var builtinVideoScaleSpecs = []builtinVideoScaleSpec{
 	{idSuffix: "480p", nameSuffix: "480p", scale: "480p"},
 }
 
+var builtinPlatformPresetSpecs = []builtinPlatformPresetSpec{
+	{id: "builtin-platform-youtube-1080p", name: "YouTube 1080p", aspectRatio: "16:9", reframeMode: library.ReframeModePad, scale: "1080p", maxBitrate: 12000},
+	{id: "builtin-platform-youtube-shorts", name: "YouTube Shorts", aspectRatio: "9:16", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 10000},
+	{id: "builtin-platform-tiktok", name: "TikTok", aspectRatio: "9:16", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 8000, maxFileSize: 287},
+	{id: "builtin-platform-douyin", name: "Douyin", aspectRatio: "9:16", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 8000},
+	{id: "builtin-platform-bilibili-1080p", name: "Bilibili 1080p", aspectRatio: "16:9", reframeMode: library.ReframeModePad, scale: "1080p", maxBitrate: 6000},
+	{id: "builtin-platform-bilibili-vertical", name: "Bilibili Vertical", aspectRatio: "9:16", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 6000},
+	{id: "builtin-platform-xiaohongshu", name: "Xiaohongshu", aspectRatio: "3:4", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 8000},
+	{id: "builtin-platform-square", name: "Square 1:1", aspectRatio: "1:1", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 8000},
+	{id: "builtin-platform-feed-4x5", name: "Feed 4:5", aspectRatio: "4:5", reframeMode: library.ReframeModeBlur, scale: "1080p", maxBitrate: 8000},
+}
+
 var builtinAudioPresetSpecs = []builtinAudioPresetSpec{
 	{id: "builtin-audio-mp3-128k", name: "MP3 128k", container: "mp3", audioCodec: "mp3", bitrate: 128},
 	{id: "builtin-audio-mp3-192k", name: "MP3 192k", container: "mp3", audioCodec: "mp3", bitrate: 192},
@@ -168,6 +193,11 @@ func (service *LibraryService) SaveTranscodePreset(ctx context.Context, preset d
 		RequiresVideo:    preset.RequiresVideo,
 		RequiresAudio:    preset.RequiresAudio,
 		IsBuiltin:        false,
+		AspectRatio:      preset.AspectRatio,
+		ReframeMode:      preset.ReframeMode,
+		MaxBitrateKbps:   preset.MaxBitrateKbps,
+		MaxFileSizeMB:    preset.MaxFileSizeMB,
+		Audio:            transcodeAudioProcessingFromDTO(preset),
 		CreatedAt:        timeOrNil(createdAt),
 		UpdatedAt:        &now,
 	})
@@ -296,7 +326,7 @@ func recommendedAudioBitrateKbps(codec string) int {
 }
 
 func defaultTranscodePresets(now time.Time) []library.TranscodePreset {
-	result := make([]library.TranscodePreset, 0, len(builtinVideoPresetSeriesSpecs)*len(builtinVideoScaleSpecs)+len(builtinAudioPresetSpecs))
+	result := make([]library.TranscodePreset, 0, len(builtinVideoPresetSeriesSpecs)*len(builtinVideoScaleSpecs)+len(builtinPlatformPresetSpecs)+len(builtinAudioPresetSpecs))
 	add := func(params library.TranscodePresetParams) {
 		params.IsBuiltin = true
 		params.CreatedAt = &now
@@ -324,6 +354,27 @@ func defaultTranscodePresets(now time.Time) []library.TranscodePreset {
 			})
 		}
 	}
+	for _, spec := range builtinPlatformPresetSpecs {
+		add(library.TranscodePresetParams{
+			ID:               spec.id,
+			Name:             spec.name,
+			OutputType:       "video",
+			Container:        "mp4",
+			VideoCodec:       "h264",
+			AudioCodec:       "aac",
+			QualityMode:      "crf",
+			CRF:              defaultH264VideoCRF,
+			AudioBitrateKbps: defaultAACAudioBitrateKbps,
+			Scale:            spec.scale,
+			FFmpegPreset:     defaultFFmpegPreset,
+			AllowUpscale:     true,
+			RequiresVideo:    true,
+			AspectRatio:      spec.aspectRatio,
+			ReframeMode:      spec.reframeMode,
+			MaxBitrateKbps:   spec.maxBitrate,
+			MaxFileSizeMB:    spec.maxFileSize,
+		})
+	}
 	for _, spec := range builtinAudioPresetSpecs {
 		add(library.TranscodePresetParams{
 			ID:               spec.id,
@@ -348,26 +399,47 @@ func toTranscodePresetDTO(preset library.TranscodePreset) dto.TranscodePreset {
 		updatedAt = preset.UpdatedAt.Format(time.RFC3339)
 	}
 	return dto.TranscodePreset{
-		ID:               preset.ID,
-		Name:             preset.Name,
-		OutputType:       string(preset.OutputType),
-		Container:        preset.Container,
-		VideoCodec:       preset.VideoCodec,
-		AudioCodec:       preset.AudioCodec,
-		QualityMode:      preset.QualityMode,
-		CRF:              preset.CRF,
-		BitrateKbps:      preset.BitrateKbps,
-		AudioBitrateKbps: preset.AudioBitrateKbps,
-		Scale:            preset.Scale,
-		Width:            preset.Width,
-		Height:           preset.Height,
-		FFmpegPreset:     preset.FFmpegPreset,
-		AllowUpscale:     preset.AllowUpscale,
-		RequiresVideo:    preset.RequiresVideo,
-		RequiresAudio:    preset.RequiresAudio,
-		IsBuiltin:        preset.IsBuiltin,
-		CreatedAt:        createdAt,
-		UpdatedAt:        updatedAt,
+		ID:                preset.ID,
+		Name:              preset.Name,
+		OutputType:        string(preset.OutputType),
+		Container:         preset.Container,
+		VideoCodec:        preset.VideoCodec,
+		AudioCodec:        preset.AudioCodec,
+		QualityMode:       preset.QualityMode,
+		CRF:               preset.CRF,
+		BitrateKbps:       preset.BitrateKbps,
+		AudioBitrateKbps:  preset.AudioBitrateKbps,
+		Scale:             preset.Scale,
+		Width:             preset.Width,
+		Height:            preset.Height,
+		FFmpegPreset:      preset.FFmpegPreset,
+		AllowUpscale:      preset.AllowUpscale,
+		RequiresVideo:     preset.RequiresVideo,
+		RequiresAudio:     preset.RequiresAudio,
+		IsBuiltin:         preset.IsBuiltin,
+		AspectRatio:       preset.AspectRatio,
+		ReframeMode:       preset.ReframeMode,
+		MaxBitrateKbps:    preset.MaxBitrateKbps,
+		MaxFileSizeMB:     preset.MaxFileSizeMB,
+		LoudnessNormalize: preset.Audio.LoudnessNormalize,
+		LoudnessTargetI:   preset.Audio.LoudnessTargetI,
+		LoudnessTruePeak:  preset.Audio.LoudnessTruePeak,
+		LoudnessRange:     preset.Audio.LoudnessRange,
+		NoiseReduction:    preset.Audio.NoiseReduction,
+		TrimSilence:       preset.Audio.TrimSilence,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+	}
+}
+
+func transcodeAudioProcessingFromDTO(preset dto.TranscodePreset) library.TranscodeAudioProcessing {
+	return library.TranscodeAudioProcessing{
+		LoudnessNormalize: preset.LoudnessNormalize,
+		LoudnessTargetI:   preset.LoudnessTargetI,
+		LoudnessTruePeak:  preset.LoudnessTruePeak,
+		LoudnessRange:     preset.LoudnessRange,
+		NoiseReduction:    preset.NoiseReduction,
+		TrimSilence:       preset.TrimSilence,
 	}
 }
 
@@ -10,8 +10,8 @@ import (
 
 func TestDefaultTranscodePresetsExposeExpandedBuiltinSet(t *testing.T) {
 	presets := defaultTranscodePresets(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
-	if len(presets) != 52 {
-		t.Fatalf("expected 52 builtin transcode presets, got %d", len(presets))
+	if len(presets) != 61 {
+		t.Fatalf("expected 61 builtin transcode presets, got %d", len(presets))
 	}
 
 	seen := make(map[string]struct{}, len(presets))
@@ -27,6 +27,8 @@ func TestDefaultTranscodePresetsExposeExpandedBuiltinSet(t *testing.T) {
 		"builtin-video-h265-mov-2160p",
 		"builtin-video-vp9-mkv-1080p",
 		"builtin-video-vp9-webm-720p",
+		"builtin-platform-tiktok",
+		"builtin-platform-xiaohongshu",
 		"builtin-audio-mp3-192k",
 		"builtin-audio-aac-m4a-256k",
 		"builtin-audio-opus-ogg-128k",
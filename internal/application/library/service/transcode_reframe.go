@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	defaultReframeShortSide = 1080
+	// Size caps leave room for container overhead so the muxed file stays under it.
+	transcodeSizeCapOverhead = 0.97
+	minCappedVideoKbps       = 100
+)
+
+// buildFFmpegFrameFilter returns the video filter that sizes the output frame. Presets
+// with an aspect ratio are reframed, everything else keeps the letterboxed scale.
+func buildFFmpegFrameFilter(plan transcodePlan) (string, error) {
+	if strings.TrimSpace(plan.request.AspectRatio) == "" {
+		return buildFFmpegScaleFilter(plan.request)
+	}
+	width, height, err := resolveReframeSize(plan.request, plan.source)
+	if err != nil {
+		return "", err
+	}
+	fill := fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=increase,crop=%d:%d", width, height, width, height)
+	fit := fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=decrease:force_divisible_by=2", width, height)
+	switch strings.ToLower(strings.TrimSpace(plan.request.ReframeMode)) {
+	case library.ReframeModeCrop:
+		return fill + ",setsar=1", nil
+	case library.ReframeModeBlur:
+		// The blurred, cropped copy fills the frame behind the fitted original.
+		return fmt.Sprintf(
+			"split=2[reframe_bg_src][reframe_fg_src];[reframe_bg_src]%s,gblur=sigma=30[reframe_bg];[reframe_fg_src]%s[reframe_fg];[reframe_bg][reframe_fg]overlay=(W-w)/2:(H-h)/2,setsar=1",
+			fill,
+			fit,
+		), nil
+	default:
+		return fmt.Sprintf("%s,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1", fit, width, height), nil
+	}
+}
+
+// resolveReframeSize keeps the short side of the requested scale and stretches the
+// long side to the aspect ratio, so 1080p at 9:16 renders 1080x1920.
+func resolveReframeSize(request dto.CreateTranscodeJobRequest, source mediaProbe) (int, int, error) {
+	unitsWidth, unitsHeight, ok := library.TranscodeAspectRatio(request.AspectRatio)
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported aspect ratio %q", request.AspectRatio)
+	}
+	shortSide := 0
+	switch scale := strings.ToLower(strings.TrimSpace(request.Scale)); scale {
+	case "", "original":
+		if source.Width > 0 && source.Height > 0 {
+			shortSide = minInt(source.Width, source.Height)
+		}
+	case "custom":
+		if request.Width > 0 && request.Height > 0 {
+			shortSide = minInt(request.Width, request.Height)
+		}
+	default:
+		target, ok := scaleTargets[scale]
+		if !ok {
+			return 0, 0, fmt.Errorf("unsupported scale preset")
+		}
+		shortSide = minInt(target[0], target[1])
+	}
+	if shortSide <= 0 {
+		shortSide = defaultReframeShortSide
+	}
+	shortSide = evenFloor(shortSide)
+	if unitsWidth <= unitsHeight {
+		return shortSide, evenFloor(shortSide * unitsHeight / unitsWidth), nil
+	}
+	return evenFloor(shortSide * unitsWidth / unitsHeight), shortSide, nil
+}
+
+// resolveTranscodeMaxVideoBitrateKbps combines the preset bitrate cap with the file
+// size cap. The size cap needs the source duration and leaves room for the audio.
+func resolveTranscodeMaxVideoBitrateKbps(plan transcodePlan) int {
+	maxBitrate := plan.request.MaxBitrateKbps
+	if plan.request.MaxFileSizeMB <= 0 || plan.source.DurationMs <= 0 {
+		return maxBitrate
+	}
+	seconds := float64(plan.source.DurationMs) / 1000
+	totalKbps := float64(plan.request.MaxFileSizeMB) * 8 * 1024 * 1024 / 1000 / seconds
+	audioKbps := plan.request.AudioBitrateKbps
+	if audioKbps <= 0 {
+		audioKbps = defaultAACAudioBitrateKbps
+	}
+	sizeCap := int(totalKbps*transcodeSizeCapOverhead) - audioKbps
+	if sizeCap < minCappedVideoKbps {
+		sizeCap = minCappedVideoKbps
+	}
+	if maxBitrate <= 0 || sizeCap < maxBitrate {
+		return sizeCap
+	}
+	return maxBitrate
+}
+
+func evenFloor(value int) int {
+	return value - value%2
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+func TestBuildFFmpegFrameFilterReframesToAspectRatio(t *testing.T) {
+	t.Parallel()
+
+	source := mediaProbe{Width: 1920, Height: 1080, DurationMs: 60000}
+	cases := []struct {
+		mode     string
+		expected string
+	}{
+		{
+			mode:     library.ReframeModeCrop,
+			expected: "scale=w=1080:h=1920:force_original_aspect_ratio=increase,crop=1080:1920,setsar=1",
+		},
+		{
+			mode:     library.ReframeModePad,
+			expected: "scale=w=1080:h=1920:force_original_aspect_ratio=decrease:force_divisible_by=2,pad=1080:1920:(ow-iw)/2:(oh-ih)/2,setsar=1",
+		},
+		{
+			mode: library.ReframeModeBlur,
+			expected: "split=2[reframe_bg_src][reframe_fg_src];" +
+				"[reframe_bg_src]scale=w=1080:h=1920:force_original_aspect_ratio=increase,crop=1080:1920,gblur=sigma=30[reframe_bg];" +
+				"[reframe_fg_src]scale=w=1080:h=1920:force_original_aspect_ratio=decrease:force_divisible_by=2[reframe_fg];" +
+				"[reframe_bg][reframe_fg]overlay=(W-w)/2:(H-h)/2,setsar=1",
+		},
+	}
+	for _, tc := range cases {
+		plan := transcodePlan{
+			request: dto.CreateTranscodeJobRequest{Scale: "1080p", AspectRatio: "9:16", ReframeMode: tc.mode},
+			source:  source,
+		}
+		filter, err := buildFFmpegFrameFilter(plan)
+		if err != nil {
+			t.Fatalf("%s: buildFFmpegFrameFilter returned error: %v", tc.mode, err)
+		}
+		if filter != tc.expected {
+			t.Fatalf("%s: unexpected filter %q", tc.mode, filter)
+		}
+	}
+}
+
+func TestResolveReframeSize(t *testing.T) {
+	t.Parallel()
+
+	source := mediaProbe{Width: 1280, Height: 720}
+	cases := []struct {
+		request dto.CreateTranscodeJobRequest
+		width   int
+		height  int
+	}{
+		{request: dto.CreateTranscodeJobRequest{Scale: "original", AspectRatio: "9:16"}, width: 720, height: 1280},
+		{request: dto.CreateTranscodeJobRequest{Scale: "1080p", AspectRatio: "16:9"}, width: 1920, height: 1080},
+		{request: dto.CreateTranscodeJobRequest{Scale: "1080p", AspectRatio: "4:5"}, width: 1080, height: 1350},
+		{request: dto.CreateTranscodeJobRequest{Scale: "720p", AspectRatio: "1:1"}, width: 720, height: 720},
+	}
+	for _, tc := range cases {
+		width, height, err := resolveReframeSize(tc.request, source)
+		if err != nil {
+			t.Fatalf("%s %s: resolveReframeSize returned error: %v", tc.request.Scale, tc.request.AspectRatio, err)
+		}
+		if width != tc.width || height != tc.height {
+			t.Fatalf("%s %s: expected %dx%d, got %dx%d", tc.request.Scale, tc.request.AspectRatio, tc.width, tc.height, width, height)
+		}
+	}
+	if _, _, err := resolveReframeSize(dto.CreateTranscodeJobRequest{AspectRatio: "2:1"}, source); err == nil {
+		t.Fatalf("expected unsupported aspect ratios to be rejected")
+	}
+}
+
+func TestBuildFFmpegTranscodeArgsAppliesBitrateCaps(t *testing.T) {
+	t.Parallel()
+
+	plan := transcodePlan{
+		request: dto.CreateTranscodeJobRequest{
+			Format:           "mp4",
+			VideoCodec:       "h264",
+			AudioCodec:       "aac",
+			QualityMode:      "crf",
+			CRF:              18,
+			AudioBitrateKbps: 256,
+			MaxBitrateKbps:   8000,
+		},
+		outputType: library.TranscodeOutputVideo,
+		source:     mediaProbe{Width: 1920, Height: 1080, DurationMs: 600000},
+	}
+	args, err := buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mp4", "", "", "", "none", "")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-crf 18 -maxrate 8000k -bufsize 16000k") {
+		t.Fatalf("expected the bitrate cap to constrain CRF output, got %q", joined)
+	}
+
+	// 287 MB over ten minutes leaves roughly 4 Mbps for the whole file.
+	plan.request.MaxFileSizeMB = 287
+	if maxBitrate := resolveTranscodeMaxVideoBitrateKbps(plan); maxBitrate != 3636 {
+		t.Fatalf("expected the file size cap to lower the bitrate cap, got %d", maxBitrate)
+	}
+	plan.request.QualityMode = "bitrate"
+	plan.request.BitrateKbps = 12000
+	args, err = buildFFmpegTranscodeArgs(plan, "/tmp/input.mp4", "/tmp/output.mp4", "", "", "", "none", "")
+	if err != nil {
+		t.Fatalf("buildFFmpegTranscodeArgs returned error: %v", err)
+	}
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-b:v 3636k") {
+		t.Fatalf("expected the target bitrate to be clamped to the cap, got %q", joined)
+	}
+}
+
+func TestBuildTranscodeVariantRequests(t *testing.T) {
+	t.Parallel()
+
+	requests := buildTranscodeVariantRequests(dto.CreateTranscodeJobRequest{
+		FileID:           "file-1",
+		PresetID:         "builtin-platform-youtube-1080p",
+		Title:            "Launch",
+		VariantPresetIDs: []string{" builtin-platform-tiktok ", "builtin-platform-youtube-1080p", "", "builtin-platform-tiktok", "builtin-platform-square"},
+	})
+	if len(requests) != 3 {
+		t.Fatalf("expected primary plus two variants, got %d", len(requests))
+	}
+	if requests[0].PresetID != "builtin-platform-youtube-1080p" || requests[0].Title != "Launch" || requests[0].VariantPresetIDs != nil {
+		t.Fatalf("unexpected primary request %#v", requests[0])
+	}
+	if requests[1].PresetID != "builtin-platform-tiktok" || requests[2].PresetID != "builtin-platform-square" {
+		t.Fatalf("unexpected variant order %q, %q", requests[1].PresetID, requests[2].PresetID)
+	}
+	for _, variant := range requests[1:] {
+		if variant.FileID != "file-1" || variant.Title != "" || variant.VariantPresetIDs != nil {
+			t.Fatalf("unexpected variant request %#v", variant)
+		}
+	}
+}
+
+func TestNewTranscodePresetNormalizesReframe(t *testing.T) {
+	t.Parallel()
+
+	preset, err := library.NewTranscodePreset(library.TranscodePresetParams{
+		ID:          "preset-1",
+		Name:        "Vertical",
+		OutputType:  "video",
+		Container:   "mp4",
+		AspectRatio: "9:16",
+	})
+	if err != nil {
+		t.Fatalf("expected preset to be valid: %v", err)
+	}
+	if preset.AspectRatio != "9:16" || preset.ReframeMode != library.ReframeModePad {
+		t.Fatalf("expected pad to be the default reframe mode, got %q %q", preset.AspectRatio, preset.ReframeMode)
+	}
+	for _, params := range []library.TranscodePresetParams{
+		{ID: "preset-2", Name: "Wide", OutputType: "video", Container: "mp4", AspectRatio: "21:9"},
+		{ID: "preset-3", Name: "Stretch", OutputType: "video", Container: "mp4", AspectRatio: "1:1", ReframeMode: "stretch"},
+		{ID: "preset-4", Name: "Capped", OutputType: "video", Container: "mp4", MaxFileSizeMB: -1},
+	} {
+		if _, err := library.NewTranscodePreset(params); !errors.Is(err, library.ErrInvalidPreset) {
+			t.Fatalf("%s: expected ErrInvalidPreset, got %v", params.Name, err)
+		}
+	}
+}
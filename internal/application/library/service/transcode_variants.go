@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const maxTranscodeVariants = 8
+
+type transcodeRenderParams struct {
+	request              dto.CreateTranscodeJobRequest
+	sourceFile           library.LibraryFile
+	probe                mediaProbe
+	ffmpegExecPath       string
+	tempDir              string
+	burninSubtitlePath   string
+	embeddedSubtitlePath string
+	subtitleFormat       string
+	subtitleHandling     string
+	coverPath            string
+	variantIndex         int
+	variantCount         int
+}
+
+type transcodeRenderResult struct {
+	file        library.LibraryFile
+	displayName string
+	outputPath  string
+}
+
+// renderTranscodeOutput resolves the plan for one output of a transcode job, runs
+// ffmpeg and registers the result as a library file.
+func (service *LibraryService) renderTranscodeOutput(
+	ctx context.Context,
+	operation *library.LibraryOperation,
+	params transcodeRenderParams,
+) (transcodeRenderResult, error) {
+	request := params.request
+	sourceFile := params.sourceFile
+	probe := params.probe
+	plan, err := service.resolveTranscodePlan(ctx, request, probe)
+	if err != nil {
+		return transcodeRenderResult{}, err
+	}
+
+	if params.coverPath != "" && plan.outputType == library.TranscodeOutputVideo && transcodeContainerSupportsCover(plan.request.Format) {
+		plan.coverPath = params.coverPath
+	}
+
+	displayName := resolveTranscodeTitle(request, sourceFile.Storage.LocalPath, plan.preset)
+	outputPath, err := service.deriveManagedOutputPath(ctx, sourceFile.LibraryID, displayName, plan.request.Format, sourceFile.Storage.LocalPath)
+	if err != nil {
+		return transcodeRenderResult{}, err
+	}
+	if err := ensureManagedOutputParentDir(outputPath); err != nil {
+		return transcodeRenderResult{}, err
+	}
+
+	// Stored chapters may have been edited since the file was probed, so they are
+	// written from the library rather than copied from the source container.
+	chaptersMetadataPath := ""
+	outputMedia := cloneMediaInfo(sourceFile.Media)
+	if outputMedia != nil && len(outputMedia.Chapters) > 0 {
+		if transcodeContainerSupportsChapters(plan.request.Format) {
+			chaptersMetadataPath = filepath.Join(params.tempDir, "chapters-"+strconv.Itoa(params.variantIndex)+".ffmetadata")
+			if err := os.WriteFile(chaptersMetadataPath, []byte(buildFFMetadataChapters(outputMedia.Chapters)), 0o644); err != nil {
+				return transcodeRenderResult{}, err
+			}
+		} else {
+			outputMedia.Chapters = nil
+		}
+	}
+	if outputMedia != nil {
+		outputMedia.LoudnessLUFS = nil
+	}
+
+	var loudness *loudnormMeasurement
+	hasAudio := strings.TrimSpace(probe.AudioCodec) != "" || probe.Channels > 0
+	if plan.preset != nil && plan.preset.Audio.Enabled() && hasAudio {
+		processing := plan.preset.Audio
+		if processing.LoudnessNormalize {
+			measurement, err := service.measureTranscodeLoudness(
+				ctx,
+				operation,
+				params.ffmpegExecPath,
+				sourceFile.Storage.LocalPath,
+				filepath.Dir(sourceFile.Storage.LocalPath),
+				processing,
+				probe.DurationMs,
+			)
+			switch {
+			case err == nil:
+				loudness = &measurement
+			case errors.Is(err, errLoudnessNotMeasurable):
+				zap.L().Warn("skip loudness normalization", zap.String("operationId", operation.ID), zap.Error(err))
+			default:
+				return transcodeRenderResult{}, err
+			}
+		}
+		plan.audioFilter = buildTranscodeAudioFilter(processing, loudness)
+	}
+
+	ffmpegArgs, err := buildFFmpegTranscodeArgs(
+		plan,
+		sourceFile.Storage.LocalPath,
+		outputPath,
+		params.burninSubtitlePath,
+		params.embeddedSubtitlePath,
+		params.subtitleFormat,
+		params.subtitleHandling,
+		chaptersMetadataPath,
+	)
+	if err != nil {
+		return transcodeRenderResult{}, err
+	}
+
+	progressMessage := progressText("library.progressDetail.ffmpegRenderingOutput")
+	if params.variantCount > 1 {
+		progressMessage = progressTextTemplate("library.progressDetail.ffmpegRenderingVariant", map[string]string{
+			"current": strconv.Itoa(params.variantIndex + 1),
+			"total":   strconv.Itoa(params.variantCount),
+			"name":    displayName,
+		})
+	}
+	operation.Progress = buildOperationProgress(
+		service.now(),
+		progressText("library.progress.transcoding"),
+		0,
+		1,
+		progressMessage,
+	)
+	if err := service.saveAndPublishOperation(ctx, *operation); err != nil {
+		return transcodeRenderResult{}, err
+	}
+
+	reporter := newFFmpegProgressReporter(service, operation, probe.DurationMs)
+	reporter.message = progressMessage
+	outputText, err := service.runFFmpegCommandWithReporter(
+		ctx,
+		reporter,
+		params.ffmpegExecPath,
+		ffmpegArgs,
+		filepath.Dir(sourceFile.Storage.LocalPath),
+	)
+	if err != nil {
+		message := strings.TrimSpace(outputText)
+		if message == "" {
+			message = err.Error()
+		}
+		return transcodeRenderResult{}, fmt.Errorf("ffmpeg transcode failed: %s", message)
+	}
+	if !pathExists(outputPath) {
+		return transcodeRenderResult{}, fmt.Errorf("ffmpeg produced no output file")
+	}
+	// The encode pass prints loudnorm statistics again, including the loudness of the
+	// normalized output.
+	if loudness != nil {
+		if normalized, err := parseLoudnormMeasurement(outputText); err == nil {
+			if outputMedia == nil {
+				outputMedia = &library.MediaInfo{}
+			}
+			value := normalized.OutputI
+			outputMedia.LoudnessLUFS = &value
+		}
+	}
+
+	finishedAt := service.now()
+	outputFile, err := service.registerManagedLocalOutputFile(ctx, registeredLocalOutputParams{
+		LibraryID:     sourceFile.LibraryID,
+		RootFileID:    rootFileID(sourceFile),
+		Name:          displayName,
+		Kind:          string(library.FileKindTranscode),
+		OperationID:   operation.ID,
+		OperationKind: "transcode",
+		OutputPath:    outputPath,
+		SourceMedia:   outputMedia,
+		OccurredAt:    finishedAt,
+	})
+	if err != nil {
+		return transcodeRenderResult{}, err
+	}
+	outputFile.LatestOperationID = operation.ID
+	outputFile.UpdatedAt = finishedAt
+	if err := service.files.Save(ctx, outputFile); err != nil {
+		return transcodeRenderResult{}, err
+	}
+	return transcodeRenderResult{file: outputFile, displayName: displayName, outputPath: outputPath}, nil
+}
+
+// buildTranscodeVariantRequests expands a request into the primary output followed
+// by one preset-only request per extra variant. Variants take their name from the
+// preset, so the custom title only applies to the primary output.
+func buildTranscodeVariantRequests(request dto.CreateTranscodeJobRequest) []dto.CreateTranscodeJobRequest {
+	primary := request
+	primary.VariantPresetIDs = nil
+	result := []dto.CreateTranscodeJobRequest{primary}
+	for _, presetID := range normalizeTranscodeVariantPresetIDs(request) {
+		variant := primary
+		variant.PresetID = presetID
+		variant.Title = ""
+		result = append(result, variant)
+	}
+	return result
+}
+
+func normalizeTranscodeVariantPresetIDs(request dto.CreateTranscodeJobRequest) []string {
+	primaryID := strings.TrimSpace(request.PresetID)
+	seen := make(map[string]struct{}, len(request.VariantPresetIDs))
+	result := make([]string, 0, len(request.VariantPresetIDs))
+	for _, value := range request.VariantPresetIDs {
+		presetID := strings.TrimSpace(value)
+		if presetID == "" || presetID == primaryID {
+			continue
+		}
+		if _, ok := seen[presetID]; ok {
+			continue
+		}
+		seen[presetID] = struct{}{}
+		result = append(result, presetID)
+	}
+	return result
+}
+
+func (service *LibraryService) validateTranscodeVariantPresets(ctx context.Context, request dto.CreateTranscodeJobRequest) error {
+	presetIDs := normalizeTranscodeVariantPresetIDs(request)
+	if len(presetIDs) > maxTranscodeVariants {
+		return fmt.Errorf("at most %d transcode variants are supported", maxTranscodeVariants)
+	}
+	for _, presetID := range presetIDs {
+		if _, err := service.getTranscodePreset(ctx, presetID); err != nil {
+			return fmt.Errorf("variant preset %s: %w", presetID, err)
+		}
+	}
+	return nil
+}
@@ -83,6 +83,12 @@ func (service *LibraryService) buildWorkspaceProjectDTO(
 		case "subtitle_qa_review":
 			task := toWorkspaceTaskSummaryDTO(operation)
 			bucket.QA = &task
+		case "subtitle_align":
+			task := toWorkspaceTaskSummaryDTO(operation)
+			bucket.Align = &task
+		case "subtitle_bilingual_merge", "subtitle_bilingual_split":
+			task := toWorkspaceTaskSummaryDTO(operation)
+			bucket.Bilingual = &task
 		}
 		taskBuckets[fileID] = bucket
 	}
@@ -233,6 +239,12 @@ func resolveWorkspaceOperationSourceFileID(operation library.LibraryOperation) s
 		return extractSubtitleProofreadRequest(operation.InputJSON).FileID
 	case "subtitle_qa_review":
 		return extractSubtitleQAReviewRequest(operation.InputJSON).FileID
+	case "subtitle_align":
+		return extractSubtitleAlignRequest(operation.InputJSON).FileID
+	case "subtitle_bilingual_merge":
+		return extractSubtitleBilingualMergeRequest(operation.InputJSON).PrimaryFileID
+	case "subtitle_bilingual_split":
+		return extractSubtitleBilingualSplitRequest(operation.InputJSON).FileID
 	default:
 		return ""
 	}
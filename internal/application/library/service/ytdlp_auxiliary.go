@@ -82,6 +82,7 @@ func (service *LibraryService) downloadYTDLPSubtitles(
 		SubtitleTemplate: subtitleTemplate,
 		CookiesPath:      cookiesPath,
 		ProxyURL:         service.resolveYTDLPProxy(request.URL),
+		RateLimit:        service.resolveYTDLPRateLimit(ctx),
 	})
 	if err != nil {
 		step.warning = fmt.Sprintf("subtitle download failed: %v", err)
@@ -76,7 +76,7 @@ func (service *LibraryService) scheduleAutoRetryYTDLP(ctx context.Context, opera
 	retryRequest := withYTDLPOperationLibrary(request, operation)
 	retryRequest.RetryOf = operation.ID
 	retryRequest.RetryCount = request.RetryCount + 1
-	newOperation, newHistory, _, err := service.createDownloadOperation(ctx, retryRequest)
+	newOperation, newHistory, _, err := service.createDownloadOperation(ctx, retryRequest, "")
 	if err != nil {
 		return "", false
 	}
@@ -179,7 +179,7 @@ func (service *LibraryService) RetryYTDLPOperation(ctx context.Context, request
 	input = withYTDLPOperationLibrary(input, operation)
 	input.RetryOf = operation.ID
 	input.RetryCount = input.RetryCount + 1
-	newOperation, newHistory, _, err := service.createDownloadOperation(ctx, input)
+	newOperation, newHistory, _, err := service.createDownloadOperation(ctx, input, "")
 	if err != nil {
 		return dto.LibraryOperationDTO{}, err
 	}
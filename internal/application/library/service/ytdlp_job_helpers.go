@@ -110,7 +110,7 @@ func (prefetch *ytdlpThumbnailPrefetch) Cleanup() {
 	_ = os.Remove(filepath.Dir(tempPath))
 }
 
-func (service *LibraryService) createDownloadOperation(ctx context.Context, request dto.CreateYTDLPJobRequest) (library.LibraryOperation, library.HistoryRecord, library.Library, error) {
+func (service *LibraryService) createDownloadOperation(ctx context.Context, request dto.CreateYTDLPJobRequest, parentOperationID string) (library.LibraryOperation, library.HistoryRecord, library.Library, error) {
 	displayName := strings.TrimSpace(request.Title)
 	if displayName == "" {
 		displayName = strings.TrimSpace(request.URL)
@@ -131,7 +131,7 @@ func (service *LibraryService) createDownloadOperation(ctx context.Context, requ
 	if err != nil {
 		return library.LibraryOperation{}, library.HistoryRecord{}, library.Library{}, err
 	}
-	correlation := library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID)}
+	correlation := library.OperationCorrelation{RunID: strings.TrimSpace(request.RunID), ParentOperationID: strings.TrimSpace(parentOperationID)}
 	meta := library.OperationMeta{Platform: strings.TrimSpace(request.Extractor), Uploader: strings.TrimSpace(request.Author)}
 	sourceDomain := extractRegistrableDomain(request.URL)
 	sourceIcon := ""
@@ -196,6 +196,17 @@ func (service *LibraryService) createDownloadOperation(ctx context.Context, requ
 }
 
 func (service *LibraryService) runYTDLPOperation(ctx context.Context, operation library.LibraryOperation, history library.HistoryRecord, request dto.CreateYTDLPJobRequest) {
+	release, err := service.acquireOperationSlot(ctx, operation, request.Priority)
+	if err != nil {
+		history.Status = string(library.OperationStatusCanceled)
+		history.UpdatedAt = service.now()
+		if saveErr := service.histories.Save(ctx, history); saveErr == nil {
+			service.publishHistoryUpdate(toHistoryDTO(history))
+		}
+		return
+	}
+	defer release()
+
 	started := service.now()
 	operation.Status = library.OperationStatusRunning
 	operation.StartedAt = &started
@@ -247,6 +258,7 @@ func (service *LibraryService) runYTDLPOperation(ctx context.Context, operation
 		OutputTemplate: outputTemplate,
 		CookiesPath:    cookiesPath,
 		ProxyURL:       service.resolveYTDLPProxy(request.URL),
+		RateLimit:      service.resolveYTDLPRateLimit(ctx),
 	})
 	if err != nil {
 		service.failYTDLPOperation(ctx, &operation, &history, err, resolveYTDLPErrorCode("", err), "")
@@ -313,7 +325,7 @@ func (service *LibraryService) runYTDLPOperation(ctx context.Context, operation
 	if title == "" {
 		title = strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
 	}
-	primaryFile, err := service.createDownloadedPrimaryFile(ctx, operation, request, title, outputPath, started)
+	primaryFile, err := service.createDownloadedPrimaryFile(ctx, operation, request, title, outputPath, result.Metadata, started)
 	if err != nil {
 		logSnapshot := service.persistYTDLPLogs(ctx, operation, result, persistLogsOnFailure, persistLogsOnSuccess, nil)
 		origin := appytdlp.Origin{Source: strings.TrimSpace(request.Source), RunID: strings.TrimSpace(request.RunID), Caller: strings.TrimSpace(request.Caller)}
@@ -401,6 +413,7 @@ func (service *LibraryService) runYTDLPOperation(ctx context.Context, operation
 			Source:                         "YTDLP",
 			RunID:                          strings.TrimSpace(request.RunID),
 			DeleteSourceFileAfterTranscode: request.DeleteSourceFileAfterTranscode,
+			Priority:                       request.Priority,
 		})
 	}
 }
@@ -598,7 +611,7 @@ func (service *LibraryService) buildYTDLPOutputs(ctx context.Context, request dt
 	return ytdlpOutputSnapshot{files: files, outputFiles: outputs, outputPaths: dedupePaths(candidatePaths), resolvedSubtitles: resolvedSubtitlePaths, resolvedThumbnails: resolvedThumbnailPaths}, nil
 }
 
-func (service *LibraryService) createDownloadedPrimaryFile(ctx context.Context, operation library.LibraryOperation, request dto.CreateYTDLPJobRequest, title string, outputPath string, createdAt time.Time) (library.LibraryFile, error) {
+func (service *LibraryService) createDownloadedPrimaryFile(ctx context.Context, operation library.LibraryOperation, request dto.CreateYTDLPJobRequest, title string, outputPath string, metadata []map[string]any, createdAt time.Time) (library.LibraryFile, error) {
 	kind := string(library.FileKindVideo)
 	if strings.EqualFold(strings.TrimSpace(request.Quality), "audio") {
 		kind = string(library.FileKindAudio)
@@ -608,6 +621,12 @@ func (service *LibraryService) createDownloadedPrimaryFile(ctx context.Context,
 		return library.LibraryFile{}, err
 	}
 	media := probe.toMediaInfo()
+	// Info JSON chapters refer to the full video, so they only apply to whole downloads.
+	if len(request.DownloadSections) == 0 {
+		if chapters := resolveYTDLPChapters(metadata, probe.DurationMs); len(chapters) > 0 {
+			media.Chapters = chapters
+		}
+	}
 	fileItem, err := library.NewLibraryFile(library.LibraryFileParams{
 		ID:                uuid.NewString(),
 		LibraryID:         operation.LibraryID,
@@ -0,0 +1,601 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"dreamcreator/internal/application/library/dto"
+	"dreamcreator/internal/domain/library"
+)
+
+const (
+	ytdlpPlaylistDefaultLimit       = 500
+	ytdlpPlaylistMaxLimit           = 5000
+	ytdlpPlaylistDefaultConcurrency = 2
+	ytdlpPlaylistMaxConcurrency     = 6
+)
+
+const (
+	ytdlpPlaylistKindVideo    = "video"
+	ytdlpPlaylistKindPlaylist = "playlist"
+	ytdlpPlaylistKindChannel  = "channel"
+)
+
+type ytdlpPlaylistOutput struct {
+	URL                 string                     `json:"url"`
+	Status              string                     `json:"status"`
+	MaxConcurrency      int                        `json:"maxConcurrency,omitempty"`
+	TotalEntryCount     int                        `json:"totalEntryCount"`
+	CompletedEntryCount int                        `json:"completedEntryCount"`
+	FailedEntryCount    int                        `json:"failedEntryCount"`
+	ChildOperationIDs   []string                   `json:"childOperationIds,omitempty"`
+	Entries             []ytdlpPlaylistEntryResult `json:"entries,omitempty"`
+}
+
+// ytdlpPlaylistEntryResult tracks the child download of one playlist entry. The
+// entry index is stable across restarts so a recovered playlist reuses its children.
+type ytdlpPlaylistEntryResult struct {
+	Index       int    `json:"index"`
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Status      string `json:"status"`
+	OperationID string `json:"operationId,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ytdlpPlaylistRun struct {
+	service   *LibraryService
+	ctx       context.Context
+	runCtx    context.Context
+	request   dto.CreateYTDLPPlaylistJobRequest
+	previous  map[int]ytdlpPlaylistEntryResult
+	mu        sync.Mutex
+	operation library.LibraryOperation
+	output    ytdlpPlaylistOutput
+	doneCount int
+}
+
+func (service *LibraryService) CreateYTDLPPlaylistJob(ctx context.Context, request dto.CreateYTDLPPlaylistJobRequest) (dto.LibraryOperationDTO, error) {
+	request = normalizeYTDLPPlaylistJobRequest(request)
+	resolvedURL, sourceDomain, err := validateDownloadURL(request.URL)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	request.URL = resolvedURL
+	if len(request.Entries) == 0 {
+		return dto.LibraryOperationDTO{}, fmt.Errorf("at least one playlist entry is required")
+	}
+	operationID := uuid.NewString()
+	libraryItem, err := service.ensureLibrary(ctx, ensureLibraryParams{
+		LibraryID:          request.LibraryID,
+		FallbackName:       request.Title,
+		InitialNameFromID:  request.Title == "",
+		CreatedBySource:    "download",
+		TriggerOperationID: operationID,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	request.LibraryID = libraryItem.ID
+	inputJSON, err := json.Marshal(request)
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	sourceIcon := ""
+	if service.iconResolver != nil && sourceDomain != "" {
+		if icon, iconErr := service.iconResolver.ResolveDomainIcon(ctx, sourceDomain); iconErr == nil {
+			sourceIcon = icon
+		}
+	}
+	now := service.now()
+	total := len(request.Entries)
+	operation, err := library.NewLibraryOperation(library.LibraryOperationParams{
+		ID:           operationID,
+		LibraryID:    libraryItem.ID,
+		Kind:         "download_playlist",
+		Status:       string(library.OperationStatusQueued),
+		DisplayName:  buildYTDLPPlaylistDisplayName(request),
+		Correlation:  library.OperationCorrelation{RunID: request.RunID},
+		InputJSON:    string(inputJSON),
+		OutputJSON:   marshalJSON(newYTDLPPlaylistOutput(request, "queued")),
+		SourceDomain: sourceDomain,
+		SourceIcon:   sourceIcon,
+		Meta:         library.OperationMeta{Platform: request.Extractor, Uploader: request.Author},
+		Progress: buildOperationProgress(
+			now,
+			progressText("library.status.queued"),
+			0,
+			total,
+			progressText("library.progressDetail.downloadPlaylistQueued"),
+		),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	if err := service.touchLibrary(ctx, libraryItem.ID, now); err != nil {
+		return dto.LibraryOperationDTO{}, err
+	}
+	operationDTO := toOperationDTO(operation)
+	service.publishOperationUpdate(operationDTO)
+	go service.runYTDLPPlaylistOperation(context.Background(), operation, request)
+	return operationDTO, nil
+}
+
+// runYTDLPPlaylistOperation downloads every chosen entry as a child download
+// operation. Cancel stops launching new entries; running downloads finish.
+func (service *LibraryService) runYTDLPPlaylistOperation(ctx context.Context, operation library.LibraryOperation, request dto.CreateYTDLPPlaylistJobRequest) {
+	request = normalizeYTDLPPlaylistJobRequest(request)
+	runCtx, cancel := context.WithCancel(ctx)
+	service.registerOperationRun(operation.ID, cancel)
+	defer func() {
+		cancel()
+		service.unregisterOperationRun(operation.ID)
+	}()
+	previous := make(map[int]ytdlpPlaylistEntryResult)
+	if existing, ok := parseYTDLPPlaylistOutput(operation.OutputJSON); ok {
+		for _, entry := range existing.Entries {
+			previous[entry.Index] = entry
+		}
+	}
+	run := &ytdlpPlaylistRun{
+		service:   service,
+		ctx:       ctx,
+		runCtx:    runCtx,
+		request:   request,
+		previous:  previous,
+		operation: operation,
+		output:    newYTDLPPlaylistOutput(request, "running"),
+	}
+	now := service.now()
+	run.operation.Status = library.OperationStatusRunning
+	run.operation.StartedAt = &now
+	run.operation.Progress = run.buildProgress(now)
+	run.operation.OutputJSON = marshalJSON(run.output)
+	if err := service.saveAndPublishOperation(ctx, run.operation); err != nil {
+		return
+	}
+
+	slots := make(chan struct{}, request.MaxConcurrency)
+	var wg sync.WaitGroup
+	for position := range request.Entries {
+		select {
+		case <-runCtx.Done():
+		case slots <- struct{}{}:
+		}
+		if run.canceled() {
+			break
+		}
+		wg.Add(1)
+		go func(position int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			run.runEntry(position)
+		}(position)
+	}
+	wg.Wait()
+
+	if runCtx.Err() != nil || service.isSubtitleOperationCanceled(ctx, operation.ID) {
+		_, _ = service.markSubtitleOperationCanceled(ctx, operation.ID)
+		return
+	}
+	service.finishYTDLPPlaylistOperation(ctx, run)
+}
+
+func (run *ytdlpPlaylistRun) runEntry(position int) {
+	entry := run.request.Entries[position]
+	result := ytdlpPlaylistEntryResult{Index: entry.Index, URL: entry.URL, Title: entry.Title, Status: "running"}
+	if previous, ok := run.previous[entry.Index]; ok && previous.OperationID != "" {
+		if item, err := run.service.operations.Get(run.ctx, previous.OperationID); err == nil && item.Status != library.OperationStatusFailed && item.Status != library.OperationStatusCanceled {
+			result.OperationID = item.ID
+			run.trackEntry(position, result)
+			if item.Status == library.OperationStatusQueued || item.Status == library.OperationStatusRunning {
+				childRequest := dto.CreateYTDLPJobRequest{}
+				if err := json.Unmarshal([]byte(item.InputJSON), &childRequest); err == nil {
+					if history, err := run.service.findOrRebuildOperationHistory(run.ctx, item, childRequest); err == nil {
+						run.service.runYTDLPOperation(context.Background(), item, history, childRequest)
+					}
+				}
+			}
+			run.completeEntry(position, run.collectChild(result))
+			return
+		}
+	}
+	childRequest := run.childRequest(entry)
+	item, history, _, err := run.service.createDownloadOperation(run.ctx, childRequest, run.operation.ID)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		run.completeEntry(position, result)
+		return
+	}
+	result.OperationID = item.ID
+	run.trackEntry(position, result)
+	run.service.runYTDLPOperation(context.Background(), item, history, withYTDLPOperationLibrary(childRequest, item))
+	run.completeEntry(position, run.collectChild(result))
+}
+
+func (run *ytdlpPlaylistRun) collectChild(result ytdlpPlaylistEntryResult) ytdlpPlaylistEntryResult {
+	item, err := run.service.operations.Get(run.ctx, result.OperationID)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	if item.Status == library.OperationStatusSucceeded {
+		result.Status = "succeeded"
+		return result
+	}
+	result.Status = "failed"
+	result.Error = firstNonEmpty(strings.TrimSpace(item.ErrorMessage), fmt.Sprintf("child operation ended as %s", item.Status))
+	return result
+}
+
+// childRequest shares the playlist download settings with one entry. Entries
+// without a direct link are downloaded from the playlist by their index.
+func (run *ytdlpPlaylistRun) childRequest(entry dto.YTDLPPlaylistEntry) dto.CreateYTDLPJobRequest {
+	request := run.request
+	child := dto.CreateYTDLPJobRequest{
+		URL:                            entry.URL,
+		LibraryID:                      request.LibraryID,
+		Title:                          entry.Title,
+		Extractor:                      request.Extractor,
+		Author:                         firstNonEmpty(entry.Author, request.Author),
+		ThumbnailURL:                   entry.ThumbnailURL,
+		WriteThumbnail:                 request.WriteThumbnail,
+		Source:                         request.Source,
+		Caller:                         request.Caller,
+		SessionKey:                     request.SessionKey,
+		RunID:                          request.RunID,
+		LogPolicy:                      request.LogPolicy,
+		Priority:                       request.Priority,
+		Quality:                        request.Quality,
+		FormatID:                       request.FormatID,
+		SubtitleLangs:                  append([]string(nil), request.SubtitleLangs...),
+		SubtitleAuto:                   request.SubtitleAuto,
+		SubtitleAll:                    request.SubtitleAll,
+		SubtitleFormat:                 request.SubtitleFormat,
+		TranscodePresetID:              request.TranscodePresetID,
+		DeleteSourceFileAfterTranscode: request.DeleteSourceFileAfterTranscode,
+		ConnectorID:                    request.ConnectorID,
+		UseConnector:                   request.UseConnector,
+	}
+	if _, _, err := validateDownloadURL(entry.URL); err != nil {
+		child.URL = request.URL
+		child.PlaylistItems = strconv.Itoa(entry.Index)
+	}
+	return child
+}
+
+func (run *ytdlpPlaylistRun) canceled() bool {
+	return run.runCtx.Err() != nil || run.service.isSubtitleOperationCanceled(run.ctx, run.operation.ID)
+}
+
+func (run *ytdlpPlaylistRun) trackEntry(position int, result ytdlpPlaylistEntryResult) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.storeEntryLocked(position, result)
+	run.persistLocked()
+}
+
+func (run *ytdlpPlaylistRun) completeEntry(position int, result ytdlpPlaylistEntryResult) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.storeEntryLocked(position, result)
+	run.doneCount++
+	if result.Status == "succeeded" {
+		run.output.CompletedEntryCount++
+	} else {
+		run.output.FailedEntryCount++
+	}
+	run.persistLocked()
+}
+
+func (run *ytdlpPlaylistRun) storeEntryLocked(position int, result ytdlpPlaylistEntryResult) {
+	if result.OperationID != "" && !slices.Contains(run.output.ChildOperationIDs, result.OperationID) {
+		run.output.ChildOperationIDs = append(run.output.ChildOperationIDs, result.OperationID)
+	}
+	run.output.Entries[position] = result
+}
+
+func (run *ytdlpPlaylistRun) persistLocked() {
+	if run.service.isSubtitleOperationCanceled(run.ctx, run.operation.ID) {
+		return
+	}
+	run.operation.Progress = run.buildProgress(run.service.now())
+	run.operation.OutputJSON = marshalJSON(run.output)
+	_ = run.service.saveAndPublishOperation(run.ctx, run.operation)
+}
+
+func (run *ytdlpPlaylistRun) buildProgress(now time.Time) *library.OperationProgress {
+	return buildOperationProgress(
+		now,
+		progressText("library.progress.downloadingPlaylist"),
+		run.doneCount,
+		max(run.output.TotalEntryCount, 1),
+		progressTextTemplate("library.progressDetail.downloadPlaylistProgress", map[string]string{
+			"current": fmt.Sprintf("%d", run.doneCount),
+			"total":   fmt.Sprintf("%d", run.output.TotalEntryCount),
+			"failed":  fmt.Sprintf("%d", run.output.FailedEntryCount),
+		}),
+	)
+}
+
+func (service *LibraryService) finishYTDLPPlaylistOperation(ctx context.Context, run *ytdlpPlaylistRun) {
+	run.mu.Lock()
+	operation := run.operation
+	output := run.output
+	run.mu.Unlock()
+
+	processed := make([]library.LibraryFile, 0, len(output.ChildOperationIDs))
+	outputFiles := make([]library.OperationOutputFile, 0, len(output.ChildOperationIDs))
+	for _, operationID := range output.ChildOperationIDs {
+		child, err := service.operations.Get(ctx, operationID)
+		if err != nil || child.Status != library.OperationStatusSucceeded {
+			continue
+		}
+		for _, outputFile := range child.OutputFiles {
+			item, err := service.files.Get(ctx, outputFile.FileID)
+			if err != nil {
+				continue
+			}
+			processed = append(processed, item)
+			outputFiles = append(outputFiles, outputFile)
+		}
+	}
+	finishedAt := service.now()
+	operation.FinishedAt = &finishedAt
+	operation.OutputFiles = outputFiles
+	operation.Metrics = buildOperationMetricsForOperation(processed, operation.StartedAt, &finishedAt)
+	total := output.TotalEntryCount
+	if output.FailedEntryCount > 0 {
+		output.Status = "failed"
+		operation.Status = library.OperationStatusFailed
+		operation.ErrorCode = "download_playlist_failed"
+		operation.ErrorMessage = fmt.Sprintf("%d of %d playlist entries failed", output.FailedEntryCount, total)
+		operation.Progress = buildOperationProgress(
+			finishedAt,
+			progressText("library.status.failed"),
+			total,
+			max(total, 1),
+			progressTextTemplate("library.progressDetail.downloadPlaylistPartiallyFailed", map[string]string{
+				"failed": fmt.Sprintf("%d", output.FailedEntryCount),
+				"total":  fmt.Sprintf("%d", total),
+			}),
+		)
+	} else {
+		output.Status = "completed"
+		operation.Status = library.OperationStatusSucceeded
+		operation.Progress = buildOperationProgress(
+			finishedAt,
+			progressText("library.status.succeeded"),
+			total,
+			max(total, 1),
+			progressText("library.progressDetail.downloadPlaylistCompleted"),
+		)
+	}
+	operation.OutputJSON = marshalJSON(output)
+	if err := service.operations.Save(ctx, operation); err != nil {
+		return
+	}
+	history, err := library.NewHistoryRecord(library.HistoryRecordParams{
+		ID:          uuid.NewString(),
+		LibraryID:   operation.LibraryID,
+		Category:    "operation",
+		Action:      operation.Kind,
+		DisplayName: operation.DisplayName,
+		Status:      string(operation.Status),
+		Source: library.HistoryRecordSource{
+			Kind:   resolveHistorySourceKind(run.request.Source),
+			Caller: run.request.Caller,
+			RunID:  run.request.RunID,
+		},
+		Refs:    library.HistoryRecordRefs{OperationID: operation.ID},
+		Files:   operation.OutputFiles,
+		Metrics: operation.Metrics,
+		OperationMeta: &library.OperationRecordMeta{
+			Kind:         operation.Kind,
+			ErrorCode:    operation.ErrorCode,
+			ErrorMessage: operation.ErrorMessage,
+		},
+		OccurredAt: &finishedAt,
+		CreatedAt:  &finishedAt,
+		UpdatedAt:  &finishedAt,
+	})
+	if err == nil && service.histories.Save(ctx, history) == nil {
+		service.publishHistoryUpdate(toHistoryDTO(history))
+	}
+	_ = service.touchLibrary(ctx, operation.LibraryID, finishedAt)
+	service.publishOperationUpdate(toOperationDTO(operation))
+}
+
+// resolveYTDLPPlaylistURLKind classifies a download URL as a playlist, a channel,
+// or a video opened from a playlist. Plain video URLs return an empty kind.
+func resolveYTDLPPlaylistURLKind(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(strings.TrimPrefix(parsed.Hostname(), "www."))
+	path := strings.ToLower(strings.TrimSuffix(parsed.Path, "/"))
+	hasList := strings.TrimSpace(parsed.Query().Get("list")) != ""
+	switch {
+	case host == "space.bilibili.com":
+		if strings.Contains(path, "/favlist") || strings.Contains(path, "/lists") || strings.Contains(path, "/channel/") {
+			return ytdlpPlaylistKindPlaylist
+		}
+		return ytdlpPlaylistKindChannel
+	case strings.HasSuffix(host, "bilibili.com") && (strings.HasPrefix(path, "/medialist/") || strings.HasPrefix(path, "/list/")):
+		return ytdlpPlaylistKindPlaylist
+	case path == "/playlist" && hasList:
+		return ytdlpPlaylistKindPlaylist
+	case strings.HasPrefix(path, "/watch") && hasList:
+		return ytdlpPlaylistKindVideo
+	case strings.HasSuffix(host, "youtube.com") && (strings.HasPrefix(path, "/@") || strings.HasPrefix(path, "/channel/") || strings.HasPrefix(path, "/c/") || strings.HasPrefix(path, "/user/")):
+		return ytdlpPlaylistKindChannel
+	case strings.HasSuffix(path, "/videos") || strings.HasSuffix(path, "/playlists") || strings.Contains(path, "/playlist/"):
+		return ytdlpPlaylistKindPlaylist
+	default:
+		return ""
+	}
+}
+
+// normalizeYTDLPPlaylistURL points a bare YouTube channel at its videos tab, since
+// the channel root lists tabs instead of videos.
+func normalizeYTDLPPlaylistURL(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !strings.HasSuffix(strings.ToLower(parsed.Hostname()), "youtube.com") {
+		return rawURL
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	isChannel := (len(segments) == 1 && strings.HasPrefix(segments[0], "@")) ||
+		(len(segments) == 2 && (segments[0] == "channel" || segments[0] == "c" || segments[0] == "user"))
+	if !isChannel {
+		return rawURL
+	}
+	parsed.Path = "/" + strings.Join(segments, "/") + "/videos"
+	return parsed.String()
+}
+
+// buildYTDLPPlaylistInfo turns flat-playlist info into selectable entries. It
+// returns nil when yt-dlp resolved a single video instead of a playlist.
+func buildYTDLPPlaylistInfo(info map[string]any, playlistURL string, urlKind string, limit int) *dto.YTDLPPlaylistInfo {
+	if getString(info, "_type") != "playlist" {
+		return nil
+	}
+	kind := ytdlpPlaylistKindPlaylist
+	if urlKind == ytdlpPlaylistKindChannel {
+		kind = ytdlpPlaylistKindChannel
+	}
+	rawEntries, _ := info["entries"].([]any)
+	entries := make([]dto.YTDLPPlaylistEntry, 0, len(rawEntries))
+	for position, raw := range rawEntries {
+		item, ok := raw.(map[string]any)
+		if !ok || getString(item, "_type") == "playlist" {
+			continue
+		}
+		index := getInt(item, "playlist_index")
+		if index <= 0 {
+			index = position + 1
+		}
+		entryURL := getString(item, "webpage_url", "original_url", "url")
+		entry := dto.YTDLPPlaylistEntry{
+			Index:        index,
+			ID:           getString(item, "id"),
+			URL:          entryURL,
+			Title:        getString(item, "title"),
+			Author:       resolveYTDLPAuthor(item),
+			ThumbnailURL: resolveYTDLPThumbnail(item),
+		}
+		if duration, ok := item["duration"].(float64); ok && duration > 0 {
+			entry.DurationSeconds = duration
+		}
+		entries = append(entries, entry)
+	}
+	playlistCount := getInt(info, "playlist_count")
+	truncated := false
+	if limit > 0 && len(rawEntries) >= limit {
+		truncated = playlistCount == 0 || playlistCount > len(rawEntries)
+	}
+	return &dto.YTDLPPlaylistInfo{
+		ID:         getString(info, "id"),
+		Kind:       kind,
+		Title:      getString(info, "title"),
+		Author:     resolveYTDLPAuthor(info),
+		URL:        firstNonEmpty(getString(info, "webpage_url"), playlistURL),
+		EntryCount: max(playlistCount, len(entries)),
+		Truncated:  truncated,
+		Entries:    entries,
+	}
+}
+
+func normalizeYTDLPPlaylistLimit(limit int) int {
+	if limit <= 0 {
+		return ytdlpPlaylistDefaultLimit
+	}
+	return min(limit, ytdlpPlaylistMaxLimit)
+}
+
+func normalizeYTDLPPlaylistJobRequest(request dto.CreateYTDLPPlaylistJobRequest) dto.CreateYTDLPPlaylistJobRequest {
+	request.URL = strings.TrimSpace(request.URL)
+	request.LibraryID = strings.TrimSpace(request.LibraryID)
+	request.Title = strings.TrimSpace(request.Title)
+	request.Extractor = strings.TrimSpace(request.Extractor)
+	request.Author = strings.TrimSpace(request.Author)
+	request.Source = strings.TrimSpace(request.Source)
+	request.Caller = strings.TrimSpace(request.Caller)
+	request.SessionKey = strings.TrimSpace(request.SessionKey)
+	request.RunID = strings.TrimSpace(request.RunID)
+	if request.MaxConcurrency <= 0 {
+		request.MaxConcurrency = ytdlpPlaylistDefaultConcurrency
+	}
+	request.MaxConcurrency = min(request.MaxConcurrency, ytdlpPlaylistMaxConcurrency)
+	entries := make([]dto.YTDLPPlaylistEntry, 0, len(request.Entries))
+	seen := make(map[int]struct{}, len(request.Entries))
+	for position, entry := range request.Entries {
+		entry.URL = strings.TrimSpace(entry.URL)
+		entry.Title = strings.TrimSpace(entry.Title)
+		if entry.Index <= 0 {
+			entry.Index = position + 1
+		}
+		if _, ok := seen[entry.Index]; ok {
+			continue
+		}
+		seen[entry.Index] = struct{}{}
+		entries = append(entries, entry)
+	}
+	request.Entries = entries
+	return request
+}
+
+func newYTDLPPlaylistOutput(request dto.CreateYTDLPPlaylistJobRequest, status string) ytdlpPlaylistOutput {
+	entries := make([]ytdlpPlaylistEntryResult, 0, len(request.Entries))
+	for _, entry := range request.Entries {
+		entries = append(entries, ytdlpPlaylistEntryResult{
+			Index:  entry.Index,
+			URL:    entry.URL,
+			Title:  entry.Title,
+			Status: "pending",
+		})
+	}
+	return ytdlpPlaylistOutput{
+		URL:             request.URL,
+		Status:          status,
+		MaxConcurrency:  request.MaxConcurrency,
+		TotalEntryCount: len(entries),
+		Entries:         entries,
+	}
+}
+
+func buildYTDLPPlaylistDisplayName(request dto.CreateYTDLPPlaylistJobRequest) string {
+	name := firstNonEmpty(request.Title, request.URL)
+	return fmt.Sprintf("%s (%d)", name, len(request.Entries))
+}
+
+func parseYTDLPPlaylistOutput(raw string) (ytdlpPlaylistOutput, bool) {
+	output := ytdlpPlaylistOutput{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &output); err != nil || output.Status == "" {
+		return ytdlpPlaylistOutput{}, false
+	}
+	return output, true
+}
+
+func extractYTDLPPlaylistRequest(inputJSON string) dto.CreateYTDLPPlaylistJobRequest {
+	request := dto.CreateYTDLPPlaylistJobRequest{}
+	_ = json.Unmarshal([]byte(strings.TrimSpace(inputJSON)), &request)
+	return normalizeYTDLPPlaylistJobRequest(request)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"dreamcreator/internal/application/library/dto"
+)
+
+func TestResolveYTDLPPlaylistURLKind(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"https://www.youtube.com/watch?v=abc":               "",
+		"https://youtu.be/abc":                              "",
+		"https://www.youtube.com/watch?v=abc&list=PL123":    ytdlpPlaylistKindVideo,
+		"https://www.youtube.com/playlist?list=PL123":       ytdlpPlaylistKindPlaylist,
+		"https://www.youtube.com/@creator":                  ytdlpPlaylistKindChannel,
+		"https://www.youtube.com/channel/UC123/videos":      ytdlpPlaylistKindChannel,
+		"https://space.bilibili.com/123":                    ytdlpPlaylistKindChannel,
+		"https://space.bilibili.com/123/favlist?fid=9":      ytdlpPlaylistKindPlaylist,
+		"https://www.bilibili.com/video/BV1xx411c7mD":       "",
+		"https://www.bilibili.com/medialist/detail/ml12345": ytdlpPlaylistKindPlaylist,
+	}
+	for rawURL, expected := range cases {
+		if kind := resolveYTDLPPlaylistURLKind(rawURL); kind != expected {
+			t.Errorf("resolveYTDLPPlaylistURLKind(%q) = %q, want %q", rawURL, kind, expected)
+		}
+	}
+	if normalized := normalizeYTDLPPlaylistURL("https://www.youtube.com/@creator"); normalized != "https://www.youtube.com/@creator/videos" {
+		t.Fatalf("expected the channel root to point at its videos tab, got %q", normalized)
+	}
+	if normalized := normalizeYTDLPPlaylistURL("https://www.youtube.com/@creator/shorts"); normalized != "https://www.youtube.com/@creator/shorts" {
+		t.Fatalf("expected an explicit channel tab to be kept, got %q", normalized)
+	}
+}
+
+func TestBuildYTDLPPlaylistInfoListsFlatEntries(t *testing.T) {
+	t.Parallel()
+
+	info := map[string]any{
+		"_type":          "playlist",
+		"id":             "PL123",
+		"title":          "Season 1",
+		"uploader":       "Creator",
+		"webpage_url":    "https://www.youtube.com/playlist?list=PL123",
+		"playlist_count": float64(40),
+		"entries": []any{
+			map[string]any{"id": "a", "url": "https://www.youtube.com/watch?v=a", "title": "Episode 1", "duration": float64(61)},
+			map[string]any{"_type": "playlist", "id": "nested", "url": "https://www.youtube.com/playlist?list=nested"},
+			map[string]any{"id": "c", "url": "c", "title": "Episode 3", "channel": "Guest"},
+		},
+	}
+	playlist := buildYTDLPPlaylistInfo(info, "https://www.youtube.com/playlist?list=PL123", ytdlpPlaylistKindPlaylist, 3)
+	if playlist == nil {
+		t.Fatalf("expected playlist info")
+	}
+	if playlist.Kind != ytdlpPlaylistKindPlaylist || playlist.EntryCount != 40 || !playlist.Truncated {
+		t.Fatalf("unexpected playlist summary %#v", playlist)
+	}
+	if len(playlist.Entries) != 2 || playlist.Entries[0].Index != 1 || playlist.Entries[0].DurationSeconds != 61 {
+		t.Fatalf("unexpected playlist entries %#v", playlist.Entries)
+	}
+	if playlist.Entries[1].Index != 3 || playlist.Entries[1].Author != "Guest" {
+		t.Fatalf("expected the nested playlist to be skipped without shifting indexes, got %#v", playlist.Entries[1])
+	}
+	if buildYTDLPPlaylistInfo(map[string]any{"_type": "video", "id": "a"}, "", "", 3) != nil {
+		t.Fatalf("expected a single video not to be treated as a playlist")
+	}
+
+	run := &ytdlpPlaylistRun{request: normalizeYTDLPPlaylistJobRequest(dto.CreateYTDLPPlaylistJobRequest{
+		URL:     "https://www.youtube.com/playlist?list=PL123",
+		Quality: "audio",
+		Entries: playlist.Entries,
+	})}
+	direct := run.childRequest(run.request.Entries[0])
+	if direct.URL != "https://www.youtube.com/watch?v=a" || direct.PlaylistItems != "" || direct.Quality != "audio" {
+		t.Fatalf("expected the entry to be downloaded from its own URL, got %#v", direct)
+	}
+	fallback := run.childRequest(run.request.Entries[1])
+	if fallback.URL != run.request.URL || fallback.PlaylistItems != "3" {
+		t.Fatalf("expected the entry without a link to be selected from the playlist, got %#v", fallback)
+	}
+}
@@ -41,6 +41,7 @@ func (service *LibraryService) PrepareYTDLPDownload(ctx context.Context, request
 		Icon:               icon,
 		ConnectorID:        connectorID,
 		ConnectorAvailable: connectorAvailable,
+		Playlist:           resolveYTDLPPlaylistURLKind(resolvedURL) != "",
 	}, nil
 }
 
@@ -75,12 +76,21 @@ func (service *LibraryService) ParseYTDLPDownload(ctx context.Context, request d
 		}
 	}
 
+	playlistKind := resolveYTDLPPlaylistURLKind(resolvedURL)
+	playlistMode := playlistKind == ytdlpPlaylistKindPlaylist || playlistKind == ytdlpPlaylistKindChannel ||
+		(playlistKind == ytdlpPlaylistKindVideo && request.Playlist)
+	playlistLimit := normalizeYTDLPPlaylistLimit(request.PlaylistLimit)
+	if playlistMode {
+		resolvedURL = normalizeYTDLPPlaylistURL(resolvedURL)
+	}
 	info, err := appytdlp.FetchInfo(ctx, appytdlp.InfoOptions{
 		ExecPath:    "",
 		Tools:       service.tools,
 		URL:         resolvedURL,
 		CookiesPath: cookiesPath,
 		ProxyURL:    service.resolveYTDLPProxy(resolvedURL),
+		Playlist:    playlistMode,
+		PlaylistEnd: playlistLimit,
 	})
 	if err != nil {
 		return dto.ParseYTDLPDownloadResponse{}, err
@@ -93,6 +103,18 @@ func (service *LibraryService) ParseYTDLPDownload(ctx context.Context, request d
 	if domain == "" {
 		domain = extractRegistrableDomain(getString(info, "webpage_url"))
 	}
+	if playlist := buildYTDLPPlaylistInfo(info, resolvedURL, playlistKind, playlistLimit); playlist != nil {
+		return dto.ParseYTDLPDownloadResponse{
+			Title:        firstNonEmpty(title, playlist.Title),
+			Domain:       domain,
+			Extractor:    extractor,
+			Author:       author,
+			ThumbnailURL: thumbnailURL,
+			Formats:      []dto.YTDLPFormatOption{},
+			Subtitles:    []dto.YTDLPSubtitleOption{},
+			Playlist:     playlist,
+		}, nil
+	}
 	formats := buildYTDLPFormatOptions(info)
 	subtitles := buildYTDLPSubtitleOptions(info)
 
@@ -230,7 +252,7 @@ func connectorTypeForDomain(domain string) connectors.ConnectorType {
 }
 
 func probeYTDLP(ctx context.Context, execPath string, targetURL string, cookiesPath string, resolver ToolResolver) error {
-	args := []string{"--no-playlist", "--skip-download", "--print", "extractor"}
+	args := append(appytdlp.PlaylistArgs(""), "--skip-download", "--print", "extractor")
 	if explicitToolArgs := appytdlp.BuildExplicitToolArgs(ctx, resolver); len(explicitToolArgs) > 0 {
 		args = append(args, explicitToolArgs...)
 	}
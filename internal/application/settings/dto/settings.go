@@ -115,6 +115,7 @@ type GatewaySettings struct {
 	VoiceEnabled              bool                     `json:"voiceEnabled"`
 	VoiceWakeEnabled          bool                     `json:"voiceWakeEnabled"`
 	SandboxEnabled            bool                     `json:"sandboxEnabled"`
+	Sandbox                   GatewaySandboxSettings   `json:"sandbox"`
 	HTTP                      GatewayHTTPSettings      `json:"http"`
 	ChannelHealthCheckMinutes int                      `json:"channelHealthCheckMinutes"`
 	Runtime                   GatewayRuntimeSettings   `json:"runtime"`
@@ -271,6 +272,13 @@ type GatewaySubagentToolPolicy struct {
 	Deny      []string `json:"deny"`
 }
 
+type GatewaySandboxSettings struct {
+	AllowNetwork   bool `json:"allowNetwork"`
+	CPUSeconds     int  `json:"cpuSeconds"`
+	MemoryMB       int  `json:"memoryMb"`
+	TimeoutSeconds int  `json:"timeoutSeconds"`
+}
+
 type GatewayCronSettings struct {
 	Enabled           bool                     `json:"enabled"`
 	MaxConcurrentRuns int                      `json:"maxConcurrentRuns"`
@@ -335,6 +343,7 @@ type UpdateGatewaySettingsRequest struct {
 	VoiceEnabled              *bool                                  `json:"voiceEnabled,omitempty"`
 	VoiceWakeEnabled          *bool                                  `json:"voiceWakeEnabled,omitempty"`
 	SandboxEnabled            *bool                                  `json:"sandboxEnabled,omitempty"`
+	Sandbox                   *UpdateGatewaySandboxSettingsRequest   `json:"sandbox,omitempty"`
 	HTTP                      *UpdateGatewayHTTPSettingsRequest      `json:"http,omitempty"`
 	ChannelHealthCheckMinutes *int                                   `json:"channelHealthCheckMinutes,omitempty"`
 	Runtime                   *UpdateGatewayRuntimeSettingsRequest   `json:"runtime,omitempty"`
@@ -491,6 +500,13 @@ type UpdateGatewaySubagentToolPolicyRequest struct {
 	Deny      *[]string `json:"deny,omitempty"`
 }
 
+type UpdateGatewaySandboxSettingsRequest struct {
+	AllowNetwork   *bool `json:"allowNetwork,omitempty"`
+	CPUSeconds     *int  `json:"cpuSeconds,omitempty"`
+	MemoryMB       *int  `json:"memoryMb,omitempty"`
+	TimeoutSeconds *int  `json:"timeoutSeconds,omitempty"`
+}
+
 type UpdateGatewayCronSettingsRequest struct {
 	Enabled           *bool                                  `json:"enabled,omitempty"`
 	MaxConcurrentRuns *int                                   `json:"maxConcurrentRuns,omitempty"`
@@ -308,6 +308,20 @@ func (service *SettingsService) UpdateSettings(ctx context.Context, request dto.
 		if request.Gateway.SandboxEnabled != nil {
 			gateway.SandboxEnabled = *request.Gateway.SandboxEnabled
 		}
+		if request.Gateway.Sandbox != nil {
+			if request.Gateway.Sandbox.AllowNetwork != nil {
+				gateway.Sandbox.AllowNetwork = *request.Gateway.Sandbox.AllowNetwork
+			}
+			if request.Gateway.Sandbox.CPUSeconds != nil && *request.Gateway.Sandbox.CPUSeconds >= 0 {
+				gateway.Sandbox.CPUSeconds = *request.Gateway.Sandbox.CPUSeconds
+			}
+			if request.Gateway.Sandbox.MemoryMB != nil && *request.Gateway.Sandbox.MemoryMB >= 0 {
+				gateway.Sandbox.MemoryMB = *request.Gateway.Sandbox.MemoryMB
+			}
+			if request.Gateway.Sandbox.TimeoutSeconds != nil && *request.Gateway.Sandbox.TimeoutSeconds >= 0 {
+				gateway.Sandbox.TimeoutSeconds = *request.Gateway.Sandbox.TimeoutSeconds
+			}
+		}
 		if request.Gateway.VoiceWakeEnabled != nil {
 			gateway.VoiceWakeEnabled = *request.Gateway.VoiceWakeEnabled
 		}
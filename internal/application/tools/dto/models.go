@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ToolSpec struct {
 	ID               string            `json:"id"`
 	Name             string            `json:"name"`
@@ -53,6 +55,7 @@ type ToolPolicyContext struct {
 	AgentID         string `json:"agentId,omitempty"`
 	ProviderID      string `json:"providerId,omitempty"`
 	Source          string `json:"source,omitempty"`
+	WorkspacePath   string `json:"workspacePath,omitempty"`
 	IsSubagent      bool   `json:"isSubagent,omitempty"`
 	RequireSandbox  bool   `json:"requireSandbox,omitempty"`
 	RequireApproval bool   `json:"requireApproval,omitempty"`
@@ -91,11 +94,37 @@ type RegisterToolRequest struct {
 	Spec ToolSpec `json:"spec"`
 }
 
+type UnregisterToolRequest struct {
+	ID string `json:"id"`
+}
+
 type EnableToolRequest struct {
 	ID      string `json:"id"`
 	Enabled bool   `json:"enabled"`
 }
 
+// ToolPolicyRule is a user-defined rule that allows, denies or asks for
+// approval of tool calls matching its tool, action and argument predicates.
+type ToolPolicyRule struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name,omitempty"`
+	Tool            string    `json:"tool,omitempty"`
+	Action          string    `json:"action,omitempty"`
+	Decision        string    `json:"decision"`
+	Reason          string    `json:"reason,omitempty"`
+	CommandPrefixes []string  `json:"commandPrefixes,omitempty"`
+	CommandPatterns []string  `json:"commandPatterns,omitempty"`
+	PathGlobs       []string  `json:"pathGlobs,omitempty"`
+	Domains         []string  `json:"domains,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type DeleteToolPolicyRuleRequest struct {
+	ID string `json:"id"`
+}
+
 type ExecuteToolRequest struct {
 	Invocation ToolInvocation `json:"invocation"`
 }
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"dreamcreator/internal/application/tools/dto"
+	"dreamcreator/internal/infrastructure/toolpolicyrepo"
+)
+
+var errPolicyRulesUnavailable = errors.New("policy rules unavailable")
+
+type PolicyRuleStore interface {
+	List(ctx context.Context) ([]toolpolicyrepo.PolicyRule, error)
+	Save(ctx context.Context, rule toolpolicyrepo.PolicyRule) (toolpolicyrepo.PolicyRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PolicyRuleService manages the user-defined tool policy rules on behalf of
+// the presentation layer.
+type PolicyRuleService struct {
+	store PolicyRuleStore
+}
+
+func NewPolicyRuleService(store PolicyRuleStore) *PolicyRuleService {
+	return &PolicyRuleService{store: store}
+}
+
+func (service *PolicyRuleService) ListPolicyRules(ctx context.Context) ([]dto.ToolPolicyRule, error) {
+	if service == nil || service.store == nil {
+		return nil, errPolicyRulesUnavailable
+	}
+	rules, err := service.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.ToolPolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, toPolicyRuleDTO(rule))
+	}
+	return result, nil
+}
+
+func (service *PolicyRuleService) SavePolicyRule(ctx context.Context, rule dto.ToolPolicyRule) (dto.ToolPolicyRule, error) {
+	if service == nil || service.store == nil {
+		return dto.ToolPolicyRule{}, errPolicyRulesUnavailable
+	}
+	saved, err := service.store.Save(ctx, fromPolicyRuleDTO(rule))
+	if err != nil {
+		return dto.ToolPolicyRule{}, err
+	}
+	return toPolicyRuleDTO(saved), nil
+}
+
+func (service *PolicyRuleService) DeletePolicyRule(ctx context.Context, request dto.DeleteToolPolicyRuleRequest) error {
+	if service == nil || service.store == nil {
+		return errPolicyRulesUnavailable
+	}
+	return service.store.Delete(ctx, request.ID)
+}
+
+func toPolicyRuleDTO(rule toolpolicyrepo.PolicyRule) dto.ToolPolicyRule {
+	return dto.ToolPolicyRule{
+		ID:              rule.ID,
+		Name:            rule.Name,
+		Tool:            rule.Tool,
+		Action:          rule.Action,
+		Decision:        rule.Decision,
+		Reason:          rule.Reason,
+		CommandPrefixes: append([]string(nil), rule.CommandPrefixes...),
+		CommandPatterns: append([]string(nil), rule.CommandPatterns...),
+		PathGlobs:       append([]string(nil), rule.PathGlobs...),
+		Domains:         append([]string(nil), rule.Domains...),
+		Enabled:         rule.Enabled,
+		CreatedAt:       rule.CreatedAt,
+		UpdatedAt:       rule.UpdatedAt,
+	}
+}
+
+func fromPolicyRuleDTO(rule dto.ToolPolicyRule) toolpolicyrepo.PolicyRule {
+	return toolpolicyrepo.PolicyRule{
+		ID:              rule.ID,
+		Name:            rule.Name,
+		Tool:            rule.Tool,
+		Action:          rule.Action,
+		Decision:        rule.Decision,
+		Reason:          rule.Reason,
+		CommandPrefixes: append([]string(nil), rule.CommandPrefixes...),
+		CommandPatterns: append([]string(nil), rule.CommandPatterns...),
+		PathGlobs:       append([]string(nil), rule.PathGlobs...),
+		Domains:         append([]string(nil), rule.Domains...),
+		Enabled:         rule.Enabled,
+		CreatedAt:       rule.CreatedAt,
+		UpdatedAt:       rule.UpdatedAt,
+	}
+}
@@ -82,6 +82,24 @@ func (service *ToolService) RegisterTool(_ context.Context, request dto.Register
 	return spec, nil
 }
 
+func (service *ToolService) UnregisterTool(_ context.Context, request dto.UnregisterToolRequest) {
+	if service == nil {
+		return
+	}
+	id := strings.TrimSpace(request.ID)
+	if id == "" {
+		return
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	spec, ok := service.tools[id]
+	if !ok {
+		return
+	}
+	delete(service.tools, spec.ID)
+	delete(service.tools, spec.Name)
+}
+
 func (service *ToolService) EnableTool(_ context.Context, request dto.EnableToolRequest) error {
 	id := strings.TrimSpace(request.ID)
 	if id == "" {
@@ -13,9 +13,55 @@ import (
 	ydlpinfr "dreamcreator/internal/infrastructure/ytdlp"
 )
 
+// PlaylistArgs keeps single downloads to the linked video unless specific playlist
+// items are requested, in which case only those entries are downloaded.
+func PlaylistArgs(playlistItems string) []string {
+	items := strings.TrimSpace(playlistItems)
+	if items == "" {
+		return []string{"--no-playlist"}
+	}
+	return []string{"--yes-playlist", "--playlist-items", items}
+}
+
+// DownloadSectionArgs limits the download to the requested time ranges.
+func DownloadSectionArgs(sections []dto.YTDLPDownloadSection) []string {
+	args := make([]string, 0, len(sections)*2)
+	for _, section := range sections {
+		end := "inf"
+		if section.EndMs > 0 {
+			end = formatSectionTimestamp(section.EndMs)
+		}
+		args = append(args, "--download-sections", "*"+formatSectionTimestamp(section.StartMs)+"-"+end)
+	}
+	return args
+}
+
+// ValidateDownloadSections rejects negative starts and ranges that end before they begin.
+func ValidateDownloadSections(sections []dto.YTDLPDownloadSection) error {
+	for index, section := range sections {
+		if section.StartMs < 0 || section.EndMs < 0 {
+			return fmt.Errorf("download section %d has a negative time", index+1)
+		}
+		if section.EndMs > 0 && section.EndMs <= section.StartMs {
+			return fmt.Errorf("download section %d must end after it starts", index+1)
+		}
+	}
+	return nil
+}
+
+func formatSectionTimestamp(valueMs int64) string {
+	if valueMs < 0 {
+		valueMs = 0
+	}
+	hours := valueMs / 3_600_000
+	minutes := (valueMs / 60_000) % 60
+	seconds := (valueMs / 1000) % 60
+	millis := valueMs % 1000
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, seconds, millis)
+}
+
 func BuildArgs(request dto.CreateYTDLPJobRequest, outputTemplate string, printFilePath string, cookiesPath string, explicitToolArgs []string, proxyURL string) []string {
-	args := []string{
-		"--no-playlist",
+	args := append(PlaylistArgs(request.PlaylistItems),
 		"--newline",
 		"--progress",
 		"--progress-template",
@@ -25,7 +71,7 @@ func BuildArgs(request dto.CreateYTDLPJobRequest, outputTemplate string, printFi
 		"--no-simulate",
 		"-o",
 		outputTemplate,
-	}
+	)
 	if strings.TrimSpace(printFilePath) != "" {
 		args = append(args, "--print-to-file", "after_move:filepath", printFilePath)
 	} else {
@@ -37,6 +83,7 @@ func BuildArgs(request dto.CreateYTDLPJobRequest, outputTemplate string, printFi
 	if strings.TrimSpace(proxyURL) != "" {
 		args = append(args, "--proxy", proxyURL)
 	}
+	args = append(args, DownloadSectionArgs(request.DownloadSections)...)
 	formatArg := ""
 	if strings.EqualFold(strings.TrimSpace(request.Quality), "audio") {
 		formatArg = "ba/b"
@@ -60,8 +107,7 @@ func BuildArgs(request dto.CreateYTDLPJobRequest, outputTemplate string, printFi
 }
 
 func BuildSubtitleArgs(request dto.CreateYTDLPJobRequest, outputTemplate string, subtitleTemplate string, cookiesPath string, explicitToolArgs []string, proxyURL string) []string {
-	args := []string{
-		"--no-playlist",
+	args := append(PlaylistArgs(request.PlaylistItems),
 		"--newline",
 		"--progress",
 		"--progress-template",
@@ -72,7 +118,7 @@ func BuildSubtitleArgs(request dto.CreateYTDLPJobRequest, outputTemplate string,
 		"--skip-download",
 		"-o",
 		outputTemplate,
-	}
+	)
 	if strings.TrimSpace(subtitleTemplate) != "" {
 		args = append(args, "-o", "subtitle:"+subtitleTemplate)
 	}
@@ -165,6 +211,10 @@ func buildCommand(ctx context.Context, options CommandOptions, subtitleOnly bool
 		)
 	}
 
+	if rateLimit := strings.TrimSpace(options.RateLimit); rateLimit != "" {
+		args = append([]string{"--limit-rate", rateLimit}, args...)
+	}
+
 	timeout := options.Timeout
 	if timeout <= 0 {
 		timeout = 2 * time.Hour
@@ -199,3 +199,60 @@ func TestBuildSubtitleCommandUsesSubtitleArgsWithoutMutatingPATH(t *testing.T) {
 		t.Fatalf("expected subtitle command not to allocate print file, got %q", command.PrintFilePath)
 	}
 }
+
+func TestBuildArgsSelectsPlaylistItemsOnlyWhenRequested(t *testing.T) {
+	t.Parallel()
+
+	single := BuildArgs(dto.CreateYTDLPJobRequest{URL: "https://example.com/watch?v=1&list=PL1"}, "out.%(ext)s", "", "", nil, "")
+	if single[0] != "--no-playlist" || strings.Contains(strings.Join(single, " "), "--yes-playlist") {
+		t.Fatalf("expected a single video download, got %v", single)
+	}
+
+	entry := BuildSubtitleArgs(dto.CreateYTDLPJobRequest{URL: "https://example.com/playlist?list=PL1", PlaylistItems: " 7 "}, "out.%(ext)s", "", "", nil, "")
+	joined := strings.Join(entry, " ")
+	if strings.Contains(joined, "--no-playlist") || !strings.Contains(joined, "--yes-playlist --playlist-items 7") {
+		t.Fatalf("expected playlist item 7 to be selected, got %v", entry)
+	}
+}
+
+func TestBuildCommandAppliesRateLimit(t *testing.T) {
+	tempDir := t.TempDir()
+	command, err := BuildCommand(context.Background(), CommandOptions{
+		ExecPath:       filepath.Join(tempDir, "yt-dlp"),
+		Request:        dto.CreateYTDLPJobRequest{URL: "https://example.com/watch?v=1"},
+		OutputTemplate: filepath.Join(tempDir, "%(title)s.%(ext)s"),
+		RateLimit:      "2M",
+	})
+	if err != nil {
+		t.Fatalf("build command: %v", err)
+	}
+	defer command.Cancel()
+	if command.Cleanup != nil {
+		defer command.Cleanup()
+	}
+	if len(command.Args) < 2 || command.Args[0] != "--limit-rate" || command.Args[1] != "2M" {
+		t.Fatalf("expected a leading --limit-rate, got %v", command.Args)
+	}
+	if command.Args[len(command.Args)-1] != "https://example.com/watch?v=1" {
+		t.Fatalf("expected the URL to stay last, got %v", command.Args)
+	}
+}
+
+func TestBuildArgsAddsDownloadSections(t *testing.T) {
+	t.Parallel()
+
+	args := BuildArgs(dto.CreateYTDLPJobRequest{
+		URL: "https://example.com/watch?v=1",
+		DownloadSections: []dto.YTDLPDownloadSection{
+			{StartMs: 90_500, EndMs: 3_725_000},
+			{StartMs: 7_200_000},
+		},
+	}, "out.%(ext)s", "", "", nil, "")
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--download-sections *00:01:30.500-01:02:05.000 --download-sections *02:00:00.000-inf") {
+		t.Fatalf("expected both download sections, got %v", args)
+	}
+	if err := ValidateDownloadSections([]dto.YTDLPDownloadSection{{StartMs: 5000, EndMs: 5000}}); err == nil {
+		t.Fatalf("expected an empty range to be rejected")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"dreamcreator/internal/domain/externaltools"
@@ -29,7 +30,13 @@ func FetchInfo(ctx context.Context, options InfoOptions) (map[string]any, error)
 	if execPath == "" {
 		return nil, fmt.Errorf("yt-dlp exec path not resolved")
 	}
-	args := []string{"--no-playlist", "--skip-download", "--dump-json"}
+	args := append(PlaylistArgs(""), "--skip-download", "--dump-json")
+	if options.Playlist {
+		args = []string{"--yes-playlist", "--flat-playlist", "--skip-download", "--dump-single-json"}
+		if options.PlaylistEnd > 0 {
+			args = append(args, "--playlist-end", strconv.Itoa(options.PlaylistEnd))
+		}
+	}
 	if explicitToolArgs := BuildExplicitToolArgs(ctx, options.Tools); len(explicitToolArgs) > 0 {
 		args = append(args, explicitToolArgs...)
 	}
@@ -44,6 +44,7 @@ type CommandOptions struct {
 	ThumbnailTemplate string
 	CookiesPath       string
 	ProxyURL          string
+	RateLimit         string
 	Timeout           time.Duration
 }
 
@@ -53,4 +54,7 @@ type InfoOptions struct {
 	URL         string
 	CookiesPath string
 	ProxyURL    string
+	// Playlist lists playlist and channel entries flat instead of resolving one video.
+	Playlist    bool
+	PlaylistEnd int
 }
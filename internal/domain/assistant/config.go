@@ -15,6 +15,9 @@ const (
 
 	AvatarSourceBuiltin = "builtin"
 	AvatarSourceUser    = "user"
+
+	MCPTransportStdio = "stdio"
+	MCPTransportHTTP  = "http"
 )
 
 const (
@@ -89,7 +92,8 @@ type AssistantModel struct {
 }
 
 type AssistantTools struct {
-	Items []AssistantToolItem `json:"items,omitempty"`
+	Items      []AssistantToolItem  `json:"items,omitempty"`
+	MCPServers []AssistantMCPServer `json:"mcpServers,omitempty"`
 }
 
 type AssistantToolItem struct {
@@ -97,6 +101,23 @@ type AssistantToolItem struct {
 	Enabled bool   `json:"enabled"`
 }
 
+// AssistantMCPServer describes an external Model Context Protocol server whose
+// tools are mounted for the assistant. Stdio servers are spawned from Command,
+// HTTP servers are reached through the streamable HTTP transport at URL.
+type AssistantMCPServer struct {
+	ID              string            `json:"id,omitempty"`
+	Transport       string            `json:"transport,omitempty"`
+	Command         string            `json:"command,omitempty"`
+	Args            []string          `json:"args,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	WorkingDir      string            `json:"workingDir,omitempty"`
+	URL             string            `json:"url,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	TimeoutSeconds  int               `json:"timeoutSeconds,omitempty"`
+	RequireApproval bool              `json:"requireApproval,omitempty"`
+	Enabled         bool              `json:"enabled"`
+}
+
 type AssistantSkills struct {
 	Mode              string `json:"mode,omitempty"`
 	MaxSkillsInPrompt int    `json:"maxSkillsInPrompt,omitempty"`
@@ -239,8 +260,9 @@ func normalizeModelConfig(model ModelConfig) ModelConfig {
 }
 
 func normalizeAssistantTools(tools AssistantTools) AssistantTools {
+	servers := normalizeAssistantMCPServers(tools.MCPServers)
 	if len(tools.Items) == 0 {
-		return AssistantTools{}
+		return AssistantTools{MCPServers: servers}
 	}
 	seen := make(map[string]struct{}, len(tools.Items))
 	items := make([]AssistantToolItem, 0, len(tools.Items))
@@ -257,9 +279,115 @@ func normalizeAssistantTools(tools AssistantTools) AssistantTools {
 		items = append(items, AssistantToolItem{ID: id, Enabled: item.Enabled})
 	}
 	if len(items) == 0 {
-		return AssistantTools{}
+		items = nil
+	}
+	return AssistantTools{Items: items, MCPServers: servers}
+}
+
+func normalizeAssistantMCPServers(servers []AssistantMCPServer) []AssistantMCPServer {
+	if len(servers) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(servers))
+	result := make([]AssistantMCPServer, 0, len(servers))
+	for _, server := range servers {
+		id := NormalizeMCPServerID(server.ID)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		server.ID = id
+		server.Command = strings.TrimSpace(server.Command)
+		server.URL = strings.TrimSpace(server.URL)
+		server.WorkingDir = strings.TrimSpace(server.WorkingDir)
+		server.Transport = normalizeMCPTransport(server.Transport, server.Command, server.URL)
+		switch server.Transport {
+		case MCPTransportStdio:
+			if server.Command == "" {
+				continue
+			}
+			server.URL = ""
+			server.Headers = nil
+		case MCPTransportHTTP:
+			if server.URL == "" {
+				continue
+			}
+			server.Command = ""
+			server.Args = nil
+			server.Env = nil
+			server.WorkingDir = ""
+		}
+		args := make([]string, 0, len(server.Args))
+		for _, arg := range server.Args {
+			if arg == "" {
+				continue
+			}
+			args = append(args, arg)
+		}
+		if len(args) == 0 {
+			args = nil
+		}
+		server.Args = args
+		server.Env = normalizeStringMap(server.Env)
+		server.Headers = normalizeStringMap(server.Headers)
+		if server.TimeoutSeconds < 0 {
+			server.TimeoutSeconds = 0
+		}
+		seen[id] = struct{}{}
+		result = append(result, server)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// NormalizeMCPServerID lowercases the id and keeps the characters that are
+// valid inside a namespaced tool name.
+func NormalizeMCPServerID(id string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(id)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			builder.WriteRune(r)
+		case r == '_', r == ' ', r == '.':
+			builder.WriteRune('-')
+		}
 	}
-	return AssistantTools{Items: items}
+	return strings.Trim(builder.String(), "-")
+}
+
+func normalizeMCPTransport(transport string, command string, url string) string {
+	switch strings.ToLower(strings.TrimSpace(transport)) {
+	case MCPTransportStdio:
+		return MCPTransportStdio
+	case MCPTransportHTTP, "https", "streamable-http", "streamable_http", "streamablehttp":
+		return MCPTransportHTTP
+	}
+	if command == "" && url != "" {
+		return MCPTransportHTTP
+	}
+	return MCPTransportStdio
+}
+
+func normalizeStringMap(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		trimmed := strings.TrimSpace(key)
+		if trimmed == "" {
+			continue
+		}
+		result[trimmed] = value
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
 }
 
 func normalizeAssistantSkills(skills AssistantSkills) AssistantSkills {
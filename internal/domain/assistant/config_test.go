@@ -73,3 +73,32 @@ func TestNormalizeAssistantSkillsDefaultsAndLimits(t *testing.T) {
 		t.Fatalf("unexpected max prompt chars: %d", item.Skills.MaxPromptChars)
 	}
 }
+
+func TestNormalizeAssistantToolsKeepsMCPServers(t *testing.T) {
+	item, err := NewAssistant(AssistantParams{
+		ID: "assistant-4",
+		Tools: AssistantTools{
+			MCPServers: []AssistantMCPServer{
+				{ID: " Files_Server ", Command: " npx ", Args: []string{"-y", "", "server-filesystem"}, Enabled: true},
+				{ID: "files-server", Command: "duplicate"},
+				{ID: "remote", Transport: "streamable-http", URL: " https://example.com/mcp ", Command: "ignored"},
+				{ID: "broken", Transport: "http"},
+				{ID: "  "},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new assistant failed: %v", err)
+	}
+
+	servers := item.Tools.MCPServers
+	if len(servers) != 2 {
+		t.Fatalf("unexpected servers: %#v", servers)
+	}
+	if servers[0].ID != "files-server" || servers[0].Transport != MCPTransportStdio || servers[0].Command != "npx" || len(servers[0].Args) != 2 {
+		t.Fatalf("unexpected stdio server: %#v", servers[0])
+	}
+	if servers[1].Transport != MCPTransportHTTP || servers[1].URL != "https://example.com/mcp" || servers[1].Command != "" {
+		t.Fatalf("unexpected http server: %#v", servers[1])
+	}
+}
@@ -8,10 +8,12 @@ import (
 type ToolName string
 
 const (
-	ToolYTDLP   ToolName = "yt-dlp"
-	ToolFFmpeg  ToolName = "ffmpeg"
-	ToolBun     ToolName = "bun"
-	ToolClawHub ToolName = "clawhub"
+	ToolYTDLP      ToolName = "yt-dlp"
+	ToolFFmpeg     ToolName = "ffmpeg"
+	ToolBun        ToolName = "bun"
+	ToolClawHub    ToolName = "clawhub"
+	ToolWhisperCPP ToolName = "whisper-cpp"
+	ToolTesseract  ToolName = "tesseract"
 )
 
 type ToolKind string
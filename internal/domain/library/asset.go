@@ -35,21 +35,27 @@ type FileOrigin struct {
 }
 
 type FileLineage struct {
-	RootFileID string
+	RootFileID    string
+	SourceFileIDs []string
 }
 
 type MediaInfo struct {
-	Format      string
-	Codec       string
-	VideoCodec  string
-	AudioCodec  string
-	DurationMs  *int64
-	Width       *int
-	Height      *int
-	FrameRate   *float64
-	BitrateKbps *int
-	Channels    *int
-	SizeBytes   *int64
+	Format          string
+	Codec           string
+	VideoCodec      string
+	AudioCodec      string
+	DurationMs      *int64
+	Width           *int
+	Height          *int
+	FrameRate       *float64
+	BitrateKbps     *int
+	Channels        *int
+	SizeBytes       *int64
+	LoudnessLUFS    *float64
+	Chapters        []MediaChapter
+	SubtitleStreams []MediaSubtitleStream
+	// CoverFileID references the thumbnail file chosen as this file's cover.
+	CoverFileID string
 }
 
 type FileState struct {
@@ -136,7 +142,7 @@ func NewLibraryFile(params LibraryFileParams) (LibraryFile, error) {
 		origin.OperationID = ""
 	} else {
 		switch origin.Kind {
-		case "download", "transcode", "subtitle_translate", "subtitle_proofread":
+		case "download", "transcode", "subtitle_translate", "subtitle_proofread", "subtitle_transcribe", "subtitle_bilingual_merge", "subtitle_bilingual_split", "clip", "chapter_split", "subtitle_ocr", "subtitle_extract", "thumbnail_generate":
 			if origin.OperationID == "" || origin.Import != nil {
 				return LibraryFile{}, ErrInvalidLibraryFile
 			}
@@ -164,7 +170,7 @@ func NewLibraryFile(params LibraryFileParams) (LibraryFile, error) {
 		Name:              name,
 		Storage:           storage,
 		Origin:            origin,
-		Lineage:           FileLineage{RootFileID: strings.TrimSpace(params.Lineage.RootFileID)},
+		Lineage:           normalizeFileLineage(params.Lineage),
 		LatestOperationID: strings.TrimSpace(params.LatestOperationID),
 		Media:             params.Media,
 		State:             state,
@@ -172,3 +178,20 @@ func NewLibraryFile(params LibraryFileParams) (LibraryFile, error) {
 		UpdatedAt:         updatedAt,
 	}, nil
 }
+
+func normalizeFileLineage(lineage FileLineage) FileLineage {
+	result := FileLineage{RootFileID: strings.TrimSpace(lineage.RootFileID)}
+	seen := make(map[string]struct{}, len(lineage.SourceFileIDs))
+	for _, value := range lineage.SourceFileIDs {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result.SourceFileIDs = append(result.SourceFileIDs, trimmed)
+	}
+	return result
+}
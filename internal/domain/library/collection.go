@@ -2,6 +2,7 @@ package library
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 	"unicode"
@@ -83,6 +84,14 @@ type LanguageTaskRuntimeConfig struct {
 	Proofread LanguageTaskRuntimeSettings
 }
 
+// OperationQueueConfig bounds how many download and transcode operations run at
+// once. RateLimit is passed to yt-dlp as --limit-rate, e.g. "2M" or "500K".
+type OperationQueueConfig struct {
+	DownloadConcurrency  int
+	TranscodeConcurrency int
+	RateLimit            string
+}
+
 type ModuleConfig struct {
 	Retention          RetentionConfig
 	Workspace          WorkspaceConfig
@@ -90,7 +99,9 @@ type ModuleConfig struct {
 	TranslateLanguages TranslateLanguagesConfig
 	LanguageAssets     LanguageAssetsConfig
 	SubtitleStyles     SubtitleStyleConfig
+	SubtitleDelivery   SubtitleDeliveryConfig
 	TaskRuntime        LanguageTaskRuntimeConfig
+	Queue              OperationQueueConfig
 }
 
 type Library struct {
@@ -130,8 +141,10 @@ func DefaultModuleConfig() ModuleConfig {
 			GlossaryProfiles: nil,
 			PromptProfiles:   nil,
 		},
-		SubtitleStyles: defaultSubtitleStyleConfig(),
-		TaskRuntime:    defaultLanguageTaskRuntimeConfig(),
+		SubtitleStyles:   defaultSubtitleStyleConfig(),
+		SubtitleDelivery: defaultSubtitleDeliveryConfig(),
+		TaskRuntime:      defaultLanguageTaskRuntimeConfig(),
+		Queue:            defaultOperationQueueConfig(),
 	}
 }
 
@@ -143,6 +156,7 @@ func NormalizeModuleConfig(config ModuleConfig) ModuleConfig {
 		result.TranslateLanguages = normalizeTranslateLanguagesConfig(result.TranslateLanguages)
 		result.LanguageAssets = normalizeLanguageAssetsConfig(result.LanguageAssets)
 		result.SubtitleStyles = normalizeSubtitleStyleConfig(result.SubtitleStyles)
+		result.SubtitleDelivery = normalizeSubtitleDeliveryConfig(result.SubtitleDelivery)
 	}
 	if strings.TrimSpace(result.SubtitleStorage.DownloadPolicy) == "" {
 		result.SubtitleStorage.DownloadPolicy = DefaultModuleConfig().SubtitleStorage.DownloadPolicy
@@ -151,6 +165,7 @@ func NormalizeModuleConfig(config ModuleConfig) ModuleConfig {
 		result.SubtitleStorage.ImportPolicy = DefaultModuleConfig().SubtitleStorage.ImportPolicy
 	}
 	result.TaskRuntime = normalizeLanguageTaskRuntimeConfig(result.TaskRuntime)
+	result.Queue = normalizeOperationQueueConfig(result.Queue)
 	return result
 }
 
@@ -614,3 +629,50 @@ func normalizeAssetID(explicit string, name string, fallback string) string {
 	}
 	return normalized
 }
+
+const (
+	DefaultDownloadConcurrency  = 3
+	DefaultTranscodeConcurrency = 2
+	MaxOperationConcurrency     = 16
+)
+
+var operationRateLimitPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[KMG]?$`)
+
+func defaultOperationQueueConfig() OperationQueueConfig {
+	return OperationQueueConfig{
+		DownloadConcurrency:  DefaultDownloadConcurrency,
+		TranscodeConcurrency: DefaultTranscodeConcurrency,
+	}
+}
+
+func normalizeOperationQueueConfig(config OperationQueueConfig) OperationQueueConfig {
+	return OperationQueueConfig{
+		DownloadConcurrency:  normalizeOperationConcurrency(config.DownloadConcurrency, DefaultDownloadConcurrency),
+		TranscodeConcurrency: normalizeOperationConcurrency(config.TranscodeConcurrency, DefaultTranscodeConcurrency),
+		RateLimit:            NormalizeOperationRateLimit(config.RateLimit),
+	}
+}
+
+func normalizeOperationConcurrency(value int, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	if value > MaxOperationConcurrency {
+		return MaxOperationConcurrency
+	}
+	return value
+}
+
+// NormalizeOperationRateLimit returns the rate in yt-dlp's --limit-rate syntax, or
+// an empty string when the value is blank or not a rate.
+func NormalizeOperationRateLimit(value string) string {
+	trimmed := strings.ToUpper(strings.TrimSpace(value))
+	trimmed = strings.TrimSuffix(strings.TrimSuffix(trimmed, "/S"), "B")
+	if trimmed == "" || !operationRateLimitPattern.MatchString(trimmed) {
+		return ""
+	}
+	if strings.Trim(trimmed, "0.KMG") == "" {
+		return ""
+	}
+	return trimmed
+}
@@ -50,3 +50,58 @@ func TestNormalizeModuleConfigCollapsesLegacyThinkingLevelsToOn(t *testing.T) {
 		t.Fatalf("expected proofread thinking mode on, got %q", got.TaskRuntime.Proofread.ThinkingMode)
 	}
 }
+
+func TestNormalizeModuleConfigKeepsBuiltinDeliveryProfiles(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultModuleConfig()
+	config.SubtitleDelivery = SubtitleDeliveryConfig{
+		Custom: []SubtitleDeliveryProfile{
+			{Name: "Broadcast", MaxCPS: 17, MaxCharsPerLine: 37, MaxLines: 2, MinDurationMs: 1500, MaxDurationMs: 1000},
+			{ID: "netflix", Name: "Shadowed builtin"},
+		},
+		DefaultProfileID: "broadcast",
+	}
+
+	got := NormalizeModuleConfig(config).SubtitleDelivery
+	if len(got.Builtin) != 2 {
+		t.Fatalf("expected builtin delivery profiles to be restored, got %d", len(got.Builtin))
+	}
+	if len(got.Custom) != 1 || got.Custom[0].ID != "broadcast" {
+		t.Fatalf("expected one custom profile with generated id, got %#v", got.Custom)
+	}
+	if got.Custom[0].MaxDurationMs != 0 {
+		t.Fatalf("expected max duration below min duration to be cleared, got %d", got.Custom[0].MaxDurationMs)
+	}
+	profile, ok := got.ResolveSubtitleDeliveryProfile("")
+	if !ok || profile.ID != "broadcast" {
+		t.Fatalf("expected default profile broadcast, got %#v", profile)
+	}
+}
+
+func TestNormalizeModuleConfigClampsOperationQueue(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultModuleConfig()
+	config.Queue = OperationQueueConfig{
+		DownloadConcurrency:  0,
+		TranscodeConcurrency: 64,
+		RateLimit:            " 2.5mb/s ",
+	}
+
+	got := NormalizeModuleConfig(config).Queue
+	if got.DownloadConcurrency != DefaultDownloadConcurrency {
+		t.Fatalf("expected default download concurrency, got %d", got.DownloadConcurrency)
+	}
+	if got.TranscodeConcurrency != MaxOperationConcurrency {
+		t.Fatalf("expected transcode concurrency to be clamped, got %d", got.TranscodeConcurrency)
+	}
+	if got.RateLimit != "2.5M" {
+		t.Fatalf("expected normalized rate limit, got %q", got.RateLimit)
+	}
+	for _, value := range []string{"fast", "0", "-1M", "1T"} {
+		if normalized := NormalizeOperationRateLimit(value); normalized != "" {
+			t.Fatalf("expected %q to be rejected, got %q", value, normalized)
+		}
+	}
+}
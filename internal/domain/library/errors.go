@@ -12,6 +12,8 @@ var (
 	ErrSubtitleDocumentNotFound    = errors.New("library subtitle document not found")
 	ErrSubtitleRevisionNotFound    = errors.New("library subtitle revision not found")
 	ErrSubtitleReviewSessionNotFound = errors.New("library subtitle review session not found")
+	ErrTranslationMemoryEntryNotFound = errors.New("library translation memory entry not found")
+	ErrSubscriptionNotFound        = errors.New("library subscription not found")
 	ErrInvalidLibrary              = errors.New("invalid library")
 	ErrInvalidLibraryFile          = errors.New("invalid library file")
 	ErrInvalidLibraryOperation     = errors.New("invalid library operation")
@@ -22,6 +24,9 @@ var (
 	ErrInvalidSubtitleDocument     = errors.New("invalid library subtitle document")
 	ErrInvalidSubtitleRevision     = errors.New("invalid library subtitle revision")
 	ErrInvalidSubtitleReviewSession = errors.New("invalid library subtitle review session")
+	ErrInvalidTranslationMemoryEntry = errors.New("invalid library translation memory entry")
+	ErrInvalidSubscription         = errors.New("invalid library subscription")
+	ErrInvalidMediaChapters        = errors.New("invalid media chapters")
 	ErrInvalidOperationOutput      = errors.New("invalid library operation output")
 	ErrPresetNotFound              = errors.New("transcode preset not found")
 	ErrInvalidPreset               = errors.New("invalid transcode preset")
@@ -106,7 +106,7 @@ func NewLibraryOperation(params LibraryOperationParams) (LibraryOperation, error
 		return LibraryOperation{}, ErrInvalidLibraryOperation
 	}
 	switch kind {
-	case "download", "transcode", "subtitle_translate", "subtitle_proofread", "subtitle_qa_review":
+	case "download", "transcode", "subtitle_translate", "subtitle_proofread", "subtitle_qa_review", "subtitle_transcribe", "subtitle_align", "subtitle_bilingual_merge", "subtitle_bilingual_split", "subtitle_glossary_extract", "subtitle_pipeline", "download_playlist", "clip", "chapter_split", "subtitle_ocr", "subtitle_extract", "thumbnail_generate":
 	default:
 		return LibraryOperation{}, ErrInvalidLibraryOperation
 	}
@@ -0,0 +1,55 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MediaChapter is a named time range of a video or audio file.
+type MediaChapter struct {
+	StartMs int64
+	EndMs   int64
+	Title   string
+}
+
+// NormalizeMediaChapters orders chapters by start time, closes open ends at the next
+// chapter or the media duration and names untitled chapters. A durationMs of zero
+// means the duration is unknown.
+func NormalizeMediaChapters(chapters []MediaChapter, durationMs int64) ([]MediaChapter, error) {
+	if len(chapters) == 0 {
+		return nil, nil
+	}
+	result := make([]MediaChapter, len(chapters))
+	copy(result, chapters)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartMs < result[j].StartMs
+	})
+	for index := range result {
+		chapter := &result[index]
+		chapter.Title = strings.TrimSpace(chapter.Title)
+		if chapter.Title == "" {
+			chapter.Title = fmt.Sprintf("Chapter %d", index+1)
+		}
+		if chapter.StartMs < 0 {
+			return nil, ErrInvalidMediaChapters
+		}
+		if index > 0 && chapter.StartMs == result[index-1].StartMs {
+			return nil, ErrInvalidMediaChapters
+		}
+		if durationMs > 0 && chapter.StartMs >= durationMs {
+			return nil, ErrInvalidMediaChapters
+		}
+		nextStart := durationMs
+		if index+1 < len(result) {
+			nextStart = result[index+1].StartMs
+		}
+		if chapter.EndMs <= chapter.StartMs || (nextStart > 0 && chapter.EndMs > nextStart) {
+			chapter.EndMs = nextStart
+		}
+		if chapter.EndMs <= chapter.StartMs {
+			return nil, ErrInvalidMediaChapters
+		}
+	}
+	return result, nil
+}
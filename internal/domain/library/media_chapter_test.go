@@ -0,0 +1,39 @@
+package library
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeMediaChaptersClosesEndsAndNamesChapters(t *testing.T) {
+	t.Parallel()
+
+	chapters, err := NormalizeMediaChapters([]MediaChapter{
+		{StartMs: 60_000, Title: " Main "},
+		{StartMs: 0},
+		{StartMs: 300_000, EndMs: 900_000, Title: "Outro"},
+	}, 600_000)
+	if err != nil {
+		t.Fatalf("normalize chapters: %v", err)
+	}
+	expected := []MediaChapter{
+		{StartMs: 0, EndMs: 60_000, Title: "Chapter 1"},
+		{StartMs: 60_000, EndMs: 300_000, Title: "Main"},
+		{StartMs: 300_000, EndMs: 600_000, Title: "Outro"},
+	}
+	for index, chapter := range expected {
+		if chapters[index] != chapter {
+			t.Fatalf("chapter %d: expected %#v, got %#v", index, chapter, chapters[index])
+		}
+	}
+
+	if _, err := NormalizeMediaChapters([]MediaChapter{{StartMs: 0}, {StartMs: 0}}, 0); !errors.Is(err, ErrInvalidMediaChapters) {
+		t.Fatalf("expected duplicate starts to be rejected, got %v", err)
+	}
+	if _, err := NormalizeMediaChapters([]MediaChapter{{StartMs: 0}, {StartMs: 700_000}}, 600_000); !errors.Is(err, ErrInvalidMediaChapters) {
+		t.Fatalf("expected a chapter after the end to be rejected, got %v", err)
+	}
+	if _, err := NormalizeMediaChapters([]MediaChapter{{StartMs: 5_000}}, 0); !errors.Is(err, ErrInvalidMediaChapters) {
+		t.Fatalf("expected an open-ended last chapter without a duration to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package library
+
+import "strings"
+
+// MediaSubtitleStream is a subtitle stream embedded in a video container. Index is
+// the absolute stream index reported by ffprobe, usable as an ffmpeg 0:<index> map.
+type MediaSubtitleStream struct {
+	Index     int
+	Codec     string
+	Language  string
+	Title     string
+	Default   bool
+	Forced    bool
+	TextBased bool
+}
+
+// IsTextSubtitleCodec reports whether an embedded subtitle codec carries text that
+// can be converted to an editable subtitle. Bitmap codecs such as PGS, VobSub and
+// DVB subtitles need OCR instead.
+func IsTextSubtitleCodec(codec string) bool {
+	switch strings.ToLower(strings.TrimSpace(codec)) {
+	case "subrip", "srt", "ass", "ssa", "mov_text", "webvtt", "text", "subviewer", "subviewer1", "microdvd", "mpl2", "jacosub", "realtext", "sami", "stl", "ttml":
+		return true
+	default:
+		return false
+	}
+}
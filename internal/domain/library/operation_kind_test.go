@@ -0,0 +1,64 @@
+package library
+
+import "testing"
+
+func TestOperationKindsAreAcceptedByLibraryRecords(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		kind          string
+		producesFiles bool
+	}{
+		{kind: "subtitle_transcribe", producesFiles: true},
+		{kind: "subtitle_align"},
+		{kind: "subtitle_bilingual_merge", producesFiles: true},
+		{kind: "subtitle_bilingual_split", producesFiles: true},
+		{kind: "subtitle_glossary_extract"},
+		{kind: "subtitle_pipeline"},
+		{kind: "download_playlist"},
+		{kind: "clip", producesFiles: true},
+		{kind: "chapter_split", producesFiles: true},
+		{kind: "subtitle_ocr", producesFiles: true},
+		{kind: "subtitle_extract", producesFiles: true},
+		{kind: "thumbnail_generate", producesFiles: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.kind, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := NewLibraryOperation(LibraryOperationParams{
+				ID:          "op-" + tc.kind,
+				LibraryID:   "lib-1",
+				Kind:        tc.kind,
+				Status:      string(OperationStatusQueued),
+				DisplayName: tc.kind,
+			}); err != nil {
+				t.Fatalf("expected %s operations to be valid: %v", tc.kind, err)
+			}
+			if _, err := NewHistoryRecord(HistoryRecordParams{
+				ID:          "history-" + tc.kind,
+				LibraryID:   "lib-1",
+				Category:    "operation",
+				Action:      tc.kind,
+				DisplayName: tc.kind,
+				Status:      string(OperationStatusSucceeded),
+			}); err != nil {
+				t.Fatalf("expected %s history records to be valid: %v", tc.kind, err)
+			}
+			if !tc.producesFiles {
+				return
+			}
+			if _, err := NewLibraryFile(LibraryFileParams{
+				ID:        "file-" + tc.kind,
+				LibraryID: "lib-1",
+				Kind:      string(FileKindVideo),
+				Name:      tc.kind,
+				Storage:   FileStorage{Mode: "local_path", LocalPath: "/tmp/" + tc.kind},
+				Origin:    FileOrigin{Kind: tc.kind, OperationID: "op-" + tc.kind},
+				State:     FileState{Status: "active"},
+			}); err != nil {
+				t.Fatalf("expected %s output files to be valid: %v", tc.kind, err)
+			}
+		})
+	}
+}
@@ -80,7 +80,7 @@ func NewHistoryRecord(params HistoryRecordParams) (HistoryRecord, error) {
 	switch category {
 	case "operation":
 		switch action {
-		case "download", "transcode", "subtitle_translate", "subtitle_proofread", "subtitle_qa_review":
+		case "download", "transcode", "subtitle_translate", "subtitle_proofread", "subtitle_qa_review", "subtitle_transcribe", "subtitle_align", "subtitle_bilingual_merge", "subtitle_bilingual_split", "subtitle_glossary_extract", "subtitle_pipeline", "download_playlist", "clip", "chapter_split", "subtitle_ocr", "subtitle_extract", "thumbnail_generate":
 		default:
 			return HistoryRecord{}, ErrInvalidHistoryRecord
 		}
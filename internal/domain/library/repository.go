@@ -1,6 +1,9 @@
 package library
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type LibraryRepository interface {
 	List(ctx context.Context) ([]Library, error)
@@ -64,6 +67,7 @@ type SubtitleDocumentRepository interface {
 
 type SubtitleRevisionRepository interface {
 	Get(ctx context.Context, id string) (SubtitleRevision, error)
+	ListByFileID(ctx context.Context, fileID string, limit int) ([]SubtitleRevision, error)
 	Save(ctx context.Context, item SubtitleRevision) error
 }
 
@@ -73,6 +77,22 @@ type SubtitleReviewSessionRepository interface {
 	Save(ctx context.Context, item SubtitleReviewSession) error
 }
 
+type TranslationMemoryRepository interface {
+	FindBySourceKeys(ctx context.Context, targetLanguage string, sourceKeys []string) ([]TranslationMemoryEntry, error)
+	ListCandidates(ctx context.Context, targetLanguage string, minSourceLength int, maxSourceLength int, limit int) ([]TranslationMemoryEntry, error)
+	Save(ctx context.Context, item TranslationMemoryEntry) error
+	MarkUsed(ctx context.Context, ids []string, usedAt time.Time) error
+}
+
+type SubscriptionRepository interface {
+	List(ctx context.Context) ([]Subscription, error)
+	Get(ctx context.Context, id string) (Subscription, error)
+	Save(ctx context.Context, item Subscription) error
+	Delete(ctx context.Context, id string) error
+	ListArchiveEntryIDs(ctx context.Context, subscriptionID string) ([]string, error)
+	SaveArchiveEntries(ctx context.Context, entries []SubscriptionArchiveEntry) error
+}
+
 type TranscodePresetRepository interface {
 	List(ctx context.Context) ([]TranscodePreset, error)
 	Get(ctx context.Context, id string) (TranscodePreset, error)
@@ -0,0 +1,144 @@
+package library
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSubscriptionPollIntervalMinutes = 60
+	MinSubscriptionPollIntervalMinutes     = 15
+	MaxSubscriptionPollIntervalMinutes     = 7 * 24 * 60
+)
+
+// Subscription follows a channel or playlist URL and downloads entries that are not
+// yet in its download archive each time the poll interval elapses.
+type Subscription struct {
+	ID                             string
+	Name                           string
+	URL                            string
+	LibraryID                      string
+	ConnectorID                    string
+	UseConnector                   bool
+	Quality                        string
+	FormatID                       string
+	SubtitleLangs                  []string
+	SubtitleAuto                   bool
+	SubtitleFormat                 string
+	TranscodePresetID              string
+	DeleteSourceFileAfterTranscode bool
+	PollIntervalMinutes            int
+	DownloadExisting               bool
+	Enabled                        bool
+	LastCheckedAt                  *time.Time
+	LastError                      string
+	CreatedAt                      time.Time
+	UpdatedAt                      time.Time
+}
+
+type SubscriptionParams struct {
+	ID                             string
+	Name                           string
+	URL                            string
+	LibraryID                      string
+	ConnectorID                    string
+	UseConnector                   bool
+	Quality                        string
+	FormatID                       string
+	SubtitleLangs                  []string
+	SubtitleAuto                   bool
+	SubtitleFormat                 string
+	TranscodePresetID              string
+	DeleteSourceFileAfterTranscode bool
+	PollIntervalMinutes            int
+	DownloadExisting               bool
+	Enabled                        bool
+	LastCheckedAt                  *time.Time
+	LastError                      string
+	CreatedAt                      *time.Time
+	UpdatedAt                      *time.Time
+}
+
+// SubscriptionArchiveEntry records an entry ID a subscription has already seen, with
+// the download operation it spawned when there was one.
+type SubscriptionArchiveEntry struct {
+	SubscriptionID string
+	EntryID        string
+	OperationID    string
+	CreatedAt      time.Time
+}
+
+func NewSubscription(params SubscriptionParams) (Subscription, error) {
+	id := strings.TrimSpace(params.ID)
+	url := strings.TrimSpace(params.URL)
+	if id == "" || url == "" {
+		return Subscription{}, ErrInvalidSubscription
+	}
+	lower := strings.ToLower(url)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return Subscription{}, ErrInvalidSubscription
+	}
+	interval := params.PollIntervalMinutes
+	if interval == 0 {
+		interval = DefaultSubscriptionPollIntervalMinutes
+	}
+	if interval < MinSubscriptionPollIntervalMinutes || interval > MaxSubscriptionPollIntervalMinutes {
+		return Subscription{}, ErrInvalidSubscription
+	}
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		name = url
+	}
+	createdAt := time.Now().UTC()
+	if params.CreatedAt != nil && !params.CreatedAt.IsZero() {
+		createdAt = params.CreatedAt.UTC()
+	}
+	updatedAt := createdAt
+	if params.UpdatedAt != nil && !params.UpdatedAt.IsZero() {
+		updatedAt = params.UpdatedAt.UTC()
+	}
+	var lastCheckedAt *time.Time
+	if params.LastCheckedAt != nil && !params.LastCheckedAt.IsZero() {
+		value := params.LastCheckedAt.UTC()
+		lastCheckedAt = &value
+	}
+	return Subscription{
+		ID:                             id,
+		Name:                           name,
+		URL:                            url,
+		LibraryID:                      strings.TrimSpace(params.LibraryID),
+		ConnectorID:                    strings.TrimSpace(params.ConnectorID),
+		UseConnector:                   params.UseConnector,
+		Quality:                        strings.TrimSpace(params.Quality),
+		FormatID:                       strings.TrimSpace(params.FormatID),
+		SubtitleLangs:                  normalizeSubscriptionLanguages(params.SubtitleLangs),
+		SubtitleAuto:                   params.SubtitleAuto,
+		SubtitleFormat:                 strings.ToLower(strings.TrimSpace(params.SubtitleFormat)),
+		TranscodePresetID:              strings.TrimSpace(params.TranscodePresetID),
+		DeleteSourceFileAfterTranscode: params.DeleteSourceFileAfterTranscode,
+		PollIntervalMinutes:            interval,
+		DownloadExisting:               params.DownloadExisting,
+		Enabled:                        params.Enabled,
+		LastCheckedAt:                  lastCheckedAt,
+		LastError:                      strings.TrimSpace(params.LastError),
+		CreatedAt:                      createdAt,
+		UpdatedAt:                      updatedAt,
+	}, nil
+}
+
+func normalizeSubscriptionLanguages(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result = append(result, trimmed)
+	}
+	return result
+}
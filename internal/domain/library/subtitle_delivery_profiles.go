@@ -0,0 +1,142 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubtitleDeliveryProfile describes the reading-speed and layout limits a
+// delivery platform enforces on subtitle cues. Zero limits are not checked.
+type SubtitleDeliveryProfile struct {
+	ID              string
+	Name            string
+	Description     string
+	MaxCPS          float64
+	MaxCharsPerLine int
+	MaxLines        int
+	MinGapMs        int64
+	MinDurationMs   int64
+	MaxDurationMs   int64
+}
+
+type SubtitleDeliveryConfig struct {
+	Builtin          []SubtitleDeliveryProfile
+	Custom           []SubtitleDeliveryProfile
+	DefaultProfileID string
+}
+
+func defaultSubtitleDeliveryConfig() SubtitleDeliveryConfig {
+	return SubtitleDeliveryConfig{
+		Builtin:          defaultBuiltinSubtitleDeliveryProfiles(),
+		Custom:           nil,
+		DefaultProfileID: "netflix",
+	}
+}
+
+func defaultBuiltinSubtitleDeliveryProfiles() []SubtitleDeliveryProfile {
+	return []SubtitleDeliveryProfile{
+		{
+			ID:              "netflix",
+			Name:            "Netflix-like",
+			Description:     "Adult reading speed with two-frame gaps at 24fps.",
+			MaxCPS:          20,
+			MaxCharsPerLine: 42,
+			MaxLines:        2,
+			MinGapMs:        83,
+			MinDurationMs:   833,
+			MaxDurationMs:   7000,
+		},
+		{
+			ID:              "youtube",
+			Name:            "YouTube",
+			Description:     "Relaxed limits for online video captions.",
+			MaxCPS:          25,
+			MaxCharsPerLine: 42,
+			MaxLines:        2,
+			MinGapMs:        0,
+			MinDurationMs:   700,
+			MaxDurationMs:   10000,
+		},
+	}
+}
+
+// ResolveSubtitleDeliveryProfile finds a profile by id among custom and builtin
+// profiles. An empty id resolves the configured default profile.
+func (config SubtitleDeliveryConfig) ResolveSubtitleDeliveryProfile(id string) (SubtitleDeliveryProfile, bool) {
+	target := strings.TrimSpace(id)
+	if target == "" {
+		target = config.DefaultProfileID
+	}
+	for _, profile := range config.Custom {
+		if profile.ID == target {
+			return profile, true
+		}
+	}
+	for _, profile := range config.Builtin {
+		if profile.ID == target {
+			return profile, true
+		}
+	}
+	return SubtitleDeliveryProfile{}, false
+}
+
+func normalizeSubtitleDeliveryConfig(config SubtitleDeliveryConfig) SubtitleDeliveryConfig {
+	result := SubtitleDeliveryConfig{
+		Builtin: defaultBuiltinSubtitleDeliveryProfiles(),
+	}
+	seen := map[string]struct{}{}
+	for _, profile := range result.Builtin {
+		seen[profile.ID] = struct{}{}
+	}
+	result.Custom = make([]SubtitleDeliveryProfile, 0, len(config.Custom))
+	for index, value := range config.Custom {
+		id := normalizeAssetID(value.ID, value.Name, fmt.Sprintf("delivery-%d", index+1))
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		result.Custom = append(result.Custom, normalizeSubtitleDeliveryProfile(value, id, index))
+	}
+	result.DefaultProfileID = strings.TrimSpace(config.DefaultProfileID)
+	if _, exists := seen[result.DefaultProfileID]; !exists {
+		result.DefaultProfileID = defaultSubtitleDeliveryConfig().DefaultProfileID
+	}
+	return result
+}
+
+func normalizeSubtitleDeliveryProfile(value SubtitleDeliveryProfile, id string, index int) SubtitleDeliveryProfile {
+	name := strings.TrimSpace(value.Name)
+	if name == "" {
+		name = fmt.Sprintf("Delivery profile %d", index+1)
+	}
+	result := SubtitleDeliveryProfile{
+		ID:              id,
+		Name:            name,
+		Description:     strings.TrimSpace(value.Description),
+		MaxCPS:          value.MaxCPS,
+		MaxCharsPerLine: value.MaxCharsPerLine,
+		MaxLines:        value.MaxLines,
+		MinGapMs:        value.MinGapMs,
+		MinDurationMs:   value.MinDurationMs,
+		MaxDurationMs:   value.MaxDurationMs,
+	}
+	if result.MaxCPS < 0 {
+		result.MaxCPS = 0
+	}
+	if result.MaxCharsPerLine < 0 {
+		result.MaxCharsPerLine = 0
+	}
+	if result.MaxLines < 0 {
+		result.MaxLines = 0
+	}
+	if result.MinGapMs < 0 {
+		result.MinGapMs = 0
+	}
+	if result.MinDurationMs < 0 {
+		result.MinDurationMs = 0
+	}
+	if result.MaxDurationMs < 0 || (result.MaxDurationMs > 0 && result.MaxDurationMs < result.MinDurationMs) {
+		result.MaxDurationMs = 0
+	}
+	return result
+}
@@ -39,7 +39,7 @@ func NewSubtitleRevision(params SubtitleRevisionParams) (SubtitleRevision, error
 		return SubtitleRevision{}, ErrInvalidSubtitleRevision
 	}
 	switch sourceKind {
-	case "snapshot", "proofread_candidate", "qa_candidate", "review_apply":
+	case "snapshot", "proofread_candidate", "qa_candidate", "review_apply", "retime", "align_candidate", "manual_edit", "agent_edit", "restore":
 	default:
 		return SubtitleRevision{}, ErrInvalidSubtitleRevision
 	}
@@ -60,14 +60,28 @@ func NewSubtitleRevision(params SubtitleRevisionParams) (SubtitleRevision, error
 	}, nil
 }
 
+// SubtitleReviewSuggestedCue is one cue produced when a suggestion splits the
+// reviewed cue into several cues.
+type SubtitleReviewSuggestedCue struct {
+	Start string
+	End   string
+	Text  string
+}
+
 type SubtitleReviewSuggestion struct {
-	CueIndex      int
-	OriginalText  string
-	SuggestedText string
-	Categories    []string
-	Reason        string
-	SourceCode    string
-	Severity      string
+	CueIndex         int
+	OriginalText     string
+	SuggestedText    string
+	OriginalStart    string
+	OriginalEnd      string
+	SuggestedStart   string
+	SuggestedEnd     string
+	SuggestedCues    []SubtitleReviewSuggestedCue
+	MergedCueIndexes []int
+	Categories       []string
+	Reason           string
+	SourceCode       string
+	Severity         string
 }
 
 type SubtitleReviewSession struct {
@@ -114,7 +128,7 @@ func NewSubtitleReviewSession(params SubtitleReviewSessionParams) (SubtitleRevie
 		return SubtitleReviewSession{}, ErrInvalidSubtitleReviewSession
 	}
 	switch kind {
-	case "proofread", "qa":
+	case "proofread", "qa", "align":
 	default:
 		return SubtitleReviewSession{}, ErrInvalidSubtitleReviewSession
 	}
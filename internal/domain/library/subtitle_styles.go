@@ -112,6 +112,9 @@ type SubtitleExportConfig struct {
 	ASS    *SubtitleASSExportConfig
 	ITT    *SubtitleITTExportConfig
 	FCPXML *SubtitleFCPXMLExportConfig
+	TTML   *SubtitleTTMLExportConfig
+	SUB    *SubtitleSUBExportConfig
+	STL    *SubtitleSTLExportConfig
 }
 
 type SubtitleSRTExportConfig struct {
@@ -148,6 +151,20 @@ type SubtitleFCPXMLExportConfig struct {
 	StartTimecodeSeconds int64
 }
 
+type SubtitleTTMLExportConfig struct {
+	Language string
+}
+
+type SubtitleSUBExportConfig struct {
+	FrameRate float64
+}
+
+type SubtitleSTLExportConfig struct {
+	FrameRate int
+	Language  string
+	Title     string
+}
+
 const (
 	defaultSubtitleExportPresetID = "builtin-subtitle-export-preset-srt-auto"
 
@@ -613,10 +630,18 @@ func normalizeSubtitleExportTargetFormat(value string) string {
 		return "vtt"
 	case "ass", "ssa":
 		return "ass"
-	case "itt", "ttml", "xml":
+	case "itt", "xml":
 		return "itt"
+	case "ttml", "dfxp", "imsc", "imsc1":
+		return "ttml"
 	case "fcpxml":
 		return "fcpxml"
+	case "sbv", "lrc":
+		return strings.ToLower(strings.TrimSpace(value))
+	case "sub", "microdvd":
+		return "sub"
+	case "stl", "ebu-stl":
+		return "stl"
 	default:
 		return ""
 	}
@@ -726,6 +751,32 @@ func normalizeSubtitleExportConfig(value SubtitleExportConfig) SubtitleExportCon
 		}
 	}
 
+	if value.TTML != nil {
+		if language := strings.TrimSpace(value.TTML.Language); language != "" {
+			result.TTML = &SubtitleTTMLExportConfig{Language: language}
+		}
+	}
+
+	if value.SUB != nil && value.SUB.FrameRate > 0 {
+		result.SUB = &SubtitleSUBExportConfig{FrameRate: value.SUB.FrameRate}
+	}
+
+	if value.STL != nil {
+		frameRate := value.STL.FrameRate
+		if frameRate != 25 && frameRate != 30 {
+			frameRate = 0
+		}
+		language := strings.TrimSpace(value.STL.Language)
+		title := strings.TrimSpace(value.STL.Title)
+		if frameRate > 0 || language != "" || title != "" {
+			result.STL = &SubtitleSTLExportConfig{
+				FrameRate: frameRate,
+				Language:  language,
+				Title:     title,
+			}
+		}
+	}
+
 	return result
 }
 
@@ -0,0 +1,59 @@
+package library
+
+const (
+	DefaultLoudnessTargetLUFS   = -16.0
+	DefaultLoudnessTruePeakDBTP = -1.5
+	DefaultLoudnessRangeLU      = 11.0
+)
+
+// TranscodeAudioProcessing holds the audio filters a transcode preset applies before
+// encoding. Loudness normalization follows EBU R128 and runs as a two-pass loudnorm:
+// the first pass measures the input and the second applies the measured values.
+type TranscodeAudioProcessing struct {
+	LoudnessNormalize bool
+	LoudnessTargetI   float64
+	LoudnessTruePeak  float64
+	LoudnessRange     float64
+	NoiseReduction    bool
+	TrimSilence       bool
+}
+
+// Enabled reports whether any audio filter has to run.
+func (processing TranscodeAudioProcessing) Enabled() bool {
+	return processing.LoudnessNormalize || processing.NoiseReduction || processing.TrimSilence
+}
+
+func normalizeTranscodeAudioProcessing(value TranscodeAudioProcessing, outputType TranscodeOutputType) (TranscodeAudioProcessing, error) {
+	result := value
+	if !result.LoudnessNormalize {
+		result.LoudnessTargetI = 0
+		result.LoudnessTruePeak = 0
+		result.LoudnessRange = 0
+	} else {
+		if result.LoudnessTargetI == 0 {
+			result.LoudnessTargetI = DefaultLoudnessTargetLUFS
+		}
+		if result.LoudnessTruePeak == 0 {
+			result.LoudnessTruePeak = DefaultLoudnessTruePeakDBTP
+		}
+		if result.LoudnessRange == 0 {
+			result.LoudnessRange = DefaultLoudnessRangeLU
+		}
+		// Ranges accepted by the ffmpeg loudnorm filter.
+		if result.LoudnessTargetI < -70 || result.LoudnessTargetI > -5 {
+			return TranscodeAudioProcessing{}, ErrInvalidPreset
+		}
+		if result.LoudnessTruePeak < -9 || result.LoudnessTruePeak > 0 {
+			return TranscodeAudioProcessing{}, ErrInvalidPreset
+		}
+		if result.LoudnessRange < 1 || result.LoudnessRange > 50 {
+			return TranscodeAudioProcessing{}, ErrInvalidPreset
+		}
+	}
+	// Removing silence from the audio track alone would drift it out of sync with the
+	// picture, so trimming is limited to audio outputs.
+	if result.TrimSilence && outputType != TranscodeOutputAudio {
+		return TranscodeAudioProcessing{}, ErrInvalidPreset
+	}
+	return result, nil
+}
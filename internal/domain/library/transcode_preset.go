@@ -32,6 +32,11 @@ type TranscodePreset struct {
 	RequiresAudio    bool
 	IsBuiltin        bool
 	Description      string
+	AspectRatio      string
+	ReframeMode      string
+	MaxBitrateKbps   int
+	MaxFileSizeMB    int
+	Audio            TranscodeAudioProcessing
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
@@ -56,6 +61,11 @@ type TranscodePresetParams struct {
 	RequiresAudio    bool
 	IsBuiltin        bool
 	Description      string
+	AspectRatio      string
+	ReframeMode      string
+	MaxBitrateKbps   int
+	MaxFileSizeMB    int
+	Audio            TranscodeAudioProcessing
 	CreatedAt        *time.Time
 	UpdatedAt        *time.Time
 }
@@ -79,6 +89,17 @@ func NewTranscodePreset(params TranscodePresetParams) (TranscodePreset, error) {
 	if container == "" {
 		return TranscodePreset{}, ErrInvalidPreset
 	}
+	aspectRatio, reframeMode, err := normalizeTranscodeReframe(params.AspectRatio, params.ReframeMode, outputType)
+	if err != nil {
+		return TranscodePreset{}, err
+	}
+	if params.MaxBitrateKbps < 0 || params.MaxFileSizeMB < 0 {
+		return TranscodePreset{}, ErrInvalidPreset
+	}
+	audio, err := normalizeTranscodeAudioProcessing(params.Audio, outputType)
+	if err != nil {
+		return TranscodePreset{}, err
+	}
 	createdAt := time.Now()
 	if params.CreatedAt != nil {
 		createdAt = *params.CreatedAt
@@ -110,6 +131,11 @@ func NewTranscodePreset(params TranscodePresetParams) (TranscodePreset, error) {
 		RequiresAudio:    params.RequiresAudio,
 		IsBuiltin:        params.IsBuiltin,
 		Description:      strings.TrimSpace(params.Description),
+		AspectRatio:      aspectRatio,
+		ReframeMode:      reframeMode,
+		MaxBitrateKbps:   params.MaxBitrateKbps,
+		MaxFileSizeMB:    params.MaxFileSizeMB,
+		Audio:            audio,
 		CreatedAt:        createdAt,
 		UpdatedAt:        updatedAt,
 	}, nil
@@ -0,0 +1,48 @@
+package library
+
+import "strings"
+
+const (
+	ReframeModeCrop = "crop"
+	ReframeModePad  = "pad"
+	ReframeModeBlur = "blur"
+)
+
+// transcodeAspectRatios lists the output frames a preset can reframe to, as
+// width and height units.
+var transcodeAspectRatios = map[string][2]int{
+	"16:9": {16, 9},
+	"9:16": {9, 16},
+	"1:1":  {1, 1},
+	"4:5":  {4, 5},
+	"3:4":  {3, 4},
+}
+
+// TranscodeAspectRatio returns the width and height units of a supported aspect
+// ratio such as "9:16".
+func TranscodeAspectRatio(value string) (int, int, bool) {
+	units, ok := transcodeAspectRatios[strings.TrimSpace(value)]
+	if !ok {
+		return 0, 0, false
+	}
+	return units[0], units[1], true
+}
+
+func normalizeTranscodeReframe(aspectRatio string, reframeMode string, outputType TranscodeOutputType) (string, string, error) {
+	aspectRatio = strings.TrimSpace(aspectRatio)
+	reframeMode = strings.ToLower(strings.TrimSpace(reframeMode))
+	if aspectRatio == "" || aspectRatio == "original" || outputType != TranscodeOutputVideo {
+		return "", "", nil
+	}
+	if _, _, ok := TranscodeAspectRatio(aspectRatio); !ok {
+		return "", "", ErrInvalidPreset
+	}
+	switch reframeMode {
+	case "":
+		reframeMode = ReframeModePad
+	case ReframeModeCrop, ReframeModePad, ReframeModeBlur:
+	default:
+		return "", "", ErrInvalidPreset
+	}
+	return aspectRatio, reframeMode, nil
+}
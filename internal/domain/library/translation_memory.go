@@ -0,0 +1,106 @@
+package library
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TranslationMemoryEntry is one accepted source/target cue pair. Entries are keyed by
+// target language and the normalized source text, so re-recording a pair updates it.
+type TranslationMemoryEntry struct {
+	ID             string
+	TargetLanguage string
+	SourceText     string
+	SourceKey      string
+	SourceLength   int
+	TargetText     string
+	OriginKind     string
+	LibraryID      string
+	FileID         string
+	OperationID    string
+	UseCount       int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type TranslationMemoryEntryParams struct {
+	ID             string
+	TargetLanguage string
+	SourceText     string
+	TargetText     string
+	OriginKind     string
+	LibraryID      string
+	FileID         string
+	OperationID    string
+	UseCount       int
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+}
+
+func NewTranslationMemoryEntry(params TranslationMemoryEntryParams) (TranslationMemoryEntry, error) {
+	id := strings.TrimSpace(params.ID)
+	targetLanguage := NormalizeTranslationMemoryLanguage(params.TargetLanguage)
+	sourceText := strings.TrimSpace(params.SourceText)
+	targetText := strings.TrimSpace(params.TargetText)
+	originKind := strings.TrimSpace(params.OriginKind)
+	sourceKey := NormalizeTranslationMemoryKey(sourceText)
+	if id == "" || targetLanguage == "" || sourceKey == "" || targetText == "" {
+		return TranslationMemoryEntry{}, ErrInvalidTranslationMemoryEntry
+	}
+	switch originKind {
+	case "translate", "review_apply":
+	default:
+		return TranslationMemoryEntry{}, ErrInvalidTranslationMemoryEntry
+	}
+	createdAt := time.Now().UTC()
+	if params.CreatedAt != nil && !params.CreatedAt.IsZero() {
+		createdAt = params.CreatedAt.UTC()
+	}
+	updatedAt := createdAt
+	if params.UpdatedAt != nil && !params.UpdatedAt.IsZero() {
+		updatedAt = params.UpdatedAt.UTC()
+	}
+	useCount := params.UseCount
+	if useCount < 0 {
+		useCount = 0
+	}
+	return TranslationMemoryEntry{
+		ID:             id,
+		TargetLanguage: targetLanguage,
+		SourceText:     sourceText,
+		SourceKey:      sourceKey,
+		SourceLength:   len([]rune(sourceKey)),
+		TargetText:     targetText,
+		OriginKind:     originKind,
+		LibraryID:      strings.TrimSpace(params.LibraryID),
+		FileID:         strings.TrimSpace(params.FileID),
+		OperationID:    strings.TrimSpace(params.OperationID),
+		UseCount:       useCount,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}, nil
+}
+
+func NormalizeTranslationMemoryLanguage(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// NormalizeTranslationMemoryKey folds case, repeated whitespace and line breaks so
+// the same line matches regardless of how a subtitle file wrapped it.
+func NormalizeTranslationMemoryKey(text string) string {
+	var builder strings.Builder
+	pendingSpace := false
+	for _, character := range strings.ToLower(text) {
+		if unicode.IsSpace(character) {
+			pendingSpace = builder.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			builder.WriteByte(' ')
+			pendingSpace = false
+		}
+		builder.WriteRune(character)
+	}
+	return builder.String()
+}
@@ -10,6 +10,7 @@ type GatewaySettings struct {
 	VoiceEnabled              bool                     `json:"voiceEnabled"`
 	VoiceWakeEnabled          bool                     `json:"voiceWakeEnabled"`
 	SandboxEnabled            bool                     `json:"sandboxEnabled"`
+	Sandbox                   GatewaySandboxSettings   `json:"sandbox"`
 	HTTP                      GatewayHTTPSettings      `json:"http"`
 	ChannelHealthCheckMinutes int                      `json:"channelHealthCheckMinutes"`
 	Runtime                   GatewayRuntimeSettings   `json:"runtime"`
@@ -202,6 +203,15 @@ type GatewaySubagentToolPolicy struct {
 	Deny      []string `json:"deny"`
 }
 
+// GatewaySandboxSettings configures the limits applied to sandboxed tool
+// commands. Zero limits disable the corresponding limit.
+type GatewaySandboxSettings struct {
+	AllowNetwork   bool `json:"allowNetwork"`
+	CPUSeconds     int  `json:"cpuSeconds"`
+	MemoryMB       int  `json:"memoryMb"`
+	TimeoutSeconds int  `json:"timeoutSeconds"`
+}
+
 type GatewayCronSettings struct {
 	Enabled           bool                     `json:"enabled"`
 	MaxConcurrentRuns int                      `json:"maxConcurrentRuns"`
@@ -266,6 +276,7 @@ type GatewaySettingsParams struct {
 	VoiceEnabled              *bool                           `json:"voiceEnabled,omitempty"`
 	VoiceWakeEnabled          *bool                           `json:"voiceWakeEnabled,omitempty"`
 	SandboxEnabled            *bool                           `json:"sandboxEnabled,omitempty"`
+	Sandbox                   *GatewaySandboxSettingsParams   `json:"sandbox,omitempty"`
 	HTTP                      *GatewayHTTPSettingsParams      `json:"http,omitempty"`
 	ChannelHealthCheckMinutes *int                            `json:"channelHealthCheckMinutes,omitempty"`
 	Runtime                   *GatewayRuntimeSettingsParams   `json:"runtime,omitempty"`
@@ -422,6 +433,13 @@ type GatewaySubagentToolPolicyParams struct {
 	Deny      []string `json:"deny,omitempty"`
 }
 
+type GatewaySandboxSettingsParams struct {
+	AllowNetwork   *bool `json:"allowNetwork,omitempty"`
+	CPUSeconds     *int  `json:"cpuSeconds,omitempty"`
+	MemoryMB       *int  `json:"memoryMb,omitempty"`
+	TimeoutSeconds *int  `json:"timeoutSeconds,omitempty"`
+}
+
 type GatewayCronSettingsParams struct {
 	Enabled           *bool                            `json:"enabled,omitempty"`
 	MaxConcurrentRuns *int                             `json:"maxConcurrentRuns,omitempty"`
@@ -562,6 +580,10 @@ const (
 	DefaultGatewayHTTPResponsesImagesMaxRedirects          = 3
 	DefaultGatewayHTTPResponsesImagesTimeoutMs             = 10000
 	DefaultGatewayChannelHealthCheckMinutes                = 5
+	DefaultGatewaySandboxAllowNetwork                      = true
+	DefaultGatewaySandboxCPUSeconds                        = 300
+	DefaultGatewaySandboxMemoryMB                          = 2048
+	DefaultGatewaySandboxTimeoutSeconds                    = 900
 	DefaultGatewayRuntimeDebugMode                         = GatewayDebugModeOff
 	DefaultGatewayCallRecordSaveStrategy                   = GatewayCallRecordSaveStrategyOff
 	DefaultGatewayCallRecordRetentionDays                  = 14
@@ -576,6 +598,12 @@ func DefaultGatewaySettings() GatewaySettings {
 		VoiceEnabled:        DefaultGatewayVoiceEnabled,
 		VoiceWakeEnabled:    DefaultGatewayVoiceWakeEnabled,
 		SandboxEnabled:      DefaultGatewaySandboxEnabled,
+		Sandbox: GatewaySandboxSettings{
+			AllowNetwork:   DefaultGatewaySandboxAllowNetwork,
+			CPUSeconds:     DefaultGatewaySandboxCPUSeconds,
+			MemoryMB:       DefaultGatewaySandboxMemoryMB,
+			TimeoutSeconds: DefaultGatewaySandboxTimeoutSeconds,
+		},
 		Runtime: GatewayRuntimeSettings{
 			MaxSteps:     DefaultGatewayRuntimeMaxSteps,
 			DebugMode:    DefaultGatewayRuntimeDebugMode,
@@ -751,6 +779,20 @@ func ResolveGatewaySettings(params GatewaySettingsParams) GatewaySettings {
 	if params.SandboxEnabled != nil {
 		settings.SandboxEnabled = *params.SandboxEnabled
 	}
+	if params.Sandbox != nil {
+		if params.Sandbox.AllowNetwork != nil {
+			settings.Sandbox.AllowNetwork = *params.Sandbox.AllowNetwork
+		}
+		if params.Sandbox.CPUSeconds != nil && *params.Sandbox.CPUSeconds >= 0 {
+			settings.Sandbox.CPUSeconds = *params.Sandbox.CPUSeconds
+		}
+		if params.Sandbox.MemoryMB != nil && *params.Sandbox.MemoryMB >= 0 {
+			settings.Sandbox.MemoryMB = *params.Sandbox.MemoryMB
+		}
+		if params.Sandbox.TimeoutSeconds != nil && *params.Sandbox.TimeoutSeconds >= 0 {
+			settings.Sandbox.TimeoutSeconds = *params.Sandbox.TimeoutSeconds
+		}
+	}
 	if params.HTTP != nil && params.HTTP.Endpoints != nil {
 		if params.HTTP.Endpoints.ChatCompletions != nil {
 			if params.HTTP.Endpoints.ChatCompletions.Enabled != nil {
@@ -65,6 +65,29 @@ func (repo *SQLiteSubtitleRevisionRepository) Get(ctx context.Context, id string
 	return toDomainSubtitleRevision(*row)
 }
 
+func (repo *SQLiteSubtitleRevisionRepository) ListByFileID(ctx context.Context, fileID string, limit int) ([]library.SubtitleRevision, error) {
+	rows := make([]subtitleRevisionRow, 0)
+	query := repo.db.NewSelect().
+		Model(&rows).
+		Where("file_id = ?", strings.TrimSpace(fileID)).
+		OrderExpr("created_at DESC, rowid DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, err
+	}
+	items := make([]library.SubtitleRevision, 0, len(rows))
+	for _, row := range rows {
+		item, err := toDomainSubtitleRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
 func (repo *SQLiteSubtitleRevisionRepository) Save(ctx context.Context, item library.SubtitleRevision) error {
 	row := subtitleRevisionRow{
 		ID:              item.ID,
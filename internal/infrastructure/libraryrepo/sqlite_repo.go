@@ -41,6 +41,7 @@ type fileRow struct {
 	OriginImportedAt     sql.NullTime   `bun:"origin_imported_at"`
 	OriginKeepSourceFile sql.NullBool   `bun:"origin_keep_source_file"`
 	LineageRootFileID    sql.NullString `bun:"lineage_root_file_id"`
+	LineageSourceIDsJSON sql.NullString `bun:"lineage_source_file_ids_json"`
 	LatestOperationID    sql.NullString `bun:"latest_operation_id"`
 	StateJSON            string         `bun:"state_json"`
 	MediaJSON            sql.NullString `bun:"media_json"`
@@ -159,22 +160,31 @@ func (repo *SQLiteFileRepository) Save(ctx context.Context, item library.Library
 		}
 		mediaJSON = nullString(string(payload))
 	}
+	lineageSourceIDsJSON := sql.NullString{}
+	if len(item.Lineage.SourceFileIDs) > 0 {
+		payload, err := json.Marshal(item.Lineage.SourceFileIDs)
+		if err != nil {
+			return err
+		}
+		lineageSourceIDsJSON = nullString(string(payload))
+	}
 	row := fileRow{
-		ID:                item.ID,
-		LibraryID:         item.LibraryID,
-		Kind:              string(item.Kind),
-		Name:              item.Name,
-		StorageMode:       item.Storage.Mode,
-		StorageLocalPath:  nullString(item.Storage.LocalPath),
-		StorageDocumentID: nullString(item.Storage.DocumentID),
-		OriginKind:        item.Origin.Kind,
-		OriginOperationID: nullString(item.Origin.OperationID),
-		LineageRootFileID: nullString(item.Lineage.RootFileID),
-		LatestOperationID: nullString(item.LatestOperationID),
-		StateJSON:         string(stateJSON),
-		MediaJSON:         mediaJSON,
-		CreatedAt:         item.CreatedAt,
-		UpdatedAt:         item.UpdatedAt,
+		ID:                   item.ID,
+		LibraryID:            item.LibraryID,
+		Kind:                 string(item.Kind),
+		Name:                 item.Name,
+		StorageMode:          item.Storage.Mode,
+		StorageLocalPath:     nullString(item.Storage.LocalPath),
+		StorageDocumentID:    nullString(item.Storage.DocumentID),
+		OriginKind:           item.Origin.Kind,
+		OriginOperationID:    nullString(item.Origin.OperationID),
+		LineageRootFileID:    nullString(item.Lineage.RootFileID),
+		LineageSourceIDsJSON: lineageSourceIDsJSON,
+		LatestOperationID:    nullString(item.LatestOperationID),
+		StateJSON:            string(stateJSON),
+		MediaJSON:            mediaJSON,
+		CreatedAt:            item.CreatedAt,
+		UpdatedAt:            item.UpdatedAt,
 	}
 	if item.Origin.Import != nil {
 		row.OriginImportBatchID = nullString(item.Origin.Import.BatchID)
@@ -197,6 +207,7 @@ func (repo *SQLiteFileRepository) Save(ctx context.Context, item library.Library
 		Set("origin_imported_at = EXCLUDED.origin_imported_at").
 		Set("origin_keep_source_file = EXCLUDED.origin_keep_source_file").
 		Set("lineage_root_file_id = EXCLUDED.lineage_root_file_id").
+		Set("lineage_source_file_ids_json = EXCLUDED.lineage_source_file_ids_json").
 		Set("latest_operation_id = EXCLUDED.latest_operation_id").
 		Set("state_json = EXCLUDED.state_json").
 		Set("media_json = EXCLUDED.media_json").
@@ -235,6 +246,12 @@ func toDomainFile(row fileRow) (library.LibraryFile, error) {
 		}
 		media = decoded
 	}
+	lineage := library.FileLineage{RootFileID: stringOrEmpty(row.LineageRootFileID)}
+	if row.LineageSourceIDsJSON.Valid && strings.TrimSpace(row.LineageSourceIDsJSON.String) != "" {
+		if err := json.Unmarshal([]byte(row.LineageSourceIDsJSON.String), &lineage.SourceFileIDs); err != nil {
+			return library.LibraryFile{}, err
+		}
+	}
 	origin := library.FileOrigin{Kind: row.OriginKind, OperationID: stringOrEmpty(row.OriginOperationID)}
 	if row.OriginKind == "import" {
 		origin.Import = &library.ImportOrigin{
@@ -251,7 +268,7 @@ func toDomainFile(row fileRow) (library.LibraryFile, error) {
 		Name:              row.Name,
 		Storage:           library.FileStorage{Mode: row.StorageMode, LocalPath: stringOrEmpty(row.StorageLocalPath), DocumentID: stringOrEmpty(row.StorageDocumentID)},
 		Origin:            origin,
-		Lineage:           library.FileLineage{RootFileID: stringOrEmpty(row.LineageRootFileID)},
+		Lineage:           lineage,
 		LatestOperationID: stringOrEmpty(row.LatestOperationID),
 		Media:             media,
 		State:             state,
@@ -0,0 +1,211 @@
+package libraryrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"dreamcreator/internal/domain/library"
+)
+
+type SQLiteSubscriptionRepository struct{ db *bun.DB }
+
+type subscriptionRow struct {
+	bun.BaseModel                  `bun:"table:library_subscriptions"`
+	ID                             string         `bun:"id,pk"`
+	Name                           string         `bun:"name"`
+	URL                            string         `bun:"url"`
+	LibraryID                      sql.NullString `bun:"library_id"`
+	ConnectorID                    sql.NullString `bun:"connector_id"`
+	UseConnector                   bool           `bun:"use_connector"`
+	Quality                        sql.NullString `bun:"quality"`
+	FormatID                       sql.NullString `bun:"format_id"`
+	SubtitleLangsJSON              sql.NullString `bun:"subtitle_langs_json"`
+	SubtitleAuto                   bool           `bun:"subtitle_auto"`
+	SubtitleFormat                 sql.NullString `bun:"subtitle_format"`
+	TranscodePresetID              sql.NullString `bun:"transcode_preset_id"`
+	DeleteSourceFileAfterTranscode bool           `bun:"delete_source_file_after_transcode"`
+	PollIntervalMinutes            int            `bun:"poll_interval_minutes"`
+	DownloadExisting               bool           `bun:"download_existing"`
+	Enabled                        bool           `bun:"enabled"`
+	LastCheckedAt                  sql.NullTime   `bun:"last_checked_at"`
+	LastError                      sql.NullString `bun:"last_error"`
+	CreatedAt                      time.Time      `bun:"created_at"`
+	UpdatedAt                      time.Time      `bun:"updated_at"`
+}
+
+type subscriptionArchiveRow struct {
+	bun.BaseModel  `bun:"table:library_subscription_archive"`
+	SubscriptionID string         `bun:"subscription_id,pk"`
+	EntryID        string         `bun:"entry_id,pk"`
+	OperationID    sql.NullString `bun:"operation_id"`
+	CreatedAt      time.Time      `bun:"created_at"`
+}
+
+func NewSQLiteSubscriptionRepository(db *bun.DB) *SQLiteSubscriptionRepository {
+	return &SQLiteSubscriptionRepository{db: db}
+}
+
+func (repo *SQLiteSubscriptionRepository) List(ctx context.Context) ([]library.Subscription, error) {
+	rows := make([]subscriptionRow, 0)
+	if err := repo.db.NewSelect().Model(&rows).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	items := make([]library.Subscription, 0, len(rows))
+	for _, row := range rows {
+		item, err := toDomainSubscription(row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (repo *SQLiteSubscriptionRepository) Get(ctx context.Context, id string) (library.Subscription, error) {
+	row := new(subscriptionRow)
+	if err := repo.db.NewSelect().Model(row).Where("id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return library.Subscription{}, library.ErrSubscriptionNotFound
+		}
+		return library.Subscription{}, err
+	}
+	return toDomainSubscription(*row)
+}
+
+func (repo *SQLiteSubscriptionRepository) Save(ctx context.Context, item library.Subscription) error {
+	langsJSON := sql.NullString{}
+	if len(item.SubtitleLangs) > 0 {
+		payload, err := json.Marshal(item.SubtitleLangs)
+		if err != nil {
+			return err
+		}
+		langsJSON = sql.NullString{String: string(payload), Valid: true}
+	}
+	row := subscriptionRow{
+		ID:                             item.ID,
+		Name:                           item.Name,
+		URL:                            item.URL,
+		LibraryID:                      nullString(item.LibraryID),
+		ConnectorID:                    nullString(item.ConnectorID),
+		UseConnector:                   item.UseConnector,
+		Quality:                        nullString(item.Quality),
+		FormatID:                       nullString(item.FormatID),
+		SubtitleLangsJSON:              langsJSON,
+		SubtitleAuto:                   item.SubtitleAuto,
+		SubtitleFormat:                 nullString(item.SubtitleFormat),
+		TranscodePresetID:              nullString(item.TranscodePresetID),
+		DeleteSourceFileAfterTranscode: item.DeleteSourceFileAfterTranscode,
+		PollIntervalMinutes:            item.PollIntervalMinutes,
+		DownloadExisting:               item.DownloadExisting,
+		Enabled:                        item.Enabled,
+		LastCheckedAt:                  nullTime(item.LastCheckedAt),
+		LastError:                      nullString(item.LastError),
+		CreatedAt:                      item.CreatedAt,
+		UpdatedAt:                      item.UpdatedAt,
+	}
+	_, err := repo.db.NewInsert().Model(&row).
+		On("CONFLICT(id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("url = EXCLUDED.url").
+		Set("library_id = EXCLUDED.library_id").
+		Set("connector_id = EXCLUDED.connector_id").
+		Set("use_connector = EXCLUDED.use_connector").
+		Set("quality = EXCLUDED.quality").
+		Set("format_id = EXCLUDED.format_id").
+		Set("subtitle_langs_json = EXCLUDED.subtitle_langs_json").
+		Set("subtitle_auto = EXCLUDED.subtitle_auto").
+		Set("subtitle_format = EXCLUDED.subtitle_format").
+		Set("transcode_preset_id = EXCLUDED.transcode_preset_id").
+		Set("delete_source_file_after_transcode = EXCLUDED.delete_source_file_after_transcode").
+		Set("poll_interval_minutes = EXCLUDED.poll_interval_minutes").
+		Set("download_existing = EXCLUDED.download_existing").
+		Set("enabled = EXCLUDED.enabled").
+		Set("last_checked_at = EXCLUDED.last_checked_at").
+		Set("last_error = EXCLUDED.last_error").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (repo *SQLiteSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	_, err := repo.db.NewDelete().Model((*subscriptionRow)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+func (repo *SQLiteSubscriptionRepository) ListArchiveEntryIDs(ctx context.Context, subscriptionID string) ([]string, error) {
+	ids := make([]string, 0)
+	if err := repo.db.NewSelect().
+		Model((*subscriptionArchiveRow)(nil)).
+		Column("entry_id").
+		Where("subscription_id = ?", subscriptionID).
+		Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *SQLiteSubscriptionRepository) SaveArchiveEntries(ctx context.Context, entries []library.SubscriptionArchiveEntry) error {
+	rows := make([]subscriptionArchiveRow, 0, len(entries))
+	for _, entry := range entries {
+		subscriptionID := strings.TrimSpace(entry.SubscriptionID)
+		entryID := strings.TrimSpace(entry.EntryID)
+		if subscriptionID == "" || entryID == "" {
+			return library.ErrInvalidSubscription
+		}
+		createdAt := entry.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now().UTC()
+		}
+		rows = append(rows, subscriptionArchiveRow{
+			SubscriptionID: subscriptionID,
+			EntryID:        entryID,
+			OperationID:    nullString(entry.OperationID),
+			CreatedAt:      createdAt,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := repo.db.NewInsert().Model(&rows).
+		On("CONFLICT(subscription_id, entry_id) DO UPDATE").
+		Set("operation_id = COALESCE(EXCLUDED.operation_id, operation_id)").
+		Exec(ctx)
+	return err
+}
+
+func toDomainSubscription(row subscriptionRow) (library.Subscription, error) {
+	var langs []string
+	if row.SubtitleLangsJSON.Valid && strings.TrimSpace(row.SubtitleLangsJSON.String) != "" {
+		if err := json.Unmarshal([]byte(row.SubtitleLangsJSON.String), &langs); err != nil {
+			return library.Subscription{}, err
+		}
+	}
+	return library.NewSubscription(library.SubscriptionParams{
+		ID:                             row.ID,
+		Name:                           row.Name,
+		URL:                            row.URL,
+		LibraryID:                      stringOrEmpty(row.LibraryID),
+		ConnectorID:                    stringOrEmpty(row.ConnectorID),
+		UseConnector:                   row.UseConnector,
+		Quality:                        stringOrEmpty(row.Quality),
+		FormatID:                       stringOrEmpty(row.FormatID),
+		SubtitleLangs:                  langs,
+		SubtitleAuto:                   row.SubtitleAuto,
+		SubtitleFormat:                 stringOrEmpty(row.SubtitleFormat),
+		TranscodePresetID:              stringOrEmpty(row.TranscodePresetID),
+		DeleteSourceFileAfterTranscode: row.DeleteSourceFileAfterTranscode,
+		PollIntervalMinutes:            row.PollIntervalMinutes,
+		DownloadExisting:               row.DownloadExisting,
+		Enabled:                        row.Enabled,
+		LastCheckedAt:                  timeOrNil(row.LastCheckedAt),
+		LastError:                      stringOrEmpty(row.LastError),
+		CreatedAt:                      &row.CreatedAt,
+		UpdatedAt:                      &row.UpdatedAt,
+	})
+}
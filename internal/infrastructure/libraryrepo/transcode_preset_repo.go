@@ -52,6 +52,11 @@ func (repo *SQLiteTranscodePresetRepository) List(ctx context.Context) ([]librar
 			RequiresAudio:    row.RequiresAudio,
 			IsBuiltin:        row.IsBuiltin,
 			Description:      stringOrEmpty(row.Description),
+			AspectRatio:      stringOrEmpty(row.AspectRatio),
+			ReframeMode:      stringOrEmpty(row.ReframeMode),
+			MaxBitrateKbps:   intOrZero(row.MaxBitrateKbps),
+			MaxFileSizeMB:    intOrZero(row.MaxFileSizeMB),
+			Audio:            transcodeAudioProcessingFromRow(row),
 			CreatedAt:        &row.CreatedAt,
 			UpdatedAt:        &row.UpdatedAt,
 		})
@@ -91,6 +96,11 @@ func (repo *SQLiteTranscodePresetRepository) Get(ctx context.Context, id string)
 		RequiresAudio:    row.RequiresAudio,
 		IsBuiltin:        row.IsBuiltin,
 		Description:      stringOrEmpty(row.Description),
+		AspectRatio:      stringOrEmpty(row.AspectRatio),
+		ReframeMode:      stringOrEmpty(row.ReframeMode),
+		MaxBitrateKbps:   intOrZero(row.MaxBitrateKbps),
+		MaxFileSizeMB:    intOrZero(row.MaxFileSizeMB),
+		Audio:            transcodeAudioProcessingFromRow(*row),
 		CreatedAt:        &row.CreatedAt,
 		UpdatedAt:        &row.UpdatedAt,
 	})
@@ -106,27 +116,37 @@ func (repo *SQLiteTranscodePresetRepository) Save(ctx context.Context, preset li
 		updatedAt = time.Now()
 	}
 	row := transcodePresetRow{
-		ID:               preset.ID,
-		Name:             preset.Name,
-		OutputType:       string(preset.OutputType),
-		Container:        preset.Container,
-		VideoCodec:       nullString(preset.VideoCodec),
-		AudioCodec:       nullString(preset.AudioCodec),
-		QualityMode:      nullString(preset.QualityMode),
-		CRF:              nullInt(preset.CRF),
-		BitrateKbps:      nullInt(preset.BitrateKbps),
-		AudioBitrateKbps: nullInt(preset.AudioBitrateKbps),
-		Scale:            nullString(preset.Scale),
-		Width:            nullInt(preset.Width),
-		Height:           nullInt(preset.Height),
-		FFmpegPreset:     nullString(preset.FFmpegPreset),
-		AllowUpscale:     preset.AllowUpscale,
-		RequiresVideo:    preset.RequiresVideo,
-		RequiresAudio:    preset.RequiresAudio,
-		IsBuiltin:        preset.IsBuiltin,
-		Description:      nullString(preset.Description),
-		CreatedAt:        createdAt,
-		UpdatedAt:        updatedAt,
+		ID:                preset.ID,
+		Name:              preset.Name,
+		OutputType:        string(preset.OutputType),
+		Container:         preset.Container,
+		VideoCodec:        nullString(preset.VideoCodec),
+		AudioCodec:        nullString(preset.AudioCodec),
+		QualityMode:       nullString(preset.QualityMode),
+		CRF:               nullInt(preset.CRF),
+		BitrateKbps:       nullInt(preset.BitrateKbps),
+		AudioBitrateKbps:  nullInt(preset.AudioBitrateKbps),
+		Scale:             nullString(preset.Scale),
+		Width:             nullInt(preset.Width),
+		Height:            nullInt(preset.Height),
+		FFmpegPreset:      nullString(preset.FFmpegPreset),
+		AllowUpscale:      preset.AllowUpscale,
+		RequiresVideo:     preset.RequiresVideo,
+		RequiresAudio:     preset.RequiresAudio,
+		IsBuiltin:         preset.IsBuiltin,
+		Description:       nullString(preset.Description),
+		AspectRatio:       nullString(preset.AspectRatio),
+		ReframeMode:       nullString(preset.ReframeMode),
+		MaxBitrateKbps:    nullInt(preset.MaxBitrateKbps),
+		MaxFileSizeMB:     nullInt(preset.MaxFileSizeMB),
+		LoudnessNormalize: preset.Audio.LoudnessNormalize,
+		LoudnessTargetI:   nullFloat(preset.Audio.LoudnessTargetI),
+		LoudnessTruePeak:  nullFloat(preset.Audio.LoudnessTruePeak),
+		LoudnessRange:     nullFloat(preset.Audio.LoudnessRange),
+		NoiseReduction:    preset.Audio.NoiseReduction,
+		TrimSilence:       preset.Audio.TrimSilence,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
 	}
 	_, err := repo.db.NewInsert().Model(&row).
 		On("CONFLICT(id) DO UPDATE").
@@ -148,6 +168,16 @@ func (repo *SQLiteTranscodePresetRepository) Save(ctx context.Context, preset li
 		Set("requires_audio = EXCLUDED.requires_audio").
 		Set("is_builtin = EXCLUDED.is_builtin").
 		Set("description = EXCLUDED.description").
+		Set("aspect_ratio = EXCLUDED.aspect_ratio").
+		Set("reframe_mode = EXCLUDED.reframe_mode").
+		Set("max_bitrate_kbps = EXCLUDED.max_bitrate_kbps").
+		Set("max_file_size_mb = EXCLUDED.max_file_size_mb").
+		Set("loudness_normalize = EXCLUDED.loudness_normalize").
+		Set("loudness_target_i = EXCLUDED.loudness_target_i").
+		Set("loudness_true_peak = EXCLUDED.loudness_true_peak").
+		Set("loudness_range = EXCLUDED.loudness_range").
+		Set("noise_reduction = EXCLUDED.noise_reduction").
+		Set("trim_silence = EXCLUDED.trim_silence").
 		Set("updated_at = EXCLUDED.updated_at").
 		Exec(ctx)
 	return err
@@ -171,3 +201,28 @@ func intOrZero(value sql.NullInt64) int {
 	}
 	return 0
 }
+
+func transcodeAudioProcessingFromRow(row transcodePresetRow) library.TranscodeAudioProcessing {
+	return library.TranscodeAudioProcessing{
+		LoudnessNormalize: row.LoudnessNormalize,
+		LoudnessTargetI:   floatOrZero(row.LoudnessTargetI),
+		LoudnessTruePeak:  floatOrZero(row.LoudnessTruePeak),
+		LoudnessRange:     floatOrZero(row.LoudnessRange),
+		NoiseReduction:    row.NoiseReduction,
+		TrimSilence:       row.TrimSilence,
+	}
+}
+
+func nullFloat(value float64) sql.NullFloat64 {
+	if value == 0 {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: value, Valid: true}
+}
+
+func floatOrZero(value sql.NullFloat64) float64 {
+	if value.Valid {
+		return value.Float64
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+package libraryrepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"dreamcreator/internal/domain/library"
+)
+
+type SQLiteTranslationMemoryRepository struct{ db *bun.DB }
+
+type translationMemoryRow struct {
+	bun.BaseModel  `bun:"table:library_translation_memory"`
+	ID             string         `bun:"id,pk"`
+	TargetLanguage string         `bun:"target_language"`
+	SourceText     string         `bun:"source_text"`
+	SourceKey      string         `bun:"source_key"`
+	SourceLength   int            `bun:"source_length"`
+	TargetText     string         `bun:"target_text"`
+	OriginKind     string         `bun:"origin_kind"`
+	LibraryID      sql.NullString `bun:"library_id"`
+	FileID         sql.NullString `bun:"file_id"`
+	OperationID    sql.NullString `bun:"operation_id"`
+	UseCount       int            `bun:"use_count"`
+	CreatedAt      time.Time      `bun:"created_at"`
+	UpdatedAt      time.Time      `bun:"updated_at"`
+}
+
+func NewSQLiteTranslationMemoryRepository(db *bun.DB) *SQLiteTranslationMemoryRepository {
+	return &SQLiteTranslationMemoryRepository{db: db}
+}
+
+func (repo *SQLiteTranslationMemoryRepository) FindBySourceKeys(ctx context.Context, targetLanguage string, sourceKeys []string) ([]library.TranslationMemoryEntry, error) {
+	keys := make([]string, 0, len(sourceKeys))
+	for _, key := range sourceKeys {
+		if trimmed := strings.TrimSpace(key); trimmed != "" {
+			keys = append(keys, trimmed)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	rows := make([]translationMemoryRow, 0, len(keys))
+	if err := repo.db.NewSelect().
+		Model(&rows).
+		Where("target_language = ?", library.NormalizeTranslationMemoryLanguage(targetLanguage)).
+		Where("source_key IN (?)", bun.In(keys)).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+	return mapTranslationMemoryRows(rows)
+}
+
+func (repo *SQLiteTranslationMemoryRepository) ListCandidates(ctx context.Context, targetLanguage string, minSourceLength int, maxSourceLength int, limit int) ([]library.TranslationMemoryEntry, error) {
+	rows := make([]translationMemoryRow, 0)
+	query := repo.db.NewSelect().
+		Model(&rows).
+		Where("target_language = ?", library.NormalizeTranslationMemoryLanguage(targetLanguage)).
+		Where("source_length BETWEEN ? AND ?", minSourceLength, maxSourceLength).
+		Order("updated_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return mapTranslationMemoryRows(rows)
+}
+
+func (repo *SQLiteTranslationMemoryRepository) Save(ctx context.Context, item library.TranslationMemoryEntry) error {
+	row := translationMemoryRow{
+		ID:             item.ID,
+		TargetLanguage: item.TargetLanguage,
+		SourceText:     item.SourceText,
+		SourceKey:      item.SourceKey,
+		SourceLength:   item.SourceLength,
+		TargetText:     item.TargetText,
+		OriginKind:     item.OriginKind,
+		LibraryID:      nullString(item.LibraryID),
+		FileID:         nullString(item.FileID),
+		OperationID:    nullString(item.OperationID),
+		UseCount:       item.UseCount,
+		CreatedAt:      item.CreatedAt,
+		UpdatedAt:      item.UpdatedAt,
+	}
+	_, err := repo.db.NewInsert().Model(&row).
+		On("CONFLICT(target_language, source_key) DO UPDATE").
+		Set("source_text = EXCLUDED.source_text").
+		Set("target_text = EXCLUDED.target_text").
+		Set("origin_kind = EXCLUDED.origin_kind").
+		Set("library_id = EXCLUDED.library_id").
+		Set("file_id = EXCLUDED.file_id").
+		Set("operation_id = EXCLUDED.operation_id").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
+
+func (repo *SQLiteTranslationMemoryRepository) MarkUsed(ctx context.Context, ids []string, usedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := repo.db.NewUpdate().
+		Model((*translationMemoryRow)(nil)).
+		Set("use_count = use_count + 1").
+		Set("updated_at = ?", usedAt).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	return err
+}
+
+func mapTranslationMemoryRows(rows []translationMemoryRow) ([]library.TranslationMemoryEntry, error) {
+	items := make([]library.TranslationMemoryEntry, 0, len(rows))
+	for _, row := range rows {
+		item, err := library.NewTranslationMemoryEntry(library.TranslationMemoryEntryParams{
+			ID:             row.ID,
+			TargetLanguage: row.TargetLanguage,
+			SourceText:     row.SourceText,
+			TargetText:     row.TargetText,
+			OriginKind:     row.OriginKind,
+			LibraryID:      stringOrEmpty(row.LibraryID),
+			FileID:         stringOrEmpty(row.FileID),
+			OperationID:    stringOrEmpty(row.OperationID),
+			UseCount:       row.UseCount,
+			CreatedAt:      &row.CreatedAt,
+			UpdatedAt:      &row.UpdatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
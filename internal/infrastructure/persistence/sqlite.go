@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strings"
 
 	_ "github.com/asg017/sqlite-vec-go-bindings/ncruces"
 	_ "github.com/ncruces/go-sqlite3/driver"
@@ -542,6 +543,22 @@ CREATE TABLE IF NOT EXISTS tool_policy_audit (
 	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS tool_policy_rules (
+	id TEXT PRIMARY KEY,
+	name TEXT,
+	tool TEXT,
+	action TEXT,
+	decision TEXT NOT NULL,
+	reason TEXT,
+	command_prefixes_json TEXT,
+	command_patterns_json TEXT,
+	path_globs_json TEXT,
+	domains_json TEXT,
+	enabled INTEGER NOT NULL DEFAULT 1,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS exec_approvals (
 	id TEXT PRIMARY KEY,
 	request_json TEXT NOT NULL,
@@ -693,6 +710,16 @@ CREATE TABLE IF NOT EXISTS transcode_presets (
 	requires_audio BOOLEAN NOT NULL DEFAULT 0,
 	is_builtin BOOLEAN NOT NULL DEFAULT 0,
 	description TEXT,
+	aspect_ratio TEXT,
+	reframe_mode TEXT,
+	max_bitrate_kbps INTEGER,
+	max_file_size_mb INTEGER,
+	loudness_normalize BOOLEAN NOT NULL DEFAULT 0,
+	loudness_target_i REAL,
+	loudness_true_peak REAL,
+	loudness_range REAL,
+	noise_reduction BOOLEAN NOT NULL DEFAULT 0,
+	trim_silence BOOLEAN NOT NULL DEFAULT 0,
 	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -934,6 +961,9 @@ CREATE TABLE IF NOT EXISTS subagent_runs (
 	if _, err := db.ExecContext(ctx, librarySchemaSQL); err != nil {
 		return err
 	}
+	if err := ensureLibraryTableConstraints(ctx, db); err != nil {
+		return err
+	}
 	if err := createMemoryChunksFTSTable(ctx, db); err != nil {
 		return err
 	}
@@ -991,6 +1021,56 @@ func ensureSQLiteColumns(ctx context.Context, db *sql.DB) error {
 			column:    "compatibility",
 			statement: "ALTER TABLE providers ADD COLUMN compatibility TEXT NOT NULL DEFAULT ''",
 		},
+		{
+			table:     "transcode_presets",
+			column:    "aspect_ratio",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN aspect_ratio TEXT",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "reframe_mode",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN reframe_mode TEXT",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "max_bitrate_kbps",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN max_bitrate_kbps INTEGER",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "max_file_size_mb",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN max_file_size_mb INTEGER",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "loudness_normalize",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN loudness_normalize BOOLEAN NOT NULL DEFAULT 0",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "loudness_target_i",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN loudness_target_i REAL",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "loudness_true_peak",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN loudness_true_peak REAL",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "loudness_range",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN loudness_range REAL",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "noise_reduction",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN noise_reduction BOOLEAN NOT NULL DEFAULT 0",
+		},
+		{
+			table:     "transcode_presets",
+			column:    "trim_silence",
+			statement: "ALTER TABLE transcode_presets ADD COLUMN trim_silence BOOLEAN NOT NULL DEFAULT 0",
+		},
 	}
 	for _, item := range updates {
 		hasTable, err := sqliteTableExists(ctx, db, item.table)
@@ -1072,6 +1152,158 @@ func sqliteTableHasColumn(ctx context.Context, db *sql.DB, table string, column
 	return false, rows.Err()
 }
 
+// libraryConstrainedTables lists library tables whose CHECK constraints enumerate
+// kinds. SQLite cannot alter constraints in place, so these tables are rebuilt
+// whenever the stored definition drifts from librarySchemaSQL.
+var libraryConstrainedTables = []string{
+	"library_files",
+	"library_subtitle_revisions",
+	"library_subtitle_review_sessions",
+	"library_operations",
+	"library_translation_memory",
+}
+
+func ensureLibraryTableConstraints(ctx context.Context, db *sql.DB) error {
+	rebuilt := false
+	for _, table := range libraryConstrainedTables {
+		desired := libraryCreateTableStatement(table)
+		if desired == "" {
+			return fmt.Errorf("library schema for %s not found", table)
+		}
+		var current string
+		row := db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table)
+		if err := row.Scan(&current); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return err
+		}
+		if normalizeSQLiteTableDefinition(current) == normalizeSQLiteTableDefinition(desired) {
+			continue
+		}
+		if err := rebuildSQLiteTable(ctx, db, table, desired); err != nil {
+			return fmt.Errorf("rebuild %s: %w", table, err)
+		}
+		rebuilt = true
+	}
+	if rebuilt {
+		// Dropping the legacy tables also dropped their indexes.
+		if _, err := db.ExecContext(ctx, librarySchemaSQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func libraryCreateTableStatement(table string) string {
+	prefix := "CREATE TABLE IF NOT EXISTS " + table + " ("
+	start := strings.Index(librarySchemaSQL, prefix)
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(librarySchemaSQL[start:], "\n);")
+	if end < 0 {
+		return ""
+	}
+	return librarySchemaSQL[start : start+end+2]
+}
+
+func normalizeSQLiteTableDefinition(statement string) string {
+	statement = strings.Replace(statement, "IF NOT EXISTS", "", 1)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '\r', '"', ';':
+			return -1
+		default:
+			return r
+		}
+	}, statement)
+}
+
+func rebuildSQLiteTable(ctx context.Context, db *sql.DB, table string, createStatement string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+	if _, err := conn.ExecContext(ctx, "PRAGMA legacy_alter_table = ON"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "PRAGMA legacy_alter_table = OFF")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	staging := table + "_rebuild"
+	if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+staging); err != nil {
+		return err
+	}
+	statement := strings.Replace(createStatement, "IF NOT EXISTS "+table+" (", staging+" (", 1)
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return err
+	}
+	oldColumns, err := sqliteTxTableColumns(ctx, tx, table)
+	if err != nil {
+		return err
+	}
+	newColumns, err := sqliteTxTableColumns(ctx, tx, staging)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]struct{}, len(oldColumns))
+	for _, column := range oldColumns {
+		existing[column] = struct{}{}
+	}
+	shared := make([]string, 0, len(newColumns))
+	for _, column := range newColumns {
+		if _, ok := existing[column]; ok {
+			shared = append(shared, column)
+		}
+	}
+	columnList := strings.Join(shared, ", ")
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+staging+" ("+columnList+") SELECT "+columnList+" FROM "+table); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DROP TABLE "+table); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE "+staging+" RENAME TO "+table); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func sqliteTxTableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "PRAGMA table_info("+table+")")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := make([]string, 0)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &pk); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
 func sqliteTableExists(ctx context.Context, db *sql.DB, table string) (bool, error) {
 	row := db.QueryRowContext(ctx, "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?", table)
 	var exists int
@@ -1126,7 +1358,7 @@ CREATE TABLE IF NOT EXISTS library_files (
   storage_local_path TEXT,
   storage_document_id TEXT,
 
-  origin_kind TEXT NOT NULL CHECK (origin_kind IN ('import','download','transcode','subtitle_translate','subtitle_proofread')),
+  origin_kind TEXT NOT NULL CHECK (origin_kind IN ('import','download','transcode','subtitle_translate','subtitle_proofread','subtitle_transcribe','subtitle_bilingual_merge','subtitle_bilingual_split','clip','chapter_split','subtitle_ocr','subtitle_extract','thumbnail_generate')),
   origin_operation_id TEXT,
   origin_import_batch_id TEXT,
   origin_import_path TEXT,
@@ -1134,6 +1366,7 @@ CREATE TABLE IF NOT EXISTS library_files (
   origin_keep_source_file BOOLEAN,
 
   lineage_root_file_id TEXT,
+  lineage_source_file_ids_json TEXT,
   latest_operation_id TEXT,
 
   state_json TEXT NOT NULL,
@@ -1150,7 +1383,7 @@ CREATE TABLE IF NOT EXISTS library_files (
   ),
   CHECK (
     (origin_kind = 'import' AND COALESCE(origin_import_path,'') <> '' AND origin_operation_id IS NULL) OR
-    (origin_kind IN ('download','transcode','subtitle_translate','subtitle_proofread') AND COALESCE(origin_operation_id,'') <> '' AND origin_import_path IS NULL)
+    (origin_kind IN ('download','transcode','subtitle_translate','subtitle_proofread','subtitle_transcribe','subtitle_bilingual_merge','subtitle_bilingual_split','clip','chapter_split','subtitle_ocr','subtitle_extract','thumbnail_generate') AND COALESCE(origin_operation_id,'') <> '' AND origin_import_path IS NULL)
   )
 );
 
@@ -1173,7 +1406,7 @@ CREATE TABLE IF NOT EXISTS library_subtitle_revisions (
   file_id TEXT NOT NULL,
   format TEXT NOT NULL,
   content TEXT NOT NULL,
-  source_kind TEXT NOT NULL CHECK (source_kind IN ('snapshot','proofread_candidate','qa_candidate','review_apply')),
+  source_kind TEXT NOT NULL CHECK (source_kind IN ('snapshot','proofread_candidate','qa_candidate','review_apply','retime','align_candidate','manual_edit','agent_edit','restore')),
   source_operation_id TEXT,
   review_session_id TEXT,
   created_at TIMESTAMP NOT NULL,
@@ -1186,7 +1419,7 @@ CREATE TABLE IF NOT EXISTS library_subtitle_review_sessions (
   id TEXT PRIMARY KEY,
   library_id TEXT NOT NULL,
   file_id TEXT NOT NULL,
-  kind TEXT NOT NULL CHECK (kind IN ('proofread','qa')),
+  kind TEXT NOT NULL CHECK (kind IN ('proofread','qa','align')),
   status TEXT NOT NULL CHECK (status IN ('pending','applied','discarded')),
   operation_id TEXT,
   source_revision_id TEXT NOT NULL,
@@ -1207,7 +1440,7 @@ CREATE TABLE IF NOT EXISTS library_subtitle_review_sessions (
 CREATE TABLE IF NOT EXISTS library_operations (
   id TEXT PRIMARY KEY,
   library_id TEXT NOT NULL,
-  kind TEXT NOT NULL CHECK (kind IN ('download','transcode','subtitle_translate','subtitle_proofread','subtitle_qa_review')),
+  kind TEXT NOT NULL CHECK (kind IN ('download','transcode','subtitle_translate','subtitle_proofread','subtitle_qa_review','subtitle_transcribe','subtitle_align','subtitle_bilingual_merge','subtitle_bilingual_split','subtitle_glossary_extract','subtitle_pipeline','download_playlist','clip','chapter_split','subtitle_ocr','subtitle_extract','thumbnail_generate')),
   status TEXT NOT NULL CHECK (status IN ('queued','running','succeeded','failed','canceled')),
   display_name TEXT NOT NULL,
 
@@ -1347,6 +1580,55 @@ CREATE TABLE IF NOT EXISTS library_file_events (
   FOREIGN KEY (operation_id) REFERENCES library_operations(id) ON DELETE SET NULL
 );
 
+CREATE TABLE IF NOT EXISTS library_translation_memory (
+  id TEXT PRIMARY KEY,
+  target_language TEXT NOT NULL,
+  source_text TEXT NOT NULL,
+  source_key TEXT NOT NULL,
+  source_length INTEGER NOT NULL,
+  target_text TEXT NOT NULL,
+  origin_kind TEXT NOT NULL CHECK (origin_kind IN ('translate','review_apply')),
+  library_id TEXT,
+  file_id TEXT,
+  operation_id TEXT,
+  use_count INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  UNIQUE (target_language, source_key)
+);
+
+CREATE TABLE IF NOT EXISTS library_subscriptions (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  library_id TEXT,
+  connector_id TEXT,
+  use_connector INTEGER NOT NULL DEFAULT 0,
+  quality TEXT,
+  format_id TEXT,
+  subtitle_langs_json TEXT,
+  subtitle_auto INTEGER NOT NULL DEFAULT 0,
+  subtitle_format TEXT,
+  transcode_preset_id TEXT,
+  delete_source_file_after_transcode INTEGER NOT NULL DEFAULT 0,
+  poll_interval_minutes INTEGER NOT NULL,
+  download_existing INTEGER NOT NULL DEFAULT 0,
+  enabled INTEGER NOT NULL DEFAULT 1,
+  last_checked_at TIMESTAMP,
+  last_error TEXT,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS library_subscription_archive (
+  subscription_id TEXT NOT NULL,
+  entry_id TEXT NOT NULL,
+  operation_id TEXT,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (subscription_id, entry_id),
+  FOREIGN KEY (subscription_id) REFERENCES library_subscriptions(id) ON DELETE CASCADE
+);
+
 CREATE INDEX IF NOT EXISTS library_files_library_created_idx ON library_files(library_id, created_at DESC);
 CREATE INDEX IF NOT EXISTS library_subtitle_revisions_file_created_idx ON library_subtitle_revisions(file_id, created_at DESC);
 CREATE INDEX IF NOT EXISTS library_subtitle_review_sessions_file_updated_idx ON library_subtitle_review_sessions(file_id, updated_at DESC);
@@ -1354,6 +1636,7 @@ CREATE INDEX IF NOT EXISTS library_operations_library_created_idx ON library_ope
 CREATE INDEX IF NOT EXISTS library_operation_chunks_operation_idx ON library_operation_chunks(operation_id, chunk_index ASC);
 CREATE INDEX IF NOT EXISTS library_history_library_occurred_idx ON library_history_records(library_id, occurred_at DESC);
 CREATE INDEX IF NOT EXISTS library_events_library_created_idx ON library_file_events(library_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS library_translation_memory_length_idx ON library_translation_memory(target_language, source_length);
 
 CREATE TRIGGER IF NOT EXISTS trg_operation_output_same_library
 BEFORE INSERT ON library_operation_outputs
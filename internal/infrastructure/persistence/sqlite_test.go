@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -217,3 +218,67 @@ CREATE TABLE gateway_sessions (
 		}
 	}
 }
+
+func TestEnsureLibraryTableConstraints_RebuildsLegacyOperationKinds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "legacy-library.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	legacySchema := strings.ReplaceAll(librarySchemaSQL, ",'subtitle_transcribe'", "")
+	if legacySchema == librarySchemaSQL {
+		t.Fatalf("expected legacy schema to differ from current schema")
+	}
+	if _, err := db.ExecContext(ctx, legacySchema); err != nil {
+		t.Fatalf("create legacy library schema: %v", err)
+	}
+	const ts = "2026-04-03T14:02:55Z"
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO library_libraries (id, name, created_by_json, created_at, updated_at) VALUES ('lib-1', 'Library', '{}', ?, ?)
+`, ts, ts); err != nil {
+		t.Fatalf("insert legacy library: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO library_operations (id, library_id, kind, status, display_name, correlation_json, input_json, output_json, created_at)
+VALUES ('op-1', 'lib-1', 'transcode', 'succeeded', 'Transcode', '{}', '{}', '{}', ?)
+`, ts); err != nil {
+		t.Fatalf("insert legacy operation: %v", err)
+	}
+
+	if err := ensureLibraryTableConstraints(ctx, db); err != nil {
+		t.Fatalf("ensure constraints: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO library_operations (id, library_id, kind, status, display_name, correlation_json, input_json, output_json, created_at)
+VALUES ('op-2', 'lib-1', 'subtitle_transcribe', 'queued', 'Transcribe', '{}', '{}', '{}', ?)
+`, ts); err != nil {
+		t.Fatalf("insert new operation kind after rebuild: %v", err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM library_operations").Scan(&count); err != nil {
+		t.Fatalf("count operations: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected legacy operation to survive rebuild, got %d rows", count)
+	}
+	var indexCount int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'library_operations_library_created_idx'").Scan(&indexCount); err != nil {
+		t.Fatalf("query index: %v", err)
+	}
+	if indexCount != 1 {
+		t.Fatalf("expected operation index to be recreated")
+	}
+
+	var stored string
+	if err := db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'library_operations'").Scan(&stored); err != nil {
+		t.Fatalf("query stored schema: %v", err)
+	}
+	if normalizeSQLiteTableDefinition(stored) != normalizeSQLiteTableDefinition(libraryCreateTableStatement("library_operations")) {
+		t.Fatalf("expected rebuilt definition to match current schema, got %s", stored)
+	}
+}
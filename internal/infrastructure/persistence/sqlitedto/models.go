@@ -152,6 +152,24 @@ type AuditRow struct {
 	CreatedAt   time.Time `bun:"created_at"`
 }
 
+type PolicyRuleRow struct {
+	bun.BaseModel `bun:"table:tool_policy_rules"`
+
+	ID                  string    `bun:"id,pk"`
+	Name                string    `bun:"name"`
+	Tool                string    `bun:"tool"`
+	Action              string    `bun:"action"`
+	Decision            string    `bun:"decision"`
+	Reason              string    `bun:"reason"`
+	CommandPrefixesJSON string    `bun:"command_prefixes_json"`
+	CommandPatternsJSON string    `bun:"command_patterns_json"`
+	PathGlobsJSON       string    `bun:"path_globs_json"`
+	DomainsJSON         string    `bun:"domains_json"`
+	Enabled             bool      `bun:"enabled"`
+	CreatedAt           time.Time `bun:"created_at"`
+	UpdatedAt           time.Time `bun:"updated_at"`
+}
+
 type ConnectorRow struct {
 	bun.BaseModel `bun:"table:connectors"`
 
@@ -544,27 +562,37 @@ type ToolRunRow struct {
 type TranscodePresetRow struct {
 	bun.BaseModel `bun:"table:transcode_presets"`
 
-	ID               string         `bun:"id,pk"`
-	Name             string         `bun:"name"`
-	OutputType       string         `bun:"output_type"`
-	Container        string         `bun:"container"`
-	VideoCodec       sql.NullString `bun:"video_codec"`
-	AudioCodec       sql.NullString `bun:"audio_codec"`
-	QualityMode      sql.NullString `bun:"quality_mode"`
-	CRF              sql.NullInt64  `bun:"crf"`
-	BitrateKbps      sql.NullInt64  `bun:"bitrate_kbps"`
-	AudioBitrateKbps sql.NullInt64  `bun:"audio_bitrate_kbps"`
-	Scale            sql.NullString `bun:"scale"`
-	Width            sql.NullInt64  `bun:"width"`
-	Height           sql.NullInt64  `bun:"height"`
-	FFmpegPreset     sql.NullString `bun:"ffmpeg_preset"`
-	AllowUpscale     bool           `bun:"allow_upscale"`
-	RequiresVideo    bool           `bun:"requires_video"`
-	RequiresAudio    bool           `bun:"requires_audio"`
-	IsBuiltin        bool           `bun:"is_builtin"`
-	Description      sql.NullString `bun:"description"`
-	CreatedAt        time.Time      `bun:"created_at"`
-	UpdatedAt        time.Time      `bun:"updated_at"`
+	ID                string          `bun:"id,pk"`
+	Name              string          `bun:"name"`
+	OutputType        string          `bun:"output_type"`
+	Container         string          `bun:"container"`
+	VideoCodec        sql.NullString  `bun:"video_codec"`
+	AudioCodec        sql.NullString  `bun:"audio_codec"`
+	QualityMode       sql.NullString  `bun:"quality_mode"`
+	CRF               sql.NullInt64   `bun:"crf"`
+	BitrateKbps       sql.NullInt64   `bun:"bitrate_kbps"`
+	AudioBitrateKbps  sql.NullInt64   `bun:"audio_bitrate_kbps"`
+	Scale             sql.NullString  `bun:"scale"`
+	Width             sql.NullInt64   `bun:"width"`
+	Height            sql.NullInt64   `bun:"height"`
+	FFmpegPreset      sql.NullString  `bun:"ffmpeg_preset"`
+	AllowUpscale      bool            `bun:"allow_upscale"`
+	RequiresVideo     bool            `bun:"requires_video"`
+	RequiresAudio     bool            `bun:"requires_audio"`
+	IsBuiltin         bool            `bun:"is_builtin"`
+	Description       sql.NullString  `bun:"description"`
+	AspectRatio       sql.NullString  `bun:"aspect_ratio"`
+	ReframeMode       sql.NullString  `bun:"reframe_mode"`
+	MaxBitrateKbps    sql.NullInt64   `bun:"max_bitrate_kbps"`
+	MaxFileSizeMB     sql.NullInt64   `bun:"max_file_size_mb"`
+	LoudnessNormalize bool            `bun:"loudness_normalize"`
+	LoudnessTargetI   sql.NullFloat64 `bun:"loudness_target_i"`
+	LoudnessTruePeak  sql.NullFloat64 `bun:"loudness_true_peak"`
+	LoudnessRange     sql.NullFloat64 `bun:"loudness_range"`
+	NoiseReduction    bool            `bun:"noise_reduction"`
+	TrimSilence       bool            `bun:"trim_silence"`
+	CreatedAt         time.Time       `bun:"created_at"`
+	UpdatedAt         time.Time       `bun:"updated_at"`
 }
 
 type TtsJobRow struct {
@@ -0,0 +1,56 @@
+//go:build unix
+
+package processutil
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+const groupExitPollInterval = 50 * time.Millisecond
+
+// ConfigureProcessGroup starts the command in its own process group so the
+// whole tree can be signalled with TerminateProcessGroup.
+func ConfigureProcessGroup(cmd *exec.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// TerminateProcessGroup asks the process group of a command started with
+// ConfigureProcessGroup to exit with SIGTERM and sends SIGKILL to whatever is
+// left after grace. The group id is the leader's pid, so background children
+// are signalled even after the leader itself has exited. The leader must be
+// reaped concurrently (by cmd.Wait) for an early exit to be noticed.
+func TerminateProcessGroup(cmd *exec.Cmd, grace time.Duration) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	pgid := cmd.Process.Pid
+	if grace > 0 {
+		if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
+			return ignoreGroupGone(err)
+		}
+		deadline := time.Now().Add(grace)
+		for time.Now().Before(deadline) {
+			time.Sleep(groupExitPollInterval)
+			if err := syscall.Kill(-pgid, 0); errors.Is(err, syscall.ESRCH) {
+				return nil
+			}
+		}
+	}
+	return ignoreGroupGone(syscall.Kill(-pgid, syscall.SIGKILL))
+}
+
+func ignoreGroupGone(err error) error {
+	if errors.Is(err, syscall.ESRCH) {
+		return nil
+	}
+	return err
+}
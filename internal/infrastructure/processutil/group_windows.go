@@ -0,0 +1,34 @@
+//go:build windows
+
+package processutil
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ConfigureProcessGroup starts the command in a new process group so the
+// whole tree can be stopped with TerminateProcessGroup.
+func ConfigureProcessGroup(cmd *exec.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// TerminateProcessGroup kills a started command together with its child
+// processes. Console process trees have no graceful stop signal on Windows,
+// so grace is not used and the tree is killed right away.
+func TerminateProcessGroup(cmd *exec.Cmd, _ time.Duration) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	killCmd := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	ConfigureCLI(killCmd)
+	return killCmd.Run()
+}
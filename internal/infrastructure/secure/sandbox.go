@@ -0,0 +1,26 @@
+package secure
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// SandboxPolicy describes how a sandboxed command is confined. Zero limits
+// mean the limit is not enforced.
+type SandboxPolicy struct {
+	WorkspaceDir  string        `json:"workspaceDir,omitempty"`
+	WritablePaths []string      `json:"writablePaths,omitempty"`
+	AllowNetwork  bool          `json:"allowNetwork"`
+	CPUSeconds    int           `json:"cpuSeconds,omitempty"`
+	MemoryBytes   int64         `json:"memoryBytes,omitempty"`
+	Timeout       time.Duration `json:"timeout,omitempty"`
+}
+
+// CommandSandbox starts child processes inside an isolated environment and
+// reports whether the host can provide that isolation.
+type CommandSandbox interface {
+	HealthChecker
+	Backend() string
+	Command(ctx context.Context, policy SandboxPolicy, name string, args ...string) (*exec.Cmd, error)
+}
@@ -0,0 +1,251 @@
+//go:build linux
+
+package secure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bubblewrapBackend       = "bwrap"
+	systemdRunCommand       = "systemd-run"
+	bubblewrapProbeTimeout  = 5 * time.Second
+	sandboxHealthyCacheTTL  = 5 * time.Minute
+	sandboxFailureCacheTTL  = 30 * time.Second
+	sandboxTimeoutKillAfter = "5"
+)
+
+// bubblewrapSandbox confines commands with bubblewrap: the host filesystem is
+// mounted read-only, only the workspace and explicit paths stay writable, and
+// pid/ipc/uts (and optionally network) namespaces are unshared. The CPU
+// limit is applied with ulimit inside the sandbox and the wall-clock limit
+// with timeout(1). Memory is limited through a transient systemd cgroup scope
+// when the user session can create one; otherwise it is not enforced and the
+// health message says so. RLIMIT_AS is deliberately not used: it caps address
+// space, which runtimes such as node, Go and the JVM reserve far beyond what
+// they use.
+type bubblewrapSandbox struct {
+	lookPath func(file string) (string, error)
+	now      func() time.Time
+
+	mu        sync.Mutex
+	status    SandboxHealthStatus
+	expiresAt time.Time
+	// memoryScope is the systemd-run binary when it can create memory
+	// limited scopes, and empty otherwise.
+	memoryScope string
+}
+
+func NewCommandSandbox() CommandSandbox {
+	return &bubblewrapSandbox{
+		lookPath: exec.LookPath,
+		now:      time.Now,
+	}
+}
+
+func (sandbox *bubblewrapSandbox) Backend() string {
+	return bubblewrapBackend
+}
+
+func (sandbox *bubblewrapSandbox) Check(ctx context.Context) SandboxHealthStatus {
+	sandbox.mu.Lock()
+	defer sandbox.mu.Unlock()
+	now := sandbox.now()
+	if !sandbox.status.CheckedAt.IsZero() && now.Before(sandbox.expiresAt) {
+		return sandbox.status
+	}
+	status := sandbox.probe(ctx)
+	sandbox.memoryScope = ""
+	if status.Available {
+		scope, err := sandbox.probeMemoryScope(ctx)
+		if err != nil {
+			status.Message = "ok; memory limit not enforced: " + err.Error()
+		}
+		sandbox.memoryScope = scope
+	}
+	status.CheckedAt = now
+	ttl := sandboxFailureCacheTTL
+	if status.Available {
+		ttl = sandboxHealthyCacheTTL
+	}
+	sandbox.status = status
+	sandbox.expiresAt = now.Add(ttl)
+	return status
+}
+
+// probe runs a trivial command in a fresh sandbox, which catches hosts where
+// bwrap is installed but unprivileged user namespaces are disabled.
+func (sandbox *bubblewrapSandbox) probe(ctx context.Context) SandboxHealthStatus {
+	bwrap, err := sandbox.lookPath(bubblewrapBackend)
+	if err != nil {
+		return SandboxHealthStatus{Message: "bubblewrap (bwrap) is not installed"}
+	}
+	truePath, err := sandbox.lookPath("true")
+	if err != nil {
+		return SandboxHealthStatus{Message: "sandbox probe command not found"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, bubblewrapProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bwrap, append(bubblewrapArgs(SandboxPolicy{}), "--", truePath)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return SandboxHealthStatus{Message: "bubblewrap unavailable: " + message}
+	}
+	return SandboxHealthStatus{Available: true, Message: "ok"}
+}
+
+// probeMemoryScope checks that systemd-run can place a command in a user
+// scope with a memory limit, which needs a systemd user session with the
+// memory controller delegated.
+func (sandbox *bubblewrapSandbox) probeMemoryScope(ctx context.Context) (string, error) {
+	systemdRun, err := sandbox.lookPath(systemdRunCommand)
+	if err != nil {
+		return "", errors.New("systemd-run is not installed")
+	}
+	truePath, err := sandbox.lookPath("true")
+	if err != nil {
+		return "", errors.New("sandbox probe command not found")
+	}
+	ctx, cancel := context.WithTimeout(ctx, bubblewrapProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, systemdRun, append(memoryScopeArgs(64<<20), truePath)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", errors.New("no cgroup memory scope available: " + message)
+	}
+	return systemdRun, nil
+}
+
+func (sandbox *bubblewrapSandbox) memoryScopeCommand(ctx context.Context) string {
+	sandbox.Check(ctx)
+	sandbox.mu.Lock()
+	defer sandbox.mu.Unlock()
+	return sandbox.memoryScope
+}
+
+func (sandbox *bubblewrapSandbox) Command(ctx context.Context, policy SandboxPolicy, name string, args ...string) (*exec.Cmd, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("command is required")
+	}
+	bwrap, err := sandbox.lookPath(bubblewrapBackend)
+	if err != nil {
+		return nil, errors.New("bubblewrap (bwrap) is not installed")
+	}
+	timeoutPath := ""
+	if policy.Timeout > 0 {
+		// Without timeout(1) the wall-clock limit could not be enforced, so
+		// the command is refused rather than run unbounded.
+		if timeoutPath, err = sandbox.lookPath("timeout"); err != nil {
+			return nil, errors.New("sandbox: timeout is not installed, so the time limit cannot be enforced")
+		}
+	}
+	command := append([]string{bwrap}, bubblewrapArgs(policy)...)
+	command = append(command, "--")
+	command = append(command, limitedCommand(policy, timeoutPath, name, args...)...)
+	if policy.MemoryBytes > 0 {
+		if systemdRun := sandbox.memoryScopeCommand(ctx); systemdRun != "" {
+			command = append(append([]string{systemdRun}, memoryScopeArgs(policy.MemoryBytes)...), command...)
+		}
+	}
+	return exec.CommandContext(ctx, command[0], command[1:]...), nil
+}
+
+// limitedCommand wraps a command with the CPU and wall-clock limits of the
+// policy. timeoutPath must be set when the policy has a timeout.
+func limitedCommand(policy SandboxPolicy, timeoutPath string, name string, args ...string) []string {
+	command := append([]string{name}, args...)
+	if limits := ulimitScript(policy); limits != "" {
+		command = append([]string{"/bin/sh", "-c", limits + `exec "$@"`, "sh"}, command...)
+	}
+	if policy.Timeout > 0 {
+		seconds := strconv.Itoa(int((policy.Timeout + time.Second - 1) / time.Second))
+		command = append([]string{timeoutPath, "-k", sandboxTimeoutKillAfter, seconds}, command...)
+	}
+	return command
+}
+
+// memoryScopeArgs runs the rest of the command line in a transient user
+// scope whose cgroup caps memory use, without swapping past the cap.
+func memoryScopeArgs(limit int64) []string {
+	return []string{
+		"--user", "--scope", "--quiet", "--collect",
+		"-p", "MemoryMax=" + strconv.FormatInt(limit, 10),
+		"-p", "MemorySwapMax=0",
+		"--",
+	}
+}
+
+func bubblewrapArgs(policy SandboxPolicy) []string {
+	args := []string{
+		"--die-with-parent",
+		"--new-session",
+		"--unshare-user-try",
+		"--unshare-pid",
+		"--unshare-ipc",
+		"--unshare-uts",
+		"--unshare-cgroup-try",
+	}
+	if !policy.AllowNetwork {
+		args = append(args, "--unshare-net")
+	}
+	args = append(args,
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+	)
+	// Writable binds come after the /tmp tmpfs so workspaces under /tmp
+	// stay reachable.
+	for _, path := range sandboxWritablePaths(policy) {
+		args = append(args, "--bind", path, path)
+	}
+	return args
+}
+
+func sandboxWritablePaths(policy SandboxPolicy) []string {
+	seen := make(map[string]struct{})
+	result := make([]string, 0, len(policy.WritablePaths)+1)
+	for _, path := range append([]string{policy.WorkspaceDir}, policy.WritablePaths...) {
+		path = strings.TrimSpace(path)
+		if path == "" || !filepath.IsAbs(path) {
+			continue
+		}
+		path = filepath.Clean(path)
+		if path == "/" {
+			continue
+		}
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		result = append(result, path)
+	}
+	return result
+}
+
+func ulimitScript(policy SandboxPolicy) string {
+	var script strings.Builder
+	if policy.CPUSeconds > 0 {
+		fmt.Fprintf(&script, "ulimit -t %d || exit 126; ", policy.CPUSeconds)
+	}
+	return script.String()
+}
@@ -0,0 +1,169 @@
+//go:build linux
+
+package secure
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeLookPath(available ...string) func(string) (string, error) {
+	return func(file string) (string, error) {
+		for _, name := range available {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestBubblewrapCommandConfinesWrites(t *testing.T) {
+	sandbox := &bubblewrapSandbox{lookPath: fakeLookPath("bwrap", "timeout"), now: time.Now}
+	cmd, err := sandbox.Command(context.Background(), SandboxPolicy{
+		WorkspaceDir:  "/home/user/workspace",
+		WritablePaths: []string{"/home/user/workspace", "relative", "/"},
+		CPUSeconds:    30,
+		MemoryBytes:   512 << 20,
+		Timeout:       90 * time.Second,
+	}, "bash", "-lc", "make")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	args := strings.Join(cmd.Args, " ")
+	for _, expected := range []string{
+		"--ro-bind / /",
+		"--tmpfs /tmp --bind /home/user/workspace /home/user/workspace --",
+		"--unshare-net",
+		"-- /usr/bin/timeout -k 5 90 /bin/sh -c ulimit -t 30 || exit 126; exec \"$@\" sh bash -lc make",
+	} {
+		if !strings.Contains(args, expected) {
+			t.Fatalf("expected %q in %s", expected, args)
+		}
+	}
+	if strings.Count(args, "--bind ") != 1 {
+		t.Fatalf("expected only the workspace to be writable, got %s", args)
+	}
+	if strings.Contains(args, "ulimit -v") {
+		t.Fatalf("expected no address space limit, got %s", args)
+	}
+}
+
+func TestBubblewrapCommandRefusesTimeoutWithoutTimeoutBinary(t *testing.T) {
+	sandbox := &bubblewrapSandbox{lookPath: fakeLookPath("bwrap"), now: time.Now}
+	if _, err := sandbox.Command(context.Background(), SandboxPolicy{Timeout: time.Minute}, "ls"); err == nil {
+		t.Fatal("expected the command to be refused without timeout")
+	}
+}
+
+func TestBubblewrapCommandLimitsMemoryWithScope(t *testing.T) {
+	now := time.Unix(0, 0)
+	sandbox := &bubblewrapSandbox{
+		lookPath:    fakeLookPath("bwrap"),
+		now:         func() time.Time { return now },
+		status:      SandboxHealthStatus{Available: true, Message: "ok", CheckedAt: now},
+		expiresAt:   now.Add(time.Minute),
+		memoryScope: "/usr/bin/systemd-run",
+	}
+	cmd, err := sandbox.Command(context.Background(), SandboxPolicy{MemoryBytes: 512 << 20}, "ls")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	args := strings.Join(cmd.Args, " ")
+	expected := "/usr/bin/systemd-run --user --scope --quiet --collect -p MemoryMax=536870912 -p MemorySwapMax=0 -- /usr/bin/bwrap "
+	if !strings.HasPrefix(args, expected) || !strings.HasSuffix(args, "-- ls") {
+		t.Fatalf("unexpected args %s", args)
+	}
+
+	sandbox.memoryScope = ""
+	cmd, err = sandbox.Command(context.Background(), SandboxPolicy{MemoryBytes: 512 << 20}, "ls")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if cmd.Args[0] != "/usr/bin/bwrap" {
+		t.Fatalf("expected bwrap without a memory scope, got %v", cmd.Args)
+	}
+}
+
+// TestLimitedCommandRunsLargeReservations runs node, which reserves far more
+// address space than it uses, under the default gateway limits.
+func TestLimitedCommandRunsLargeReservations(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	timeoutPath, err := exec.LookPath("timeout")
+	if err != nil {
+		t.Skip("timeout is not installed")
+	}
+	policy := SandboxPolicy{CPUSeconds: 300, MemoryBytes: 2048 << 20, Timeout: 900 * time.Second}
+	script := "new WebAssembly.Memory({initial: 1}); console.log('ok')"
+	command := limitedCommand(policy, timeoutPath, node, "-e", script)
+	output, err := exec.Command(command[0], command[1:]...).CombinedOutput()
+	if err != nil || strings.TrimSpace(string(output)) != "ok" {
+		t.Fatalf("expected node to run under the limits: %v (%s)", err, output)
+	}
+
+	sandbox := NewCommandSandbox()
+	if status := sandbox.Check(context.Background()); !status.Available {
+		t.Skipf("bubblewrap unavailable: %s", status.Message)
+	}
+	cmd, err := sandbox.Command(context.Background(), policy, node, "-e", script)
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil || strings.TrimSpace(string(output)) != "ok" {
+		t.Fatalf("expected node to run in the sandbox: %v (%s)", err, output)
+	}
+}
+
+func TestBubblewrapCommandKeepsNetworkWhenAllowed(t *testing.T) {
+	sandbox := &bubblewrapSandbox{lookPath: fakeLookPath("bwrap"), now: time.Now}
+	cmd, err := sandbox.Command(context.Background(), SandboxPolicy{AllowNetwork: true}, "ls")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	args := strings.Join(cmd.Args, " ")
+	if strings.Contains(args, "--unshare-net") || !strings.HasSuffix(args, "-- ls") {
+		t.Fatalf("unexpected args %s", args)
+	}
+}
+
+func TestBubblewrapHealthReportsMissingBinary(t *testing.T) {
+	now := time.Unix(0, 0)
+	sandbox := &bubblewrapSandbox{
+		lookPath: fakeLookPath(),
+		now:      func() time.Time { return now },
+	}
+	status := sandbox.Check(context.Background())
+	if status.Available || !strings.Contains(status.Message, "not installed") {
+		t.Fatalf("unexpected status %#v", status)
+	}
+	sandbox.lookPath = func(string) (string, error) { return "", errors.New("unexpected probe") }
+	if cached := sandbox.Check(context.Background()); cached.Message != status.Message {
+		t.Fatalf("expected the status to be cached, got %#v", cached)
+	}
+	if _, err := sandbox.Command(context.Background(), SandboxPolicy{}, "ls"); err == nil {
+		t.Fatal("expected commands to fail without bwrap")
+	}
+}
+
+func TestBubblewrapRunsCommandWhenAvailable(t *testing.T) {
+	sandbox := NewCommandSandbox()
+	if status := sandbox.Check(context.Background()); !status.Available {
+		t.Skipf("bubblewrap unavailable: %s", status.Message)
+	}
+	workspace := t.TempDir()
+	cmd, err := sandbox.Command(context.Background(), SandboxPolicy{WorkspaceDir: workspace}, "/bin/sh", "-c", "touch ok && ! touch /etc/dreamcreator-sandbox-probe")
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	cmd.Dir = workspace
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("sandboxed command failed: %v (%s)", err, output)
+	}
+}
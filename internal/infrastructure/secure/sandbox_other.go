@@ -0,0 +1,9 @@
+//go:build !linux
+
+package secure
+
+// NewCommandSandbox returns nil on platforms without a sandbox backend;
+// callers keep running tools unconfined there.
+func NewCommandSandbox() CommandSandbox {
+	return nil
+}
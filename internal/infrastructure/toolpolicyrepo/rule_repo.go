@@ -0,0 +1,228 @@
+package toolpolicyrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+
+	"dreamcreator/internal/infrastructure/persistence/sqlitedto"
+)
+
+type policyRuleRow = sqlitedto.PolicyRuleRow
+
+// SQLitePolicyRuleStore persists policy rules and evaluates tool calls
+// against them. Rules are cached in memory until the next write.
+type SQLitePolicyRuleStore struct {
+	db *bun.DB
+
+	mu     sync.RWMutex
+	cached []PolicyRule
+	loaded bool
+}
+
+func NewSQLitePolicyRuleStore(db *bun.DB) *SQLitePolicyRuleStore {
+	return &SQLitePolicyRuleStore{db: db}
+}
+
+func (store *SQLitePolicyRuleStore) List(ctx context.Context) ([]PolicyRule, error) {
+	if store == nil || store.db == nil {
+		return nil, errors.New("policy rule store unavailable")
+	}
+	store.mu.RLock()
+	if store.loaded {
+		rules := append([]PolicyRule(nil), store.cached...)
+		store.mu.RUnlock()
+		return rules, nil
+	}
+	store.mu.RUnlock()
+
+	rows := make([]policyRuleRow, 0)
+	if err := store.db.NewSelect().Model(&rows).Order("created_at ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	rules := make([]PolicyRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := toPolicyRule(row)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	store.mu.Lock()
+	store.cached = rules
+	store.loaded = true
+	store.mu.Unlock()
+	return append([]PolicyRule(nil), rules...), nil
+}
+
+// Save creates or replaces a rule. A new ID is assigned when the rule has
+// none.
+func (store *SQLitePolicyRuleStore) Save(ctx context.Context, rule PolicyRule) (PolicyRule, error) {
+	if store == nil || store.db == nil {
+		return PolicyRule{}, errors.New("policy rule store unavailable")
+	}
+	rule = normalizePolicyRule(rule)
+	if err := rule.Validate(); err != nil {
+		return PolicyRule{}, err
+	}
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = uuid.NewString()
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+	row, err := toPolicyRuleRow(rule)
+	if err != nil {
+		return PolicyRule{}, err
+	}
+	_, err = store.db.NewInsert().Model(&row).
+		On("CONFLICT(id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("tool = EXCLUDED.tool").
+		Set("action = EXCLUDED.action").
+		Set("decision = EXCLUDED.decision").
+		Set("reason = EXCLUDED.reason").
+		Set("command_prefixes_json = EXCLUDED.command_prefixes_json").
+		Set("command_patterns_json = EXCLUDED.command_patterns_json").
+		Set("path_globs_json = EXCLUDED.path_globs_json").
+		Set("domains_json = EXCLUDED.domains_json").
+		Set("enabled = EXCLUDED.enabled").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	store.invalidate()
+	if err != nil {
+		return PolicyRule{}, err
+	}
+	return rule, nil
+}
+
+func (store *SQLitePolicyRuleStore) Delete(ctx context.Context, id string) error {
+	if store == nil || store.db == nil {
+		return errors.New("policy rule store unavailable")
+	}
+	result, err := store.db.NewDelete().Model((*policyRuleRow)(nil)).Where("id = ?", strings.TrimSpace(id)).Exec(ctx)
+	store.invalidate()
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrPolicyRuleNotFound
+	}
+	return nil
+}
+
+func (store *SQLitePolicyRuleStore) Evaluate(ctx context.Context, call ToolCall) (Verdict, error) {
+	rules, err := store.List(ctx)
+	if err != nil {
+		return Verdict{}, err
+	}
+	return EvaluateRules(rules, call), nil
+}
+
+func (store *SQLitePolicyRuleStore) invalidate() {
+	store.mu.Lock()
+	store.cached = nil
+	store.loaded = false
+	store.mu.Unlock()
+}
+
+func normalizePolicyRule(rule PolicyRule) PolicyRule {
+	rule.ID = strings.TrimSpace(rule.ID)
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Tool = strings.TrimSpace(rule.Tool)
+	rule.Action = strings.TrimSpace(rule.Action)
+	rule.Decision = strings.ToLower(strings.TrimSpace(rule.Decision))
+	rule.Reason = strings.TrimSpace(rule.Reason)
+	rule.CommandPrefixes = compactStrings(rule.CommandPrefixes)
+	rule.CommandPatterns = compactStrings(rule.CommandPatterns)
+	rule.PathGlobs = compactStrings(rule.PathGlobs)
+	rule.Domains = compactStrings(rule.Domains)
+	return rule
+}
+
+func compactStrings(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func toPolicyRuleRow(rule PolicyRule) (policyRuleRow, error) {
+	row := policyRuleRow{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		Tool:      rule.Tool,
+		Action:    rule.Action,
+		Decision:  rule.Decision,
+		Reason:    rule.Reason,
+		Enabled:   rule.Enabled,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	lists := []struct {
+		values []string
+		target *string
+	}{
+		{rule.CommandPrefixes, &row.CommandPrefixesJSON},
+		{rule.CommandPatterns, &row.CommandPatternsJSON},
+		{rule.PathGlobs, &row.PathGlobsJSON},
+		{rule.Domains, &row.DomainsJSON},
+	}
+	for _, list := range lists {
+		if len(list.values) == 0 {
+			continue
+		}
+		data, err := json.Marshal(list.values)
+		if err != nil {
+			return policyRuleRow{}, err
+		}
+		*list.target = string(data)
+	}
+	return row, nil
+}
+
+func toPolicyRule(row policyRuleRow) (PolicyRule, error) {
+	rule := PolicyRule{
+		ID:        row.ID,
+		Name:      row.Name,
+		Tool:      row.Tool,
+		Action:    row.Action,
+		Decision:  row.Decision,
+		Reason:    row.Reason,
+		Enabled:   row.Enabled,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	lists := []struct {
+		raw    string
+		target *[]string
+	}{
+		{row.CommandPrefixesJSON, &rule.CommandPrefixes},
+		{row.CommandPatternsJSON, &rule.CommandPatterns},
+		{row.PathGlobsJSON, &rule.PathGlobs},
+		{row.DomainsJSON, &rule.Domains},
+	}
+	for _, list := range lists {
+		if strings.TrimSpace(list.raw) == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(list.raw), list.target); err != nil {
+			return PolicyRule{}, err
+		}
+	}
+	return rule, nil
+}
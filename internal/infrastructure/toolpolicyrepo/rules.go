@@ -0,0 +1,550 @@
+package toolpolicyrepo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	RuleDecisionAllow = "allow"
+	RuleDecisionAsk   = "ask"
+	RuleDecisionDeny  = "deny"
+)
+
+var ErrPolicyRuleNotFound = errors.New("policy rule not found")
+
+// PolicyRule matches a tool call by tool name and, optionally, by predicates
+// on its arguments. Every predicate kind that is set must match. Deny and ask
+// rules match when any command, path or URL of the call matches; allow rules
+// only match when all of them do, so a compound command cannot piggyback on
+// an allowed prefix. Command predicates see each segment both as written and
+// with wrappers such as env, sudo, eval, xargs or "sh -c" removed and argv[0]
+// reduced to its base name, so "/bin/rm", "sudo rm" and "bash -c 'rm'" match
+// an "rm" prefix. Allow rules never match a command that redirects output to
+// a file, since the redirection writes outside the allowed command.
+type PolicyRule struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name,omitempty"`
+	Tool            string    `json:"tool,omitempty"`
+	Action          string    `json:"action,omitempty"`
+	Decision        string    `json:"decision"`
+	Reason          string    `json:"reason,omitempty"`
+	CommandPrefixes []string  `json:"commandPrefixes,omitempty"`
+	CommandPatterns []string  `json:"commandPatterns,omitempty"`
+	PathGlobs       []string  `json:"pathGlobs,omitempty"`
+	Domains         []string  `json:"domains,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// ToolCall is the subject a rule is evaluated against. Paths should be
+// absolute; a relative path is unresolved, so allow rules with path globs do
+// not match it and deny or ask rules that might match it require approval.
+// URLs may omit the scheme.
+type ToolCall struct {
+	Tool     string
+	Action   string
+	Commands []string
+	Paths    []string
+	URLs     []string
+}
+
+type Verdict struct {
+	Decision     string   `json:"decision,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+	MatchedRules []string `json:"matchedRules,omitempty"`
+}
+
+func (rule PolicyRule) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(rule.Decision)) {
+	case RuleDecisionAllow, RuleDecisionAsk, RuleDecisionDeny:
+	default:
+		return fmt.Errorf("invalid rule decision %q", rule.Decision)
+	}
+	if strings.TrimSpace(rule.Tool) == "" && !rule.hasPredicates() {
+		return errors.New("rule needs a tool or an argument predicate")
+	}
+	if _, err := path.Match(strings.TrimSpace(rule.Tool), ""); err != nil {
+		return fmt.Errorf("invalid tool pattern %q", rule.Tool)
+	}
+	for _, pattern := range rule.CommandPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid command pattern %q: %w", pattern, err)
+		}
+	}
+	for _, glob := range rule.PathGlobs {
+		if strings.TrimSpace(glob) == "" {
+			return errors.New("path glob is empty")
+		}
+	}
+	return nil
+}
+
+func (rule PolicyRule) hasPredicates() bool {
+	return len(rule.CommandPrefixes) > 0 || len(rule.CommandPatterns) > 0 || len(rule.PathGlobs) > 0 || len(rule.Domains) > 0
+}
+
+// EvaluateRules returns the most restrictive verdict of the enabled rules that
+// match the call (deny over ask over allow). The decision is empty when no
+// rule matched.
+func EvaluateRules(rules []PolicyRule, call ToolCall) Verdict {
+	verdict := Verdict{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		matched, unresolved := rule.matches(call)
+		if !matched {
+			continue
+		}
+		verdict.MatchedRules = append(verdict.MatchedRules, "rule:"+rule.ID)
+		decision := strings.ToLower(strings.TrimSpace(rule.Decision))
+		reason := strings.TrimSpace(rule.Reason)
+		if unresolved {
+			decision = RuleDecisionAsk
+			reason = fmt.Sprintf("approval required: policy rule %s may apply to a relative path", rule.label())
+		}
+		if decisionRank(decision) <= decisionRank(verdict.Decision) {
+			continue
+		}
+		verdict.Decision = decision
+		verdict.Reason = reason
+		if verdict.Reason == "" {
+			verdict.Reason = fmt.Sprintf("%s by policy rule %s", ruleVerb(decision), rule.label())
+		}
+	}
+	return verdict
+}
+
+func decisionRank(decision string) int {
+	switch decision {
+	case RuleDecisionDeny:
+		return 3
+	case RuleDecisionAsk:
+		return 2
+	case RuleDecisionAllow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func ruleVerb(decision string) string {
+	switch decision {
+	case RuleDecisionDeny:
+		return "denied"
+	case RuleDecisionAsk:
+		return "approval required"
+	default:
+		return "allowed"
+	}
+}
+
+func (rule PolicyRule) label() string {
+	if name := strings.TrimSpace(rule.Name); name != "" {
+		return fmt.Sprintf("%q", name)
+	}
+	return rule.ID
+}
+
+// matches reports whether the rule applies to the call. unresolved is set
+// when a deny or ask rule matched only because a relative path might fall
+// under its path globs.
+func (rule PolicyRule) matches(call ToolCall) (matched bool, unresolved bool) {
+	if pattern := strings.TrimSpace(rule.Tool); pattern != "" {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(strings.TrimSpace(call.Tool))); !ok {
+			return false, false
+		}
+	}
+	if action := strings.TrimSpace(rule.Action); action != "" && !strings.EqualFold(action, strings.TrimSpace(call.Action)) {
+		return false, false
+	}
+	requireAll := strings.EqualFold(strings.TrimSpace(rule.Decision), RuleDecisionAllow)
+	if len(rule.CommandPrefixes) > 0 || len(rule.CommandPatterns) > 0 {
+		segments := commandSegments(call.Commands)
+		if requireAll {
+			for _, segment := range segments {
+				if writesRedirect(segment) {
+					return false, false
+				}
+			}
+		}
+		if !matchValues(segments, requireAll, rule.matchesCommand) {
+			return false, false
+		}
+	}
+	if len(rule.PathGlobs) > 0 {
+		absolute := make([]string, 0, len(call.Paths))
+		for _, target := range call.Paths {
+			if filepath.IsAbs(strings.TrimSpace(target)) {
+				absolute = append(absolute, target)
+			}
+		}
+		relative := len(absolute) < len(call.Paths)
+		switch {
+		case requireAll && relative:
+			return false, false
+		case matchValues(absolute, requireAll, rule.matchesPath):
+		case relative:
+			unresolved = true
+		default:
+			return false, false
+		}
+	}
+	if len(rule.Domains) > 0 {
+		if !matchValues(call.URLs, requireAll, rule.matchesURL) {
+			return false, false
+		}
+	}
+	return true, unresolved
+}
+
+func matchValues(values []string, requireAll bool, match func(string) bool) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		matched := match(value)
+		if requireAll && !matched {
+			return false
+		}
+		if !requireAll && matched {
+			return true
+		}
+	}
+	return requireAll
+}
+
+func (rule PolicyRule) matchesCommand(segment string) bool {
+	candidates := []string{segment}
+	if normalized := normalizeCommand(segment); normalized != segment {
+		candidates = append(candidates, normalized)
+	}
+	for _, candidate := range candidates {
+		for _, prefix := range rule.CommandPrefixes {
+			prefix = strings.Join(strings.Fields(prefix), " ")
+			if prefix != "" && (candidate == prefix || strings.HasPrefix(candidate, prefix+" ")) {
+				return true
+			}
+		}
+		for _, pattern := range rule.CommandPatterns {
+			if expression, err := regexp.Compile(pattern); err == nil && expression.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commandWrappers lists launchers that run their arguments as the actual
+// command, with the options that consume a value.
+var commandWrappers = map[string][]string{
+	"builtin": nil,
+	"command": nil,
+	"doas":    {"-u", "-C"},
+	"env":     {"-u", "-C", "--unset", "--chdir"},
+	"eval":    nil,
+	"exec":    {"-a"},
+	"nice":    {"-n", "--adjustment"},
+	"nohup":   nil,
+	"sudo":    {"-u", "-g", "-C", "-D", "-h", "-p", "-r", "-t", "-U", "--user", "--group", "--chdir", "--host", "--prompt"},
+	"time":    {"-f", "-o", "--format", "--output"},
+	"timeout": {"-k", "-s", "--kill-after", "--signal"},
+	"xargs": {"-a", "-d", "-E", "-I", "-L", "-n", "-P", "-s", "--arg-file", "--delimiter", "--eof", "--max-args",
+		"--max-chars", "--max-lines", "--max-procs", "--replace"},
+}
+
+// shellInterpreters run the script passed with -c. The script is split into
+// segments along with the rest of the command line, so unwrapping leaves its
+// first command.
+var shellInterpreters = map[string]bool{
+	"ash":  true,
+	"bash": true,
+	"dash": true,
+	"ksh":  true,
+	"sh":   true,
+	"zsh":  true,
+}
+
+// normalizeCommand strips leading variable assignments, wrapper commands and
+// shell -c invocations from a segment, drops quotes and reduces argv[0] to its
+// base name.
+func normalizeCommand(segment string) string {
+	fields := make([]string, 0, 8)
+	for _, field := range strings.Fields(segment) {
+		if field = strings.Trim(field, `"'`); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	for len(fields) > 0 {
+		if isEnvAssignment(fields[0]) {
+			fields = fields[1:]
+			continue
+		}
+		name := commandBase(fields[0])
+		if shellInterpreters[name] {
+			script, ok := shellScript(fields[1:])
+			if !ok {
+				break
+			}
+			fields = script
+			continue
+		}
+		valueOptions, ok := commandWrappers[name]
+		if !ok {
+			break
+		}
+		fields = fields[1:]
+		for len(fields) > 0 {
+			option := fields[0]
+			if option == "--" {
+				fields = fields[1:]
+				break
+			}
+			if name == "env" && isEnvAssignment(option) {
+				fields = fields[1:]
+				continue
+			}
+			if !strings.HasPrefix(option, "-") {
+				break
+			}
+			fields = fields[1:]
+			if containsOption(valueOptions, option) && len(fields) > 0 {
+				fields = fields[1:]
+			}
+		}
+		if name == "timeout" && len(fields) > 0 {
+			// timeout takes the duration before the command.
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	fields[0] = commandBase(fields[0])
+	return strings.Join(fields, " ")
+}
+
+// shellScript returns the fields following the -c option of a shell, and
+// false when the shell runs a script file or reads commands from stdin.
+func shellScript(args []string) ([]string, bool) {
+	for index := 0; index < len(args); index++ {
+		option := args[index]
+		switch {
+		case option == "--rcfile" || option == "--init-file":
+			index++
+		case strings.HasPrefix(option, "--"):
+		case strings.HasPrefix(option, "-") || strings.HasPrefix(option, "+"):
+			if option[0] == '-' && strings.Contains(option[1:], "c") {
+				return args[index+1:], true
+			}
+			if strings.ContainsAny(option[1:], "oO") {
+				index++
+			}
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func commandBase(argv0 string) string {
+	argv0 = strings.Trim(argv0, `"'`)
+	if index := strings.LastIndexAny(argv0, `/\`); index >= 0 {
+		argv0 = argv0[index+1:]
+	}
+	if strings.HasSuffix(strings.ToLower(argv0), ".exe") {
+		argv0 = argv0[:len(argv0)-len(".exe")]
+	}
+	return argv0
+}
+
+func isEnvAssignment(field string) bool {
+	name, _, ok := strings.Cut(field, "=")
+	if !ok || name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	for index, char := range name {
+		if char == '_' || (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') || (index > 0 && char >= '0' && char <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func containsOption(options []string, option string) bool {
+	for _, candidate := range options {
+		if candidate == option {
+			return true
+		}
+	}
+	return false
+}
+
+// commandSegments splits shell command lines on control operators so each
+// pipeline stage is checked on its own. Quoting is not interpreted; an
+// operator inside quotes only produces an extra, harmless segment. The "&"
+// of redirections such as "2>&1" or "&>" does not split.
+func commandSegments(commands []string) []string {
+	result := make([]string, 0, len(commands))
+	appendSegment := func(segment string) {
+		segment = strings.TrimLeft(strings.Join(strings.Fields(segment), " "), "({ ")
+		if segment != "" {
+			result = append(result, segment)
+		}
+	}
+	for _, command := range commands {
+		start := 0
+		for index := 0; index < len(command); index++ {
+			switch command[index] {
+			case '&':
+				if (index > 0 && (command[index-1] == '>' || command[index-1] == '<')) ||
+					(index+1 < len(command) && command[index+1] == '>') {
+					continue
+				}
+			case '$':
+				if index+1 >= len(command) || command[index+1] != '(' {
+					continue
+				}
+				appendSegment(command[start:index])
+				index++
+				start = index + 1
+				continue
+			case ';', '|', '`', '\n':
+			default:
+				continue
+			}
+			appendSegment(command[start:index])
+			start = index + 1
+		}
+		appendSegment(command[start:])
+	}
+	return result
+}
+
+// writesRedirect reports whether a segment redirects output to anything
+// other than another descriptor or a standard device.
+func writesRedirect(segment string) bool {
+	for index := 0; index < len(segment); index++ {
+		if segment[index] != '>' {
+			continue
+		}
+		next := index + 1
+		if next < len(segment) && (segment[next] == '>' || segment[next] == '|') {
+			next++
+		}
+		if next < len(segment) && segment[next] == '&' {
+			target := strings.Fields(segment[next+1:] + " ")
+			if len(target) > 0 && strings.Trim(target[0], "0123456789-") == "" {
+				index = next
+				continue
+			}
+			next++
+		}
+		target := ""
+		if fields := strings.Fields(segment[next:]); len(fields) > 0 {
+			target = strings.Trim(fields[0], `"'`)
+		}
+		switch target {
+		case "/dev/null", "/dev/stdout", "/dev/stderr":
+			index = next
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func (rule PolicyRule) matchesPath(target string) bool {
+	target = filepath.ToSlash(filepath.Clean(strings.TrimSpace(target)))
+	for _, glob := range rule.PathGlobs {
+		if expression, err := compilePathGlob(glob); err == nil && expression.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePathGlob turns a path glob into a regular expression: "*" and "?"
+// stay within one path segment, "**" crosses segments and a leading "~/" is
+// expanded to the home directory.
+func compilePathGlob(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimSpace(glob)
+	if strings.HasPrefix(glob, "~/") || glob == "~" {
+		if home, err := os.UserHomeDir(); err == nil {
+			glob = filepath.Join(home, strings.TrimPrefix(glob, "~"))
+		}
+	}
+	glob = filepath.ToSlash(glob)
+	var expression strings.Builder
+	expression.WriteString("^")
+	for index := 0; index < len(glob); index++ {
+		switch char := glob[index]; char {
+		case '*':
+			if index+1 < len(glob) && glob[index+1] == '*' {
+				index++
+				if index+1 < len(glob) && glob[index+1] == '/' {
+					index++
+					expression.WriteString("(?:.*/)?")
+				} else {
+					expression.WriteString(".*")
+				}
+				continue
+			}
+			expression.WriteString("[^/]*")
+		case '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	expression.WriteString("$")
+	return regexp.Compile(expression.String())
+}
+
+func (rule PolicyRule) matchesURL(raw string) bool {
+	host := urlHost(raw)
+	if host == "" {
+		return false
+	}
+	for _, domain := range rule.Domains {
+		domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		if domain == "" {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(domain, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func urlHost(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+}
@@ -0,0 +1,465 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	gatewayauth "dreamcreator/internal/application/gateway/auth"
+	tooldto "dreamcreator/internal/application/tools/dto"
+)
+
+const (
+	ProtocolVersion = "2025-06-18"
+
+	SessionIDHeader       = "Mcp-Session-Id"
+	ProtocolVersionHeader = "MCP-Protocol-Version"
+
+	serverName      = "dreamcreator"
+	policySource    = "mcp"
+	maxRequestBytes = 4 << 20
+	sessionIdleTTL  = 24 * time.Hour
+)
+
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcForbidden      = -32003
+)
+
+var supportedProtocolVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// ToolService is the slice of the gateway tool service the MCP endpoint needs.
+type ToolService interface {
+	ListTools(ctx context.Context) []tooldto.ToolSpec
+	InvokeWithPolicy(ctx context.Context, request tooldto.ToolsInvokeRequest, policyCtx tooldto.ToolPolicyContext) (tooldto.ToolsInvokeResponse, error)
+	CleanupRuntimeSession(ctx context.Context, sessionKey string)
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type session struct {
+	subject  string
+	lastSeen time.Time
+}
+
+// Server publishes the gateway tools over the MCP streamable HTTP transport.
+// Every request must carry a gateway token; tools are filtered and invoked
+// according to the scopes attached to that token.
+type Server struct {
+	tools   ToolService
+	auth    gatewayauth.Service
+	guard   gatewayauth.ScopeGuard
+	version string
+	now     func() time.Time
+
+	workspace string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func NewServer(tools ToolService, auth gatewayauth.Service, guard gatewayauth.ScopeGuard, version string) *Server {
+	return &Server{
+		tools:    tools,
+		auth:     auth,
+		guard:    guard,
+		version:  strings.TrimSpace(version),
+		now:      time.Now,
+		sessions: make(map[string]*session),
+	}
+}
+
+// SetWorkspacePath sets the workspace tool calls are anchored at and, when
+// sandboxed, confined to.
+func (server *Server) SetWorkspacePath(path string) {
+	if server == nil {
+		return
+	}
+	server.mu.Lock()
+	server.workspace = strings.TrimSpace(path)
+	server.mu.Unlock()
+}
+
+// SweepIdleSessions forgets sessions idle for longer than the session TTL
+// and releases the runtime resources, such as background processes, that
+// their tool calls left behind.
+func (server *Server) SweepIdleSessions(ctx context.Context) {
+	if server == nil || server.tools == nil {
+		return
+	}
+	now := server.now()
+	expired := make([]string, 0)
+	server.mu.Lock()
+	for id, current := range server.sessions {
+		if now.Sub(current.lastSeen) > sessionIdleTTL {
+			delete(server.sessions, id)
+			expired = append(expired, id)
+		}
+	}
+	server.mu.Unlock()
+	for _, id := range expired {
+		server.tools.CleanupRuntimeSession(ctx, runtimeSessionKey(id))
+	}
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		setCORSHeaders(w, r)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	setCORSHeaders(w, r)
+	if !isLocalOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if server == nil || server.tools == nil || server.auth == nil {
+		http.Error(w, "mcp server unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	authCtx, err := server.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="dreamcreator"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		server.handlePost(w, r, authCtx)
+	case http.MethodDelete:
+		server.handleDelete(w, r, authCtx)
+	default:
+		// Server-initiated streams are not offered; clients fall back to
+		// request/response over POST.
+		w.Header().Set("Allow", "POST, DELETE, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (server *Server) authenticate(r *http.Request) (gatewayauth.AuthContext, error) {
+	token := bearerToken(r.Header.Get("Authorization"))
+	// The shared gateway auth service may allow anonymous sessions for the
+	// local control plane; the MCP endpoint always requires a token.
+	if token == "" {
+		return gatewayauth.AuthContext{}, gatewayauth.ErrUnauthorized
+	}
+	return server.auth.Authenticate(r.Context(), gatewayauth.Credentials{Token: token}, "operator", nil)
+}
+
+func (server *Server) handlePost(w http.ResponseWriter, r *http.Request, authCtx gatewayauth.AuthContext) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	var request rpcRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeRPCResponse(w, http.StatusBadRequest, rpcResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &rpcError{Code: rpcParseError, Message: "invalid JSON-RPC message"},
+		})
+		return
+	}
+	if request.JSONRPC != "2.0" {
+		writeRPCResponse(w, http.StatusBadRequest, rpcResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error:   &rpcError{Code: rpcInvalidRequest, Message: "jsonrpc must be 2.0"},
+		})
+		return
+	}
+	if request.Method == "" {
+		// Responses to server requests are not expected; accept and drop them.
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if request.Method == "initialize" {
+		result, sessionID, rpcErr := server.initialize(r.Context(), authCtx, request.Params)
+		if sessionID != "" {
+			w.Header().Set(SessionIDHeader, sessionID)
+		}
+		writeRPCResult(w, request.ID, result, rpcErr)
+		return
+	}
+
+	sessionID := strings.TrimSpace(r.Header.Get(SessionIDHeader))
+	if sessionID == "" {
+		http.Error(w, "missing "+SessionIDHeader+" header", http.StatusBadRequest)
+		return
+	}
+	if !server.touchSession(sessionID, authCtx.Subject) {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if len(request.ID) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var result any
+	var rpcErr *rpcError
+	switch request.Method {
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		result = map[string]any{"tools": server.listTools(r.Context(), authCtx)}
+	case "tools/call":
+		result, rpcErr = server.callTool(r.Context(), authCtx, sessionID, request.Params)
+	default:
+		rpcErr = &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + request.Method}
+	}
+	writeRPCResult(w, request.ID, result, rpcErr)
+}
+
+func (server *Server) handleDelete(w http.ResponseWriter, r *http.Request, authCtx gatewayauth.AuthContext) {
+	sessionID := strings.TrimSpace(r.Header.Get(SessionIDHeader))
+	server.mu.Lock()
+	current, ok := server.sessions[sessionID]
+	ok = ok && current.subject == authCtx.Subject
+	if ok {
+		delete(server.sessions, sessionID)
+	}
+	server.mu.Unlock()
+	if !ok {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	server.tools.CleanupRuntimeSession(context.WithoutCancel(r.Context()), runtimeSessionKey(sessionID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (server *Server) initialize(ctx context.Context, authCtx gatewayauth.AuthContext, params json.RawMessage) (any, string, *rpcError) {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &request); err != nil {
+			return nil, "", &rpcError{Code: rpcInvalidParams, Message: "invalid initialize params"}
+		}
+	}
+	version := ProtocolVersion
+	for _, supported := range supportedProtocolVersions {
+		if strings.TrimSpace(request.ProtocolVersion) == supported {
+			version = supported
+			break
+		}
+	}
+
+	server.SweepIdleSessions(context.WithoutCancel(ctx))
+	sessionID := uuid.NewString()
+	server.mu.Lock()
+	server.sessions[sessionID] = &session{subject: authCtx.Subject, lastSeen: server.now()}
+	server.mu.Unlock()
+
+	serverInfo := map[string]any{"name": serverName}
+	if server.version != "" {
+		serverInfo["version"] = server.version
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools": map[string]any{"listChanged": false},
+		},
+		"serverInfo":   serverInfo,
+		"instructions": "DreamCreator library, subtitle, download, memory and cron tools. Library jobs run asynchronously: create them with library_manage_* tools and poll library_operation_status for progress.",
+	}, sessionID, nil
+}
+
+func (server *Server) touchSession(sessionID string, subject string) bool {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	current, ok := server.sessions[sessionID]
+	if !ok || current.subject != subject {
+		return false
+	}
+	current.lastSeen = server.now()
+	return true
+}
+
+func (server *Server) allowed(method string, authCtx gatewayauth.AuthContext, scopes ...string) bool {
+	if server.guard == nil {
+		return false
+	}
+	return server.guard.Check(method, authCtx, scopes).Allowed
+}
+
+func (server *Server) listTools(ctx context.Context, authCtx gatewayauth.AuthContext) []toolDescriptor {
+	result := make([]toolDescriptor, 0)
+	if !server.allowed("tools/list", authCtx, ScopeToolsList) {
+		return result
+	}
+	for _, tool := range buildExposedTools(server.tools.ListTools(ctx)) {
+		if !server.allowed("tools/list", authCtx, ToolScope(tool.tool)) {
+			continue
+		}
+		result = append(result, tool.descriptor())
+	}
+	return result
+}
+
+func (server *Server) callTool(ctx context.Context, authCtx gatewayauth.AuthContext, sessionID string, params json.RawMessage) (any, *rpcError) {
+	var request struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "invalid tools/call params"}
+	}
+	name := strings.TrimSpace(request.Name)
+	var target *exposedTool
+	for _, tool := range buildExposedTools(server.tools.ListTools(ctx)) {
+		if tool.name == name {
+			target = &tool
+			break
+		}
+	}
+	if target == nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "unknown tool: " + name}
+	}
+	if !server.allowed("tools/call", authCtx, ToolScope(target.tool)) {
+		return nil, &rpcError{Code: rpcForbidden, Message: "scope not granted: " + ToolScope(target.tool)}
+	}
+	args, err := target.invocationArgs(request.Arguments)
+	if err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	sessionKey := runtimeSessionKey(sessionID)
+	server.mu.Lock()
+	workspace := server.workspace
+	server.mu.Unlock()
+	response, err := server.tools.InvokeWithPolicy(ctx, tooldto.ToolsInvokeRequest{
+		Tool:       target.tool,
+		ToolCallID: uuid.NewString(),
+		Action:     target.action,
+		Args:       args,
+		SessionKey: sessionKey,
+	}, tooldto.ToolPolicyContext{
+		SessionKey:    sessionKey,
+		AgentID:       authCtx.Subject,
+		Source:        policySource,
+		WorkspacePath: workspace,
+	})
+	if err != nil {
+		return toolCallError(err.Error()), nil
+	}
+	if message := strings.TrimSpace(response.Result.ErrorMessage); message != "" {
+		return toolCallError(message), nil
+	}
+	return toolCallOutput(response.Result.OutputJSON), nil
+}
+
+// runtimeSessionKey is the gateway session key tool calls of an MCP session
+// run under.
+func runtimeSessionKey(sessionID string) string {
+	return "mcp:" + sessionID
+}
+
+func toolCallOutput(output string) map[string]any {
+	output = strings.TrimSpace(output)
+	result := map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": output}},
+		"isError": false,
+	}
+	var structured map[string]any
+	if err := json.Unmarshal([]byte(output), &structured); err == nil && structured != nil {
+		result["structuredContent"] = structured
+	}
+	return result
+}
+
+func toolCallError(message string) map[string]any {
+	return map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": message}},
+		"isError": true,
+	}
+}
+
+func writeRPCResult(w http.ResponseWriter, id json.RawMessage, result any, rpcErr *rpcError) {
+	response := rpcResponse{JSONRPC: "2.0", ID: id}
+	if rpcErr != nil {
+		response.Error = rpcErr
+	} else {
+		response.Result = result
+	}
+	writeRPCResponse(w, http.StatusOK, response)
+}
+
+func writeRPCResponse(w http.ResponseWriter, status int, response rpcResponse) {
+	if len(response.ID) == 0 {
+		response.ID = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func bearerToken(header string) string {
+	header = strings.TrimSpace(header)
+	if len(header) < len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[len("Bearer "):])
+}
+
+// isLocalOrigin rejects browser requests from foreign pages so a website
+// cannot reach the loopback endpoint through DNS rebinding.
+func isLocalOrigin(origin string) bool {
+	origin = strings.TrimSpace(origin)
+	if origin == "" || origin == "null" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := parsed.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.EqualFold(host, "wails.localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := strings.TrimSpace(r.Header.Get("Origin"))
+	if origin != "" && isLocalOrigin(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "POST, DELETE, OPTIONS")
+	allowHeaders := strings.TrimSpace(r.Header.Get("Access-Control-Request-Headers"))
+	if allowHeaders == "" {
+		allowHeaders = "Content-Type, Authorization, " + SessionIDHeader + ", " + ProtocolVersionHeader
+	}
+	w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+	w.Header().Set("Access-Control-Expose-Headers", SessionIDHeader)
+}
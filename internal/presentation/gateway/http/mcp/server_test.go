@@ -0,0 +1,362 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gatewayauth "dreamcreator/internal/application/gateway/auth"
+	tooldto "dreamcreator/internal/application/tools/dto"
+)
+
+type stubToolService struct {
+	specs      []tooldto.ToolSpec
+	invoked    []tooldto.ToolsInvokeRequest
+	sources    []string
+	workspaces []string
+	cleaned    []string
+}
+
+func (stub *stubToolService) ListTools(context.Context) []tooldto.ToolSpec {
+	return stub.specs
+}
+
+func (stub *stubToolService) InvokeWithPolicy(_ context.Context, request tooldto.ToolsInvokeRequest, policyCtx tooldto.ToolPolicyContext) (tooldto.ToolsInvokeResponse, error) {
+	stub.invoked = append(stub.invoked, request)
+	stub.sources = append(stub.sources, policyCtx.Source)
+	stub.workspaces = append(stub.workspaces, policyCtx.WorkspacePath)
+	return tooldto.ToolsInvokeResponse{
+		Result: tooldto.ToolResult{OutputJSON: `{"ok":true,"operationId":"op-1"}`},
+	}, nil
+}
+
+func (stub *stubToolService) CleanupRuntimeSession(_ context.Context, sessionKey string) {
+	stub.cleaned = append(stub.cleaned, sessionKey)
+}
+
+func newStubToolService() *stubToolService {
+	return &stubToolService{specs: []tooldto.ToolSpec{
+		{
+			Name:        "library_manage",
+			Description: "Create library jobs.",
+			RiskLevel:   "high",
+			Enabled:     true,
+			Methods: []tooldto.ToolMethodSpec{{
+				Name: "download.create",
+				InputSchema: map[string]any{"oneOf": []any{
+					map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"action": map[string]any{"type": "string", "const": "download.create"},
+							"params": map[string]any{"type": "object"},
+						},
+						"required": []string{"action"},
+					},
+					map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"action": map[string]any{"type": "string", "const": "download.create"},
+							"url":    map[string]any{"type": "string"},
+						},
+						"required": []string{"action", "url"},
+					},
+				}},
+			}},
+		},
+		{
+			Name:        "cron",
+			Description: "Cron manager.",
+			RiskLevel:   "high",
+			SchemaJSON: `{"type":"object","properties":{"action":{"type":"string","enum":["status","list","add","runs"]},"params":{"type":"object"}},` +
+				`"allOf":[{"if":{"properties":{"action":{"const":"list"}}},"then":{"properties":{"params":{"type":"object","properties":{"query":{"type":"string"}}}}}}]}`,
+			Enabled: true,
+		},
+		{Name: "exec", Description: "Run commands.", Enabled: true},
+	}}
+}
+
+type rpcClient struct {
+	t         *testing.T
+	server    *httptest.Server
+	token     string
+	sessionID string
+}
+
+func (client *rpcClient) call(method string, params any) (*http.Response, rpcResponse) {
+	client.t.Helper()
+	payload := map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		payload["params"] = params
+	}
+	body, _ := json.Marshal(payload)
+	request, _ := http.NewRequest(http.MethodPost, client.server.URL, bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if client.token != "" {
+		request.Header.Set("Authorization", "Bearer "+client.token)
+	}
+	if client.sessionID != "" {
+		request.Header.Set(SessionIDHeader, client.sessionID)
+	}
+	response, err := client.server.Client().Do(request)
+	if err != nil {
+		client.t.Fatalf("request failed: %v", err)
+	}
+	defer response.Body.Close()
+	var decoded rpcResponse
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+			client.t.Fatalf("decode response failed: %v", err)
+		}
+	}
+	return response, decoded
+}
+
+func newTestServer(t *testing.T, scopes []string) (*rpcClient, *stubToolService) {
+	t.Helper()
+	auth := gatewayauth.NewInMemoryService()
+	auth.AllowAnonymous(true)
+	auth.AddToken("secret", gatewayauth.AuthContext{Subject: "editor", Scopes: scopes})
+	tools := newStubToolService()
+	server := httptest.NewServer(NewServer(tools, auth, gatewayauth.NewDefaultScopeGuard(), "1.0.0"))
+	t.Cleanup(server.Close)
+	return &rpcClient{t: t, server: server, token: "secret"}, tools
+}
+
+func decodeResult(t *testing.T, response rpcResponse, target any) {
+	t.Helper()
+	if response.Error != nil {
+		t.Fatalf("unexpected rpc error %#v", response.Error)
+	}
+	data, _ := json.Marshal(response.Result)
+	if err := json.Unmarshal(data, target); err != nil {
+		t.Fatalf("decode result failed: %v", err)
+	}
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	client, _ := newTestServer(t, DefaultScopes())
+	client.token = ""
+	if response, _ := client.call("initialize", nil); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected anonymous requests to be rejected, got %d", response.StatusCode)
+	}
+	client.token = "wrong"
+	if response, _ := client.call("initialize", nil); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unknown tokens to be rejected, got %d", response.StatusCode)
+	}
+}
+
+func TestServerListsAndCallsScopedTools(t *testing.T) {
+	client, tools := newTestServer(t, DefaultScopes())
+	response, initialized := client.call("initialize", map[string]any{"protocolVersion": "2025-03-26"})
+	var info struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	decodeResult(t, initialized, &info)
+	client.sessionID = response.Header.Get(SessionIDHeader)
+	if client.sessionID == "" || info.ProtocolVersion != "2025-03-26" {
+		t.Fatalf("unexpected initialize result %q (session %q)", info.ProtocolVersion, client.sessionID)
+	}
+
+	_, listed := client.call("tools/list", nil)
+	var list struct {
+		Tools []toolDescriptor `json:"tools"`
+	}
+	decodeResult(t, listed, &list)
+	names := make([]string, 0, len(list.Tools))
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "library_manage_download_create,cron_status,cron_list,cron_runs" {
+		t.Fatalf("unexpected tools %v", names)
+	}
+	download := list.Tools[0]
+	if _, ok := download.InputSchema["properties"].(map[string]any)["action"]; ok {
+		t.Fatalf("expected the action to be hidden, got %#v", download.InputSchema)
+	}
+	if required, _ := download.InputSchema["required"].([]any); len(required) != 1 || required[0] != "url" {
+		t.Fatalf("expected the flat schema to be used, got %#v", download.InputSchema)
+	}
+	if !download.Annotations.DestructiveHint {
+		t.Fatal("expected high risk tools to be marked destructive")
+	}
+
+	_, called := client.call("tools/call", map[string]any{
+		"name":      "library_manage_download_create",
+		"arguments": map[string]any{"url": "https://example.com/video"},
+	})
+	var result struct {
+		IsError           bool           `json:"isError"`
+		StructuredContent map[string]any `json:"structuredContent"`
+	}
+	decodeResult(t, called, &result)
+	if result.IsError || result.StructuredContent["operationId"] != "op-1" {
+		t.Fatalf("unexpected call result %#v", result)
+	}
+	if len(tools.invoked) != 1 || tools.invoked[0].Tool != "library_manage" || tools.sources[0] != "mcp" {
+		t.Fatalf("unexpected invocation %#v", tools.invoked)
+	}
+	if !strings.Contains(tools.invoked[0].Args, `"action":"download.create"`) || !strings.Contains(tools.invoked[0].Args, `"url"`) {
+		t.Fatalf("expected the action to be injected, got %s", tools.invoked[0].Args)
+	}
+
+	client.sessionID = "unknown"
+	if response, _ := client.call("tools/list", nil); response.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected unknown sessions to be rejected, got %d", response.StatusCode)
+	}
+}
+
+func TestServerEnforcesToolScopes(t *testing.T) {
+	client, tools := newTestServer(t, []string{ScopeToolsList, ToolScope("cron")})
+	response, _ := client.call("initialize", nil)
+	client.sessionID = response.Header.Get(SessionIDHeader)
+
+	_, listed := client.call("tools/list", nil)
+	var list struct {
+		Tools []toolDescriptor `json:"tools"`
+	}
+	decodeResult(t, listed, &list)
+	if len(list.Tools) != 3 || list.Tools[1].Name != "cron_list" {
+		t.Fatalf("expected only the cron read actions to be listed, got %#v", list.Tools)
+	}
+
+	_, called := client.call("tools/call", map[string]any{"name": "library_manage_download_create"})
+	if called.Error == nil || called.Error.Code != rpcForbidden {
+		t.Fatalf("expected a scope error, got %#v", called)
+	}
+	if len(tools.invoked) != 0 {
+		t.Fatalf("expected no invocation, got %#v", tools.invoked)
+	}
+}
+
+func TestServerExposesCronReadOnly(t *testing.T) {
+	client, tools := newTestServer(t, DefaultScopes())
+	response, _ := client.call("initialize", nil)
+	client.sessionID = response.Header.Get(SessionIDHeader)
+
+	_, listed := client.call("tools/list", nil)
+	var list struct {
+		Tools []toolDescriptor `json:"tools"`
+	}
+	decodeResult(t, listed, &list)
+	for _, tool := range list.Tools {
+		if strings.HasPrefix(tool.Name, "cron") && (!tool.Annotations.ReadOnlyHint || tool.Annotations.DestructiveHint) {
+			t.Fatalf("expected cron tools to be read-only, got %#v", tool)
+		}
+		if tool.Name == "cron_list" {
+			params, _ := tool.InputSchema["properties"].(map[string]any)["params"].(map[string]any)
+			if _, ok := params["properties"].(map[string]any)["query"]; !ok {
+				t.Fatalf("expected the list params schema, got %#v", tool.InputSchema)
+			}
+		}
+	}
+
+	_, called := client.call("tools/call", map[string]any{"name": "cron_add", "arguments": map[string]any{"params": map[string]any{}}})
+	if called.Error == nil {
+		t.Fatalf("expected cron add to be unavailable, got %#v", called)
+	}
+	client.call("tools/call", map[string]any{"name": "cron_list", "arguments": map[string]any{"action": "add"}})
+	if len(tools.invoked) != 1 || !strings.Contains(tools.invoked[0].Args, `"action":"list"`) {
+		t.Fatalf("expected the list action to be forced, got %#v", tools.invoked)
+	}
+}
+
+func TestServerCleansUpClosedAndIdleSessions(t *testing.T) {
+	auth := gatewayauth.NewInMemoryService()
+	auth.AddToken("secret", gatewayauth.AuthContext{Subject: "editor", Scopes: DefaultScopes()})
+	tools := newStubToolService()
+	now := time.Now()
+	mcpServer := NewServer(tools, auth, gatewayauth.NewDefaultScopeGuard(), "1.0.0")
+	mcpServer.now = func() time.Time { return now }
+	mcpServer.SetWorkspacePath("/workspace/mcp")
+	server := httptest.NewServer(mcpServer)
+	t.Cleanup(server.Close)
+	client := &rpcClient{t: t, server: server, token: "secret"}
+
+	response, _ := client.call("initialize", nil)
+	client.sessionID = response.Header.Get(SessionIDHeader)
+	client.call("tools/call", map[string]any{"name": "cron_list"})
+	if len(tools.workspaces) != 1 || tools.workspaces[0] != "/workspace/mcp" {
+		t.Fatalf("expected the workspace to reach the policy context, got %v", tools.workspaces)
+	}
+
+	request, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set(SessionIDHeader, client.sessionID)
+	deleted, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	deleted.Body.Close()
+	if deleted.StatusCode != http.StatusNoContent || len(tools.cleaned) != 1 || tools.cleaned[0] != "mcp:"+client.sessionID {
+		t.Fatalf("expected the closed session to be cleaned up, got %d %v", deleted.StatusCode, tools.cleaned)
+	}
+
+	response, _ = client.call("initialize", nil)
+	idle := response.Header.Get(SessionIDHeader)
+	now = now.Add(sessionIdleTTL + time.Minute)
+	mcpServer.SweepIdleSessions(context.Background())
+	if len(tools.cleaned) != 2 || tools.cleaned[1] != "mcp:"+idle {
+		t.Fatalf("expected the idle session to be cleaned up, got %v", tools.cleaned)
+	}
+	client.sessionID = idle
+	if response, _ := client.call("tools/list", nil); response.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected the idle session to be forgotten, got %d", response.StatusCode)
+	}
+}
+
+func TestBuildExposedToolsDisambiguatesNames(t *testing.T) {
+	prefix := strings.Repeat("x", maxToolNameLength)
+	tools := buildExposedTools([]tooldto.ToolSpec{{
+		Name:    "library_manage",
+		Enabled: true,
+		Methods: []tooldto.ToolMethodSpec{{Name: prefix + ".a"}, {Name: prefix + ".b"}, {Name: "a.b"}, {Name: "a_b"}},
+	}})
+	seen := make(map[string]struct{}, len(tools))
+	for _, tool := range tools {
+		if len(tool.name) > maxToolNameLength {
+			t.Fatalf("expected %q to fit the name limit", tool.name)
+		}
+		if _, ok := seen[tool.name]; ok {
+			t.Fatalf("expected unique tool names, got %q twice", tool.name)
+		}
+		seen[tool.name] = struct{}{}
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected every method to be exposed, got %d", len(seen))
+	}
+}
+
+func TestStdioBridgeRelaysMessages(t *testing.T) {
+	client, _ := newTestServer(t, DefaultScopes())
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	}, "\n")
+	var output bytes.Buffer
+	endpoint := Endpoint{URL: client.server.URL, Token: client.token}
+	if err := RunStdioBridge(context.Background(), endpoint, strings.NewReader(input), &output, client.server.Client()); err != nil {
+		t.Fatalf("bridge failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two replies, got %q", output.String())
+	}
+	if !strings.Contains(lines[1], "library_manage_download_create") {
+		t.Fatalf("expected tools/list to reuse the session, got %s", lines[1])
+	}
+
+	var failed bytes.Buffer
+	offline := Endpoint{URL: "http://127.0.0.1:1/mcp", Token: "secret"}
+	if err := RunStdioBridge(context.Background(), offline, strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"ping"}`), &failed, nil); err != nil {
+		t.Fatalf("bridge failed: %v", err)
+	}
+	if !strings.Contains(failed.String(), `"id":7`) || !strings.Contains(failed.String(), "not reachable") {
+		t.Fatalf("expected a JSON-RPC error for an offline app, got %s", failed.String())
+	}
+}
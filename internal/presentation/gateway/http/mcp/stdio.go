@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	EndpointFileName = "mcp-endpoint.json"
+
+	endpointURLEnv   = "DREAMCREATOR_MCP_URL"
+	endpointTokenEnv = "DREAMCREATOR_MCP_TOKEN"
+
+	maxStdioMessageBytes = 16 << 20
+)
+
+// Endpoint tells local clients where the running app serves MCP and which
+// token to present.
+type Endpoint struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// WriteEndpoint records the endpoint for the stdio shim. The file holds a
+// bearer token, so it is only readable by the current user.
+func WriteEndpoint(path string, endpoint Endpoint) error {
+	data, err := json.MarshalIndent(endpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadEndpoint resolves the endpoint from the environment, falling back to
+// the file written by the running app.
+func LoadEndpoint(path string) (Endpoint, error) {
+	endpoint := Endpoint{
+		URL:   strings.TrimSpace(os.Getenv(endpointURLEnv)),
+		Token: strings.TrimSpace(os.Getenv(endpointTokenEnv)),
+	}
+	if endpoint.URL != "" && endpoint.Token != "" {
+		return endpoint, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Endpoint{}, errors.New("dreamcreator is not running: mcp endpoint file not found")
+		}
+		return Endpoint{}, err
+	}
+	var stored Endpoint
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return Endpoint{}, fmt.Errorf("decode mcp endpoint file: %w", err)
+	}
+	if endpoint.URL == "" {
+		endpoint.URL = strings.TrimSpace(stored.URL)
+	}
+	if endpoint.Token == "" {
+		endpoint.Token = strings.TrimSpace(stored.Token)
+	}
+	if endpoint.URL == "" || endpoint.Token == "" {
+		return Endpoint{}, errors.New("mcp endpoint file is incomplete")
+	}
+	return endpoint, nil
+}
+
+// RunStdioBridge relays newline-delimited JSON-RPC messages from input to the
+// HTTP endpoint and writes the replies to output, so clients that only speak
+// the stdio transport can use the running app. It returns when input closes.
+func RunStdioBridge(ctx context.Context, endpoint Endpoint, input io.Reader, output io.Writer, client *http.Client) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	bridge := &stdioBridge{endpoint: endpoint, client: client, output: output}
+	defer bridge.close()
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessageBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := bridge.forward(ctx, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+type stdioBridge struct {
+	endpoint Endpoint
+	client   *http.Client
+	output   io.Writer
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+func (bridge *stdioBridge) forward(ctx context.Context, line []byte) error {
+	var message rpcRequest
+	if err := json.Unmarshal(line, &message); err != nil {
+		return bridge.write(rpcResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &rpcError{Code: rpcParseError, Message: "invalid JSON-RPC message"},
+		})
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, bridge.endpoint.URL, bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	request.Header.Set("Authorization", "Bearer "+bridge.endpoint.Token)
+	request.Header.Set(ProtocolVersionHeader, ProtocolVersion)
+	bridge.mu.Lock()
+	if bridge.sessionID != "" {
+		request.Header.Set(SessionIDHeader, bridge.sessionID)
+	}
+	bridge.mu.Unlock()
+
+	response, err := bridge.client.Do(request)
+	if err != nil {
+		return bridge.fail(message.ID, "dreamcreator is not reachable: "+err.Error())
+	}
+	defer response.Body.Close()
+	if sessionID := strings.TrimSpace(response.Header.Get(SessionIDHeader)); sessionID != "" {
+		bridge.mu.Lock()
+		bridge.sessionID = sessionID
+		bridge.mu.Unlock()
+	}
+	if response.StatusCode == http.StatusAccepted || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxStdioMessageBytes))
+	if err != nil {
+		return bridge.fail(message.ID, err.Error())
+	}
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "application/json") {
+		return bridge.writeRaw(bytes.TrimSpace(body))
+	}
+	return bridge.fail(message.ID, fmt.Sprintf("mcp endpoint returned %s: %s", response.Status, strings.TrimSpace(string(body))))
+}
+
+// fail reports a transport error to the client as a JSON-RPC error; errors
+// for notifications are dropped because nothing is waiting for them.
+func (bridge *stdioBridge) fail(id json.RawMessage, message string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	return bridge.write(rpcResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &rpcError{Code: -32000, Message: message},
+	})
+}
+
+func (bridge *stdioBridge) write(response rpcResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return bridge.writeRaw(data)
+}
+
+func (bridge *stdioBridge) writeRaw(data []byte) error {
+	if _, err := bridge.output.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (bridge *stdioBridge) close() {
+	bridge.mu.Lock()
+	sessionID := bridge.sessionID
+	bridge.mu.Unlock()
+	if sessionID == "" {
+		return
+	}
+	request, err := http.NewRequest(http.MethodDelete, bridge.endpoint.URL, nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("Authorization", "Bearer "+bridge.endpoint.Token)
+	request.Header.Set(SessionIDHeader, sessionID)
+	if response, err := bridge.client.Do(request); err == nil {
+		_ = response.Body.Close()
+	}
+}
@@ -0,0 +1,318 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tooldto "dreamcreator/internal/application/tools/dto"
+)
+
+// ScopeToolsList is required to see any tool in tools/list.
+const ScopeToolsList = "mcp.tools.list"
+
+const (
+	maxToolNameLength   = 64
+	toolNameHashLength  = 8
+	maxDescriptionChars = 1024
+)
+
+type exposedToolConfig struct {
+	name     string
+	readOnly bool
+	// actions restricts the tool to these actions, each published as its own
+	// MCP tool. Empty publishes every method, or the whole tool when it has
+	// none.
+	actions []string
+}
+
+// exposedTools lists the gateway tools published over MCP. Tools that declare
+// methods are split into one MCP tool per method so editors see typed inputs
+// for each library, download and subtitle operation. Cron only exposes its
+// read actions: a token that could add jobs would schedule agent turns that
+// run later without the editor in the loop.
+var exposedTools = []exposedToolConfig{
+	{name: "library", readOnly: true},
+	{name: "library_manage"},
+	{name: "memory_query", readOnly: true},
+	{name: "cron", readOnly: true, actions: []string{"status", "list", "runs"}},
+	{name: "external_tools_query", readOnly: true},
+}
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// ToolScope returns the scope a token needs to list and call a gateway tool.
+func ToolScope(tool string) string {
+	return "tools." + strings.TrimSpace(tool)
+}
+
+// DefaultScopes grants access to every tool the endpoint exposes.
+func DefaultScopes() []string {
+	scopes := []string{ScopeToolsList}
+	for _, tool := range exposedTools {
+		scopes = append(scopes, ToolScope(tool.name))
+	}
+	return scopes
+}
+
+type exposedTool struct {
+	name        string
+	tool        string
+	action      string
+	description string
+	inputSchema map[string]any
+	readOnly    bool
+	destructive bool
+}
+
+type toolDescriptor struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description"`
+	InputSchema map[string]any  `json:"inputSchema"`
+	Annotations toolAnnotations `json:"annotations"`
+}
+
+type toolAnnotations struct {
+	ReadOnlyHint    bool `json:"readOnlyHint"`
+	DestructiveHint bool `json:"destructiveHint"`
+	OpenWorldHint   bool `json:"openWorldHint"`
+}
+
+func (tool exposedTool) descriptor() toolDescriptor {
+	title := tool.tool
+	if tool.action != "" {
+		title = tool.tool + " " + tool.action
+	}
+	return toolDescriptor{
+		Name:        tool.name,
+		Title:       title,
+		Description: tool.description,
+		InputSchema: tool.inputSchema,
+		Annotations: toolAnnotations{
+			ReadOnlyHint:    tool.readOnly,
+			DestructiveHint: tool.destructive,
+		},
+	}
+}
+
+// invocationArgs converts MCP tool arguments into the gateway tool payload,
+// injecting the action for per-method tools.
+func (tool exposedTool) invocationArgs(raw json.RawMessage) (string, error) {
+	args := map[string]any{}
+	if trimmed := strings.TrimSpace(string(raw)); trimmed != "" && trimmed != "null" {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return "", errors.New("arguments must be a JSON object")
+		}
+	}
+	if tool.action != "" {
+		args["action"] = tool.action
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func buildExposedTools(specs []tooldto.ToolSpec) []exposedTool {
+	byName := make(map[string]tooldto.ToolSpec, len(specs))
+	for _, spec := range specs {
+		byName[strings.TrimSpace(spec.Name)] = spec
+	}
+	result := make([]exposedTool, 0)
+	used := make(map[string]struct{})
+	for _, config := range exposedTools {
+		spec, ok := byName[config.name]
+		if !ok || !spec.Enabled {
+			continue
+		}
+		destructive := !config.readOnly && strings.EqualFold(spec.RiskLevel, "high")
+		methods := spec.Methods
+		if len(config.actions) > 0 {
+			methods = configuredMethods(spec, config.actions)
+		}
+		if len(methods) == 0 {
+			used[spec.Name] = struct{}{}
+			result = append(result, exposedTool{
+				name:        spec.Name,
+				tool:        spec.Name,
+				description: truncateDescription(spec.Description),
+				inputSchema: objectSchema(decodeSchemaJSON(spec.SchemaJSON)),
+				readOnly:    config.readOnly,
+				destructive: destructive,
+			})
+			continue
+		}
+		for _, method := range methods {
+			action := strings.TrimSpace(method.Name)
+			if action == "" {
+				continue
+			}
+			name := methodToolName(spec.Name, action)
+			if _, taken := used[name]; taken {
+				// Truncation or sanitizing can map two methods to one name.
+				name = disambiguateToolName(name, spec.Name+"."+action)
+			}
+			used[name] = struct{}{}
+			result = append(result, exposedTool{
+				name:        name,
+				tool:        spec.Name,
+				action:      action,
+				description: truncateDescription(fmt.Sprintf("%s action of the %s tool. %s", action, spec.Name, spec.Description)),
+				inputSchema: methodInputSchema(method.InputSchema),
+				readOnly:    config.readOnly,
+				destructive: destructive,
+			})
+		}
+	}
+	return result
+}
+
+// configuredMethods returns the methods of spec named in actions. For a tool
+// without methods the action input schema is taken from the action enum and
+// the per-action params branches of its schema.
+func configuredMethods(spec tooldto.ToolSpec, actions []string) []tooldto.ToolMethodSpec {
+	result := make([]tooldto.ToolMethodSpec, 0, len(actions))
+	for _, action := range actions {
+		if len(spec.Methods) > 0 {
+			for _, method := range spec.Methods {
+				if strings.TrimSpace(method.Name) == action {
+					result = append(result, method)
+				}
+			}
+			continue
+		}
+		result = append(result, tooldto.ToolMethodSpec{
+			Name:        action,
+			InputSchema: actionInputSchema(decodeSchemaJSON(spec.SchemaJSON), action),
+		})
+	}
+	return result
+}
+
+// actionInputSchema narrows an {action, params} tool schema to one action,
+// keeping the params schema of the matching allOf branch.
+func actionInputSchema(schema map[string]any, action string) map[string]any {
+	properties, _ := schema["properties"].(map[string]any)
+	params, hasParams := properties["params"]
+	branches, _ := schema["allOf"].([]any)
+	for _, branch := range branches {
+		branchMap, _ := branch.(map[string]any)
+		condition, _ := branchMap["if"].(map[string]any)
+		conditionProperties, _ := condition["properties"].(map[string]any)
+		actionCondition, _ := conditionProperties["action"].(map[string]any)
+		if actionCondition["const"] != action {
+			continue
+		}
+		then, _ := branchMap["then"].(map[string]any)
+		thenProperties, _ := then["properties"].(map[string]any)
+		if branchParams, ok := thenProperties["params"]; ok {
+			params, hasParams = branchParams, true
+		}
+	}
+	result := map[string]any{"type": "object", "properties": map[string]any{}}
+	if hasParams {
+		result["properties"] = map[string]any{"params": params}
+	}
+	return result
+}
+
+// methodToolName flattens a tool method into an MCP tool name, e.g.
+// library_manage + subtitle.translate.create => library_manage_subtitle_translate_create.
+func methodToolName(tool string, action string) string {
+	name := invalidToolNameChars.ReplaceAllString(tool+"_"+strings.ReplaceAll(action, ".", "_"), "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// disambiguateToolName suffixes name with a short hash of key, truncating it
+// to keep within the tool name limit.
+func disambiguateToolName(name string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	suffix := "_" + hex.EncodeToString(sum[:])[:toolNameHashLength]
+	if limit := maxToolNameLength - len(suffix); len(name) > limit {
+		name = name[:limit]
+	}
+	return name + suffix
+}
+
+// methodInputSchema picks the flat variant of a method schema (arguments at
+// the top level instead of under params) and drops the action field, which
+// the endpoint fills in on call.
+func methodInputSchema(schema any) map[string]any {
+	decoded := toSchemaMap(schema)
+	if variants, ok := decoded["oneOf"].([]any); ok && len(variants) > 0 {
+		selected := toSchemaMap(variants[0])
+		for _, variant := range variants {
+			candidate := toSchemaMap(variant)
+			properties, _ := candidate["properties"].(map[string]any)
+			if _, nested := properties["params"]; !nested {
+				selected = candidate
+				break
+			}
+		}
+		decoded = selected
+	}
+	if properties, ok := decoded["properties"].(map[string]any); ok {
+		delete(properties, "action")
+	}
+	if required, ok := decoded["required"].([]any); ok {
+		filtered := make([]any, 0, len(required))
+		for _, item := range required {
+			if item != "action" {
+				filtered = append(filtered, item)
+			}
+		}
+		if len(filtered) == 0 {
+			delete(decoded, "required")
+		} else {
+			decoded["required"] = filtered
+		}
+	}
+	return objectSchema(decoded)
+}
+
+// toSchemaMap deep-copies a schema through JSON so edits never leak back into
+// the shared tool specs.
+func toSchemaMap(schema any) map[string]any {
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return map[string]any{}
+	}
+	return decodeSchemaJSON(string(encoded))
+}
+
+func decodeSchemaJSON(raw string) map[string]any {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(raw), &schema); err != nil || schema == nil {
+		return map[string]any{}
+	}
+	return schema
+}
+
+func objectSchema(schema map[string]any) map[string]any {
+	if schema == nil {
+		schema = map[string]any{}
+	}
+	schema["type"] = "object"
+	if _, ok := schema["properties"]; !ok {
+		schema["properties"] = map[string]any{}
+	}
+	return schema
+}
+
+func truncateDescription(value string) string {
+	value = strings.TrimSpace(value)
+	runes := []rune(value)
+	if len(runes) <= maxDescriptionChars {
+		return value
+	}
+	return string(runes[:maxDescriptionChars-3]) + "..."
+}
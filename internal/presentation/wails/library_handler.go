@@ -112,6 +112,18 @@ func (handler *LibraryHandler) ResumeOperation(ctx context.Context, request dto.
 	return handler.service.ResumeOperation(ctx, request)
 }
 
+func (handler *LibraryHandler) SetOperationPriority(ctx context.Context, request dto.SetOperationPriorityRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.SetOperationPriority(ctx, request)
+}
+
+func (handler *LibraryHandler) GetOperationQueue(ctx context.Context) (dto.OperationQueueStateDTO, error) {
+	return handler.service.GetOperationQueue(ctx)
+}
+
+func (handler *LibraryHandler) SetOperationQueuePaused(ctx context.Context, request dto.SetOperationQueuePausedRequest) (dto.OperationQueueStateDTO, error) {
+	return handler.service.SetOperationQueuePaused(ctx, request)
+}
+
 func (handler *LibraryHandler) DeleteOperation(ctx context.Context, request dto.DeleteOperationRequest) error {
 	return handler.service.DeleteOperation(ctx, request)
 }
@@ -184,6 +196,10 @@ func (handler *LibraryHandler) CreateYTDLPJob(ctx context.Context, request dto.C
 	return handler.service.CreateYTDLPJob(ctx, request)
 }
 
+func (handler *LibraryHandler) CreateYTDLPPlaylistJob(ctx context.Context, request dto.CreateYTDLPPlaylistJobRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateYTDLPPlaylistJob(ctx, request)
+}
+
 func (handler *LibraryHandler) CheckYTDLPOperationFailure(ctx context.Context, request dto.CheckYTDLPOperationFailureRequest) (dto.CheckYTDLPOperationFailureResponse, error) {
 	return handler.service.CheckYTDLPOperationFailure(ctx, request)
 }
@@ -208,6 +224,22 @@ func (handler *LibraryHandler) CreateTranscodeJob(ctx context.Context, request d
 	return handler.service.CreateTranscodeJob(ctx, request)
 }
 
+func (handler *LibraryHandler) CreateClipJob(ctx context.Context, request dto.CreateClipJobRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateClipJob(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateChapterSplitJob(ctx context.Context, request dto.CreateChapterSplitJobRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateChapterSplitJob(ctx, request)
+}
+
+func (handler *LibraryHandler) UpdateFileChapters(ctx context.Context, request dto.UpdateFileChaptersRequest) (dto.LibraryFileDTO, error) {
+	return handler.service.UpdateFileChapters(ctx, request)
+}
+
+func (handler *LibraryHandler) ExportFileChapters(ctx context.Context, request dto.ExportFileChaptersRequest) (dto.ExportFileChaptersResult, error) {
+	return handler.service.ExportFileChapters(ctx, request)
+}
+
 func (handler *LibraryHandler) CreateSubtitleTranslateJob(ctx context.Context, request dto.SubtitleTranslateRequest) (dto.LibraryOperationDTO, error) {
 	return handler.service.CreateSubtitleTranslateJob(ctx, request)
 }
@@ -220,6 +252,26 @@ func (handler *LibraryHandler) CreateSubtitleQAReviewJob(ctx context.Context, re
 	return handler.service.CreateSubtitleQAReviewJob(ctx, request)
 }
 
+func (handler *LibraryHandler) CreateSubtitleTranscribeJob(ctx context.Context, request dto.SubtitleTranscribeRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitleTranscribeJob(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateSubtitleOCRJob(ctx context.Context, request dto.SubtitleOCRRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitleOCRJob(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateSubtitleExtractJob(ctx context.Context, request dto.CreateSubtitleExtractJobRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitleExtractJob(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateThumbnailJob(ctx context.Context, request dto.CreateThumbnailJobRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateThumbnailJob(ctx, request)
+}
+
+func (handler *LibraryHandler) SetFileCover(ctx context.Context, request dto.SetFileCoverRequest) (dto.LibraryFileDTO, error) {
+	return handler.service.SetFileCover(ctx, request)
+}
+
 func (handler *LibraryHandler) ListTranscodePresets(ctx context.Context) ([]dto.TranscodePreset, error) {
 	return handler.service.ListTranscodePresets(ctx)
 }
@@ -236,6 +288,22 @@ func (handler *LibraryHandler) DeleteTranscodePreset(ctx context.Context, reques
 	return handler.service.DeleteTranscodePreset(ctx, request)
 }
 
+func (handler *LibraryHandler) ListSubscriptions(ctx context.Context) ([]dto.LibrarySubscriptionDTO, error) {
+	return handler.service.ListSubscriptions(ctx)
+}
+
+func (handler *LibraryHandler) SaveSubscription(ctx context.Context, request dto.LibrarySubscriptionDTO) (dto.LibrarySubscriptionDTO, error) {
+	return handler.service.SaveSubscription(ctx, request)
+}
+
+func (handler *LibraryHandler) DeleteSubscription(ctx context.Context, request dto.DeleteSubscriptionRequest) error {
+	return handler.service.DeleteSubscription(ctx, request)
+}
+
+func (handler *LibraryHandler) PollSubscription(ctx context.Context, request dto.PollSubscriptionRequest) (dto.PollSubscriptionResult, error) {
+	return handler.service.PollSubscription(ctx, request)
+}
+
 func (handler *LibraryHandler) ParseSubtitle(ctx context.Context, request dto.SubtitleParseRequest) (dto.SubtitleParseResult, error) {
 	return handler.service.ParseSubtitle(ctx, request)
 }
@@ -260,6 +328,46 @@ func (handler *LibraryHandler) SaveSubtitle(ctx context.Context, request dto.Sub
 	return handler.service.SaveSubtitle(ctx, request)
 }
 
+func (handler *LibraryHandler) CreateSubtitleAlignJob(ctx context.Context, request dto.SubtitleAlignRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitleAlignJob(ctx, request)
+}
+
+func (handler *LibraryHandler) MergeBilingualSubtitles(ctx context.Context, request dto.SubtitleBilingualMergeRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.MergeBilingualSubtitles(ctx, request)
+}
+
+func (handler *LibraryHandler) SplitBilingualSubtitle(ctx context.Context, request dto.SubtitleBilingualSplitRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.SplitBilingualSubtitle(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateSubtitlePipelineJob(ctx context.Context, request dto.SubtitlePipelineRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitlePipelineJob(ctx, request)
+}
+
+func (handler *LibraryHandler) CreateSubtitleGlossaryExtractJob(ctx context.Context, request dto.SubtitleGlossaryExtractRequest) (dto.LibraryOperationDTO, error) {
+	return handler.service.CreateSubtitleGlossaryExtractJob(ctx, request)
+}
+
+func (handler *LibraryHandler) AcceptGlossaryTermCandidates(ctx context.Context, request dto.AcceptGlossaryTermCandidatesRequest) (dto.LibraryModuleConfigDTO, error) {
+	return handler.service.AcceptGlossaryTermCandidates(ctx, request)
+}
+
+func (handler *LibraryHandler) RetimeSubtitle(ctx context.Context, request dto.SubtitleRetimeRequest) (dto.SubtitleRetimeResult, error) {
+	return handler.service.RetimeSubtitle(ctx, request)
+}
+
 func (handler *LibraryHandler) RestoreSubtitleOriginal(ctx context.Context, request dto.RestoreSubtitleOriginalRequest) (dto.RestoreSubtitleOriginalResult, error) {
 	return handler.service.RestoreSubtitleOriginal(ctx, request)
 }
+
+func (handler *LibraryHandler) ListSubtitleRevisions(ctx context.Context, request dto.ListSubtitleRevisionsRequest) ([]dto.SubtitleRevisionDTO, error) {
+	return handler.service.ListSubtitleRevisions(ctx, request)
+}
+
+func (handler *LibraryHandler) DiffSubtitleRevisions(ctx context.Context, request dto.DiffSubtitleRevisionsRequest) (dto.SubtitleRevisionDiffResult, error) {
+	return handler.service.DiffSubtitleRevisions(ctx, request)
+}
+
+func (handler *LibraryHandler) RestoreSubtitleRevision(ctx context.Context, request dto.RestoreSubtitleRevisionRequest) (dto.RestoreSubtitleRevisionResult, error) {
+	return handler.service.RestoreSubtitleRevision(ctx, request)
+}
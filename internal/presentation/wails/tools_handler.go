@@ -2,20 +2,30 @@ package wails
 
 import (
 	"context"
+	"errors"
 
 	"dreamcreator/internal/application/tools/dto"
 	"dreamcreator/internal/application/tools/service"
 )
 
+var errPolicyRulesUnavailable = errors.New("policy rules unavailable")
+
 type ToolsHandler struct {
 	service     *service.ToolService
 	listService ToolListService
+	rules       ToolPolicyRuleService
 }
 
 type ToolListService interface {
 	ListTools(ctx context.Context) []dto.ToolSpec
 }
 
+type ToolPolicyRuleService interface {
+	ListPolicyRules(ctx context.Context) ([]dto.ToolPolicyRule, error)
+	SavePolicyRule(ctx context.Context, rule dto.ToolPolicyRule) (dto.ToolPolicyRule, error)
+	DeletePolicyRule(ctx context.Context, request dto.DeleteToolPolicyRuleRequest) error
+}
+
 type toolListAdapter struct {
 	service *service.ToolService
 }
@@ -37,6 +47,13 @@ func NewToolsHandler(service *service.ToolService, listService ToolListService)
 	}
 }
 
+func (handler *ToolsHandler) SetPolicyRuleService(rules ToolPolicyRuleService) {
+	if handler == nil {
+		return
+	}
+	handler.rules = rules
+}
+
 func (handler *ToolsHandler) ServiceName() string {
 	return "ToolsHandler"
 }
@@ -63,3 +80,24 @@ func (handler *ToolsHandler) ExecuteTool(ctx context.Context, request dto.Execut
 func (handler *ToolsHandler) QueryToolLogs(ctx context.Context, request dto.QueryToolLogsRequest) ([]dto.ToolInvocation, error) {
 	return handler.service.QueryToolLogs(ctx, request)
 }
+
+func (handler *ToolsHandler) ListPolicyRules(ctx context.Context) ([]dto.ToolPolicyRule, error) {
+	if handler == nil || handler.rules == nil {
+		return nil, errPolicyRulesUnavailable
+	}
+	return handler.rules.ListPolicyRules(ctx)
+}
+
+func (handler *ToolsHandler) SavePolicyRule(ctx context.Context, rule dto.ToolPolicyRule) (dto.ToolPolicyRule, error) {
+	if handler == nil || handler.rules == nil {
+		return dto.ToolPolicyRule{}, errPolicyRulesUnavailable
+	}
+	return handler.rules.SavePolicyRule(ctx, rule)
+}
+
+func (handler *ToolsHandler) DeletePolicyRule(ctx context.Context, request dto.DeleteToolPolicyRuleRequest) error {
+	if handler == nil || handler.rules == nil {
+		return errPolicyRulesUnavailable
+	}
+	return handler.rules.DeletePolicyRule(ctx, request)
+}
@@ -22,6 +22,14 @@ func main() {
 		}
 	}()
 
+	ranMCPStdioBridge, err := app.TryRunMCPStdioBridge(context.Background(), os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ranMCPStdioBridge {
+		return
+	}
+
 	appliedPreparedUpdate, err := app.TryApplyPreparedUpdateOnLaunch(context.Background(), os.Args[1:])
 	if err != nil {
 		log.Fatal(err)